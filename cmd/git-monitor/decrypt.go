@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/encryption"
+	"github.com/anupsv/git-monitoring/pkg/redact"
+)
+
+// runDecrypt implements the decrypt subcommand, which prints a report, checkpoint or state file written
+// with encryption enabled
+// Returns the process exit code
+func runDecrypt(args []string) int {
+	fs := flag.NewFlagSet("decrypt", flag.ContinueOnError)
+	configPath := fs.String("config", "config.toml", "Path to configuration file, for the key of [encryption]")
+	outputPath := fs.String("output", "", "Write the decrypted file to this path instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		log.Printf("Usage: git-monitor decrypt [--config config.toml] [--output path] <file>")
+		return 2
+	}
+
+	// The key of the environment is enough, so files can be decrypted away from the configuration
+	var cfg *config.Config
+	if os.Getenv("GIT_MONITOR_ENCRYPTION_KEY") == "" {
+		var err error
+		if cfg, err = config.LoadConfig(*configPath); err != nil {
+			log.Printf("Error loading configuration: %v", err)
+			return 1
+		}
+		redact.Register(cfg.Secrets()...)
+	}
+	if err := enableEncryption(cfg); err != nil {
+		log.Printf("Error loading encryption key: %v", err)
+		return 1
+	}
+
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		log.Printf("Error reading %s: %v", fs.Arg(0), err)
+		return 1
+	}
+	plaintext, err := encryption.Unseal(data)
+	if err != nil {
+		log.Printf("Error decrypting %s: %v", fs.Arg(0), err)
+		return 1
+	}
+
+	if *outputPath != "" {
+		err = os.WriteFile(*outputPath, plaintext, 0600)
+	} else {
+		_, err = os.Stdout.Write(plaintext)
+	}
+	if err != nil {
+		log.Printf("Error writing decrypted file: %v", err)
+		return 1
+	}
+	return 0
+}
+
+// enableEncryption encrypts what this process writes, and decrypts what it reads, with the key of the configuration
+// Without a configuration, e.g. when subcommands are pointed to a file, the key of GIT_MONITOR_ENCRYPTION_KEY is used
+func enableEncryption(cfg *config.Config) error {
+	encryptionCfg := config.EncryptionConfig{Key: os.Getenv("GIT_MONITOR_ENCRYPTION_KEY")}
+	encryptionCfg.Enabled = encryptionCfg.Key != ""
+	if cfg != nil {
+		encryptionCfg = cfg.Encryption
+	}
+	if !encryptionCfg.Enabled {
+		return nil
+	}
+
+	cipher, err := encryption.Load(encryptionCfg)
+	if err != nil {
+		return err
+	}
+	encryption.Enable(cipher)
+	return nil
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/redact"
+	"github.com/anupsv/git-monitoring/pkg/state"
+)
+
+// runHistory implements the history subcommand, which queries the findings persisted in the state file
+// Returns the process exit code
+func runHistory(args []string) int {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	configPath := fs.String("config", "config.toml", "Path to configuration file")
+	statePath := fs.String("state", "", "Path to the state file (default: state path from the configuration)")
+	repo := fs.String("repo", "", "Only show findings of this repository (owner/repo)")
+	monitor := fs.String("monitor", "", "Only show findings of this monitor (e.g. repo_visibility)")
+	since := fs.String("since", "", "Only show findings seen within this period (e.g. 30d, 12h)")
+	format := fs.String("format", "text", "Output format: text, json or csv")
+	outputPath := fs.String("output", "", "Write the results to this file instead of stdout")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	path := *statePath
+	var cfg *config.Config
+	if path == "" {
+		var err error
+		if cfg, err = config.LoadConfig(*configPath); err != nil {
+			log.Printf("Error loading configuration: %v", err)
+			return 1
+		}
+		redact.Register(cfg.Secrets()...)
+		path = cfg.State.Path
+	}
+	if err := enableEncryption(cfg); err != nil {
+		log.Printf("Error loading encryption key: %v", err)
+		return 1
+	}
+
+	query := state.Query{
+		Repository: *repo,
+		Monitor:    *monitor,
+	}
+	if *since != "" {
+		period, err := config.ParseDuration(*since)
+		if err != nil {
+			log.Printf("Invalid --since value: %v", err)
+			return 2
+		}
+		query.Since = time.Now().Add(-period)
+	}
+
+	// Stores other than local files are checked by loading the state
+	if _, err := os.Stat(path); err != nil && !strings.Contains(path, "://") {
+		log.Printf("State file %s not found. Enable [state] in the configuration to record findings", path)
+		return 1
+	}
+
+	s, err := state.Load(path)
+	if err != nil {
+		log.Printf("Error loading state: %v", err)
+		return 1
+	}
+
+	records := s.Query(query)
+
+	out := io.Writer(os.Stdout)
+	if *outputPath != "" {
+		f, err := os.OpenFile(*outputPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+		if err != nil {
+			log.Printf("Error creating %s: %v", *outputPath, err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
+
+	switch *format {
+	case "json":
+		err = printHistoryJSON(out, records, s.Acknowledgements)
+	case "csv":
+		err = printHistoryCSV(out, records, s.Acknowledgements)
+	case "text":
+		printHistoryText(out, records, s.Acknowledgements)
+	default:
+		log.Printf("Invalid --format value: %s. Must be one of: text, json, csv", *format)
+		return 2
+	}
+
+	if err != nil {
+		log.Printf("Error writing history: %v", err)
+		return 1
+	}
+
+	return 0
+}
+
+// historyEntry is a record with the triage of its finding, as printed in JSON
+type historyEntry struct {
+	state.Record
+	Acknowledgement *state.Acknowledgement `json:"acknowledgement,omitempty"`
+}
+
+// triage describes how a finding was triaged, or returns an empty string
+func triage(acks map[string]state.Acknowledgement, f findings.Finding) string {
+	ack, ok := acks[f.Fingerprint()]
+	if !ok {
+		return ""
+	}
+	if ack.Until != nil {
+		return fmt.Sprintf("%s by %s until %s", ack.Action, ack.By, ack.Until.Format("2006-01-02 15:04"))
+	}
+	return fmt.Sprintf("%s by %s", ack.Action, ack.By)
+}
+
+// printHistoryText prints the records as a fixed-width table
+func printHistoryText(out io.Writer, records []state.Record, acks map[string]state.Acknowledgement) {
+	if len(records) == 0 {
+		fmt.Fprintln(out, "No findings recorded for this query")
+		return
+	}
+
+	fmt.Fprintln(out, "Fingerprint       First Seen        Last Seen         Resolved          Monitor                   Repository                Subject")
+	fmt.Fprintln(out, "--------------------------------------------------------------------------------------------------------------------------------------")
+
+	for _, r := range records {
+		// Format repository name with padding
+		repoStr := r.Finding.Repository
+		if len(repoStr) > 24 {
+			repoStr = repoStr[:21] + "..."
+		} else {
+			repoStr = fmt.Sprintf("%-24s", repoStr)
+		}
+
+		resolved := "open"
+		if r.ResolvedAt != nil {
+			resolved = r.ResolvedAt.Format("2006-01-02 15:04")
+		}
+
+		subject := r.Finding.Subject
+		if t := triage(acks, r.Finding); t != "" {
+			subject += " (" + t + ")"
+		}
+
+		fmt.Fprintf(out, "%-17s %-17s %-17s %-17s %-25s %s %s\n",
+			r.Finding.Fingerprint(), r.FirstSeen.Format("2006-01-02 15:04"), r.LastSeen.Format("2006-01-02 15:04"), resolved,
+			r.Finding.Monitor, repoStr, subject)
+	}
+}
+
+// printHistoryJSON prints the records as a JSON array
+func printHistoryJSON(out io.Writer, records []state.Record, acks map[string]state.Acknowledgement) error {
+	entries := make([]historyEntry, 0, len(records))
+	for _, r := range records {
+		entry := historyEntry{Record: r}
+		if ack, ok := acks[r.Finding.Fingerprint()]; ok {
+			entry.Acknowledgement = &ack
+		}
+		entries = append(entries, entry)
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(entries)
+}
+
+// printHistoryCSV prints the records as CSV with a header row
+func printHistoryCSV(out io.Writer, records []state.Record, acks map[string]state.Acknowledgement) error {
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{"fingerprint", "first_seen", "last_seen", "resolved_at", "monitor", "repository", "subject", "summary", "url", "triage"}); err != nil {
+		return err
+	}
+
+	for _, r := range records {
+		resolved := ""
+		if r.ResolvedAt != nil {
+			resolved = r.ResolvedAt.Format(time.RFC3339)
+		}
+		row := []string{
+			r.Finding.Fingerprint(),
+			r.FirstSeen.Format(time.RFC3339),
+			r.LastSeen.Format(time.RFC3339),
+			resolved,
+			r.Finding.Monitor,
+			r.Finding.Repository,
+			r.Finding.Subject,
+			r.Finding.Summary,
+			r.Finding.URL,
+			triage(acks, r.Finding),
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/anupsv/git-monitoring/pkg/checkpoint"
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/state"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	"github.com/anupsv/git-monitoring/pkg/usage"
+)
+
+// monitorJob is the run of a monitor for an account
+type monitorJob struct {
+	monitor monitorDefinition
+	cfg     *config.Config      // Configuration of the account
+	resume  *checkpoint.Monitor // Progress of an interrupted run to continue, nil to start over
+	budget  int64               // API calls the run may send, unlimited when 0
+
+	// Outcome of the run, set before done is closed
+	run   monitorRun
+	usage usage.Monitor
+	done  chan struct{}
+}
+
+// Key identifies the run in the state and checkpoint
+func (j *monitorJob) Key() string {
+	return state.Key(j.cfg.Account, j.monitor.Key)
+}
+
+// execute runs the monitor in a scope of its own, so its coverage and API usage are not mixed up
+// with those of monitors running at the same time
+func (j *monitorJob) execute(ctx context.Context, useMarkdown bool) {
+	defer close(j.done)
+
+	if !useMarkdown && j.cfg.Account != "" {
+		fmt.Printf("Account %s:\n", j.cfg.Account)
+	}
+
+	ctx = common.WithScope(ctx)
+	common.LimitScope(ctx, j.budget)
+	start := usage.Take(ctx)
+	j.run = j.monitor.Run(ctx, j.cfg, useMarkdown, j.resume)
+	j.usage = usage.Measure(ctx, j.monitor.Key, j.cfg.Account, j.cfg.GitHub.Token, start)
+}
+
+// startMonitorJobs runs the jobs in the background in their order, up to concurrency at a time
+// Callers wait for each job's done channel before reading its outcome
+func startMonitorJobs(ctx context.Context, jobs []*monitorJob, concurrency int, useMarkdown bool) {
+	slots := make(chan struct{}, concurrency)
+	go func() {
+		for _, job := range jobs {
+			slots <- struct{}{}
+			go func(job *monitorJob) {
+				defer func() { <-slots }()
+				defer exitOnPanic()
+				job.execute(ctx, useMarkdown)
+			}(job)
+		}
+	}()
+}
@@ -10,84 +10,154 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"reflect"
+	"runtime/debug"
+	"slices"
 	"strings"
-
+	"syscall"
+	"time"
+	// Embed timezone data so the timezone setting works in minimal container images
+	_ "time/tzdata"
+
+	"github.com/anupsv/git-monitoring/pkg/archive"
+	"github.com/anupsv/git-monitoring/pkg/checkpoint"
+	"github.com/anupsv/git-monitoring/pkg/comparison"
 	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/coverage"
+	"github.com/anupsv/git-monitoring/pkg/encryption"
+	"github.com/anupsv/git-monitoring/pkg/escalation"
+	"github.com/anupsv/git-monitoring/pkg/events"
+	"github.com/anupsv/git-monitoring/pkg/evidence"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/heartbeat"
+	"github.com/anupsv/git-monitoring/pkg/metrics"
+	"github.com/anupsv/git-monitoring/pkg/notify"
+	"github.com/anupsv/git-monitoring/pkg/ownership"
+	"github.com/anupsv/git-monitoring/pkg/policy"
+	"github.com/anupsv/git-monitoring/pkg/projects"
+	"github.com/anupsv/git-monitoring/pkg/provenance"
+	"github.com/anupsv/git-monitoring/pkg/redact"
+	"github.com/anupsv/git-monitoring/pkg/remediation"
+	"github.com/anupsv/git-monitoring/pkg/scoring"
+	"github.com/anupsv/git-monitoring/pkg/servicenow"
+	"github.com/anupsv/git-monitoring/pkg/sla"
+	"github.com/anupsv/git-monitoring/pkg/state"
+	"github.com/anupsv/git-monitoring/pkg/suppression"
+	"github.com/anupsv/git-monitoring/pkg/tools/adminenforcement"
+	"github.com/anupsv/git-monitoring/pkg/tools/codeowners"
+	"github.com/anupsv/git-monitoring/pkg/tools/codescanning"
 	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	"github.com/anupsv/git-monitoring/pkg/tools/dependabot"
+	"github.com/anupsv/git-monitoring/pkg/tools/dormantaccess"
+	"github.com/anupsv/git-monitoring/pkg/tools/dormantrepos"
+	"github.com/anupsv/git-monitoring/pkg/tools/environments"
+	"github.com/anupsv/git-monitoring/pkg/tools/forkruns"
+	"github.com/anupsv/git-monitoring/pkg/tools/ghas"
+	"github.com/anupsv/git-monitoring/pkg/tools/issuehygiene"
+	"github.com/anupsv/git-monitoring/pkg/tools/openprchecker"
 	"github.com/anupsv/git-monitoring/pkg/tools/prchecker"
+	"github.com/anupsv/git-monitoring/pkg/tools/pushprotection"
+	"github.com/anupsv/git-monitoring/pkg/tools/repocreation"
 	"github.com/anupsv/git-monitoring/pkg/tools/repovisibility"
+	"github.com/anupsv/git-monitoring/pkg/tools/rulesets"
+	"github.com/anupsv/git-monitoring/pkg/tools/tokenhealth"
+	"github.com/anupsv/git-monitoring/pkg/tools/workflowpermissions"
+	"github.com/anupsv/git-monitoring/pkg/usage"
 )
 
-// captureOutput captures stdout output from a function
-func captureOutput(f func()) string {
-	old := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
-
-	f()
+// version is the version of the binary, set at build time with -ldflags "-X main.version=..."
+var version = "dev"
 
-	w.Close()
-	os.Stdout = old
+// noIssuesMessage is reported when no monitor found anything
+const noIssuesMessage = "## :white_check_mark: No Issues Found\n\nAll repositories are compliant with policies.\n"
 
+// render returns the markdown a renderer function writes, to compose it into reports and notifications
+func render(write func(w io.Writer)) string {
 	var buf strings.Builder
-	_, _ = io.Copy(&buf, r)
+	write(&buf)
 	return buf.String()
 }
 
+// leadingSections are the summaries reports start with, in this order, before the sections of the monitors
+var leadingSections = []string{"score", "organizations", "changes", "sla", "escalations"}
+
+// orderSections puts the leading sections first, keeping the order the other sections were added in
+func orderSections(sections []notify.Section) []notify.Section {
+	ordered := make([]notify.Section, 0, len(sections))
+	for _, name := range leadingSections {
+		for _, section := range sections {
+			if section.Monitor == name {
+				ordered = append(ordered, section)
+			}
+		}
+	}
+	for _, section := range sections {
+		if !slices.Contains(leadingSections, section.Monitor) {
+			ordered = append(ordered, section)
+		}
+	}
+	return ordered
+}
+
 // runPRChecker runs the PR checker monitor
-func runPRChecker(cfg *config.Config, useMarkdown bool) ([]prchecker.Result, bool) {
+func runPRChecker(ctx context.Context, cfg *config.Config, useMarkdown bool) ([]prchecker.Result, error) {
 	var problematicResults []prchecker.Result
-	monitorFailed := false
-
 	if !useMarkdown {
 		fmt.Println("Running PR Checker monitor...")
 	}
 
-	results := prchecker.Monitor(cfg)
+	results := prchecker.Monitor(ctx, cfg)
 
-	// Check if any results contain errors
+	// Check if any results contain errors, reporting the first with the number of repositories that failed
+	var firstErr error
+	failedRepos := 0
 	for _, result := range results {
 		if result.Error != nil {
-			monitorFailed = true
-			break
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", result.Repository, result.Error)
+			}
+			failedRepos++
+			continue
 		}
 		// Save problematic results for markdown output
-		if len(result.UnapprovedPRs) > 0 {
+		if len(result.UnapprovedPRs) > 0 || len(result.Violations) > 0 {
 			problematicResults = append(problematicResults, result)
 		}
 	}
+	var err error
+	if firstErr != nil {
+		err = fmt.Errorf("%d of %d repositories could not be checked, first error: %w", failedRepos, len(results), firstErr)
+	}
 
 	// Print results based on output format
 	if useMarkdown {
 		// We don't print to console here anymore, just return the results
 		// The caller will handle capturing the output
-		return problematicResults, monitorFailed
+		return problematicResults, err
 	}
 
 	prchecker.PrintResults(results)
-	return problematicResults, monitorFailed
+	return problematicResults, err
 }
 
 // runRepoVisibilityChecker runs the repository visibility checker
-func runRepoVisibilityChecker(cfg *config.Config, useMarkdown bool) ([]string, bool) {
-	monitorFailed := false
-
+func runRepoVisibilityChecker(ctx context.Context, cfg *config.Config, useMarkdown bool) ([]string, error) {
 	if !useMarkdown {
 		fmt.Println("Running Repository Visibility monitor...")
 	}
 
 	// Create GitHub client
-	client := common.NewGitHubClient(context.Background(), cfg.GitHub.Token)
+	client := common.NewGitHubClient(ctx, cfg.GitHub.Token)
 
 	// Create and run the visibility checker
 	checker := repovisibility.NewRepoVisibilityChecker(client, cfg)
-	recentlyPublic, err := checker.Run(context.Background())
+	recentlyPublic, err := checker.Run(ctx)
 
 	if err != nil {
 		log.Printf("Error checking repository visibility: %v", err)
-		monitorFailed = true
-		return nil, monitorFailed
+		return nil, err
 	}
 
 	if len(recentlyPublic) > 0 {
@@ -97,320 +167,1903 @@ func runRepoVisibilityChecker(cfg *config.Config, useMarkdown bool) ([]string, b
 				fmt.Printf("  - %s\n", repo)
 			}
 		}
-		return recentlyPublic, monitorFailed
+		return recentlyPublic, nil
 	}
 
 	if !useMarkdown {
 		fmt.Println("No organization repositories were recently made public")
 	}
 
-	return nil, monitorFailed
+	return nil, nil
 }
 
-// writeMarkdownToFile writes the markdown results to a file
-// Returns true if writing was successful, false otherwise
-func writeMarkdownToFile(outputPath string, content string) bool {
-	// Ensure directory exists if a path is specified
-	dir := filepath.Dir(outputPath)
-	if dir != "." && dir != "/" {
-		log.Printf("Creating directory: %s", dir)
-		// Create directory with permissive permissions (0755)
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			log.Printf("Error creating directory %s: %v", dir, err)
-			return false
-		}
-
-		// Explicitly set permissions on the directory to ensure it's accessible
-		if err := os.Chmod(dir, 0755); err != nil {
-			log.Printf("Warning: Failed to set permissions on directory %s: %v", dir, err)
-			// Continue anyway - we'll try to create the file
-		}
-
-		// Log directory info
-		if info, err := os.Stat(dir); err == nil {
-			log.Printf("Directory %s created with mode: %v", dir, info.Mode())
-		} else {
-			log.Printf("Warning: Could not stat directory %s: %v", dir, err)
-		}
+// runRulesetsChecker runs the repository rulesets drift monitor
+func runRulesetsChecker(ctx context.Context, cfg *config.Config, useMarkdown bool) ([]rulesets.Drift, error) {
+	if !useMarkdown {
+		fmt.Println("Running Rulesets Drift monitor...")
 	}
 
-	// Use 0600 permissions (read/write for owner only) for better security
-	log.Printf("Writing markdown results to %s", outputPath)
-	if err := os.WriteFile(outputPath, []byte(content), 0600); err != nil {
-		log.Printf("Error writing markdown results to file %s: %v", outputPath, err)
+	// Create GitHub client
+	client := common.NewGitHubClient(ctx, cfg.GitHub.Token)
 
-		// Fallback: Try to write to a file in the current directory
-		fallbackPath := filepath.Base(outputPath)
-		log.Printf("Attempting to write to fallback location: %s", fallbackPath)
-		if err := os.WriteFile(fallbackPath, []byte(content), 0600); err != nil {
-			log.Printf("Error writing to fallback location %s: %v", fallbackPath, err)
+	// Create and run the rulesets checker
+	checker := rulesets.NewRulesetsChecker(client, cfg)
+	drift, err := checker.Run(ctx)
 
-			// Print content with special markers for extraction
-			fmt.Println("\n--- MARKDOWN_OUTPUT_START ---")
-			fmt.Println(content)
-			fmt.Println("--- MARKDOWN_OUTPUT_END ---")
-			fmt.Println("\nCouldn't write to file. Use the marked output above.")
-			return false
-		}
+	if err != nil {
+		log.Printf("Error checking rulesets: %v", err)
+		return nil, err
+	}
 
-		fmt.Printf("\nMarkdown results written to fallback location: %s\n", fallbackPath)
-		return true
+	if len(drift) > 0 {
+		if !useMarkdown {
+			fmt.Println("WARNING: The following rulesets differ from the desired state:")
+			for _, d := range drift {
+				fmt.Printf("  - %s: %s %s (%s)\n", d.Target, d.Ruleset, d.Issue, d.Details)
+			}
+		}
+		return drift, nil
 	}
 
-	// Log file info
-	if info, err := os.Stat(outputPath); err == nil {
-		log.Printf("File %s created with mode: %v, size: %d bytes", outputPath, info.Mode(), info.Size())
-	} else {
-		log.Printf("Warning: Could not stat file %s: %v", outputPath, err)
+	if !useMarkdown {
+		fmt.Println("All rulesets match the desired state")
 	}
 
-	fmt.Printf("\nMarkdown results written to %s\n", outputPath)
-	return true
+	return nil, nil
 }
 
-// sendToSlack sends the markdown content directly to a Slack webhook
-func sendToSlack(webhookURL string, content string) bool {
-	log.Printf("Preparing to send results to Slack webhook")
+// runCodeScanningChecker runs the dismissed code scanning alert monitor
+func runCodeScanningChecker(ctx context.Context, cfg *config.Config, useMarkdown bool) ([]codescanning.Dismissal, error) {
+	if !useMarkdown {
+		fmt.Println("Running Code Scanning Dismissals monitor...")
+	}
 
-	// Format content for Slack - wrap in a code block
-	summary := "Git Monitoring Results"
+	// Create GitHub client
+	client := common.NewGitHubClient(ctx, cfg.GitHub.Token)
 
-	// Extract first header as summary if available
-	contentLines := strings.Split(content, "\n")
-	for _, line := range contentLines {
-		if strings.HasPrefix(line, "## ") {
-			summary = strings.TrimPrefix(line, "## ")
-			break
-		}
-	}
+	// Create and run the code scanning checker
+	checker := codescanning.NewCodeScanningChecker(client, cfg)
+	dismissals, err := checker.Run(ctx)
 
-	// Create the Slack payload
-	type SlackText struct {
-		Type string `json:"type"`
-		Text string `json:"text"`
+	if err != nil {
+		log.Printf("Error checking code scanning alerts: %v", err)
+		return nil, err
 	}
 
-	type SlackBlock struct {
-		Type string    `json:"type"`
-		Text SlackText `json:"text,omitempty"`
+	if len(dismissals) > 0 {
+		if !useMarkdown {
+			fmt.Println("WARNING: The following code scanning alerts were recently dismissed:")
+			for _, d := range dismissals {
+				fmt.Printf("  - %s #%d: %s dismissed by %s (%s) %s\n",
+					d.Repository, d.Number, d.Rule, d.DismissedBy, d.Reason, d.URL)
+			}
+		}
+		return dismissals, nil
 	}
 
-	type SlackPayload struct {
-		Text   string       `json:"text"`
-		Blocks []SlackBlock `json:"blocks"`
+	if !useMarkdown {
+		fmt.Println("No code scanning alerts were recently dismissed")
 	}
 
-	// Create a message with code block formatting
-	formattedText := fmt.Sprintf("*%s*\n\n```\n%s\n```", summary, content)
+	return nil, nil
+}
 
-	// Slack has a 3000 character limit for block text
-	if len(formattedText) > 3000 {
-		formattedText = formattedText[:2950] + "...\n```\n(Content truncated due to size limits)"
+// runDependabotChecker runs the dismissed Dependabot alert monitor
+func runDependabotChecker(ctx context.Context, cfg *config.Config, useMarkdown bool) ([]dependabot.Dismissal, error) {
+	if !useMarkdown {
+		fmt.Println("Running Dependabot Dismissals monitor...")
 	}
 
-	payload := SlackPayload{
-		Text: summary,
-		Blocks: []SlackBlock{
-			{
-				Type: "section",
-				Text: SlackText{
-					Type: "mrkdwn",
-					Text: formattedText,
-				},
-			},
-		},
-	}
+	// Create GitHub client
+	client := common.NewGitHubClient(ctx, cfg.GitHub.Token)
+
+	// Create and run the Dependabot checker
+	checker := dependabot.NewDependabotChecker(client, cfg)
+	dismissals, err := checker.Run(ctx)
 
-	// Convert payload to JSON
-	jsonPayload, err := json.Marshal(payload)
 	if err != nil {
-		log.Printf("Error creating JSON payload: %v", err)
-		return false
+		log.Printf("Error checking Dependabot alerts: %v", err)
+		return nil, err
 	}
 
-	// Print masked webhook URL for debugging
-	if len(webhookURL) > 10 {
-		maskedURL := webhookURL[:8] + "..." + webhookURL[len(webhookURL)-10:]
-		log.Printf("Sending to webhook URL (masked): %s", maskedURL)
-	} else {
-		log.Printf("Webhook URL is too short, might be invalid")
+	if len(dismissals) > 0 {
+		if !useMarkdown {
+			fmt.Println("WARNING: The following Dependabot alerts were recently dismissed:")
+			for _, d := range dismissals {
+				fmt.Printf("  - %s #%d: %s %s (%s) dismissed by %s (%s) %s\n",
+					d.Repository, d.Number, d.Severity, d.Package, d.Advisory, d.DismissedBy, d.Reason, d.URL)
+			}
+		}
+		return dismissals, nil
 	}
 
-	// Basic validation to ensure the URL is HTTPS (more permissive)
-	if !strings.HasPrefix(webhookURL, "https://") {
-		log.Printf("Invalid Slack webhook URL: URL must begin with https://")
-		log.Printf("Please check your webhook URL and ensure it starts with https://")
-		return false
+	if !useMarkdown {
+		fmt.Println("No Dependabot alerts were recently dismissed")
 	}
 
-	// Log request details
-	log.Printf("Sending payload to Slack (size: %d bytes)", len(jsonPayload))
+	return nil, nil
+}
+
+// runPushProtectionChecker runs the secret scanning push protection bypass monitor
+func runPushProtectionChecker(ctx context.Context, cfg *config.Config, useMarkdown bool) ([]pushprotection.Bypass, error) {
+	if !useMarkdown {
+		fmt.Println("Running Push Protection Bypass monitor...")
+	}
+
+	// Create GitHub client
+	client := common.NewGitHubClient(ctx, cfg.GitHub.Token)
+
+	// Create and run the push protection checker
+	checker := pushprotection.NewPushProtectionChecker(client, cfg)
+	bypasses, err := checker.Run(ctx)
 
-	// Send request to Slack
-	// #nosec G107 -- URL is validated above to use HTTPS
-	resp, err := http.Post(webhookURL, "application/json", bytes.NewBuffer(jsonPayload))
 	if err != nil {
-		log.Printf("Error sending to Slack: %v", err)
-		log.Printf("Network details: %T", err)
-		return false
+		log.Printf("Error checking push protection bypasses: %v", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	// Check response
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		log.Printf("Slack API error: Status: %d, Response: %s", resp.StatusCode, string(body))
-		log.Printf("Headers: %v", resp.Header)
-		return false
+	if len(bypasses) > 0 {
+		if !useMarkdown {
+			fmt.Println("WARNING: Push protection was recently bypassed for the following secrets:")
+			for _, b := range bypasses {
+				fmt.Printf("  - %s #%d: %s bypassed by %s %s\n",
+					b.Repository, b.Number, b.SecretType, b.BypassedBy, b.URL)
+			}
+		}
+		return bypasses, nil
 	}
 
-	log.Printf("Successfully sent results to Slack webhook (HTTP %d)", resp.StatusCode)
-	return true
+	if !useMarkdown {
+		fmt.Println("No push protection bypasses were recently recorded")
+	}
+
+	return nil, nil
 }
 
-// getMarkdownOutputPath returns the path to write markdown results to
-// It checks command-line flag, environment variables, and falls back to a default
-func getMarkdownOutputPath(outputFlag string) string {
-	// If flag is set, use it
-	if outputFlag != "" {
-		log.Printf("Using output path from command line flag: %s", outputFlag)
-		return outputFlag
+// runDormantAccessChecker runs the dormant privileged account monitor
+func runDormantAccessChecker(ctx context.Context, cfg *config.Config, useMarkdown bool) ([]dormantaccess.Account, error) {
+	if !useMarkdown {
+		fmt.Println("Running Dormant Privileged Accounts monitor...")
 	}
 
-	// Check environment variables
-	if path := os.Getenv("MARKDOWN_OUTPUT_PATH"); path != "" {
-		log.Printf("Using output path from MARKDOWN_OUTPUT_PATH env var: %s", path)
-		return path
+	// Create GitHub client
+	client := common.NewGitHubClient(ctx, cfg.GitHub.Token)
+
+	// Create and run the dormant access checker
+	checker := dormantaccess.NewDormantAccessChecker(client, cfg)
+	accounts, err := checker.Run(ctx)
+
+	if err != nil {
+		log.Printf("Error checking dormant privileged accounts: %v", err)
+		return nil, err
 	}
 
-	// Check if we're in a GitHub Actions environment
-	if os.Getenv("GITHUB_ACTIONS") == "true" {
-		// GitHub Actions - use workspace directory if available
-		if workspace := os.Getenv("GITHUB_WORKSPACE"); workspace != "" {
-			path := filepath.Join(workspace, "markdown-result.md")
-			log.Printf("In GitHub Actions, using workspace path: %s", path)
-			return path
+	if len(accounts) > 0 {
+		if !useMarkdown {
+			fmt.Println("WARNING: The following privileged accounts have no recent activity:")
+			for _, a := range accounts {
+				fmt.Printf("  - %s (%s on %s)\n", a.Login, a.Role, a.Scope)
+			}
 		}
-		// Alternative: use temp directory which should be writable
-		path := filepath.Join(os.TempDir(), "markdown-result.md")
-		log.Printf("In GitHub Actions but no workspace, using temp dir: %s", path)
-		return path
+		return accounts, nil
 	}
 
-	// Default fallback
-	log.Printf("Using default output path: markdown-result.md")
-	return "markdown-result.md"
+	if !useMarkdown {
+		fmt.Println("No dormant privileged accounts found")
+	}
+
+	return nil, nil
 }
 
-func main() {
-	// Define command line flags
-	configPath := flag.String("config", "config.toml", "Path to configuration file")
-	markdownOutput := flag.Bool("markdown", true, "Output results in Markdown format for Slack (default)")
-	outputPath := flag.String("output", "", "Path to write markdown results (default: markdown-result.md)")
-	slackWebhook := flag.String("slack", "", "Slack webhook URL to post results directly (overrides file output)")
-	flag.Parse()
+// runDormantReposChecker runs the dormant repository monitor
+func runDormantReposChecker(ctx context.Context, cfg *config.Config, useMarkdown bool) ([]dormantrepos.Repository, error) {
+	if !useMarkdown {
+		fmt.Println("Running Dormant Repositories monitor...")
+	}
+
+	// Create GitHub client
+	client := common.NewGitHubClient(ctx, cfg.GitHub.Token)
+
+	// Create and run the dormant repository checker
+	checker := dormantrepos.NewDormantReposChecker(client, cfg)
+	repos, err := checker.Run(ctx)
 
-	// Load configuration
-	cfg, err := config.LoadConfig(*configPath)
 	if err != nil {
-		log.Fatalf("Error loading configuration: %v", err)
+		log.Printf("Error checking dormant repositories: %v", err)
+		return nil, err
 	}
 
-	// Validate configuration
-	if err := cfg.Validate(); err != nil {
-		log.Fatalf("Invalid configuration: %v", err)
+	if len(repos) > 0 {
+		if !useMarkdown {
+			fmt.Println("WARNING: The following repositories have no recent activity:")
+			for _, r := range repos {
+				fmt.Printf("  - %s (last activity %s)\n", r.Name, r.LastActivity().Format("2006-01-02"))
+			}
+		}
+		return repos, nil
 	}
 
-	// Flag to track if any monitor has experienced an actual error
-	monitorFailed := false
-	// String builder to collect markdown output
-	var markdownBuilder strings.Builder
-
-	// Run PR checker if enabled
-	var prResults []prchecker.Result
-	if cfg.Monitors.PRChecker.Enabled {
-		var prFailed bool
-		prResults, prFailed = runPRChecker(cfg, *markdownOutput)
-		if prFailed {
-			monitorFailed = true
-		}
+	if !useMarkdown {
+		fmt.Println("No dormant repositories found")
+	}
 
-		// Capture output for markdown file or Slack
-		if *markdownOutput && len(prResults) > 0 {
-			output := captureOutput(func() {
-				prchecker.PrintResultsMarkdown(prResults)
-			})
-			markdownBuilder.WriteString(output)
+	return nil, nil
+}
 
-			// Only print to console if not sending to Slack
-			if *slackWebhook == "" {
-				fmt.Print(output)
-			}
-		}
-	} else if !*markdownOutput {
-		fmt.Println("PR Checker monitor is disabled in configuration")
+// runWorkflowPermissionsChecker runs the workflow token permission monitor
+func runWorkflowPermissionsChecker(ctx context.Context, cfg *config.Config, useMarkdown bool) ([]workflowpermissions.Exposure, error) {
+	if !useMarkdown {
+		fmt.Println("Running Workflow Token Permissions monitor...")
 	}
 
-	// Run repository visibility checker if enabled
-	var repoResults []string
-	if cfg.Monitors.RepoVisibility.Enabled {
-		var repoFailed bool
-		repoResults, repoFailed = runRepoVisibilityChecker(cfg, *markdownOutput)
-		if repoFailed {
-			monitorFailed = true
-		}
+	// Create GitHub client
+	client := common.NewGitHubClient(ctx, cfg.GitHub.Token)
 
-		// Capture output for markdown file or Slack
-		if *markdownOutput && len(repoResults) > 0 {
-			output := captureOutput(func() {
-				repovisibility.PrintResultsMarkdown(repoResults)
-			})
-			markdownBuilder.WriteString(output)
+	// Create and run the workflow permissions checker
+	checker := workflowpermissions.NewWorkflowPermissionsChecker(client, cfg)
+	exposures, err := checker.Run(ctx)
 
-			// Only print to console if not sending to Slack
-			if *slackWebhook == "" {
-				fmt.Print(output)
+	if err != nil {
+		log.Printf("Error checking workflow token permissions: %v", err)
+		return nil, err
+	}
+
+	if len(exposures) > 0 {
+		if !useMarkdown {
+			fmt.Println("WARNING: The following workflow token settings undermine code review:")
+			for _, e := range exposures {
+				fmt.Printf("  - %s: %s %s\n", e.Target, e.Detail, e.URL)
 			}
 		}
-	} else if !*markdownOutput {
-		fmt.Println("Repository Visibility monitor is disabled in configuration")
+		return exposures, nil
 	}
 
-	// Determine content to write or send
-	var content string
-	if markdownBuilder.Len() > 0 {
-		content = markdownBuilder.String()
-	} else {
-		// Write a simple message when no issues were found
-		content = "## :white_check_mark: No Issues Found\n\nAll repositories are compliant with policies.\n"
+	if !useMarkdown {
+		fmt.Println("No permissive workflow token settings found")
 	}
 
-	// If Slack webhook is provided, send results directly to Slack
-	if *slackWebhook != "" {
-		log.Printf("Slack webhook provided, sending results directly")
-		if sendToSlack(*slackWebhook, content) {
-			fmt.Println("Results sent to Slack successfully")
-			// Optionally print the content to console as well for visibility
-			if *markdownOutput {
-				fmt.Println("\nContent sent to Slack:")
-				fmt.Println("-----------------------------------")
-				fmt.Println(content)
-				fmt.Println("-----------------------------------")
-			}
-		} else {
-			fmt.Println("Failed to send results to Slack")
-			// Print to console as fallback
-			fmt.Println("\n--- MARKDOWN_OUTPUT_START ---")
-			fmt.Println(content)
-			fmt.Println("--- MARKDOWN_OUTPUT_END ---")
-		}
-	} else if *markdownOutput {
-		// Otherwise, try to write to file if markdown output is enabled
-		mdOutputPath := getMarkdownOutputPath(*outputPath)
-		fileWritten := writeMarkdownToFile(mdOutputPath, content)
+	return nil, nil
+}
 
-		if !fileWritten {
-			// If we couldn't write to the file, print the content with special markers
+// runEnvironmentsChecker runs the deployment environment protection monitor
+func runEnvironmentsChecker(ctx context.Context, cfg *config.Config, useMarkdown bool) ([]environments.Violation, error) {
+	if !useMarkdown {
+		fmt.Println("Running Deployment Environment Protection monitor...")
+	}
+
+	// Create GitHub client
+	client := common.NewGitHubClient(ctx, cfg.GitHub.Token)
+
+	// Create and run the environments checker
+	checker := environments.NewEnvironmentsChecker(client, cfg)
+	violations, err := checker.Run(ctx)
+
+	if err != nil {
+		log.Printf("Error checking deployment environments: %v", err)
+		return nil, err
+	}
+
+	if len(violations) > 0 {
+		if !useMarkdown {
+			fmt.Println("WARNING: The following deployments or environments break protection rules:")
+			for _, v := range violations {
+				fmt.Printf("  - %s (%s): %s %s\n", v.Repository, v.Environment, v.Detail, v.URL)
+			}
+		}
+		return violations, nil
+	}
+
+	if !useMarkdown {
+		fmt.Println("No deployments or environments breaking protection rules found")
+	}
+
+	return nil, nil
+}
+
+// runForkRunsChecker runs the fork workflow run approval monitor
+func runForkRunsChecker(ctx context.Context, cfg *config.Config, useMarkdown bool) ([]forkruns.Approval, error) {
+	if !useMarkdown {
+		fmt.Println("Running Fork Workflow Approvals monitor...")
+	}
+
+	// Create GitHub client
+	client := common.NewGitHubClient(ctx, cfg.GitHub.Token)
+
+	// Create and run the fork workflow run checker
+	checker := forkruns.NewForkRunsChecker(client, cfg)
+	approvals, err := checker.Run(ctx)
+
+	if err != nil {
+		log.Printf("Error checking fork workflow approvals: %v", err)
+		return nil, err
+	}
+
+	if len(approvals) > 0 {
+		if !useMarkdown {
+			fmt.Println("WARNING: The following workflow runs of outside contributions were approved:")
+			for _, a := range approvals {
+				fmt.Printf("  - %s: %s run of %s approved by %s (%d secrets) %s\n",
+					a.Repository, a.Workflow, a.Contributor, a.ApprovedBy, len(a.Secrets), a.URL)
+			}
+		}
+		return approvals, nil
+	}
+
+	if !useMarkdown {
+		fmt.Println("No fork workflow runs were recently approved")
+	}
+
+	return nil, nil
+}
+
+// runRepoCreationChecker runs the repository creation monitor
+func runRepoCreationChecker(ctx context.Context, cfg *config.Config, useMarkdown bool) ([]repocreation.Repository, error) {
+	if !useMarkdown {
+		fmt.Println("Running Repository Creation monitor...")
+	}
+
+	// Create GitHub client
+	client := common.NewGitHubClient(ctx, cfg.GitHub.Token)
+
+	// Create and run the repository creation checker
+	checker := repocreation.NewRepoCreationChecker(client, cfg)
+	repos, err := checker.Run(ctx)
+
+	if err != nil {
+		log.Printf("Error checking repository creation: %v", err)
+		return nil, err
+	}
+
+	if len(repos) > 0 {
+		if !useMarkdown {
+			fmt.Println("WARNING: The following repositories were created outside the golden path:")
+			for _, r := range repos {
+				fmt.Printf("  - %s (created by %s, template %q) %s\n", r.Name, r.Creator, r.Template, r.URL)
+			}
+		}
+		return repos, nil
+	}
+
+	if !useMarkdown {
+		fmt.Println("No repositories were recently created outside the golden path")
+	}
+
+	return nil, nil
+}
+
+// runIssueHygieneChecker runs the issue hygiene monitor
+func runIssueHygieneChecker(ctx context.Context, cfg *config.Config, useMarkdown bool) ([]issuehygiene.Repository, error) {
+	if !useMarkdown {
+		fmt.Println("Running Issue Hygiene monitor...")
+	}
+
+	// Create GitHub client
+	client := common.NewGitHubClient(ctx, cfg.GitHub.Token)
+
+	// Create and run the issue hygiene checker
+	checker := issuehygiene.NewIssueHygieneChecker(client, cfg)
+	repos, err := checker.Run(ctx)
+
+	if err != nil {
+		log.Printf("Error checking issue hygiene: %v", err)
+		return nil, err
+	}
+
+	if len(repos) > 0 {
+		if !useMarkdown {
+			fmt.Println("WARNING: The following public repositories are not responding to their community:")
+			for _, r := range repos {
+				fmt.Printf("  - %s: %s %s\n", r.Name, r.Summary(), r.URL)
+			}
+		}
+		return repos, nil
+	}
+
+	if !useMarkdown {
+		fmt.Println("No issue hygiene problems found")
+	}
+
+	return nil, nil
+}
+
+// runAdminEnforcementChecker runs the audit of branch protection not enforced for administrators
+func runAdminEnforcementChecker(ctx context.Context, cfg *config.Config, useMarkdown bool) ([]adminenforcement.Repository, error) {
+	if !useMarkdown {
+		fmt.Println("Running Admin Enforcement monitor...")
+	}
+
+	// Create GitHub client
+	client := common.NewGitHubClient(ctx, cfg.GitHub.Token)
+
+	// Create and run the admin enforcement checker
+	checker := adminenforcement.NewAdminEnforcementChecker(client, cfg)
+	repos, err := checker.Run(ctx)
+
+	if err != nil {
+		log.Printf("Error checking admin enforcement: %v", err)
+		return nil, err
+	}
+
+	if len(repos) > 0 {
+		if !useMarkdown {
+			fmt.Println("WARNING: Administrators can bypass the branch protection of the following repositories:")
+			for _, r := range repos {
+				fmt.Printf("  - %s: %s %s\n", r.Name, r.Summary(), r.URL)
+			}
+		}
+		return repos, nil
+	}
+
+	if !useMarkdown {
+		fmt.Println("Branch protection is enforced for administrators")
+	}
+
+	return nil, nil
+}
+
+// runCodeownersChecker runs the CODEOWNERS coverage report
+func runCodeownersChecker(ctx context.Context, cfg *config.Config, useMarkdown bool) ([]codeowners.Repository, error) {
+	if !useMarkdown {
+		fmt.Println("Running CODEOWNERS Coverage monitor...")
+	}
+
+	// Create GitHub client
+	client := common.NewGitHubClient(ctx, cfg.GitHub.Token)
+
+	// Create and run the CODEOWNERS coverage checker
+	checker := codeowners.NewCodeownersChecker(client, cfg)
+	repos, err := checker.Run(ctx)
+
+	if err != nil {
+		log.Printf("Error checking CODEOWNERS coverage: %v", err)
+		return nil, err
+	}
+
+	if len(repos) > 0 {
+		if !useMarkdown {
+			fmt.Println("WARNING: The CODEOWNERS files of the following repositories leave files without reviewers:")
+			for _, r := range repos {
+				fmt.Printf("  - %s: %s %s\n", r.Name, r.Summary(), r.URL)
+			}
+		}
+		return repos, nil
+	}
+
+	if !useMarkdown {
+		fmt.Println("All checked repositories meet the CODEOWNERS coverage threshold")
+	}
+
+	return nil, nil
+}
+
+// runGHASChecker runs the GitHub Advanced Security utilization monitor
+func runGHASChecker(ctx context.Context, cfg *config.Config, useMarkdown bool) ([]ghas.Organization, error) {
+	if !useMarkdown {
+		fmt.Println("Running GitHub Advanced Security Utilization monitor...")
+	}
+
+	// Create GitHub client
+	client := common.NewGitHubClient(ctx, cfg.GitHub.Token)
+
+	// Create and run the GHAS utilization checker
+	checker := ghas.NewGHASChecker(client, cfg)
+	orgs, err := checker.Run(ctx)
+
+	if err != nil {
+		log.Printf("Error checking GHAS utilization: %v", err)
+		return nil, err
+	}
+
+	if !useMarkdown {
+		for _, o := range orgs {
+			fmt.Printf("%s: %d GHAS seats consumed by %d repositories\n", o.Name, o.Committers, len(o.Repositories))
+			for _, r := range o.Unused() {
+				fmt.Printf("  - WARNING: %s: %s %s\n", r.Name, r.Summary(), r.URL)
+			}
+		}
+	}
+
+	return orgs, nil
+}
+
+// runTokenHealthChecker runs the GitHub token health monitor
+func runTokenHealthChecker(ctx context.Context, cfg *config.Config, useMarkdown bool) ([]tokenhealth.Token, error) {
+	if !useMarkdown {
+		fmt.Println("Running GitHub Token Health monitor...")
+	}
+
+	// Create GitHub client
+	client := common.NewGitHubClient(ctx, cfg.GitHub.Token)
+
+	// Create and run the token health checker
+	checker := tokenhealth.NewTokenHealthChecker(client, cfg)
+	tokens, err := checker.Run(ctx)
+
+	if err != nil {
+		log.Printf("Error checking the health of the GitHub token: %v", err)
+		return nil, err
+	}
+
+	if !useMarkdown {
+		for _, t := range tokens {
+			fmt.Printf("Token of %s: %d%% of the rate limit left, %d problems\n", t.Login, t.Headroom, len(t.Problems))
+			for _, p := range t.Problems {
+				fmt.Printf("  - WARNING: %s %s\n", p.Summary, p.URL)
+			}
+		}
+	}
+
+	return tokens, nil
+}
+
+// runOpenPRChecker runs the monitor of open PRs without reviews
+func runOpenPRChecker(ctx context.Context, cfg *config.Config, useMarkdown bool) ([]openprchecker.PullRequest, error) {
+	if !useMarkdown {
+		fmt.Println("Running Open PRs Without Reviews monitor...")
+	}
+
+	// Create GitHub client
+	client := common.NewGitHubClient(ctx, cfg.GitHub.Token)
+
+	// Create and run the open PR checker
+	checker := openprchecker.NewOpenPRChecker(client, cfg)
+	prs, err := checker.Run(ctx)
+
+	if err != nil {
+		log.Printf("Error checking open PRs: %v", err)
+		return nil, err
+	}
+
+	if !useMarkdown {
+		for _, p := range prs {
+			fmt.Printf("  - WARNING: %s #%d %s %s\n", p.Repository, p.Number, p.Summary(), p.URL)
+		}
+		if len(prs) == 0 {
+			fmt.Println("No open PRs are waiting for their first review")
+		}
+	}
+
+	return prs, nil
+}
+
+// writeResultsToFile writes the results to a file
+// Returns true if writing was successful, false otherwise
+func writeResultsToFile(outputPath string, content string) bool {
+	// Mask secrets that errors included in the results may quote
+	content = redact.Secrets(content)
+
+	// Ensure directory exists if a path is specified
+	dir := filepath.Dir(outputPath)
+	if dir != "." && dir != "/" {
+		log.Printf("Creating directory: %s", dir)
+		// Create directory with permissive permissions (0755)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			log.Printf("Error creating directory %s: %v", dir, err)
+			return false
+		}
+
+		// Explicitly set permissions on the directory to ensure it's accessible
+		if err := os.Chmod(dir, 0755); err != nil {
+			log.Printf("Warning: Failed to set permissions on directory %s: %v", dir, err)
+			// Continue anyway - we'll try to create the file
+		}
+
+		// Log directory info
+		if info, err := os.Stat(dir); err == nil {
+			log.Printf("Directory %s created with mode: %v", dir, info.Mode())
+		} else {
+			log.Printf("Warning: Could not stat directory %s: %v", dir, err)
+		}
+	}
+
+	// Encrypt the results when encryption is enabled, they enumerate security weaknesses
+	data, err := encryption.Seal([]byte(content))
+	if err != nil {
+		log.Printf("Error encrypting results for %s: %v", outputPath, err)
+		return false
+	}
+
+	// Use 0600 permissions (read/write for owner only) for better security
+	log.Printf("Writing results to %s", outputPath)
+	if err := os.WriteFile(outputPath, data, 0600); err != nil {
+		log.Printf("Error writing results to file %s: %v", outputPath, err)
+
+		// Fallback: Try to write to a file in the current directory
+		fallbackPath := filepath.Base(outputPath)
+		log.Printf("Attempting to write to fallback location: %s", fallbackPath)
+		if err := os.WriteFile(fallbackPath, data, 0600); err != nil {
+			log.Printf("Error writing to fallback location %s: %v", fallbackPath, err)
+
+			// Print content with special markers for extraction
+			fmt.Println("\n--- MARKDOWN_OUTPUT_START ---")
+			fmt.Println(content)
+			fmt.Println("--- MARKDOWN_OUTPUT_END ---")
+			fmt.Println("\nCouldn't write to file. Use the marked output above.")
+			return false
+		}
+
+		fmt.Printf("\nResults written to fallback location: %s\n", fallbackPath)
+		return true
+	}
+
+	// Log file info
+	if info, err := os.Stat(outputPath); err == nil {
+		log.Printf("File %s created with mode: %v, size: %d bytes", outputPath, info.Mode(), info.Size())
+	} else {
+		log.Printf("Warning: Could not stat file %s: %v", outputPath, err)
+	}
+
+	fmt.Printf("\nResults written to %s\n", outputPath)
+	return true
+}
+
+// monitorReport is the JSON document written to a monitor's dedicated output
+type monitorReport struct {
+	Monitor  string              `json:"monitor"`
+	Account  string              `json:"account,omitempty"`
+	Results  interface{}         `json:"results"`
+	Findings []findings.Finding  `json:"findings"` // Results as findings, with their fingerprints
+	Changes  findings.Changes    `json:"changes"`
+	APIUsage usage.Monitor       `json:"api_usage"` // GitHub API calls of the monitor and the remaining rate limit
+	Metadata provenance.Metadata `json:"metadata"`  // How the report was produced
+
+	// Targets checked and skipped, the results are partial when the scan stopped early
+	Coverage coverage.Monitor `json:"coverage"`
+}
+
+// writeMonitorOutput writes the results of a single monitor to its dedicated output file
+// Returns true if writing was successful, false otherwise
+func writeMonitorOutput(output config.OutputConfig, monitor, account string, results interface{}, list []findings.Finding, changes findings.Changes, writeMarkdown func(w io.Writer), monitorCoverage coverage.Monitor, apiUsage usage.Monitor, metadata provenance.Metadata) bool {
+	var content string
+
+	switch output.Format {
+	case "json":
+		report := monitorReport{
+			Monitor:  monitor,
+			Account:  account,
+			Results:  results,
+			Findings: list,
+			Changes:  changes,
+			APIUsage: apiUsage,
+			Metadata: metadata,
+			Coverage: monitorCoverage,
+		}
+		if report.Findings == nil {
+			report.Findings = []findings.Finding{}
+		}
+		// Monitors return nil when nothing was found, keep the results a valid list
+		if reflect.ValueOf(results).IsNil() {
+			report.Results = []interface{}{}
+		}
+
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			log.Printf("Error encoding results for %s: %v", output.Path, err)
+			return false
+		}
+		content = string(data) + "\n"
+	case "csv":
+		var buf bytes.Buffer
+		if err := findings.WriteCSV(&buf, list); err != nil {
+			log.Printf("Error encoding results for %s: %v", output.Path, err)
+			return false
+		}
+		if err := metadata.WriteCSVFooter(&buf); err != nil {
+			log.Printf("Error encoding results for %s: %v", output.Path, err)
+			return false
+		}
+		content = buf.String()
+	default:
+		var buf strings.Builder
+		writeMarkdown(&buf)
+		if buf.Len() == 0 {
+			buf.WriteString(noIssuesMessage)
+		}
+		findings.WriteChangesMarkdown(&buf, changes)
+		coverage.WriteMarkdown(&buf, []coverage.Monitor{monitorCoverage}, nil)
+		coverage.WriteNotesMarkdown(&buf, []coverage.Monitor{monitorCoverage})
+		metadata.WriteMarkdown(&buf)
+		content = buf.String()
+	}
+
+	return writeResultsToFile(output.Path, content)
+}
+
+// newEvidence starts the evidence bundle of a run and loads its signer
+// It exits when the signer cannot be loaded, so runs are never left without evidence
+func newEvidence(cfg *config.Config, run *provenance.Run, metadata provenance.Metadata) (*evidence.Bundle, evidence.Signer) {
+	var signer evidence.Signer
+	switch cfg.Evidence.Signer {
+	case "key":
+		keySigner, err := evidence.LoadKeySigner(cfg.Evidence.KeyPath, cfg.Evidence.KeyPassword)
+		if err != nil {
+			log.Fatalf("Error loading evidence signing key: %v", err)
+		}
+		signer = keySigner
+	case "gpg":
+		signer = &evidence.GPGSigner{KeyID: cfg.Evidence.GPGKey}
+	}
+
+	return evidence.NewBundle(metadata.StartedAt, metadata.ConfigSHA256, run.Identities()), signer
+}
+
+// writeEvidence completes the evidence bundle of a run with the suppressions, then writes and signs it
+func writeEvidence(cfg *config.Config, bundle *evidence.Bundle, signer evidence.Signer, suppressions []suppression.Suppression) bool {
+	if suppressions != nil {
+		bundle.Suppressions = suppressions
+	}
+	bundle.FinishedAt = time.Now()
+
+	signaturePath, err := bundle.Write(cfg.Evidence.Path, signer)
+	if err != nil {
+		log.Printf("Error writing evidence: %v", err)
+		return false
+	}
+
+	if signaturePath != "" {
+		log.Printf("Evidence written to %s, signature to %s", cfg.Evidence.Path, signaturePath)
+	} else {
+		log.Printf("Evidence written to %s", cfg.Evidence.Path)
+	}
+	return true
+}
+
+// newProvenance records the provenance of a run: the binary version, configuration hash and token identities
+// The configuration hash is required for evidence, so the run exits when it cannot be computed with evidence enabled
+func newProvenance(cfg *config.Config, configPath string, startedAt time.Time, runID string) *provenance.Run {
+	configHash, err := provenance.HashFile(configPath)
+	if err != nil {
+		if cfg.Evidence.Enabled {
+			log.Fatalf("Error hashing configuration for evidence: %v", err)
+		}
+		log.Printf("Error hashing configuration, reports will not include its hash: %v", err)
+	}
+
+	ctx := context.Background()
+	var identities []provenance.Identity
+	for _, accountCfg := range cfg.AccountConfigs() {
+		identity := provenance.Identity{Account: accountCfg.Account}
+		user, err := common.NewGitHubClient(ctx, accountCfg.GitHub.Token).GetAuthenticatedUser(ctx)
+		if err != nil {
+			log.Printf("Error getting the login of the token, reports will show it as unknown: %v", err)
+			identity.Error = err.Error()
+		} else {
+			identity.Login = user.GetLogin()
+		}
+		identities = append(identities, identity)
+	}
+
+	return provenance.NewRun(runID, buildVersion(), configHash, startedAt, identities)
+}
+
+// loadCheckpoint loads the progress of the scan to resume, or nil to start a new scan
+// Progress saved with a different configuration is not resumed, as its targets may have changed
+func loadCheckpoint(cfg *config.Config, configSHA256 string) *checkpoint.Checkpoint {
+	saved, err := checkpoint.Load(cfg.Checkpoint.Path)
+	if err != nil {
+		log.Fatalf("Error loading checkpoint: %v", err)
+	}
+	if saved == nil {
+		log.Printf("No checkpoint found at %s, starting a new scan", cfg.Checkpoint.Path)
+		return nil
+	}
+	if saved.ConfigSHA256 != configSHA256 {
+		log.Printf("The configuration changed since the checkpoint was saved, starting a new scan")
+		return nil
+	}
+
+	log.Printf("Resuming the scan started at %s", saved.StartedAt.Format(time.RFC3339))
+	return saved
+}
+
+// stopOnInterrupt lets the checks in flight finish when the process is interrupted, so the progress is saved
+// A second interrupt exits immediately
+func stopOnInterrupt() {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		log.Printf("Interrupted, finishing the checks in flight and saving progress. Interrupt again to exit immediately")
+		common.Interrupt()
+		<-signals
+		os.Exit(130)
+	}()
+}
+
+// buildVersion returns the version of the binary, set at build time with -ldflags "-X main.version=..."
+// Binaries built without it report the module version from go install, or "dev"
+func buildVersion() string {
+	if version != "dev" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return version
+}
+
+// userAgent returns the product GitHub API requests identify as, the configured one or git-monitor with its version
+func userAgent(cfg *config.Config) string {
+	if cfg.GitHub.UserAgent != "" {
+		return cfg.GitHub.UserAgent
+	}
+	return common.DefaultUserAgent + "/" + buildVersion()
+}
+
+// sendToSlack sends the markdown content directly to a Slack webhook
+func sendToSlack(webhookURL string, content string) bool {
+	log.Printf("Preparing to send results to Slack webhook")
+	content = redact.Secrets(content)
+
+	// Format content for Slack - wrap in a code block
+	summary := "Git Monitoring Results"
+
+	// Extract first header as summary if available
+	contentLines := strings.Split(content, "\n")
+	for _, line := range contentLines {
+		if strings.HasPrefix(line, "## ") {
+			summary = strings.TrimPrefix(line, "## ")
+			break
+		}
+	}
+
+	// Create the Slack payload
+	type SlackText struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+
+	type SlackBlock struct {
+		Type string    `json:"type"`
+		Text SlackText `json:"text,omitempty"`
+	}
+
+	type SlackPayload struct {
+		Text   string       `json:"text"`
+		Blocks []SlackBlock `json:"blocks"`
+	}
+
+	// Create a message with code block formatting
+	formattedText := fmt.Sprintf("*%s*\n\n```\n%s\n```", summary, content)
+
+	// Slack has a 3000 character limit for block text
+	if len(formattedText) > 3000 {
+		formattedText = formattedText[:2950] + "...\n```\n(Content truncated due to size limits)"
+	}
+
+	payload := SlackPayload{
+		Text: summary,
+		Blocks: []SlackBlock{
+			{
+				Type: "section",
+				Text: SlackText{
+					Type: "mrkdwn",
+					Text: formattedText,
+				},
+			},
+		},
+	}
+
+	// Convert payload to JSON
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Error creating JSON payload: %v", err)
+		return false
+	}
+
+	// Log the webhook URL for debugging, masked as anyone knowing it can post to the channel
+	log.Printf("Sending to webhook URL: %s", redact.Secrets(webhookURL))
+
+	// Basic validation to ensure the URL is HTTPS (more permissive)
+	if !strings.HasPrefix(webhookURL, "https://") {
+		log.Printf("Invalid Slack webhook URL: URL must begin with https://")
+		log.Printf("Please check your webhook URL and ensure it starts with https://")
+		return false
+	}
+
+	// Log request details
+	log.Printf("Sending payload to Slack (size: %d bytes)", len(jsonPayload))
+
+	// Send request to Slack
+	// #nosec G107 -- URL is validated above to use HTTPS
+	resp, err := http.Post(webhookURL, "application/json", bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		log.Printf("Error sending to Slack: %v", err)
+		log.Printf("Network details: %T", err)
+		return false
+	}
+	defer resp.Body.Close()
+
+	// Check response
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		log.Printf("Slack API error: Status: %d, Response: %s", resp.StatusCode, string(body))
+		log.Printf("Headers: %v", resp.Header)
+		return false
+	}
+
+	log.Printf("Successfully sent results to Slack webhook (HTTP %d)", resp.StatusCode)
+	return true
+}
+
+// notificationSender resolves the targets of undelivered notifications to the webhooks of this run
+// Notifications for a target without a webhook stay queued
+func notificationSender(cfg *config.Config, slackWebhook string) notify.Sender {
+	return func(target, content string) bool {
+		var webhook string
+		switch target {
+		case notify.TargetSlack:
+			webhook = slackWebhook
+		case notify.TargetPage:
+			webhook = cfg.Scoring.PageWebhook
+		case notify.TargetOps:
+			webhook = cfg.Notifications.Ops.Webhook
+		case notify.TargetSLA:
+			webhook = cfg.SLA.Webhook
+			if webhook == "" {
+				webhook = slackWebhook
+			}
+		default:
+			if team, ok := strings.CutPrefix(target, notify.TargetTeamPrefix); ok {
+				webhook = cfg.Ownership.Webhooks[team]
+			}
+			if name, ok := strings.CutPrefix(target, notify.TargetEscalationPrefix); ok {
+				for _, policy := range cfg.Escalation.Policies {
+					if policy.Name == name {
+						webhook = policy.Webhook
+					}
+				}
+			}
+		}
+		if webhook == "" {
+			return false
+		}
+		return sendToSlack(webhook, content)
+	}
+}
+
+// retryNotifications sends the notifications that earlier runs could not deliver
+func retryNotifications(cfg *config.Config, send notify.Sender) {
+	delivered, pending, err := notify.Retry(cfg.State.Path, send, time.Now(), cfg.Notifications.Retry.MaxAge.Duration)
+	if err != nil {
+		log.Printf("Error retrying undelivered notifications: %v", err)
+		return
+	}
+	if delivered > 0 || pending > 0 {
+		log.Printf("Delivered %d undelivered notifications, %d still queued", delivered, pending)
+	}
+}
+
+// queueNotification keeps a notification that could not be delivered to retry it, when retries are enabled
+// Returns true when the notification was queued
+func queueNotification(cfg *config.Config, target, content string) bool {
+	if !cfg.Notifications.Retry.Enabled {
+		return false
+	}
+	if err := notify.Enqueue(cfg.State.Path, target, redact.Secrets(content), time.Now()); err != nil {
+		log.Printf("Error queueing undelivered notification: %v", err)
+		return false
+	}
+	log.Printf("Queued the undelivered %s notification for a retry", target)
+	return true
+}
+
+// notifyChanged reports whether a notification target has findings to notify, always true unless notifications
+// are only sent on change. Errors reading the state notify anyway, to never miss findings
+func notifyChanged(cfg *config.Config, target string, list []findings.Finding) bool {
+	if !cfg.Notifications.OnChangeOnly {
+		return true
+	}
+	changed, err := notify.Changed(cfg.State.Path, target, list)
+	if err != nil {
+		log.Printf("Error comparing the findings notified to %s, notifying anyway: %v", target, err)
+		return true
+	}
+	if !changed {
+		log.Printf("Findings unchanged since the last %s notification, not notifying", target)
+	}
+	return changed
+}
+
+// recordNotified records the findings notified to a target, when notifications are only sent on change
+func recordNotified(cfg *config.Config, target string, list []findings.Finding) {
+	if !cfg.Notifications.OnChangeOnly {
+		return
+	}
+	if err := notify.Notified(cfg.State.Path, target, list); err != nil {
+		log.Printf("Error recording the findings notified to %s: %v", target, err)
+	}
+}
+
+// sendOpsAlert alerts the operations channel about monitors that failed, when one is configured
+// Alerts that cannot be delivered are queued like other notifications
+func sendOpsAlert(cfg *config.Config, failures []notify.Failure) {
+	if cfg.Notifications.Ops.Webhook == "" || len(failures) == 0 {
+		return
+	}
+
+	log.Printf("%d monitor runs failed, alerting the ops channel", len(failures))
+	content := render(func(w io.Writer) {
+		notify.WriteFailuresMarkdown(w, failures)
+	})
+	if !sendToSlack(cfg.Notifications.Ops.Webhook, content) {
+		fmt.Println("Failed to send the monitor failure alert to the ops channel")
+		queueNotification(cfg, notify.TargetOps, content)
+	}
+}
+
+// newOwnershipResolver returns the resolver of the teams owning repositories, looking them up with the token
+// of the account of each finding
+func newOwnershipResolver(cfg *config.Config) *ownership.Resolver {
+	clients := make(map[string]common.GitHubClientInterface)
+	for _, accountCfg := range cfg.AccountConfigs() {
+		clients[accountCfg.Account] = common.NewGitHubClient(context.Background(), accountCfg.GitHub.Token)
+	}
+	return ownership.NewResolver(cfg.Ownership, func(account string) common.GitHubClientInterface {
+		return clients[account]
+	})
+}
+
+// syncProjects syncs the project board with the findings of a run, authenticating with the projects token,
+// or the [github] token when there is none
+func syncProjects(cfg *config.Config, run projects.Run) bool {
+	token := cfg.Projects.Token
+	if token == "" {
+		token = cfg.GitHub.Token
+	}
+	result, err := projects.New(cfg.Projects, token).Sync(context.Background(), run)
+	if err != nil {
+		log.Printf("Error syncing project board: %v", err)
+		return false
+	}
+	log.Printf("Synced project board: %d created, %d updated, %d reopened, %d resolved",
+		result.Created, result.Updated, result.Reopened, result.Resolved)
+	return true
+}
+
+// openServiceNowRecords opens a ServiceNow record for each severe finding without an active one
+func openServiceNowRecords(cfg *config.Config, list []findings.Finding) bool {
+	result, err := servicenow.New(cfg.ServiceNow).Notify(context.Background(), list)
+	for _, number := range result.Opened {
+		log.Printf("Opened ServiceNow %s %s", cfg.ServiceNow.Table, number)
+	}
+	if err != nil {
+		log.Printf("Error opening ServiceNow records: %v", err)
+		return false
+	}
+	if result.Existing > 0 {
+		log.Printf("%d severe findings already have an active ServiceNow %s", result.Existing, cfg.ServiceNow.Table)
+	}
+	return true
+}
+
+// publishEvents publishes events to each enabled sink, reporting whether all of them received the events
+func publishEvents(cfg *config.Config, list []events.Event) bool {
+	var sinks []events.Sink
+	if cfg.Events.EventGrid.Enabled {
+		sinks = append(sinks, events.NewEventGrid(cfg.Events.EventGrid))
+	}
+	if cfg.Events.PubSub.Enabled {
+		sinks = append(sinks, events.NewPubSub(cfg.Events.PubSub))
+	}
+
+	published := true
+	for _, sink := range sinks {
+		if err := events.Publish(context.Background(), sink, list); err != nil {
+			log.Printf("Error publishing events to %s: %v", sink.Name(), err)
+			published = false
+			continue
+		}
+		log.Printf("Published %d events to %s", len(list), sink.Name())
+	}
+	return published
+}
+
+// sendTeamNotifications sends each team with a webhook the findings of its repositories, regardless of
+// the notification schedule. Notifications that cannot be delivered are queued like other notifications
+func sendTeamNotifications(cfg *config.Config, teams []ownership.Team, repoName func(string) string, footer string) {
+	for _, team := range teams {
+		webhook := cfg.Ownership.Webhooks[team.Name]
+		target := notify.TargetTeamPrefix + team.Name
+		if webhook == "" || !notifyChanged(cfg, target, team.Findings) {
+			continue
+		}
+
+		content := render(func(w io.Writer) {
+			ownership.WriteTeamMarkdown(w, team, repoName)
+		}) + footer
+		if !sendToSlack(webhook, content) {
+			fmt.Printf("Failed to send the findings of team %s\n", team.Name)
+			if queueNotification(cfg, target, content) {
+				recordNotified(cfg, target, team.Findings)
+			}
+			continue
+		}
+		log.Printf("Sent %d findings to team %s", len(team.Findings), team.Name)
+		recordNotified(cfg, target, team.Findings)
+	}
+}
+
+// sendEscalationNotifications sends each escalation policy with a webhook the findings it escalated in this run,
+// regardless of the notification schedule. Notifications that cannot be delivered are queued like other notifications
+func sendEscalationNotifications(cfg *config.Config, escalated []escalation.Escalated, repoName func(string) string, footer string, now time.Time) {
+	for _, policy := range cfg.Escalation.Policies {
+		policyEscalated := escalation.ByPolicy(escalated, policy.Name)
+		if policy.Webhook == "" || len(policyEscalated) == 0 {
+			continue
+		}
+
+		content := render(func(w io.Writer) {
+			escalation.WritePolicyMarkdown(w, policy.Name, policyEscalated, repoName, now)
+		}) + footer
+		if !sendToSlack(policy.Webhook, content) {
+			fmt.Printf("Failed to send the findings escalated by %s\n", policy.Name)
+			queueNotification(cfg, notify.TargetEscalationPrefix+policy.Name, content)
+			continue
+		}
+		log.Printf("Sent %d findings escalated by %s", len(policyEscalated), policy.Name)
+	}
+}
+
+// repoSLADeadlines returns the remediation deadlines set in the policy files of the repositories with findings,
+// by lowercased "owner/repo", no shorter than min_sla_days. Policy files are read with the token of the account
+// the findings were reported for. Within policy.WithCache, files the monitors already loaded are not fetched again
+func repoSLADeadlines(ctx context.Context, cfg *config.Config, records []state.Record) map[string]time.Duration {
+	loaders := make(map[string]*policy.Loader)
+	for _, accountCfg := range cfg.AccountConfigs() {
+		loaders[accountCfg.Account] = policy.NewLoader(common.NewGitHubClient(ctx, accountCfg.GitHub.Token), cfg.RepoPolicy.Path)
+	}
+
+	return sla.RepositoryDeadlines(ctx, loaders, records, cfg.RepoPolicy.MinSLADays)
+}
+
+// sendSLANotification sends the findings whose remediation deadline passed since the previous run to the SLA
+// webhook, regardless of the notification schedule. A notification that cannot be delivered is queued
+func sendSLANotification(cfg *config.Config, webhook string, breaches []sla.Breach, repoName func(string) string, footer string, now time.Time) {
+	content := render(func(w io.Writer) {
+		sla.WriteNotificationMarkdown(w, breaches, repoName, now)
+	}) + footer
+	if !sendToSlack(webhook, content) {
+		fmt.Println("Failed to send the SLA breaches")
+		queueNotification(cfg, notify.TargetSLA, content)
+		return
+	}
+	log.Printf("Sent %d SLA breaches", len(breaches))
+}
+
+// sendSlackNotification sends the results to Slack, applying the notification schedule when it is enabled
+// footer is appended to the content that is sent, including digests, and the sections of monitors with critical
+// findings in list are sent outside business hours
+// Returns true when the results were sent, queued for a retry or held for the next digest
+func sendSlackNotification(cfg *config.Config, webhookURL string, sections []notify.Section, list []findings.Finding, content, footer string, useMarkdown bool) bool {
+	var scheduler *notify.Scheduler
+	var plan *notify.Plan
+
+	if cfg.Notifications.Schedule.Enabled {
+		scheduler = notify.NewScheduler(cfg)
+
+		var err error
+		plan, err = scheduler.Plan(time.Now(), sections, list, noIssuesMessage)
+		if err != nil {
+			// Never drop findings because of the schedule, send everything instead
+			log.Printf("Error applying notification schedule, sending results immediately: %v", err)
+			plan = nil
+		} else {
+			if len(plan.Queued) > 0 {
+				log.Printf("Outside business hours, queued %d monitor results for the next digest", len(plan.Queued))
+			}
+			if plan.Content == "" {
+				fmt.Println("Outside business hours, no critical results to send to Slack")
+				return true
+			}
+			content = plan.Content
+		}
+	}
+	content += footer
+
+	if !sendToSlack(webhookURL, content) {
+		// A queued digest is kept so it is sent with the next notification, unless the notification
+		// including it is queued for a retry
+		fmt.Println("Failed to send results to Slack")
+		queued := queueNotification(cfg, notify.TargetSlack, content)
+		if queued && plan != nil {
+			if err := scheduler.Delivered(plan); err != nil {
+				log.Printf("Error clearing notification digest: %v", err)
+			}
+		}
+		// Print to console as fallback
+		fmt.Println("\n--- MARKDOWN_OUTPUT_START ---")
+		fmt.Println(content)
+		fmt.Println("--- MARKDOWN_OUTPUT_END ---")
+		return queued
+	}
+
+	fmt.Println("Results sent to Slack successfully")
+	// Optionally print the content to console as well for visibility
+	if useMarkdown {
+		fmt.Println("\nContent sent to Slack:")
+		fmt.Println("-----------------------------------")
+		fmt.Println(content)
+		fmt.Println("-----------------------------------")
+	}
+
+	if plan != nil {
+		if err := scheduler.Delivered(plan); err != nil {
+			log.Printf("Error clearing notification digest: %v", err)
+		}
+	}
+	return true
+}
+
+// getMarkdownOutputPath returns the path to write markdown results to
+// It checks command-line flag, environment variables, and falls back to a default
+func getMarkdownOutputPath(outputFlag string) string {
+	// If flag is set, use it
+	if outputFlag != "" {
+		log.Printf("Using output path from command line flag: %s", outputFlag)
+		return outputFlag
+	}
+
+	// Check environment variables
+	if path := os.Getenv("MARKDOWN_OUTPUT_PATH"); path != "" {
+		log.Printf("Using output path from MARKDOWN_OUTPUT_PATH env var: %s", path)
+		return path
+	}
+
+	// Check if we're in a GitHub Actions environment
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		// GitHub Actions - use workspace directory if available
+		if workspace := os.Getenv("GITHUB_WORKSPACE"); workspace != "" {
+			path := filepath.Join(workspace, "markdown-result.md")
+			log.Printf("In GitHub Actions, using workspace path: %s", path)
+			return path
+		}
+		// Alternative: use temp directory which should be writable
+		path := filepath.Join(os.TempDir(), "markdown-result.md")
+		log.Printf("In GitHub Actions but no workspace, using temp dir: %s", path)
+		return path
+	}
+
+	// Default fallback
+	log.Printf("Using default output path: markdown-result.md")
+	return "markdown-result.md"
+}
+
+func main() {
+	maskLogs()
+	defer exitOnPanic()
+
+	// Dispatch subcommands before parsing the flags of a monitoring run
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "history":
+			os.Exit(runHistory(os.Args[2:]))
+		case "reports":
+			os.Exit(runReports(os.Args[2:]))
+		case "serve":
+			os.Exit(runServe(os.Args[2:]))
+		case "decrypt":
+			os.Exit(runDecrypt(os.Args[2:]))
+		case "merge-reports":
+			os.Exit(runMergeReports(os.Args[2:]))
+		}
+	}
+
+	// Define command line flags
+	configPath := flag.String("config", "config.toml", "Path to configuration file")
+	markdownOutput := flag.Bool("markdown", true, "Output results in Markdown format for Slack (default)")
+	outputPath := flag.String("output", "", "Path to write markdown results (default: markdown-result.md)")
+	slackWebhook := flag.String("slack", "", "Slack webhook URL to post results directly (overrides file output)")
+	maxAPICalls := flag.Int64("max-api-calls", 0, "Stop checking repositories after this many GitHub API calls and report partial results (default: unlimited)")
+	deadline := flag.Duration("deadline", 0, "Stop checking new repositories this long after the start, e.g. 10m, and report partial results (default: none)")
+	resume := flag.Bool("resume", false, "Resume the scan saved in the checkpoint, skipping the repositories it already checked")
+	pprofAddr := flag.String("pprof", "", "Serve runtime profiles on this address while scanning, e.g. :6060 (default: disabled)")
+	concurrency := flag.Int("concurrency", 0, "Number of monitors run at the same time, sharing each token's rate limit, 1 to run them one at a time (default: all enabled monitors)")
+	sampleSize := flag.String("sample", "", "Check only this many, e.g. 500, or this percentage, e.g. 10%, of the repositories listed for each organization, team or user, rotating through them across runs (default: all)")
+	sampleSeed := flag.Int64("sample-seed", time.Now().Unix()/int64(24*time.Hour/time.Second), "Round of the --sample rotation, the same seed checks the same repositories (default: days since the Unix epoch, rotating daily)")
+	shardSpec := flag.String("shard", "", "Check only this shard of the repositories, e.g. 2/5 for the second of five parallel runs, whose JSON outputs merge-reports combines (default: all)")
+	flag.Parse()
+
+	startedAt := time.Now()
+	if *pprofAddr != "" {
+		startProfiling(*pprofAddr)
+	}
+
+	// Load configuration
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Error loading configuration: %v", err)
+	}
+	redact.Register(cfg.Secrets()...)
+	redact.Register(*slackWebhook)
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	if err := enableEncryption(cfg); err != nil {
+		log.Fatalf("Error loading encryption key: %v", err)
+	}
+	if *resume && !cfg.Checkpoint.Enabled {
+		log.Fatalf("--resume requires checkpoints to be enabled in the [checkpoint] section")
+	}
+	if *concurrency < 0 {
+		log.Fatalf("--concurrency must not be negative")
+	}
+
+	// Tag the GitHub API requests of the run, so organization admins can attribute them in audit logs
+	// The run ID is also the ID of the archived report and is recorded in the metadata of the reports
+	runID := archive.NewID(startedAt)
+	common.SetUserAgent(userAgent(cfg))
+	common.SetRunID(runID)
+	log.Printf("Starting run %s as %s", runID, common.UserAgent(context.Background()))
+
+	// Split the repositories of large estates between parallel runs, each checking its own shard
+	if *shardSpec != "" {
+		shard, err := common.ParseShard(*shardSpec)
+		if err != nil {
+			log.Fatalf("--shard: %v", err)
+		}
+		common.SetShard(&shard)
+		log.Printf("Checking shard %s of the repositories, merge the JSON outputs of the shards with merge-reports", shard)
+	}
+
+	// Check a slice of large estates per run, rotating through all repositories across runs
+	if *sampleSize != "" {
+		sample, err := common.ParseSample(*sampleSize, *sampleSeed)
+		if err != nil {
+			log.Fatalf("--sample: %v", err)
+		}
+		common.SetSample(&sample)
+		log.Printf("Checking a sample of %s of the listed repositories, rerun with --sample-seed %d to check the same ones", *sampleSize, *sampleSeed)
+	}
+
+	// Ping the dead man's switch, so it alerts when runs stop or hang
+	pinger := heartbeat.New(cfg.Heartbeat)
+	if err := pinger.Start(); err != nil {
+		log.Printf("Error pinging heartbeat at start: %v", err)
+	}
+
+	// Leave the rest of the token's rate limit to other automation, skipping what the budget does not cover
+	common.SetAPICallBudget(*maxAPICalls)
+
+	// Bound the run's duration, letting the checks in flight at the deadline finish
+	if *deadline > 0 {
+		common.SetDeadline(startedAt.Add(*deadline))
+	}
+
+	// Flag to track if any monitor has experienced an actual error
+	monitorFailed := false
+	// Markdown output of each monitor, in the order the monitors ran
+	var sections []notify.Section
+
+	// Load the findings of the previous run to report changes since then
+	var tracker *state.Tracker
+	if cfg.State.Enabled {
+		tracker, err = state.NewTracker(cfg.State.Path)
+		if err != nil {
+			log.Printf("Error loading state, changes since the last run will not be reported: %v", err)
+		}
+	}
+
+	// Targets each monitor checked, and those it did not because the scan stopped
+	var coverages []coverage.Monitor
+	// Targets of each monitor, to compare the coverage of organizations
+	var targets []common.Coverage
+	// Targets each monitor scanned compared with its previous run, for the coverage report
+	var scopes []coverage.Scope
+
+	// Suppressed findings, to list the suppressions that expire soon
+	var suppressions []suppression.Suppression
+
+	// Findings that count towards the risk score, i.e. that are not suppressed
+	var scored []findings.Finding
+
+	// Slack notifications get redacted sections when redaction is enabled, the full report keeps the details
+	var redactor *redact.Redactor
+	var redactedSections []notify.Section
+	if cfg.Redaction.Enabled {
+		redactor, err = redact.NewRedactor(cfg.Redaction)
+		if err != nil {
+			log.Fatalf("Error loading redaction aliases: %v", err)
+		}
+	}
+
+	// addSection adds a section to the report, and prints it to the console when not sending to Slack
+	// write renders the section with repoName naming repositories, nil in the report and the redactor's aliases
+	// in redacted notifications. Sections write renders empty are left out, of redacted notifications only when
+	// they would name repositories
+	addSection := func(name string, write func(w io.Writer, repoName func(string) string)) {
+		output := render(func(w io.Writer) { write(w, nil) })
+		if output == "" {
+			return
+		}
+		sections = append(sections, notify.Section{Monitor: name, Content: output})
+		if redactor != nil {
+			if redacted := render(func(w io.Writer) { write(w, redactor.Repository) }); redacted != "" {
+				redactedSections = append(redactedSections, notify.Section{Monitor: name, Content: redacted})
+			}
+		}
+
+		// Only print to console if not sending to Slack
+		if *slackWebhook == "" {
+			fmt.Print(output)
+		}
+	}
+
+	// Record how the reports of this run are produced
+	provenanceRun := newProvenance(cfg, *configPath, startedAt, runID)
+
+	// Record the run as evidence, signing it with the configured signer
+	var bundle *evidence.Bundle
+	var signer evidence.Signer
+	if cfg.Evidence.Enabled {
+		bundle, signer = newEvidence(cfg, provenanceRun, provenanceRun.Metadata())
+	}
+
+	// Save progress after each monitor, continuing the saved progress when resuming
+	var progress, resumed *checkpoint.Checkpoint
+	if cfg.Checkpoint.Enabled {
+		configHash := provenanceRun.Metadata().ConfigSHA256
+		if *resume {
+			resumed = loadCheckpoint(cfg, configHash)
+		}
+		progress = checkpoint.New(startedAt, configHash)
+		if resumed != nil {
+			progress.StartedAt = resumed.StartedAt
+		}
+		stopOnInterrupt()
+	}
+
+	// Reuse membership lookups of earlier runs, saving those of this run for the next
+	if cfg.Membership.Enabled {
+		if err := common.LoadMembershipCache(cfg.Membership.Path, cfg.Membership.TTL.Duration); err != nil {
+			log.Printf("Error loading membership cache, looking memberships up again: %v", err)
+		}
+	}
+
+	// API calls of each monitor, reported at the end of the run to plan schedules around the rate limit
+	apiUsage := usage.NewReport()
+
+	// Monitor runs that failed, sent to the ops channel so failures are not only visible in logs
+	var failures []notify.Failure
+
+	// Findings of every monitor and the monitors that failed, for the report archive
+	var runFindings []findings.Finding
+	var failedMonitors []string
+
+	// Monitor runs that covered their whole scope, whose findings missing from this run resolved
+	completeRuns := make(map[string]bool)
+
+	// Run each enabled monitor for each account, up to the configured number at a time
+	// Their results are processed in the order of the monitors, as each run finishes
+	var jobs []*monitorJob
+	for _, m := range monitors {
+		if !m.EnabledInAnyAccount(cfg) {
+			if !*markdownOutput {
+				fmt.Printf("%s monitor is disabled in configuration\n", m.Name)
+			}
+			continue
+		}
+
+		for _, accountCfg := range cfg.AccountConfigs() {
+			if !m.Enabled(accountCfg) {
+				continue
+			}
+
+			job := &monitorJob{monitor: m, cfg: accountCfg, done: make(chan struct{})}
+			if resumed != nil {
+				if saved, ok := resumed.Monitor(job.Key()); ok {
+					job.resume = &saved
+				}
+			}
+			jobs = append(jobs, job)
+		}
+	}
+	// Without --concurrency every enabled monitor runs at the same time
+	if *concurrency == 0 {
+		*concurrency = max(len(jobs), 1)
+	}
+	// Monitors and reports share the rate limiters of their tokens and the policy files already fetched
+	runCtx := policy.WithCache(common.WithSharedRateLimiters(context.Background()))
+	startMonitorJobs(runCtx, jobs, *concurrency, *markdownOutput)
+
+	totalResults := 0
+	for _, job := range jobs {
+		key := job.Key()
+		<-job.done
+		m, accountCfg, run, monitorUsage := job.monitor, job.cfg, job.run, job.usage
+		apiUsage.AddMonitor(monitorUsage)
+		if run.Failed {
+			monitorFailed = true
+			failures = append(failures, notify.Failure{Monitor: m.Name, Account: accountCfg.Account, Err: run.Err})
+			failedMonitors = append(failedMonitors, key)
+		}
+		runFindings = append(runFindings, run.Findings...)
+		totalResults += run.Count
+		if bundle != nil {
+			bundle.AddMonitor(m.Key, accountCfg.Account, run.Failed, run.Findings, run.Coverage.Skipped)
+		}
+
+		monitorCoverage := coverage.New(m.Key, accountCfg.Account, run.Coverage)
+		coverages = append(coverages, monitorCoverage)
+		targets = append(targets, run.Coverage)
+		if cfg.CoverageReport.Enabled {
+			// Failed, sharded and sampled runs do not cover the monitor's whole scope, so they are not compared
+			var previous []string
+			compared := false
+			if !run.Failed && monitorCoverage.Unsampled == 0 && monitorCoverage.OtherShards == 0 {
+				previous, compared = tracker.Scope(key, coverage.Targets(run.Coverage))
+			}
+			scopes = append(scopes, coverage.NewScope(m.Key, accountCfg.Account, run.Coverage, previous, compared))
+		}
+
+		if progress != nil {
+			err := progress.Record(key, run.Failed, run.Coverage, run.Unsuppressed)
+			if err == nil {
+				err = progress.Save(cfg.Checkpoint.Path)
+			}
+			if err != nil {
+				log.Printf("Error saving progress: %v", err)
+			}
+		}
+
+		// Compare with the previous run, unless the results are incomplete or only cover a shard or sample
+		var changes findings.Changes
+		if !run.Failed && monitorCoverage.Complete() && monitorCoverage.Unsampled == 0 && monitorCoverage.OtherShards == 0 {
+			changes = tracker.Record(key, run.Findings)
+			completeRuns[key] = true
+		}
+
+		suppressions = append(suppressions, run.Suppressed...)
+		for _, f := range run.Findings {
+			if ack := tracker.Suppression(f); ack != nil {
+				suppressions = append(suppressions, suppression.Suppression{Finding: f, Owner: ack.By, Expires: *ack.Until, Source: "Slack"})
+			} else {
+				scored = append(scored, f)
+			}
+		}
+
+		// Write to the monitor's own output if configured, otherwise render it for the markdown file or Slack
+		if output := m.Output(accountCfg); output.Path != "" {
+			if !writeMonitorOutput(output, m.Key, accountCfg.Account, run.Results, run.Findings, changes, run.WriteMarkdown, monitorCoverage, monitorUsage, provenanceRun.Metadata()) {
+				monitorFailed = true
+			}
+		} else if *markdownOutput && run.Count > 0 {
+			heading := m.Name
+			if accountCfg.Account != "" {
+				heading += " (" + accountCfg.Account + ")"
+			}
+			addSection(m.Key, func(w io.Writer, repoName func(string) string) {
+				if repoName == nil {
+					run.WriteMarkdown(w)
+				} else {
+					redactor.WriteFindingsMarkdown(w, heading, run.Findings)
+				}
+			})
+		}
+	}
+
+	// How long findings have been open is measured at the same time for escalations and SLAs
+	checkedAt := time.Now()
+
+	// Escalate the findings that stayed unresolved past an escalation policy, raising their severity
+	var escalated []escalation.Escalated
+	if cfg.Escalation.Enabled && tracker != nil {
+		escalated = escalation.Apply(tracker, cfg.Escalation.Policies, checkedAt)
+		escalation.SetSeverities(scored, escalated)
+		escalation.SetSeverities(runFindings, escalated)
+		if *markdownOutput && len(escalated) > 0 {
+			addSection("escalations", func(w io.Writer, repoName func(string) string) {
+				escalation.WriteMarkdown(w, escalated, repoName, checkedAt)
+			})
+		}
+	}
+
+	// Report the findings open past their remediation deadline
+	var breaches []sla.Breach
+	if cfg.SLA.Enabled && tracker != nil {
+		var repoDeadlines map[string]time.Duration
+		// Policy files are not read at all when min_sla_days leaves repositories the central deadlines
+		if cfg.RepoPolicy.Enabled && cfg.RepoPolicy.MinSLADays > 0 {
+			// Policy files are mostly those the monitors loaded; the rest are fetched in a scope of their own,
+			// reported with the API usage of the monitors
+			slaCtx := common.WithScope(runCtx)
+			start := usage.Take(slaCtx)
+			repoDeadlines = repoSLADeadlines(slaCtx, cfg, tracker.Reported())
+			apiUsage.AddMonitor(usage.Measure(slaCtx, "sla", "", cfg.GitHub.Token, start))
+		}
+		breaches = sla.Check(tracker, cfg.SLA.Deadlines, repoDeadlines, checkedAt)
+		if *markdownOutput && len(breaches) > 0 {
+			addSection("sla", func(w io.Writer, repoName func(string) string) {
+				sla.WriteMarkdown(w, breaches, repoName, checkedAt)
+			})
+		}
+	}
+
+	// Report changes since the previous run and persist this run's findings
+	if tracker != nil {
+		changes := tracker.Changes()
+		if *markdownOutput && !changes.Empty() {
+			addSection("changes", func(w io.Writer, repoName func(string) string) {
+				if repoName == nil {
+					findings.WriteChangesMarkdown(w, changes)
+				} else {
+					redactor.WriteChangesMarkdown(w, changes)
+				}
+			})
+		}
+
+		if err := tracker.Save(); err != nil {
+			log.Printf("Error saving state: %v", err)
+		}
+
+		// Publish the findings opened and resolved since the previous run to the event sinks
+		if list := events.FromChanges(changes, cfg.Events.Source, checkedAt); len(list) > 0 && !publishEvents(cfg, list) {
+			monitorFailed = true
+		}
+	}
+
+	// List suppressions that expire soon, so their owners can renew or fix them before the findings are reported again
+	if days := cfg.Suppressions.ExpiringSoonDays; days > 0 && *markdownOutput {
+		expiring := suppression.ExpiringSoon(suppressions, time.Now(), time.Duration(days)*24*time.Hour)
+		if len(expiring) > 0 {
+			addSection("suppressions", func(w io.Writer, repoName func(string) string) {
+				if repoName == nil {
+					suppression.WriteExpiringMarkdown(w, expiring, cfg.Location())
+					return
+				}
+				expiringFindings := make([]findings.Finding, 0, len(expiring))
+				for _, s := range expiring {
+					expiringFindings = append(expiringFindings, s.Finding)
+				}
+				redactor.WriteFindingsMarkdown(w, "Suppressions Expiring Soon", expiringFindings)
+			})
+		}
+	}
+
+	// Report the targets skipped when the scan stopped early, so partial results are not mistaken for complete ones
+	if coverage.Partial(coverages) {
+		log.Printf("Scan stopped early (%s), the results are partial", common.Stopped())
+		if *markdownOutput {
+			addSection("skipped", func(w io.Writer, repoName func(string) string) {
+				coverage.WriteMarkdown(w, coverages, repoName)
+			})
+		} else {
+			for _, m := range coverages {
+				for _, s := range m.Skipped {
+					fmt.Printf("Skipped (%s): %s %s\n", s.Reason, m.Monitor, s.Target)
+				}
+			}
+		}
+	}
+
+	// Keep the progress of a partial scan to resume it, and forget it once a scan completes
+	if progress != nil {
+		if coverage.Partial(coverages) {
+			log.Printf("Progress saved to %s, run with --resume to continue the scan", cfg.Checkpoint.Path)
+		} else if err := checkpoint.Remove(cfg.Checkpoint.Path); err != nil {
+			log.Printf("Error removing checkpoint: %v", err)
+		}
+	}
+
+	if cfg.Membership.Enabled {
+		if err := common.SaveMembershipCache(cfg.Membership.Path); err != nil {
+			log.Printf("Error saving membership cache: %v", err)
+		}
+	}
+
+	// Group the findings by the teams owning their repositories, to route them to those who can fix them
+	var teams []ownership.Team
+	if cfg.Ownership.Enabled {
+		teams = newOwnershipResolver(cfg).Group(context.Background(), scored)
+		if *markdownOutput && len(teams) > 0 {
+			addSection("teams", func(w io.Writer, repoName func(string) string) {
+				ownership.WriteMarkdown(w, teams, repoName)
+			})
+		}
+	}
+
+	// Link each finding to its runbook and the page to fix it at, so responders do not have to look for them
+	// The links name the repositories, so redacted notifications leave them out
+	if cfg.Remediation.Enabled && *markdownOutput && len(scored) > 0 {
+		addSection("remediation", func(w io.Writer, repoName func(string) string) {
+			if repoName == nil {
+				remediation.WriteMarkdown(w, cfg.Remediation.Runbooks, scored)
+			}
+		})
+	}
+
+	// Compare the organizations side by side, so the one needing attention stands out
+	// The comparison names organizations, not repositories, so it is not redacted, and runs without findings
+	// keep their no issues message
+	if cfg.Comparison.Enabled && len(scored) > 0 {
+		orgs := comparison.Compare(scored, targets, cfg.Scoring.Weights)
+		if !*markdownOutput {
+			for _, org := range orgs {
+				fmt.Printf("Organization %s: score %d, %d findings, %d%% coverage\n", org.Name, org.Score, org.Findings, org.Coverage())
+			}
+		} else {
+			addSection("organizations", func(w io.Writer, _ func(string) string) {
+				comparison.WriteMarkdown(w, orgs)
+			})
+		}
+	}
+
+	// Score the run, the scores lead the report so the riskiest repositories are seen first
+	var score scoring.Score
+	if cfg.Scoring.Enabled {
+		score = scoring.Compute(scored, cfg.Scoring.Weights)
+		if *markdownOutput && score.Total > 0 {
+			addSection("score", func(w io.Writer, repoName func(string) string) {
+				scoring.WriteMarkdown(w, score, cfg.Scoring.Threshold, repoName)
+			})
+		} else if !*markdownOutput {
+			fmt.Printf("Risk score: %d (threshold %d)\n", score.Total, cfg.Scoring.Threshold)
+		}
+	}
+
+	// Determine content to write or send, starting with the summaries
+	sections, redactedSections = orderSections(sections), orderSections(redactedSections)
+	var content string
+	if len(sections) > 0 {
+		content = notify.Join(sections)
+	} else {
+		// Write a simple message when no issues were found
+		content = noIssuesMessage
+	}
+
+	// Slack gets the redacted sections when redaction is enabled
+	slackSections, slackContent := sections, content
+	if redactor != nil {
+		slackSections, slackContent = redactedSections, noIssuesMessage
+		if len(redactedSections) > 0 {
+			slackContent = notify.Join(redactedSections)
+		}
+	}
+
+	// Note the archived, sharded and unsampled repositories left out, so a report is not mistaken for one that checked them
+	// The notes are not findings of their own, a run that only left repositories out still has no issues
+	if archived := coverage.Archived(coverages); archived > 0 {
+		log.Printf("Skipped %d archived repositories (exclude_archived)", archived)
+	}
+	if unsampled := coverage.Unsampled(coverages); unsampled > 0 {
+		log.Printf("Left %d repositories out of the sample (--sample)", unsampled)
+	}
+	if otherShards := coverage.OtherShards(coverages); otherShards > 0 {
+		log.Printf("Left %d repositories to the other shards of the scan (--shard)", otherShards)
+	}
+	if note := render(func(w io.Writer) { coverage.WriteNotesMarkdown(w, coverages) }); *markdownOutput && note != "" {
+		content += "\n" + note
+		slackContent += "\n" + note
+	}
+
+	// Report what each monitor scanned, so targets thought to be monitored but not scanned are noticed
+	// Like the notes, the report is not a finding of its own
+	if cfg.CoverageReport.Enabled {
+		if *markdownOutput {
+			content += "\n" + render(func(w io.Writer) { coverage.WriteScopeMarkdown(w, scopes, nil) })
+			var name func(string) string
+			if redactor != nil {
+				name = redactor.Repository
+			}
+			slackContent += "\n" + render(func(w io.Writer) { coverage.WriteScopeMarkdown(w, scopes, name) })
+		} else {
+			for _, scope := range scopes {
+				fmt.Printf("Coverage of %s: %d scanned, %d errored, %d skipped, %d new, %d gone\n", scope.Monitor,
+					len(scope.Scanned), len(scope.Errored), len(scope.Skipped), len(scope.Discovered), len(scope.Disappeared))
+			}
+		}
+	}
+
+	// Report the API usage of the run and the remaining rate limit of each token
+	for _, accountCfg := range cfg.AccountConfigs() {
+		apiUsage.AddRateLimit(accountCfg.Account, accountCfg.GitHub.Token)
+	}
+	if !*markdownOutput {
+		apiUsage.PrintText()
+	}
+
+	// Record what the monitoring itself cost, to track its health over time
+	if cfg.Metrics.Path != "" {
+		run := metrics.Run{FinishedAt: time.Now(), Usage: apiUsage, Coverage: coverages}
+		if err := run.WriteFile(cfg.Metrics.Path); err != nil {
+			log.Printf("Error writing metrics: %v", err)
+		}
+	}
+
+	// End every report with how it was produced
+	footer := render(provenanceRun.Metadata().WriteMarkdown)
+
+	// If Slack webhook is provided, send results directly to Slack
+	// Deliver what earlier runs could not send first, so notifications arrive in order
+	if cfg.Notifications.Retry.Enabled {
+		retryNotifications(cfg, notificationSender(cfg, *slackWebhook))
+	}
+
+	sendOpsAlert(cfg, failures)
+
+	// With on_change_only, runs finding the same findings as the last notification only write their outputs
+	if *slackWebhook != "" && notifyChanged(cfg, notify.TargetSlack, scored) {
+		log.Printf("Slack webhook provided, sending results directly")
+		if sendSlackNotification(cfg, *slackWebhook, slackSections, scored, slackContent, footer, *markdownOutput) {
+			recordNotified(cfg, notify.TargetSlack, scored)
+		}
+	}
+
+	if len(teams) > 0 && len(cfg.Ownership.Webhooks) > 0 {
+		var repoName func(string) string
+		if redactor != nil {
+			repoName = redactor.Repository
+		}
+		sendTeamNotifications(cfg, teams, repoName, footer)
+	}
+
+	if len(escalated) > 0 {
+		var repoName func(string) string
+		if redactor != nil {
+			repoName = redactor.Repository
+		}
+		sendEscalationNotifications(cfg, escalated, repoName, footer, checkedAt)
+	}
+
+	// Notify the breaches of this run, to the run's webhook unless the SLA has its own
+	if newBreaches := sla.NewBreaches(breaches); len(newBreaches) > 0 {
+		webhook := cfg.SLA.Webhook
+		if webhook == "" {
+			webhook = *slackWebhook
+		}
+		if webhook != "" {
+			var repoName func(string) string
+			if redactor != nil {
+				repoName = redactor.Repository
+			}
+			sendSLANotification(cfg, webhook, newBreaches, repoName, footer, checkedAt)
+		}
+	}
+
+	// Page immediately when the run score reaches the threshold, regardless of the notification schedule
+	paged := score.Exceeds(cfg.Scoring.Threshold) && cfg.Scoring.PageWebhook != ""
+	if paged {
+		log.Printf("Risk score %d reached the threshold %d, sending results to the page webhook", score.Total, cfg.Scoring.Threshold)
+		if !sendToSlack(cfg.Scoring.PageWebhook, slackContent+footer) {
+			fmt.Println("Failed to send results to the page webhook")
+			queueNotification(cfg, notify.TargetPage, slackContent+footer)
+		}
+	}
+
+	// List the redacted names in the full report, so its readers can look up what was sent
+	if redactor != nil && (*slackWebhook != "" || paged) {
+		content += render(redactor.WriteMappingMarkdown)
+	}
+	// The API usage is for operators, so it is left out of notifications
+	content += render(apiUsage.WriteMarkdown)
+	content += footer
+
+	// Archive the full report, to browse past runs with the reports subcommand
+	if cfg.Archive.Enabled {
+		report := archive.Report{
+			ID:         runID,
+			Trigger:    archive.TriggerRun,
+			StartedAt:  startedAt,
+			FinishedAt: time.Now(),
+			Failed:     failedMonitors,
+			Findings:   runFindings,
+			Markdown:   content,
+		}
+		if !archiveReport(cfg, report) {
+			monitorFailed = true
+		}
+	}
+
+	// Write to file if markdown output is enabled and the results were not sent to Slack,
+	// or were only sent redacted
+	if *markdownOutput && (*slackWebhook == "" || redactor != nil) {
+		mdOutputPath := getMarkdownOutputPath(*outputPath)
+		fileWritten := writeResultsToFile(mdOutputPath, content)
+
+		if !fileWritten {
+			// If we couldn't write to the file, print the content with special markers
 			// for easy extraction in GitHub Actions
 			fmt.Println("\n--- MARKDOWN_OUTPUT_START ---")
 			fmt.Println(content)
@@ -419,6 +2072,31 @@ func main() {
 		}
 	}
 
+	// Track the open findings on the project board, moving the items of resolved findings to done
+	if cfg.Projects.Enabled && !syncProjects(cfg, projects.Run{Open: scored, Found: runFindings, Complete: completeRuns}) {
+		monitorFailed = true
+	}
+
+	// Open ServiceNow records for the severe findings, e.g. those escalated to critical
+	if cfg.ServiceNow.Enabled && !openServiceNowRecords(cfg, scored) {
+		monitorFailed = true
+	}
+
+	if bundle != nil && !writeEvidence(cfg, bundle, signer, suppressions) {
+		monitorFailed = true
+	}
+
+	// The run is alive even when the risk score is over the threshold, only failed monitors count as failures
+	var pingErr error
+	if monitorFailed {
+		pingErr = pinger.Fail()
+	} else {
+		pingErr = pinger.Success()
+	}
+	if pingErr != nil {
+		log.Printf("Error pinging heartbeat: %v", pingErr)
+	}
+
 	if monitorFailed {
 		if !*markdownOutput {
 			fmt.Println("One or more monitors encountered processing errors")
@@ -426,8 +2104,15 @@ func main() {
 		os.Exit(1)
 	}
 
+	if score.Exceeds(cfg.Scoring.Threshold) {
+		if !*markdownOutput {
+			fmt.Printf("Risk score %d reached the threshold %d\n", score.Total, cfg.Scoring.Threshold)
+		}
+		os.Exit(2)
+	}
+
 	// Only show "completed successfully" if there are no problematic results
-	if !*markdownOutput && len(prResults) == 0 && len(repoResults) == 0 {
+	if !*markdownOutput && totalResults == 0 {
 		fmt.Println("All monitors completed successfully")
 	}
 }
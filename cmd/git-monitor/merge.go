@@ -0,0 +1,416 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/coverage"
+	"github.com/anupsv/git-monitoring/pkg/encryption"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/notify"
+	"github.com/anupsv/git-monitoring/pkg/provenance"
+	"github.com/anupsv/git-monitoring/pkg/redact"
+	"github.com/anupsv/git-monitoring/pkg/scoring"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	"github.com/anupsv/git-monitoring/pkg/usage"
+)
+
+// mergeReportsUsage describes the merge-reports subcommand
+const mergeReportsUsage = `Usage:
+  git-monitor merge-reports [--config config.toml] [--format json|markdown] [--output path] [--slack webhook] <report.json>...`
+
+// mergedReport is the JSON document merge-reports writes for the reports of several monitors
+type mergedReport struct {
+	Summary  mergeSummary        `json:"summary"`
+	Findings []findings.Finding  `json:"findings"` // Findings of all monitors, each reported once
+	Monitors []monitorReport     `json:"monitors"` // Merged report of each monitor, by monitor and account
+	Metadata provenance.Metadata `json:"metadata"` // How the merged reports were produced, spanning their runs
+}
+
+// mergeSummary sums up merged reports, recomputed from their merged findings, coverage and metadata
+type mergeSummary struct {
+	Reports      int   `json:"reports"`      // JSON outputs merged
+	Monitors     int   `json:"monitors"`     // Monitor runs, by monitor and account
+	Findings     int   `json:"findings"`     // Findings, each reported once
+	Repositories int   `json:"repositories"` // Repositories and organizations with findings
+	Checked      int   `json:"checked"`      // Targets checked by the monitors
+	Errored      int   `json:"errored"`      // Checked targets whose check failed
+	Skipped      int   `json:"skipped"`      // Targets not checked because a scan stopped early
+	APICalls     int64 `json:"api_calls"`    // GitHub API requests of the merged runs
+	RiskScore    int   `json:"risk_score"`   // Risk score of the findings, with [scoring] enabled in --config
+}
+
+// runMergeReports implements the merge-reports subcommand, which combines the JSON outputs of monitors written by
+// separate runs, such as the shards of a scan (see --shard) or jobs running different monitors, into one report
+// Returns the process exit code
+func runMergeReports(args []string) int {
+	fs := flag.NewFlagSet("merge-reports", flag.ContinueOnError)
+	configPath := fs.String("config", "", "Path to configuration file, for the key of [encryption] and the weights of [scoring] (default: GIT_MONITOR_ENCRYPTION_KEY, no score)")
+	format := fs.String("format", "json", "Output format: json or markdown")
+	outputPath := fs.String("output", "", "Write the merged report to this path instead of stdout, encrypted when encryption is enabled")
+	slackWebhook := fs.String("slack", "", "Slack webhook URL to post the merged report to, as markdown")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, mergeReportsUsage)
+		return 2
+	}
+	if *format != "json" && *format != "markdown" {
+		log.Printf("Invalid --format value: %s. Must be one of: json, markdown", *format)
+		return 2
+	}
+	redact.Register(*slackWebhook)
+
+	var cfg *config.Config
+	if *configPath != "" {
+		var err error
+		if cfg, err = config.LoadConfig(*configPath); err != nil {
+			log.Printf("Error loading configuration: %v", err)
+			return 1
+		}
+		redact.Register(cfg.Secrets()...)
+	}
+	if err := enableEncryption(cfg); err != nil {
+		log.Printf("Error loading encryption key: %v", err)
+		return 1
+	}
+
+	reports := make([]monitorReport, 0, fs.NArg())
+	for _, path := range fs.Args() {
+		report, err := readMonitorReport(path)
+		if err != nil {
+			log.Printf("Error reading %s: %v", path, err)
+			return 1
+		}
+		reports = append(reports, report)
+	}
+
+	var merged []monitorReport
+	for _, group := range groupReports(reports) {
+		report, err := mergeReports(group)
+		if err != nil {
+			log.Printf("Error merging reports: %v", err)
+			return 1
+		}
+		merged = append(merged, report)
+	}
+	all, summary := summarizeReports(cfg, merged, len(reports))
+	metadata := make([]provenance.Metadata, 0, len(reports))
+	for _, report := range reports {
+		metadata = append(metadata, report.Metadata)
+	}
+	mergedMetadata := mergeMetadata(metadata)
+	summary.APICalls = mergedMetadata.APICalls
+
+	var markdown string
+	if *format == "markdown" || *slackWebhook != "" {
+		var err error
+		if markdown, err = mergedMarkdown(cfg, merged, all, summary, mergedMetadata); err != nil {
+			log.Printf("Error writing merged report: %v", err)
+			return 1
+		}
+	}
+
+	content := markdown
+	if *format == "json" {
+		// The reports of a single monitor merge into a report of that monitor, like those that were merged
+		var document interface{} = mergedReport{Summary: summary, Findings: all, Monitors: merged, Metadata: mergedMetadata}
+		if len(merged) == 1 {
+			document = merged[0]
+		}
+		data, err := json.MarshalIndent(document, "", "  ")
+		if err != nil {
+			log.Printf("Error encoding merged report: %v", err)
+			return 1
+		}
+		content = string(data) + "\n"
+	}
+
+	exitCode := 0
+	if *outputPath != "" {
+		if !writeResultsToFile(*outputPath, content) {
+			exitCode = 1
+		}
+	} else if _, err := fmt.Fprint(os.Stdout, redact.Secrets(content)); err != nil {
+		log.Printf("Error writing merged report: %v", err)
+		exitCode = 1
+	}
+
+	if *slackWebhook != "" && !sendToSlack(*slackWebhook, markdown) {
+		log.Printf("Failed to send the merged report to Slack")
+		exitCode = 1
+	}
+	return exitCode
+}
+
+// readMonitorReport reads the JSON output of a monitor, decrypting it when it was written with encryption enabled
+func readMonitorReport(path string) (monitorReport, error) {
+	var report monitorReport
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return report, err
+	}
+	if data, err = encryption.Unseal(data); err != nil {
+		return report, err
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return report, fmt.Errorf("not a JSON output of a monitor: %w", err)
+	}
+	if report.Monitor == "" {
+		return report, fmt.Errorf("not a JSON output of a monitor: no monitor named")
+	}
+	return report, nil
+}
+
+// groupReports groups the reports by monitor and account, in the order the monitors run and are reported
+func groupReports(reports []monitorReport) [][]monitorReport {
+	order := make(map[string]int, len(monitors))
+	for i, m := range monitors {
+		order[m.Key] = i
+	}
+
+	var groups [][]monitorReport
+	index := make(map[string]int)
+	for _, report := range reports {
+		key := report.Monitor + "\x00" + report.Account
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], report)
+	}
+
+	// Monitors this version does not know come last, keeping the order they were given in
+	sort.SliceStable(groups, func(a, b int) bool {
+		oa, knownA := order[groups[a][0].Monitor]
+		ob, knownB := order[groups[b][0].Monitor]
+		if knownA != knownB {
+			return knownA
+		}
+		if oa != ob {
+			return oa < ob
+		}
+		return groups[a][0].Account < groups[b][0].Account
+	})
+	return groups
+}
+
+// mergeReports combines reports of the same monitor and account into one report
+// Reports of the shards of a scan must include every shard of the scan once, while reports of whole scans, e.g. of
+// jobs checking different organizations, are merged as they are. Organizations are checked by every shard, so results
+// and findings reported by several reports are only kept once
+// Sharded runs are not compared with the previous run, so merged reports have no changes
+func mergeReports(reports []monitorReport) (monitorReport, error) {
+	first := reports[0]
+	if len(reports) == 1 && first.Metadata.Shard == "" {
+		return first, nil
+	}
+	if err := checkShards(reports); err != nil {
+		return monitorReport{}, err
+	}
+
+	merged := monitorReport{Monitor: first.Monitor, Account: first.Account}
+	results := []interface{}{}
+	seenResults := make(map[string]bool)
+	var all []findings.Finding
+	coverages := make([]coverage.Monitor, 0, len(reports))
+	usages := make([]usage.Monitor, 0, len(reports))
+	metadata := make([]provenance.Metadata, 0, len(reports))
+	for _, report := range reports {
+		list, _ := report.Results.([]interface{})
+		for _, result := range list {
+			key, err := json.Marshal(result)
+			if err != nil {
+				return monitorReport{}, err
+			}
+			if !seenResults[string(key)] {
+				seenResults[string(key)] = true
+				results = append(results, result)
+			}
+		}
+		all = append(all, report.Findings...)
+		coverages = append(coverages, report.Coverage)
+		usages = append(usages, report.APIUsage)
+		metadata = append(metadata, report.Metadata)
+	}
+
+	merged.Results = results
+	merged.Findings = findings.Dedupe(all)
+	merged.Coverage = coverage.Merge(coverages)
+	merged.APIUsage = usage.Merge(usages)
+	merged.Metadata = mergeMetadata(metadata)
+	return merged, nil
+}
+
+// checkShards checks that reports of a monitor written by the shards of a scan include every shard once
+// Reports of whole scans need no check, but cannot be mixed with reports of shards
+func checkShards(reports []monitorReport) error {
+	first := reports[0]
+	sharded := 0
+	for _, report := range reports {
+		if report.Metadata.Shard != "" {
+			sharded++
+		}
+	}
+	if sharded == 0 {
+		return nil
+	}
+	if sharded < len(reports) {
+		return fmt.Errorf("reports of %s mix shards of a scan and whole scans", monitorLabel(first))
+	}
+
+	count, seen := 0, make(map[int]bool, len(reports))
+	for _, report := range reports {
+		shard, err := common.ParseShard(report.Metadata.Shard)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			count = shard.Count
+		}
+		if shard.Count != count {
+			return fmt.Errorf("shard %s of %s is of a scan split into %d shards, not %d", shard, monitorLabel(report), shard.Count, count)
+		}
+		if seen[shard.Index] {
+			return fmt.Errorf("shard %s of %s is given more than once", shard, monitorLabel(report))
+		}
+		seen[shard.Index] = true
+		if report.Metadata.ConfigSHA256 != first.Metadata.ConfigSHA256 {
+			log.Printf("Warning: shard %s of %s was run with a different configuration than shard %s", shard, monitorLabel(report), first.Metadata.Shard)
+		}
+	}
+
+	var missing []string
+	for i := 1; i <= count; i++ {
+		if !seen[i] {
+			missing = append(missing, fmt.Sprintf("%d/%d", i, count))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("the reports of shards %s of %s are missing", strings.Join(missing, ", "), monitorLabel(first))
+	}
+	return nil
+}
+
+// summarizeReports returns the findings of merged reports, each once, and their summary
+// The API calls of the summary are left to the merged metadata
+func summarizeReports(cfg *config.Config, merged []monitorReport, inputs int) ([]findings.Finding, mergeSummary) {
+	summary := mergeSummary{Reports: inputs, Monitors: len(merged)}
+	var all []findings.Finding
+	for _, report := range merged {
+		all = append(all, report.Findings...)
+		summary.Checked += report.Coverage.Checked
+		summary.Errored += report.Coverage.Errored
+		summary.Skipped += len(report.Coverage.Skipped)
+	}
+	all = findings.Dedupe(all)
+
+	repositories := make(map[string]bool)
+	for _, f := range all {
+		repositories[f.Account+"\x00"+f.Repository] = true
+	}
+	summary.Findings, summary.Repositories = len(all), len(repositories)
+	if cfg != nil && cfg.Scoring.Enabled {
+		summary.RiskScore = scoring.Compute(all, cfg.Scoring.Weights).Total
+	}
+	return all, summary
+}
+
+// mergedMarkdown writes merged reports as markdown, like the report of a run: the summary and risk score,
+// the results of each monitor, what the monitors covered, and the metadata of the merged runs
+func mergedMarkdown(cfg *config.Config, merged []monitorReport, all []findings.Finding, summary mergeSummary, metadata provenance.Metadata) (string, error) {
+	definitions := make(map[string]monitorDefinition, len(monitors))
+	for _, m := range monitors {
+		definitions[m.Key] = m
+	}
+
+	var buf strings.Builder
+	fmt.Fprintln(&buf, "## :bar_chart: Merged Report")
+	fmt.Fprintf(&buf, "%d findings in %d repositories from %d monitor runs, merged from %d reports.\n\n",
+		summary.Findings, summary.Repositories, summary.Monitors, summary.Reports)
+
+	if cfg != nil && cfg.Scoring.Enabled && summary.RiskScore > 0 {
+		scoring.WriteMarkdown(&buf, scoring.Compute(all, cfg.Scoring.Weights), cfg.Scoring.Threshold, nil)
+	}
+
+	var sections []notify.Section
+	for _, report := range merged {
+		definition, ok := definitions[report.Monitor]
+		if !ok {
+			return "", fmt.Errorf("unknown monitor %q", report.Monitor)
+		}
+		var err error
+		output := render(func(w io.Writer) { err = definition.WriteResults(w, report.Results) })
+		if err != nil {
+			return "", fmt.Errorf("decoding the results of %s: %w", monitorLabel(report), err)
+		}
+		if output != "" {
+			sections = append(sections, notify.Section{Monitor: report.Monitor, Content: annotateHeading(output, report.Account, nil)})
+		}
+	}
+	if len(sections) == 0 {
+		sections = append(sections, notify.Section{Content: noIssuesMessage})
+	}
+	buf.WriteString(notify.Join(sections))
+
+	coverages := make([]coverage.Monitor, 0, len(merged))
+	for _, report := range merged {
+		coverages = append(coverages, report.Coverage)
+	}
+	buf.WriteString("\n")
+	coverage.WriteMarkdown(&buf, coverages, nil)
+	coverage.WriteNotesMarkdown(&buf, coverages)
+	metadata.WriteMarkdown(&buf)
+	return buf.String(), nil
+}
+
+// mergeMetadata combines the metadata of reports of separate runs: it spans from the start of the first run
+// to the end of the last, with the calls and identities of all runs. The run IDs are joined
+// Reports of the same run count the calls of the whole run so far, so each run counts with its last report
+func mergeMetadata(reports []provenance.Metadata) provenance.Metadata {
+	merged := provenance.Metadata{Version: reports[0].Version, ConfigSHA256: reports[0].ConfigSHA256, Identities: []provenance.Identity{}}
+	var runIDs []string
+	calls := make(map[string]int64)
+	identities := make(map[provenance.Identity]bool)
+	for _, m := range reports {
+		if merged.StartedAt.IsZero() || m.StartedAt.Before(merged.StartedAt) {
+			merged.StartedAt = m.StartedAt
+		}
+		if m.FinishedAt.After(merged.FinishedAt) {
+			merged.FinishedAt = m.FinishedAt
+		}
+		if _, ok := calls[m.RunID]; !ok && m.RunID != "" {
+			runIDs = append(runIDs, m.RunID)
+		}
+		calls[m.RunID] = max(calls[m.RunID], m.APICalls)
+		for _, identity := range m.Identities {
+			if !identities[identity] {
+				identities[identity] = true
+				merged.Identities = append(merged.Identities, identity)
+			}
+		}
+	}
+	for _, n := range calls {
+		merged.APICalls += n
+	}
+	merged.RunID = strings.Join(runIDs, ",")
+	return merged
+}
+
+// monitorLabel names the monitor of a report, with its account when there is one
+func monitorLabel(report monitorReport) string {
+	if report.Account != "" {
+		return fmt.Sprintf("%s (%s)", report.Monitor, report.Account)
+	}
+	return report.Monitor
+}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/anupsv/git-monitoring/pkg/checkpoint"
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/suppression"
+	"github.com/anupsv/git-monitoring/pkg/tools/adminenforcement"
+	"github.com/anupsv/git-monitoring/pkg/tools/codeowners"
+	"github.com/anupsv/git-monitoring/pkg/tools/codescanning"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	"github.com/anupsv/git-monitoring/pkg/tools/dependabot"
+	"github.com/anupsv/git-monitoring/pkg/tools/dormantaccess"
+	"github.com/anupsv/git-monitoring/pkg/tools/dormantrepos"
+	"github.com/anupsv/git-monitoring/pkg/tools/environments"
+	"github.com/anupsv/git-monitoring/pkg/tools/forkruns"
+	"github.com/anupsv/git-monitoring/pkg/tools/ghas"
+	"github.com/anupsv/git-monitoring/pkg/tools/issuehygiene"
+	"github.com/anupsv/git-monitoring/pkg/tools/openprchecker"
+	"github.com/anupsv/git-monitoring/pkg/tools/prchecker"
+	"github.com/anupsv/git-monitoring/pkg/tools/pushprotection"
+	"github.com/anupsv/git-monitoring/pkg/tools/repocreation"
+	"github.com/anupsv/git-monitoring/pkg/tools/repovisibility"
+	"github.com/anupsv/git-monitoring/pkg/tools/rulesets"
+	"github.com/anupsv/git-monitoring/pkg/tools/tokenhealth"
+	"github.com/anupsv/git-monitoring/pkg/tools/workflowpermissions"
+)
+
+// monitorRun is the outcome of running a single monitor
+type monitorRun struct {
+	Results       interface{}        // Typed results of the monitor, used for JSON output
+	Count         int                // Number of results
+	Failed        bool               // Whether the monitor encountered processing errors
+	Err           error              // Why the monitor failed, nil when it did not
+	Findings      []findings.Finding // Results as findings, for state tracking
+	WriteMarkdown func(w io.Writer)  // Writes the results as markdown
+
+	// Findings left out of the results by the suppression files of their repositories
+	Suppressed []suppression.Suppression
+
+	// Targets checked, and those not checked because the scan stopped, e.g. at the deadline
+	Coverage common.Coverage
+
+	// Results before in-repo suppressions, including those of a resumed run, to save as progress
+	Unsuppressed interface{}
+}
+
+// monitorDefinition describes how a monitor is configured and run
+type monitorDefinition struct {
+	Key     string // Configuration key, also used in state and notifications
+	Name    string // Human readable name
+	Enabled func(cfg *config.Config) bool
+	Output  func(cfg *config.Config) config.OutputConfig
+	Targets func(cfg *config.Config) (organizations, repositories []string)
+	// Run runs the monitor, continuing the progress of an interrupted run when resume is not nil
+	// The context must have a scope of its own (common.WithScope), in which the monitor's coverage is kept
+	Run func(ctx context.Context, cfg *config.Config, useMarkdown bool, resume *checkpoint.Monitor) monitorRun
+	// WriteResults writes the results of a JSON output of the monitor as markdown, e.g. to merge reports
+	WriteResults func(w io.Writer, results interface{}) error
+}
+
+// newMonitorDefinition wires a monitor's typed functions into a monitorDefinition
+func newMonitorDefinition[T any](
+	key, name string,
+	enabled func(cfg *config.Config) bool,
+	output func(cfg *config.Config) config.OutputConfig,
+	targets func(cfg *config.Config) (organizations, repositories []string),
+	run func(ctx context.Context, cfg *config.Config, useMarkdown bool) ([]T, error),
+	toFindings func([]T) []findings.Finding,
+	writeMarkdown func(io.Writer, []T),
+) monitorDefinition {
+	return monitorDefinition{
+		Key:     key,
+		Name:    name,
+		Enabled: enabled,
+		Output:  output,
+		Targets: targets,
+		Run: func(ctx context.Context, cfg *config.Config, useMarkdown bool, resume *checkpoint.Monitor) monitorRun {
+			if resume != nil {
+				common.ResumeCoverage(ctx, resume.Coverage)
+			}
+			results, err := run(ctx, cfg, useMarkdown)
+			failed := err != nil
+			targets := common.TakeCoverage(ctx)
+
+			// Add the results of the targets checked by the resumed run
+			if resume != nil {
+				var previous []T
+				if decodeErr := json.Unmarshal(resume.Results, &previous); decodeErr != nil {
+					log.Printf("Error decoding the results of the resumed run of %s: %v", key, decodeErr)
+					err = errors.Join(err, fmt.Errorf("decoding the results of the resumed run: %w", decodeErr))
+				}
+				if resume.Failed {
+					err = errors.Join(err, errors.New("the resumed run failed, see its logs"))
+				}
+				results = append(previous, results...)
+				failed = err != nil
+			}
+			unsuppressed := results
+
+			list := toFindings(results)
+			var suppressed []suppression.Suppression
+			if cfg.Suppressions.InRepo {
+				results, list, suppressed = withoutSuppressed(ctx, cfg, results, toFindings)
+			}
+
+			// Label findings with the account they were reported for and the compliance controls they relate to
+			controls := cfg.Compliance.Controls[key]
+			for i := range list {
+				list[i].Account = cfg.Account
+				list[i].Controls = controls
+			}
+			for i := range suppressed {
+				suppressed[i].Finding.Account = cfg.Account
+				suppressed[i].Finding.Controls = controls
+			}
+
+			return monitorRun{
+				Results:      results,
+				Count:        len(results),
+				Failed:       failed,
+				Err:          err,
+				Findings:     list,
+				Suppressed:   suppressed,
+				Coverage:     targets,
+				Unsuppressed: unsuppressed,
+				WriteMarkdown: func(w io.Writer) {
+					if cfg.Account == "" && len(controls) == 0 {
+						writeMarkdown(w, results)
+						return
+					}
+					output := render(func(w io.Writer) { writeMarkdown(w, results) })
+					io.WriteString(w, annotateHeading(output, cfg.Account, controls))
+				},
+			}
+		},
+		WriteResults: func(w io.Writer, results interface{}) error {
+			data, err := json.Marshal(results)
+			if err != nil {
+				return err
+			}
+			// Errors of results are written as empty objects, which cannot be decoded back,
+			// so the results are written without them
+			var typed []T
+			var typeErr *json.UnmarshalTypeError
+			if err := json.Unmarshal(data, &typed); err != nil && !errors.As(err, &typeErr) {
+				return err
+			}
+			if len(typed) > 0 {
+				writeMarkdown(w, typed)
+			}
+			return nil
+		},
+	}
+}
+
+// annotateHeading appends the account to the first heading of a monitor's markdown output
+// and lists the compliance controls of the monitor below it
+func annotateHeading(output, account string, controls []string) string {
+	lines := strings.SplitAfter(output, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## ") {
+			heading := strings.TrimSuffix(line, "\n")
+			if account != "" {
+				heading += " (" + account + ")"
+			}
+			if len(controls) > 0 {
+				heading += "\nCompliance controls: " + strings.Join(controls, ", ")
+			}
+			lines[i] = heading + "\n"
+			break
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+// withoutSuppressed leaves out the findings suppressed by the suppression files of their repositories
+// Results with several findings, such as the unapproved pull requests of a repository,
+// are only left out when all of their findings are suppressed
+func withoutSuppressed[T any](ctx context.Context, cfg *config.Config, results []T, toFindings func([]T) []findings.Finding) ([]T, []findings.Finding, []suppression.Suppression) {
+	loader := suppression.NewLoader(common.NewGitHubClient(ctx, cfg.GitHub.Token), cfg.Suppressions.Path)
+
+	var kept []T
+	list := make([]findings.Finding, 0)
+	var suppressed []suppression.Suppression
+	for _, result := range results {
+		resultFindings := toFindings([]T{result})
+
+		var reported []findings.Finding
+		for _, f := range resultFindings {
+			if rule := loader.Match(ctx, f); rule != nil {
+				suppressed = append(suppressed, rule.Suppression(f, cfg.Suppressions.Path))
+			} else {
+				reported = append(reported, f)
+			}
+		}
+
+		if len(reported) > 0 || len(resultFindings) == 0 {
+			kept = append(kept, result)
+			list = append(list, reported...)
+		}
+	}
+
+	return kept, list, suppressed
+}
+
+// monitors lists all monitors in the order they run and are reported
+var monitors = []monitorDefinition{
+	newMonitorDefinition("pr_checker", "PR Checker",
+		func(cfg *config.Config) bool { return cfg.Monitors.PRChecker.Enabled },
+		func(cfg *config.Config) config.OutputConfig { return cfg.Monitors.PRChecker.Output },
+		func(cfg *config.Config) ([]string, []string) {
+			if cfg.Monitors.PRChecker.RepoVisibility == "specific" {
+				return nil, cfg.Monitors.PRChecker.SpecificRepositories
+			}
+			if cfg.Monitors.PRChecker.Organization == "" {
+				return nil, nil
+			}
+			return []string{cfg.Monitors.PRChecker.Organization}, nil
+		},
+		runPRChecker, prchecker.Findings, func(w io.Writer, results []prchecker.Result) { prchecker.WriteResultsMarkdown(w, results) }),
+	newMonitorDefinition("repo_visibility", "Repository Visibility",
+		func(cfg *config.Config) bool { return cfg.Monitors.RepoVisibility.Enabled },
+		func(cfg *config.Config) config.OutputConfig { return cfg.Monitors.RepoVisibility.Output },
+		func(cfg *config.Config) ([]string, []string) { return cfg.Monitors.RepoVisibility.Organizations, nil },
+		runRepoVisibilityChecker, repovisibility.Findings, repovisibility.WriteResultsMarkdown),
+	newMonitorDefinition("rulesets", "Rulesets Drift",
+		func(cfg *config.Config) bool { return cfg.Monitors.Rulesets.Enabled },
+		func(cfg *config.Config) config.OutputConfig { return cfg.Monitors.Rulesets.Output },
+		func(cfg *config.Config) ([]string, []string) {
+			return cfg.Monitors.Rulesets.Organizations, cfg.Monitors.Rulesets.Repositories
+		},
+		runRulesetsChecker, rulesets.Findings, rulesets.WriteResultsMarkdown),
+	newMonitorDefinition("code_scanning_dismissals", "Code Scanning Dismissals",
+		func(cfg *config.Config) bool { return cfg.Monitors.CodeScanning.Enabled },
+		func(cfg *config.Config) config.OutputConfig { return cfg.Monitors.CodeScanning.Output },
+		func(cfg *config.Config) ([]string, []string) {
+			return cfg.Monitors.CodeScanning.Organizations, cfg.Monitors.CodeScanning.Repositories
+		},
+		runCodeScanningChecker, codescanning.Findings, codescanning.WriteResultsMarkdown),
+	newMonitorDefinition("dependabot_dismissals", "Dependabot Dismissals",
+		func(cfg *config.Config) bool { return cfg.Monitors.Dependabot.Enabled },
+		func(cfg *config.Config) config.OutputConfig { return cfg.Monitors.Dependabot.Output },
+		func(cfg *config.Config) ([]string, []string) {
+			return cfg.Monitors.Dependabot.Organizations, cfg.Monitors.Dependabot.Repositories
+		},
+		runDependabotChecker, dependabot.Findings, dependabot.WriteResultsMarkdown),
+	newMonitorDefinition("push_protection_bypasses", "Push Protection Bypass",
+		func(cfg *config.Config) bool { return cfg.Monitors.PushProtection.Enabled },
+		func(cfg *config.Config) config.OutputConfig { return cfg.Monitors.PushProtection.Output },
+		func(cfg *config.Config) ([]string, []string) {
+			return cfg.Monitors.PushProtection.Organizations, cfg.Monitors.PushProtection.Repositories
+		},
+		runPushProtectionChecker, pushprotection.Findings, pushprotection.WriteResultsMarkdown),
+	newMonitorDefinition("dormant_accounts", "Dormant Privileged Accounts",
+		func(cfg *config.Config) bool { return cfg.Monitors.DormantAccess.Enabled },
+		func(cfg *config.Config) config.OutputConfig { return cfg.Monitors.DormantAccess.Output },
+		func(cfg *config.Config) ([]string, []string) {
+			return cfg.Monitors.DormantAccess.Organizations, cfg.Monitors.DormantAccess.Repositories
+		},
+		runDormantAccessChecker, dormantaccess.Findings, dormantaccess.WriteResultsMarkdown),
+	newMonitorDefinition("dormant_repositories", "Dormant Repositories",
+		func(cfg *config.Config) bool { return cfg.Monitors.DormantRepos.Enabled },
+		func(cfg *config.Config) config.OutputConfig { return cfg.Monitors.DormantRepos.Output },
+		func(cfg *config.Config) ([]string, []string) {
+			return cfg.Monitors.DormantRepos.Organizations, cfg.Monitors.DormantRepos.Repositories
+		},
+		runDormantReposChecker, dormantrepos.Findings, dormantrepos.WriteResultsMarkdown),
+	newMonitorDefinition("workflow_permissions", "Workflow Token Permissions",
+		func(cfg *config.Config) bool { return cfg.Monitors.WorkflowPermissions.Enabled },
+		func(cfg *config.Config) config.OutputConfig { return cfg.Monitors.WorkflowPermissions.Output },
+		func(cfg *config.Config) ([]string, []string) {
+			return cfg.Monitors.WorkflowPermissions.Organizations, cfg.Monitors.WorkflowPermissions.Repositories
+		},
+		runWorkflowPermissionsChecker, workflowpermissions.Findings, workflowpermissions.WriteResultsMarkdown),
+	newMonitorDefinition("environment_protection", "Deployment Environment Protection",
+		func(cfg *config.Config) bool { return cfg.Monitors.Environments.Enabled },
+		func(cfg *config.Config) config.OutputConfig { return cfg.Monitors.Environments.Output },
+		func(cfg *config.Config) ([]string, []string) {
+			return cfg.Monitors.Environments.Organizations, cfg.Monitors.Environments.Repositories
+		},
+		runEnvironmentsChecker, environments.Findings, environments.WriteResultsMarkdown),
+	newMonitorDefinition("fork_workflow_approvals", "Fork Workflow Approvals",
+		func(cfg *config.Config) bool { return cfg.Monitors.ForkRuns.Enabled },
+		func(cfg *config.Config) config.OutputConfig { return cfg.Monitors.ForkRuns.Output },
+		func(cfg *config.Config) ([]string, []string) {
+			return cfg.Monitors.ForkRuns.Organizations, cfg.Monitors.ForkRuns.Repositories
+		},
+		runForkRunsChecker, forkruns.Findings, forkruns.WriteResultsMarkdown),
+	newMonitorDefinition("repo_creation", "Repository Creation",
+		func(cfg *config.Config) bool { return cfg.Monitors.RepoCreation.Enabled },
+		func(cfg *config.Config) config.OutputConfig { return cfg.Monitors.RepoCreation.Output },
+		func(cfg *config.Config) ([]string, []string) { return cfg.Monitors.RepoCreation.Organizations, nil },
+		runRepoCreationChecker, repocreation.Findings, repocreation.WriteResultsMarkdown),
+	newMonitorDefinition("issue_hygiene", "Issue Hygiene",
+		func(cfg *config.Config) bool { return cfg.Monitors.IssueHygiene.Enabled },
+		func(cfg *config.Config) config.OutputConfig { return cfg.Monitors.IssueHygiene.Output },
+		func(cfg *config.Config) ([]string, []string) {
+			return cfg.Monitors.IssueHygiene.Organizations, cfg.Monitors.IssueHygiene.Repositories
+		},
+		runIssueHygieneChecker, issuehygiene.Findings, issuehygiene.WriteResultsMarkdown),
+	newMonitorDefinition("admin_enforcement", "Admin Enforcement",
+		func(cfg *config.Config) bool { return cfg.Monitors.AdminEnforcement.Enabled },
+		func(cfg *config.Config) config.OutputConfig { return cfg.Monitors.AdminEnforcement.Output },
+		func(cfg *config.Config) ([]string, []string) { return cfg.Monitors.AdminEnforcement.Organizations, nil },
+		runAdminEnforcementChecker, adminenforcement.Findings, adminenforcement.WriteResultsMarkdown),
+	newMonitorDefinition("codeowners_coverage", "CODEOWNERS Coverage",
+		func(cfg *config.Config) bool { return cfg.Monitors.Codeowners.Enabled },
+		func(cfg *config.Config) config.OutputConfig { return cfg.Monitors.Codeowners.Output },
+		func(cfg *config.Config) ([]string, []string) {
+			return cfg.Monitors.Codeowners.Organizations, cfg.Monitors.Codeowners.Repositories
+		},
+		runCodeownersChecker, codeowners.Findings, codeowners.WriteResultsMarkdown),
+	newMonitorDefinition("ghas_utilization", "GitHub Advanced Security Utilization",
+		func(cfg *config.Config) bool { return cfg.Monitors.GHAS.Enabled },
+		func(cfg *config.Config) config.OutputConfig { return cfg.Monitors.GHAS.Output },
+		func(cfg *config.Config) ([]string, []string) { return cfg.Monitors.GHAS.Organizations, nil },
+		runGHASChecker, ghas.Findings, ghas.WriteResultsMarkdown),
+	newMonitorDefinition("token_health", "GitHub Token Health",
+		func(cfg *config.Config) bool { return cfg.Monitors.TokenHealth.Enabled },
+		func(cfg *config.Config) config.OutputConfig { return cfg.Monitors.TokenHealth.Output },
+		func(cfg *config.Config) ([]string, []string) { return cfg.Monitors.TokenHealth.Organizations, nil },
+		runTokenHealthChecker, tokenhealth.Findings, tokenhealth.WriteResultsMarkdown),
+	newMonitorDefinition("open_pr_checker", "Open PRs Without Reviews",
+		func(cfg *config.Config) bool { return cfg.Monitors.OpenPRChecker.Enabled },
+		func(cfg *config.Config) config.OutputConfig { return cfg.Monitors.OpenPRChecker.Output },
+		func(cfg *config.Config) ([]string, []string) {
+			return cfg.Monitors.OpenPRChecker.Organizations, cfg.Monitors.OpenPRChecker.Repositories
+		},
+		runOpenPRChecker, openprchecker.Findings, openprchecker.WriteResultsMarkdown),
+}
+
+// enabledMonitorKeys returns the keys of the monitors enabled in the configuration of any account
+func enabledMonitorKeys(cfg *config.Config) []string {
+	var keys []string
+	for _, m := range monitors {
+		if m.EnabledInAnyAccount(cfg) {
+			keys = append(keys, m.Key)
+		}
+	}
+	return keys
+}
+
+// EnabledInAnyAccount reports whether the monitor is enabled for at least one account
+func (m monitorDefinition) EnabledInAnyAccount(cfg *config.Config) bool {
+	for _, accountCfg := range cfg.AccountConfigs() {
+		if m.Enabled(accountCfg) {
+			return true
+		}
+	}
+	return false
+}
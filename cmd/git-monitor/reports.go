@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/archive"
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/redact"
+)
+
+// reportsUsage describes the reports subcommand
+const reportsUsage = `Usage:
+  git-monitor reports list [--config config.toml] [--location dir] [--format text|json]
+  git-monitor reports show [--config config.toml] [--location dir] [--format markdown|json] <id|latest>`
+
+// runReports implements the reports subcommand, which browses the reports archived by earlier runs
+// Returns the process exit code
+func runReports(args []string) int {
+	if len(args) == 0 || (args[0] != "list" && args[0] != "show") {
+		fmt.Fprintln(os.Stderr, reportsUsage)
+		return 2
+	}
+	command := args[0]
+
+	fs := flag.NewFlagSet("reports "+command, flag.ContinueOnError)
+	configPath := fs.String("config", "config.toml", "Path to configuration file")
+	location := fs.String("location", "", "Directory or s3://bucket/prefix of the archive (default: archive location from the configuration)")
+	defaultFormat := "text"
+	if command == "show" {
+		defaultFormat = "markdown"
+	}
+	format := fs.String("format", defaultFormat, "Output format: text or json for list, markdown or json for show")
+
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	path := *location
+	var cfg *config.Config
+	if path == "" {
+		var err error
+		if cfg, err = config.LoadConfig(*configPath); err != nil {
+			log.Printf("Error loading configuration: %v", err)
+			return 1
+		}
+		redact.Register(cfg.Secrets()...)
+		path = cfg.Archive.Location
+	}
+	if err := enableEncryption(cfg); err != nil {
+		log.Printf("Error loading encryption key: %v", err)
+		return 1
+	}
+
+	reports, err := archive.Open(path)
+	if err != nil {
+		log.Printf("Error opening report archive: %v", err)
+		return 1
+	}
+
+	if command == "list" {
+		return listReports(os.Stdout, reports, *format)
+	}
+
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, reportsUsage)
+		return 2
+	}
+	return showReport(os.Stdout, reports, fs.Arg(0), *format)
+}
+
+// listReports prints the archived reports, latest first
+func listReports(out io.Writer, reports *archive.Archive, format string) int {
+	if format != "text" && format != "json" {
+		log.Printf("Invalid --format value: %s. Must be one of: text, json", format)
+		return 2
+	}
+
+	list, err := reports.List()
+	if err != nil {
+		log.Printf("Error listing reports: %v", err)
+		return 1
+	}
+
+	if format == "json" {
+		// Findings are left out of the listing, show prints them
+		type summary struct {
+			ID         string    `json:"id"`
+			Trigger    string    `json:"trigger"`
+			StartedAt  time.Time `json:"started_at"`
+			FinishedAt time.Time `json:"finished_at"`
+			Findings   int       `json:"findings"`
+			Failed     []string  `json:"failed,omitempty"`
+		}
+		summaries := make([]summary, 0, len(list))
+		for _, r := range list {
+			summaries = append(summaries, summary{r.ID, r.Trigger, r.StartedAt, r.FinishedAt, len(r.Findings), r.Failed})
+		}
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(summaries); err != nil {
+			log.Printf("Error writing reports: %v", err)
+			return 1
+		}
+		return 0
+	}
+
+	if len(list) == 0 {
+		fmt.Fprintln(out, "No reports archived yet. Enable [archive] in the configuration to archive the report of each run")
+		return 0
+	}
+
+	fmt.Fprintln(out, "ID                        Trigger  Finished          Findings  Failed monitors")
+	fmt.Fprintln(out, "------------------------------------------------------------------------------")
+	for _, r := range list {
+		failed := "-"
+		if len(r.Failed) > 0 {
+			failed = strings.Join(r.Failed, ", ")
+		}
+		fmt.Fprintf(out, "%-25s %-8s %-17s %-9d %s\n", r.ID, r.Trigger, r.FinishedAt.Local().Format("2006-01-02 15:04"), len(r.Findings), failed)
+	}
+	return 0
+}
+
+// showReport prints an archived report, as markdown or as JSON with its findings
+func showReport(out io.Writer, reports *archive.Archive, id, format string) int {
+	if format != "markdown" && format != "json" {
+		log.Printf("Invalid --format value: %s. Must be one of: markdown, json", format)
+		return 2
+	}
+
+	report, err := reports.Load(id)
+	if err != nil {
+		log.Printf("Error loading report: %v", err)
+		return 1
+	}
+
+	if format == "markdown" {
+		fmt.Fprint(out, report.Markdown)
+		return 0
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Printf("Error writing report: %v", err)
+		return 1
+	}
+	return 0
+}
+
+// archiveReport archives the report of a run and prunes the reports past the retention
+// Returns false when the report could not be archived
+func archiveReport(cfg *config.Config, report archive.Report) bool {
+	report.Markdown = redact.Secrets(report.Markdown)
+	reports, err := archive.Open(cfg.Archive.Location)
+	if err == nil {
+		err = reports.Save(report)
+	}
+	if err != nil {
+		log.Printf("Error archiving report: %v", err)
+		return false
+	}
+	log.Printf("Report archived as %s", report.ID)
+
+	retention := archive.Retention{MaxAge: cfg.Archive.MaxAge.Duration, MaxReports: cfg.Archive.MaxReports}
+	pruned, err := reports.Prune(retention, time.Now())
+	if len(pruned) > 0 {
+		log.Printf("Pruned %d archived reports past the retention", len(pruned))
+	}
+	if err != nil {
+		log.Printf("Error pruning archived reports: %v", err)
+	}
+	return true
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"runtime/debug"
+
+	"github.com/anupsv/git-monitoring/pkg/redact"
+)
+
+// maskLogs masks secrets in everything logged by this process, including by the HTTP servers of serve
+func maskLogs() {
+	log.SetOutput(redact.NewWriter(os.Stderr))
+}
+
+// exitOnPanic reports a panic with secrets masked in its value and stack, and exits
+// The Go runtime prints panics as they are, with the arguments of the functions on the stack
+// Deferred at the top of main and of goroutines, as a panic is only recovered in its own goroutine
+func exitOnPanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	fmt.Fprintln(os.Stderr, redact.Secrets(fmt.Sprintf("panic: %v\n\n%s", r, debug.Stack())))
+	os.Exit(2)
+}
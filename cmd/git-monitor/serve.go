@@ -0,0 +1,465 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/api/gitmonitorv1"
+	"github.com/anupsv/git-monitoring/pkg/archive"
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/coverage"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/leader"
+	"github.com/anupsv/git-monitoring/pkg/metrics"
+	"github.com/anupsv/git-monitoring/pkg/notify"
+	"github.com/anupsv/git-monitoring/pkg/quota"
+	"github.com/anupsv/git-monitoring/pkg/redact"
+	"github.com/anupsv/git-monitoring/pkg/server"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	"github.com/anupsv/git-monitoring/pkg/usage"
+)
+
+// runServe implements the serve subcommand, which runs the API for on-demand scans
+// Returns the process exit code
+func runServe(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	configPath := fs.String("config", "config.toml", "Path to configuration file")
+	listen := fs.String("listen", "", "Address to listen on (default: listen address from the configuration)")
+	grpcListen := fs.String("grpc-listen", "", "Address the gRPC API listens on (default: grpc_listen from the configuration)")
+	slackWebhook := fs.String("slack", "", "Slack webhook URL to retry undelivered notifications with, when notification retries are enabled")
+	pprofAddr := fs.String("pprof", "", "Serve runtime profiles on this address, e.g. :6060 (default: disabled)")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *pprofAddr != "" {
+		startProfiling(*pprofAddr)
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Printf("Error loading configuration: %v", err)
+		return 1
+	}
+	redact.Register(cfg.Secrets()...)
+	redact.Register(*slackWebhook)
+	common.SetUserAgent(userAgent(cfg))
+
+	if *listen != "" {
+		cfg.Server.Listen = *listen
+	}
+	if *grpcListen != "" {
+		cfg.Server.GRPCListen = *grpcListen
+	}
+
+	if err := cfg.Validate(); err != nil {
+		log.Printf("Invalid configuration: %v", err)
+		return 1
+	}
+
+	if err := cfg.ValidateServer(); err != nil {
+		log.Printf("Invalid configuration: %v", err)
+		return 1
+	}
+
+	if err := enableEncryption(cfg); err != nil {
+		log.Printf("Error loading encryption key: %v", err)
+		return 1
+	}
+
+	if cfg.Server.AuthToken == "" {
+		log.Printf("Warning: no API token configured, the API is served without authentication")
+	}
+
+	options := server.Options{
+		AuthToken: cfg.Server.AuthToken,
+		Monitors:  enabledMonitorKeys(cfg),
+		InScope:   cfg.InScope,
+		MaxRuns:   cfg.Server.MaxRuns,
+	}
+	if cfg.Server.Slack.Enabled {
+		options.Slack = &server.SlackOptions{
+			SigningSecret:   cfg.Server.Slack.SigningSecret,
+			BotToken:        cfg.Server.Slack.BotToken,
+			AllowedUsers:    cfg.Server.Slack.AllowedUsers,
+			AllowedChannels: cfg.Server.Slack.AllowedChannels,
+		}
+		// Triage buttons are recorded in the state, so they are only offered when state is enabled
+		if cfg.State.Enabled {
+			options.Slack.StatePath = cfg.State.Path
+		}
+	}
+
+	if cfg.Server.GitHubWebhook.Enabled {
+		options.GitHubWebhook = &server.GitHubWebhookOptions{
+			Secret:            cfg.Server.GitHubWebhook.Secret,
+			DeliveryRetention: cfg.Server.GitHubWebhook.DeliveryRetention.Duration,
+		}
+		// Delivery IDs survive restarts in the state, so replays are ignored after a restart too
+		if cfg.State.Enabled {
+			options.GitHubWebhook.StatePath = cfg.State.Path
+		}
+	}
+
+	// With the membership cache enabled, lookups expire after its ttl and are saved after each scan
+	// Otherwise they are only reused within a scan, as memberships change while the server runs
+	if cfg.Membership.Enabled {
+		if err := common.LoadMembershipCache(cfg.Membership.Path, cfg.Membership.TTL.Duration); err != nil {
+			log.Printf("Error loading membership cache, looking memberships up again: %v", err)
+		}
+	}
+
+	// Metrics of the last scan, served on /metrics
+	var lastScanMu sync.Mutex
+	var lastScan metrics.Run
+	options.Metrics = func(w io.Writer) error {
+		lastScanMu.Lock()
+		defer lastScanMu.Unlock()
+		return lastScan.Write(w)
+	}
+
+	// With the rate budget enabled, monitors are deferred once their share of the hourly API calls is used up
+	var budget *quota.Coordinator
+	if cfg.Server.RateBudget.Enabled {
+		budget = quota.NewCoordinator(int64(cfg.Server.RateBudget.HourlyCalls), cfg.Server.RateBudget.Shares, options.Monitors)
+		for _, key := range options.Monitors {
+			log.Printf("Rate budget of %s: %d API calls per hour", key, budget.Share(key))
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// With leader election, only the replica holding the lease scans and retries notifications
+	var elector *leader.Elector
+	if cfg.Server.LeaderElection.Enabled {
+		elector, err = newElector(cfg.Server.LeaderElection, cfg.State.Path)
+		if err != nil {
+			log.Printf("Error setting up leader election: %v", err)
+			return 1
+		}
+		options.IsLeader = elector.IsLeader
+		go elector.Run(ctx)
+	}
+
+	srv := server.New(func(ctx context.Context, req server.ScanRequest) (*server.ScanResult, error) {
+		defer exitOnPanic()
+		// Tag the GitHub API requests of the scan with the ID of its run in the API
+		ctx = common.WithRunID(ctx, server.RunID(ctx))
+		if !cfg.Membership.Enabled {
+			common.ResetMembershipCache()
+		}
+		startedAt := time.Now()
+		result, telemetry, err := runScan(ctx, cfg, req, budget)
+		// Errors and reports are returned by the API, mask the secrets they may quote
+		err = redact.Error(err)
+		if result != nil {
+			result.Report = redact.Secrets(result.Report)
+		}
+		if err == nil {
+			lastScanMu.Lock()
+			lastScan = telemetry
+			lastScanMu.Unlock()
+		}
+		if err == nil && cfg.Archive.Enabled {
+			archiveReport(cfg, archive.Report{
+				ID:         archive.NewID(startedAt),
+				Trigger:    archive.TriggerScan,
+				StartedAt:  startedAt,
+				FinishedAt: telemetry.FinishedAt,
+				Failed:     result.Failed,
+				Findings:   result.Findings,
+				Markdown:   result.Report,
+			})
+		}
+		if cfg.Membership.Enabled {
+			if err := common.SaveMembershipCache(cfg.Membership.Path); err != nil {
+				log.Printf("Error saving membership cache: %v", err)
+			}
+		}
+		return result, err
+	}, options)
+
+	registerWebhookHandlers(srv, options.Monitors)
+
+	go srv.Start(ctx)
+
+	// Retry notifications that scans could not deliver, so outages of the chat system do not drop findings
+	if cfg.Notifications.Retry.Enabled {
+		go func() {
+			ticker := time.NewTicker(cfg.Notifications.Retry.Interval.Duration)
+			defer ticker.Stop()
+			for {
+				if elector == nil || elector.IsLeader() {
+					retryNotifications(cfg, notificationSender(cfg, *slackWebhook))
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}()
+	}
+
+	if cfg.Server.GRPCListen != "" {
+		listener, err := net.Listen("tcp", cfg.Server.GRPCListen)
+		if err != nil {
+			log.Printf("Error listening on %s: %v", cfg.Server.GRPCListen, err)
+			return 1
+		}
+
+		grpcServer := srv.NewGRPCServer(configSummary(cfg))
+		go func() {
+			<-ctx.Done()
+			grpcServer.GracefulStop()
+		}()
+		go func() {
+			log.Printf("gRPC API listening on %s", cfg.Server.GRPCListen)
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Printf("Error running gRPC server: %v", err)
+			}
+		}()
+	}
+
+	httpServer := &http.Server{
+		Addr:              cfg.Server.Listen,
+		Handler:           srv.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Error shutting down server: %v", err)
+		}
+	}()
+
+	log.Printf("Listening on %s", cfg.Server.Listen)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Printf("Error running server: %v", err)
+		return 1
+	}
+
+	return 0
+}
+
+// newElector creates the elector of this replica, competing for the lease in the configured backend
+func newElector(election config.LeaderElectionConfig, statePath string) (*leader.Elector, error) {
+	identity := election.Identity
+	if identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("error reading the hostname, set identity instead: %v", err)
+		}
+		identity = hostname
+	}
+
+	var lock leader.Lock = leader.StateLock{Path: statePath}
+	if election.Backend == "kubernetes" {
+		kubernetesLock, err := leader.NewInClusterLock(election.Namespace, election.LeaseName)
+		if err != nil {
+			return nil, err
+		}
+		lock = kubernetesLock
+	}
+
+	log.Printf("Electing a leader as %s with the %s backend", identity, election.Backend)
+	return leader.NewElector(lock, identity, election.Duration.Duration), nil
+}
+
+// pullRequestEvent is the part of a pull_request webhook payload the receiver needs
+type pullRequestEvent struct {
+	Action      string `json:"action"`
+	PullRequest struct {
+		Number int  `json:"number"`
+		Merged bool `json:"merged"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// registerWebhookHandlers registers the handlers of the GitHub webhook deliveries the server acts on
+// A merged pull request queues a PR checker scan of its repository, when the PR checker is enabled
+func registerWebhookHandlers(srv *server.Server, enabled []string) {
+	if !slices.Contains(enabled, "pr_checker") {
+		return
+	}
+
+	srv.HandleWebhook("pull_request", func(ctx context.Context, event server.WebhookEvent) error {
+		var payload pullRequestEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("invalid pull_request payload: %v", err)
+		}
+		if payload.Action != "closed" || !payload.PullRequest.Merged {
+			return nil
+		}
+
+		run, err := srv.Submit(server.ScanRequest{
+			Monitors:     []string{"pr_checker"},
+			Repositories: []string{payload.Repository.FullName},
+		})
+		if err != nil {
+			return err
+		}
+		log.Printf("Merge of %s#%d queued scan %s", payload.Repository.FullName, payload.PullRequest.Number, run.ID)
+		return nil
+	})
+}
+
+// runScan runs an on-demand scan requested through the API
+// Scans only report their results to the caller: they do not update the state,
+// write monitor outputs or send notifications, so scoped scans cannot mark findings outside their scope as resolved
+// The telemetry of the scan is returned for the metrics endpoint
+// With a rate budget, monitors whose share of the hourly API calls is used up are deferred, and the others
+// stop once they use up what is left of their share
+func runScan(ctx context.Context, cfg *config.Config, req server.ScanRequest, budget *quota.Coordinator) (*server.ScanResult, metrics.Run, error) {
+	scanCfg := cfg
+	if len(req.Repositories) > 0 {
+		scanCfg = cfg.ScopeToRepositories(req.Repositories)
+	}
+
+	selected := make(map[string]bool)
+	for _, key := range req.Monitors {
+		selected[key] = true
+	}
+
+	result := &server.ScanResult{
+		Findings: []findings.Finding{},
+		Failed:   []string{},
+	}
+	var sections []notify.Section
+	var failures []notify.Failure
+	telemetry := metrics.Run{Usage: usage.NewReport()}
+
+	// Monitors in flight finish when the server shuts down, only the next ones are not started
+	monitorCtx := common.WithSharedRateLimiters(context.WithoutCancel(ctx))
+
+	for _, m := range monitors {
+		if len(selected) > 0 && !selected[m.Key] {
+			continue
+		}
+
+		failed, deferred := false, false
+		for _, accountCfg := range scanCfg.AccountConfigs() {
+			if !m.Enabled(accountCfg) {
+				continue
+			}
+
+			// Stop between monitors when the server shuts down
+			if err := ctx.Err(); err != nil {
+				return nil, metrics.Run{}, err
+			}
+
+			job := &monitorJob{monitor: m, cfg: accountCfg, done: make(chan struct{})}
+			if budget != nil {
+				var limit *common.RateLimit
+				if latest, ok := common.LatestRateLimit(accountCfg.GitHub.Token); ok {
+					limit = &latest
+				}
+				job.budget = budget.Allowance(accountCfg.Account, m.Key, limit, time.Now())
+				if job.budget == 0 {
+					log.Printf("Deferring %s monitor%s: its share of the hourly API calls is used up", m.Key, accountSuffix(accountCfg.Account))
+					deferred = true
+					continue
+				}
+			}
+
+			job.execute(monitorCtx, true)
+			if budget != nil {
+				budget.Record(accountCfg.Account, m.Key, job.usage.APICalls, time.Now())
+			}
+			run := job.run
+			telemetry.Usage.AddMonitor(job.usage)
+			telemetry.Coverage = append(telemetry.Coverage, coverage.New(m.Key, accountCfg.Account, run.Coverage))
+			if run.Failed {
+				failed = true
+				failures = append(failures, notify.Failure{Monitor: m.Name, Account: accountCfg.Account, Err: run.Err})
+			}
+			result.Findings = append(result.Findings, run.Findings...)
+
+			if run.Count > 0 {
+				sections = append(sections, notify.Section{Monitor: m.Key, Content: render(run.WriteMarkdown)})
+			}
+		}
+		if failed {
+			result.Failed = append(result.Failed, m.Key)
+		}
+		if deferred {
+			result.Deferred = append(result.Deferred, m.Key)
+		}
+	}
+
+	sendOpsAlert(cfg, failures)
+
+	result.Report = noIssuesMessage
+	if len(sections) > 0 {
+		result.Report = notify.Join(sections)
+	}
+	if len(result.Deferred) > 0 {
+		result.Report += fmt.Sprintf("\nDeferred until their share of the hourly API calls frees up: %s\n", strings.Join(result.Deferred, ", "))
+	}
+
+	for _, accountCfg := range scanCfg.AccountConfigs() {
+		telemetry.Usage.AddRateLimit(accountCfg.Account, accountCfg.GitHub.Token)
+	}
+	telemetry.FinishedAt = time.Now()
+
+	return result, telemetry, nil
+}
+
+// accountSuffix labels log messages with the account they are about, empty without accounts
+func accountSuffix(account string) string {
+	if account == "" {
+		return ""
+	}
+	return " of account " + account
+}
+
+// configSummary describes the monitor configuration for the gRPC GetConfig call
+// It only includes what is checked, never tokens
+func configSummary(cfg *config.Config) *gitmonitorv1.GetConfigResponse {
+	summary := &gitmonitorv1.GetConfigResponse{
+		Timezone:     cfg.Timezone,
+		StateEnabled: cfg.State.Enabled,
+	}
+
+	for _, m := range monitors {
+		// Combine the targets of every account the monitor is enabled for
+		var organizations, repositories []string
+		for _, accountCfg := range cfg.AccountConfigs() {
+			if len(cfg.Accounts) > 0 && !m.Enabled(accountCfg) {
+				continue
+			}
+			orgs, repos := m.Targets(accountCfg)
+			organizations = append(organizations, orgs...)
+			repositories = append(repositories, repos...)
+		}
+
+		summary.Monitors = append(summary.Monitors, &gitmonitorv1.MonitorConfig{
+			Key:           m.Key,
+			Enabled:       m.EnabledInAnyAccount(cfg),
+			Organizations: organizations,
+			Repositories:  repositories,
+		})
+	}
+
+	return summary
+}
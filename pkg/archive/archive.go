@@ -0,0 +1,227 @@
+package archive
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/encryption"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+)
+
+// Triggers of archived reports
+const (
+	TriggerRun  = "run"  // Monitoring run
+	TriggerScan = "scan" // On-demand scan of the server
+)
+
+// idTimeFormat is the time prefix of report IDs, which sorts them by start time
+const idTimeFormat = "20060102-150405"
+
+// idPattern matches report IDs, the start time followed by a random suffix
+var idPattern = regexp.MustCompile(`^[0-9]{8}-[0-9]{6}-[0-9a-f]{8}$`)
+
+// Report is the archived report of a run: its findings as JSON and its markdown report
+type Report struct {
+	ID         string             `json:"id"`
+	Trigger    string             `json:"trigger"` // TriggerRun or TriggerScan
+	StartedAt  time.Time          `json:"started_at"`
+	FinishedAt time.Time          `json:"finished_at"`
+	Failed     []string           `json:"failed,omitempty"` // Monitors that failed, whose findings are missing
+	Findings   []findings.Finding `json:"findings"`
+
+	// Markdown report of the run, archived next to the JSON and only loaded by Load
+	Markdown string `json:"-"`
+}
+
+// NewID returns the ID of a report of a run started at the given time
+// IDs sort by start time, and the random suffix keeps scans started within the same second apart
+func NewID(startedAt time.Time) string {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		log.Printf("Error generating report ID: %v", err)
+	}
+	return startedAt.UTC().Format(idTimeFormat) + "-" + hex.EncodeToString(suffix)
+}
+
+// Retention bounds how many reports the archive keeps
+type Retention struct {
+	MaxAge     time.Duration // Reports of runs started longer ago are pruned, 0 keeps them
+	MaxReports int           // Only this many of the latest reports are kept, 0 keeps all
+}
+
+// Archive keeps the reports of runs in a directory or S3 bucket
+type Archive struct {
+	storage storage
+}
+
+// Open returns the archive at location, a directory or "s3://bucket/prefix"
+func Open(location string) (*Archive, error) {
+	scheme, rest, ok := strings.Cut(location, "://")
+	if !ok {
+		return &Archive{storage: dirStorage{dir: location}}, nil
+	}
+	if scheme != "s3" {
+		return nil, fmt.Errorf("invalid archive location %s: must be a directory or s3://bucket/prefix", location)
+	}
+	storage, err := openS3(rest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive %s: %v", location, err)
+	}
+	return &Archive{storage: storage}, nil
+}
+
+// Save archives a report, its markdown first so a listed report always has both
+func (a *Archive) Save(report Report) error {
+	if !idPattern.MatchString(report.ID) {
+		return fmt.Errorf("invalid report ID %q", report.ID)
+	}
+	if report.Findings == nil {
+		report.Findings = []findings.Finding{}
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Both files are encrypted when encryption is enabled, the ID and so the start time are not
+	markdown, err := encryption.Seal([]byte(report.Markdown))
+	if err != nil {
+		return fmt.Errorf("error encrypting report %s: %v", report.ID, err)
+	}
+	if data, err = encryption.Seal(data); err != nil {
+		return fmt.Errorf("error encrypting report %s: %v", report.ID, err)
+	}
+	if err := a.storage.write(report.ID+".md", markdown); err != nil {
+		return fmt.Errorf("error archiving report %s: %v", report.ID, err)
+	}
+	if err := a.storage.write(report.ID+".json", data); err != nil {
+		return fmt.Errorf("error archiving report %s: %v", report.ID, err)
+	}
+	return nil
+}
+
+// IDs returns the IDs of the archived reports, latest first
+func (a *Archive) IDs() ([]string, error) {
+	names, err := a.storage.names()
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, name := range names {
+		if id, ok := strings.CutSuffix(name, ".json"); ok && idPattern.MatchString(id) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+	return ids, nil
+}
+
+// List returns the archived reports without their markdown, latest first
+func (a *Archive) List() ([]Report, error) {
+	ids, err := a.IDs()
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]Report, 0, len(ids))
+	for _, id := range ids {
+		report, err := a.load(id)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// Load returns an archived report with its markdown, "latest" being the latest report
+func (a *Archive) Load(id string) (Report, error) {
+	if id == "latest" {
+		ids, err := a.IDs()
+		if err != nil {
+			return Report{}, err
+		}
+		if len(ids) == 0 {
+			return Report{}, fmt.Errorf("no reports archived yet")
+		}
+		id = ids[0]
+	}
+	if !idPattern.MatchString(id) {
+		return Report{}, fmt.Errorf("invalid report ID %q", id)
+	}
+
+	report, err := a.load(id)
+	if err != nil {
+		return Report{}, err
+	}
+	markdown, err := a.storage.read(id + ".md")
+	if err == nil {
+		markdown, err = encryption.Unseal(markdown)
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return Report{}, fmt.Errorf("error reading report %s: %v", id, err)
+	}
+	report.Markdown = string(markdown)
+	return report, nil
+}
+
+// load reads the JSON of a report
+func (a *Archive) load(id string) (Report, error) {
+	data, err := a.storage.read(id + ".json")
+	if errors.Is(err, os.ErrNotExist) {
+		return Report{}, fmt.Errorf("report %s not found", id)
+	}
+	if err == nil {
+		data, err = encryption.Unseal(data)
+	}
+	if err != nil {
+		return Report{}, fmt.Errorf("error reading report %s: %v", id, err)
+	}
+
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return Report{}, fmt.Errorf("invalid report %s: %v", id, err)
+	}
+	return report, nil
+}
+
+// Prune removes the reports past the retention and returns their IDs
+// Ages are read from the IDs, so reports are pruned without reading them
+func (a *Archive) Prune(retention Retention, now time.Time) ([]string, error) {
+	ids, err := a.IDs()
+	if err != nil {
+		return nil, err
+	}
+
+	var pruned []string
+	for i, id := range ids {
+		startedAt, err := time.Parse(idTimeFormat, id[:len(idTimeFormat)])
+		if err != nil {
+			continue
+		}
+		expired := retention.MaxAge > 0 && now.Sub(startedAt) > retention.MaxAge
+		excess := retention.MaxReports > 0 && i >= retention.MaxReports
+		if !expired && !excess {
+			continue
+		}
+
+		// The JSON goes first, so a report left half removed is no longer listed
+		for _, name := range []string{id + ".json", id + ".md"} {
+			if err := a.storage.remove(name); err != nil {
+				return pruned, fmt.Errorf("error pruning report %s: %v", id, err)
+			}
+		}
+		pruned = append(pruned, id)
+	}
+	return pruned, nil
+}
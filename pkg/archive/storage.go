@@ -0,0 +1,168 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/s3"
+)
+
+// storageTimeout bounds each request to an S3 archive
+const storageTimeout = 30 * time.Second
+
+// storage holds the files of an archive by name
+type storage interface {
+	write(name string, data []byte) error
+	read(name string) ([]byte, error) // Returns an error wrapping os.ErrNotExist for missing files
+	names() ([]string, error)
+	remove(name string) error
+}
+
+// dirStorage keeps the files of an archive in a local directory
+type dirStorage struct {
+	dir string
+}
+
+// write replaces a file through a temporary file, so readers never see it partially written
+func (s dirStorage) write(name string, data []byte) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.dir, name+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(s.dir, name))
+}
+
+func (s dirStorage) read(name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(s.dir, name))
+}
+
+// names lists the files of the directory, none when it does not exist yet
+func (s dirStorage) names() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+func (s dirStorage) remove(name string) error {
+	if err := os.Remove(filepath.Join(s.dir, name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// s3Storage keeps the files of an archive as objects under a prefix of an S3 bucket
+type s3Storage struct {
+	client *s3.Client
+	prefix string // Ends with "/" unless empty
+}
+
+// openS3 returns the storage at "bucket/prefix", with the credentials of the standard AWS environment variables
+func openS3(location string) (*s3Storage, error) {
+	bucket, prefix, _ := strings.Cut(location, "/")
+	if bucket == "" {
+		return nil, fmt.Errorf("location must be s3://bucket/prefix")
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	client, err := s3.FromEnv(bucket, storageTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return &s3Storage{client: client, prefix: prefix}, nil
+}
+
+func (s *s3Storage) write(name string, data []byte) error {
+	contentType := "application/json"
+	if strings.HasSuffix(name, ".md") {
+		contentType = "text/markdown; charset=utf-8"
+	}
+	resp, body, err := s.do(http.MethodPut, name, data, http.Header{"Content-Type": {contentType}})
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("writing s3://%s/%s%s returned %s: %s", s.client.Bucket, s.prefix, name, resp.Status, body)
+	}
+	return nil
+}
+
+func (s *s3Storage) read(name string) ([]byte, error) {
+	resp, body, err := s.do(http.MethodGet, name, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("s3://%s/%s%s: %w", s.client.Bucket, s.prefix, name, os.ErrNotExist)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("reading s3://%s/%s%s returned %s: %s", s.client.Bucket, s.prefix, name, resp.Status, body)
+	}
+	return body, nil
+}
+
+// names lists the objects directly under the prefix
+func (s *s3Storage) names() ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), storageTimeout)
+	defer cancel()
+	keys, err := s.client.List(ctx, s.prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if name := strings.TrimPrefix(key, s.prefix); !strings.Contains(name, "/") {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func (s *s3Storage) remove(name string) error {
+	resp, body, err := s.do(http.MethodDelete, name, nil, nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("deleting s3://%s/%s%s returned %s: %s", s.client.Bucket, s.prefix, name, resp.Status, body)
+	}
+	return nil
+}
+
+// do sends a request for the object of a file
+func (s *s3Storage) do(method, name string, body []byte, header http.Header) (*http.Response, []byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), storageTimeout)
+	defer cancel()
+	return s.client.Do(ctx, method, s.prefix+name, nil, body, header)
+}
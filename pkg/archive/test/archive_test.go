@@ -0,0 +1,189 @@
+package test
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/archive"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+)
+
+// saveReports archives a report of a run started every day before now, the latest first
+func saveReports(t *testing.T, reports *archive.Archive, now time.Time, days int) []string {
+	t.Helper()
+
+	var ids []string
+	for day := 0; day < days; day++ {
+		startedAt := now.Add(-time.Duration(day) * 24 * time.Hour)
+		report := archive.Report{
+			ID:         archive.NewID(startedAt),
+			Trigger:    archive.TriggerRun,
+			StartedAt:  startedAt,
+			FinishedAt: startedAt.Add(time.Minute),
+			Findings:   []findings.Finding{{Monitor: "pr_checker", Repository: "owner/repo", Subject: fmt.Sprintf("PR #%d", day)}},
+			Markdown:   fmt.Sprintf("# Report of day %d\n", day),
+		}
+		if err := reports.Save(report); err != nil {
+			t.Fatalf("Failed to save report: %v", err)
+		}
+		ids = append(ids, report.ID)
+	}
+	return ids
+}
+
+// testArchive checks saving, listing, loading and pruning the reports of an archive
+func testArchive(t *testing.T, location string) {
+	reports, err := archive.Open(location)
+	if err != nil {
+		t.Fatalf("Failed to open archive: %v", err)
+	}
+
+	if list, err := reports.List(); err != nil || len(list) != 0 {
+		t.Fatalf("Expected an empty archive, got %+v, %v", list, err)
+	}
+	if _, err := reports.Load("latest"); err == nil {
+		t.Error("Expected an error loading the latest report of an empty archive")
+	}
+
+	now := time.Now()
+	ids := saveReports(t, reports, now, 5)
+
+	list, err := reports.List()
+	if err != nil {
+		t.Fatalf("Failed to list reports: %v", err)
+	}
+	if len(list) != 5 || list[0].ID != ids[0] || list[4].ID != ids[4] {
+		t.Fatalf("Expected the 5 reports latest first, got %+v", list)
+	}
+	if len(list[0].Findings) != 1 || list[0].Markdown != "" {
+		t.Errorf("Expected listed reports with their findings and without markdown, got %+v", list[0])
+	}
+
+	latest, err := reports.Load("latest")
+	if err != nil {
+		t.Fatalf("Failed to load the latest report: %v", err)
+	}
+	if latest.ID != ids[0] || latest.Markdown != "# Report of day 0\n" || latest.Findings[0].Subject != "PR #0" {
+		t.Errorf("Unexpected latest report %+v", latest)
+	}
+	if _, err := reports.Load("../state"); err == nil {
+		t.Error("Expected an invalid ID to be rejected")
+	}
+
+	// Reports past the max age are pruned, then those beyond the max count
+	pruned, err := reports.Prune(archive.Retention{MaxAge: 3*24*time.Hour + time.Hour}, now)
+	if err != nil || len(pruned) != 1 || pruned[0] != ids[4] {
+		t.Fatalf("Expected the report of 4 days ago to be pruned, got %v, %v", pruned, err)
+	}
+	pruned, err = reports.Prune(archive.Retention{MaxReports: 2}, now)
+	if err != nil || len(pruned) != 2 {
+		t.Fatalf("Expected 2 reports beyond the latest 2 to be pruned, got %v, %v", pruned, err)
+	}
+
+	list, err = reports.List()
+	if err != nil {
+		t.Fatalf("Failed to list reports: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != ids[0] || list[1].ID != ids[1] {
+		t.Errorf("Expected the latest 2 reports to be kept, got %+v", list)
+	}
+}
+
+func TestDirectoryArchive(t *testing.T) {
+	testArchive(t, filepath.Join(t.TempDir(), "reports"))
+}
+
+// fakeBucket serves the objects of a bucket, listing them a page of 3 keys at a time
+type fakeBucket struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeBucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+		http.Error(w, "unsigned request", http.StatusForbidden)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/monitoring/")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+		var keys []string
+		for k := range f.objects {
+			if strings.HasPrefix(k, r.URL.Query().Get("prefix")) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		start := 0
+		if token := r.URL.Query().Get("continuation-token"); token != "" {
+			fmt.Sscanf(token, "%d", &start)
+		}
+
+		type content struct {
+			Key string `xml:"Key"`
+		}
+		result := struct {
+			XMLName               xml.Name  `xml:"ListBucketResult"`
+			Contents              []content `xml:"Contents"`
+			IsTruncated           bool      `xml:"IsTruncated"`
+			NextContinuationToken string    `xml:"NextContinuationToken,omitempty"`
+		}{}
+		for _, k := range keys[start:min(start+3, len(keys))] {
+			result.Contents = append(result.Contents, content{Key: k})
+		}
+		if start+3 < len(keys) {
+			result.IsTruncated = true
+			result.NextContinuationToken = fmt.Sprint(start + 3)
+		}
+		xml.NewEncoder(w).Encode(result)
+	case r.Method == http.MethodGet:
+		data, ok := f.objects[key]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	case r.Method == http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[key] = body
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Archive(t *testing.T) {
+	bucket := &fakeBucket{objects: map[string][]byte{"git-monitor/other/file.json": []byte("{}")}}
+	server := httptest.NewServer(bucket)
+	defer server.Close()
+
+	t.Setenv("AWS_ENDPOINT_URL_S3", server.URL)
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	testArchive(t, "s3://monitoring/git-monitor/reports")
+
+	// Objects outside the prefix of the archive are left alone
+	if _, ok := bucket.objects["git-monitor/other/file.json"]; !ok {
+		t.Error("Expected objects outside the archive to be kept")
+	}
+}
+
+func TestOpenRejectsUnsupportedLocations(t *testing.T) {
+	if _, err := archive.Open("gs://bucket/reports"); err == nil {
+		t.Error("Expected an error opening an archive in an unsupported location")
+	}
+}
@@ -0,0 +1,124 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/encryption"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+// checkpointVersion is the version of the checkpoint format
+const checkpointVersion = 1
+
+// Checkpoint is the progress of a scan, saved so it can be resumed after it stopped early
+type Checkpoint struct {
+	Version      int                `json:"version"`
+	StartedAt    time.Time          `json:"started_at"`    // Start of the scan the progress belongs to
+	ConfigSHA256 string             `json:"config_sha256"` // Hash of the configuration the scan used
+	Monitors     map[string]Monitor `json:"monitors"`      // Progress of each monitor run, by state key
+}
+
+// Monitor is the progress of a monitor run
+type Monitor struct {
+	Failed   bool            `json:"failed"` // Whether checks failed, so the resumed run reports the failure
+	Coverage common.Coverage `json:"coverage"`
+	Results  json.RawMessage `json:"results"` // Results of the checked targets, before in-repo suppressions
+}
+
+// New starts the checkpoint of a scan
+func New(startedAt time.Time, configSHA256 string) *Checkpoint {
+	return &Checkpoint{
+		Version:      checkpointVersion,
+		StartedAt:    startedAt,
+		ConfigSHA256: configSHA256,
+		Monitors:     make(map[string]Monitor),
+	}
+}
+
+// Load reads a checkpoint, returning nil without an error when there is none
+func Load(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err == nil {
+		data, err = encryption.Unseal(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint %s: %w", path, err)
+	}
+
+	var c Checkpoint
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %w", path, err)
+	}
+	if c.Version != checkpointVersion {
+		return nil, fmt.Errorf("unsupported checkpoint version %d in %s", c.Version, path)
+	}
+	if c.Monitors == nil {
+		c.Monitors = make(map[string]Monitor)
+	}
+
+	return &c, nil
+}
+
+// Monitor returns the progress of a monitor run, and whether there is any
+func (c *Checkpoint) Monitor(key string) (Monitor, bool) {
+	m, ok := c.Monitors[key]
+	return m, ok
+}
+
+// Record saves the progress of a monitor run
+func (c *Checkpoint) Record(key string, failed bool, coverage common.Coverage, results interface{}) error {
+	data, err := json.Marshal(results)
+	if err != nil {
+		return fmt.Errorf("failed to encode results of %s: %w", key, err)
+	}
+
+	c.Monitors[key] = Monitor{Failed: failed, Coverage: coverage, Results: data}
+	return nil
+}
+
+// Save writes the checkpoint to the given path
+// The file is replaced atomically so a run interrupted while saving never leaves a truncated checkpoint behind
+func (c *Checkpoint) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+	if data, err = encryption.Seal(data); err != nil {
+		return fmt.Errorf("failed to encrypt checkpoint: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".checkpoint-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace checkpoint %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Remove deletes the checkpoint once the scan completed, so it is not resumed again
+func Remove(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove checkpoint %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/checkpoint"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+type result struct {
+	Repository string
+	Count      int
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	saved, err := checkpoint.Load(path)
+	if err != nil || saved != nil {
+		t.Fatalf("Expected no checkpoint before one is saved, got %+v, %v", saved, err)
+	}
+
+	started := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := checkpoint.New(started, "abc123")
+	coverage := common.Coverage{
+		Checked: []string{"owner/a"},
+		Skipped: []common.SkippedTarget{{Target: "owner/b", Reason: common.StopDeadline}},
+	}
+	if err := c.Record("acme/pr_checker", true, coverage, []result{{Repository: "owner/a", Count: 2}}); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if err := c.Save(path); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	loaded, err := checkpoint.Load(path)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if !loaded.StartedAt.Equal(started) || loaded.ConfigSHA256 != "abc123" {
+		t.Errorf("Unexpected checkpoint: %+v", loaded)
+	}
+
+	m, ok := loaded.Monitor("acme/pr_checker")
+	if !ok || !m.Failed || len(m.Coverage.Checked) != 1 || m.Coverage.Checked[0] != "owner/a" {
+		t.Fatalf("Unexpected monitor progress: %+v", m)
+	}
+	var results []result
+	if err := json.Unmarshal(m.Results, &results); err != nil || len(results) != 1 || results[0].Count != 2 {
+		t.Errorf("Unexpected results: %+v, %v", results, err)
+	}
+	if _, ok := loaded.Monitor("rulesets"); ok {
+		t.Errorf("Did not expect progress for a monitor that was not recorded")
+	}
+
+	if err := checkpoint.Remove(path); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected the checkpoint to be removed")
+	}
+	if err := checkpoint.Remove(path); err != nil {
+		t.Errorf("Did not expect an error removing a missing checkpoint but got: %v", err)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	invalid := filepath.Join(dir, "invalid.json")
+	os.WriteFile(invalid, []byte("{"), 0644)
+	if _, err := checkpoint.Load(invalid); err == nil {
+		t.Errorf("Expected an error for an invalid checkpoint")
+	}
+
+	unsupported := filepath.Join(dir, "unsupported.json")
+	os.WriteFile(unsupported, []byte(`{"version": 99}`), 0644)
+	if _, err := checkpoint.Load(unsupported); err == nil {
+		t.Errorf("Expected an error for an unsupported checkpoint version")
+	}
+}
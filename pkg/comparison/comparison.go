@@ -0,0 +1,139 @@
+package comparison
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/scoring"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+// Organization is the summary of the findings and coverage of an organization, compared with the others
+type Organization struct {
+	Name         string
+	Findings     int // Number of findings
+	Repositories int // Repositories with findings
+	Score        int // Sum of the weights of the findings
+	Checked      int // Targets checked, including those that failed
+	Errored      int // Checked targets whose check failed
+	Skipped      int // Targets not checked because the scan stopped early
+}
+
+// Coverage returns the percentage of the organization's targets that were checked
+func (o Organization) Coverage() int {
+	total := o.Checked + o.Skipped
+	if total == 0 {
+		return 100
+	}
+	return o.Checked * 100 / total
+}
+
+// Owner returns the organization or user owning a repository or target, e.g. "acme" for "acme/api",
+// "org:acme" and "team:acme/platform", and empty for targets of no organization
+func Owner(target string) string {
+	if org, ok := strings.CutPrefix(target, "org:"); ok {
+		return org
+	}
+	target = strings.TrimPrefix(target, "team:")
+	owner, _, ok := strings.Cut(target, "/")
+	if !ok || strings.Contains(owner, ":") {
+		return ""
+	}
+	return owner
+}
+
+// Compare summarizes the findings and coverage of each organization, the riskiest first
+// Organizations are matched case-insensitively, and named as they were first seen
+func Compare(list []findings.Finding, coverages []common.Coverage, weights map[string]int) []Organization {
+	byName := make(map[string]*Organization)
+	organization := func(target string) *Organization {
+		owner := Owner(target)
+		if owner == "" {
+			return nil
+		}
+		key := strings.ToLower(owner)
+		org, ok := byName[key]
+		if !ok {
+			org = &Organization{Name: owner}
+			byName[key] = org
+		}
+		return org
+	}
+
+	repositories := make(map[string]bool)
+	for _, f := range list {
+		org := organization(f.Repository)
+		if org == nil {
+			continue
+		}
+		org.Findings++
+		org.Score += scoring.Weight(f, weights)
+		if key := strings.ToLower(f.Repository); !repositories[key] {
+			repositories[key] = true
+			org.Repositories++
+		}
+	}
+
+	for _, c := range coverages {
+		for _, target := range c.Checked {
+			if org := organization(target); org != nil {
+				org.Checked++
+			}
+		}
+		for _, target := range c.Errored {
+			if org := organization(target); org != nil {
+				org.Errored++
+			}
+		}
+		for _, s := range c.Skipped {
+			if org := organization(s.Target); org != nil {
+				org.Skipped++
+			}
+		}
+	}
+
+	orgs := make([]Organization, 0, len(byName))
+	for _, org := range byName {
+		orgs = append(orgs, *org)
+	}
+	sort.Slice(orgs, func(i, j int) bool {
+		a, b := orgs[i], orgs[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Findings != b.Findings {
+			return a.Findings > b.Findings
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+	return orgs
+}
+
+// WriteMarkdown writes the organizations side by side in a code block format suitable for Slack,
+// when there are several to compare
+func WriteMarkdown(w io.Writer, orgs []Organization) {
+	if len(orgs) < 2 {
+		return // Nothing to compare
+	}
+
+	fmt.Fprintln(w, "## :office: Organization Comparison")
+	if orgs[0].Score > 0 {
+		fmt.Fprintf(w, "%d organizations compared, %s needs the most attention with a risk score of %d.\n\n", len(orgs), orgs[0].Name, orgs[0].Score)
+	} else {
+		fmt.Fprintf(w, "%d organizations compared, none with findings.\n\n", len(orgs))
+	}
+
+	// Start code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "Organization              Score  Findings  Repositories  Checked  Errors  Coverage")
+	fmt.Fprintln(w, "----------------------------------------------------------------------------------")
+	for _, org := range orgs {
+		fmt.Fprintf(w, "%-25s %-6d %-9d %-13d %-8d %-7d %d%%\n", org.Name, org.Score, org.Findings, org.Repositories, org.Checked, org.Errored, org.Coverage())
+	}
+	// End code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
@@ -0,0 +1,74 @@
+package test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/anupsv/git-monitoring/pkg/comparison"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+func TestOwner(t *testing.T) {
+	tests := map[string]string{
+		"acme/api":            "acme",
+		"org:acme":            "acme",
+		"team:acme/platform":  "acme",
+		"user-repositories":   "",
+		"enterprise:acme-ent": "",
+		"":                    "",
+	}
+	for target, expected := range tests {
+		if owner := comparison.Owner(target); owner != expected {
+			t.Errorf("Expected %q to be owned by %q, got %q", target, expected, owner)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	list := []findings.Finding{
+		{Monitor: "pr_checker", Repository: "acme/api", Subject: "PR #1"},
+		{Monitor: "pr_checker", Repository: "acme/api", Subject: "PR #2"},
+		{Monitor: "repo_visibility", Repository: "Globex/site", Subject: "globex/site"},
+		{Monitor: "pr_checker", Repository: "acme/web", Subject: "PR #3", Severity: findings.SeverityLow},
+	}
+	coverages := []common.Coverage{
+		{Checked: []string{"acme/api", "acme/web", "globex/site", "globex/docs"}, Errored: []string{"globex/docs"}},
+		{Checked: []string{"org:initech"}, Skipped: []common.SkippedTarget{{Target: "org:acme", Reason: common.StopBudget}}},
+	}
+	orgs := comparison.Compare(list, coverages, map[string]int{"repo_visibility": 8, "pr_checker": 3})
+
+	var names []string
+	for _, org := range orgs {
+		names = append(names, org.Name)
+	}
+	if strings.Join(names, ",") != "Globex,acme,initech" {
+		t.Fatalf("Expected organizations ordered by risk score, got %v", names)
+	}
+	globex, acme, initech := orgs[0], orgs[1], orgs[2]
+	if globex.Score != 8 || globex.Findings != 1 || globex.Checked != 2 || globex.Errored != 1 || globex.Coverage() != 100 {
+		t.Errorf("Unexpected summary of globex: %+v", globex)
+	}
+	if acme.Score != 6 || acme.Findings != 3 || acme.Repositories != 2 || acme.Coverage() != 66 {
+		t.Errorf("Expected low-severity findings to count without weight and a partial coverage for acme, got %+v", acme)
+	}
+	if initech.Findings != 0 || initech.Checked != 1 {
+		t.Errorf("Expected organizations without findings to be compared, got %+v", initech)
+	}
+
+	var buf bytes.Buffer
+	comparison.WriteMarkdown(&buf, orgs)
+	output := buf.String()
+	for _, expected := range []string{"Organization Comparison", "Globex needs the most attention with a risk score of 8", "66%"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in the comparison, got %s", expected, output)
+		}
+	}
+
+	buf.Reset()
+	comparison.WriteMarkdown(&buf, orgs[:1])
+	if buf.Len() != 0 {
+		t.Errorf("Expected no comparison of a single organization, got %s", buf.String())
+	}
+}
@@ -1,161 +1,2653 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
 	"log"
+	"net/url"
 	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/BurntSushi/toml"
 )
 
 // Config represents the application configuration
 type Config struct {
-	GitHub      GitHubConfig   `toml:"github"`
-	Monitors    MonitorsConfig `toml:"monitors"`
-	RepoFilters Filters        `toml:"repo_filters"`
+	// IANA timezone (e.g. "Europe/Berlin") used for report timestamps and daily windows.
+	// Empty reports in UTC and does not align windows to midnight
+	Timezone       string               `toml:"timezone"`
+	GitHub         GitHubConfig         `toml:"github"`
+	Monitors       MonitorsConfig       `toml:"monitors"`
+	RepoFilters    Filters              `toml:"repo_filters"`
+	Notifications  NotificationsConfig  `toml:"notifications"`
+	State          StateConfig          `toml:"state"`
+	Suppressions   SuppressionsConfig   `toml:"suppressions"`
+	RepoPolicy     RepoPolicyConfig     `toml:"repo_policy"`
+	Redaction      RedactionConfig      `toml:"redaction"`
+	Scoring        ScoringConfig        `toml:"scoring"`
+	Comparison     ComparisonConfig     `toml:"comparison"`
+	CoverageReport CoverageReportConfig `toml:"coverage_report"`
+	Compliance     ComplianceConfig     `toml:"compliance"`
+	Remediation    RemediationConfig    `toml:"remediation"`
+	Ownership      OwnershipConfig      `toml:"ownership"`
+	Escalation     EscalationConfig     `toml:"escalation"`
+	SLA            SLAConfig            `toml:"sla"`
+	Evidence       EvidenceConfig       `toml:"evidence"`
+	Archive        ArchiveConfig        `toml:"archive"`
+	Projects       ProjectsConfig       `toml:"projects"`
+	ServiceNow     ServiceNowConfig     `toml:"servicenow"`
+	Events         EventsConfig         `toml:"events"`
+	Encryption     EncryptionConfig     `toml:"encryption"`
+	Checkpoint     CheckpointConfig     `toml:"checkpoint"`
+	Membership     MembershipConfig     `toml:"membership_cache"`
+	Heartbeat      HeartbeatConfig      `toml:"heartbeat"`
+	Metrics        MetricsConfig        `toml:"metrics"`
+	Server         ServerConfig         `toml:"server"`
+
+	// GitHub accounts scanned in the same run, each with its own token and monitors
+	// When accounts are set, the top-level [github] token and [monitors] are not used
+	Accounts []AccountConfig `toml:"accounts"`
+
+	// Name of the account this configuration was derived from by AccountConfigs, empty otherwise
+	Account string `toml:"-"`
+
+	// Whether check windows end now rather than at local midnight, for the scans of ScopeToRepositories
+	WindowsEndNow bool `toml:"-"`
+}
+
+// AccountConfig contains configuration for one of several GitHub accounts scanned in a run
+type AccountConfig struct {
+	Name     string         `toml:"name"`      // Label of the account's results, e.g. the customer or business unit
+	Token    string         `toml:"token"`     // GitHub token of the account
+	TokenEnv string         `toml:"token_env"` // Environment variable holding the token, takes precedence over token
+	Monitors MonitorsConfig `toml:"monitors"`  // Monitors of the account, with the same defaults as [monitors]
+}
+
+// GitHubConfig contains GitHub API configuration
+type GitHubConfig struct {
+	Token string `toml:"token"`
+	// Product the monitor's API requests identify as in their User-Agent, followed by the ID of the run,
+	// e.g. "acme-git-monitor/1.0". "git-monitor/<version>" when empty
+	UserAgent string `toml:"user_agent"`
+}
+
+// MonitorsConfig contains configuration for all monitors
+type MonitorsConfig struct {
+	PRChecker      PRCheckerConfig      `toml:"pr_checker"`
+	RepoVisibility RepoVisibilityConfig `toml:"repo_visibility"`
+	Rulesets       RulesetsConfig       `toml:"rulesets"`
+	CodeScanning   CodeScanningConfig   `toml:"code_scanning_dismissals"`
+	Dependabot     DependabotConfig     `toml:"dependabot_dismissals"`
+	PushProtection PushProtectionConfig `toml:"push_protection_bypasses"`
+	DormantAccess  DormantAccessConfig  `toml:"dormant_accounts"`
+	DormantRepos   DormantReposConfig   `toml:"dormant_repositories"`
+
+	WorkflowPermissions WorkflowPermissionsConfig `toml:"workflow_permissions"`
+	Environments        EnvironmentsConfig        `toml:"environment_protection"`
+	ForkRuns            ForkRunsConfig            `toml:"fork_workflow_approvals"`
+	RepoCreation        RepoCreationConfig        `toml:"repo_creation"`
+	IssueHygiene        IssueHygieneConfig        `toml:"issue_hygiene"`
+	AdminEnforcement    AdminEnforcementConfig    `toml:"admin_enforcement"`
+	Codeowners          CodeownersConfig          `toml:"codeowners_coverage"`
+	GHAS                GHASConfig                `toml:"ghas_utilization"`
+	TokenHealth         TokenHealthConfig         `toml:"token_health"`
+	OpenPRChecker       OpenPRCheckerConfig       `toml:"open_pr_checker"`
+}
+
+// PRCheckerConfig contains configuration for the PR checker
+type PRCheckerConfig struct {
+	Enabled                bool                `toml:"enabled"`
+	RepoVisibility         string              `toml:"repo_visibility"`          // Options: "all", "public-only", "private-only", "specific"
+	Organization           string              `toml:"organization"`             // GitHub organization name (optional)
+	TeamSlug               string              `toml:"team_slug"`                // Only check repositories this team of the organization has access to (optional)
+	SpecificRepositories   []string            `toml:"specific_repositories"`    // Only used when RepoVisibility is "specific"
+	ExcludedRepositories   []string            `toml:"excluded_repositories"`    // Used with "all", "public-only", "private-only" to exclude specific repos
+	ExcludeForks           bool                `toml:"exclude_forks"`            // Leave out forks when listing the repositories of the organization or team
+	MinRepoAge             Duration            `toml:"min_repo_age_hours"`       // Leave out listed repositories created more recently, still being set up (optional)
+	SkipInactiveDays       int                 `toml:"skip_inactive_days"`       // Leave out listed repositories without pushes in this many days (optional)
+	Discovery              string              `toml:"discovery"`                // How merged PRs are found: "list" per repository (default) or "search" across the organization
+	ExcludedAuthors        []string            `toml:"excluded_authors"`         // Logins whose merged PRs are not checked, e.g. "dependabot[bot]" or "renovate" (optional)
+	ExcludeBots            bool                `toml:"exclude_bots"`             // Leave out merged PRs authored by bots, e.g. Dependabot and Renovate
+	BaseBranches           []string            `toml:"base_branches"`            // Globs of the base branches merged PRs are checked for, e.g. ["main", "release/*"], all when empty (optional)
+	RequiredApprovals      int                 `toml:"required_approvals"`       // Distinct reviewers who must approve merged PRs, PRs with fewer are flagged (optional)
+	MinReviewTime          Duration            `toml:"min_review_time"`          // Flag approvals submitted sooner after the PR was opened or last pushed to as rubber stamps (optional)
+	FlagCommitterApprovals bool                `toml:"flag_committer_approvals"` // Flag PRs approved only by reviewers who also contributed commits
+	FlagDismissedReviews   bool                `toml:"flag_dismissed_reviews"`   // Flag PRs merged after a review requesting changes was dismissed
+	DismissalWindow        Duration            `toml:"dismissal_window"`         // Only flag dismissals this long or less before the merge, any dismissal when 0 (optional)
+	FlagStaleApprovals     bool                `toml:"flag_stale_approvals"`     // Flag PRs whose approvals were all given before commits pushed since
+	FlagProtectionBypasses bool                `toml:"flag_protection_bypasses"` // Flag PRs merged with fewer approvals than their base branch's protection requires
+	FlagFailingChecks      bool                `toml:"flag_failing_checks"`      // Flag PRs merged while status checks required on their base branch were failing or missing
+	FlagOutsideApprovals   bool                `toml:"flag_outside_approvals"`   // Flag PRs approved only by reviewers outside the organization owning the repository
+	RequiredReviewerTeams  []string            `toml:"required_reviewer_teams"`  // Teams ("org/team") whose members' approvals are the only ones counted (optional)
+	RepoReviewerTeams      map[string][]string `toml:"repo_reviewer_teams"`      // Reviewer teams of specific repositories by "owner/repo", replacing required_reviewer_teams (optional)
+	RequireCodeOwners      bool                `toml:"require_code_owners"`      // Flag PRs whose files with code owners were approved by none of their owners
+	RequiredSections       []string            `toml:"required_sections"`        // Regexes of description template headings merged PRs must fill in (optional)
+	TitlePattern           string              `toml:"title_pattern"`            // Regex merged PR titles must match, reported as low-severity findings (optional)
+	TitlePreset            string              `toml:"title_preset"`             // Title convention merged PR titles must follow instead of title_pattern: "conventional_commits" (optional)
+	RequireTicket          bool                `toml:"require_ticket"`           // Flag merged PRs without an issue tracker key in the title or head branch
+	TicketKeys             []string            `toml:"ticket_keys"`              // Project keys ticket references must use, any uppercase key when empty
+	RepoTicketKeys         map[string][]string `toml:"repo_ticket_keys"`         // Project keys of specific repositories by "owner/repo", replacing ticket_keys (optional)
+	RepoPaths              map[string][]string `toml:"repo_paths"`               // Globs of the paths merged PRs must change to be checked, by "owner/repo" (optional)
+	StatusPosters          map[string][]string `toml:"status_posters"`           // Apps and users allowed to pass required status checks, by check name or "*" (optional)
+	AllowedMergeMethods    []string            `toml:"allowed_merge_methods"`    // Merge methods merged PRs may use: "merge", "squash" or "rebase", any when empty (optional)
+	MergeHours             MergeHoursConfig    `toml:"merge_hours"`              // Flag PRs merged outside business hours or on weekends (optional)
+	RepoOverrides          []PRCheckerOverride `toml:"repo_overrides"`           // Policies of specific repositories overriding the settings above (optional)
+	CacheVerdicts          bool                `toml:"cache_verdicts"`           // Reuse the verdicts of merged PRs checked by earlier runs, kept in the state (default true)
+	OutOfWindowThreshold   int                 `toml:"out_of_window_threshold"`  // Consecutive PRs merged before the time window after which listing a repository stops (default 20)
+	Pagination             PaginationConfig    `toml:"pagination"`               // Page size and page cap of listing the PRs of each repository (optional)
+	StatusChecksPagination PaginationConfig    `toml:"status_checks_pagination"` // Page size and page cap of listing the statuses and check runs of each merged PR (optional)
+	TimeWindow             Duration            `toml:"time_window_hours"`        // Time window, e.g. "24h", "7d" or a number of hours
+	DebugLogging           bool                `toml:"debug_logging"`            // Enable verbose logging for debugging
+	Output                 OutputConfig        `toml:"output"`                   // Dedicated output for this monitor (optional)
+}
+
+// PRCheckerOverride overrides the PR checker policy of one repository, settings left out keep the global ones
+type PRCheckerOverride struct {
+	Repository        string   `toml:"repository"`         // Repository the policy applies to, "owner/repo"
+	TimeWindow        Duration `toml:"time_window_hours"`  // Time window of the repository's merged PRs (optional)
+	RequiredApprovals *int     `toml:"required_approvals"` // Distinct reviewers who must approve merged PRs, 0 for any approval (optional)
+	ExcludedAuthors   []string `toml:"excluded_authors"`   // Logins whose merged PRs are not checked, replacing excluded_authors (optional)
+	ExcludeBots       *bool    `toml:"exclude_bots"`       // Whether merged PRs authored by bots are not checked (optional)
+}
+
+// MergeHoursConfig flags PRs merged outside business hours, for change-management audits
+type MergeHoursConfig struct {
+	Enabled  bool     `toml:"enabled"`  // Whether PRs merged outside business hours are flagged
+	Days     []string `toml:"days"`     // Days merges are expected on, e.g. "mon" (default Monday to Friday)
+	Start    string   `toml:"start"`    // Start of business hours as "HH:MM" (default "09:00")
+	End      string   `toml:"end"`      // End of business hours as "HH:MM" (default "17:00")
+	Timezone string   `toml:"timezone"` // IANA timezone of business hours, the reporting timezone when empty (optional)
+}
+
+// RepoVisibilityConfig contains configuration for the repository visibility checker
+type RepoVisibilityConfig struct {
+	Enabled bool `toml:"enabled"` // Whether the repository visibility checker is enabled
+
+	// Repository visibility filter. Options: "all", "public-only", "private-only", "specific"
+	RepoVisibility string `toml:"repo_visibility"`
+
+	// Organizations to monitor for repository visibility changes
+	Organizations []string `toml:"organizations"`
+
+	// Time window to look for visibility changes, e.g. "36h", "7d" or a number of hours
+	CheckWindow Duration `toml:"check_window_hours"`
+
+	// Dedicated output for this monitor (optional)
+	Output OutputConfig `toml:"output"`
+}
+
+// RulesetsConfig contains configuration for the repository rulesets drift monitor
+type RulesetsConfig struct {
+	Enabled bool `toml:"enabled"` // Whether the rulesets drift monitor is enabled
+
+	// Organizations whose organization-level rulesets are checked
+	Organizations []string `toml:"organizations"`
+
+	// Repositories ("owner/repo") whose effective rulesets are checked
+	Repositories []string `toml:"repositories"`
+
+	// Desired state that the fetched rulesets are compared against
+	Desired []DesiredRuleset `toml:"desired"`
+
+	// Dedicated output for this monitor (optional)
+	Output OutputConfig `toml:"output"`
+}
+
+// DesiredRuleset declares a ruleset that is expected to exist
+type DesiredRuleset struct {
+	Name          string   `toml:"name"`           // Name of the ruleset as shown in GitHub
+	Scope         string   `toml:"scope"`          // Options: "organization", "repository"
+	Enforcement   string   `toml:"enforcement"`    // Expected enforcement, defaults to "active"
+	AllowBypass   bool     `toml:"allow_bypass"`   // Whether bypass actors are acceptable
+	RequiredRules []string `toml:"required_rules"` // Rule types that must be present (e.g. "pull_request")
+}
+
+// CodeScanningConfig contains configuration for the dismissed code scanning alert monitor
+type CodeScanningConfig struct {
+	Enabled bool `toml:"enabled"` // Whether the dismissed code scanning alert monitor is enabled
+
+	// Organizations whose code scanning alerts are audited
+	Organizations []string `toml:"organizations"`
+
+	// Repositories ("owner/repo") whose code scanning alerts are audited
+	Repositories []string `toml:"repositories"`
+
+	// Time window to look for dismissed alerts, e.g. "36h", "7d" or a number of hours
+	CheckWindow Duration `toml:"check_window_hours"`
+
+	// Page size and page cap of listing the code scanning alerts of each repository or organization (optional)
+	Pagination PaginationConfig `toml:"pagination"`
+
+	// Dedicated output for this monitor (optional)
+	Output OutputConfig `toml:"output"`
+}
+
+// DependabotConfig contains configuration for the dismissed Dependabot alert monitor
+type DependabotConfig struct {
+	Enabled bool `toml:"enabled"` // Whether the dismissed Dependabot alert monitor is enabled
+
+	// Organizations whose Dependabot alerts are audited
+	Organizations []string `toml:"organizations"`
+
+	// Repositories ("owner/repo") whose Dependabot alerts are audited
+	Repositories []string `toml:"repositories"`
+
+	// Only report dismissals of alerts at or above this severity.
+	// Options: "low", "medium", "high", "critical". Empty reports all severities
+	MinimumSeverity string `toml:"minimum_severity"`
+
+	// Time window to look for dismissed alerts, e.g. "36h", "7d" or a number of hours
+	CheckWindow Duration `toml:"check_window_hours"`
+
+	// Dedicated output for this monitor (optional)
+	Output OutputConfig `toml:"output"`
+}
+
+// PushProtectionConfig contains configuration for the secret scanning push protection bypass monitor
+type PushProtectionConfig struct {
+	Enabled bool `toml:"enabled"` // Whether the push protection bypass monitor is enabled
+
+	// Organizations whose secret scanning alerts are audited
+	Organizations []string `toml:"organizations"`
+
+	// Repositories ("owner/repo") whose secret scanning alerts are audited
+	Repositories []string `toml:"repositories"`
+
+	// Time window to look for push protection bypasses, e.g. "36h", "7d" or a number of hours
+	CheckWindow Duration `toml:"check_window_hours"`
+
+	// Dedicated output for this monitor (optional)
+	Output OutputConfig `toml:"output"`
+}
+
+// WorkflowPermissionsConfig contains configuration for the workflow token permission monitor
+type WorkflowPermissionsConfig struct {
+	Enabled bool `toml:"enabled"` // Whether the workflow token permission monitor is enabled
+
+	// Organizations whose default workflow token permissions are audited
+	Organizations []string `toml:"organizations"`
+
+	// Repositories ("owner/repo") whose default workflow token permissions are audited
+	Repositories []string `toml:"repositories"`
+
+	// Don't report a GITHUB_TOKEN with read and write permissions by default, only workflows approving PRs
+	AllowWriteDefault bool `toml:"allow_write_default"`
+
+	// Dedicated output for this monitor (optional)
+	Output OutputConfig `toml:"output"`
+}
+
+// EnvironmentsConfig contains configuration for the deployment environment protection monitor
+type EnvironmentsConfig struct {
+	Enabled bool `toml:"enabled"` // Whether the deployment environment protection monitor is enabled
+
+	// Organizations whose repositories' environments are audited
+	Organizations []string `toml:"organizations"`
+
+	// Repositories ("owner/repo") whose environments are audited
+	Repositories []string `toml:"repositories"`
+
+	// Names of the protected environments to audit, e.g. "production"
+	Environments []string `toml:"environments"`
+
+	// Time window to look for deployments, e.g. "36h", "7d" or a number of hours
+	CheckWindow Duration `toml:"check_window_hours"`
+
+	// Protection the environments must have, reported as drift otherwise
+	RequireReviewers    bool `toml:"require_reviewers"`      // Deployments need the approval of required reviewers
+	MinWaitTimer        int  `toml:"min_wait_timer_minutes"` // Minimum wait timer before deploying, 0 to not check it
+	RequireBranchPolicy bool `toml:"require_branch_policy"`  // Only protected or selected branches can deploy
+
+	// Page size and page cap of listing the deployments to each environment of a repository (optional)
+	Pagination PaginationConfig `toml:"pagination"`
+
+	// Dedicated output for this monitor (optional)
+	Output OutputConfig `toml:"output"`
+}
+
+// ForkRunsConfig contains configuration for the fork workflow run approval monitor
+type ForkRunsConfig struct {
+	Enabled bool `toml:"enabled"` // Whether the fork workflow run approval monitor is enabled
+
+	// Organizations whose repositories' workflow runs are audited
+	Organizations []string `toml:"organizations"`
+
+	// Repositories ("owner/repo") whose workflow runs are audited
+	Repositories []string `toml:"repositories"`
+
+	// Time window to look for approved workflow runs, e.g. "36h", "7d" or a number of hours
+	CheckWindow Duration `toml:"check_window_hours"`
+
+	// Page size and page cap of listing the workflow runs of each repository, per triggering event (optional)
+	Pagination PaginationConfig `toml:"pagination"`
+
+	// Dedicated output for this monitor (optional)
+	Output OutputConfig `toml:"output"`
+}
+
+// RepoCreationConfig contains configuration for the repository creation monitor
+type RepoCreationConfig struct {
+	Enabled bool `toml:"enabled"` // Whether the repository creation monitor is enabled
+
+	// Organizations to monitor for new repositories
+	Organizations []string `toml:"organizations"`
+
+	// Time window to look for new repositories, e.g. "36h", "7d" or a number of hours
+	CheckWindow Duration `toml:"check_window_hours"`
+
+	// Templates ("owner/repo") new repositories can be created from
+	ApprovedTemplates []string `toml:"approved_templates"`
+
+	// Organization roles whose members can create repositories without a template: "admin" (owners) or "member"
+	ApprovedRoles []string `toml:"approved_roles"`
+
+	// Users, e.g. provisioning bots, that can create repositories without a template
+	ApprovedCreators []string `toml:"approved_creators"`
+
+	// Dedicated output for this monitor (optional)
+	Output OutputConfig `toml:"output"`
+}
+
+// IssueHygieneConfig contains configuration for the issue hygiene monitor of public repositories
+type IssueHygieneConfig struct {
+	Enabled bool `toml:"enabled"` // Whether the issue hygiene monitor is enabled
+
+	// Organizations whose public repositories are checked
+	Organizations []string `toml:"organizations"`
+
+	// Repositories ("owner/repo") checked when they are public
+	Repositories []string `toml:"repositories"`
+
+	// How long external issues can stay untriaged, e.g. "72h", "7d" or a number of hours
+	TriageSLA Duration `toml:"triage_sla_hours"`
+
+	// Labels marking issues as waiting for triage, e.g. "needs-triage", which do not count as triaged
+	TriageLabels []string `toml:"triage_labels"`
+
+	// Dedicated output for this monitor (optional)
+	Output OutputConfig `toml:"output"`
+}
+
+// OpenPRCheckerConfig contains configuration for the monitor of open PRs without reviews
+type OpenPRCheckerConfig struct {
+	Enabled bool `toml:"enabled"` // Whether the open PR monitor is enabled
+
+	// Organizations whose repositories are checked
+	Organizations []string `toml:"organizations"`
+
+	// Repositories ("owner/repo") to check
+	Repositories []string `toml:"repositories"`
+
+	// How long an open PR can wait for its first review, e.g. "36h", "2d" or a number of hours
+	MinAge Duration `toml:"min_age_hours"`
+
+	// Whether draft PRs are reported too
+	IncludeDrafts bool `toml:"include_drafts"`
+
+	// Page size and page cap of listing the open PRs of each repository (optional)
+	Pagination PaginationConfig `toml:"pagination"`
+
+	// Dedicated output for this monitor (optional)
+	Output OutputConfig `toml:"output"`
+}
+
+// AdminEnforcementConfig contains configuration for the audit of branch protection not enforced for administrators
+type AdminEnforcementConfig struct {
+	Enabled bool `toml:"enabled"` // Whether the admin enforcement audit is enabled
+
+	// Organizations whose repositories' protected branches are audited
+	Organizations []string `toml:"organizations"`
+
+	// Time window to look for branch protection overrides in the audit log, e.g. "36h", "7d" or a number of hours
+	CheckWindow Duration `toml:"check_window_hours"`
+
+	// Dedicated output for this monitor (optional)
+	Output OutputConfig `toml:"output"`
+}
+
+// CodeownersConfig contains configuration for the CODEOWNERS coverage report
+type CodeownersConfig struct {
+	Enabled bool `toml:"enabled"` // Whether the CODEOWNERS coverage report is enabled
+
+	// Organizations whose repositories are checked
+	Organizations []string `toml:"organizations"`
+
+	// Repositories ("owner/repo") to check
+	Repositories []string `toml:"repositories"`
+
+	// Share of the sampled files CODEOWNERS must cover, in percent
+	MinCoverage float64 `toml:"min_coverage"`
+
+	// Number of files sampled from the tree of each repository
+	SampleSize int `toml:"sample_size"`
+
+	// Paths in CODEOWNERS syntax that must always have owners, e.g. "/infra/" (optional)
+	CriticalPaths []string `toml:"critical_paths"`
+
+	// Dedicated output for this monitor (optional)
+	Output OutputConfig `toml:"output"`
+}
+
+// GHASConfig contains configuration for the GitHub Advanced Security utilization report
+type GHASConfig struct {
+	Enabled bool `toml:"enabled"` // Whether the GitHub Advanced Security utilization report is enabled
+
+	// Organizations whose seat usage is reported, which needs an organization owner or billing manager token
+	Organizations []string `toml:"organizations"`
+
+	// Dedicated output for this monitor (optional)
+	Output OutputConfig `toml:"output"`
+}
+
+// TokenHealthConfig contains configuration for the GitHub token health monitor
+type TokenHealthConfig struct {
+	Enabled bool `toml:"enabled"` // Whether the token health monitor is enabled
+
+	// Organizations the token must be authorized for when they enforce SAML single sign-on (optional)
+	Organizations []string `toml:"organizations"`
+
+	// Days before the token expires to start alerting
+	ExpiryWarningDays int `toml:"expiry_warning_days"`
+
+	// Percentage of the rate limit the token must have left
+	MinRateLimitHeadroom int `toml:"min_rate_limit_headroom"`
+
+	// OAuth scopes a classic token must keep, e.g. "repo" or "read:org" (optional)
+	RequiredScopes []string `toml:"required_scopes"`
+
+	// Dedicated output for this monitor (optional)
+	Output OutputConfig `toml:"output"`
+}
+
+// DormantAccessConfig contains configuration for the dormant privileged account monitor
+type DormantAccessConfig struct {
+	Enabled bool `toml:"enabled"` // Whether the dormant privileged account monitor is enabled
+
+	// Organizations whose owners are checked for recent activity
+	Organizations []string `toml:"organizations"`
+
+	// Repositories ("owner/repo") whose admin and maintain collaborators are checked
+	Repositories []string `toml:"repositories"`
+
+	// Accounts with no activity for this many days are reported
+	InactiveDays int `toml:"inactive_days"`
+
+	// Accounts that are never reported (e.g. break-glass or service accounts)
+	ExcludedUsers []string `toml:"excluded_users"`
+
+	// Dedicated output for this monitor (optional)
+	Output OutputConfig `toml:"output"`
+}
+
+// DormantReposConfig contains configuration for the dormant repository monitor
+type DormantReposConfig struct {
+	Enabled bool `toml:"enabled"` // Whether the dormant repository monitor is enabled
+
+	// Organizations whose repositories are checked for recent activity
+	Organizations []string `toml:"organizations"`
+
+	// Repositories ("owner/repo") checked for recent activity
+	Repositories []string `toml:"repositories"`
+
+	// Repositories without pushes, issues or pull requests for this many days are reported
+	InactiveDays int `toml:"inactive_days"`
+
+	// Whether the report suggests archiving dormant repositories
+	SuggestArchive bool `toml:"suggest_archive"`
+
+	// Dedicated output for this monitor (optional)
+	Output OutputConfig `toml:"output"`
+}
+
+// OutputConfig configures a dedicated output file for a single monitor
+// When Path is empty the monitor's results are merged into the combined report
+type OutputConfig struct {
+	Path   string `toml:"path"`   // File the monitor's results are written to
+	Format string `toml:"format"` // Options: "markdown" (default), "json", "csv"
+}
+
+// DefaultPageSize is the number of items listed per GitHub API request, the most GitHub returns
+const DefaultPageSize = 100
+
+// PaginationConfig configures how a monitor pages through the pull requests, or other items, of each repository
+type PaginationConfig struct {
+	PageSize int `toml:"page_size"` // Items per request, 1 to 100 (default 100)
+	MaxPages int `toml:"max_pages"` // Pages fetched per repository at most, 0 for no limit
+}
+
+// PerPage returns the page size, the default when not set
+func (p PaginationConfig) PerPage() int {
+	if p.PageSize == 0 {
+		return DefaultPageSize
+	}
+	return p.PageSize
+}
+
+// StateConfig contains configuration for the state persisted between runs
+type StateConfig struct {
+	Enabled bool   `toml:"enabled"` // Whether findings are persisted and compared with the previous run
+	Path    string `toml:"path"`    // File the state is stored in, or URL of a bbolt, SQLite, PostgreSQL or S3 store
+}
+
+// SuppressionsConfig contains configuration for suppression files committed in repositories
+type SuppressionsConfig struct {
+	InRepo bool   `toml:"in_repo"` // Whether findings are checked against the suppression file of their repository
+	Path   string `toml:"path"`    // Path of the suppression file in each repository
+
+	// Suppressions expiring within this many days are listed in the report. 0 disables the section
+	ExpiringSoonDays int `toml:"expiring_soon_days"`
+}
+
+// RedactionConfig contains configuration for redacting repository names in Slack notifications
+// The full report is still written to the markdown output file, which should be kept restricted
+type RedactionConfig struct {
+	Enabled     bool   `toml:"enabled"`      // Whether repository names are redacted in Slack notifications
+	AliasesFile string `toml:"aliases_file"` // TOML file mapping repository names to aliases, others are hashed
+	Salt        string `toml:"salt"`         // Secret mixed into hashes, so they cannot be matched against known names
+}
+
+// ScoringConfig contains configuration for risk scores computed from weighted findings
+type ScoringConfig struct {
+	Enabled bool `toml:"enabled"` // Whether per-repository and per-run risk scores are reported
+
+	// Weight of each finding by monitor key (e.g. pr_checker = 5). Monitors without a weight count 1
+	Weights map[string]int `toml:"weights"`
+
+	// Run score at or above which the run exits with code 2 and the page webhook is notified
+	Threshold int `toml:"threshold"`
+
+	// Slack webhook of a paging or on-call channel, notified immediately when the threshold is reached
+	PageWebhook string `toml:"page_webhook"`
+}
+
+// ComparisonConfig contains configuration for the side-by-side comparison of organizations in the report
+type ComparisonConfig struct {
+	Enabled bool `toml:"enabled"` // Whether the findings, risk score and coverage of each organization are compared
+}
+
+// CoverageReportConfig contains configuration for the report of the targets each monitor scanned
+type CoverageReportConfig struct {
+	// Whether the report lists the targets scanned, errored and skipped, and with state, those discovered
+	// or gone since the previous run
+	Enabled bool `toml:"enabled"`
+}
+
+// ComplianceConfig maps monitors to the compliance controls their findings are evidence for
+type ComplianceConfig struct {
+	// Control IDs by monitor key, e.g. pr_checker = ["SOC2 CC8.1", "ISO 27001 A.8.32"]
+	Controls map[string][]string `toml:"controls"`
+}
+
+// RemediationConfig contains configuration for the links to where findings are fixed in reports and notifications
+type RemediationConfig struct {
+	Enabled bool `toml:"enabled"` // Whether reports list runbooks and the pages to fix each finding at
+
+	// Runbook URL templates by monitor key (e.g. repo_visibility) or by monitor and rule (e.g. "pr_checker.unapproved"),
+	// which takes precedence over the monitor's. {monitor}, {rule} and {repository} are replaced with the finding's
+	Runbooks map[string]string `toml:"runbooks"`
+}
+
+// SLAConfig contains configuration for the remediation deadlines of findings
+type SLAConfig struct {
+	Enabled bool `toml:"enabled"` // Whether findings open past their deadline are reported as SLA breaches
+
+	// Time to remediate findings from when they were first seen, by monitor key (e.g. repo_visibility = "4h")
+	// or by monitor and rule (e.g. "pr_checker.unapproved" = "3d"), which takes precedence over the monitor's
+	Deadlines map[string]Duration `toml:"deadlines"`
+
+	// Slack webhook notified of findings as their deadline passes, the run's webhook when empty
+	Webhook string `toml:"webhook"`
+}
+
+// EscalationConfig contains configuration for escalating findings that stay unresolved
+type EscalationConfig struct {
+	Enabled bool `toml:"enabled"` // Whether findings persisting across runs are escalated
+
+	// Policies escalating findings, applied from the least to the most persistent findings they match
+	Policies []EscalationPolicy `toml:"policies"`
+}
+
+// EscalationPolicy escalates the findings reported in at least AfterRuns consecutive runs, or first seen at least
+// After ago, whichever comes first
+type EscalationPolicy struct {
+	Name      string   `toml:"name"`       // Name of the policy, recorded with the escalations of findings
+	AfterRuns int      `toml:"after_runs"` // Consecutive runs reporting a finding before it escalates, 0 to ignore
+	After     Duration `toml:"after"`      // Time since a finding was first seen before it escalates, 0 to ignore
+	Severity  string   `toml:"severity"`   // Severity of escalated findings: medium, high or critical
+	Webhook   string   `toml:"webhook"`    // Slack webhook notified of findings when they escalate, e.g. of managers
+	Monitors  []string `toml:"monitors"`   // Monitors whose findings escalate, all when empty
+}
+
+// validEscalationSeverities are the severities findings escalate to
+var validEscalationSeverities = map[string]bool{"medium": true, "high": true, "critical": true}
+
+// OwnershipConfig contains configuration for assigning repositories to the teams owning them,
+// so reports group findings by team and teams are sent their own findings
+type OwnershipConfig struct {
+	Enabled bool `toml:"enabled"` // Whether findings are grouped by owning team
+
+	// Where owners are looked up, in order, the first owner found is used (default ["config"])
+	// "config" uses teams, "codeowners" the team owning "*" in the repository's CODEOWNERS file,
+	// and "teams" the team with admin access to the repository
+	Sources []string `toml:"sources"`
+
+	// Owning team of repositories by "owner/repo" or glob, e.g. "acme/payments-*". The longest matching pattern wins
+	Teams map[string]string `toml:"teams"`
+
+	// Team of repositories no source assigns, "unowned" when empty
+	DefaultTeam string `toml:"default_team"`
+
+	// Slack webhooks of teams by team name, which are sent their findings after each run
+	Webhooks map[string]string `toml:"webhooks"`
+}
+
+// EvidenceConfig contains configuration for the signed evidence bundle written for each run
+type EvidenceConfig struct {
+	Enabled bool   `toml:"enabled"` // Whether an evidence bundle is written
+	Path    string `toml:"path"`    // File the bundle is written to, the signature is written next to it
+
+	// How the bundle is signed. Options: "" (unsigned), "key" (cosign or PEM private key), "gpg"
+	Signer      string `toml:"signer"`
+	KeyPath     string `toml:"key_path"`     // Private key for the "key" signer
+	KeyPassword string `toml:"key_password"` // Password of an encrypted cosign key. COSIGN_PASSWORD takes precedence
+	GPGKey      string `toml:"gpg_key"`      // Key ID for the "gpg" signer, the default key when empty
+}
+
+// ArchiveConfig contains configuration for the archive of the reports of every run, browsed with the reports subcommand
+type ArchiveConfig struct {
+	Enabled  bool   `toml:"enabled"`  // Whether the report of each run is archived
+	Location string `toml:"location"` // Directory, or "s3://bucket/prefix", the reports are archived in
+
+	// Reports of runs started longer ago are pruned after each run, e.g. "90d". 0 keeps them
+	MaxAge Duration `toml:"max_age"`
+	// Only this many of the latest reports are kept, 0 keeps all
+	MaxReports int `toml:"max_reports"`
+}
+
+// ProjectsConfig contains configuration for tracking open findings as items of a GitHub Projects board
+type ProjectsConfig struct {
+	Enabled bool   `toml:"enabled"` // Whether findings are synced to the board after each run
+	Owner   string `toml:"owner"`   // Organization or user owning the project
+	Number  int    `toml:"number"`  // Number of the project, as in its URL
+
+	// Single select field tracking the status of items, and its options for open and resolved findings
+	StatusField string `toml:"status_field"`
+	OpenStatus  string `toml:"open_status"`
+	DoneStatus  string `toml:"done_status"`
+
+	// Token with the project scope, the [github] token when empty. GIT_MONITOR_PROJECTS_TOKEN takes precedence
+	Token string `toml:"token"`
+
+	// GraphQL API the board is reached through, e.g. that of GitHub Enterprise Server (default github.com's)
+	GraphQLURL string `toml:"graphql_url"`
+}
+
+// ServiceNowConfig contains configuration for the ServiceNow records opened for severe findings
+type ServiceNowConfig struct {
+	Enabled     bool   `toml:"enabled"`      // Whether records are opened after each run
+	InstanceURL string `toml:"instance_url"` // URL of the instance, e.g. https://example.service-now.com
+	Table       string `toml:"table"`        // Table records are created in, one extending task such as incident
+
+	// Credentials of the integration user. GIT_MONITOR_SERVICENOW_PASSWORD takes precedence over password
+	Username string `toml:"username"`
+	Password string `toml:"password"`
+
+	// Least severe severity of the findings records are opened for: medium, high or critical
+	MinSeverity string `toml:"min_severity"`
+
+	// Assignment group and configuration item of the records, as names or sys_ids
+	AssignmentGroup   string `toml:"assignment_group"`
+	ConfigurationItem string `toml:"configuration_item"`
+
+	// Other fields set on the records (e.g. category = "security"), overriding those set by git-monitor
+	Fields map[string]string `toml:"fields"`
+}
+
+// EventsConfig contains configuration for the sinks the findings opened and resolved by each run are published to,
+// as CloudEvents
+type EventsConfig struct {
+	Source    string          `toml:"source"` // Source of the events, identifying the deployment (default "git-monitor")
+	EventGrid EventGridConfig `toml:"event_grid"`
+	PubSub    PubSubConfig    `toml:"pubsub"`
+}
+
+// EventGridConfig contains configuration for publishing events to an Azure Event Grid topic
+type EventGridConfig struct {
+	Enabled  bool   `toml:"enabled"`
+	Endpoint string `toml:"endpoint"` // Endpoint of a topic using the CloudEvents schema
+	Key      string `toml:"key"`      // Access key of the topic. GIT_MONITOR_EVENTGRID_KEY takes precedence
+}
+
+// PubSubConfig contains configuration for publishing events to a Google Cloud Pub/Sub topic
+type PubSubConfig struct {
+	Enabled bool   `toml:"enabled"`
+	Project string `toml:"project"` // Project of the topic
+	Topic   string `toml:"topic"`   // Name of the topic
+
+	// OAuth access token allowed to publish to the topic, GIT_MONITOR_PUBSUB_TOKEN takes precedence
+	// When empty, a token of the service account is requested from the metadata server of GCE, GKE or Cloud Run
+	Token string `toml:"token"`
+
+	// Pub/Sub API the topic is reached through, e.g. the emulator (default https://pubsub.googleapis.com)
+	Endpoint string `toml:"endpoint"`
+}
+
+// EncryptionConfig contains configuration for encrypting the state and the reports written to disk with AES-GCM
+type EncryptionConfig struct {
+	Enabled bool `toml:"enabled"` // Whether the state, checkpoints, archive and written reports are encrypted
+
+	// Base64 encoded AES key of 16, 24 or 32 bytes. GIT_MONITOR_ENCRYPTION_KEY takes precedence
+	Key string `toml:"key"`
+
+	// Base64 encoded ciphertext of a data key generated with AWS KMS, decrypted with KMS when the run starts
+	// using the AWS_* environment variables of S3 state stores
+	KMSDataKey string `toml:"kms_data_key"`
+}
+
+// CheckpointConfig contains configuration for the progress saved during a scan, to resume it with --resume
+type CheckpointConfig struct {
+	Enabled bool   `toml:"enabled"` // Whether progress is saved after each monitor and interrupted scans stop gracefully
+	Path    string `toml:"path"`    // File the progress is saved to, removed once a scan completes
+}
+
+// MembershipConfig contains configuration for the membership cache kept between runs
+// Membership lookups are always cached within a run
+type MembershipConfig struct {
+	Enabled bool     `toml:"enabled"` // Whether membership lookups are saved and reused by the next runs
+	Path    string   `toml:"path"`    // File the lookups are saved to
+	TTL     Duration `toml:"ttl"`     // How long a lookup is reused, e.g. "24h"
+}
+
+// HeartbeatConfig contains the URLs of a dead man's switch check (e.g. Healthchecks.io or Cronitor),
+// pinged by each run so the check alerts when runs stop
+type HeartbeatConfig struct {
+	URL      string `toml:"url"`       // Pinged when a run succeeds, empty disables the heartbeat
+	StartURL string `toml:"start_url"` // Pinged when a run starts, optional
+	FailURL  string `toml:"fail_url"`  // Pinged when monitors of a run fail, optional
+}
+
+// MetricsConfig contains configuration for the metrics of the monitoring itself: how long monitors ran,
+// what they checked and what they cost, in the Prometheus text format
+type MetricsConfig struct {
+	// File the metrics of each run are written to, e.g. for the node exporter's textfile collector
+	// Empty writes no file. The server always serves the metrics of its last scan on /metrics
+	Path string `toml:"path"`
+}
+
+// RepoPolicyConfig contains configuration for policy files in which repositories override selected thresholds
+// Overrides are limited by the caps below, so teams can tune their checks within central guardrails
+type RepoPolicyConfig struct {
+	Enabled bool   `toml:"enabled"` // Whether repositories' policy files are read
+	Path    string `toml:"path"`    // Path of the policy file in each repository
+
+	// Highest inactive_days a repository can set for the dormant repository monitor
+	// 0 caps overrides at the central inactive_days, so repositories can only be stricter
+	MaxDormantInactiveDays int `toml:"max_dormant_inactive_days"`
+
+	// Lowest min_review_time a repository can set for the PR checker
+	// 0 floors overrides at the central min_review_time, so repositories can only be stricter
+	MinReviewTimeFloor Duration `toml:"min_review_time_floor"`
+
+	// Highest required_approvals a repository can set for the PR checker
+	// 0 caps overrides at the central required_approvals, so repositories cannot require more
+	MaxRequiredApprovals int `toml:"max_required_approvals"`
+
+	// Lowest sla days a repository can set for the remediation deadlines of its findings
+	// 0 leaves the central deadlines, so repositories cannot shorten them
+	MinSLADays int `toml:"min_sla_days"`
+}
+
+// ServerConfig contains configuration for server mode (the serve subcommand)
+type ServerConfig struct {
+	Listen     string `toml:"listen"`      // Address the REST API listens on, e.g. ":8080"
+	GRPCListen string `toml:"grpc_listen"` // Address the gRPC API listens on, e.g. ":9090". Empty disables gRPC
+
+	// Bearer token required by the API. The GIT_MONITOR_API_TOKEN environment variable takes precedence
+	// Leave empty to serve the API without authentication
+	AuthToken string `toml:"auth_token"`
+
+	// Number of finished scan runs kept in memory for polling
+	MaxRuns int `toml:"max_runs"`
+
+	// Slack app serving the slash command
+	Slack SlackAppConfig `toml:"slack"`
+
+	// GitHub webhook receiver, scanning repositories as their pull requests are merged
+	GitHubWebhook GitHubWebhookConfig `toml:"github_webhook"`
+
+	// Budget of the hourly API calls of each token across the monitors of scans
+	RateBudget RateBudgetConfig `toml:"rate_budget"`
+
+	// Election of the replica that scans and notifies, when several replicas run for availability
+	LeaderElection LeaderElectionConfig `toml:"leader_election"`
+}
+
+// LeaderElectionConfig contains configuration for electing a leader among the replicas of the server
+// Only the leader runs scans and retries notifications, so replicas do not send duplicate alerts
+type LeaderElectionConfig struct {
+	Enabled bool `toml:"enabled"` // Whether replicas elect a leader
+
+	// Where the lease is kept: "state" (default), in the state store shared by the replicas,
+	// or "kubernetes", in a coordination.k8s.io Lease
+	Backend string `toml:"backend"`
+
+	LeaseName string   `toml:"lease_name"`     // Name of the Kubernetes Lease (default "git-monitor")
+	Namespace string   `toml:"namespace"`      // Namespace of the Kubernetes Lease (default: the pod's namespace)
+	Duration  Duration `toml:"lease_duration"` // How long a lease lasts without renewal (default "30s")
+
+	// Name of this replica in the lease (default: the hostname, the pod name in Kubernetes)
+	Identity string `toml:"identity"`
+}
+
+// RateBudgetConfig contains configuration for budgeting the hourly API calls across monitors in server mode
+type RateBudgetConfig struct {
+	Enabled bool `toml:"enabled"` // Whether monitors are deferred once their share of the hourly calls is used up
+
+	// API calls per hour of each account's token shared by the monitors (default 4500, the pace of the rate limiter)
+	HourlyCalls int `toml:"hourly_calls"`
+
+	// Percentage of the hourly calls of a monitor, by monitor key, e.g. {pr_checker = 60}
+	// Monitors without a share split the rest equally
+	Shares map[string]float64 `toml:"shares"`
+}
+
+// GitHubWebhookConfig contains configuration for the GitHub webhook receiver served in server mode
+type GitHubWebhookConfig struct {
+	Enabled bool `toml:"enabled"` // Whether the /github/webhook endpoint is served
+
+	// Secret of the webhook, used to verify deliveries. The GITHUB_WEBHOOK_SECRET environment variable takes precedence
+	Secret string `toml:"secret"`
+
+	// How long delivery IDs are remembered to ignore replayed deliveries (default 72 hours)
+	// They are kept in the state when it is enabled, so replays are also ignored after a restart
+	// Deliveries of events older than the retention are rejected
+	DeliveryRetention Duration `toml:"delivery_retention_hours"`
+}
+
+// SlackAppConfig contains configuration for the Slack slash command served in server mode
+type SlackAppConfig struct {
+	Enabled bool `toml:"enabled"` // Whether the /slack/commands endpoint is served
+
+	// Signing secret of the Slack app. The SLACK_SIGNING_SECRET environment variable takes precedence
+	SigningSecret string `toml:"signing_secret"`
+
+	// Bot token used to reply in a thread. The SLACK_BOT_TOKEN environment variable takes precedence
+	// Without it, results are sent to the command's response URL
+	BotToken string `toml:"bot_token"`
+
+	// IDs of the Slack users (e.g. "U0123ABCD") and channels (e.g. "C0123ABCD") the command and triage buttons
+	// are accepted from. Empty lists accept every member of the workspace
+	AllowedUsers    []string `toml:"allowed_users"`
+	AllowedChannels []string `toml:"allowed_channels"`
+}
+
+// NotificationsConfig contains configuration for how results are delivered to notification channels
+type NotificationsConfig struct {
+	Schedule ScheduleConfig `toml:"schedule"`
+	Retry    RetryConfig    `toml:"retry"`
+	Ops      OpsConfig      `toml:"ops"`
+
+	// Only send the run's Slack notification and team notifications when their findings differ from those
+	// last notified, requires state. Reports, outputs and metrics are still written every run
+	OnChangeOnly bool `toml:"on_change_only"`
+}
+
+// OpsConfig contains configuration for operational alerts about the monitoring itself,
+// such as monitors failing because of a revoked token or an API outage
+type OpsConfig struct {
+	// Slack webhook of the operations channel, distinct from the channels findings are sent to
+	// Empty disables the alerts
+	Webhook string `toml:"webhook"`
+}
+
+// RetryConfig keeps notifications that could not be delivered in the state, to retry them
+// with the next run and, in server mode, periodically
+type RetryConfig struct {
+	Enabled  bool     `toml:"enabled"`  // Whether undelivered notifications are kept, requires state
+	MaxAge   Duration `toml:"max_age"`  // Undelivered notifications older than this are dropped
+	Interval Duration `toml:"interval"` // How often the server retries undelivered notifications
+}
+
+// ScheduleConfig restricts real-time notifications to business hours
+// Findings outside business hours are queued and sent as a digest with the first notification of the next business day
+type ScheduleConfig struct {
+	Enabled bool `toml:"enabled"` // Whether notifications are scheduled
+
+	// Days on which real-time notifications are sent (e.g. "mon", "tue")
+	Days []string `toml:"days"`
+
+	// Start and end of business hours as "HH:MM" in the configured timezone
+	Start string `toml:"start"`
+	End   string `toml:"end"`
+
+	// File where off-hours findings are queued until the next digest
+	QueuePath string `toml:"queue_path"`
+
+	// Monitors whose findings are always sent immediately, besides those with critical findings
+	CriticalMonitors []string `toml:"critical_monitors"`
+}
+
+// Filters contains repository filtering configuration
+// They apply to the repositories monitors discover by listing an organization, team or user,
+// not to repositories listed explicitly. Empty filters keep every repository
+type Filters struct {
+	Topic      string   `toml:"topic"`      // Only keep repositories with this topic
+	Exclusions []string `toml:"exclusions"` // Repositories to leave out, as "owner/repo"
+
+	// Only keep repositories whose primary language is one of these, e.g. "Go", compared case-insensitively
+	Languages []string `toml:"languages"`
+
+	// Only keep repositories of at least and at most this size in kilobytes, 0 for no limit
+	MinSizeKB int `toml:"min_size_kb"`
+	MaxSizeKB int `toml:"max_size_kb"`
+
+	// Only keep forks (true) or only repositories that are not forks (false). Unset keeps both
+	IsFork *bool `toml:"is_fork"`
+	// Only keep archived (true) or only active (false) repositories. Unset keeps both
+	IsArchived *bool `toml:"is_archived"`
+	// Leave out archived repositories when is_archived is unset, on by default as they no longer change
+	ExcludeArchived bool `toml:"exclude_archived"`
+	// Only keep template repositories (true) or only other repositories (false). Unset keeps both
+	IsTemplate *bool `toml:"is_template"`
+}
+
+// defaultMonitors returns the monitor configuration used for settings missing from the file
+func defaultMonitors() MonitorsConfig {
+	return MonitorsConfig{
+		PRChecker: PRCheckerConfig{
+			TimeWindow:           Hours(24),  // Default to 24 hours
+			RepoVisibility:       "specific", // Default to specific repos
+			SpecificRepositories: []string{}, // Empty list as default
+			ExcludedRepositories: []string{}, // Empty list as default
+			CacheVerdicts:        true,       // Default to reusing verdicts when state is enabled
+			OutOfWindowThreshold: 20,         // Default to stopping after 20 PRs merged before the window
+			MergeHours: MergeHoursConfig{
+				Days:  []string{"mon", "tue", "wed", "thu", "fri"},
+				Start: "09:00",
+				End:   "17:00",
+			},
+		},
+		RepoVisibility: RepoVisibilityConfig{
+			Enabled:        false,     // Default to disabled
+			CheckWindow:    Hours(24), // Default to 24 hours
+			Organizations:  []string{},
+			RepoVisibility: "specific", // Default to specific repos
+		},
+		Rulesets: RulesetsConfig{
+			Enabled:       false, // Default to disabled
+			Organizations: []string{},
+			Repositories:  []string{},
+		},
+		CodeScanning: CodeScanningConfig{
+			Enabled:       false, // Default to disabled
+			Organizations: []string{},
+			Repositories:  []string{},
+			CheckWindow:   Hours(24), // Default to 24 hours
+		},
+		Dependabot: DependabotConfig{
+			Enabled:       false, // Default to disabled
+			Organizations: []string{},
+			Repositories:  []string{},
+			CheckWindow:   Hours(24), // Default to 24 hours
+		},
+		PushProtection: PushProtectionConfig{
+			Enabled:       false, // Default to disabled
+			Organizations: []string{},
+			Repositories:  []string{},
+			CheckWindow:   Hours(24), // Default to 24 hours
+		},
+		WorkflowPermissions: WorkflowPermissionsConfig{
+			Enabled:       false, // Default to disabled
+			Organizations: []string{},
+			Repositories:  []string{},
+		},
+		Environments: EnvironmentsConfig{
+			Enabled:       false, // Default to disabled
+			Organizations: []string{},
+			Repositories:  []string{},
+			Environments:  []string{"production"},
+			CheckWindow:   Hours(24), // Default to 24 hours
+		},
+		ForkRuns: ForkRunsConfig{
+			Enabled:       false, // Default to disabled
+			Organizations: []string{},
+			Repositories:  []string{},
+			CheckWindow:   Hours(24), // Default to 24 hours
+		},
+		RepoCreation: RepoCreationConfig{
+			Enabled:           false, // Default to disabled
+			Organizations:     []string{},
+			CheckWindow:       Hours(24), // Default to 24 hours
+			ApprovedTemplates: []string{},
+			ApprovedRoles:     []string{},
+			ApprovedCreators:  []string{},
+		},
+		IssueHygiene: IssueHygieneConfig{
+			Enabled:       false, // Default to disabled
+			Organizations: []string{},
+			Repositories:  []string{},
+			TriageSLA:     Hours(7 * 24), // Default to a week
+			TriageLabels:  []string{},
+		},
+		AdminEnforcement: AdminEnforcementConfig{
+			Enabled:       false, // Default to disabled
+			Organizations: []string{},
+			CheckWindow:   Hours(7 * 24), // Default to a week
+		},
+		Codeowners: CodeownersConfig{
+			Enabled:       false, // Default to disabled
+			Organizations: []string{},
+			Repositories:  []string{},
+			MinCoverage:   80,   // Default to 80% of the files
+			SampleSize:    1000, // Default to 1000 files per repository
+			CriticalPaths: []string{},
+		},
+		GHAS: GHASConfig{
+			Enabled:       false, // Default to disabled
+			Organizations: []string{},
+		},
+		TokenHealth: TokenHealthConfig{
+			Enabled:              false, // Default to disabled
+			Organizations:        []string{},
+			ExpiryWarningDays:    14, // Default to two weeks
+			MinRateLimitHeadroom: 20, // Default to 20% of the rate limit
+			RequiredScopes:       []string{},
+		},
+		OpenPRChecker: OpenPRCheckerConfig{
+			Enabled:       false, // Default to disabled
+			Organizations: []string{},
+			Repositories:  []string{},
+			MinAge:        Hours(24), // Default to a day
+		},
+		DormantAccess: DormantAccessConfig{
+			Enabled:       false, // Default to disabled
+			Organizations: []string{},
+			Repositories:  []string{},
+			InactiveDays:  90, // Default to 90 days, the retention of the events API
+			ExcludedUsers: []string{},
+		},
+		DormantRepos: DormantReposConfig{
+			Enabled:       false, // Default to disabled
+			Organizations: []string{},
+			Repositories:  []string{},
+			InactiveDays:  180, // Default to 180 days
+		},
+	}
+}
+
+// LoadConfig loads the configuration from the specified file
+func LoadConfig(filePath string) (*Config, error) {
+	config := &Config{
+		Monitors: defaultMonitors(),
+	}
+
+	config.State = StateConfig{
+		Path: "git-monitor-state.json",
+	}
+
+	config.Suppressions = SuppressionsConfig{
+		Path:             ".git-monitor.yml",
+		ExpiringSoonDays: 7,
+	}
+
+	config.RepoPolicy = RepoPolicyConfig{
+		Path: ".github/git-monitor.toml",
+	}
+
+	config.RepoFilters = Filters{
+		ExcludeArchived: true,
+	}
+
+	config.Evidence = EvidenceConfig{
+		Path: "evidence.json",
+	}
+
+	config.Ownership = OwnershipConfig{
+		Sources: []string{"config"},
+	}
+
+	config.Archive = ArchiveConfig{
+		Location: "reports",
+		MaxAge:   Hours(90 * 24),
+	}
+
+	config.Projects = ProjectsConfig{
+		StatusField: "Status",
+		OpenStatus:  "Todo",
+		DoneStatus:  "Done",
+	}
+
+	config.ServiceNow = ServiceNowConfig{
+		Table:       "incident",
+		MinSeverity: "critical",
+	}
+
+	config.Events = EventsConfig{
+		Source: "git-monitor",
+	}
+
+	config.Checkpoint = CheckpointConfig{
+		Path: "git-monitor-checkpoint.json",
+	}
+
+	config.Membership = MembershipConfig{
+		Path: "git-monitor-membership.json",
+		TTL:  Hours(24),
+	}
+
+	config.Server = ServerConfig{
+		Listen:     ":8080",
+		MaxRuns:    100,
+		RateBudget: RateBudgetConfig{HourlyCalls: 4500},
+		LeaderElection: LeaderElectionConfig{
+			Backend:   "state",
+			LeaseName: "git-monitor",
+			Duration:  Duration{30 * time.Second},
+		},
+	}
+
+	config.Notifications.Schedule = ScheduleConfig{
+		Days:      []string{"mon", "tue", "wed", "thu", "fri"},
+		Start:     "09:00",
+		End:       "17:00",
+		QueuePath: "notification-digest.md",
+	}
+
+	config.Notifications.Retry = RetryConfig{
+		MaxAge:   Hours(7 * 24),
+		Interval: Duration{5 * time.Minute},
+	}
+
+	_, err := os.Stat(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("config file not found: %v", err)
+	}
+
+	_, err = toml.DecodeFile(filePath, config)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding config file: %v", err)
+	}
+
+	if err := decodeAccountMonitors(filePath, config); err != nil {
+		return nil, err
+	}
+
+	// Check if token is in environment variable
+	if envToken := os.Getenv("GITHUB_TOKEN"); envToken != "" {
+		config.GitHub.Token = envToken
+	}
+
+	// Check if the API token is in environment variable
+	if envToken := os.Getenv("GIT_MONITOR_API_TOKEN"); envToken != "" {
+		config.Server.AuthToken = envToken
+	}
+
+	// Check if the redaction salt is in environment variable
+	if envSalt := os.Getenv("GIT_MONITOR_REDACTION_SALT"); envSalt != "" {
+		config.Redaction.Salt = envSalt
+	}
+
+	// Check if the page webhook is in environment variable
+	if envWebhook := os.Getenv("GIT_MONITOR_PAGE_WEBHOOK"); envWebhook != "" {
+		config.Scoring.PageWebhook = envWebhook
+	}
+
+	// Check if the heartbeat URL is in environment variable
+	if envURL := os.Getenv("GIT_MONITOR_HEARTBEAT_URL"); envURL != "" {
+		config.Heartbeat.URL = envURL
+	}
+
+	// Check if the SLA webhook is in environment variable
+	if envWebhook := os.Getenv("GIT_MONITOR_SLA_WEBHOOK"); envWebhook != "" {
+		config.SLA.Webhook = envWebhook
+	}
+
+	// Check if the projects token is in environment variable
+	if envToken := os.Getenv("GIT_MONITOR_PROJECTS_TOKEN"); envToken != "" {
+		config.Projects.Token = envToken
+	}
+
+	// Check if the ServiceNow password is in environment variable
+	if envPassword := os.Getenv("GIT_MONITOR_SERVICENOW_PASSWORD"); envPassword != "" {
+		config.ServiceNow.Password = envPassword
+	}
+
+	// Check if the encryption key is in environment variable
+	if envKey := os.Getenv("GIT_MONITOR_ENCRYPTION_KEY"); envKey != "" {
+		config.Encryption.Key = envKey
+	}
+
+	// Check if the event sink credentials are in environment variables
+	if envKey := os.Getenv("GIT_MONITOR_EVENTGRID_KEY"); envKey != "" {
+		config.Events.EventGrid.Key = envKey
+	}
+	if envToken := os.Getenv("GIT_MONITOR_PUBSUB_TOKEN"); envToken != "" {
+		config.Events.PubSub.Token = envToken
+	}
+
+	// Check if the ops webhook is in environment variable
+	if envWebhook := os.Getenv("GIT_MONITOR_OPS_WEBHOOK"); envWebhook != "" {
+		config.Notifications.Ops.Webhook = envWebhook
+	}
+
+	// Check if the state store is in environment variable, as its URL can hold a password
+	if envPath := os.Getenv("GIT_MONITOR_STATE_PATH"); envPath != "" {
+		config.State.Path = envPath
+	}
+
+	// Check if the signing key password is in environment variable
+	if envPassword := os.Getenv("COSIGN_PASSWORD"); envPassword != "" {
+		config.Evidence.KeyPassword = envPassword
+	}
+
+	// Check if the Slack app secrets are in environment variables
+	if envSecret := os.Getenv("SLACK_SIGNING_SECRET"); envSecret != "" {
+		config.Server.Slack.SigningSecret = envSecret
+	}
+	if envToken := os.Getenv("SLACK_BOT_TOKEN"); envToken != "" {
+		config.Server.Slack.BotToken = envToken
+	}
+
+	// Check if the GitHub webhook secret is in environment variable
+	if envSecret := os.Getenv("GITHUB_WEBHOOK_SECRET"); envSecret != "" {
+		config.Server.GitHubWebhook.Secret = envSecret
+	}
+
+	// Check if account tokens are in environment variables
+	for i, account := range config.Accounts {
+		if account.TokenEnv == "" {
+			continue
+		}
+		if envToken := os.Getenv(account.TokenEnv); envToken != "" {
+			config.Accounts[i].Token = envToken
+		}
+	}
+
+	return config, nil
+}
+
+// decodeAccountMonitors decodes the monitors of each account on top of the default monitors
+// Decoding into the Accounts slice directly would leave settings missing from the file zero
+func decodeAccountMonitors(filePath string, config *Config) error {
+	var raw struct {
+		Accounts []struct {
+			Monitors toml.Primitive `toml:"monitors"`
+		} `toml:"accounts"`
+	}
+
+	meta, err := toml.DecodeFile(filePath, &raw)
+	if err != nil {
+		return fmt.Errorf("error decoding config file: %v", err)
+	}
+
+	for i := range config.Accounts {
+		monitors := defaultMonitors()
+		if err := meta.PrimitiveDecode(raw.Accounts[i].Monitors, &monitors); err != nil {
+			return fmt.Errorf("error decoding monitors of account %s: %v", config.Accounts[i].Name, err)
+		}
+		config.Accounts[i].Monitors = monitors
+	}
+
+	return nil
+}
+
+// AccountConfigs returns the configuration of each account scanned in a run
+// Without accounts, the configuration itself is the only account
+func (c *Config) AccountConfigs() []*Config {
+	if len(c.Accounts) == 0 {
+		return []*Config{c}
+	}
+
+	configs := make([]*Config, 0, len(c.Accounts))
+	for _, a := range c.Accounts {
+		account := *c
+		account.Accounts = nil
+		account.Account = a.Name
+		account.GitHub.Token = a.Token
+		account.Monitors = a.Monitors
+		configs = append(configs, &account)
+	}
+
+	return configs
+}
+
+// Location returns the configured reporting timezone
+// It returns nil when no timezone is configured or it cannot be loaded
+func (c *Config) Location() *time.Location {
+	if c.Timezone == "" {
+		return nil
+	}
+
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil
+	}
+
+	return loc
+}
+
+// WindowLocation returns the location check windows spanning whole days are aligned to, see common.WindowStart
+// Scans of a few repositories requested on demand check events up to now, so their windows are not aligned
+func (c *Config) WindowLocation() *time.Location {
+	if c.WindowsEndNow {
+		return nil
+	}
+	return c.Location()
+}
+
+// Secrets returns the tokens, passwords, keys and webhook URLs of the configuration, to mask them in output
+// Webhook URLs are secrets, anyone knowing one can post to its channel
+func (c *Config) Secrets() []string {
+	secrets := []string{
+		c.GitHub.Token,
+		c.Redaction.Salt,
+		c.Scoring.PageWebhook,
+		c.SLA.Webhook,
+		c.Notifications.Ops.Webhook,
+		c.Evidence.KeyPassword,
+		c.Projects.Token,
+		c.ServiceNow.Password,
+		c.Events.EventGrid.Key,
+		c.Events.PubSub.Token,
+		c.Encryption.Key,
+		c.Heartbeat.URL,
+		c.Heartbeat.StartURL,
+		c.Heartbeat.FailURL,
+		c.Server.AuthToken,
+		c.Server.Slack.SigningSecret,
+		c.Server.Slack.BotToken,
+		c.Server.GitHubWebhook.Secret,
+	}
+	for _, account := range c.Accounts {
+		secrets = append(secrets, account.Token)
+	}
+	for _, webhook := range c.Ownership.Webhooks {
+		secrets = append(secrets, webhook)
+	}
+	for _, policy := range c.Escalation.Policies {
+		secrets = append(secrets, policy.Webhook)
+	}
+	return secrets
+}
+
+// ScopeToRepositories returns a copy of the configuration in which every monitor only checks the given repositories
+// that are among its own targets, see InScope. Organization-wide settings are dropped, and monitors left without
+// repositories are disabled. The repository visibility monitor only checks organizations, so it is disabled in the
+// scoped configuration. Check windows of the scoped configuration end now, see WindowLocation
+func (c *Config) ScopeToRepositories(repositories []string) *Config {
+	scoped := *c
+	scoped.WindowsEndNow = true
+	scopeMonitors(&scoped.Monitors, repositories, c.RepoFilters.Exclusions)
+
+	scoped.Accounts = append([]AccountConfig(nil), c.Accounts...)
+	for i := range scoped.Accounts {
+		scopeMonitors(&scoped.Accounts[i].Monitors, repositories, c.RepoFilters.Exclusions)
+	}
+
+	return &scoped
+}
+
+// InScope reports whether a repository, "owner/repo", is a target of an enabled monitor of any account: listed in
+// its repositories or owned by one of its organizations, and not excluded by repo_filters or, for the PR checker,
+// excluded_repositories. On-demand scans are limited to these, so they cannot reach other repositories the token can read
+func (c *Config) InScope(repository string) bool {
+	if containsFold(c.RepoFilters.Exclusions, repository) {
+		return false
+	}
+	for _, account := range c.AccountConfigs() {
+		for _, t := range monitorTargets(&account.Monitors) {
+			if t.covers(repository) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// targets are the organizations and repositories a monitor checks
+type targets struct {
+	enabled       *bool
+	organizations *[]string
+	repositories  *[]string
+	excluded      []string // Repositories of the organizations the monitor leaves out
+}
+
+// covers reports whether the monitor is enabled and checks a repository
+func (t targets) covers(repository string) bool {
+	if !*t.enabled || containsFold(t.excluded, repository) {
+		return false
+	}
+	if containsFold(*t.repositories, repository) {
+		return true
+	}
+	owner, _, _ := strings.Cut(repository, "/")
+	return containsFold(*t.organizations, owner)
+}
+
+// monitorTargets returns the targets of the monitors checking repositories
+// The PR checker's are its organization, or its specific repositories, and repositories of the authenticated user
+// are not known without listing them, so they are not among its targets
+func monitorTargets(monitors *MonitorsConfig) []targets {
+	pr := &monitors.PRChecker
+	prOrgs, prRepos := []string{}, []string{}
+	if pr.RepoVisibility == "specific" {
+		prRepos = pr.SpecificRepositories
+	} else if pr.Organization != "" {
+		prOrgs = []string{pr.Organization}
+	}
+
+	return []targets{
+		{&pr.Enabled, &prOrgs, &prRepos, pr.ExcludedRepositories},
+		{&monitors.Rulesets.Enabled, &monitors.Rulesets.Organizations, &monitors.Rulesets.Repositories, nil},
+		{&monitors.CodeScanning.Enabled, &monitors.CodeScanning.Organizations, &monitors.CodeScanning.Repositories, nil},
+		{&monitors.Dependabot.Enabled, &monitors.Dependabot.Organizations, &monitors.Dependabot.Repositories, nil},
+		{&monitors.PushProtection.Enabled, &monitors.PushProtection.Organizations, &monitors.PushProtection.Repositories, nil},
+		{&monitors.WorkflowPermissions.Enabled, &monitors.WorkflowPermissions.Organizations, &monitors.WorkflowPermissions.Repositories, nil},
+		{&monitors.Environments.Enabled, &monitors.Environments.Organizations, &monitors.Environments.Repositories, nil},
+		{&monitors.ForkRuns.Enabled, &monitors.ForkRuns.Organizations, &monitors.ForkRuns.Repositories, nil},
+		{&monitors.IssueHygiene.Enabled, &monitors.IssueHygiene.Organizations, &monitors.IssueHygiene.Repositories, nil},
+		{&monitors.OpenPRChecker.Enabled, &monitors.OpenPRChecker.Organizations, &monitors.OpenPRChecker.Repositories, nil},
+		{&monitors.Codeowners.Enabled, &monitors.Codeowners.Organizations, &monitors.Codeowners.Repositories, nil},
+		{&monitors.DormantAccess.Enabled, &monitors.DormantAccess.Organizations, &monitors.DormantAccess.Repositories, nil},
+		{&monitors.DormantRepos.Enabled, &monitors.DormantRepos.Organizations, &monitors.DormantRepos.Repositories, nil},
+	}
+}
+
+// containsFold reports whether values contain value, compared case-insensitively as GitHub names are
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeMonitors makes every monitor only check the given repositories among its targets, leaving out exclusions
+func scopeMonitors(monitors *MonitorsConfig, repositories, exclusions []string) {
+	list := monitorTargets(monitors)
+	for _, t := range list {
+		scoped := []string{}
+		for _, repo := range repositories {
+			if t.covers(repo) && !containsFold(exclusions, repo) {
+				scoped = append(scoped, repo)
+			}
+		}
+		*t.organizations = []string{}
+		*t.repositories = scoped
+		*t.enabled = *t.enabled && len(scoped) > 0
+	}
+
+	monitors.PRChecker.RepoVisibility = "specific"
+	monitors.PRChecker.Organization = ""
+	monitors.PRChecker.TeamSlug = ""
+	monitors.PRChecker.Discovery = ""
+	monitors.PRChecker.SpecificRepositories = *list[0].repositories // The PR checker's targets come first
+
+	monitors.RepoVisibility.Enabled = false
+	monitors.RepoCreation.Enabled = false
+	monitors.AdminEnforcement.Enabled = false
+	monitors.GHAS.Enabled = false
+	monitors.TokenHealth.Enabled = false
+}
+
+// ticketKeyPattern matches issue tracker project keys, e.g. "ABC" of "ABC-123"
+var ticketKeyPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
+// validTitlePresets are the title conventions merged PR titles can be checked against
+var validTitlePresets = map[string]bool{"conventional_commits": true}
+
+// validMergeMethods are the ways GitHub merges pull requests
+var validMergeMethods = map[string]bool{"merge": true, "squash": true, "rebase": true}
+
+// Validate ensures the configuration is valid
+func (c *Config) Validate() error {
+	// The user agent is sent as a header, which cannot hold control characters such as line breaks
+	if strings.IndexFunc(c.GitHub.UserAgent, unicode.IsControl) >= 0 {
+		return fmt.Errorf("invalid GitHub user_agent %q: must not contain control characters", c.GitHub.UserAgent)
+	}
+
+	if len(c.Accounts) > 0 {
+		return c.validateAccounts()
+	}
+
+	if c.GitHub.Token == "" {
+		return fmt.Errorf("GitHub token is required. Set it in the config file or GITHUB_TOKEN environment variable")
+	}
+
+	if c.Timezone != "" {
+		if _, err := time.LoadLocation(c.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone: %s", c.Timezone)
+		}
+	}
+
+	if c.Monitors.PRChecker.Enabled {
+		// Validate repo visibility setting
+		validVisibilities := map[string]bool{
+			"all":          true,
+			"public-only":  true,
+			"private-only": true,
+			"specific":     true,
+		}
+
+		if !validVisibilities[c.Monitors.PRChecker.RepoVisibility] {
+			return fmt.Errorf("invalid repository visibility: %s. Must be one of: all, public-only, private-only, specific",
+				c.Monitors.PRChecker.RepoVisibility)
+		}
+
+		// Only check repositories list if visibility is set to "specific"
+		if c.Monitors.PRChecker.RepoVisibility == "specific" && len(c.Monitors.PRChecker.SpecificRepositories) == 0 {
+			return fmt.Errorf("at least one repository must be specified for PR checker when repo_visibility is 'specific'")
+		}
+
+		// If organization is specified with "specific" visibility, warn but continue
+		if c.Monitors.PRChecker.RepoVisibility == "specific" && c.Monitors.PRChecker.Organization != "" {
+			log.Printf("WARNING: Organization '%s' is specified but repo_visibility is 'specific'. The organization setting will be ignored.",
+				c.Monitors.PRChecker.Organization)
+		}
+
+		// Teams belong to an organization, so the team's repositories are looked up in it
+		if c.Monitors.PRChecker.TeamSlug != "" && c.Monitors.PRChecker.RepoVisibility != "specific" && c.Monitors.PRChecker.Organization == "" {
+			return fmt.Errorf("organization must be specified for PR checker when team_slug is set")
+		}
+
+		// The search API finds the merged PRs of an organization, not of listed repositories
+		switch c.Monitors.PRChecker.Discovery {
+		case "", "list":
+		case "search":
+			if c.Monitors.PRChecker.RepoVisibility == "specific" || c.Monitors.PRChecker.Organization == "" {
+				return fmt.Errorf("organization and a repo_visibility other than 'specific' must be specified for PR checker when discovery is 'search'")
+			}
+		default:
+			return fmt.Errorf("invalid PR checker discovery: %s. Must be one of: list, search", c.Monitors.PRChecker.Discovery)
+		}
+	}
+
+	if c.Monitors.PRChecker.TimeWindow.Duration <= 0 {
+		return fmt.Errorf("time window must be greater than 0")
+	}
+
+	if c.Monitors.PRChecker.MinRepoAge.Duration < 0 || c.Monitors.PRChecker.SkipInactiveDays < 0 {
+		return fmt.Errorf("min repo age and skip inactive days for PR checker must not be negative")
+	}
+
+	if c.Monitors.PRChecker.RequiredApprovals < 0 {
+		return fmt.Errorf("required approvals for PR checker must not be negative")
+	}
+
+	if c.Monitors.PRChecker.MinReviewTime.Duration < 0 {
+		return fmt.Errorf("min review time for PR checker must not be negative")
+	}
+
+	if c.Monitors.PRChecker.DismissalWindow.Duration < 0 {
+		return fmt.Errorf("dismissal window for PR checker must not be negative")
+	}
+
+	if c.Monitors.PRChecker.OutOfWindowThreshold < 0 {
+		return fmt.Errorf("out of window threshold for PR checker must not be negative")
+	}
+
+	for _, pattern := range c.Monitors.PRChecker.RequiredSections {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid required section pattern %q for PR checker: %v", pattern, err)
+		}
+	}
+
+	reviewerTeams := append([]string{}, c.Monitors.PRChecker.RequiredReviewerTeams...)
+	for repo, teams := range c.Monitors.PRChecker.RepoReviewerTeams {
+		if _, _, ok := strings.Cut(repo, "/"); !ok {
+			return fmt.Errorf("invalid repository in PR checker repo_reviewer_teams: %s. Must be 'owner/repo'", repo)
+		}
+		reviewerTeams = append(reviewerTeams, teams...)
+	}
+	for _, team := range reviewerTeams {
+		if org, slug, ok := strings.Cut(team, "/"); !ok || org == "" || slug == "" {
+			return fmt.Errorf("invalid reviewer team for PR checker: %s. Must be 'org/team'", team)
+		}
+	}
+
+	ticketKeys := append([]string{}, c.Monitors.PRChecker.TicketKeys...)
+	for repo, keys := range c.Monitors.PRChecker.RepoTicketKeys {
+		if _, _, ok := strings.Cut(repo, "/"); !ok {
+			return fmt.Errorf("invalid repository in PR checker repo_ticket_keys: %s. Must be 'owner/repo'", repo)
+		}
+		ticketKeys = append(ticketKeys, keys...)
+	}
+	for _, key := range ticketKeys {
+		if !ticketKeyPattern.MatchString(key) {
+			return fmt.Errorf("invalid ticket key for PR checker: %q. Must be letters and digits starting with a letter", key)
+		}
+	}
+
+	for repo, globs := range c.Monitors.PRChecker.RepoPaths {
+		if _, _, ok := strings.Cut(repo, "/"); !ok {
+			return fmt.Errorf("invalid repository in PR checker repo_paths: %s. Must be 'owner/repo'", repo)
+		}
+		// An empty scope would silently check every merged PR of the repository
+		if len(globs) == 0 {
+			return fmt.Errorf("no paths for repository %s in PR checker repo_paths", repo)
+		}
+		for _, glob := range globs {
+			if strings.TrimSpace(glob) == "" {
+				return fmt.Errorf("empty path for repository %s in PR checker repo_paths", repo)
+			}
+		}
+	}
+
+	for _, branch := range c.Monitors.PRChecker.BaseBranches {
+		if strings.TrimSpace(branch) == "" {
+			return fmt.Errorf("empty branch in PR checker base_branches")
+		}
+	}
+
+	if _, err := regexp.Compile(c.Monitors.PRChecker.TitlePattern); err != nil {
+		return fmt.Errorf("invalid title pattern %q for PR checker: %v", c.Monitors.PRChecker.TitlePattern, err)
+	}
+	if preset := c.Monitors.PRChecker.TitlePreset; preset != "" {
+		if !validTitlePresets[preset] {
+			return fmt.Errorf("invalid title preset for PR checker: %q. Must be: conventional_commits", preset)
+		}
+		if c.Monitors.PRChecker.TitlePattern != "" {
+			return fmt.Errorf("title_preset and title_pattern for PR checker are exclusive, set one of them")
+		}
+	}
+
+	if hours := c.Monitors.PRChecker.MergeHours; hours.Enabled {
+		if err := validateHours("PR checker merge hours", hours.Days, hours.Start, hours.End); err != nil {
+			return err
+		}
+		if hours.Timezone != "" {
+			if _, err := time.LoadLocation(hours.Timezone); err != nil {
+				return fmt.Errorf("invalid timezone in PR checker merge hours: %s", hours.Timezone)
+			}
+		}
+	}
+
+	for _, method := range c.Monitors.PRChecker.AllowedMergeMethods {
+		if !validMergeMethods[method] {
+			return fmt.Errorf("invalid merge method for PR checker: %q. Must be one of: merge, squash, rebase", method)
+		}
+	}
+
+	overridden := make(map[string]bool)
+	for _, override := range c.Monitors.PRChecker.RepoOverrides {
+		if owner, repo, ok := strings.Cut(override.Repository, "/"); !ok || owner == "" || repo == "" {
+			return fmt.Errorf("invalid repository in PR checker repo_overrides: %q. Must be 'owner/repo'", override.Repository)
+		}
+		// Two overrides of a repository would leave which one applies to the order of the file
+		if overridden[strings.ToLower(override.Repository)] {
+			return fmt.Errorf("repository %s is overridden more than once in PR checker repo_overrides", override.Repository)
+		}
+		overridden[strings.ToLower(override.Repository)] = true
+		if override.TimeWindow.Duration < 0 {
+			return fmt.Errorf("time window of repository %s in PR checker repo_overrides must not be negative", override.Repository)
+		}
+		if override.RequiredApprovals != nil && *override.RequiredApprovals < 0 {
+			return fmt.Errorf("required approvals of repository %s in PR checker repo_overrides must not be negative", override.Repository)
+		}
+		for _, author := range override.ExcludedAuthors {
+			if strings.TrimSpace(author) == "" {
+				return fmt.Errorf("empty author for repository %s in PR checker repo_overrides", override.Repository)
+			}
+		}
+	}
+
+	if c.Monitors.RepoVisibility.Enabled {
+		// Validate repo visibility setting
+		validVisibilities := map[string]bool{
+			"all":          true,
+			"public-only":  true,
+			"private-only": true,
+			"specific":     true,
+		}
+
+		if !validVisibilities[c.Monitors.RepoVisibility.RepoVisibility] {
+			return fmt.Errorf("invalid repository visibility for repo_visibility monitor: %s. Must be one of: all, public-only, private-only, specific",
+				c.Monitors.RepoVisibility.RepoVisibility)
+		}
+
+		// If using "specific" visibility, require at least one organization
+		if c.Monitors.RepoVisibility.RepoVisibility == "specific" && len(c.Monitors.RepoVisibility.Organizations) == 0 {
+			return fmt.Errorf("at least one organization must be specified for repo_visibility monitor when repo_visibility is 'specific'")
+		}
+
+		// All visibility options require at least one organization
+		if len(c.Monitors.RepoVisibility.Organizations) == 0 {
+			return fmt.Errorf("at least one organization must be specified for repo_visibility monitor")
+		}
+
+		if c.Monitors.RepoVisibility.CheckWindow.Duration <= 0 {
+			return fmt.Errorf("check window for repo visibility must be greater than 0")
+		}
+	}
+
+	if c.Monitors.Rulesets.Enabled {
+		if len(c.Monitors.Rulesets.Organizations) == 0 && len(c.Monitors.Rulesets.Repositories) == 0 {
+			return fmt.Errorf("at least one organization or repository must be specified for rulesets monitor")
+		}
+
+		if len(c.Monitors.Rulesets.Desired) == 0 {
+			return fmt.Errorf("at least one desired ruleset must be declared for rulesets monitor")
+		}
+
+		validScopes := map[string]bool{
+			"organization": true,
+			"repository":   true,
+		}
+		validEnforcements := map[string]bool{
+			"":         true, // Defaults to "active"
+			"active":   true,
+			"evaluate": true,
+			"disabled": true,
+		}
+
+		for _, desired := range c.Monitors.Rulesets.Desired {
+			if desired.Name == "" {
+				return fmt.Errorf("desired ruleset name cannot be empty")
+			}
+			if !validScopes[desired.Scope] {
+				return fmt.Errorf("invalid scope for desired ruleset %s: %s. Must be one of: organization, repository",
+					desired.Name, desired.Scope)
+			}
+			if !validEnforcements[desired.Enforcement] {
+				return fmt.Errorf("invalid enforcement for desired ruleset %s: %s. Must be one of: active, evaluate, disabled",
+					desired.Name, desired.Enforcement)
+			}
+		}
+	}
+
+	if c.Monitors.CodeScanning.Enabled {
+		if len(c.Monitors.CodeScanning.Organizations) == 0 && len(c.Monitors.CodeScanning.Repositories) == 0 {
+			return fmt.Errorf("at least one organization or repository must be specified for code_scanning_dismissals monitor")
+		}
+
+		if c.Monitors.CodeScanning.CheckWindow.Duration <= 0 {
+			return fmt.Errorf("check window for code scanning dismissals must be greater than 0")
+		}
+	}
+
+	if c.Monitors.Dependabot.Enabled {
+		if len(c.Monitors.Dependabot.Organizations) == 0 && len(c.Monitors.Dependabot.Repositories) == 0 {
+			return fmt.Errorf("at least one organization or repository must be specified for dependabot_dismissals monitor")
+		}
+
+		validSeverities := map[string]bool{
+			"":         true, // Report all severities
+			"low":      true,
+			"medium":   true,
+			"high":     true,
+			"critical": true,
+		}
+
+		if !validSeverities[c.Monitors.Dependabot.MinimumSeverity] {
+			return fmt.Errorf("invalid minimum severity for dependabot_dismissals monitor: %s. Must be one of: low, medium, high, critical",
+				c.Monitors.Dependabot.MinimumSeverity)
+		}
+
+		if c.Monitors.Dependabot.CheckWindow.Duration <= 0 {
+			return fmt.Errorf("check window for dependabot dismissals must be greater than 0")
+		}
+	}
+
+	if c.Monitors.PushProtection.Enabled {
+		if len(c.Monitors.PushProtection.Organizations) == 0 && len(c.Monitors.PushProtection.Repositories) == 0 {
+			return fmt.Errorf("at least one organization or repository must be specified for push_protection_bypasses monitor")
+		}
+
+		if c.Monitors.PushProtection.CheckWindow.Duration <= 0 {
+			return fmt.Errorf("check window for push protection bypasses must be greater than 0")
+		}
+	}
+
+	if c.Monitors.WorkflowPermissions.Enabled {
+		if len(c.Monitors.WorkflowPermissions.Organizations) == 0 && len(c.Monitors.WorkflowPermissions.Repositories) == 0 {
+			return fmt.Errorf("at least one organization or repository must be specified for workflow_permissions monitor")
+		}
+	}
+
+	if c.Monitors.Environments.Enabled {
+		if len(c.Monitors.Environments.Organizations) == 0 && len(c.Monitors.Environments.Repositories) == 0 {
+			return fmt.Errorf("at least one organization or repository must be specified for environment_protection monitor")
+		}
+
+		if len(c.Monitors.Environments.Environments) == 0 {
+			return fmt.Errorf("at least one environment must be specified for environment_protection monitor")
+		}
+
+		if c.Monitors.Environments.CheckWindow.Duration <= 0 {
+			return fmt.Errorf("check window for environment protection must be greater than 0")
+		}
+
+		if c.Monitors.Environments.MinWaitTimer < 0 {
+			return fmt.Errorf("minimum wait timer for environment protection must not be negative")
+		}
+	}
+
+	if c.Monitors.ForkRuns.Enabled {
+		if len(c.Monitors.ForkRuns.Organizations) == 0 && len(c.Monitors.ForkRuns.Repositories) == 0 {
+			return fmt.Errorf("at least one organization or repository must be specified for fork_workflow_approvals monitor")
+		}
+
+		if c.Monitors.ForkRuns.CheckWindow.Duration <= 0 {
+			return fmt.Errorf("check window for fork workflow approvals must be greater than 0")
+		}
+	}
+
+	if c.Monitors.RepoCreation.Enabled {
+		if len(c.Monitors.RepoCreation.Organizations) == 0 {
+			return fmt.Errorf("at least one organization must be specified for repo_creation monitor")
+		}
+
+		if c.Monitors.RepoCreation.CheckWindow.Duration <= 0 {
+			return fmt.Errorf("check window for repository creation must be greater than 0")
+		}
+
+		for _, role := range c.Monitors.RepoCreation.ApprovedRoles {
+			if role != "admin" && role != "member" {
+				return fmt.Errorf("invalid approved role for repo_creation monitor: %s. Must be one of: admin, member", role)
+			}
+		}
+
+		for _, template := range c.Monitors.RepoCreation.ApprovedTemplates {
+			if _, _, ok := strings.Cut(template, "/"); !ok {
+				return fmt.Errorf("invalid approved template for repo_creation monitor: %s. Expected 'owner/repo'", template)
+			}
+		}
+	}
+
+	if c.Monitors.IssueHygiene.Enabled {
+		if len(c.Monitors.IssueHygiene.Organizations) == 0 && len(c.Monitors.IssueHygiene.Repositories) == 0 {
+			return fmt.Errorf("at least one organization or repository must be specified for issue_hygiene monitor")
+		}
+
+		if c.Monitors.IssueHygiene.TriageSLA.Duration <= 0 {
+			return fmt.Errorf("triage SLA for issue hygiene must be greater than 0")
+		}
+	}
+
+	if c.Monitors.AdminEnforcement.Enabled {
+		if len(c.Monitors.AdminEnforcement.Organizations) == 0 {
+			return fmt.Errorf("at least one organization must be specified for admin_enforcement monitor")
+		}
+
+		if c.Monitors.AdminEnforcement.CheckWindow.Duration <= 0 {
+			return fmt.Errorf("check window for admin enforcement must be greater than 0")
+		}
+	}
+
+	if c.Monitors.Codeowners.Enabled {
+		if len(c.Monitors.Codeowners.Organizations) == 0 && len(c.Monitors.Codeowners.Repositories) == 0 {
+			return fmt.Errorf("at least one organization or repository must be specified for codeowners_coverage monitor")
+		}
+
+		if c.Monitors.Codeowners.MinCoverage <= 0 || c.Monitors.Codeowners.MinCoverage > 100 {
+			return fmt.Errorf("min coverage for codeowners_coverage monitor must be above 0 and at most 100")
+		}
+
+		if c.Monitors.Codeowners.SampleSize <= 0 {
+			return fmt.Errorf("sample size for codeowners_coverage monitor must be greater than 0")
+		}
+	}
+
+	if c.Monitors.GHAS.Enabled {
+		if len(c.Monitors.GHAS.Organizations) == 0 {
+			return fmt.Errorf("at least one organization must be specified for ghas_utilization monitor")
+		}
+	}
+
+	if c.Monitors.TokenHealth.Enabled {
+		if c.Monitors.TokenHealth.ExpiryWarningDays < 0 {
+			return fmt.Errorf("expiry warning days for token_health monitor must not be negative")
+		}
+
+		if c.Monitors.TokenHealth.MinRateLimitHeadroom < 0 || c.Monitors.TokenHealth.MinRateLimitHeadroom > 100 {
+			return fmt.Errorf("min rate limit headroom for token_health monitor must be between 0 and 100")
+		}
+	}
+
+	if c.Monitors.OpenPRChecker.Enabled {
+		if len(c.Monitors.OpenPRChecker.Organizations) == 0 && len(c.Monitors.OpenPRChecker.Repositories) == 0 {
+			return fmt.Errorf("at least one organization or repository must be specified for open_pr_checker monitor")
+		}
+
+		if c.Monitors.OpenPRChecker.MinAge.Duration <= 0 {
+			return fmt.Errorf("min age for open_pr_checker monitor must be greater than 0")
+		}
+	}
+
+	if c.Monitors.DormantAccess.Enabled {
+		if len(c.Monitors.DormantAccess.Organizations) == 0 && len(c.Monitors.DormantAccess.Repositories) == 0 {
+			return fmt.Errorf("at least one organization or repository must be specified for dormant_accounts monitor")
+		}
+
+		if c.Monitors.DormantAccess.InactiveDays <= 0 {
+			return fmt.Errorf("inactive days for dormant accounts must be greater than 0")
+		}
+	}
+
+	if c.Monitors.DormantRepos.Enabled {
+		if len(c.Monitors.DormantRepos.Organizations) == 0 && len(c.Monitors.DormantRepos.Repositories) == 0 {
+			return fmt.Errorf("at least one organization or repository must be specified for dormant_repositories monitor")
+		}
+
+		if c.Monitors.DormantRepos.InactiveDays <= 0 {
+			return fmt.Errorf("inactive days for dormant repositories must be greater than 0")
+		}
+	}
+
+	if c.State.Enabled {
+		if c.State.Path == "" {
+			return fmt.Errorf("path must be specified when state is enabled")
+		}
+
+		if scheme, _, ok := strings.Cut(c.State.Path, "://"); ok {
+			switch scheme {
+			case "bbolt", "sqlite", "postgres", "postgresql", "s3":
+			default:
+				return fmt.Errorf("invalid state store: %s. Must be a file path or a URL starting with bbolt://, sqlite://, postgres:// or s3://", scheme)
+			}
+		}
+	}
+
+	if c.Suppressions.InRepo && c.Suppressions.Path == "" {
+		return fmt.Errorf("path must be specified when in-repo suppressions are enabled")
+	}
+
+	if c.Suppressions.ExpiringSoonDays < 0 {
+		return fmt.Errorf("expiring soon days for suppressions must not be negative")
+	}
+
+	if c.RepoPolicy.Enabled {
+		if c.RepoPolicy.Path == "" {
+			return fmt.Errorf("path must be specified when repository policies are enabled")
+		}
+
+		if c.RepoPolicy.MaxDormantInactiveDays < 0 {
+			return fmt.Errorf("max dormant inactive days for repository policies must not be negative")
+		}
+
+		if c.RepoPolicy.MinReviewTimeFloor.Duration < 0 {
+			return fmt.Errorf("min review time floor for repository policies must not be negative")
+		}
+
+		if c.RepoPolicy.MaxRequiredApprovals < 0 {
+			return fmt.Errorf("max required approvals for repository policies must not be negative")
+		}
+
+		if c.RepoPolicy.MinSLADays < 0 {
+			return fmt.Errorf("min SLA days for repository policies must not be negative")
+		}
+	}
+
+	// Hashes without a salt are matched by hashing known repository names, and the aliases file cannot be known
+	// to name every repository the monitors find, so the salt is required whatever the aliases
+	if c.Redaction.Enabled && strings.TrimSpace(c.Redaction.Salt) == "" {
+		return fmt.Errorf("salt is required when redaction is enabled. Set it in the config file or GIT_MONITOR_REDACTION_SALT environment variable")
+	}
+
+	if c.Scoring.Enabled {
+		if err := c.validateScoring(); err != nil {
+			return err
+		}
+	}
+
+	for monitor, controls := range c.Compliance.Controls {
+		if !monitorKeys[monitor] {
+			return fmt.Errorf("invalid monitor in compliance controls: %s", monitor)
+		}
+		for _, control := range controls {
+			if strings.TrimSpace(control) == "" {
+				return fmt.Errorf("compliance control IDs for %s must not be empty", monitor)
+			}
+		}
+	}
+
+	for key, runbook := range c.Remediation.Runbooks {
+		monitor, _, _ := strings.Cut(key, ".")
+		if !monitorKeys[monitor] {
+			return fmt.Errorf("invalid monitor in remediation runbooks: %s", key)
+		}
+		u, err := url.Parse(runbook)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("remediation runbook of %s must be an HTTP(S) URL", key)
+		}
+	}
+
+	if c.Checkpoint.Enabled && c.Checkpoint.Path == "" {
+		return fmt.Errorf("path must be specified when checkpoints are enabled")
+	}
+
+	if c.Membership.Enabled {
+		if c.Membership.Path == "" {
+			return fmt.Errorf("path must be specified when the membership cache is enabled")
+		}
+		if c.Membership.TTL.Duration <= 0 {
+			return fmt.Errorf("membership cache ttl must be positive")
+		}
+	}
+
+	if c.Evidence.Enabled {
+		if err := c.validateEvidence(); err != nil {
+			return err
+		}
+	}
+
+	if c.Projects.Enabled {
+		if err := c.validateProjects(); err != nil {
+			return err
+		}
+	}
+
+	if c.ServiceNow.Enabled {
+		if err := c.validateServiceNow(); err != nil {
+			return err
+		}
+	}
+
+	if c.Events.EventGrid.Enabled || c.Events.PubSub.Enabled {
+		if err := c.validateEvents(); err != nil {
+			return err
+		}
+	}
+
+	if c.Encryption.Enabled {
+		if err := c.validateEncryption(); err != nil {
+			return err
+		}
+	}
+
+	if c.Archive.Enabled {
+		if err := c.validateArchive(); err != nil {
+			return err
+		}
+	}
+
+	if c.Ownership.Enabled {
+		if err := c.validateOwnership(); err != nil {
+			return err
+		}
+	}
+
+	if c.Escalation.Enabled {
+		if err := c.validateEscalation(); err != nil {
+			return err
+		}
+	}
+
+	if c.SLA.Enabled {
+		if err := c.validateSLA(); err != nil {
+			return err
+		}
+	}
+
+	if c.Notifications.Schedule.Enabled {
+		if err := c.validateSchedule(); err != nil {
+			return err
+		}
+	}
+
+	if err := c.validateHeartbeat(); err != nil {
+		return err
+	}
+
+	if f := c.RepoFilters; f.MinSizeKB < 0 || f.MaxSizeKB < 0 || (f.MaxSizeKB > 0 && f.MinSizeKB > f.MaxSizeKB) {
+		return fmt.Errorf("repo_filters min_size_kb and max_size_kb must not be negative, and min_size_kb must not exceed max_size_kb")
+	}
+
+	if c.Notifications.OnChangeOnly && !c.State.Enabled {
+		return fmt.Errorf("notifications on_change_only requires state to be enabled, the notified findings are kept in the state")
+	}
+
+	if retry := c.Notifications.Retry; retry.Enabled {
+		if !c.State.Enabled {
+			return fmt.Errorf("notification retries require state to be enabled, undelivered notifications are kept in the state")
+		}
+		if retry.MaxAge.Duration <= 0 {
+			return fmt.Errorf("notification retry max_age must be positive")
+		}
+		if retry.Interval.Duration <= 0 {
+			return fmt.Errorf("notification retry interval must be positive")
+		}
+	}
+
+	if err := c.validatePagination(); err != nil {
+		return err
+	}
+
+	return c.validateOutputs()
 }
 
-// GitHubConfig contains GitHub API configuration
-type GitHubConfig struct {
-	Token string `toml:"token"`
+// validateProjects ensures the board findings are synced to is identified and can be reached
+func (c *Config) validateProjects() error {
+	p := c.Projects
+	if p.Owner == "" || p.Number <= 0 {
+		return fmt.Errorf("projects owner and number must be specified")
+	}
+	if p.StatusField == "" || p.OpenStatus == "" || p.DoneStatus == "" {
+		return fmt.Errorf("projects status_field, open_status and done_status must not be empty")
+	}
+	if strings.EqualFold(p.OpenStatus, p.DoneStatus) {
+		return fmt.Errorf("projects open_status and done_status must differ")
+	}
+	if p.GraphQLURL != "" {
+		u, err := url.Parse(p.GraphQLURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("projects graphql_url must be an HTTP(S) URL")
+		}
+	}
+	return nil
 }
 
-// MonitorsConfig contains configuration for all monitors
-type MonitorsConfig struct {
-	PRChecker      PRCheckerConfig      `toml:"pr_checker"`
-	RepoVisibility RepoVisibilityConfig `toml:"repo_visibility"`
+// validateServiceNow ensures records can be opened on the instance, and only for escalated severities
+func (c *Config) validateServiceNow() error {
+	sn := c.ServiceNow
+	u, err := url.Parse(sn.InstanceURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("servicenow instance_url must be an HTTP(S) URL")
+	}
+	if sn.Table == "" {
+		return fmt.Errorf("servicenow table must not be empty")
+	}
+	if sn.Username == "" || sn.Password == "" {
+		return fmt.Errorf("servicenow username and password must be specified. Set the password in the config file or GIT_MONITOR_SERVICENOW_PASSWORD environment variable")
+	}
+	if !validEscalationSeverities[sn.MinSeverity] {
+		return fmt.Errorf("invalid servicenow min_severity: %s. Must be one of: medium, high, critical", sn.MinSeverity)
+	}
+	return nil
 }
 
-// PRCheckerConfig contains configuration for the PR checker
-type PRCheckerConfig struct {
-	Enabled              bool     `toml:"enabled"`
-	RepoVisibility       string   `toml:"repo_visibility"`       // Options: "all", "public-only", "private-only", "specific"
-	Organization         string   `toml:"organization"`          // GitHub organization name (optional)
-	SpecificRepositories []string `toml:"specific_repositories"` // Only used when RepoVisibility is "specific"
-	ExcludedRepositories []string `toml:"excluded_repositories"` // Used with "all", "public-only", "private-only" to exclude specific repos
-	TimeWindow           int      `toml:"time_window_hours"`     // Time window in hours
-	DebugLogging         bool     `toml:"debug_logging"`         // Enable verbose logging for debugging
+// validateEvents ensures the enabled event sinks are reachable, and that the state the changes
+// published are computed from is kept
+func (c *Config) validateEvents() error {
+	if !c.State.Enabled {
+		return fmt.Errorf("event sinks require state to be enabled, events are published for the changes since the previous run")
+	}
+	if c.Events.Source == "" {
+		return fmt.Errorf("events source must not be empty")
+	}
+	if grid := c.Events.EventGrid; grid.Enabled {
+		u, err := url.Parse(grid.Endpoint)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("event_grid endpoint must be an HTTPS URL")
+		}
+		if grid.Key == "" {
+			return fmt.Errorf("event_grid key must be specified. Set it in the config file or GIT_MONITOR_EVENTGRID_KEY environment variable")
+		}
+	}
+	if pubsub := c.Events.PubSub; pubsub.Enabled {
+		if pubsub.Project == "" || pubsub.Topic == "" {
+			return fmt.Errorf("pubsub project and topic must be specified")
+		}
+		if pubsub.Endpoint != "" {
+			u, err := url.Parse(pubsub.Endpoint)
+			if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+				return fmt.Errorf("pubsub endpoint must be an HTTP(S) URL")
+			}
+		}
+	}
+	return nil
 }
 
-// RepoVisibilityConfig contains configuration for the repository visibility checker
-type RepoVisibilityConfig struct {
-	Enabled bool `toml:"enabled"` // Whether the repository visibility checker is enabled
+// validateEncryption ensures there is exactly one source of the encryption key, and that a configured key
+// is a valid AES key
+func (c *Config) validateEncryption() error {
+	e := c.Encryption
+	if (e.Key == "") == (e.KMSDataKey == "") {
+		return fmt.Errorf("exactly one of encryption key and kms_data_key must be specified. Set the key in the config file or GIT_MONITOR_ENCRYPTION_KEY environment variable")
+	}
+	if e.Key != "" {
+		key, err := base64.StdEncoding.DecodeString(e.Key)
+		if err != nil || (len(key) != 16 && len(key) != 24 && len(key) != 32) {
+			return fmt.Errorf("encryption key must be a base64 encoded key of 16, 24 or 32 bytes")
+		}
+	}
+	if e.KMSDataKey != "" {
+		if _, err := base64.StdEncoding.DecodeString(e.KMSDataKey); err != nil {
+			return fmt.Errorf("encryption kms_data_key must be base64 encoded")
+		}
+	}
+	return nil
+}
 
-	// Repository visibility filter. Options: "all", "public-only", "private-only", "specific"
-	RepoVisibility string `toml:"repo_visibility"`
+// validateHeartbeat ensures the heartbeat URLs are HTTP(S) URLs, and that a run that pings
+// its start also pings its end, so the check does not report every run as hanging
+func (c *Config) validateHeartbeat() error {
+	h := c.Heartbeat
+	if h.URL == "" && (h.StartURL != "" || h.FailURL != "") {
+		return fmt.Errorf("heartbeat url must be specified with start_url or fail_url")
+	}
 
-	// Organizations to monitor for repository visibility changes
-	Organizations []string `toml:"organizations"`
+	urls := []struct{ name, raw string }{{"url", h.URL}, {"start_url", h.StartURL}, {"fail_url", h.FailURL}}
+	for _, hu := range urls {
+		if hu.raw == "" {
+			continue
+		}
+		u, err := url.Parse(hu.raw)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("heartbeat %s must be an HTTP(S) URL", hu.name)
+		}
+	}
 
-	// Time window (in hours) to look for visibility changes
-	CheckWindow int `toml:"check_window_hours"`
+	return nil
 }
 
-// Filters contains repository filtering configuration
-type Filters struct {
-	Topic      string   `toml:"topic"`
-	Exclusions []string `toml:"exclusions"`
+// validateAccounts ensures the configuration of each account is valid
+func (c *Config) validateAccounts() error {
+	if c.Monitors.anyEnabled() {
+		return fmt.Errorf("monitors must be configured per account when accounts are set")
+	}
+
+	names := make(map[string]bool)
+	for _, account := range c.Accounts {
+		if account.Name == "" {
+			return fmt.Errorf("name must be specified for every account")
+		}
+		if names[account.Name] {
+			return fmt.Errorf("duplicate account name: %s", account.Name)
+		}
+		names[account.Name] = true
+
+		if account.Token == "" {
+			return fmt.Errorf("token is required for account %s. Set token or the environment variable named by token_env", account.Name)
+		}
+	}
+
+	for _, account := range c.AccountConfigs() {
+		if err := account.Validate(); err != nil {
+			return fmt.Errorf("account %s: %w", account.Account, err)
+		}
+	}
+
+	// The board is not reached with the token of an account, so it needs a token of its own
+	if c.Projects.Enabled && c.Projects.Token == "" {
+		return fmt.Errorf("projects token must be specified when accounts are set. Set it in the config file or GIT_MONITOR_PROJECTS_TOKEN environment variable")
+	}
+
+	return nil
 }
 
-// LoadConfig loads the configuration from the specified file
-func LoadConfig(filePath string) (*Config, error) {
-	config := &Config{
-		Monitors: MonitorsConfig{
-			PRChecker: PRCheckerConfig{
-				TimeWindow:           24,         // Default to 24 hours
-				RepoVisibility:       "specific", // Default to specific repos
-				SpecificRepositories: []string{}, // Empty list as default
-				ExcludedRepositories: []string{}, // Empty list as default
-			},
-			RepoVisibility: RepoVisibilityConfig{
-				Enabled:        false, // Default to disabled
-				CheckWindow:    24,    // Default to 24 hours
-				Organizations:  []string{},
-				RepoVisibility: "specific", // Default to specific repos
-			},
-		},
+// anyEnabled reports whether any monitor is enabled
+func (m MonitorsConfig) anyEnabled() bool {
+	return m.PRChecker.Enabled || m.RepoVisibility.Enabled || m.Rulesets.Enabled || m.CodeScanning.Enabled ||
+		m.Dependabot.Enabled || m.PushProtection.Enabled || m.DormantAccess.Enabled || m.DormantRepos.Enabled ||
+		m.WorkflowPermissions.Enabled || m.Environments.Enabled || m.ForkRuns.Enabled ||
+		m.RepoCreation.Enabled || m.IssueHygiene.Enabled || m.AdminEnforcement.Enabled ||
+		m.Codeowners.Enabled || m.GHAS.Enabled || m.TokenHealth.Enabled ||
+		m.OpenPRChecker.Enabled
+}
+
+// ValidateServer ensures the server mode configuration is valid
+// It is only checked by the serve subcommand, one-off runs ignore the server settings
+func (c *Config) ValidateServer() error {
+	if c.Server.Listen == "" {
+		return fmt.Errorf("listen address must be specified for the server")
 	}
 
-	_, err := os.Stat(filePath)
+	if c.Server.MaxRuns <= 0 {
+		return fmt.Errorf("max runs for the server must be greater than 0")
+	}
+
+	if c.Server.GRPCListen != "" && c.Server.GRPCListen == c.Server.Listen {
+		return fmt.Errorf("gRPC listen address must differ from the REST listen address")
+	}
+
+	if c.Server.Slack.Enabled && c.Server.Slack.SigningSecret == "" {
+		return fmt.Errorf("signing secret must be specified when the Slack app is enabled. Set it in the config file or SLACK_SIGNING_SECRET environment variable")
+	}
+
+	if c.Server.GitHubWebhook.Enabled && c.Server.GitHubWebhook.Secret == "" {
+		return fmt.Errorf("secret must be specified when the GitHub webhook is enabled. Set it in the config file or GITHUB_WEBHOOK_SECRET environment variable")
+	}
+
+	if c.Server.GitHubWebhook.DeliveryRetention.Duration < 0 {
+		return fmt.Errorf("delivery retention of the GitHub webhook must not be negative")
+	}
+
+	if c.Server.RateBudget.Enabled {
+		if err := c.validateRateBudget(); err != nil {
+			return err
+		}
+	}
+
+	if c.Server.LeaderElection.Enabled {
+		if err := c.validateLeaderElection(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateLeaderElection ensures the election of the leader among replicas is valid
+func (c *Config) validateLeaderElection() error {
+	election := c.Server.LeaderElection
+
+	switch election.Backend {
+	case "state":
+		if !c.State.Enabled {
+			return fmt.Errorf("state must be enabled to elect a leader with the state backend")
+		}
+	case "kubernetes":
+		if election.LeaseName == "" {
+			return fmt.Errorf("lease name must be specified to elect a leader with the kubernetes backend")
+		}
+	default:
+		return fmt.Errorf("invalid leader election backend: %s. Must be state or kubernetes", election.Backend)
+	}
+
+	if election.Duration.Duration < 3*time.Second {
+		return fmt.Errorf("lease duration of the leader election must be at least 3 seconds")
+	}
+
+	return nil
+}
+
+// validateRateBudget ensures the budget of the hourly API calls is valid
+func (c *Config) validateRateBudget() error {
+	budget := c.Server.RateBudget
+
+	if budget.HourlyCalls <= 0 {
+		return fmt.Errorf("hourly calls of the rate budget must be greater than 0")
+	}
+
+	var total float64
+	for key, share := range budget.Shares {
+		if !monitorKeys[key] {
+			return fmt.Errorf("invalid monitor in rate budget shares: %s", key)
+		}
+		if share <= 0 {
+			return fmt.Errorf("rate budget share of %s must be greater than 0", key)
+		}
+		total += share
+	}
+	if total > 100 {
+		return fmt.Errorf("rate budget shares must add up to at most 100, got %g", total)
+	}
+
+	return nil
+}
+
+// validateSchedule ensures the notification schedule is valid
+func (c *Config) validateSchedule() error {
+	schedule := c.Notifications.Schedule
+
+	if err := validateHours("notification schedule", schedule.Days, schedule.Start, schedule.End); err != nil {
+		return err
+	}
+
+	if schedule.QueuePath == "" {
+		return fmt.Errorf("queue path must be specified for the notification schedule")
+	}
+
+	return nil
+}
+
+// validateHours ensures the days and the start and end times of business hours are valid
+func validateHours(name string, days []string, startTime, endTime string) error {
+	validDays := map[string]bool{
+		"mon": true, "tue": true, "wed": true, "thu": true, "fri": true, "sat": true, "sun": true,
+	}
+
+	if len(days) == 0 {
+		return fmt.Errorf("at least one day must be specified for the %s", name)
+	}
+
+	for _, day := range days {
+		if !validDays[day] {
+			return fmt.Errorf("invalid day in %s: %s. Must be one of: mon, tue, wed, thu, fri, sat, sun", name, day)
+		}
+	}
+
+	start, err := time.Parse("15:04", startTime)
 	if err != nil {
-		return nil, fmt.Errorf("config file not found: %v", err)
+		return fmt.Errorf("invalid start time in %s: %s. Must be HH:MM", name, startTime)
 	}
 
-	_, err = toml.DecodeFile(filePath, config)
+	end, err := time.Parse("15:04", endTime)
 	if err != nil {
-		return nil, fmt.Errorf("error decoding config file: %v", err)
+		return fmt.Errorf("invalid end time in %s: %s. Must be HH:MM", name, endTime)
 	}
 
-	// Check if token is in environment variable
-	if envToken := os.Getenv("GITHUB_TOKEN"); envToken != "" {
-		config.GitHub.Token = envToken
+	if !end.After(start) {
+		return fmt.Errorf("end time must be after start time in %s", name)
 	}
 
-	return config, nil
+	return nil
 }
 
-// Validate ensures the configuration is valid
-func (c *Config) Validate() error {
-	if c.GitHub.Token == "" {
-		return fmt.Errorf("GitHub token is required. Set it in the config file or GITHUB_TOKEN environment variable")
+// validateScoring ensures the risk scoring configuration is valid
+func (c *Config) validateScoring() error {
+	if c.Scoring.Threshold <= 0 {
+		return fmt.Errorf("threshold for scoring must be greater than 0")
 	}
 
-	if c.Monitors.PRChecker.Enabled {
-		// Validate repo visibility setting
-		validVisibilities := map[string]bool{
-			"all":          true,
-			"public-only":  true,
-			"private-only": true,
-			"specific":     true,
+	for monitor, weight := range c.Scoring.Weights {
+		if !monitorKeys[monitor] {
+			return fmt.Errorf("invalid monitor in scoring weights: %s", monitor)
+		}
+		if weight < 0 {
+			return fmt.Errorf("weight for %s must not be negative", monitor)
 		}
+	}
 
-		if !validVisibilities[c.Monitors.PRChecker.RepoVisibility] {
-			return fmt.Errorf("invalid repository visibility: %s. Must be one of: all, public-only, private-only, specific",
-				c.Monitors.PRChecker.RepoVisibility)
+	return nil
+}
+
+// validateEvidence ensures the evidence bundle configuration is valid
+func (c *Config) validateEvidence() error {
+	if c.Evidence.Path == "" {
+		return fmt.Errorf("path must be specified when evidence is enabled")
+	}
+
+	switch c.Evidence.Signer {
+	case "", "gpg":
+	case "key":
+		if c.Evidence.KeyPath == "" {
+			return fmt.Errorf("key path must be specified for the key evidence signer")
 		}
+	default:
+		return fmt.Errorf("invalid evidence signer: %s. Must be one of: key, gpg", c.Evidence.Signer)
+	}
 
-		// Only check repositories list if visibility is set to "specific"
-		if c.Monitors.PRChecker.RepoVisibility == "specific" && len(c.Monitors.PRChecker.SpecificRepositories) == 0 {
-			return fmt.Errorf("at least one repository must be specified for PR checker when repo_visibility is 'specific'")
+	return nil
+}
+
+// validateSLA ensures the remediation deadlines are valid
+func (c *Config) validateSLA() error {
+	if !c.State.Enabled {
+		return fmt.Errorf("SLA tracking requires state to be enabled, when findings were first seen is kept in the state")
+	}
+	if len(c.SLA.Deadlines) == 0 {
+		return fmt.Errorf("at least one SLA deadline must be specified")
+	}
+	for key, deadline := range c.SLA.Deadlines {
+		if deadline.Duration <= 0 {
+			return fmt.Errorf("SLA deadline of %s must be positive", key)
+		}
+	}
+	if c.SLA.Webhook != "" && !strings.HasPrefix(c.SLA.Webhook, "https://") {
+		return fmt.Errorf("SLA webhook must be an https:// URL")
+	}
+	return nil
+}
+
+// validateEscalation ensures the escalation policies are valid
+func (c *Config) validateEscalation() error {
+	if !c.State.Enabled {
+		return fmt.Errorf("escalation requires state to be enabled, how long findings persisted is kept in the state")
+	}
+	if len(c.Escalation.Policies) == 0 {
+		return fmt.Errorf("at least one escalation policy must be specified")
+	}
+
+	names := make(map[string]bool)
+	for _, policy := range c.Escalation.Policies {
+		if policy.Name == "" {
+			return fmt.Errorf("escalation policies must have a name")
+		}
+		if names[policy.Name] {
+			return fmt.Errorf("duplicate escalation policy: %s", policy.Name)
 		}
+		names[policy.Name] = true
 
-		// If organization is specified with "specific" visibility, warn but continue
-		if c.Monitors.PRChecker.RepoVisibility == "specific" && c.Monitors.PRChecker.Organization != "" {
-			log.Printf("WARNING: Organization '%s' is specified but repo_visibility is 'specific'. The organization setting will be ignored.",
-				c.Monitors.PRChecker.Organization)
+		if policy.AfterRuns < 0 || policy.After.Duration < 0 {
+			return fmt.Errorf("after_runs and after of escalation policy %s must not be negative", policy.Name)
+		}
+		if policy.AfterRuns == 0 && policy.After.Duration == 0 {
+			return fmt.Errorf("escalation policy %s must set after_runs or after", policy.Name)
+		}
+		if !validEscalationSeverities[policy.Severity] {
+			return fmt.Errorf("invalid severity of escalation policy %s: %s. Must be one of: medium, high, critical", policy.Name, policy.Severity)
+		}
+		if policy.Webhook != "" && !strings.HasPrefix(policy.Webhook, "https://") {
+			return fmt.Errorf("webhook of escalation policy %s must be an https:// URL", policy.Name)
 		}
 	}
 
-	if c.Monitors.PRChecker.TimeWindow <= 0 {
-		return fmt.Errorf("time window must be greater than 0")
+	return nil
+}
+
+// validateOwnership ensures the ownership of repositories is valid
+func (c *Config) validateOwnership() error {
+	if len(c.Ownership.Sources) == 0 {
+		return fmt.Errorf("at least one ownership source must be specified")
+	}
+	for _, source := range c.Ownership.Sources {
+		if source != "config" && source != "codeowners" && source != "teams" {
+			return fmt.Errorf("invalid ownership source: %s. Must be one of: config, codeowners, teams", source)
+		}
 	}
 
-	if c.Monitors.RepoVisibility.Enabled {
-		// Validate repo visibility setting
-		validVisibilities := map[string]bool{
-			"all":          true,
-			"public-only":  true,
-			"private-only": true,
-			"specific":     true,
+	for pattern, team := range c.Ownership.Teams {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid ownership pattern %q: %v", pattern, err)
 		}
+		if team == "" {
+			return fmt.Errorf("team of ownership pattern %q must not be empty", pattern)
+		}
+	}
 
-		if !validVisibilities[c.Monitors.RepoVisibility.RepoVisibility] {
-			return fmt.Errorf("invalid repository visibility for repo_visibility monitor: %s. Must be one of: all, public-only, private-only, specific",
-				c.Monitors.RepoVisibility.RepoVisibility)
+	for team, webhook := range c.Ownership.Webhooks {
+		if !strings.HasPrefix(webhook, "https://") {
+			return fmt.Errorf("webhook of team %s must be an https:// URL", team)
 		}
+	}
 
-		// If using "specific" visibility, require at least one organization
-		if c.Monitors.RepoVisibility.RepoVisibility == "specific" && len(c.Monitors.RepoVisibility.Organizations) == 0 {
-			return fmt.Errorf("at least one organization must be specified for repo_visibility monitor when repo_visibility is 'specific'")
+	return nil
+}
+
+// validateArchive ensures the report archive configuration is valid
+func (c *Config) validateArchive() error {
+	if c.Archive.Location == "" {
+		return fmt.Errorf("location must be specified when the report archive is enabled")
+	}
+	if scheme, _, ok := strings.Cut(c.Archive.Location, "://"); ok && scheme != "s3" {
+		return fmt.Errorf("invalid archive location: %s. Must be a directory or a URL starting with s3://", c.Archive.Location)
+	}
+
+	if c.Archive.MaxAge.Duration < 0 {
+		return fmt.Errorf("max age of the report archive must not be negative")
+	}
+	if c.Archive.MaxReports < 0 {
+		return fmt.Errorf("max reports of the report archive must not be negative")
+	}
+
+	return nil
+}
+
+// monitorKeys are the configuration keys of the monitors
+var monitorKeys = map[string]bool{
+	"pr_checker":               true,
+	"repo_visibility":          true,
+	"rulesets":                 true,
+	"code_scanning_dismissals": true,
+	"dependabot_dismissals":    true,
+	"push_protection_bypasses": true,
+	"dormant_accounts":         true,
+	"dormant_repositories":     true,
+	"workflow_permissions":     true,
+	"environment_protection":   true,
+	"fork_workflow_approvals":  true,
+	"repo_creation":            true,
+	"issue_hygiene":            true,
+	"admin_enforcement":        true,
+	"codeowners_coverage":      true,
+	"ghas_utilization":         true,
+	"token_health":             true,
+	"open_pr_checker":          true,
+}
+
+// validatePagination ensures the page sizes and page caps of the monitors' listings are valid
+func (c *Config) validatePagination() error {
+	paginations := []struct {
+		listing    string
+		pagination PaginationConfig
+	}{
+		{"pr_checker monitor", c.Monitors.PRChecker.Pagination},
+		{"status checks of pr_checker monitor", c.Monitors.PRChecker.StatusChecksPagination},
+		{"open_pr_checker monitor", c.Monitors.OpenPRChecker.Pagination},
+		{"code_scanning_dismissals monitor", c.Monitors.CodeScanning.Pagination},
+		{"environment_protection monitor", c.Monitors.Environments.Pagination},
+		{"fork_workflow_approvals monitor", c.Monitors.ForkRuns.Pagination},
+	}
+
+	for _, p := range paginations {
+		if p.pagination.PageSize < 0 || p.pagination.PageSize > DefaultPageSize {
+			return fmt.Errorf("page size for %s must be between 1 and %d", p.listing, DefaultPageSize)
+		}
+		if p.pagination.MaxPages < 0 {
+			return fmt.Errorf("max pages for %s must not be negative", p.listing)
 		}
+	}
 
-		// All visibility options require at least one organization
-		if len(c.Monitors.RepoVisibility.Organizations) == 0 {
-			return fmt.Errorf("at least one organization must be specified for repo_visibility monitor")
+	return nil
+}
+
+// validateOutputs ensures the dedicated monitor outputs are valid
+func (c *Config) validateOutputs() error {
+	outputs := []struct {
+		monitor string
+		output  OutputConfig
+	}{
+		{"pr_checker", c.Monitors.PRChecker.Output},
+		{"repo_visibility", c.Monitors.RepoVisibility.Output},
+		{"rulesets", c.Monitors.Rulesets.Output},
+		{"code_scanning_dismissals", c.Monitors.CodeScanning.Output},
+		{"dependabot_dismissals", c.Monitors.Dependabot.Output},
+		{"push_protection_bypasses", c.Monitors.PushProtection.Output},
+		{"dormant_accounts", c.Monitors.DormantAccess.Output},
+		{"dormant_repositories", c.Monitors.DormantRepos.Output},
+		{"workflow_permissions", c.Monitors.WorkflowPermissions.Output},
+		{"environment_protection", c.Monitors.Environments.Output},
+		{"fork_workflow_approvals", c.Monitors.ForkRuns.Output},
+		{"repo_creation", c.Monitors.RepoCreation.Output},
+		{"issue_hygiene", c.Monitors.IssueHygiene.Output},
+		{"admin_enforcement", c.Monitors.AdminEnforcement.Output},
+		{"codeowners_coverage", c.Monitors.Codeowners.Output},
+		{"ghas_utilization", c.Monitors.GHAS.Output},
+		{"token_health", c.Monitors.TokenHealth.Output},
+		{"open_pr_checker", c.Monitors.OpenPRChecker.Output},
+	}
+
+	validFormats := map[string]bool{
+		"":         true, // Defaults to markdown
+		"markdown": true,
+		"json":     true,
+		"csv":      true,
+	}
+
+	for _, o := range outputs {
+		if !validFormats[o.output.Format] {
+			return fmt.Errorf("invalid output format for %s monitor: %s. Must be one of: markdown, json, csv", o.monitor, o.output.Format)
 		}
 
-		if c.Monitors.RepoVisibility.CheckWindow <= 0 {
-			return fmt.Errorf("check window for repo visibility must be greater than 0")
+		if o.output.Format != "" && o.output.Path == "" {
+			return fmt.Errorf("output path must be specified for %s monitor when an output format is set", o.monitor)
 		}
 	}
 
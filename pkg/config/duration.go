@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration is a time window read from the configuration file
+// It accepts Go duration strings ("90m", "36h"), a day suffix ("7d") and,
+// for backward compatibility, plain integers which are interpreted as hours
+type Duration struct {
+	time.Duration
+}
+
+// Hours returns a Duration of the given number of hours
+func Hours(h int) Duration {
+	return Duration{time.Duration(h) * time.Hour}
+}
+
+// UnmarshalTOML implements toml.Unmarshaler
+func (d *Duration) UnmarshalTOML(value interface{}) error {
+	switch v := value.(type) {
+	case int64:
+		d.Duration = time.Duration(v) * time.Hour
+		return nil
+	case string:
+		parsed, err := ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		d.Duration = parsed
+		return nil
+	default:
+		return fmt.Errorf("invalid duration %v: must be a duration string or a number of hours", value)
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// ParseDuration parses a duration string, additionally accepting whole days ("7d")
+// and bare numbers of hours ("24")
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+
+	if hours, err := strconv.Atoi(s); err == nil {
+		return time.Duration(hours) * time.Hour, nil
+	}
+
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	return parsed, nil
+}
@@ -2,13 +2,16 @@ package test
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/anupsv/git-monitoring/pkg/config"
 )
 
 func TestValidate(t *testing.T) {
+	negative := -1
 	tests := []struct {
 		name          string
 		config        *config.Config
@@ -27,7 +30,7 @@ func TestValidate(t *testing.T) {
 						RepoVisibility:       "specific",
 						Organization:         "",
 						SpecificRepositories: []string{"owner/repo"},
-						TimeWindow:           24,
+						TimeWindow:           config.Hours(24),
 					},
 					RepoVisibility: config.RepoVisibilityConfig{
 						Enabled: false,
@@ -48,7 +51,7 @@ func TestValidate(t *testing.T) {
 						RepoVisibility:       "specific",
 						Organization:         "",
 						SpecificRepositories: []string{"owner/repo"},
-						TimeWindow:           24,
+						TimeWindow:           config.Hours(24),
 					},
 				},
 			},
@@ -67,7 +70,7 @@ func TestValidate(t *testing.T) {
 						RepoVisibility:       "specific",
 						Organization:         "",
 						SpecificRepositories: []string{},
-						TimeWindow:           24,
+						TimeWindow:           config.Hours(24),
 					},
 				},
 			},
@@ -86,7 +89,7 @@ func TestValidate(t *testing.T) {
 						RepoVisibility:       "specific",
 						Organization:         "",
 						SpecificRepositories: []string{"owner/repo"},
-						TimeWindow:           0,
+						TimeWindow:           config.Hours(0),
 					},
 				},
 			},
@@ -105,7 +108,7 @@ func TestValidate(t *testing.T) {
 						RepoVisibility:       "invalid",
 						Organization:         "",
 						SpecificRepositories: []string{"owner/repo"},
-						TimeWindow:           24,
+						TimeWindow:           config.Hours(24),
 					},
 				},
 			},
@@ -124,7 +127,7 @@ func TestValidate(t *testing.T) {
 						RepoVisibility:       "all",
 						Organization:         "testorg",
 						SpecificRepositories: []string{}, // Can be empty with non-specific visibility
-						TimeWindow:           24,
+						TimeWindow:           config.Hours(24),
 					},
 				},
 			},
@@ -142,7 +145,7 @@ func TestValidate(t *testing.T) {
 						RepoVisibility:       "specific",
 						Organization:         "testorg", // Will generate a warning but not an error
 						SpecificRepositories: []string{"owner/repo"},
-						TimeWindow:           24,
+						TimeWindow:           config.Hours(24),
 					},
 				},
 			},
@@ -157,13 +160,13 @@ func TestValidate(t *testing.T) {
 				Monitors: config.MonitorsConfig{
 					PRChecker: config.PRCheckerConfig{
 						Enabled:    false,
-						TimeWindow: 24, // Add default time window to pass validation
+						TimeWindow: config.Hours(24), // Add default time window to pass validation
 					},
 					RepoVisibility: config.RepoVisibilityConfig{
 						Enabled:        true,
 						Organizations:  []string{"test-org"},
-						CheckWindow:    0,     // Invalid check window
-						RepoVisibility: "all", // Valid repo visibility
+						CheckWindow:    config.Hours(0), // Invalid check window
+						RepoVisibility: "all",           // Valid repo visibility
 					},
 				},
 			},
@@ -179,12 +182,12 @@ func TestValidate(t *testing.T) {
 				Monitors: config.MonitorsConfig{
 					PRChecker: config.PRCheckerConfig{
 						Enabled:    false,
-						TimeWindow: 24, // Add default time window to pass validation
+						TimeWindow: config.Hours(24), // Add default time window to pass validation
 					},
 					RepoVisibility: config.RepoVisibilityConfig{
 						Enabled:        true,
 						Organizations:  []string{"test-org"},
-						CheckWindow:    24, // Valid check window
+						CheckWindow:    config.Hours(24), // Valid check window
 						RepoVisibility: "specific",
 					},
 				},
@@ -200,13 +203,13 @@ func TestValidate(t *testing.T) {
 				Monitors: config.MonitorsConfig{
 					PRChecker: config.PRCheckerConfig{
 						Enabled:    false,
-						TimeWindow: 24,
+						TimeWindow: config.Hours(24),
 					},
 					RepoVisibility: config.RepoVisibilityConfig{
 						Enabled:        true,
 						RepoVisibility: "specific",
 						Organizations:  []string{}, // Empty organizations list with specific visibility
-						CheckWindow:    24,
+						CheckWindow:    config.Hours(24),
 					},
 				},
 			},
@@ -222,18 +225,1731 @@ func TestValidate(t *testing.T) {
 				Monitors: config.MonitorsConfig{
 					PRChecker: config.PRCheckerConfig{
 						Enabled:    false,
-						TimeWindow: 24,
+						TimeWindow: config.Hours(24),
 					},
 					RepoVisibility: config.RepoVisibilityConfig{
 						Enabled:        true,
 						RepoVisibility: "invalid-value", // Invalid visibility
-						CheckWindow:    24,
+						CheckWindow:    config.Hours(24),
 					},
 				},
 			},
 			expectError:   true,
 			errorContains: "invalid repository visibility",
 		},
+		{
+			name: "Rulesets enabled without targets",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+					Rulesets: config.RulesetsConfig{
+						Enabled: true,
+						Desired: []config.DesiredRuleset{{Name: "protect-main", Scope: "organization"}},
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "at least one organization or repository must be specified for rulesets monitor",
+		},
+		{
+			name: "Rulesets with invalid desired scope",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+					Rulesets: config.RulesetsConfig{
+						Enabled:       true,
+						Organizations: []string{"test-org"},
+						Desired:       []config.DesiredRuleset{{Name: "protect-main", Scope: "enterprise"}},
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid scope for desired ruleset",
+		},
+		{
+			name: "Valid Rulesets configuration",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+					Rulesets: config.RulesetsConfig{
+						Enabled:       true,
+						Organizations: []string{"test-org"},
+						Desired: []config.DesiredRuleset{
+							{Name: "protect-main", Scope: "organization", Enforcement: "active", RequiredRules: []string{"pull_request"}},
+						},
+					},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "Code scanning dismissals enabled without targets",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+					CodeScanning: config.CodeScanningConfig{
+						Enabled:     true,
+						CheckWindow: config.Hours(24),
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "at least one organization or repository must be specified for code_scanning_dismissals monitor",
+		},
+		{
+			name: "Dependabot dismissals with invalid minimum severity",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+					Dependabot: config.DependabotConfig{
+						Enabled:         true,
+						Organizations:   []string{"test-org"},
+						MinimumSeverity: "severe",
+						CheckWindow:     config.Hours(24),
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid minimum severity for dependabot_dismissals monitor",
+		},
+		{
+			name: "Dormant accounts with invalid inactive days",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+					DormantAccess: config.DormantAccessConfig{
+						Enabled:       true,
+						Organizations: []string{"test-org"},
+						InactiveDays:  0,
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "inactive days for dormant accounts must be greater than 0",
+		},
+		{
+			name: "Dormant repositories enabled without targets",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+					DormantRepos: config.DormantReposConfig{
+						Enabled:      true,
+						InactiveDays: 180,
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "at least one organization or repository must be specified for dormant_repositories monitor",
+		},
+		{
+			name: "Workflow permissions enabled without targets",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+					WorkflowPermissions: config.WorkflowPermissionsConfig{
+						Enabled: true,
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "at least one organization or repository must be specified for workflow_permissions monitor",
+		},
+		{
+			name: "Environment protection without environments",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+					Environments: config.EnvironmentsConfig{
+						Enabled:       true,
+						Organizations: []string{"test-org"},
+						CheckWindow:   config.Hours(24),
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "at least one environment must be specified for environment_protection monitor",
+		},
+		{
+			name: "Repository creation with invalid approved role",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+					RepoCreation: config.RepoCreationConfig{
+						Enabled:       true,
+						Organizations: []string{"test-org"},
+						CheckWindow:   config.Hours(24),
+						ApprovedRoles: []string{"owner"},
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid approved role for repo_creation monitor: owner",
+		},
+		{
+			name: "Admin enforcement without organizations",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+					AdminEnforcement: config.AdminEnforcementConfig{
+						Enabled:     true,
+						CheckWindow: config.Hours(24),
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "at least one organization must be specified for admin_enforcement monitor",
+		},
+		{
+			name: "GHAS utilization without organizations",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+					GHAS: config.GHASConfig{
+						Enabled: true,
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "at least one organization must be specified for ghas_utilization monitor",
+		},
+		{
+			name: "Token health with rate limit headroom above 100",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+					TokenHealth: config.TokenHealthConfig{
+						Enabled:              true,
+						MinRateLimitHeadroom: 120,
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "min rate limit headroom for token_health monitor must be between 0 and 100",
+		},
+		{
+			name: "Open PR checker without organizations or repositories",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+					OpenPRChecker: config.OpenPRCheckerConfig{
+						Enabled: true,
+						MinAge:  config.Hours(24),
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "at least one organization or repository must be specified for open_pr_checker monitor",
+		},
+		{
+			name: "State in an unsupported store",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:        true,
+						RepoVisibility: "all",
+						TimeWindow:     config.Hours(24),
+					},
+				},
+				State: config.StateConfig{
+					Enabled: true,
+					Path:    "redis://localhost:6379/0",
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid state store: redis",
+		},
+		{
+			name: "Monitor output with invalid format",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:              true,
+						RepoVisibility:       "specific",
+						SpecificRepositories: []string{"owner/repo"},
+						TimeWindow:           config.Hours(24),
+						Output: config.OutputConfig{
+							Path:   "pr-report.xml",
+							Format: "xml",
+						},
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid output format for pr_checker monitor",
+		},
+		{
+			name: "Monitor output format without path",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+					RepoVisibility: config.RepoVisibilityConfig{
+						Output: config.OutputConfig{
+							Format: "json",
+						},
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "output path must be specified for repo_visibility monitor",
+		},
+		{
+			name: "Invalid timezone",
+			config: &config.Config{
+				Timezone: "Mars/Olympus_Mons",
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid timezone",
+		},
+		{
+			name: "Notification schedule with invalid hours",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Notifications: config.NotificationsConfig{
+					Schedule: config.ScheduleConfig{
+						Enabled:   true,
+						Days:      []string{"mon"},
+						Start:     "17:00",
+						End:       "09:00",
+						QueuePath: "digest.md",
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "end time must be after start time in notification schedule",
+		},
+		{
+			name: "In-repo suppressions without path",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Suppressions: config.SuppressionsConfig{
+					InRepo: true,
+				},
+			},
+			expectError:   true,
+			errorContains: "path must be specified when in-repo suppressions are enabled",
+		},
+		{
+			name: "Repository policies with negative cap",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				RepoPolicy: config.RepoPolicyConfig{
+					Enabled:                true,
+					Path:                   ".github/git-monitor.toml",
+					MaxDormantInactiveDays: -1,
+				},
+			},
+			expectError:   true,
+			errorContains: "max dormant inactive days for repository policies must not be negative",
+		},
+		{
+			name: "Repository policies with negative approvals cap",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				RepoPolicy: config.RepoPolicyConfig{
+					Enabled:              true,
+					Path:                 ".github/git-monitor.toml",
+					MaxRequiredApprovals: -1,
+				},
+			},
+			expectError:   true,
+			errorContains: "max required approvals for repository policies must not be negative",
+		},
+		{
+			name: "Repository policies with negative SLA floor",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				RepoPolicy: config.RepoPolicyConfig{
+					Enabled:    true,
+					Path:       ".github/git-monitor.toml",
+					MinSLADays: -1,
+				},
+			},
+			expectError:   true,
+			errorContains: "min SLA days for repository policies must not be negative",
+		},
+		{
+			name: "Redaction without salt",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Redaction: config.RedactionConfig{
+					Enabled: true,
+				},
+			},
+			expectError:   true,
+			errorContains: "salt is required when redaction is enabled",
+		},
+		{
+			name: "Redaction with a blank salt and aliases",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Redaction: config.RedactionConfig{
+					Enabled:     true,
+					AliasesFile: "aliases.toml",
+					Salt:        "  ",
+				},
+			},
+			expectError:   true,
+			errorContains: "salt is required when redaction is enabled",
+		},
+		{
+			name: "Scoring without threshold",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Scoring: config.ScoringConfig{
+					Enabled: true,
+				},
+			},
+			expectError:   true,
+			errorContains: "threshold for scoring must be greater than 0",
+		},
+		{
+			name: "Scoring with unknown monitor",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Scoring: config.ScoringConfig{
+					Enabled:   true,
+					Threshold: 10,
+					Weights:   map[string]int{"unknown": 5},
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid monitor in scoring weights: unknown",
+		},
+		{
+			name: "Scoring with negative weight",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Scoring: config.ScoringConfig{
+					Enabled:   true,
+					Threshold: 10,
+					Weights:   map[string]int{"pr_checker": -1},
+				},
+			},
+			expectError:   true,
+			errorContains: "weight for pr_checker must not be negative",
+		},
+		{
+			name: "Compliance controls for unknown monitor",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Compliance: config.ComplianceConfig{
+					Controls: map[string][]string{"pr_checks": {"SOC2 CC8.1"}},
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid monitor in compliance controls: pr_checks",
+		},
+		{
+			name: "Remediation runbook for unknown monitor",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Remediation: config.RemediationConfig{
+					Runbooks: map[string]string{"pr_checks.unapproved": "https://wiki.example.com/unapproved"},
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid monitor in remediation runbooks: pr_checks.unapproved",
+		},
+		{
+			name: "Remediation runbook not a URL",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Remediation: config.RemediationConfig{
+					Runbooks: map[string]string{"repo_visibility": "wiki/visibility"},
+				},
+			},
+			expectError:   true,
+			errorContains: "remediation runbook of repo_visibility must be an HTTP(S) URL",
+		},
+		{
+			name: "Evidence with invalid signer",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Evidence: config.EvidenceConfig{
+					Enabled: true,
+					Path:    "evidence.json",
+					Signer:  "cosign",
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid evidence signer: cosign",
+		},
+		{
+			name: "Evidence key signer without key",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Evidence: config.EvidenceConfig{
+					Enabled: true,
+					Path:    "evidence.json",
+					Signer:  "key",
+				},
+			},
+			expectError:   true,
+			errorContains: "key path must be specified for the key evidence signer",
+		},
+		{
+			name: "Archive in an unsupported location",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Archive: config.ArchiveConfig{
+					Enabled:  true,
+					Location: "gs://bucket/reports",
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid archive location: gs://bucket/reports",
+		},
+		{
+			name: "Archive with negative max reports",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Archive: config.ArchiveConfig{
+					Enabled:    true,
+					Location:   "s3://bucket/reports",
+					MaxReports: -1,
+				},
+			},
+			expectError:   true,
+			errorContains: "max reports of the report archive must not be negative",
+		},
+		{
+			name: "Ownership with invalid source",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Ownership: config.OwnershipConfig{
+					Enabled: true,
+					Sources: []string{"config", "ldap"},
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid ownership source: ldap",
+		},
+		{
+			name: "Ownership with invalid pattern",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Ownership: config.OwnershipConfig{
+					Enabled: true,
+					Sources: []string{"config"},
+					Teams:   map[string]string{"acme/[payments": "payments"},
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid ownership pattern",
+		},
+		{
+			name: "Ownership with insecure team webhook",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Ownership: config.OwnershipConfig{
+					Enabled:  true,
+					Sources:  []string{"config", "codeowners"},
+					Webhooks: map[string]string{"payments": "http://hooks.slack.com/services/x"},
+				},
+			},
+			expectError:   true,
+			errorContains: "webhook of team payments must be an https:// URL",
+		},
+		{
+			name: "Escalation without state",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Escalation: config.EscalationConfig{
+					Enabled:  true,
+					Policies: []config.EscalationPolicy{{Name: "managers", AfterRuns: 5, Severity: "high"}},
+				},
+			},
+			expectError:   true,
+			errorContains: "escalation requires state to be enabled",
+		},
+		{
+			name: "Escalation policy without threshold",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				State: config.StateConfig{
+					Enabled: true,
+					Path:    "state.json",
+				},
+				Escalation: config.EscalationConfig{
+					Enabled:  true,
+					Policies: []config.EscalationPolicy{{Name: "managers", Severity: "high"}},
+				},
+			},
+			expectError:   true,
+			errorContains: "escalation policy managers must set after_runs or after",
+		},
+		{
+			name: "Escalation policy with invalid severity",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				State: config.StateConfig{
+					Enabled: true,
+					Path:    "state.json",
+				},
+				Escalation: config.EscalationConfig{
+					Enabled:  true,
+					Policies: []config.EscalationPolicy{{Name: "managers", AfterRuns: 5, Severity: "urgent"}},
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid severity of escalation policy managers: urgent",
+		},
+		{
+			name: "SLA with non-positive deadline",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				State: config.StateConfig{
+					Enabled: true,
+					Path:    "state.json",
+				},
+				SLA: config.SLAConfig{
+					Enabled:   true,
+					Deadlines: map[string]config.Duration{"repo_visibility": config.Hours(0)},
+				},
+			},
+			expectError:   true,
+			errorContains: "SLA deadline of repo_visibility must be positive",
+		},
+		{
+			name: "Checkpoint without path",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Checkpoint: config.CheckpointConfig{
+					Enabled: true,
+				},
+			},
+			expectError:   true,
+			errorContains: "path must be specified when checkpoints are enabled",
+		},
+		{
+			name: "Membership cache without ttl",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Membership: config.MembershipConfig{
+					Enabled: true,
+					Path:    "git-monitor-membership.json",
+				},
+			},
+			expectError:   true,
+			errorContains: "membership cache ttl must be positive",
+		},
+		{
+			name: "Notification retries without state",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Notifications: config.NotificationsConfig{
+					Retry: config.RetryConfig{
+						Enabled:  true,
+						MaxAge:   config.Hours(24),
+						Interval: config.Hours(1),
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "notification retries require state to be enabled",
+		},
+		{
+			name: "Notifications on change only without state",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Notifications: config.NotificationsConfig{
+					OnChangeOnly: true,
+				},
+			},
+			expectError:   true,
+			errorContains: "notifications on_change_only requires state to be enabled",
+		},
+		{
+			name: "Team slug without organization",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:        true,
+						RepoVisibility: "all",
+						TeamSlug:       "platform",
+						TimeWindow:     config.Hours(24),
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "organization must be specified for PR checker when team_slug is set",
+		},
+		{
+			name: "Search discovery without organization",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:        true,
+						RepoVisibility: "all",
+						Discovery:      "search",
+						TimeWindow:     config.Hours(24),
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "when discovery is 'search'",
+		},
+		{
+			name: "Invalid PR checker discovery",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:        true,
+						RepoVisibility: "all",
+						Discovery:      "graphql",
+						TimeWindow:     config.Hours(24),
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid PR checker discovery",
+		},
+		{
+			name: "Negative PR checker inactive days",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:          true,
+						RepoVisibility:   "all",
+						TimeWindow:       config.Hours(24),
+						SkipInactiveDays: -1,
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "skip inactive days for PR checker must not be negative",
+		},
+		{
+			name: "Negative PR checker required approvals",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:           true,
+						RepoVisibility:    "all",
+						TimeWindow:        config.Hours(24),
+						RequiredApprovals: -1,
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "required approvals for PR checker must not be negative",
+		},
+		{
+			name: "Negative PR checker min review time",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:        true,
+						RepoVisibility: "all",
+						TimeWindow:     config.Hours(24),
+						MinReviewTime:  config.Duration{Duration: -time.Minute},
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "min review time for PR checker must not be negative",
+		},
+		{
+			name: "Negative PR checker dismissal window",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:         true,
+						RepoVisibility:  "all",
+						TimeWindow:      config.Hours(24),
+						DismissalWindow: config.Duration{Duration: -time.Hour},
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "dismissal window for PR checker must not be negative",
+		},
+		{
+			name: "Invalid PR checker required section pattern",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:          true,
+						RepoVisibility:   "all",
+						TimeWindow:       config.Hours(24),
+						RequiredSections: []string{"^## (Testing"},
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid required section pattern",
+		},
+		{
+			name: "Invalid PR checker title pattern",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:        true,
+						RepoVisibility: "all",
+						TimeWindow:     config.Hours(24),
+						TitlePattern:   "^[A-Z+-",
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid title pattern",
+		},
+		{
+			name: "Unknown PR checker title preset",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:        true,
+						RepoVisibility: "all",
+						TimeWindow:     config.Hours(24),
+						TitlePreset:    "semantic",
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid title preset",
+		},
+		{
+			name: "Invalid day in PR checker merge hours",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:        true,
+						RepoVisibility: "all",
+						TimeWindow:     config.Hours(24),
+						MergeHours: config.MergeHoursConfig{
+							Enabled: true,
+							Days:    []string{"monday"},
+							Start:   "09:00",
+							End:     "17:00",
+						},
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid day in PR checker merge hours",
+		},
+		{
+			name: "Invalid timezone in PR checker merge hours",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:        true,
+						RepoVisibility: "all",
+						TimeWindow:     config.Hours(24),
+						MergeHours: config.MergeHoursConfig{
+							Enabled:  true,
+							Days:     []string{"mon", "tue", "wed", "thu", "fri"},
+							Start:    "09:00",
+							End:      "17:00",
+							Timezone: "Mars/Olympus",
+						},
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid timezone in PR checker merge hours",
+		},
+		{
+			name: "PR checker title preset and pattern",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:        true,
+						RepoVisibility: "all",
+						TimeWindow:     config.Hours(24),
+						TitlePattern:   "^[A-Z]+-\\d+ ",
+						TitlePreset:    "conventional_commits",
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "title_preset and title_pattern for PR checker are exclusive",
+		},
+		{
+			name: "User agent with a line break",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token:     "valid-token",
+					UserAgent: "git-monitor\r\nX-Injected: 1",
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid GitHub user_agent",
+		},
+		{
+			name: "Invalid PR checker merge method",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:             true,
+						RepoVisibility:      "all",
+						TimeWindow:          config.Hours(24),
+						AllowedMergeMethods: []string{"squash", "fast-forward"},
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid merge method",
+		},
+		{
+			name: "PR checker override of an invalid repository",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:        true,
+						RepoVisibility: "all",
+						TimeWindow:     config.Hours(24),
+						RepoOverrides:  []config.PRCheckerOverride{{Repository: "payments"}},
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid repository in PR checker repo_overrides",
+		},
+		{
+			name: "PR checker repository overridden twice",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:        true,
+						RepoVisibility: "all",
+						TimeWindow:     config.Hours(24),
+						RepoOverrides: []config.PRCheckerOverride{
+							{Repository: "acme/payments", TimeWindow: config.Hours(72)},
+							{Repository: "Acme/Payments", ExcludedAuthors: []string{"renovate"}},
+						},
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "overridden more than once",
+		},
+		{
+			name: "PR checker override with negative required approvals",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:        true,
+						RepoVisibility: "all",
+						TimeWindow:     config.Hours(24),
+						RepoOverrides:  []config.PRCheckerOverride{{Repository: "acme/payments", RequiredApprovals: &negative}},
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "required approvals of repository acme/payments",
+		},
+		{
+			name: "PR checker page size above the maximum",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:        true,
+						RepoVisibility: "all",
+						TimeWindow:     config.Hours(24),
+						Pagination:     config.PaginationConfig{PageSize: 500},
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "page size for pr_checker monitor must be between 1 and 100",
+		},
+		{
+			name: "Negative open PR checker max pages",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						TimeWindow: config.Hours(24),
+					},
+					OpenPRChecker: config.OpenPRCheckerConfig{
+						Enabled:      true,
+						Repositories: []string{"acme/api"},
+						MinAge:       config.Hours(24),
+						Pagination:   config.PaginationConfig{MaxPages: -1},
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "max pages for open_pr_checker monitor must not be negative",
+		},
+		{
+			name: "PR checker status checks page size above the maximum",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:                true,
+						RepoVisibility:         "all",
+						TimeWindow:             config.Hours(24),
+						StatusChecksPagination: config.PaginationConfig{PageSize: 500},
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "page size for status checks of pr_checker monitor must be between 1 and 100",
+		},
+		{
+			name: "Negative fork workflow approvals max pages",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						TimeWindow: config.Hours(24),
+					},
+					ForkRuns: config.ForkRunsConfig{
+						Enabled:      true,
+						Repositories: []string{"acme/api"},
+						CheckWindow:  config.Hours(24),
+						Pagination:   config.PaginationConfig{MaxPages: -1},
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "max pages for fork_workflow_approvals monitor must not be negative",
+		},
+		{
+			name: "Negative PR checker out of window threshold",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:              true,
+						RepoVisibility:       "all",
+						TimeWindow:           config.Hours(24),
+						OutOfWindowThreshold: -1,
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "out of window threshold",
+		},
+		{
+			name: "Invalid PR checker ticket key",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:        true,
+						RepoVisibility: "all",
+						TimeWindow:     config.Hours(24),
+						RequireTicket:  true,
+						RepoTicketKeys: map[string][]string{"acme/payments": {"PAY-"}},
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid ticket key for PR checker",
+		},
+		{
+			name: "Invalid PR checker reviewer team",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:           true,
+						RepoVisibility:    "all",
+						TimeWindow:        config.Hours(24),
+						RepoReviewerTeams: map[string][]string{"acme/payments": {"security"}},
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid reviewer team for PR checker: security",
+		},
+		{
+			name: "Empty PR checker base branch",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:        true,
+						RepoVisibility: "all",
+						TimeWindow:     config.Hours(24),
+						BaseBranches:   []string{"main", " "},
+					},
+				},
+			},
+			expectError:   true,
+			errorContains: "empty branch in PR checker base_branches",
+		},
+		{
+			name: "Repository filters with minimum size above maximum",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				RepoFilters: config.Filters{
+					MinSizeKB: 2048,
+					MaxSizeKB: 1024,
+				},
+			},
+			expectError:   true,
+			errorContains: "min_size_kb must not exceed max_size_kb",
+		},
+		{
+			name: "Heartbeat start URL without URL",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Heartbeat: config.HeartbeatConfig{
+					StartURL: "https://hc-ping.com/uuid/start",
+				},
+			},
+			expectError:   true,
+			errorContains: "heartbeat url must be specified with start_url or fail_url",
+		},
+		{
+			name: "Heartbeat URL without scheme",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Heartbeat: config.HeartbeatConfig{
+					URL: "hc-ping.com/uuid",
+				},
+			},
+			expectError:   true,
+			errorContains: "heartbeat url must be an HTTP(S) URL",
+		},
+		{
+			name: "Projects without number",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Projects: config.ProjectsConfig{
+					Enabled:     true,
+					Owner:       "example-org",
+					StatusField: "Status",
+					OpenStatus:  "Todo",
+					DoneStatus:  "Done",
+				},
+			},
+			expectError:   true,
+			errorContains: "projects owner and number must be specified",
+		},
+		{
+			name: "Projects with the same open and done status",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Projects: config.ProjectsConfig{
+					Enabled:     true,
+					Owner:       "example-org",
+					Number:      3,
+					StatusField: "Status",
+					OpenStatus:  "Done",
+					DoneStatus:  "done",
+				},
+			},
+			expectError:   true,
+			errorContains: "projects open_status and done_status must differ",
+		},
+		{
+			name: "ServiceNow without password",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				ServiceNow: config.ServiceNowConfig{
+					Enabled:     true,
+					InstanceURL: "https://example.service-now.com",
+					Table:       "incident",
+					Username:    "git-monitor",
+					MinSeverity: "critical",
+				},
+			},
+			expectError:   true,
+			errorContains: "servicenow username and password must be specified",
+		},
+		{
+			name: "ServiceNow with low min severity",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				ServiceNow: config.ServiceNowConfig{
+					Enabled:     true,
+					InstanceURL: "https://example.service-now.com",
+					Table:       "incident",
+					Username:    "git-monitor",
+					Password:    "secret",
+					MinSeverity: "low",
+				},
+			},
+			expectError:   true,
+			errorContains: "invalid servicenow min_severity: low",
+		},
+		{
+			name: "Event sinks without state",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Events: config.EventsConfig{
+					Source: "git-monitor",
+					PubSub: config.PubSubConfig{Enabled: true, Project: "security", Topic: "git-monitor"},
+				},
+			},
+			expectError:   true,
+			errorContains: "event sinks require state to be enabled",
+		},
+		{
+			name: "Event Grid without key",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				State: config.StateConfig{Enabled: true, Path: "state.json"},
+				Events: config.EventsConfig{
+					Source:    "git-monitor",
+					EventGrid: config.EventGridConfig{Enabled: true, Endpoint: "https://topic.westeurope-1.eventgrid.azure.net/api/events"},
+				},
+			},
+			expectError:   true,
+			errorContains: "event_grid key must be specified",
+		},
+		{
+			name: "Pub/Sub without topic",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				State: config.StateConfig{Enabled: true, Path: "state.json"},
+				Events: config.EventsConfig{
+					Source: "git-monitor",
+					PubSub: config.PubSubConfig{Enabled: true, Project: "security"},
+				},
+			},
+			expectError:   true,
+			errorContains: "pubsub project and topic must be specified",
+		},
+		{
+			name: "Encryption with key and KMS data key",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Encryption: config.EncryptionConfig{
+					Enabled:    true,
+					Key:        "BwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwc=",
+					KMSDataKey: "d3JhcHBlZA==",
+				},
+			},
+			expectError:   true,
+			errorContains: "exactly one of encryption key and kms_data_key must be specified",
+		},
+		{
+			name: "Encryption with a key of invalid length",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Encryption: config.EncryptionConfig{
+					Enabled: true,
+					Key:     "c2hvcnQ=",
+				},
+			},
+			expectError:   true,
+			errorContains: "encryption key must be a base64 encoded key of 16, 24 or 32 bytes",
+		},
+		{
+			name: "Valid projects",
+			config: &config.Config{
+				GitHub: config.GitHubConfig{
+					Token: "valid-token",
+				},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:    false,
+						TimeWindow: config.Hours(24),
+					},
+				},
+				Projects: config.ProjectsConfig{
+					Enabled:     true,
+					Owner:       "example-org",
+					Number:      3,
+					StatusField: "Status",
+					OpenStatus:  "Todo",
+					DoneStatus:  "Done",
+					GraphQLURL:  "https://github.example.com/api/graphql",
+				},
+			},
+			expectError: false,
+		},
 	}
 
 	for _, tc := range tests {
@@ -331,8 +2047,8 @@ token = "test-token"
 		t.Errorf("Expected specific_repositories to be [\"owner/repo\"], got %v", cfg.Monitors.PRChecker.SpecificRepositories)
 	}
 
-	if cfg.Monitors.PRChecker.TimeWindow != 24 {
-		t.Errorf("Expected time window to be 24, got %d", cfg.Monitors.PRChecker.TimeWindow)
+	if cfg.Monitors.PRChecker.TimeWindow.Duration != 24*time.Hour {
+		t.Errorf("Expected time window to be 24h, got %v", cfg.Monitors.PRChecker.TimeWindow)
 	}
 
 	// Verify repository visibility configuration
@@ -348,8 +2064,13 @@ token = "test-token"
 		t.Errorf("Expected organizations to be [\"test-org\"], got %v", cfg.Monitors.RepoVisibility.Organizations)
 	}
 
-	if cfg.Monitors.RepoVisibility.CheckWindow != 48 {
-		t.Errorf("Expected check window to be 48, got %d", cfg.Monitors.RepoVisibility.CheckWindow)
+	if cfg.Monitors.RepoVisibility.CheckWindow.Duration != 48*time.Hour {
+		t.Errorf("Expected check window to be 48h, got %v", cfg.Monitors.RepoVisibility.CheckWindow)
+	}
+
+	// Archived repositories are skipped unless configured otherwise
+	if !cfg.RepoFilters.ExcludeArchived {
+		t.Error("Expected exclude_archived to be enabled by default")
 	}
 }
 
@@ -403,6 +2124,148 @@ token = ""
 	}
 }
 
+func TestLoadConfigDurations(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expected    time.Duration
+		expectError bool
+	}{
+		{name: "Integer hours", value: `36`, expected: 36 * time.Hour},
+		{name: "Go duration string", value: `"90m"`, expected: 90 * time.Minute},
+		{name: "Days", value: `"7d"`, expected: 7 * 24 * time.Hour},
+		{name: "Invalid string", value: `"soon"`, expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			content := `
+[github]
+token = "test-token"
+
+[monitors]
+  [monitors.code_scanning_dismissals]
+  check_window_hours = ` + tc.value + `
+`
+
+			tempFile, err := os.CreateTemp("", "config-*.toml")
+			if err != nil {
+				t.Fatalf("Failed to create temp file: %v", err)
+			}
+			defer os.Remove(tempFile.Name())
+
+			if _, err := tempFile.Write([]byte(content)); err != nil {
+				t.Fatalf("Failed to write to temp file: %v", err)
+			}
+
+			if err := tempFile.Close(); err != nil {
+				t.Fatalf("Failed to close temp file: %v", err)
+			}
+
+			cfg, err := config.LoadConfig(tempFile.Name())
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected an error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to load config: %v", err)
+			}
+
+			if cfg.Monitors.CodeScanning.CheckWindow.Duration != tc.expected {
+				t.Errorf("Expected check window %v, got %v", tc.expected, cfg.Monitors.CodeScanning.CheckWindow)
+			}
+		})
+	}
+}
+
+func TestLoadConfigSLADeadlines(t *testing.T) {
+	content := `
+[github]
+token = "test-token"
+
+[sla]
+enabled = true
+
+[sla.deadlines]
+repo_visibility = "4h"
+"pr_checker.unapproved" = "3d"
+dormant_repositories = 720
+`
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	expected := map[string]time.Duration{
+		"repo_visibility":       4 * time.Hour,
+		"pr_checker.unapproved": 3 * 24 * time.Hour,
+		"dormant_repositories":  720 * time.Hour,
+	}
+	for key, deadline := range expected {
+		if got := cfg.SLA.Deadlines[key].Duration; got != deadline {
+			t.Errorf("Expected the SLA of %s to be %v, got %v", key, deadline, got)
+		}
+	}
+}
+
+func TestLoadConfigPRCheckerOverrides(t *testing.T) {
+	content := `
+[github]
+token = "test-token"
+
+[monitors.pr_checker]
+enabled = true
+repo_visibility = "all"
+organization = "acme"
+excluded_authors = ["renovate"]
+
+[[monitors.pr_checker.repo_overrides]]
+repository = "acme/payments"
+time_window_hours = "7d"
+required_approvals = 0
+exclude_bots = false
+
+[[monitors.pr_checker.repo_overrides]]
+repository = "acme/docs"
+excluded_authors = []
+`
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	overrides := cfg.Monitors.PRChecker.RepoOverrides
+	if len(overrides) != 2 {
+		t.Fatalf("Expected 2 repository overrides, got %+v", overrides)
+	}
+	payments, docs := overrides[0], overrides[1]
+	if payments.TimeWindow.Duration != 7*24*time.Hour {
+		t.Errorf("Expected a time window of 7 days, got %v", payments.TimeWindow)
+	}
+	// Settings set to their zero value override the global ones, settings left out do not
+	if payments.RequiredApprovals == nil || *payments.RequiredApprovals != 0 || payments.ExcludeBots == nil || *payments.ExcludeBots {
+		t.Errorf("Expected required approvals and exclude_bots set to zero values, got %+v", payments)
+	}
+	if payments.ExcludedAuthors != nil {
+		t.Errorf("Expected no excluded authors override, got %v", payments.ExcludedAuthors)
+	}
+	if docs.ExcludedAuthors == nil || len(docs.ExcludedAuthors) != 0 || docs.RequiredApprovals != nil || docs.TimeWindow.Duration != 0 {
+		t.Errorf("Expected only an empty excluded authors override, got %+v", docs)
+	}
+}
+
 func TestLoadConfigFileNotFound(t *testing.T) {
 	_, err := config.LoadConfig("non-existent-file.toml")
 	if err == nil {
@@ -484,7 +2347,7 @@ func TestValidateRepoVisibility(t *testing.T) {
 						RepoVisibility: tc.repoVisibility,
 						// Add valid repositories for specific so the validation passes that part
 						SpecificRepositories: []string{"owner/repo"},
-						TimeWindow:           24,
+						TimeWindow:           config.Hours(24),
 					},
 				},
 			}
@@ -501,3 +2364,319 @@ func TestValidateRepoVisibility(t *testing.T) {
 		})
 	}
 }
+
+func TestSecrets(t *testing.T) {
+	cfg := &config.Config{
+		GitHub:     config.GitHubConfig{Token: "ghp_main"},
+		ServiceNow: config.ServiceNowConfig{Password: "servicenow-password"},
+		Ownership:  config.OwnershipConfig{Webhooks: map[string]string{"payments": "https://hooks.slack.com/services/T/B/payments"}},
+		Escalation: config.EscalationConfig{Policies: []config.EscalationPolicy{{Webhook: "https://hooks.slack.com/services/T/B/managers"}}},
+		Accounts:   []config.AccountConfig{{Name: "acme", Token: "ghp_acme"}},
+	}
+
+	secrets := strings.Join(cfg.Secrets(), "\n")
+	for _, want := range []string{"ghp_main", "servicenow-password", "https://hooks.slack.com/services/T/B/payments",
+		"https://hooks.slack.com/services/T/B/managers", "ghp_acme"} {
+		if !strings.Contains(secrets, want) {
+			t.Errorf("Expected the secrets to contain %q", want)
+		}
+	}
+}
+
+func TestScopeToRepositories(t *testing.T) {
+	cfg := &config.Config{
+		Timezone: "UTC",
+		Monitors: config.MonitorsConfig{
+			PRChecker: config.PRCheckerConfig{
+				Enabled:              true,
+				RepoVisibility:       "all",
+				Organization:         "test-org",
+				ExcludedRepositories: []string{"test-org/excluded"},
+			},
+			RepoVisibility: config.RepoVisibilityConfig{
+				Enabled:       true,
+				Organizations: []string{"test-org"},
+			},
+			CodeScanning: config.CodeScanningConfig{
+				Enabled:       true,
+				Organizations: []string{"test-org"},
+				Repositories:  []string{"other/repo"},
+			},
+		},
+	}
+
+	scoped := cfg.ScopeToRepositories([]string{"test-org/repo"})
+
+	if scoped.Monitors.PRChecker.RepoVisibility != "specific" {
+		t.Errorf("Expected PR checker visibility to be specific, got %q", scoped.Monitors.PRChecker.RepoVisibility)
+	}
+	if len(scoped.Monitors.PRChecker.SpecificRepositories) != 1 || scoped.Monitors.PRChecker.SpecificRepositories[0] != "test-org/repo" {
+		t.Errorf("Expected PR checker to check only test-org/repo, got %v", scoped.Monitors.PRChecker.SpecificRepositories)
+	}
+	if scoped.WindowLocation() != nil || cfg.WindowLocation() == nil {
+		t.Error("Expected only the check windows of the scoped configuration to end now")
+	}
+	if scoped.Monitors.RepoVisibility.Enabled {
+		t.Error("Expected repository visibility monitor to be disabled in a scoped configuration")
+	}
+	if len(scoped.Monitors.CodeScanning.Organizations) != 0 {
+		t.Errorf("Expected code scanning organizations to be dropped, got %v", scoped.Monitors.CodeScanning.Organizations)
+	}
+	if len(scoped.Monitors.CodeScanning.Repositories) != 1 || scoped.Monitors.CodeScanning.Repositories[0] != "test-org/repo" {
+		t.Errorf("Expected code scanning to check only test-org/repo, got %v", scoped.Monitors.CodeScanning.Repositories)
+	}
+
+	// The original configuration is left untouched
+	if cfg.Monitors.PRChecker.RepoVisibility != "all" || !cfg.Monitors.RepoVisibility.Enabled {
+		t.Error("Expected the original configuration to be unchanged")
+	}
+	if cfg.Monitors.CodeScanning.Repositories[0] != "other/repo" {
+		t.Errorf("Expected original code scanning repositories to be unchanged, got %v", cfg.Monitors.CodeScanning.Repositories)
+	}
+
+	// Monitors only check the requested repositories among their own targets, and keep their exclusions
+	scoped = cfg.ScopeToRepositories([]string{"test-org/excluded", "other/repo", "stranger/repo"})
+	if scoped.Monitors.PRChecker.Enabled {
+		t.Errorf("Expected the PR checker to be disabled without repositories to check, got %v", scoped.Monitors.PRChecker.SpecificRepositories)
+	}
+	if got := scoped.Monitors.CodeScanning.Repositories; len(got) != 2 || got[0] != "test-org/excluded" || got[1] != "other/repo" {
+		t.Errorf("Expected code scanning to check its own targets only, got %v", got)
+	}
+	if len(scoped.Monitors.PRChecker.ExcludedRepositories) != 1 {
+		t.Errorf("Expected the PR checker exclusions to be kept, got %v", scoped.Monitors.PRChecker.ExcludedRepositories)
+	}
+}
+
+func TestInScope(t *testing.T) {
+	cfg := &config.Config{
+		RepoFilters: config.Filters{Exclusions: []string{"acme/secret"}},
+		Monitors: config.MonitorsConfig{
+			PRChecker: config.PRCheckerConfig{
+				Enabled:              true,
+				RepoVisibility:       "all",
+				Organization:         "acme",
+				ExcludedRepositories: []string{"acme/sandbox"},
+			},
+			CodeScanning: config.CodeScanningConfig{
+				Enabled:      true,
+				Repositories: []string{"partner/shared"},
+			},
+			Dependabot: config.DependabotConfig{
+				Organizations: []string{"disabled-org"},
+			},
+		},
+	}
+
+	tests := []struct {
+		repository string
+		expected   bool
+	}{
+		{"acme/api", true},
+		{"ACME/Api", true},
+		{"partner/shared", true},
+		{"partner/other", false},
+		{"acme/sandbox", false},
+		{"acme/secret", false},
+		{"disabled-org/repo", false},
+		{"stranger/repo", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.repository, func(t *testing.T) {
+			if got := cfg.InScope(tc.repository); got != tc.expected {
+				t.Errorf("Expected %s in scope %v, got %v", tc.repository, tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestValidateServer(t *testing.T) {
+	tests := []struct {
+		name        string
+		server      config.ServerConfig
+		expectError bool
+	}{
+		{"Valid", config.ServerConfig{Listen: ":8080", MaxRuns: 100}, false},
+		{"Missing listen address", config.ServerConfig{MaxRuns: 100}, true},
+		{"Invalid max runs", config.ServerConfig{Listen: ":8080"}, true},
+		{"Valid gRPC listen address", config.ServerConfig{Listen: ":8080", GRPCListen: ":9090", MaxRuns: 100}, false},
+		{"Shared gRPC listen address", config.ServerConfig{Listen: ":8080", GRPCListen: ":8080", MaxRuns: 100}, true},
+		{"Slack app with signing secret", config.ServerConfig{Listen: ":8080", MaxRuns: 100, Slack: config.SlackAppConfig{Enabled: true, SigningSecret: "secret"}}, false},
+		{"Slack app without signing secret", config.ServerConfig{Listen: ":8080", MaxRuns: 100, Slack: config.SlackAppConfig{Enabled: true}}, true},
+		{"GitHub webhook with secret", config.ServerConfig{Listen: ":8080", MaxRuns: 100, GitHubWebhook: config.GitHubWebhookConfig{Enabled: true, Secret: "secret"}}, false},
+		{"GitHub webhook without secret", config.ServerConfig{Listen: ":8080", MaxRuns: 100, GitHubWebhook: config.GitHubWebhookConfig{Enabled: true}}, true},
+		{"Rate budget with shares", config.ServerConfig{Listen: ":8080", MaxRuns: 100, RateBudget: config.RateBudgetConfig{Enabled: true, HourlyCalls: 4500, Shares: map[string]float64{"pr_checker": 60, "rulesets": 20}}}, false},
+		{"Rate budget without hourly calls", config.ServerConfig{Listen: ":8080", MaxRuns: 100, RateBudget: config.RateBudgetConfig{Enabled: true}}, true},
+		{"Rate budget share of unknown monitor", config.ServerConfig{Listen: ":8080", MaxRuns: 100, RateBudget: config.RateBudgetConfig{Enabled: true, HourlyCalls: 4500, Shares: map[string]float64{"unknown": 10}}}, true},
+		{"Rate budget shares over 100", config.ServerConfig{Listen: ":8080", MaxRuns: 100, RateBudget: config.RateBudgetConfig{Enabled: true, HourlyCalls: 4500, Shares: map[string]float64{"pr_checker": 60, "rulesets": 50}}}, true},
+		{"Leader election with a Kubernetes lease", config.ServerConfig{Listen: ":8080", MaxRuns: 100, LeaderElection: config.LeaderElectionConfig{Enabled: true, Backend: "kubernetes", LeaseName: "git-monitor", Duration: config.Duration{Duration: 30 * time.Second}}}, false},
+		{"Leader election in the state without state", config.ServerConfig{Listen: ":8080", MaxRuns: 100, LeaderElection: config.LeaderElectionConfig{Enabled: true, Backend: "state", Duration: config.Duration{Duration: 30 * time.Second}}}, true},
+		{"Leader election with an unknown backend", config.ServerConfig{Listen: ":8080", MaxRuns: 100, LeaderElection: config.LeaderElectionConfig{Enabled: true, Backend: "etcd", Duration: config.Duration{Duration: 30 * time.Second}}}, true},
+		{"Leader election with a short lease", config.ServerConfig{Listen: ":8080", MaxRuns: 100, LeaderElection: config.LeaderElectionConfig{Enabled: true, Backend: "kubernetes", LeaseName: "git-monitor", Duration: config.Duration{Duration: time.Second}}}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &config.Config{Server: tc.server}
+			err := cfg.ValidateServer()
+
+			if tc.expectError && err == nil {
+				t.Error("Expected validation error but got nil")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("Expected no validation error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestAccounts(t *testing.T) {
+	os.Setenv("GLOBEX_GITHUB_TOKEN", "globex-token")
+	defer os.Unsetenv("GLOBEX_GITHUB_TOKEN")
+
+	content := `
+[[accounts]]
+name = "acme"
+token = "acme-token"
+
+  [accounts.monitors.repo_visibility]
+  enabled = true
+  organizations = ["acme"]
+
+[[accounts]]
+name = "globex"
+token_env = "GLOBEX_GITHUB_TOKEN"
+
+  [accounts.monitors.pr_checker]
+  enabled = true
+  specific_repositories = ["globex/api"]
+`
+
+	tempFile, err := os.CreateTemp("", "config-*.toml")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer os.Remove(tempFile.Name())
+
+	if _, err := tempFile.Write([]byte(content)); err != nil {
+		t.Fatalf("Failed to write to temp file: %v", err)
+	}
+	if err := tempFile.Close(); err != nil {
+		t.Fatalf("Failed to close temp file: %v", err)
+	}
+
+	cfg, err := config.LoadConfig(tempFile.Name())
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	accounts := cfg.AccountConfigs()
+	if len(accounts) != 2 {
+		t.Fatalf("Expected 2 account configurations, got %d", len(accounts))
+	}
+
+	acme, globex := accounts[0], accounts[1]
+	if acme.Account != "acme" || acme.GitHub.Token != "acme-token" || !acme.Monitors.RepoVisibility.Enabled {
+		t.Errorf("Unexpected configuration for acme: %+v", acme)
+	}
+	if acme.Monitors.RepoVisibility.CheckWindow.Duration != 24*time.Hour {
+		t.Errorf("Expected account monitors to get the default check window, got %v", acme.Monitors.RepoVisibility.CheckWindow.Duration)
+	}
+	if globex.Account != "globex" || globex.GitHub.Token != "globex-token" {
+		t.Errorf("Expected globex to use the token from GLOBEX_GITHUB_TOKEN, got %q", globex.GitHub.Token)
+	}
+	if !globex.Monitors.PRChecker.Enabled || globex.Monitors.PRChecker.RepoVisibility != "specific" {
+		t.Errorf("Unexpected PR checker configuration for globex: %+v", globex.Monitors.PRChecker)
+	}
+	if len(globex.Accounts) != 0 || globex.Monitors.RepoVisibility.Enabled {
+		t.Error("Expected account configurations to only contain their own monitors")
+	}
+
+	// Scoping applies to every account
+	scoped := cfg.ScopeToRepositories([]string{"globex/api"})
+	for _, account := range scoped.AccountConfigs() {
+		if account.Monitors.RepoVisibility.Enabled {
+			t.Errorf("Expected repository visibility to be disabled for %s in a scoped configuration", account.Account)
+		}
+	}
+	if !cfg.Accounts[0].Monitors.RepoVisibility.Enabled {
+		t.Error("Expected the original account configuration to be unchanged")
+	}
+
+	// Configurations without accounts are their own only account
+	single := &config.Config{GitHub: config.GitHubConfig{Token: "token"}}
+	if got := single.AccountConfigs(); len(got) != 1 || got[0] != single {
+		t.Errorf("Expected the configuration itself, got %v", got)
+	}
+}
+
+func TestValidateAccounts(t *testing.T) {
+	account := func(name, token string) config.AccountConfig {
+		return config.AccountConfig{
+			Name:  name,
+			Token: token,
+			Monitors: config.MonitorsConfig{
+				RepoVisibility: config.RepoVisibilityConfig{Enabled: true, Organizations: []string{name}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		config        *config.Config
+		errorContains string
+	}{
+		{
+			name:          "Top-level monitors with accounts",
+			config:        &config.Config{Accounts: []config.AccountConfig{account("acme", "token")}, Monitors: config.MonitorsConfig{Rulesets: config.RulesetsConfig{Enabled: true}}},
+			errorContains: "monitors must be configured per account",
+		},
+		{
+			name:          "Missing name",
+			config:        &config.Config{Accounts: []config.AccountConfig{account("", "token")}},
+			errorContains: "name must be specified",
+		},
+		{
+			name:          "Duplicate name",
+			config:        &config.Config{Accounts: []config.AccountConfig{account("acme", "token"), account("acme", "other")}},
+			errorContains: "duplicate account name: acme",
+		},
+		{
+			name:          "Missing token",
+			config:        &config.Config{Accounts: []config.AccountConfig{account("acme", "")}},
+			errorContains: "token is required for account acme",
+		},
+		{
+			name: "Invalid account monitors",
+			config: &config.Config{Accounts: []config.AccountConfig{{
+				Name:     "acme",
+				Token:    "token",
+				Monitors: config.MonitorsConfig{RepoVisibility: config.RepoVisibilityConfig{Enabled: true}},
+			}}},
+			errorContains: "account acme:",
+		},
+		{
+			name: "Projects without a token of their own",
+			config: &config.Config{
+				Accounts: []config.AccountConfig{{
+					Name:     "acme",
+					Token:    "token",
+					Monitors: config.MonitorsConfig{PRChecker: config.PRCheckerConfig{TimeWindow: config.Hours(24)}},
+				}},
+				Projects: config.ProjectsConfig{Enabled: true, Owner: "acme", Number: 3, StatusField: "Status", OpenStatus: "Todo", DoneStatus: "Done"},
+			},
+			errorContains: "projects token must be specified",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.Validate()
+			if err == nil || !strings.Contains(err.Error(), tc.errorContains) {
+				t.Errorf("Expected error containing %q, got %v", tc.errorContains, err)
+			}
+		})
+	}
+}
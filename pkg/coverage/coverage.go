@@ -0,0 +1,176 @@
+package coverage
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+// Monitor is the coverage of a monitor run: the targets it checked and those it skipped when the scan stopped early
+type Monitor struct {
+	Monitor string                 `json:"monitor"`
+	Account string                 `json:"account,omitempty"`
+	Checked int                    `json:"checked"` // Targets checked, including those that failed
+	Errored int                    `json:"errored"` // Targets checked whose check failed
+	Skipped []common.SkippedTarget `json:"skipped"` // Targets not checked, whose findings are missing
+	// Archived repositories left out by exclude_archived
+	Archived int `json:"archived"`
+	// Repositories left out of the sample of the run, checked by other rounds of the rotation
+	Unsampled int `json:"unsampled"`
+	// Repositories left to the other shards of the scan, checked by their runs
+	OtherShards int `json:"other_shards"`
+}
+
+// New records the coverage of a monitor run
+func New(monitor, account string, c common.Coverage) Monitor {
+	skipped := c.Skipped
+	if skipped == nil {
+		skipped = []common.SkippedTarget{}
+	}
+	return Monitor{Monitor: monitor, Account: account, Checked: len(c.Checked), Errored: len(c.Errored), Skipped: skipped, Archived: c.Archived, Unsampled: c.Unsampled, OtherShards: c.OtherShards}
+}
+
+// Merge combines the coverage of the shards of a monitor run, see the merge-reports command
+// The repositories each shard left to the others were checked by them, so they are not counted
+func Merge(shards []Monitor) Monitor {
+	merged := Monitor{Skipped: []common.SkippedTarget{}}
+	for i, m := range shards {
+		if i == 0 {
+			merged.Monitor, merged.Account = m.Monitor, m.Account
+		}
+		merged.Checked += m.Checked
+		merged.Errored += m.Errored
+		merged.Skipped = append(merged.Skipped, m.Skipped...)
+		merged.Archived += m.Archived
+		merged.Unsampled += m.Unsampled
+	}
+	return merged
+}
+
+// Complete reports whether the monitor checked all of its targets
+func (m Monitor) Complete() bool {
+	return len(m.Skipped) == 0
+}
+
+// Partial reports whether any monitor skipped targets
+func Partial(monitors []Monitor) bool {
+	for _, m := range monitors {
+		if !m.Complete() {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteMarkdown writes the checked and skipped targets in a code block format suitable for Slack,
+// when any monitor skipped targets
+// Target names are passed through name, e.g. to redact them, when it is not nil
+func WriteMarkdown(w io.Writer, monitors []Monitor, name func(string) string) {
+	if !Partial(monitors) {
+		return // All targets were checked
+	}
+
+	if name == nil {
+		name = func(target string) string { return target }
+	}
+
+	checked, skipped := 0, 0
+	reasons := make(map[string]bool)
+	for _, m := range monitors {
+		checked += m.Checked
+		skipped += len(m.Skipped)
+		for _, s := range m.Skipped {
+			reasons[s.Reason] = true
+		}
+	}
+	reasonList := make([]string, 0, len(reasons))
+	for reason := range reasons {
+		reasonList = append(reasonList, reason)
+	}
+	sort.Strings(reasonList)
+
+	// Print header for partial results
+	fmt.Fprintln(w, "## :hourglass: Partial Results")
+	fmt.Fprintf(w, "The scan stopped early (%s). %d targets were checked and %d skipped, the findings of skipped targets are not included.\n\n",
+		strings.Join(reasonList, ", "), checked, skipped)
+
+	// Start code block for completed coverage
+	fmt.Fprintln(w, "```")
+	// Create fixed-width headers with proper spacing for code block
+	fmt.Fprintln(w, "Monitor                   Checked  Skipped  Coverage")
+	fmt.Fprintln(w, "---------------------------------------------------------")
+	for _, m := range monitors {
+		status := "complete"
+		if !m.Complete() {
+			status = "partial"
+		}
+		fmt.Fprintf(w, "%-25s %-8d %-8d %s\n", label(m), m.Checked, len(m.Skipped), status)
+	}
+	// End code block
+	fmt.Fprintln(w, "```")
+
+	// Start code block for skipped targets
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "Monitor                   Status              Target")
+	fmt.Fprintln(w, "---------------------------------------------------------------")
+	for _, m := range monitors {
+		for _, s := range m.Skipped {
+			fmt.Fprintf(w, "%-25s %-19s %s\n", label(m), "skipped ("+s.Reason+")", name(s.Target))
+		}
+	}
+	// End code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
+
+// Archived returns how many archived repositories the monitors left out
+func Archived(monitors []Monitor) int {
+	archived := 0
+	for _, m := range monitors {
+		archived += m.Archived
+	}
+	return archived
+}
+
+// Unsampled returns how many repositories the monitors left out of the sample of the run
+func Unsampled(monitors []Monitor) int {
+	unsampled := 0
+	for _, m := range monitors {
+		unsampled += m.Unsampled
+	}
+	return unsampled
+}
+
+// OtherShards returns how many repositories the monitors left to the other shards of the scan
+func OtherShards(monitors []Monitor) int {
+	otherShards := 0
+	for _, m := range monitors {
+		otherShards += m.OtherShards
+	}
+	return otherShards
+}
+
+// WriteNotesMarkdown notes the repositories the monitors left out by design rather than because the scan
+// stopped: archived repositories and those outside the shard or sample of the run
+func WriteNotesMarkdown(w io.Writer, monitors []Monitor) {
+	if archived := Archived(monitors); archived > 0 {
+		fmt.Fprintf(w, "_%d archived repositories were skipped (exclude_archived)._\n\n", archived)
+	}
+	if unsampled := Unsampled(monitors); unsampled > 0 {
+		fmt.Fprintf(w, "_Sampled run: %d repositories were left out of this run's sample (--sample)._\n\n", unsampled)
+	}
+	if otherShards := OtherShards(monitors); otherShards > 0 {
+		fmt.Fprintf(w, "_Sharded run: %d repositories were left to the other shards of the scan (--shard)._\n\n", otherShards)
+	}
+}
+
+// label names a monitor run, with its account when there is one
+func label(m Monitor) string {
+	if m.Account != "" {
+		return fmt.Sprintf("%s (%s)", m.Monitor, m.Account)
+	}
+	return m.Monitor
+}
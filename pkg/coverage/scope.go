@@ -0,0 +1,141 @@
+package coverage
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+// Scope is what a monitor run scanned, compared with the scope of the monitor's previous run
+type Scope struct {
+	Monitor string
+	Account string
+	Scanned []string // Targets checked successfully
+	Errored []string // Targets checked whose check failed
+	Skipped []string // Targets not checked because the scan stopped early
+	// Targets in the scope of this run but not of the previous one, e.g. new repositories
+	Discovered []string
+	// Targets in the scope of the previous run but not of this one, e.g. deleted or no longer accessible repositories
+	Disappeared []string
+	Compared    bool // Whether the scope of the previous run was known
+}
+
+// NewScope compares the targets of a monitor run with those of its previous run
+// Targets checked or skipped are in the scope of the run, so skipped targets are neither discovered nor gone
+func NewScope(monitor, account string, c common.Coverage, previous []string, compared bool) Scope {
+	scope := Scope{Monitor: monitor, Account: account, Errored: c.Errored, Compared: compared}
+	failed := make(map[string]bool, len(c.Errored))
+	for _, target := range c.Errored {
+		failed[target] = true
+	}
+	for _, target := range c.Checked {
+		if !failed[target] {
+			scope.Scanned = append(scope.Scanned, target)
+		}
+	}
+	for _, s := range c.Skipped {
+		scope.Skipped = append(scope.Skipped, s.Target)
+	}
+
+	if compared {
+		current := Targets(c)
+		before := make(map[string]bool, len(previous))
+		for _, target := range previous {
+			before[target] = true
+		}
+		now := make(map[string]bool, len(current))
+		for _, target := range current {
+			now[target] = true
+			if !before[target] {
+				scope.Discovered = append(scope.Discovered, target)
+			}
+		}
+		for _, target := range previous {
+			if !now[target] {
+				scope.Disappeared = append(scope.Disappeared, target)
+			}
+		}
+	}
+	return scope
+}
+
+// Targets returns the targets in the scope of a run, those checked and those skipped, sorted
+func Targets(c common.Coverage) []string {
+	seen := make(map[string]bool, len(c.Checked)+len(c.Skipped))
+	targets := make([]string, 0, len(c.Checked)+len(c.Skipped))
+	add := func(target string) {
+		if !seen[target] {
+			seen[target] = true
+			targets = append(targets, target)
+		}
+	}
+	for _, target := range c.Checked {
+		add(target)
+	}
+	for _, s := range c.Skipped {
+		add(s.Target)
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// WriteScopeMarkdown writes what each monitor scanned, and the targets that were not scanned or changed since
+// the previous run, in a code block format suitable for Slack
+// Target names are passed through name, e.g. to redact them, when it is not nil
+func WriteScopeMarkdown(w io.Writer, scopes []Scope, name func(string) string) {
+	if len(scopes) == 0 {
+		return // No monitors ran
+	}
+
+	if name == nil {
+		name = func(target string) string { return target }
+	}
+
+	fmt.Fprintln(w, "## :satellite: Scan Coverage")
+	fmt.Fprintln(w, "Targets each monitor scanned, and those it did not or that changed since the previous run.")
+	fmt.Fprintln(w, "")
+
+	// Start code block for the summary
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "Monitor                   Scanned  Errored  Skipped  New    Gone")
+	fmt.Fprintln(w, "--------------------------------------------------------------------")
+	for _, s := range scopes {
+		discovered, disappeared := "-", "-"
+		if s.Compared {
+			discovered, disappeared = fmt.Sprint(len(s.Discovered)), fmt.Sprint(len(s.Disappeared))
+		}
+		fmt.Fprintf(w, "%-25s %-8d %-8d %-8d %-6s %s\n", scopeLabel(s), len(s.Scanned), len(s.Errored), len(s.Skipped), discovered, disappeared)
+	}
+	// End code block
+	fmt.Fprintln(w, "```")
+
+	// Start code block for the targets not scanned or changed, if any
+	var rows []string
+	for _, s := range scopes {
+		for _, status := range []struct {
+			label   string
+			targets []string
+		}{{"errored", s.Errored}, {"skipped", s.Skipped}, {"new", s.Discovered}, {"gone", s.Disappeared}} {
+			for _, target := range status.targets {
+				rows = append(rows, fmt.Sprintf("%-25s %-8s %s", scopeLabel(s), status.label, name(target)))
+			}
+		}
+	}
+	if len(rows) > 0 {
+		fmt.Fprintln(w, "```")
+		fmt.Fprintln(w, "Monitor                   Status   Target")
+		fmt.Fprintln(w, "--------------------------------------------------------")
+		for _, row := range rows {
+			fmt.Fprintln(w, row)
+		}
+		fmt.Fprintln(w, "```")
+	}
+	fmt.Fprintln(w, "")
+}
+
+// scopeLabel names the monitor run of a scope, with its account when there is one
+func scopeLabel(s Scope) string {
+	return label(Monitor{Monitor: s.Monitor, Account: s.Account})
+}
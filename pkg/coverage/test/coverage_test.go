@@ -0,0 +1,102 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anupsv/git-monitoring/pkg/coverage"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+func TestCoverage(t *testing.T) {
+	complete := coverage.New("rulesets", "", common.Coverage{Checked: []string{"org:acme", "owner/a", "owner/b"}})
+	if !complete.Complete() || complete.Skipped == nil {
+		t.Errorf("Expected complete coverage with an empty skipped list, got %+v", complete)
+	}
+
+	partial := coverage.New("pr_checker", "acme", common.Coverage{
+		Checked: []string{"owner/a", "owner/b"},
+		Skipped: []common.SkippedTarget{{Target: "owner/repo", Reason: common.StopDeadline}},
+	})
+	if partial.Complete() {
+		t.Errorf("Expected partial coverage, got %+v", partial)
+	}
+
+	if coverage.Partial([]coverage.Monitor{complete}) {
+		t.Errorf("Did not expect complete monitors to be partial")
+	}
+	if !coverage.Partial([]coverage.Monitor{complete, partial}) {
+		t.Errorf("Expected a monitor with skipped targets to make the results partial")
+	}
+
+	// Archived repositories left out are noted without making the results partial
+	archived := coverage.New("dormant_repositories", "", common.Coverage{Checked: []string{"org:acme"}, Archived: 3})
+	if !archived.Complete() || coverage.Archived([]coverage.Monitor{complete, archived}) != 3 {
+		t.Errorf("Expected complete coverage with 3 archived repositories, got %+v", archived)
+	}
+	var buf strings.Builder
+	coverage.WriteNotesMarkdown(&buf, []coverage.Monitor{complete})
+	if buf.Len() != 0 {
+		t.Errorf("Expected no note without archived repositories, got %q", buf.String())
+	}
+	coverage.WriteNotesMarkdown(&buf, []coverage.Monitor{complete, archived})
+	if !strings.Contains(buf.String(), "3 archived repositories were skipped") {
+		t.Errorf("Expected a note of the archived repositories, got %q", buf.String())
+	}
+
+	// Repositories left out of the sample are noted the same way
+	sampled := coverage.New("pr_checker", "", common.Coverage{Checked: []string{"owner/a"}, Unsampled: 9})
+	buf.Reset()
+	coverage.WriteNotesMarkdown(&buf, []coverage.Monitor{sampled})
+	if !sampled.Complete() || !strings.Contains(buf.String(), "9 repositories were left out of this run's sample") {
+		t.Errorf("Expected a note of the unsampled repositories, got %q", buf.String())
+	}
+
+	// Repositories left to other shards are noted, and no longer counted once the shards are merged
+	first := coverage.New("pr_checker", "", common.Coverage{Checked: []string{"org:acme", "owner/a"}, OtherShards: 4})
+	second := coverage.New("pr_checker", "", common.Coverage{
+		Checked:     []string{"org:acme", "owner/b"},
+		Skipped:     []common.SkippedTarget{{Target: "owner/c", Reason: common.StopBudget}},
+		OtherShards: 3,
+	})
+	buf.Reset()
+	coverage.WriteNotesMarkdown(&buf, []coverage.Monitor{first})
+	if !strings.Contains(buf.String(), "4 repositories were left to the other shards") {
+		t.Errorf("Expected a note of the repositories of other shards, got %q", buf.String())
+	}
+	merged := coverage.Merge([]coverage.Monitor{first, second})
+	if merged.Monitor != "pr_checker" || merged.Checked != 4 || len(merged.Skipped) != 1 || merged.OtherShards != 0 {
+		t.Errorf("Expected the merged coverage to add up the shards, got %+v", merged)
+	}
+}
+
+func TestScope(t *testing.T) {
+	run := common.Coverage{
+		Checked: []string{"owner/a", "owner/b", "owner/new"},
+		Errored: []string{"owner/b"},
+		Skipped: []common.SkippedTarget{{Target: "owner/c", Reason: common.StopBudget}},
+	}
+	if targets := coverage.Targets(run); strings.Join(targets, ",") != "owner/a,owner/b,owner/c,owner/new" {
+		t.Errorf("Expected the checked and skipped targets in scope, got %v", targets)
+	}
+
+	scope := coverage.NewScope("pr_checker", "", run, []string{"owner/a", "owner/b", "owner/c", "owner/deleted"}, true)
+	if strings.Join(scope.Scanned, ",") != "owner/a,owner/new" || len(scope.Errored) != 1 || len(scope.Skipped) != 1 {
+		t.Errorf("Expected 2 scanned, 1 errored and 1 skipped target, got %+v", scope)
+	}
+	if strings.Join(scope.Discovered, ",") != "owner/new" || strings.Join(scope.Disappeared, ",") != "owner/deleted" {
+		t.Errorf("Expected owner/new to be discovered and owner/deleted to be gone, got %+v", scope)
+	}
+
+	var buf strings.Builder
+	coverage.WriteScopeMarkdown(&buf, []coverage.Scope{scope, coverage.NewScope("rulesets", "acme", common.Coverage{Checked: []string{"org:acme"}}, nil, false)}, func(string) string { return "[redacted]" })
+	output := buf.String()
+	for _, expected := range []string{"Scan Coverage", "gone     [redacted]", "rulesets (acme)", "1        0        0        -      -"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in the coverage report, got %s", expected, output)
+		}
+	}
+	if strings.Contains(output, "owner/") {
+		t.Errorf("Expected target names to be rendered by name, got %s", output)
+	}
+}
@@ -0,0 +1,106 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// header starts encrypted data, so data written before encryption was enabled is still read
+var header = []byte("git-monitor-encrypted:v1\n")
+
+// ErrNoKey is returned when encrypted data is read without encryption enabled
+var ErrNoKey = errors.New("data is encrypted, enable [encryption] with its key to read it")
+
+// Cipher encrypts and decrypts data with AES-GCM, prefixing each ciphertext with the header and a random nonce
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// New creates a Cipher with an AES key of 16, 24 or 32 bytes
+func New(key []byte) (*Cipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Encrypt returns the encrypted data
+func (c *Cipher) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	out := make([]byte, 0, len(header)+len(nonce)+len(plaintext)+c.aead.Overhead())
+	out = append(out, header...)
+	out = append(out, nonce...)
+	return c.aead.Seal(out, nonce, plaintext, header), nil
+}
+
+// Decrypt returns the decrypted data, and data that is not encrypted as it is
+func (c *Cipher) Decrypt(data []byte) ([]byte, error) {
+	if !Encrypted(data) {
+		return data, nil
+	}
+	data = data[len(header):]
+	if len(data) < c.aead.NonceSize() {
+		return nil, fmt.Errorf("encrypted data is truncated")
+	}
+	nonce, ciphertext := data[:c.aead.NonceSize()], data[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data, it was encrypted with another key or was modified")
+	}
+	return plaintext, nil
+}
+
+// Encrypted reports whether data was encrypted by a Cipher
+func Encrypted(data []byte) bool {
+	return bytes.HasPrefix(data, header)
+}
+
+var (
+	activeMu sync.RWMutex
+	active   *Cipher // Cipher of the data written by this process, nil when encryption is disabled
+)
+
+// Enable encrypts the data written by this process with c from now on, nil disables encryption
+func Enable(c *Cipher) {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	active = c
+}
+
+// Seal encrypts data when encryption is enabled, and returns it as it is otherwise
+func Seal(data []byte) ([]byte, error) {
+	activeMu.RLock()
+	c := active
+	activeMu.RUnlock()
+	if c == nil {
+		return data, nil
+	}
+	return c.Encrypt(data)
+}
+
+// Unseal decrypts data that was sealed, and returns data that was not as it is
+func Unseal(data []byte) ([]byte, error) {
+	activeMu.RLock()
+	c := active
+	activeMu.RUnlock()
+	if c == nil {
+		if Encrypted(data) {
+			return nil, ErrNoKey
+		}
+		return data, nil
+	}
+	return c.Decrypt(data)
+}
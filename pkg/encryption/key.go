@@ -0,0 +1,83 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/s3"
+)
+
+// kmsTimeout bounds the request decrypting the data key
+const kmsTimeout = 30 * time.Second
+
+// Load returns the cipher of the configured key, decrypting the data key with AWS KMS when configured
+func Load(cfg config.EncryptionConfig) (*Cipher, error) {
+	if cfg.Key != "" {
+		key, err := base64.StdEncoding.DecodeString(cfg.Key)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key must be base64 encoded: %w", err)
+		}
+		return New(key)
+	}
+
+	blob, err := base64.StdEncoding.DecodeString(cfg.KMSDataKey)
+	if err != nil {
+		return nil, fmt.Errorf("encryption kms_data_key must be base64 encoded: %w", err)
+	}
+	key, err := decryptDataKey(context.Background(), blob)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the data key with KMS: %w", err)
+	}
+	return New(key)
+}
+
+// decryptDataKey decrypts the ciphertext of a data key with the Decrypt action of AWS KMS, signed with the
+// credentials of the AWS_* environment variables. AWS_ENDPOINT_URL_KMS points it to another endpoint
+func decryptDataKey(ctx context.Context, blob []byte) ([]byte, error) {
+	client, err := s3.FromEnv("", kmsTimeout)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := os.Getenv("AWS_ENDPOINT_URL_KMS")
+	if endpoint == "" {
+		endpoint = "https://kms." + client.Region + ".amazonaws.com"
+	}
+
+	body, err := json.Marshal(map[string][]byte{"CiphertextBlob": blob})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService.Decrypt")
+	client.Sign(req, body, "kms")
+
+	resp, err := client.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var decoded struct {
+		Plaintext []byte `json:"Plaintext"` // Base64 in JSON, decoded by encoding/json
+		Message   string `json:"message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("error decoding KMS response: HTTP %d", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, decoded.Message)
+	}
+	return decoded.Plaintext, nil
+}
@@ -0,0 +1,130 @@
+package test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/encryption"
+)
+
+var key = bytes.Repeat([]byte{7}, 32)
+
+func TestCipher(t *testing.T) {
+	cipher, err := encryption.New(key)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	plaintext := []byte(`{"findings": {"pr_checker": []}}`)
+	sealed, err := cipher.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if !encryption.Encrypted(sealed) || bytes.Contains(sealed, plaintext) {
+		t.Errorf("Expected encrypted data, got %q", sealed)
+	}
+	if again, _ := cipher.Encrypt(plaintext); bytes.Equal(again, sealed) {
+		t.Error("Expected a new nonce for each encryption")
+	}
+
+	opened, err := cipher.Decrypt(sealed)
+	if err != nil || !bytes.Equal(opened, plaintext) {
+		t.Errorf("Expected %q, got %q (%v)", plaintext, opened, err)
+	}
+
+	// Data written before encryption was enabled is read as it is
+	if opened, err := cipher.Decrypt(plaintext); err != nil || !bytes.Equal(opened, plaintext) {
+		t.Errorf("Expected plaintext to be returned as it is, got %q (%v)", opened, err)
+	}
+
+	// Other keys and modified data are rejected
+	other, _ := encryption.New(bytes.Repeat([]byte{8}, 32))
+	if _, err := other.Decrypt(sealed); err == nil {
+		t.Error("Expected an error decrypting with another key")
+	}
+	sealed[len(sealed)-1] ^= 1
+	if _, err := cipher.Decrypt(sealed); err == nil {
+		t.Error("Expected an error decrypting modified data")
+	}
+
+	if _, err := encryption.New([]byte("short")); err == nil {
+		t.Error("Expected an error for an invalid key")
+	}
+}
+
+func TestSeal(t *testing.T) {
+	defer encryption.Enable(nil)
+	data := []byte("# Report")
+
+	// Without encryption, data is written as it is and encrypted data cannot be read
+	if sealed, err := encryption.Seal(data); err != nil || !bytes.Equal(sealed, data) {
+		t.Errorf("Expected data as it is, got %q (%v)", sealed, err)
+	}
+
+	cipher, _ := encryption.New(key)
+	encryption.Enable(cipher)
+	sealed, err := encryption.Seal(data)
+	if err != nil || !encryption.Encrypted(sealed) {
+		t.Fatalf("Expected encrypted data, got %q (%v)", sealed, err)
+	}
+	if opened, err := encryption.Unseal(sealed); err != nil || !bytes.Equal(opened, data) {
+		t.Errorf("Expected %q, got %q (%v)", data, opened, err)
+	}
+
+	encryption.Enable(nil)
+	if _, err := encryption.Unseal(sealed); !errors.Is(err, encryption.ErrNoKey) {
+		t.Errorf("Expected ErrNoKey, got %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	cipher, err := encryption.Load(config.EncryptionConfig{Key: base64.StdEncoding.EncodeToString(key)})
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	sealed, _ := cipher.Encrypt([]byte("state"))
+
+	// The data key is decrypted with the Decrypt action of KMS
+	var target, authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target, authorization = r.Header.Get("X-Amz-Target"), r.Header.Get("Authorization")
+		var req struct {
+			CiphertextBlob []byte
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if string(req.CiphertextBlob) != "wrapped-key" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type": "InvalidCiphertextException", "message": "invalid ciphertext"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string][]byte{"KeyId": []byte("key"), "Plaintext": key})
+	}))
+	defer server.Close()
+	t.Setenv("AWS_ENDPOINT_URL_KMS", server.URL)
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	kmsCipher, err := encryption.Load(config.EncryptionConfig{KMSDataKey: base64.StdEncoding.EncodeToString([]byte("wrapped-key"))})
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if target != "TrentService.Decrypt" || !strings.Contains(authorization, "/eu-west-1/kms/aws4_request") {
+		t.Errorf("Unexpected KMS request: %s, %s", target, authorization)
+	}
+	if opened, err := kmsCipher.Decrypt(sealed); err != nil || string(opened) != "state" {
+		t.Errorf("Expected the data key to decrypt the state, got %q (%v)", opened, err)
+	}
+
+	_, err = encryption.Load(config.EncryptionConfig{KMSDataKey: base64.StdEncoding.EncodeToString([]byte("other"))})
+	if err == nil || !strings.Contains(err.Error(), "invalid ciphertext") {
+		t.Errorf("Expected the KMS error, got %v", err)
+	}
+}
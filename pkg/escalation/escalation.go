@@ -0,0 +1,162 @@
+package escalation
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/state"
+)
+
+// severityRank orders the severities findings escalate to
+var severityRank = map[string]int{
+	findings.SeverityMedium:   1,
+	findings.SeverityHigh:     2,
+	findings.SeverityCritical: 3,
+}
+
+// Escalated is a finding escalated because it stayed unresolved
+type Escalated struct {
+	Finding   findings.Finding
+	Severity  string    // Most severe severity the finding escalated to
+	Policies  []string  // Policies that escalated the finding, oldest first
+	New       []string  // Policies that escalated the finding in this run
+	FirstSeen time.Time // When the finding was first reported
+	Runs      int       // Consecutive runs that reported the finding
+}
+
+// Apply escalates the findings reported in this run that reached the thresholds of a policy, once per policy,
+// and records the escalations in the state. It returns every escalated finding reported in this run,
+// most severe and oldest first
+func Apply(tracker *state.Tracker, policies []config.EscalationPolicy, now time.Time) []Escalated {
+	var escalated []Escalated
+	for _, record := range tracker.Reported() {
+		for _, policy := range policies {
+			if record.Escalated(policy.Name) || !reached(policy, record, now) {
+				continue
+			}
+			escalation := state.Escalation{Policy: policy.Name, Severity: policy.Severity, At: now, Runs: record.Runs}
+			tracker.Escalate(record.Finding, escalation)
+			record.Escalations = append(record.Escalations, escalation)
+		}
+		if len(record.Escalations) == 0 {
+			continue
+		}
+
+		e := Escalated{Finding: record.Finding, FirstSeen: record.FirstSeen, Runs: record.Runs}
+		for _, escalation := range record.Escalations {
+			e.Policies = append(e.Policies, escalation.Policy)
+			if escalation.At.Equal(now) {
+				e.New = append(e.New, escalation.Policy)
+			}
+			if severityRank[escalation.Severity] > severityRank[e.Severity] {
+				e.Severity = escalation.Severity
+			}
+		}
+		escalated = append(escalated, e)
+	}
+
+	sort.SliceStable(escalated, func(i, j int) bool {
+		if severityRank[escalated[i].Severity] != severityRank[escalated[j].Severity] {
+			return severityRank[escalated[i].Severity] > severityRank[escalated[j].Severity]
+		}
+		return escalated[i].FirstSeen.Before(escalated[j].FirstSeen)
+	})
+	return escalated
+}
+
+// reached reports whether a finding persisted long enough for a policy to escalate it
+func reached(policy config.EscalationPolicy, record state.Record, now time.Time) bool {
+	if len(policy.Monitors) > 0 && !slices.Contains(policy.Monitors, record.Finding.Monitor) {
+		return false
+	}
+	if policy.AfterRuns > 0 && record.Runs >= policy.AfterRuns {
+		return true
+	}
+	return policy.After.Duration > 0 && now.Sub(record.FirstSeen) >= policy.After.Duration
+}
+
+// SetSeverities raises the severity of escalated findings in list, so risk scores and outputs reflect it
+func SetSeverities(list []findings.Finding, escalated []Escalated) {
+	severities := make(map[string]string, len(escalated))
+	for _, e := range escalated {
+		severities[e.Finding.Fingerprint()] = e.Severity
+	}
+	for i := range list {
+		if severity, ok := severities[list[i].Fingerprint()]; ok {
+			list[i].Severity = severity
+		}
+	}
+}
+
+// ByPolicy returns the findings a policy escalated in this run
+func ByPolicy(escalated []Escalated, policy string) []Escalated {
+	var result []Escalated
+	for _, e := range escalated {
+		if slices.Contains(e.New, policy) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// WriteMarkdown writes the escalated findings in a code block format suitable for Slack
+// repoName renders repository names, e.g. redacted, and nil keeps them
+func WriteMarkdown(w io.Writer, escalated []Escalated, repoName func(string) string, now time.Time) {
+	if len(escalated) == 0 {
+		return // No results to display
+	}
+
+	newCount := 0
+	for _, e := range escalated {
+		if len(e.New) > 0 {
+			newCount++
+		}
+	}
+
+	fmt.Fprintln(w, "## :arrow_double_up: Escalated Findings")
+	fmt.Fprintf(w, "%d findings stayed unresolved past an escalation policy, %d escalated in this run.\n\n", len(escalated), newCount)
+	writeTable(w, escalated, repoName, now)
+}
+
+// WritePolicyMarkdown writes the findings a policy escalated in this run, for the policy's webhook
+func WritePolicyMarkdown(w io.Writer, policy string, escalated []Escalated, repoName func(string) string, now time.Time) {
+	fmt.Fprintf(w, "## :arrow_double_up: Findings Escalated by %s\n", policy)
+	fmt.Fprintf(w, "%d findings stayed unresolved past the %s escalation policy.\n\n", len(escalated), policy)
+	writeTable(w, escalated, repoName, now)
+}
+
+// writeTable writes a table of escalated findings
+func writeTable(w io.Writer, escalated []Escalated, repoName func(string) string, now time.Time) {
+	// Start code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "Severity  Open  Runs  Monitor                   Repository                Subject")
+	fmt.Fprintln(w, "-----------------------------------------------------------------------------------------")
+	for _, e := range escalated {
+		repository := e.Finding.Repository
+		if repoName != nil {
+			repository = repoName(repository)
+		}
+		severity := e.Severity
+		if len(e.New) > 0 {
+			severity += "*"
+		}
+		fmt.Fprintf(w, "%-9s %-5s %-5d %-25s %-25s %s\n", severity, age(now.Sub(e.FirstSeen)), e.Runs, e.Finding.Monitor, repository, e.Finding.Subject)
+	}
+	fmt.Fprintln(w, "* escalated in this run")
+	// End code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
+
+// age renders how long a finding has been open in days, or hours within the first day
+func age(d time.Duration) string {
+	if d < 24*time.Hour {
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
@@ -0,0 +1,115 @@
+package test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/escalation"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/state"
+)
+
+// run records the findings of a run in the state and applies the escalation policies
+func run(t *testing.T, path string, policies []config.EscalationPolicy, now time.Time, list ...findings.Finding) []escalation.Escalated {
+	t.Helper()
+	tracker, err := state.NewTracker(path)
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+	tracker.Record("pr_checker", list)
+	escalated := escalation.Apply(tracker, policies, now)
+	if err := tracker.Save(); err != nil {
+		t.Fatalf("Failed to save state: %v", err)
+	}
+	return escalated
+}
+
+func TestApply(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	policies := []config.EscalationPolicy{
+		{Name: "managers", AfterRuns: 2, Severity: findings.SeverityHigh, Webhook: "https://hooks.slack.com/services/managers"},
+		{Name: "directors", After: config.Hours(24), Severity: findings.SeverityCritical},
+		{Name: "secrets", AfterRuns: 1, Severity: findings.SeverityCritical, Monitors: []string{"push_protection_bypasses"}},
+	}
+	persistent := findings.Finding{Monitor: "pr_checker", Repository: "owner/api", Subject: "PR #1"}
+	recent := findings.Finding{Monitor: "pr_checker", Repository: "owner/api", Subject: "PR #2"}
+
+	if escalated := run(t, path, policies, time.Now(), persistent); len(escalated) != 0 {
+		t.Fatalf("Expected no escalations after one run, got %+v", escalated)
+	}
+
+	escalated := run(t, path, policies, time.Now(), persistent, recent)
+	if len(escalated) != 1 || escalated[0].Finding.Subject != "PR #1" {
+		t.Fatalf("Expected the finding reported in two runs to escalate, got %+v", escalated)
+	}
+	if escalated[0].Severity != findings.SeverityHigh || escalated[0].Runs != 2 || strings.Join(escalated[0].New, ",") != "managers" {
+		t.Errorf("Expected a new high escalation by managers after 2 runs, got %+v", escalated[0])
+	}
+	if got := escalation.ByPolicy(escalated, "managers"); len(got) != 1 {
+		t.Errorf("Expected managers to be notified of the escalation, got %+v", got)
+	}
+
+	// Escalations are recorded, so a policy escalates a finding once, and later policies raise its severity
+	escalated = run(t, path, policies, time.Now().Add(48*time.Hour), persistent, recent)
+	if len(escalated) != 2 {
+		t.Fatalf("Expected both findings to be escalated, got %+v", escalated)
+	}
+	first := escalated[0]
+	if first.Finding.Subject != "PR #1" || first.Severity != findings.SeverityCritical || strings.Join(first.New, ",") != "directors" {
+		t.Errorf("Expected PR #1 to be escalated to critical by directors only, got %+v", first)
+	}
+	if strings.Join(first.Policies, ",") != "managers,directors" {
+		t.Errorf("Expected the escalation history of PR #1, got %v", first.Policies)
+	}
+
+	s, err := state.Load(path)
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+	for _, r := range s.History {
+		if r.Finding.Subject == "PR #1" && (r.Runs != 3 || len(r.Escalations) != 2) {
+			t.Errorf("Expected PR #1 to be recorded with 3 runs and 2 escalations, got %+v", r)
+		}
+	}
+
+	// A finding that is resolved and reported again starts over
+	run(t, path, policies, time.Now(), recent)
+	if escalated := run(t, path, policies, time.Now(), persistent); len(escalated) != 0 {
+		t.Errorf("Expected a reopened finding to start over, got %+v", escalated)
+	}
+}
+
+func TestSetSeverities(t *testing.T) {
+	f := findings.Finding{Monitor: "pr_checker", Repository: "owner/api", Subject: "PR #1"}
+	list := []findings.Finding{f, {Monitor: "pr_checker", Repository: "owner/api", Subject: "PR #2"}}
+
+	escalation.SetSeverities(list, []escalation.Escalated{{Finding: f, Severity: findings.SeverityHigh}})
+	if list[0].Severity != findings.SeverityHigh || list[1].Severity != "" {
+		t.Errorf("Expected only the escalated finding to be raised to high, got %+v", list)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	now := time.Now()
+	escalated := []escalation.Escalated{{
+		Finding:   findings.Finding{Monitor: "pr_checker", Repository: "owner/api", Subject: "PR #1"},
+		Severity:  findings.SeverityHigh,
+		Policies:  []string{"managers"},
+		New:       []string{"managers"},
+		FirstSeen: now.Add(-72 * time.Hour),
+		Runs:      3,
+	}}
+
+	var buf bytes.Buffer
+	escalation.WriteMarkdown(&buf, escalated, nil, now)
+	output := buf.String()
+	for _, expected := range []string{"Escalated Findings", "1 escalated in this run", "high*", "3d", "owner/api"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in the escalations, got %s", expected, output)
+		}
+	}
+}
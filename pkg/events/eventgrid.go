@@ -0,0 +1,52 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+)
+
+// EventGrid publishes events to an Azure Event Grid topic using the CloudEvents schema
+type EventGrid struct {
+	endpoint string
+	key      string
+	client   *http.Client
+}
+
+// NewEventGrid creates a sink for the configured topic
+func NewEventGrid(cfg config.EventGridConfig) *EventGrid {
+	return &EventGrid{endpoint: cfg.Endpoint, key: cfg.Key, client: newClient()}
+}
+
+// Name returns the name of the sink
+func (g *EventGrid) Name() string {
+	return "Event Grid"
+}
+
+// Publish sends the events as a CloudEvents batch, authenticated with the topic's access key
+func (g *EventGrid) Publish(ctx context.Context, list []Event) error {
+	payload, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/cloudevents-batch+json; charset=utf-8")
+	req.Header.Set("aeg-sas-key", g.key)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("publishing to Event Grid failed: HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
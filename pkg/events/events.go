@@ -0,0 +1,92 @@
+package events
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/findings"
+)
+
+// typePrefix starts the types of the events published for findings
+const typePrefix = "io.github.anupsv.git-monitor.finding."
+
+// Types of the events published for findings
+const (
+	TypeOpened   = typePrefix + "opened"   // The finding was not reported by the previous run
+	TypeResolved = typePrefix + "resolved" // The finding of the previous run is no longer reported
+)
+
+// SpecVersion is the version of the CloudEvents specification events follow
+const SpecVersion = "1.0"
+
+// requestTimeout bounds each request to a sink
+const requestTimeout = 30 * time.Second
+
+// batchSize is the most events sent to a sink in a single request
+const batchSize = 100
+
+// Event is a CloudEvent about a finding, in the JSON format of CloudEvents 1.0
+type Event struct {
+	SpecVersion     string           `json:"specversion"`
+	ID              string           `json:"id"`      // Unique per finding, type and run, so retries can be deduplicated
+	Source          string           `json:"source"`  // The configured source
+	Type            string           `json:"type"`    // TypeOpened or TypeResolved
+	Subject         string           `json:"subject"` // Fingerprint of the finding
+	Time            time.Time        `json:"time"`    // When the run found the change
+	DataContentType string           `json:"datacontenttype"`
+	Data            findings.Finding `json:"data"` // The finding, as in JSON outputs
+}
+
+// Sink publishes events to a cloud service
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, events []Event) error
+}
+
+// FromChanges returns the events of the findings opened and resolved since the previous run
+func FromChanges(changes findings.Changes, source string, now time.Time) []Event {
+	var list []Event
+	for _, f := range changes.New {
+		list = append(list, newEvent(TypeOpened, f, source, now))
+	}
+	for _, f := range changes.Resolved {
+		list = append(list, newEvent(TypeResolved, f, source, now))
+	}
+	return list
+}
+
+// newEvent returns the event of a type about a finding
+func newEvent(eventType string, f findings.Finding, source string, now time.Time) Event {
+	fingerprint := f.Fingerprint()
+	return Event{
+		SpecVersion:     SpecVersion,
+		ID:              fingerprint + "-" + strings.TrimPrefix(eventType, typePrefix) + "-" + now.UTC().Format("20060102T150405Z"),
+		Source:          source,
+		Type:            eventType,
+		Subject:         fingerprint,
+		Time:            now.UTC(),
+		DataContentType: "application/json",
+		Data:            f,
+	}
+}
+
+// Publish sends the events to the sink in batches
+func Publish(ctx context.Context, sink Sink, list []Event) error {
+	for start := 0; start < len(list); start += batchSize {
+		end := start + batchSize
+		if end > len(list) {
+			end = len(list)
+		}
+		if err := sink.Publish(ctx, list[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newClient returns the HTTP client of sinks
+func newClient() *http.Client {
+	return &http.Client{Timeout: requestTimeout}
+}
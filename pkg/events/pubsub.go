@@ -0,0 +1,126 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+)
+
+// DefaultPubSubEndpoint is the Pub/Sub API of Google Cloud
+const DefaultPubSubEndpoint = "https://pubsub.googleapis.com"
+
+// metadataTokenURL is where GCE, GKE and Cloud Run serve access tokens of the attached service account
+const metadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+// PubSub publishes events to a Google Cloud Pub/Sub topic, in the binary content mode of the
+// CloudEvents Pub/Sub binding: the finding as message data and the other attributes as ce- attributes
+type PubSub struct {
+	config config.PubSubConfig
+	url    string
+	client *http.Client
+}
+
+// NewPubSub creates a sink for the configured topic
+func NewPubSub(cfg config.PubSubConfig) *PubSub {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultPubSubEndpoint
+	}
+	topic := "projects/" + url.PathEscape(cfg.Project) + "/topics/" + url.PathEscape(cfg.Topic)
+	return &PubSub{config: cfg, url: strings.TrimSuffix(endpoint, "/") + "/v1/" + topic + ":publish", client: newClient()}
+}
+
+// Name returns the name of the sink
+func (p *PubSub) Name() string {
+	return "Pub/Sub"
+}
+
+// pubsubMessage is a message of a publish request
+type pubsubMessage struct {
+	Data       []byte            `json:"data"` // Encoded as base64, as Pub/Sub expects
+	Attributes map[string]string `json:"attributes"`
+}
+
+// Publish sends the events as messages of a single publish request
+func (p *PubSub) Publish(ctx context.Context, list []Event) error {
+	token, err := p.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	messages := make([]pubsubMessage, 0, len(list))
+	for _, event := range list {
+		data, err := json.Marshal(event.Data)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, pubsubMessage{Data: data, Attributes: map[string]string{
+			"ce-specversion":     event.SpecVersion,
+			"ce-id":              event.ID,
+			"ce-source":          event.Source,
+			"ce-type":            event.Type,
+			"ce-subject":         event.Subject,
+			"ce-time":            event.Time.Format("2006-01-02T15:04:05Z07:00"),
+			"content-type":       event.DataContentType,
+			"ce-datacontenttype": event.DataContentType,
+		}})
+	}
+	payload, err := json.Marshal(map[string]interface{}{"messages": messages})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("publishing to Pub/Sub failed: HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// token returns the configured access token, or else one requested from the metadata server
+// The emulator needs no token, so none is requested for a configured endpoint
+func (p *PubSub) token(ctx context.Context) (string, error) {
+	if p.config.Token != "" || p.config.Endpoint != "" {
+		return p.config.Token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadataTokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error requesting a Pub/Sub token from the metadata server, set the pubsub token outside Google Cloud: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error requesting a Pub/Sub token from the metadata server: HTTP %d", resp.StatusCode)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("error decoding the token of the metadata server: %w", err)
+	}
+	return token.AccessToken, nil
+}
@@ -0,0 +1,133 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/events"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+)
+
+var (
+	opened   = findings.Finding{Monitor: "pr_checker", Repository: "example-org/api", Subject: "PR #1", Summary: "Merged without approval"}
+	resolved = findings.Finding{Monitor: "rulesets", Repository: "example-org/web", Summary: "No ruleset"}
+	now      = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+)
+
+func TestFromChanges(t *testing.T) {
+	list := events.FromChanges(findings.Changes{New: []findings.Finding{opened}, Resolved: []findings.Finding{resolved}}, "git-monitor/prod", now)
+	if len(list) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(list))
+	}
+
+	event := list[0]
+	if event.Type != events.TypeOpened || event.Subject != opened.Fingerprint() || event.Source != "git-monitor/prod" ||
+		event.SpecVersion != "1.0" || !event.Time.Equal(now) {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+	if event.ID != opened.Fingerprint()+"-opened-20260301T090000Z" {
+		t.Errorf("Unexpected ID: %s", event.ID)
+	}
+	if list[1].Type != events.TypeResolved || list[1].Data.Monitor != "rulesets" {
+		t.Errorf("Unexpected event: %+v", list[1])
+	}
+
+	// The finding is the data of the event, with its fingerprint as in JSON outputs
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	var decoded struct {
+		Data struct {
+			Fingerprint string `json:"fingerprint"`
+		} `json:"data"`
+		DataContentType string `json:"datacontenttype"`
+	}
+	json.Unmarshal(encoded, &decoded)
+	if decoded.Data.Fingerprint != opened.Fingerprint() || decoded.DataContentType != "application/json" {
+		t.Errorf("Unexpected JSON: %s", encoded)
+	}
+}
+
+func TestEventGrid(t *testing.T) {
+	var batches [][]events.Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("aeg-sas-key") != "topic-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get("Content-Type") != "application/cloudevents-batch+json; charset=utf-8" {
+			t.Errorf("Unexpected content type: %s", r.Header.Get("Content-Type"))
+		}
+		var batch []events.Event
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			t.Errorf("Error decoding batch: %v", err)
+		}
+		batches = append(batches, batch)
+	}))
+	defer server.Close()
+
+	// Events are sent in batches of 100
+	list := make([]events.Event, 0, 150)
+	for i := 0; i < 150; i++ {
+		list = append(list, events.FromChanges(findings.Changes{New: []findings.Finding{opened}}, "git-monitor", now)...)
+	}
+	sink := events.NewEventGrid(config.EventGridConfig{Endpoint: server.URL, Key: "topic-key"})
+	if err := events.Publish(context.Background(), sink, list); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if len(batches) != 2 || len(batches[0]) != 100 || len(batches[1]) != 50 {
+		t.Errorf("Unexpected batches of %d events", len(batches))
+	}
+	if batches[0][0].Type != events.TypeOpened {
+		t.Errorf("Unexpected event: %+v", batches[0][0])
+	}
+
+	sink = events.NewEventGrid(config.EventGridConfig{Endpoint: server.URL, Key: "wrong"})
+	if err := events.Publish(context.Background(), sink, list); err == nil {
+		t.Error("Expected an error for a rejected key")
+	}
+}
+
+func TestPubSub(t *testing.T) {
+	var path, auth string
+	var request struct {
+		Messages []struct {
+			Data       []byte            `json:"data"`
+			Attributes map[string]string `json:"attributes"`
+		} `json:"messages"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, auth = r.URL.Path, r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&request)
+		w.Write([]byte(`{"messageIds": ["1"]}`))
+	}))
+	defer server.Close()
+
+	sink := events.NewPubSub(config.PubSubConfig{Project: "security", Topic: "git-monitor", Token: "access-token", Endpoint: server.URL})
+	list := events.FromChanges(findings.Changes{Resolved: []findings.Finding{resolved}}, "git-monitor", now)
+	if err := events.Publish(context.Background(), sink, list); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	if path != "/v1/projects/security/topics/git-monitor:publish" || auth != "Bearer access-token" {
+		t.Errorf("Unexpected request to %s with %q", path, auth)
+	}
+	if len(request.Messages) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(request.Messages))
+	}
+	message := request.Messages[0]
+	if message.Attributes["ce-type"] != events.TypeResolved || message.Attributes["ce-subject"] != resolved.Fingerprint() ||
+		message.Attributes["ce-time"] != "2026-03-01T09:00:00Z" {
+		t.Errorf("Unexpected attributes: %v", message.Attributes)
+	}
+	var data findings.Finding
+	if err := json.Unmarshal(message.Data, &data); err != nil || data.Monitor != "rulesets" {
+		t.Errorf("Unexpected data %s: %v", message.Data, err)
+	}
+}
@@ -0,0 +1,105 @@
+package evidence
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/provenance"
+	"github.com/anupsv/git-monitoring/pkg/suppression"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+// bundleVersion is the version of the evidence bundle format
+const bundleVersion = 1
+
+// Bundle is the evidence of a single run
+type Bundle struct {
+	Version      int                       `json:"version"`
+	StartedAt    time.Time                 `json:"started_at"`
+	FinishedAt   time.Time                 `json:"finished_at"`
+	ConfigSHA256 string                    `json:"config_sha256"` // Hash of the configuration file the run used
+	Identities   []provenance.Identity     `json:"identities"`    // Who the tokens of the run authenticate as
+	Monitors     []Monitor                 `json:"monitors"`
+	Suppressions []suppression.Suppression `json:"suppressions"` // Findings left out of the results and why
+}
+
+// Monitor is the outcome of a monitor in a run
+type Monitor struct {
+	Monitor  string             `json:"monitor"`
+	Account  string             `json:"account,omitempty"`
+	Failed   bool               `json:"failed"` // Whether the findings are incomplete because of processing errors
+	Findings []findings.Finding `json:"findings"`
+
+	// Targets not checked because the scan stopped, so the findings are incomplete
+	Skipped []common.SkippedTarget `json:"skipped,omitempty"`
+}
+
+// NewBundle creates the evidence bundle of a run started at the given time
+func NewBundle(startedAt time.Time, configSHA256 string, identities []provenance.Identity) *Bundle {
+	if identities == nil {
+		identities = []provenance.Identity{}
+	}
+	return &Bundle{
+		Version:      bundleVersion,
+		StartedAt:    startedAt,
+		ConfigSHA256: configSHA256,
+		Identities:   identities,
+		Monitors:     []Monitor{},
+		Suppressions: []suppression.Suppression{},
+	}
+}
+
+// AddMonitor records the findings of a monitor and the targets it skipped
+func (b *Bundle) AddMonitor(monitor, account string, failed bool, list []findings.Finding, skipped []common.SkippedTarget) {
+	if list == nil {
+		list = []findings.Finding{}
+	}
+	b.Monitors = append(b.Monitors, Monitor{Monitor: monitor, Account: account, Failed: failed, Findings: list, Skipped: skipped})
+}
+
+// Marshal encodes the bundle as canonical JSON: compact, in field order, with times in UTC to the second
+// The same bundle always encodes to the same bytes, so signatures can be verified against a re-encoded bundle
+func (b *Bundle) Marshal() ([]byte, error) {
+	canonical := *b
+	canonical.StartedAt = b.StartedAt.UTC().Truncate(time.Second)
+	canonical.FinishedAt = b.FinishedAt.UTC().Truncate(time.Second)
+
+	data, err := json.Marshal(canonical)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding evidence bundle: %v", err)
+	}
+
+	return append(data, '\n'), nil
+}
+
+// Write encodes the bundle to path and signs it with signer, when it is not nil
+// It returns the path of the detached signature, empty when the bundle is not signed
+func (b *Bundle) Write(path string, signer Signer) (string, error) {
+	data, err := b.Marshal()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("error writing evidence bundle: %v", err)
+	}
+
+	if signer == nil {
+		return "", nil
+	}
+
+	signature, err := signer.Sign(data)
+	if err != nil {
+		return "", fmt.Errorf("error signing evidence bundle: %v", err)
+	}
+
+	signaturePath := path + signer.Extension()
+	if err := os.WriteFile(signaturePath, signature, 0644); err != nil {
+		return "", fmt.Errorf("error writing evidence signature: %v", err)
+	}
+
+	return signaturePath, nil
+}
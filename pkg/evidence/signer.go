@@ -0,0 +1,181 @@
+package evidence
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Signer creates detached signatures of evidence bundles
+type Signer interface {
+	Sign(data []byte) ([]byte, error)
+	Extension() string // Appended to the bundle path to name the signature file
+}
+
+// KeySigner signs with a private key, producing base64 encoded signatures like `cosign sign-blob`
+type KeySigner struct {
+	key crypto.Signer
+}
+
+// encryptedKey is the format of encrypted cosign private keys
+type encryptedKey struct {
+	KDF struct {
+		Name   string `json:"name"`
+		Params struct {
+			N int `json:"N"`
+			R int `json:"r"`
+			P int `json:"p"`
+		} `json:"params"`
+		Salt []byte `json:"salt"`
+	} `json:"kdf"`
+	Cipher struct {
+		Name  string `json:"name"`
+		Nonce []byte `json:"nonce"`
+	} `json:"cipher"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// LoadKeySigner reads a PEM private key: a cosign key pair's encrypted private key, decrypted with password,
+// or an unencrypted PKCS#8 or EC private key. ECDSA and Ed25519 keys are supported
+func LoadKeySigner(path, password string) (*KeySigner, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading signing key: %v", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("signing key %s is not PEM encoded", path)
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "ENCRYPTED SIGSTORE PRIVATE KEY", "ENCRYPTED COSIGN PRIVATE KEY":
+		der, err := decryptKey(block.Bytes, password)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err = x509.ParsePKCS8PrivateKey(der)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing signing key: %v", err)
+		}
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing signing key: %v", err)
+		}
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing signing key: %v", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported signing key type: %s", block.Type)
+	}
+
+	switch key := parsed.(type) {
+	case *ecdsa.PrivateKey:
+		return &KeySigner{key: key}, nil
+	case ed25519.PrivateKey:
+		return &KeySigner{key: key}, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing key algorithm: %T. Must be ECDSA or Ed25519", parsed)
+	}
+}
+
+// decryptKey decrypts the DER of an encrypted cosign private key
+func decryptKey(data []byte, password string) ([]byte, error) {
+	var key encryptedKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("error decoding encrypted signing key: %v", err)
+	}
+
+	if key.KDF.Name != "scrypt" || key.Cipher.Name != "nacl/secretbox" {
+		return nil, fmt.Errorf("unsupported signing key encryption: %s with %s", key.KDF.Name, key.Cipher.Name)
+	}
+	if len(key.Cipher.Nonce) != 24 {
+		return nil, fmt.Errorf("invalid nonce in encrypted signing key")
+	}
+
+	derived, err := scrypt.Key([]byte(password), key.KDF.Salt, key.KDF.Params.N, key.KDF.Params.R, key.KDF.Params.P, 32)
+	if err != nil {
+		return nil, fmt.Errorf("error deriving signing key password: %v", err)
+	}
+
+	var secret [32]byte
+	var nonce [24]byte
+	copy(secret[:], derived)
+	copy(nonce[:], key.Cipher.Nonce)
+
+	der, ok := secretbox.Open(nil, key.Ciphertext, &nonce, &secret)
+	if !ok {
+		return nil, fmt.Errorf("error decrypting signing key: wrong password")
+	}
+
+	return der, nil
+}
+
+// Sign signs the SHA-256 of data with ECDSA keys, or data itself with Ed25519 keys
+func (s *KeySigner) Sign(data []byte) ([]byte, error) {
+	var signature []byte
+	var err error
+	if _, ok := s.key.(ed25519.PrivateKey); ok {
+		signature, err = s.key.Sign(rand.Reader, data, crypto.Hash(0))
+	} else {
+		digest := sha256.Sum256(data)
+		signature, err = s.key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(base64.StdEncoding.EncodeToString(signature)), nil
+}
+
+// Extension returns the extension of signature files
+func (s *KeySigner) Extension() string {
+	return ".sig"
+}
+
+// GPGSigner signs with the gpg command, producing ASCII armored detached signatures
+type GPGSigner struct {
+	KeyID string // Key to sign with, the default key of the keyring when empty
+}
+
+// Sign signs data with gpg
+func (s *GPGSigner) Sign(data []byte) ([]byte, error) {
+	args := []string{"--batch", "--yes", "--armor", "--detach-sign"}
+	if s.KeyID != "" {
+		args = append(args, "--local-user", s.KeyID)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("gpg", args...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("gpg failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// Extension returns the extension of signature files
+func (s *GPGSigner) Extension() string {
+	return ".asc"
+}
@@ -0,0 +1,208 @@
+package test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/anupsv/git-monitoring/pkg/evidence"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/provenance"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+func newBundle() *evidence.Bundle {
+	started := time.Date(2030, 1, 1, 12, 0, 0, 500, time.FixedZone("CET", 3600))
+	bundle := evidence.NewBundle(started, "abc123", []provenance.Identity{{Login: "monitor-bot"}})
+	bundle.FinishedAt = started.Add(time.Minute)
+	bundle.AddMonitor("pr_checker", "", false, []findings.Finding{
+		{Monitor: "pr_checker", Repository: "owner/repo", Subject: "PR #1", Summary: "Fix login by alice merged without approval"},
+	}, nil)
+	bundle.AddMonitor("repo_visibility", "", true, nil, nil)
+	bundle.AddMonitor("rulesets", "", false, nil, []common.SkippedTarget{{Target: "owner/other", Reason: common.StopBudget}})
+	return bundle
+}
+
+func TestMarshal(t *testing.T) {
+	data, err := newBundle().Marshal()
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	again, err := newBundle().Marshal()
+	if err != nil || string(again) != string(data) {
+		t.Errorf("Expected the same bundle to encode to the same bytes")
+	}
+
+	content := string(data)
+	for _, expected := range []string{
+		`"started_at":"2030-01-01T11:00:00Z"`,
+		`"config_sha256":"abc123"`,
+		`"identities":[{"login":"monitor-bot"}]`,
+		`"monitor":"repo_visibility","failed":true,"findings":[]}`,
+		`"monitor":"rulesets","failed":false,"findings":[],"skipped":[{"target":"owner/other","reason":"budget"}]`,
+		`"suppressions":[]`,
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Expected the bundle to contain %s, got %s", expected, content)
+		}
+	}
+}
+
+func TestKeySigner(t *testing.T) {
+	dir := t.TempDir()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to encode key: %v", err)
+	}
+
+	verify := func(t *testing.T, signer evidence.Signer) {
+		path := filepath.Join(dir, "evidence.json")
+		signaturePath, err := newBundle().Write(path, signer)
+		if err != nil {
+			t.Fatalf("Did not expect an error but got: %v", err)
+		}
+		if signaturePath != path+".sig" {
+			t.Errorf("Expected the signature next to the bundle, got %s", signaturePath)
+		}
+
+		data, _ := os.ReadFile(path)
+		encoded, _ := os.ReadFile(signaturePath)
+		signature, err := base64.StdEncoding.DecodeString(string(encoded))
+		if err != nil {
+			t.Fatalf("Expected a base64 encoded signature: %v", err)
+		}
+		digest := sha256.Sum256(data)
+		if !ecdsa.VerifyASN1(&key.PublicKey, digest[:], signature) {
+			t.Error("Expected the signature to verify against the bundle")
+		}
+	}
+
+	t.Run("PKCS8 key", func(t *testing.T) {
+		keyPath := filepath.Join(dir, "key.pem")
+		writePEM(t, keyPath, "PRIVATE KEY", der)
+
+		signer, err := evidence.LoadKeySigner(keyPath, "")
+		if err != nil {
+			t.Fatalf("Did not expect an error but got: %v", err)
+		}
+		verify(t, signer)
+	})
+
+	t.Run("Encrypted cosign key", func(t *testing.T) {
+		keyPath := filepath.Join(dir, "cosign.key")
+		writePEM(t, keyPath, "ENCRYPTED SIGSTORE PRIVATE KEY", encryptKey(t, der, "secret"))
+
+		signer, err := evidence.LoadKeySigner(keyPath, "secret")
+		if err != nil {
+			t.Fatalf("Did not expect an error but got: %v", err)
+		}
+		verify(t, signer)
+
+		if _, err := evidence.LoadKeySigner(keyPath, "wrong"); err == nil || !strings.Contains(err.Error(), "wrong password") {
+			t.Errorf("Expected a wrong password error, got %v", err)
+		}
+	})
+
+	t.Run("Ed25519 key", func(t *testing.T) {
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("Failed to generate key: %v", err)
+		}
+		der, _ := x509.MarshalPKCS8PrivateKey(private)
+		keyPath := filepath.Join(dir, "ed25519.pem")
+		writePEM(t, keyPath, "PRIVATE KEY", der)
+
+		signer, err := evidence.LoadKeySigner(keyPath, "")
+		if err != nil {
+			t.Fatalf("Did not expect an error but got: %v", err)
+		}
+		data := []byte("bundle")
+		encoded, err := signer.Sign(data)
+		if err != nil {
+			t.Fatalf("Did not expect an error but got: %v", err)
+		}
+		signature, _ := base64.StdEncoding.DecodeString(string(encoded))
+		if !ed25519.Verify(public, data, signature) {
+			t.Error("Expected the signature to verify")
+		}
+	})
+
+	t.Run("RSA key", func(t *testing.T) {
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatalf("Failed to generate key: %v", err)
+		}
+		der, _ := x509.MarshalPKCS8PrivateKey(rsaKey)
+		keyPath := filepath.Join(dir, "rsa.pem")
+		writePEM(t, keyPath, "PRIVATE KEY", der)
+
+		if _, err := evidence.LoadKeySigner(keyPath, ""); err == nil || !strings.Contains(err.Error(), "unsupported signing key algorithm") {
+			t.Errorf("Expected an unsupported algorithm error, got %v", err)
+		}
+	})
+}
+
+func TestWriteUnsigned(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "evidence.json")
+	signaturePath, err := newBundle().Write(path, nil)
+	if err != nil || signaturePath != "" {
+		t.Fatalf("Expected an unsigned bundle, got %q (%v)", signaturePath, err)
+	}
+
+	var decoded map[string]interface{}
+	data, _ := os.ReadFile(path)
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded["version"] != float64(1) {
+		t.Errorf("Expected a version 1 bundle, got %s (%v)", data, err)
+	}
+}
+
+// writePEM writes a PEM block to path
+func writePEM(t *testing.T, path, blockType string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data}), 0600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+}
+
+// encryptKey encrypts a key the way cosign generate-key-pair does
+func encryptKey(t *testing.T, der []byte, password string) []byte {
+	t.Helper()
+	salt := make([]byte, 32)
+	var nonce [24]byte
+	rand.Read(salt)
+	rand.Read(nonce[:])
+
+	derived, err := scrypt.Key([]byte(password), salt, 32768, 8, 1, 32)
+	if err != nil {
+		t.Fatalf("Failed to derive key: %v", err)
+	}
+	var secret [32]byte
+	copy(secret[:], derived)
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"kdf":        map[string]interface{}{"name": "scrypt", "params": map[string]int{"N": 32768, "r": 8, "p": 1}, "salt": salt},
+		"cipher":     map[string]interface{}{"name": "nacl/secretbox", "nonce": nonce[:]},
+		"ciphertext": secretbox.Seal(nil, der, &nonce, &secret),
+	})
+	return data
+}
@@ -0,0 +1,223 @@
+package findings
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// SeverityLow marks findings about conventions rather than security, e.g. PR titles, which do not add to risk scores
+const SeverityLow = "low"
+
+// Severities of findings escalated because they stayed unresolved, which weigh more in risk scores
+const (
+	SeverityMedium   = "medium"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+// Finding is a single issue reported by a monitor, in a form that can be compared across runs
+type Finding struct {
+	Monitor    string `json:"monitor"`       // Configuration key of the monitor (e.g. "pr_checker")
+	Repository string `json:"repository"`    // "owner/repo", or "org:<name>" for organization-wide findings
+	Subject    string `json:"subject"`       // What the finding is about within the repository (e.g. "PR #12")
+	Summary    string `json:"summary"`       // Human readable description
+	URL        string `json:"url,omitempty"` // Link to the affected resource
+
+	// Name of the account the finding was reported for, empty without multiple accounts
+	Account string `json:"account,omitempty"`
+	// Compliance control IDs the monitor is mapped to (e.g. "SOC2 CC8.1")
+	Controls []string `json:"controls,omitempty"`
+	// Severity of the finding, SeverityLow, an escalated severity, or empty for findings weighted by their monitor
+	Severity string `json:"severity,omitempty"`
+	// Rule of the monitor the finding breaks, for monitors checking several (e.g. "unapproved" of the PR checker)
+	Rule string `json:"rule,omitempty"`
+}
+
+// Fingerprint identifies a finding across runs
+// It is a hash of the monitor, repository and subject, so the same issue always has the same fingerprint.
+// Findings of named accounts also hash the account. Fingerprints are used to correlate, deduplicate,
+// acknowledge and suppress findings
+func (f Finding) Fingerprint() string {
+	if f.Account != "" {
+		return Fingerprint(f.Monitor, f.Account+"\x00"+f.Repository, f.Subject)
+	}
+	return Fingerprint(f.Monitor, f.Repository, f.Subject)
+}
+
+// Fingerprint computes the fingerprint of a finding from its monitor, repository and subject
+func Fingerprint(monitor, repository, subject string) string {
+	sum := sha256.Sum256([]byte(monitor + "\x00" + repository + "\x00" + subject))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Dedupe returns the findings without those reported more than once, e.g. by several shards of a scan,
+// keeping the first of each fingerprint in order
+func Dedupe(list []Finding) []Finding {
+	seen := make(map[string]bool, len(list))
+	unique := make([]Finding, 0, len(list))
+	for _, f := range list {
+		fingerprint := f.Fingerprint()
+		if seen[fingerprint] {
+			continue
+		}
+		seen[fingerprint] = true
+		unique = append(unique, f)
+	}
+	return unique
+}
+
+// MarshalJSON includes the fingerprint in the JSON representation of a finding
+func (f Finding) MarshalJSON() ([]byte, error) {
+	type finding Finding
+	return json.Marshal(struct {
+		Fingerprint string `json:"fingerprint"`
+		finding
+	}{
+		Fingerprint: f.Fingerprint(),
+		finding:     finding(f),
+	})
+}
+
+// csvHeader lists the columns of findings written as CSV
+var csvHeader = []string{"fingerprint", "monitor", "account", "repository", "subject", "summary", "url", "controls", "severity"}
+
+// WriteCSV writes findings as CSV with a header row, for spreadsheets and audit evidence
+// Compliance controls are joined with "; "
+func WriteCSV(w io.Writer, list []Finding) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, f := range list {
+		record := []string{f.Fingerprint(), f.Monitor, f.Account, f.Repository, f.Subject, f.Summary, f.URL, strings.Join(f.Controls, "; "), f.Severity}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// Changes describes how the findings of a run differ from the previous run
+type Changes struct {
+	New      []Finding `json:"new"`      // Findings that were not reported in the previous run
+	Resolved []Finding `json:"resolved"` // Findings of the previous run that are no longer reported
+
+	// Repositories with findings that had none in the previous run
+	NewlyAffected []string `json:"newly_affected"`
+	// Repositories that had findings in the previous run and have none now
+	NowClean []string `json:"now_clean"`
+}
+
+// Empty reports whether nothing changed
+func (c Changes) Empty() bool {
+	return len(c.New) == 0 && len(c.Resolved) == 0
+}
+
+// Compare computes the changes between the previous and current findings
+func Compare(previous, current []Finding) Changes {
+	previousKeys := make(map[string]bool)
+	previousRepos := make(map[string]bool)
+	for _, f := range previous {
+		previousKeys[f.Fingerprint()] = true
+		previousRepos[f.Repository] = true
+	}
+
+	currentKeys := make(map[string]bool)
+	currentRepos := make(map[string]bool)
+	for _, f := range current {
+		currentKeys[f.Fingerprint()] = true
+		currentRepos[f.Repository] = true
+	}
+
+	changes := Changes{
+		New:           make([]Finding, 0),
+		Resolved:      make([]Finding, 0),
+		NewlyAffected: make([]string, 0),
+		NowClean:      make([]string, 0),
+	}
+
+	for _, f := range current {
+		if !previousKeys[f.Fingerprint()] {
+			changes.New = append(changes.New, f)
+		}
+	}
+
+	for _, f := range previous {
+		if !currentKeys[f.Fingerprint()] {
+			changes.Resolved = append(changes.Resolved, f)
+		}
+	}
+
+	for repo := range currentRepos {
+		if !previousRepos[repo] {
+			changes.NewlyAffected = append(changes.NewlyAffected, repo)
+		}
+	}
+
+	for repo := range previousRepos {
+		if !currentRepos[repo] {
+			changes.NowClean = append(changes.NowClean, repo)
+		}
+	}
+
+	sort.Strings(changes.NewlyAffected)
+	sort.Strings(changes.NowClean)
+
+	return changes
+}
+
+// WriteChangesMarkdown writes the changes since the previous run in a code block format
+// suitable for Slack notifications
+func WriteChangesMarkdown(w io.Writer, changes Changes) {
+	if changes.Empty() {
+		return // No changes to display
+	}
+
+	// Print header for changes
+	fmt.Fprintln(w, "## :arrows_counterclockwise: Changes Since Last Run")
+	fmt.Fprintf(w, "%d new and %d resolved findings since the previous run.\n\n", len(changes.New), len(changes.Resolved))
+
+	// Start code block
+	fmt.Fprintln(w, "```")
+	// Create fixed-width headers with proper spacing for code block
+	fmt.Fprintln(w, "Change    Fingerprint       Monitor                   Repository                Subject")
+	fmt.Fprintln(w, "---------------------------------------------------------------------------------------")
+
+	writeChanges(w, "new", changes.New)
+	writeChanges(w, "resolved", changes.Resolved)
+
+	// End code block
+	fmt.Fprintln(w, "```")
+
+	if len(changes.NewlyAffected) > 0 {
+		fmt.Fprintf(w, "Newly affected repositories: %s\n", strings.Join(changes.NewlyAffected, ", "))
+	}
+	if len(changes.NowClean) > 0 {
+		fmt.Fprintf(w, "Repositories now clean: %s\n", strings.Join(changes.NowClean, ", "))
+	}
+	fmt.Fprintln(w, "")
+}
+
+// writeChanges writes one row per finding in a fixed-width format for code blocks
+func writeChanges(w io.Writer, change string, list []Finding) {
+	for _, f := range list {
+		// Format repository name with padding
+		repoStr := f.Repository
+		if len(repoStr) > 24 {
+			repoStr = repoStr[:21] + "..."
+		} else {
+			repoStr = fmt.Sprintf("%-24s", repoStr)
+		}
+
+		fmt.Fprintf(w, "%-9s %-17s %-25s %s %s\n", change, f.Fingerprint(), f.Monitor, repoStr, f.Subject)
+	}
+}
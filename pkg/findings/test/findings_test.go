@@ -0,0 +1,120 @@
+package test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/anupsv/git-monitoring/pkg/findings"
+)
+
+func finding(repo, subject string) findings.Finding {
+	return findings.Finding{Monitor: "pr_checker", Repository: repo, Subject: subject}
+}
+
+func TestCompare(t *testing.T) {
+	previous := []findings.Finding{
+		finding("owner/kept", "PR #1"),
+		finding("owner/kept", "PR #2"),
+		finding("owner/fixed", "PR #3"),
+	}
+	current := []findings.Finding{
+		finding("owner/kept", "PR #1"),
+		finding("owner/new", "PR #4"),
+	}
+
+	changes := findings.Compare(previous, current)
+
+	if len(changes.New) != 1 || changes.New[0].Subject != "PR #4" {
+		t.Errorf("Expected PR #4 to be new, got %+v", changes.New)
+	}
+	if len(changes.Resolved) != 2 {
+		t.Errorf("Expected 2 resolved findings, got %+v", changes.Resolved)
+	}
+	if len(changes.NewlyAffected) != 1 || changes.NewlyAffected[0] != "owner/new" {
+		t.Errorf("Expected owner/new to be newly affected, got %v", changes.NewlyAffected)
+	}
+	if len(changes.NowClean) != 1 || changes.NowClean[0] != "owner/fixed" {
+		t.Errorf("Expected owner/fixed to be clean, got %v", changes.NowClean)
+	}
+}
+
+func TestCompareUnchanged(t *testing.T) {
+	list := []findings.Finding{finding("owner/repo", "PR #1")}
+
+	if changes := findings.Compare(list, list); !changes.Empty() {
+		t.Errorf("Expected no changes, got %+v", changes)
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	list := []findings.Finding{
+		finding("org:acme", "member bob"),
+		finding("owner/a", "PR #1"),
+		finding("org:acme", "member bob"),
+		finding("owner/b", "PR #1"),
+	}
+
+	unique := findings.Dedupe(list)
+	if len(unique) != 3 || unique[0].Repository != "org:acme" || unique[2].Repository != "owner/b" {
+		t.Errorf("Expected the 3 distinct findings in order, got %+v", unique)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	f := finding("owner/repo", "PR #1")
+
+	// The fingerprint only depends on the monitor, repository and subject
+	changed := f
+	changed.Summary = "PR #1 was merged without approval"
+	changed.URL = "https://github.com/owner/repo/pull/1"
+	if f.Fingerprint() != changed.Fingerprint() {
+		t.Errorf("Expected the fingerprint to ignore the summary and URL")
+	}
+	if f.Fingerprint() != findings.Fingerprint("pr_checker", "owner/repo", "PR #1") {
+		t.Errorf("Expected the fingerprint to be computed from the monitor, repository and subject")
+	}
+	if len(f.Fingerprint()) != 16 {
+		t.Errorf("Expected a 16 character fingerprint, got %q", f.Fingerprint())
+	}
+
+	// Fields are separated, so they cannot run into each other
+	if findings.Fingerprint("pr_checker", "owner/repo", "PR #1") == findings.Fingerprint("pr_checker", "owner/rep", "oPR #1") {
+		t.Error("Expected different findings to have different fingerprints")
+	}
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if !strings.Contains(string(data), `"fingerprint":"`+f.Fingerprint()+`"`) {
+		t.Errorf("Expected the fingerprint in the JSON output, got %s", data)
+	}
+
+	var decoded findings.Finding
+	if err := json.Unmarshal(data, &decoded); err != nil || !reflect.DeepEqual(decoded, f) {
+		t.Errorf("Expected the finding to round trip through JSON, got %+v (%v)", decoded, err)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	f := findings.Finding{
+		Monitor:    "pr_checker",
+		Repository: "owner/repo",
+		Subject:    "PR #1",
+		Summary:    `Fix "login", again by alice merged without approval`,
+		Controls:   []string{"SOC2 CC8.1", "ISO 27001 A.8.32"},
+	}
+
+	var buf strings.Builder
+	if err := findings.WriteCSV(&buf, []findings.Finding{f}); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	expected := "fingerprint,monitor,account,repository,subject,summary,url,controls,severity\n" +
+		f.Fingerprint() + `,pr_checker,,owner/repo,PR #1,"Fix ""login"", again by alice merged without approval",,SOC2 CC8.1; ISO 27001 A.8.32,` + "\n"
+	if buf.String() != expected {
+		t.Errorf("Expected CSV:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
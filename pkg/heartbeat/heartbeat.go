@@ -0,0 +1,75 @@
+package heartbeat
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+)
+
+// pingTimeout bounds each ping, so an unavailable check never holds up a run
+const pingTimeout = 10 * time.Second
+
+// Pinger pings the URLs of a dead man's switch check at the start and end of a run
+// Methods on a nil Pinger are no-ops, so callers do not need to check whether the heartbeat is configured
+type Pinger struct {
+	config config.HeartbeatConfig
+	client *http.Client
+}
+
+// New creates a Pinger for the configured check, or nil when the heartbeat is not configured
+func New(cfg config.HeartbeatConfig) *Pinger {
+	if cfg.URL == "" {
+		return nil
+	}
+	return &Pinger{config: cfg, client: &http.Client{Timeout: pingTimeout}}
+}
+
+// Start pings the start URL, when one is configured, so the check can also alert on runs that hang
+func (p *Pinger) Start() error {
+	if p == nil || p.config.StartURL == "" {
+		return nil
+	}
+	return p.ping(p.config.StartURL)
+}
+
+// Success pings the check's URL after a successful run
+func (p *Pinger) Success() error {
+	if p == nil {
+		return nil
+	}
+	return p.ping(p.config.URL)
+}
+
+// Fail pings the fail URL after a run with failed monitors, when one is configured
+// Without a fail URL the check is not pinged, so it alerts once the run is overdue
+func (p *Pinger) Fail() error {
+	if p == nil || p.config.FailURL == "" {
+		return nil
+	}
+	return p.ping(p.config.FailURL)
+}
+
+// ping sends a GET request to target, failing on non-2xx responses
+// The URL is left out of errors, as it identifies the check and often works as its credential
+func (p *Pinger) ping(target string) error {
+	resp, err := p.client.Get(target)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to ping heartbeat: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("heartbeat ping failed: HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
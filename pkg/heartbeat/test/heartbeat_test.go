@@ -0,0 +1,69 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/heartbeat"
+)
+
+func TestPinger(t *testing.T) {
+	var pinged []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pinged = append(pinged, r.URL.Path)
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	pinger := heartbeat.New(config.HeartbeatConfig{
+		URL:      server.URL + "/check",
+		StartURL: server.URL + "/check/start",
+		FailURL:  server.URL + "/check/fail",
+	})
+	for _, ping := range []func() error{pinger.Start, pinger.Success, pinger.Fail} {
+		if err := ping(); err != nil {
+			t.Fatalf("Did not expect an error but got: %v", err)
+		}
+	}
+	if got := strings.Join(pinged, ","); got != "/check/start,/check,/check/fail" {
+		t.Errorf("Unexpected pings: %s", got)
+	}
+
+	// Only the success URL is required, the others are skipped when not configured
+	pinged = nil
+	pinger = heartbeat.New(config.HeartbeatConfig{URL: server.URL + "/check"})
+	if err := pinger.Start(); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if err := pinger.Fail(); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if len(pinged) != 0 {
+		t.Errorf("Expected no pings, got %v", pinged)
+	}
+
+	// Error responses are reported without the URL
+	err := heartbeat.New(config.HeartbeatConfig{URL: server.URL + "/down"}).Success()
+	if err == nil || !strings.Contains(err.Error(), "HTTP 503") || strings.Contains(err.Error(), server.URL) {
+		t.Errorf("Expected an HTTP 503 error without the URL, got %v", err)
+	}
+}
+
+func TestNilPinger(t *testing.T) {
+	// Without a URL the heartbeat is disabled and pings are no-ops
+	pinger := heartbeat.New(config.HeartbeatConfig{})
+	if pinger != nil {
+		t.Fatalf("Expected no pinger without a URL, got %+v", pinger)
+	}
+	if err := pinger.Start(); err != nil {
+		t.Errorf("Did not expect an error but got: %v", err)
+	}
+	if err := pinger.Success(); err != nil {
+		t.Errorf("Did not expect an error but got: %v", err)
+	}
+}
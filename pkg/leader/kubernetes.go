@@ -0,0 +1,231 @@
+package leader
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// serviceAccountDir holds the credentials Kubernetes mounts into pods
+const serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// microTime is the format of the times of a Lease
+const microTime = "2006-01-02T15:04:05.000000Z07:00"
+
+// KubernetesLock keeps the lease in a coordination.k8s.io/v1 Lease, the way Kubernetes controllers elect leaders
+// The service account of the pods needs get, create and update permissions on leases in the namespace
+type KubernetesLock struct {
+	APIServer string                 // URL of the API server
+	Token     func() (string, error) // Bearer token of the service account, read for each request as it rotates
+	Client    *http.Client
+	Namespace string
+	Name      string // Name of the Lease
+}
+
+// NewInClusterLock returns the lock of the named Lease with the pod's service account
+// An empty namespace is the pod's namespace
+func NewInClusterLock(namespace, name string) (*KubernetesLock, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, fmt.Errorf("not running in a Kubernetes pod: KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT are not set")
+	}
+
+	if namespace == "" {
+		data, err := os.ReadFile(serviceAccountDir + "/namespace")
+		if err != nil {
+			return nil, fmt.Errorf("error reading the pod's namespace: %v", err)
+		}
+		namespace = strings.TrimSpace(string(data))
+	}
+
+	ca, err := os.ReadFile(serviceAccountDir + "/ca.crt")
+	if err != nil {
+		return nil, fmt.Errorf("error reading the cluster's CA certificate: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("invalid CA certificate in %s/ca.crt", serviceAccountDir)
+	}
+
+	return &KubernetesLock{
+		APIServer: "https://" + net.JoinHostPort(host, port),
+		Token: func() (string, error) {
+			token, err := os.ReadFile(serviceAccountDir + "/token")
+			return strings.TrimSpace(string(token)), err
+		},
+		Client: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}},
+		},
+		Namespace: namespace,
+		Name:      name,
+	}, nil
+}
+
+// lease is the part of a Lease the lock reads and writes
+// The metadata is written back as read, with the resourceVersion the update is conditional on
+type lease struct {
+	APIVersion string          `json:"apiVersion"`
+	Kind       string          `json:"kind"`
+	Metadata   json.RawMessage `json:"metadata"`
+	Spec       leaseSpec       `json:"spec"`
+}
+
+// leaseSpec is the spec of a Lease
+type leaseSpec struct {
+	HolderIdentity       string `json:"holderIdentity,omitempty"`
+	LeaseDurationSeconds int    `json:"leaseDurationSeconds,omitempty"`
+	AcquireTime          string `json:"acquireTime,omitempty"`
+	RenewTime            string `json:"renewTime,omitempty"`
+	LeaseTransitions     int    `json:"leaseTransitions"`
+}
+
+// expired reports whether the lease lapsed without being renewed
+func (s leaseSpec) expired(now time.Time) bool {
+	renewed, err := time.Parse(microTime, s.RenewTime)
+	if err != nil {
+		return true
+	}
+	return !now.Before(renewed.Add(time.Duration(s.LeaseDurationSeconds) * time.Second))
+}
+
+// Acquire creates the Lease, renews it or takes it over once expired
+// Updates carry the resourceVersion read, so the API server rejects them when another replica changed the Lease
+func (l *KubernetesLock) Acquire(ctx context.Context, identity string, duration time.Duration, now time.Time) (bool, error) {
+	current, err := l.get(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	renewTime := now.UTC().Format(microTime)
+	if current == nil {
+		metadata, err := json.Marshal(map[string]string{"name": l.Name, "namespace": l.Namespace})
+		if err != nil {
+			return false, err
+		}
+		created := lease{
+			APIVersion: "coordination.k8s.io/v1",
+			Kind:       "Lease",
+			Metadata:   metadata,
+			Spec: leaseSpec{
+				HolderIdentity:       identity,
+				LeaseDurationSeconds: int(duration.Seconds()),
+				AcquireTime:          renewTime,
+				RenewTime:            renewTime,
+			},
+		}
+		return l.write(ctx, http.MethodPost, l.url(""), created)
+	}
+
+	spec := current.Spec
+	if spec.HolderIdentity != identity {
+		if spec.HolderIdentity != "" && !spec.expired(now) {
+			return false, nil
+		}
+		spec.HolderIdentity = identity
+		spec.AcquireTime = renewTime
+		spec.LeaseTransitions++
+	}
+	spec.LeaseDurationSeconds = int(duration.Seconds())
+	spec.RenewTime = renewTime
+	current.Spec = spec
+	return l.write(ctx, http.MethodPut, l.url(l.Name), *current)
+}
+
+// Release clears the holder of the Lease when identity holds it
+func (l *KubernetesLock) Release(ctx context.Context, identity string) error {
+	current, err := l.get(ctx)
+	if err != nil || current == nil || current.Spec.HolderIdentity != identity {
+		return err
+	}
+	current.Spec.HolderIdentity = ""
+	_, err = l.write(ctx, http.MethodPut, l.url(l.Name), *current)
+	return err
+}
+
+// url returns the URL of the namespace's leases, or of the named one
+func (l *KubernetesLock) url(name string) string {
+	url := fmt.Sprintf("%s/apis/coordination.k8s.io/v1/namespaces/%s/leases", strings.TrimSuffix(l.APIServer, "/"), l.Namespace)
+	if name != "" {
+		url += "/" + name
+	}
+	return url
+}
+
+// get returns the Lease, nil when it does not exist yet
+func (l *KubernetesLock) get(ctx context.Context) (*lease, error) {
+	status, body, err := l.do(ctx, http.MethodGet, l.url(l.Name), nil)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound {
+		return nil, nil
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("getting lease %s/%s returned %d: %s", l.Namespace, l.Name, status, body)
+	}
+
+	var current lease
+	if err := json.Unmarshal(body, &current); err != nil {
+		return nil, fmt.Errorf("invalid lease %s/%s: %v", l.Namespace, l.Name, err)
+	}
+	return &current, nil
+}
+
+// write creates or replaces the Lease, and reports false when another replica changed it first
+func (l *KubernetesLock) write(ctx context.Context, method, url string, desired lease) (bool, error) {
+	payload, err := json.Marshal(desired)
+	if err != nil {
+		return false, err
+	}
+	status, body, err := l.do(ctx, method, url, payload)
+	if err != nil {
+		return false, err
+	}
+
+	switch status {
+	case http.StatusOK, http.StatusCreated:
+		return true, nil
+	case http.StatusConflict:
+		return false, nil
+	default:
+		return false, fmt.Errorf("writing lease %s/%s returned %d: %s", l.Namespace, l.Name, status, body)
+	}
+}
+
+// do sends an authenticated request to the API server
+func (l *KubernetesLock) do(ctx context.Context, method, url string, payload []byte) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if l.Token != nil {
+		token, err := l.Token()
+		if err != nil {
+			return 0, nil, fmt.Errorf("error reading service account token: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := l.Client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	return resp.StatusCode, body, err
+}
@@ -0,0 +1,76 @@
+package leader
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+// Lock is a lease held by one replica of the server at a time
+type Lock interface {
+	// Acquire takes the lease for identity, or renews it, until now+duration, and reports whether identity holds it
+	// A lease held by another identity is only taken once it expired
+	Acquire(ctx context.Context, identity string, duration time.Duration, now time.Time) (bool, error)
+
+	// Release ends the lease of identity, so another replica takes over without waiting for it to expire
+	Release(ctx context.Context, identity string) error
+}
+
+// Elector keeps a replica's leadership up to date, so only the leader scans and sends notifications
+type Elector struct {
+	lock     Lock
+	identity string
+	duration time.Duration
+	leader   atomic.Bool
+}
+
+// NewElector creates an elector competing for the lock as identity, with leases lasting duration
+func NewElector(lock Lock, identity string, duration time.Duration) *Elector {
+	return &Elector{lock: lock, identity: identity, duration: duration}
+}
+
+// IsLeader reports whether this replica holds the lease
+func (e *Elector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Run competes for the lease until the context is cancelled, renewing it every third of its duration,
+// and then releases it. A replica that cannot renew its lease steps down at once rather than risk two leaders
+func (e *Elector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.duration / 3)
+	defer ticker.Stop()
+
+	for {
+		e.attempt(ctx)
+		select {
+		case <-ctx.Done():
+			if e.leader.Swap(false) {
+				releaseCtx, cancel := context.WithTimeout(context.Background(), e.duration/3)
+				defer cancel()
+				if err := e.lock.Release(releaseCtx, e.identity); err != nil {
+					log.Printf("Error releasing leadership: %v", err)
+				}
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// attempt acquires or renews the lease once
+func (e *Elector) attempt(ctx context.Context) {
+	acquired, err := e.lock.Acquire(ctx, e.identity, e.duration, time.Now())
+	if err != nil {
+		log.Printf("Error acquiring leadership: %v", err)
+		acquired = false
+	}
+
+	if was := e.leader.Swap(acquired); was != acquired {
+		if acquired {
+			log.Printf("%s became the leader, scanning and sending notifications", e.identity)
+		} else {
+			log.Printf("%s is no longer the leader, leaving scans and notifications to the leader", e.identity)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package leader
+
+import (
+	"context"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/state"
+)
+
+// StateLock keeps the lease in the state, so replicas sharing a state store elect a leader without Kubernetes
+// Stores shared across hosts, PostgreSQL or S3, save conditionally, so two replicas cannot both take the lease
+type StateLock struct {
+	Path string // Path or URL of the state store
+}
+
+// Acquire takes or renews the lease in the state
+func (l StateLock) Acquire(_ context.Context, identity string, duration time.Duration, now time.Time) (bool, error) {
+	acquired := false
+	err := state.Update(l.Path, func(s *state.State) error {
+		// Retried updates start over, so a lease taken by another replica meanwhile is seen
+		acquired = false
+		if s.Leader != nil && s.Leader.Holder != identity && now.Before(s.Leader.Expires) {
+			return nil
+		}
+		s.Leader = &state.Lease{Holder: identity, Expires: now.Add(duration)}
+		acquired = true
+		return nil
+	})
+	return acquired, err
+}
+
+// Release removes the lease from the state when identity holds it
+func (l StateLock) Release(_ context.Context, identity string) error {
+	return state.Update(l.Path, func(s *state.State) error {
+		if s.Leader != nil && s.Leader.Holder == identity {
+			s.Leader = nil
+		}
+		return nil
+	})
+}
@@ -0,0 +1,207 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/leader"
+)
+
+// testLocks checks that a lock is held by one identity at a time, taken over once expired and released
+func testLocks(t *testing.T, lock leader.Lock) {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Now()
+
+	if ok, err := lock.Acquire(ctx, "replica-a", 30*time.Second, now); err != nil || !ok {
+		t.Fatalf("Expected replica-a to acquire the free lease, got %v, %v", ok, err)
+	}
+	if ok, err := lock.Acquire(ctx, "replica-b", 30*time.Second, now.Add(10*time.Second)); err != nil || ok {
+		t.Fatalf("Expected replica-b not to acquire the lease of replica-a, got %v, %v", ok, err)
+	}
+	if ok, err := lock.Acquire(ctx, "replica-a", 30*time.Second, now.Add(20*time.Second)); err != nil || !ok {
+		t.Fatalf("Expected replica-a to renew its lease, got %v, %v", ok, err)
+	}
+	// The renewal extended the lease past its first expiry
+	if ok, err := lock.Acquire(ctx, "replica-b", 30*time.Second, now.Add(40*time.Second)); err != nil || ok {
+		t.Fatalf("Expected replica-b not to acquire the renewed lease, got %v, %v", ok, err)
+	}
+	if ok, err := lock.Acquire(ctx, "replica-b", 30*time.Second, now.Add(60*time.Second)); err != nil || !ok {
+		t.Fatalf("Expected replica-b to take over the expired lease, got %v, %v", ok, err)
+	}
+
+	// Releasing a lease held by another replica does nothing
+	if err := lock.Release(ctx, "replica-a"); err != nil {
+		t.Fatalf("Failed to release: %v", err)
+	}
+	if ok, err := lock.Acquire(ctx, "replica-a", 30*time.Second, now.Add(61*time.Second)); err != nil || ok {
+		t.Fatalf("Expected the lease of replica-b to be kept, got %v, %v", ok, err)
+	}
+	if err := lock.Release(ctx, "replica-b"); err != nil {
+		t.Fatalf("Failed to release: %v", err)
+	}
+	if ok, err := lock.Acquire(ctx, "replica-a", 30*time.Second, now.Add(62*time.Second)); err != nil || !ok {
+		t.Fatalf("Expected replica-a to acquire the released lease, got %v, %v", ok, err)
+	}
+}
+
+func TestStateLock(t *testing.T) {
+	dir := t.TempDir()
+	for _, path := range []string{filepath.Join(dir, "state.json"), "sqlite://" + filepath.Join(dir, "state.sqlite")} {
+		t.Run(path, func(t *testing.T) {
+			testLocks(t, leader.StateLock{Path: path})
+		})
+	}
+}
+
+// fakeLeases serves the Lease API of Kubernetes, with resource versions checked on updates
+type fakeLeases struct {
+	mu      sync.Mutex
+	lease   map[string]interface{}
+	version int
+	// Changes the lease between the read and the write of the next update
+	interfere bool
+}
+
+func (f *fakeLeases) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer token" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if f.lease == nil {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(f.lease)
+	case http.MethodPost, http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		var lease map[string]interface{}
+		json.Unmarshal(body, &lease)
+		metadata := lease["metadata"].(map[string]interface{})
+
+		if r.Method == http.MethodPost && f.lease != nil {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		if r.Method == http.MethodPut && (f.interfere || metadata["resourceVersion"] != f.lease["metadata"].(map[string]interface{})["resourceVersion"]) {
+			f.interfere = false
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		f.version++
+		metadata["resourceVersion"] = string(rune('0' + f.version))
+		f.lease = lease
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(lease)
+	}
+}
+
+func TestKubernetesLock(t *testing.T) {
+	fake := &fakeLeases{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	lock := &leader.KubernetesLock{
+		APIServer: server.URL,
+		Token:     func() (string, error) { return "token", nil },
+		Client:    server.Client(),
+		Namespace: "monitoring",
+		Name:      "git-monitor",
+	}
+	testLocks(t, lock)
+
+	spec := fake.lease["spec"].(map[string]interface{})
+	if spec["holderIdentity"] != "replica-a" || spec["leaseDurationSeconds"] != float64(30) {
+		t.Errorf("Unexpected lease spec %+v", spec)
+	}
+
+	// Another replica updated the lease since it was read
+	fake.interfere = true
+	if ok, err := lock.Acquire(context.Background(), "replica-a", 30*time.Second, time.Now().Add(70*time.Second)); err != nil || ok {
+		t.Errorf("Expected a conflicting update not to acquire the lease, got %v, %v", ok, err)
+	}
+}
+
+// flakyLock holds the lease until it fails
+type flakyLock struct {
+	mu     sync.Mutex
+	fail   bool
+	leader string
+}
+
+func (l *flakyLock) Acquire(_ context.Context, identity string, _ time.Duration, _ time.Time) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.fail {
+		return false, io.ErrUnexpectedEOF
+	}
+	if l.leader == "" {
+		l.leader = identity
+	}
+	return l.leader == identity, nil
+}
+
+func (l *flakyLock) Release(_ context.Context, identity string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.leader == identity {
+		l.leader = ""
+	}
+	return nil
+}
+
+// waitFor polls a condition until it holds
+func waitFor(t *testing.T, description string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", description)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestElector(t *testing.T) {
+	lock := &flakyLock{}
+	ctx, cancel := context.WithCancel(context.Background())
+	elector := leader.NewElector(lock, "replica-a", 30*time.Millisecond)
+	done := make(chan struct{})
+	go func() {
+		elector.Run(ctx)
+		close(done)
+	}()
+	waitFor(t, "replica-a to lead", elector.IsLeader)
+
+	// A replica that cannot renew its lease steps down
+	lock.mu.Lock()
+	lock.fail = true
+	lock.mu.Unlock()
+	waitFor(t, "replica-a to step down", func() bool { return !elector.IsLeader() })
+
+	lock.mu.Lock()
+	lock.fail = false
+	lock.mu.Unlock()
+	waitFor(t, "replica-a to lead again", elector.IsLeader)
+
+	// Stopping releases the lease for the other replicas
+	cancel()
+	<-done
+	if elector.IsLeader() || lock.leader != "" {
+		t.Errorf("Expected the lease to be released when stopping, held by %q", lock.leader)
+	}
+}
@@ -0,0 +1,152 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/coverage"
+	"github.com/anupsv/git-monitoring/pkg/usage"
+)
+
+// Run is the telemetry of a run: how long each monitor took, what it checked and what it cost
+type Run struct {
+	FinishedAt time.Time
+	Usage      *usage.Report
+	Coverage   []coverage.Monitor
+}
+
+// metric is a gauge in the Prometheus text format
+type metric struct {
+	name    string
+	help    string
+	samples []sample
+}
+
+// sample is a value of a metric with its labels, as name and value pairs
+type sample struct {
+	labels []string
+	value  float64
+}
+
+// monitorLabels labels a sample with the monitor and its account
+func monitorLabels(monitor, account string, extra ...string) []string {
+	return append([]string{"monitor", monitor, "account", account}, extra...)
+}
+
+// metrics converts the telemetry of a run to gauges, in a stable order
+func (r Run) metrics() []metric {
+	duration := metric{name: "git_monitor_monitor_duration_seconds", help: "How long the monitor ran"}
+	apiCalls := metric{name: "git_monitor_monitor_api_calls", help: "GitHub API requests sent by the monitor, including rate limit checks"}
+	cacheHits := metric{name: "git_monitor_monitor_cache_hits", help: "Membership lookups of the monitor answered from the cache"}
+	cacheMisses := metric{name: "git_monitor_monitor_cache_misses", help: "Membership lookups of the monitor that needed an API call"}
+	targets := metric{name: "git_monitor_monitor_targets", help: "Repositories and organizations of the monitor, by whether they were checked, skipped, errored, or left out as archived or outside the sample"}
+	remaining := metric{name: "git_monitor_rate_limit_remaining", help: "Requests left in the rate limit of the account's token at the end of the run"}
+	limit := metric{name: "git_monitor_rate_limit", help: "Requests allowed per hour by the rate limit of the account's token"}
+
+	if r.Usage != nil {
+		for _, m := range r.Usage.Monitors {
+			labels := monitorLabels(m.Monitor, m.Account)
+			duration.samples = append(duration.samples, sample{labels, m.DurationSeconds})
+			apiCalls.samples = append(apiCalls.samples, sample{labels, float64(m.APICalls)})
+			cacheHits.samples = append(cacheHits.samples, sample{labels, float64(m.CacheHits)})
+			cacheMisses.samples = append(cacheMisses.samples, sample{labels, float64(m.CacheMisses)})
+		}
+		for _, l := range r.Usage.RateLimits {
+			labels := []string{"account", l.Account}
+			remaining.samples = append(remaining.samples, sample{labels, float64(l.Remaining)})
+			limit.samples = append(limit.samples, sample{labels, float64(l.Limit)})
+		}
+	}
+
+	for _, c := range r.Coverage {
+		targets.samples = append(targets.samples,
+			sample{monitorLabels(c.Monitor, c.Account, "status", "checked"), float64(c.Checked)},
+			sample{monitorLabels(c.Monitor, c.Account, "status", "skipped"), float64(len(c.Skipped))},
+			sample{monitorLabels(c.Monitor, c.Account, "status", "errored"), float64(c.Errored)},
+			sample{monitorLabels(c.Monitor, c.Account, "status", "archived"), float64(c.Archived)},
+			sample{monitorLabels(c.Monitor, c.Account, "status", "unsampled"), float64(c.Unsampled)},
+			sample{monitorLabels(c.Monitor, c.Account, "status", "other_shards"), float64(c.OtherShards)},
+		)
+	}
+
+	finished := metric{name: "git_monitor_last_run_timestamp_seconds", help: "When the last run finished, in seconds since the Unix epoch"}
+	if !r.FinishedAt.IsZero() {
+		finished.samples = []sample{{nil, float64(r.FinishedAt.Unix())}}
+	}
+
+	return []metric{finished, duration, apiCalls, cacheHits, cacheMisses, targets, remaining, limit}
+}
+
+// Write writes the telemetry of a run in the Prometheus text format
+// Metrics without samples are left out, e.g. the rate limits of a run that sent no requests
+func (r Run) Write(w io.Writer) error {
+	for _, m := range r.metrics() {
+		if len(m.samples) == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", m.name, m.help, m.name); err != nil {
+			return err
+		}
+		for _, s := range m.samples {
+			if _, err := fmt.Fprintf(w, "%s%s %s\n", m.name, formatLabels(s.labels), strconv.FormatFloat(s.value, 'g', -1, 64)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteFile writes the telemetry of a run to path
+// The file is replaced atomically so collectors never read a partially written file
+func (r Run) WriteFile(path string) error {
+	var buf strings.Builder
+	if err := r.Write(&buf); err != nil {
+		return fmt.Errorf("failed to encode metrics: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".metrics-*.prom")
+	if err != nil {
+		return fmt.Errorf("failed to create metrics file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(buf.String()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write metrics file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write metrics file: %w", err)
+	}
+
+	// Temporary files are private, collectors run as other users
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write metrics file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace metrics file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// labelEscaper escapes label values as required by the Prometheus text format
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatLabels formats name and value pairs as a label set, empty without labels
+func formatLabels(labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, labels[i], labelEscaper.Replace(labels[i+1])))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
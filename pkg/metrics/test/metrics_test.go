@@ -0,0 +1,82 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/coverage"
+	"github.com/anupsv/git-monitoring/pkg/metrics"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	"github.com/anupsv/git-monitoring/pkg/usage"
+)
+
+func TestWrite(t *testing.T) {
+	report := usage.NewReport()
+	report.Monitors = append(report.Monitors, usage.Monitor{Monitor: "pr_checker", Account: `ac"me`, APICalls: 42, DurationSeconds: 1.5, CacheHits: 3, CacheMisses: 1})
+	report.RateLimits = append(report.RateLimits, usage.RateLimit{Account: `ac"me`, RateLimit: common.RateLimit{Limit: 5000, Remaining: 4958}})
+
+	run := metrics.Run{
+		FinishedAt: time.Unix(1700000000, 0),
+		Usage:      report,
+		Coverage: []coverage.Monitor{{
+			Monitor: "pr_checker",
+			Account: `ac"me`,
+			Checked: 10,
+			Errored: 2,
+			Skipped: []common.SkippedTarget{{Target: "owner/repo", Reason: common.StopBudget}},
+		}},
+	}
+
+	var buf strings.Builder
+	if err := run.Write(&buf); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	output := buf.String()
+
+	expected := []string{
+		"# TYPE git_monitor_last_run_timestamp_seconds gauge\ngit_monitor_last_run_timestamp_seconds 1.7e+09\n",
+		`git_monitor_monitor_duration_seconds{monitor="pr_checker",account="ac\"me"} 1.5`,
+		`git_monitor_monitor_api_calls{monitor="pr_checker",account="ac\"me"} 42`,
+		`git_monitor_monitor_cache_hits{monitor="pr_checker",account="ac\"me"} 3`,
+		`git_monitor_monitor_cache_misses{monitor="pr_checker",account="ac\"me"} 1`,
+		`git_monitor_monitor_targets{monitor="pr_checker",account="ac\"me",status="checked"} 10`,
+		`git_monitor_monitor_targets{monitor="pr_checker",account="ac\"me",status="skipped"} 1`,
+		`git_monitor_monitor_targets{monitor="pr_checker",account="ac\"me",status="errored"} 2`,
+		`git_monitor_rate_limit_remaining{account="ac\"me"} 4958`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", e, output)
+		}
+	}
+}
+
+func TestWriteEmpty(t *testing.T) {
+	var buf strings.Builder
+	if err := (metrics.Run{}).Write(&buf); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	// Metrics without samples are left out rather than declared empty
+	if buf.Len() != 0 {
+		t.Errorf("Expected no metrics, got:\n%s", buf.String())
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "git-monitor.prom")
+	run := metrics.Run{FinishedAt: time.Now(), Usage: usage.NewReport()}
+	if err := run.WriteFile(path); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read metrics file: %v", err)
+	}
+	if !strings.Contains(string(data), "git_monitor_last_run_timestamp_seconds") {
+		t.Errorf("Unexpected metrics file:\n%s", data)
+	}
+}
@@ -0,0 +1,48 @@
+package notify
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/state"
+)
+
+// Changed reports whether the findings differ from those last notified to the target, as recorded in the state
+// at path by Notified. Targets never notified count as changed
+func Changed(path, target string, list []findings.Finding) (bool, error) {
+	s, err := state.Load(path)
+	if err != nil {
+		return true, err
+	}
+	notified, ok := s.Notified[target]
+	return !ok || notified != Digest(list), nil
+}
+
+// Notified records the findings notified to the target in the state at path, to tell with Changed
+// whether later runs have anything new to notify
+func Notified(path, target string, list []findings.Finding) error {
+	return state.Update(path, func(s *state.State) error {
+		if s.Notified == nil {
+			s.Notified = make(map[string]string)
+		}
+		s.Notified[target] = Digest(list)
+		return nil
+	})
+}
+
+// Digest identifies a set of findings by their fingerprints, whatever their order
+func Digest(list []findings.Finding) string {
+	fingerprints := make([]string, 0, len(list))
+	for _, f := range list {
+		fingerprints = append(fingerprints, f.Fingerprint())
+	}
+	sort.Strings(fingerprints)
+
+	h := sha256.New()
+	for _, fingerprint := range fingerprints {
+		h.Write([]byte(fingerprint + "\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
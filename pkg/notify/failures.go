@@ -0,0 +1,39 @@
+package notify
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Failure is a monitor run that failed, e.g. because of a revoked token or an API outage
+type Failure struct {
+	Monitor string // Human readable name of the monitor
+	Account string // Account the monitor ran for, empty without multiple accounts
+	Err     error
+}
+
+// WriteFailuresMarkdown writes an operational alert listing the monitors that failed
+// Their findings may be missing from the report, so the alert goes to whoever operates the monitoring
+func WriteFailuresMarkdown(w io.Writer, failures []Failure) {
+	if len(failures) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "## :rotating_light: Monitor Failures")
+	fmt.Fprintf(w, "%d monitor runs failed, their findings may be missing from the report.\n\n", len(failures))
+
+	for _, f := range failures {
+		monitor := f.Monitor
+		if f.Account != "" {
+			monitor += " (" + f.Account + ")"
+		}
+		reason := "unknown error"
+		if f.Err != nil {
+			// Joined errors span several lines, keep each failure on one
+			reason = strings.ReplaceAll(f.Err.Error(), "\n", "; ")
+		}
+		fmt.Fprintf(w, "- %s failed: %s\n", monitor, reason)
+	}
+	fmt.Fprintln(w, "")
+}
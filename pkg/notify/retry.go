@@ -0,0 +1,67 @@
+package notify
+
+import (
+	"log"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/state"
+)
+
+// Targets of notifications, resolved to their webhooks when the notifications are sent
+const (
+	TargetSlack = "slack" // The Slack webhook of the run
+	TargetPage  = "page"  // The page webhook of the risk score
+	TargetOps   = "ops"   // The webhook of the operations channel
+	TargetSLA   = "sla"   // The webhook notified of SLA breaches
+
+	// Prefix of the targets of teams, followed by the team name, e.g. "team:payments"
+	TargetTeamPrefix = "team:"
+	// Prefix of the targets of escalation policies, followed by the policy name, e.g. "escalation:managers"
+	TargetEscalationPrefix = "escalation:"
+)
+
+// Sender delivers content to a target, returning false when it could not be delivered
+type Sender func(target, content string) bool
+
+// Enqueue keeps a notification that could not be delivered in the state at path, so it is retried later
+func Enqueue(path, target, content string, now time.Time) error {
+	return state.Update(path, func(s *state.State) error {
+		s.Undelivered = append(s.Undelivered, state.Notification{
+			Target:      target,
+			Content:     content,
+			QueuedAt:    now,
+			Attempts:    1,
+			LastAttempt: now,
+		})
+		return nil
+	})
+}
+
+// Retry sends the notifications queued in the state at path, oldest first, and returns how many were delivered
+// and how many are still queued. Notifications that fail again stay queued, and those queued longer than maxAge
+// are dropped with a log message, so an unreachable target does not grow the state forever
+func Retry(path string, send Sender, now time.Time, maxAge time.Duration) (delivered, pending int, err error) {
+	err = state.Update(path, func(s *state.State) error {
+		var kept []state.Notification
+		for _, n := range s.Undelivered {
+			if now.Sub(n.QueuedAt) > maxAge {
+				log.Printf("Dropping %s notification queued at %s after %d failed attempts", n.Target, n.QueuedAt.Format(time.RFC3339), n.Attempts)
+				continue
+			}
+
+			if send(n.Target, n.Content) {
+				delivered++
+				continue
+			}
+
+			n.Attempts++
+			n.LastAttempt = now
+			kept = append(kept, n)
+		}
+
+		s.Undelivered = kept
+		pending = len(kept)
+		return nil
+	})
+	return delivered, pending, err
+}
@@ -0,0 +1,195 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/encryption"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+)
+
+// digestHeader introduces findings that were queued outside business hours
+const digestHeader = "## :sunrise: Off-Hours Digest\nThe following findings were detected outside business hours.\n\n"
+
+// Section is the rendered output of a single monitor
+type Section struct {
+	Monitor string // Configuration key of the monitor (e.g. "pr_checker")
+	Content string
+}
+
+// Join concatenates the content of the sections
+func Join(sections []Section) string {
+	var builder strings.Builder
+	for _, section := range sections {
+		builder.WriteString(section.Content)
+	}
+	return builder.String()
+}
+
+// Plan describes what should be sent for a run once the schedule is applied
+type Plan struct {
+	Content       string    // Content to send now, empty when nothing should be sent
+	Queued        []Section // Sections queued for the next digest
+	IncludeDigest bool      // Whether Content includes the queued digest
+}
+
+// Scheduler decides whether findings are sent immediately or queued for the next digest
+type Scheduler struct {
+	config   config.ScheduleConfig
+	location *time.Location
+	days     map[time.Weekday]bool
+	critical map[string]bool // Monitors listed in critical_monitors, sent immediately whatever their findings
+}
+
+// NewScheduler creates a new Scheduler
+func NewScheduler(cfg *config.Config) *Scheduler {
+	weekdays := map[string]time.Weekday{
+		"sun": time.Sunday,
+		"mon": time.Monday,
+		"tue": time.Tuesday,
+		"wed": time.Wednesday,
+		"thu": time.Thursday,
+		"fri": time.Friday,
+		"sat": time.Saturday,
+	}
+
+	days := make(map[time.Weekday]bool)
+	for _, day := range cfg.Notifications.Schedule.Days {
+		if weekday, ok := weekdays[day]; ok {
+			days[weekday] = true
+		}
+	}
+
+	critical := make(map[string]bool)
+	for _, monitor := range cfg.Notifications.Schedule.CriticalMonitors {
+		critical[monitor] = true
+	}
+
+	location := cfg.Location()
+	if location == nil {
+		location = time.UTC
+	}
+
+	return &Scheduler{
+		config:   cfg.Notifications.Schedule,
+		location: location,
+		days:     days,
+		critical: critical,
+	}
+}
+
+// InBusinessHours reports whether the given time falls within the configured business hours
+func (s *Scheduler) InBusinessHours(t time.Time) bool {
+	local := t.In(s.location)
+	if !s.days[local.Weekday()] {
+		return false
+	}
+
+	start, err := time.Parse("15:04", s.config.Start)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse("15:04", s.config.End)
+	if err != nil {
+		return false
+	}
+
+	minutes := local.Hour()*60 + local.Minute()
+	return minutes >= start.Hour()*60+start.Minute() && minutes < end.Hour()*60+end.Minute()
+}
+
+// Plan applies the schedule to the sections of a run
+// During business hours the queued digest is prepended to the current findings,
+// and emptyMessage is sent when there is nothing to report. Outside business hours
+// only the sections of monitors with a critical finding in list, once escalations are applied,
+// or listed in critical_monitors are sent, the remaining sections are appended to the queue
+func (s *Scheduler) Plan(now time.Time, sections []Section, list []findings.Finding, emptyMessage string) (*Plan, error) {
+	if s.InBusinessHours(now) {
+		digest, err := s.loadDigest()
+		if err != nil {
+			return nil, err
+		}
+
+		content := Join(sections)
+		if digest != "" {
+			content = digestHeader + digest + content
+		}
+		if content == "" {
+			content = emptyMessage
+		}
+
+		return &Plan{Content: content, IncludeDigest: digest != ""}, nil
+	}
+
+	critical := make(map[string]bool)
+	for _, f := range list {
+		if f.Severity == findings.SeverityCritical {
+			critical[f.Monitor] = true
+		}
+	}
+
+	var immediate, queued []Section
+	for _, section := range sections {
+		if critical[section.Monitor] || s.critical[section.Monitor] {
+			immediate = append(immediate, section)
+		} else {
+			queued = append(queued, section)
+		}
+	}
+
+	if len(queued) > 0 {
+		if err := s.appendDigest(Join(queued)); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Plan{Content: Join(immediate), Queued: queued}, nil
+}
+
+// Delivered records that a plan was sent, clearing the digest it included
+func (s *Scheduler) Delivered(plan *Plan) error {
+	if !plan.IncludeDigest {
+		return nil
+	}
+
+	if err := os.Remove(s.config.QueuePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clear notification digest %s: %w", s.config.QueuePath, err)
+	}
+
+	return nil
+}
+
+// loadDigest reads the queued digest, returning an empty string when nothing is queued
+func (s *Scheduler) loadDigest() (string, error) {
+	data, err := os.ReadFile(s.config.QueuePath)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err == nil {
+		data, err = encryption.Unseal(data)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read notification digest %s: %w", s.config.QueuePath, err)
+	}
+	return string(data), nil
+}
+
+// appendDigest adds content to the queued digest
+// The digest is rewritten rather than appended to, so it can be encrypted as a whole
+func (s *Scheduler) appendDigest(content string) error {
+	queued, err := s.loadDigest()
+	if err != nil {
+		return err
+	}
+	data, err := encryption.Seal([]byte(queued + content))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt notification digest: %w", err)
+	}
+	if err := os.WriteFile(s.config.QueuePath, data, 0600); err != nil {
+		return fmt.Errorf("failed to queue findings in %s: %w", s.config.QueuePath, err)
+	}
+	return nil
+}
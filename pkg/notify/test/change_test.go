@@ -0,0 +1,59 @@
+package test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/notify"
+	"github.com/anupsv/git-monitoring/pkg/state"
+)
+
+func TestChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	first := findings.Finding{Monitor: "pr_checker", Repository: "owner/repo", Subject: "PR #1"}
+	second := findings.Finding{Monitor: "pr_checker", Repository: "owner/repo", Subject: "PR #2"}
+
+	changed := func(target string, list []findings.Finding) bool {
+		t.Helper()
+		c, err := notify.Changed(path, target, list)
+		if err != nil {
+			t.Fatalf("Did not expect an error but got: %v", err)
+		}
+		return c
+	}
+
+	// Targets never notified have findings to notify, even none
+	if !changed(notify.TargetSlack, nil) {
+		t.Error("Expected a target never notified to count as changed")
+	}
+
+	if err := notify.Notified(path, notify.TargetSlack, []findings.Finding{first, second}); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if changed(notify.TargetSlack, []findings.Finding{second, first}) {
+		t.Error("Expected the same findings in another order to be unchanged")
+	}
+	if !changed(notify.TargetSlack, []findings.Finding{first}) {
+		t.Error("Expected a resolved finding to count as changed")
+	}
+	if !changed(notify.TargetTeamPrefix+"platform", []findings.Finding{first, second}) {
+		t.Error("Expected other targets to keep their own notified findings")
+	}
+
+	// A run saving its findings keeps what was notified while it was in progress
+	tracker, err := state.NewTracker(path)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	tracker.Record("pr_checker", []findings.Finding{first})
+	if err := notify.Notified(path, notify.TargetSlack, []findings.Finding{first}); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if err := tracker.Save(); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if changed(notify.TargetSlack, []findings.Finding{first}) {
+		t.Error("Expected the notified findings to be kept by the tracker")
+	}
+}
@@ -0,0 +1,36 @@
+package test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/anupsv/git-monitoring/pkg/notify"
+)
+
+func TestWriteFailuresMarkdown(t *testing.T) {
+	var buf strings.Builder
+	notify.WriteFailuresMarkdown(&buf, []notify.Failure{
+		{Monitor: "PR Checker", Err: errors.New("401 Bad credentials")},
+		{Monitor: "Rulesets Drift", Account: "acme", Err: errors.Join(errors.New("first"), errors.New("second"))},
+	})
+
+	output := buf.String()
+	for _, want := range []string{
+		"## :rotating_light: Monitor Failures",
+		"2 monitor runs failed",
+		"- PR Checker failed: 401 Bad credentials\n",
+		"- Rulesets Drift (acme) failed: first; second\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	// Nothing is written without failures
+	buf.Reset()
+	notify.WriteFailuresMarkdown(&buf, nil)
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output without failures, got %q", buf.String())
+	}
+}
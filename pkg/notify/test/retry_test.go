@@ -0,0 +1,96 @@
+package test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/notify"
+	"github.com/anupsv/git-monitoring/pkg/state"
+)
+
+func TestRetry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	queuedAt := time.Date(2024, 3, 13, 9, 0, 0, 0, time.UTC)
+
+	for _, n := range []struct{ target, content string }{
+		{notify.TargetSlack, "first"},
+		{notify.TargetPage, "second"},
+		{notify.TargetSlack, "third"},
+	} {
+		if err := notify.Enqueue(path, n.target, n.content, queuedAt); err != nil {
+			t.Fatalf("Did not expect an error but got: %v", err)
+		}
+	}
+
+	// The page webhook is still down
+	var sent []string
+	send := func(target, content string) bool {
+		if target == notify.TargetPage {
+			return false
+		}
+		sent = append(sent, content)
+		return true
+	}
+
+	now := queuedAt.Add(time.Hour)
+	delivered, pending, err := notify.Retry(path, send, now, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if delivered != 2 || pending != 1 {
+		t.Errorf("Expected 2 delivered and 1 pending, got %d and %d", delivered, pending)
+	}
+	// Notifications are sent oldest first
+	if len(sent) != 2 || sent[0] != "first" || sent[1] != "third" {
+		t.Errorf("Expected first and third to be sent in order, got %v", sent)
+	}
+
+	s, err := state.Load(path)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if len(s.Undelivered) != 1 {
+		t.Fatalf("Expected 1 undelivered notification, got %+v", s.Undelivered)
+	}
+	if n := s.Undelivered[0]; n.Content != "second" || n.Attempts != 2 || !n.LastAttempt.Equal(now) {
+		t.Errorf("Unexpected undelivered notification: %+v", n)
+	}
+
+	// Notifications queued longer than the max age are dropped without being sent
+	sent = nil
+	delivered, pending, err = notify.Retry(path, send, queuedAt.Add(48*time.Hour), 24*time.Hour)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if delivered != 0 || pending != 0 || len(sent) != 0 {
+		t.Errorf("Expected the expired notification to be dropped, got %d delivered, %d pending, sent %v", delivered, pending, sent)
+	}
+}
+
+func TestRetryKeptByTracker(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	// A run saving its findings keeps notifications queued while it was in progress
+	tracker, err := state.NewTracker(path)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	tracker.Record("pr_checker", []findings.Finding{{Monitor: "pr_checker", Repository: "owner/repo", Subject: "PR #1"}})
+
+	if err := notify.Enqueue(path, notify.TargetSlack, "queued", time.Now()); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if err := tracker.Save(); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	s, err := state.Load(path)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if len(s.Undelivered) != 1 || s.Undelivered[0].Content != "queued" {
+		t.Errorf("Expected the queued notification to be kept, got %+v", s.Undelivered)
+	}
+}
@@ -0,0 +1,128 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/notify"
+)
+
+func newConfig(t *testing.T) *config.Config {
+	return &config.Config{
+		Notifications: config.NotificationsConfig{
+			Schedule: config.ScheduleConfig{
+				Enabled:          true,
+				Days:             []string{"mon", "tue", "wed", "thu", "fri"},
+				Start:            "09:00",
+				End:              "17:00",
+				QueuePath:        filepath.Join(t.TempDir(), "digest.md"),
+				CriticalMonitors: []string{"repo_visibility"},
+			},
+		},
+	}
+}
+
+func TestInBusinessHours(t *testing.T) {
+	scheduler := notify.NewScheduler(newConfig(t))
+
+	tests := []struct {
+		name     string
+		time     time.Time
+		expected bool
+	}{
+		{"Weekday morning", time.Date(2024, 3, 13, 9, 0, 0, 0, time.UTC), true},
+		{"Weekday evening", time.Date(2024, 3, 13, 17, 0, 0, 0, time.UTC), false},
+		{"Weekday night", time.Date(2024, 3, 13, 3, 0, 0, 0, time.UTC), false},
+		{"Saturday", time.Date(2024, 3, 16, 12, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := scheduler.InBusinessHours(tc.time); got != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestPlanQueuesOffHoursFindings(t *testing.T) {
+	cfg := newConfig(t)
+	scheduler := notify.NewScheduler(cfg)
+
+	night := time.Date(2024, 3, 13, 23, 0, 0, 0, time.UTC)
+	sections := []notify.Section{
+		{Monitor: "pr_checker", Content: "## PRs\n"},
+		{Monitor: "repo_visibility", Content: "## Public repos\n"},
+	}
+
+	plan, err := scheduler.Plan(night, sections, nil, "nothing")
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	// Critical findings are sent immediately, the rest is queued
+	if plan.Content != "## Public repos\n" {
+		t.Errorf("Expected only the critical section to be sent, got %q", plan.Content)
+	}
+	if len(plan.Queued) != 1 || plan.Queued[0].Monitor != "pr_checker" {
+		t.Errorf("Expected the PR checker section to be queued, got %+v", plan.Queued)
+	}
+
+	// The next business-hours run includes the digest
+	morning := time.Date(2024, 3, 14, 9, 30, 0, 0, time.UTC)
+	plan, err = scheduler.Plan(morning, nil, nil, "nothing")
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if !plan.IncludeDigest || !strings.Contains(plan.Content, "## PRs") {
+		t.Errorf("Expected the digest to be included, got %q", plan.Content)
+	}
+
+	if err := scheduler.Delivered(plan); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if _, err := os.Stat(cfg.Notifications.Schedule.QueuePath); !os.IsNotExist(err) {
+		t.Errorf("Expected the digest to be cleared after delivery")
+	}
+
+	// With nothing queued and nothing found, the empty message is sent
+	plan, err = scheduler.Plan(morning, nil, nil, "nothing")
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if plan.Content != "nothing" {
+		t.Errorf("Expected the empty message, got %q", plan.Content)
+	}
+}
+
+func TestPlanSendsCriticalFindings(t *testing.T) {
+	scheduler := notify.NewScheduler(newConfig(t))
+
+	night := time.Date(2024, 3, 13, 23, 0, 0, 0, time.UTC)
+	sections := []notify.Section{
+		{Monitor: "pr_checker", Content: "## PRs\n"},
+		{Monitor: "dormant_repositories", Content: "## Dormant repos\n"},
+	}
+	list := []findings.Finding{
+		{Monitor: "pr_checker", Repository: "owner/api", Subject: "PR #1", Severity: findings.SeverityCritical},
+		{Monitor: "dormant_repositories", Repository: "owner/old", Subject: "owner/old", Severity: findings.SeverityHigh},
+	}
+
+	plan, err := scheduler.Plan(night, sections, list, "nothing")
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	// The PR checker is not among the critical monitors, but one of its findings is critical
+	if plan.Content != "## PRs\n" {
+		t.Errorf("Expected only the section with a critical finding to be sent, got %q", plan.Content)
+	}
+	if len(plan.Queued) != 1 || plan.Queued[0].Monitor != "dormant_repositories" {
+		t.Errorf("Expected the dormant repositories section to be queued, got %+v", plan.Queued)
+	}
+}
@@ -0,0 +1,234 @@
+package ownership
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/tools/codeowners"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+// Sources of the owners of repositories
+const (
+	SourceConfig     = "config"     // Teams configured by repository pattern
+	SourceCodeowners = "codeowners" // Team owning "*" in the repository's CODEOWNERS file
+	SourceTeams      = "teams"      // Team with admin access to the repository
+)
+
+// Unowned is the team of findings no source assigns an owner to, unless a default team is configured
+const Unowned = "unowned"
+
+// defaultPatterns are the CODEOWNERS patterns of the default owners of a repository
+var defaultPatterns = map[string]bool{"*": true, "/*": true, "**": true, "/**": true}
+
+// Team is an owning team and the findings of its repositories
+type Team struct {
+	Name     string
+	Findings []findings.Finding
+}
+
+// Resolver looks up the teams owning repositories, each repository once
+type Resolver struct {
+	cfg     config.OwnershipConfig
+	clients func(account string) common.GitHubClientInterface // Client of the account of a finding
+
+	mu     sync.Mutex
+	owners map[string]string // Owning team by account and repository
+}
+
+// NewResolver creates a resolver looking owners up in the configured sources
+// clients returns the GitHub client of an account, the empty account for runs without accounts
+func NewResolver(cfg config.OwnershipConfig, clients func(account string) common.GitHubClientInterface) *Resolver {
+	return &Resolver{cfg: cfg, clients: clients, owners: make(map[string]string)}
+}
+
+// Owner returns the team owning a repository of an account
+// Lookups that fail are logged and the next source is tried, so an unreachable API does not lose findings
+func (r *Resolver) Owner(ctx context.Context, account, repository string) string {
+	key := account + "\x00" + repository
+	r.mu.Lock()
+	team, ok := r.owners[key]
+	r.mu.Unlock()
+	if ok {
+		return team
+	}
+
+	team = r.lookup(ctx, account, repository)
+	if team == "" {
+		team = r.cfg.DefaultTeam
+	}
+	if team == "" {
+		team = Unowned
+	}
+
+	r.mu.Lock()
+	r.owners[key] = team
+	r.mu.Unlock()
+	return team
+}
+
+// lookup returns the team the first source that knows one assigns, empty when none does
+func (r *Resolver) lookup(ctx context.Context, account, repository string) string {
+	owner, repo, ok := strings.Cut(repository, "/")
+	for _, source := range r.cfg.Sources {
+		var team string
+		var err error
+		switch {
+		case source == SourceConfig:
+			team = ConfiguredTeam(r.cfg.Teams, repository)
+		case !ok:
+			// Organization-wide findings have no repository to look up
+			continue
+		case source == SourceCodeowners:
+			team, err = codeownersTeam(ctx, r.clients(account), owner, repo)
+		case source == SourceTeams:
+			team, err = adminTeam(ctx, r.clients(account), owner, repo)
+		}
+		if err != nil {
+			log.Printf("Error looking up the %s owner of %s: %v", source, repository, err)
+			continue
+		}
+		if team != "" {
+			return team
+		}
+	}
+	return ""
+}
+
+// ConfiguredTeam returns the team of the longest pattern matching a repository, compared case-insensitively,
+// and empty when none matches
+func ConfiguredTeam(teams map[string]string, repository string) string {
+	best := ""
+	for pattern := range teams {
+		matched, err := path.Match(strings.ToLower(pattern), strings.ToLower(repository))
+		if err != nil || !matched {
+			continue
+		}
+		// Equally long patterns are ordered, so the same pattern wins in every run
+		if best == "" || len(pattern) > len(best) || (len(pattern) == len(best) && pattern < best) {
+			best = pattern
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return teams[best]
+}
+
+// codeownersTeam returns the first team among the default owners in the repository's CODEOWNERS file
+func codeownersTeam(ctx context.Context, client common.GitHubClientInterface, owner, repo string) (string, error) {
+	for _, location := range codeowners.Locations {
+		content, err := client.GetFileContent(ctx, owner, repo, location)
+		if err != nil {
+			return "", err
+		}
+		if content == nil {
+			continue
+		}
+
+		// The last default rule wins, as on GitHub
+		rules := codeowners.Parse(string(content))
+		for i := len(rules) - 1; i >= 0; i-- {
+			if !defaultPatterns[rules[i].Pattern] {
+				continue
+			}
+			for _, codeOwner := range rules[i].Owners {
+				if _, team, isTeam := strings.Cut(strings.TrimPrefix(codeOwner, "@"), "/"); isTeam {
+					return team, nil
+				}
+			}
+			return "", nil
+		}
+		return "", nil
+	}
+	return "", nil
+}
+
+// adminTeam returns the first team, by slug, with admin access to the repository
+func adminTeam(ctx context.Context, client common.GitHubClientInterface, owner, repo string) (string, error) {
+	teams, err := client.ListRepositoryTeams(ctx, owner, repo)
+	if err != nil {
+		return "", err
+	}
+
+	var admins []string
+	for _, team := range teams {
+		if team.GetPermission() == "admin" {
+			admins = append(admins, team.GetSlug())
+		}
+	}
+	if len(admins) == 0 {
+		return "", nil
+	}
+	sort.Strings(admins)
+	return admins[0], nil
+}
+
+// Group groups findings by the team owning their repository, ordered by team name with unowned findings last
+func (r *Resolver) Group(ctx context.Context, list []findings.Finding) []Team {
+	byTeam := make(map[string][]findings.Finding)
+	for _, f := range list {
+		team := r.Owner(ctx, f.Account, f.Repository)
+		byTeam[team] = append(byTeam[team], f)
+	}
+
+	teams := make([]Team, 0, len(byTeam))
+	for name, teamFindings := range byTeam {
+		teams = append(teams, Team{Name: name, Findings: teamFindings})
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		if (teams[i].Name == Unowned) != (teams[j].Name == Unowned) {
+			return teams[j].Name == Unowned
+		}
+		return teams[i].Name < teams[j].Name
+	})
+	return teams
+}
+
+// WriteMarkdown writes the findings of each team in a code block format suitable for Slack
+// repoName renders repository names, e.g. redacted, and nil keeps them
+func WriteMarkdown(w io.Writer, teams []Team, repoName func(string) string) {
+	if len(teams) == 0 {
+		return // No results to display
+	}
+
+	fmt.Fprintln(w, "## :busts_in_silhouette: Findings by Team")
+	fmt.Fprintf(w, "Findings of %d teams, routed to the teams owning their repositories.\n\n", len(teams))
+	for _, team := range teams {
+		fmt.Fprintf(w, "### %s (%d)\n", team.Name, len(team.Findings))
+		writeTeam(w, team, repoName)
+	}
+}
+
+// WriteTeamMarkdown writes the findings of a team, for the notification sent to the team
+func WriteTeamMarkdown(w io.Writer, team Team, repoName func(string) string) {
+	fmt.Fprintf(w, "## :busts_in_silhouette: Findings of %s\n", team.Name)
+	fmt.Fprintf(w, "%d findings in repositories owned by %s.\n\n", len(team.Findings), team.Name)
+	writeTeam(w, team, repoName)
+}
+
+// writeTeam writes a table of the findings of a team
+func writeTeam(w io.Writer, team Team, repoName func(string) string) {
+	// Start code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "Monitor                   Repository                Subject")
+	fmt.Fprintln(w, "-----------------------------------------------------------------------")
+	for _, f := range team.Findings {
+		repository := f.Repository
+		if repoName != nil {
+			repository = repoName(repository)
+		}
+		fmt.Fprintf(w, "%-25s %-25s %s\n", f.Monitor, repository, f.Subject)
+	}
+	// End code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
@@ -0,0 +1,135 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/ownership"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	mockgithub "github.com/anupsv/git-monitoring/pkg/tools/common/test"
+	"github.com/google/go-github/v45/github"
+)
+
+func TestConfiguredTeam(t *testing.T) {
+	teams := map[string]string{
+		"acme/*":         "platform",
+		"acme/payments*": "payments",
+		"other/*":        "other",
+	}
+
+	tests := map[string]string{
+		"acme/website":       "platform",
+		"acme/payments-api":  "payments",
+		"ACME/Payments-Core": "payments",
+		"unknown/repo":       "",
+	}
+	for repository, expected := range tests {
+		if team := ownership.ConfiguredTeam(teams, repository); team != expected {
+			t.Errorf("Expected %s to be owned by %q, got %q", repository, expected, team)
+		}
+	}
+}
+
+func TestResolver(t *testing.T) {
+	mockClient := &mockgithub.MockGitHubClient{
+		MockFileContents: map[string]string{
+			"acme/api/.github/CODEOWNERS": "* @acme/backend\n/docs/ @acme/writers\n",
+			"acme/web/CODEOWNERS":         "* @alice\n",
+		},
+		MockRepoTeams: map[string][]*github.Team{
+			"acme/web": {
+				{Slug: github.String("web-readers"), Permission: github.String("pull")},
+				{Slug: github.String("web-admins"), Permission: github.String("admin")},
+			},
+		},
+	}
+	cfg := config.OwnershipConfig{
+		Enabled: true,
+		Sources: []string{ownership.SourceConfig, ownership.SourceCodeowners, ownership.SourceTeams},
+		Teams:   map[string]string{"acme/infra-*": "infrastructure"},
+	}
+	resolver := ownership.NewResolver(cfg, func(string) common.GitHubClientInterface { return mockClient })
+
+	ctx := context.Background()
+	tests := map[string]string{
+		"acme/infra-terraform": "infrastructure", // Configured
+		"acme/api":             "backend",        // CODEOWNERS
+		"acme/web":             "web-admins",     // Individual code owner, so the admin team
+		"acme/legacy":          ownership.Unowned,
+	}
+	for repository, expected := range tests {
+		if team := resolver.Owner(ctx, "", repository); team != expected {
+			t.Errorf("Expected %s to be owned by %q, got %q", repository, expected, team)
+		}
+	}
+
+	// Owners are looked up once per repository
+	calls := mockClient.ListRepositoryTeamsCalls
+	resolver.Owner(ctx, "", "acme/web")
+	if mockClient.ListRepositoryTeamsCalls != calls {
+		t.Errorf("Expected the owner of acme/web to be cached")
+	}
+
+	cfg.DefaultTeam = "security"
+	resolver = ownership.NewResolver(cfg, func(string) common.GitHubClientInterface { return mockClient })
+	if team := resolver.Owner(ctx, "", "acme/legacy"); team != "security" {
+		t.Errorf("Expected unowned repositories to go to the default team, got %q", team)
+	}
+}
+
+func TestResolverLookupErrors(t *testing.T) {
+	mockClient := &mockgithub.MockGitHubClient{
+		MockFileContentErr: errors.New("API error"),
+		MockRepoTeams: map[string][]*github.Team{
+			"acme/api": {{Slug: github.String("backend"), Permission: github.String("admin")}},
+		},
+	}
+	cfg := config.OwnershipConfig{Sources: []string{ownership.SourceCodeowners, ownership.SourceTeams}}
+	resolver := ownership.NewResolver(cfg, func(string) common.GitHubClientInterface { return mockClient })
+
+	if team := resolver.Owner(context.Background(), "", "acme/api"); team != "backend" {
+		t.Errorf("Expected a failing source to fall through to the next, got %q", team)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	cfg := config.OwnershipConfig{
+		Sources: []string{ownership.SourceConfig},
+		Teams:   map[string]string{"acme/api": "backend", "acme/web": "frontend"},
+	}
+	resolver := ownership.NewResolver(cfg, func(string) common.GitHubClientInterface { return &mockgithub.MockGitHubClient{} })
+
+	list := []findings.Finding{
+		{Monitor: "pr_checker", Repository: "acme/web", Subject: "PR #1"},
+		{Monitor: "dormant_repositories", Repository: "acme/old", Subject: "acme/old"},
+		{Monitor: "pr_checker", Repository: "acme/api", Subject: "PR #2"},
+		{Monitor: "pr_checker", Repository: "acme/web", Subject: "PR #3"},
+	}
+	teams := resolver.Group(context.Background(), list)
+
+	var names []string
+	for _, team := range teams {
+		names = append(names, team.Name)
+	}
+	if strings.Join(names, ",") != "backend,frontend,unowned" {
+		t.Fatalf("Expected teams ordered by name with unowned last, got %v", names)
+	}
+	if len(teams[1].Findings) != 2 {
+		t.Errorf("Expected 2 findings for frontend, got %+v", teams[1].Findings)
+	}
+
+	var buf bytes.Buffer
+	ownership.WriteTeamMarkdown(&buf, teams[1], func(string) string { return "[redacted]" })
+	output := buf.String()
+	if !strings.Contains(output, "Findings of frontend") || !strings.Contains(output, "PR #3") {
+		t.Errorf("Expected the team's findings in its report, got %s", output)
+	}
+	if strings.Contains(output, "acme/web") {
+		t.Errorf("Expected repository names to be rendered by repoName, got %s", output)
+	}
+}
@@ -0,0 +1,176 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+// Policy holds the thresholds a repository overrides in its policy file
+// Zero values keep the central configuration
+type Policy struct {
+	DormantRepositories DormantReposPolicy `toml:"dormant_repositories"`
+	PRChecker           PRCheckerPolicy    `toml:"pr_checker"`
+	SLA                 SLAPolicy          `toml:"sla"`
+}
+
+// PRCheckerPolicy overrides the PR checker for a repository
+// Overrides can only tighten the central settings: fewer approvals or fewer base branches are ignored
+type PRCheckerPolicy struct {
+	MinReviewTime     config.Duration `toml:"min_review_time"`    // Approvals submitted sooner are rubber stamps
+	RequiredApprovals int             `toml:"required_approvals"` // Distinct reviewers who must approve merged PRs
+	BaseBranches      []string        `toml:"base_branches"`      // Globs of base branches checked besides the central ones
+}
+
+// SLAPolicy overrides the remediation deadlines of a repository's findings
+type SLAPolicy struct {
+	Days int `toml:"days"` // Days to remediate findings in, the central deadline when shorter
+}
+
+// DormantReposPolicy overrides the dormant repository monitor for a repository
+type DormantReposPolicy struct {
+	InactiveDays int `toml:"inactive_days"` // Days without activity before the repository is reported
+}
+
+// Parse parses and validates a repository policy file
+// Settings that repositories cannot override are rejected, so typos do not silently keep the central value
+func Parse(data []byte) (*Policy, error) {
+	var p Policy
+	meta, err := toml.Decode(string(data), &p)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding policy file: %v", err)
+	}
+
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, 0, len(undecoded))
+		for _, key := range undecoded {
+			keys = append(keys, key.String())
+		}
+		return nil, fmt.Errorf("unsupported settings in policy file: %s", strings.Join(keys, ", "))
+	}
+
+	if p.DormantRepositories.InactiveDays < 0 {
+		return nil, fmt.Errorf("inactive days for dormant repositories must not be negative")
+	}
+
+	if p.PRChecker.MinReviewTime.Duration < 0 {
+		return nil, fmt.Errorf("min review time for the PR checker must not be negative")
+	}
+
+	if p.PRChecker.RequiredApprovals < 0 {
+		return nil, fmt.Errorf("required approvals for the PR checker must not be negative")
+	}
+
+	for _, branch := range p.PRChecker.BaseBranches {
+		if strings.TrimSpace(branch) == "" {
+			return nil, fmt.Errorf("empty branch in PR checker base_branches")
+		}
+	}
+
+	if p.SLA.Days < 0 {
+		return nil, fmt.Errorf("SLA days must not be negative")
+	}
+
+	return &p, nil
+}
+
+// Limit applies a repository override to a central value, within the central cap
+// The central value is kept when the repository does not override it. Overrides above max are lowered to max
+func Limit(central, override, max int) int {
+	if override <= 0 {
+		return central
+	}
+	if override > max {
+		return max
+	}
+	return override
+}
+
+// cacheKey is the context key of the policies loaded during a run
+type cacheKey struct{}
+
+// cache holds the policies loaded during a run, by path and lowercased repository
+type cache struct {
+	mu       sync.Mutex
+	policies map[string]*Policy
+}
+
+// WithCache returns a context in which loaders share the policies they load, so a repository's file is
+// fetched once per run rather than once per monitor and report
+func WithCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheKey{}, &cache{policies: make(map[string]*Policy)})
+}
+
+// cacheOf returns the policies shared by the loaders of a run, nil outside WithCache
+func cacheOf(ctx context.Context) *cache {
+	c, _ := ctx.Value(cacheKey{}).(*cache)
+	return c
+}
+
+// Loader fetches the policy files of repositories
+// Each repository's file is fetched at most once per Loader, or per run within WithCache
+type Loader struct {
+	client   common.GitHubClientInterface
+	path     string
+	policies map[string]*Policy
+}
+
+// NewLoader creates a loader reading the policy file at path in each repository
+func NewLoader(client common.GitHubClientInterface, path string) *Loader {
+	return &Loader{
+		client:   client,
+		path:     path,
+		policies: make(map[string]*Policy),
+	}
+}
+
+// Load returns the policy of a repository ("owner/repo")
+// Repositories without a policy file, or with one that cannot be read or is invalid, get an empty policy,
+// so they are checked against the central configuration. Errors are logged
+func (l *Loader) Load(ctx context.Context, repository string) *Policy {
+	if p, ok := l.policies[repository]; ok {
+		return p
+	}
+
+	shared := cacheOf(ctx)
+	key := l.path + "\x00" + strings.ToLower(repository)
+	if shared != nil {
+		shared.mu.Lock()
+		p, ok := shared.policies[key]
+		shared.mu.Unlock()
+		if ok {
+			l.policies[repository] = p
+			return p
+		}
+	}
+
+	p := &Policy{}
+	if owner, repo, ok := common.ParseRepository(repository); ok {
+		data, err := l.client.GetFileContent(ctx, owner, repo, l.path)
+		if err != nil {
+			log.Printf("Error loading policy of %s, using the central configuration: %v", repository, err)
+		} else if data != nil {
+			parsed, err := Parse(data)
+			if err != nil {
+				log.Printf("Invalid %s in %s, using the central configuration: %v", l.path, repository, err)
+			} else {
+				p = parsed
+			}
+		}
+	}
+
+	l.policies[repository] = p
+	if shared != nil {
+		shared.mu.Lock()
+		shared.policies[key] = p
+		shared.mu.Unlock()
+	}
+	return p
+}
@@ -0,0 +1,144 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/policy"
+	mockgithub "github.com/anupsv/git-monitoring/pkg/tools/common/test"
+)
+
+func TestParse(t *testing.T) {
+	p, err := policy.Parse([]byte("[dormant_repositories]\ninactive_days = 30\n"))
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if p.DormantRepositories.InactiveDays != 30 {
+		t.Errorf("Expected 30 inactive days, got %d", p.DormantRepositories.InactiveDays)
+	}
+
+	p, err = policy.Parse([]byte("[pr_checker]\nmin_review_time = \"10m\"\n"))
+	if err != nil || p.PRChecker.MinReviewTime.Duration != 10*time.Minute {
+		t.Errorf("Expected a min review time of 10m, got %+v, %v", p, err)
+	}
+
+	p, err = policy.Parse([]byte("[pr_checker]\nrequired_approvals = 2\nbase_branches = [\"release/*\"]\n\n[sla]\ndays = 3\n"))
+	if err != nil || p.PRChecker.RequiredApprovals != 2 || len(p.PRChecker.BaseBranches) != 1 || p.SLA.Days != 3 {
+		t.Errorf("Expected 2 approvals, the release branches and 3 SLA days, got %+v, %v", p, err)
+	}
+
+	tests := []struct {
+		name          string
+		content       string
+		errorContains string
+	}{
+		{"Unsupported setting", "[github]\ntoken = \"x\"\n", "unsupported settings in policy file: github"},
+		{"Misspelled setting", "[dormant_repositories]\ninactive_day = 30\n", "dormant_repositories.inactive_day"},
+		{"Negative value", "[dormant_repositories]\ninactive_days = -1\n", "must not be negative"},
+		{"Negative review time", "[pr_checker]\nmin_review_time = \"-5m\"\n", "must not be negative"},
+		{"Negative approvals", "[pr_checker]\nrequired_approvals = -1\n", "must not be negative"},
+		{"Empty base branch", "[pr_checker]\nbase_branches = [\" \"]\n", "empty branch in PR checker base_branches"},
+		{"Negative SLA days", "[sla]\ndays = -2\n", "SLA days must not be negative"},
+		{"Invalid TOML", "[dormant_repositories", "error decoding policy file"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := policy.Parse([]byte(tc.content))
+			if err == nil || !strings.Contains(err.Error(), tc.errorContains) {
+				t.Errorf("Expected error containing %q, got %v", tc.errorContains, err)
+			}
+		})
+	}
+}
+
+func TestLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		central  int
+		override int
+		max      int
+		expected int
+	}{
+		{"No override", 180, 0, 365, 180},
+		{"Stricter override", 180, 30, 365, 30},
+		{"Looser override within cap", 180, 270, 365, 270},
+		{"Looser override above cap", 180, 500, 365, 365},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := policy.Limit(tc.central, tc.override, tc.max); got != tc.expected {
+				t.Errorf("Expected %d, got %d", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestLoader(t *testing.T) {
+	mockClient := &mockgithub.MockGitHubClient{
+		MockFileContents: map[string]string{
+			"owner/tuned/.github/git-monitor.toml":   "[dormant_repositories]\ninactive_days = 30\n",
+			"owner/invalid/.github/git-monitor.toml": "[dormant_repositories]\ninactive_days = \"soon\"\n",
+		},
+	}
+	loader := policy.NewLoader(mockClient, ".github/git-monitor.toml")
+	ctx := context.Background()
+
+	if days := loader.Load(ctx, "owner/tuned").DormantRepositories.InactiveDays; days != 30 {
+		t.Errorf("Expected the repository's override, got %d", days)
+	}
+	if days := loader.Load(ctx, "owner/invalid").DormantRepositories.InactiveDays; days != 0 {
+		t.Errorf("Expected an invalid policy to be ignored, got %d", days)
+	}
+	if days := loader.Load(ctx, "owner/default").DormantRepositories.InactiveDays; days != 0 {
+		t.Errorf("Expected no override without a policy file, got %d", days)
+	}
+
+	// Each repository's file is only fetched once
+	loader.Load(ctx, "owner/tuned")
+	if mockClient.GetFileContentCalls != 3 {
+		t.Errorf("Expected 3 file lookups, got %d", mockClient.GetFileContentCalls)
+	}
+
+	// Errors fetching the file fall back to the central configuration
+	failing := policy.NewLoader(&mockgithub.MockGitHubClient{MockFileContentErr: errors.New("API error")}, ".github/git-monitor.toml")
+	if days := failing.Load(ctx, "owner/tuned").DormantRepositories.InactiveDays; days != 0 {
+		t.Errorf("Expected no override when the file cannot be fetched, got %d", days)
+	}
+}
+
+func TestLoaderCache(t *testing.T) {
+	mockClient := &mockgithub.MockGitHubClient{
+		MockFileContents: map[string]string{
+			"owner/tuned/.github/git-monitor.toml": "[sla]\ndays = 5\n",
+		},
+	}
+	ctx := policy.WithCache(context.Background())
+
+	// Loaders of the same run share the files already fetched
+	if days := policy.NewLoader(mockClient, ".github/git-monitor.toml").Load(ctx, "owner/tuned").SLA.Days; days != 5 {
+		t.Errorf("Expected the repository's override, got %d", days)
+	}
+	if days := policy.NewLoader(mockClient, ".github/git-monitor.toml").Load(ctx, "Owner/Tuned").SLA.Days; days != 5 {
+		t.Errorf("Expected the cached override, got %d", days)
+	}
+	if mockClient.GetFileContentCalls != 1 {
+		t.Errorf("Expected 1 file lookup, got %d", mockClient.GetFileContentCalls)
+	}
+
+	// Policy files at another path are fetched separately
+	policy.NewLoader(mockClient, ".github/other.toml").Load(ctx, "owner/tuned")
+	if mockClient.GetFileContentCalls != 2 {
+		t.Errorf("Expected 2 file lookups, got %d", mockClient.GetFileContentCalls)
+	}
+
+	// Without the cache, each loader fetches the file again
+	policy.NewLoader(mockClient, ".github/git-monitor.toml").Load(context.Background(), "owner/tuned")
+	if mockClient.GetFileContentCalls != 3 {
+		t.Errorf("Expected 3 file lookups, got %d", mockClient.GetFileContentCalls)
+	}
+}
@@ -0,0 +1,400 @@
+package projects
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/state"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+// DefaultGraphQLURL is the GraphQL API of github.com
+const DefaultGraphQLURL = "https://api.github.com/graphql"
+
+// requestTimeout bounds each GraphQL request
+const requestTimeout = 30 * time.Second
+
+// markerPattern finds the fingerprint and monitor key recorded in the body of the items created for findings
+var markerPattern = regexp.MustCompile(`<!-- git-monitor fingerprint=(\S+) key=(\S+) -->`)
+
+// Run is what a run found, to sync the board with
+type Run struct {
+	Open     []findings.Finding // Findings tracked on the board, i.e. those not suppressed
+	Found    []findings.Finding // All findings, including suppressed ones, whose items are left as they are
+	Complete map[string]bool    // Monitor runs that covered their whole scope, by state key, whose missing findings resolved
+}
+
+// Result counts the items the sync changed
+type Result struct {
+	Created  int // Items added for new findings
+	Updated  int // Items whose title or body changed with their finding
+	Reopened int // Done items whose finding was found again
+	Resolved int // Items moved to done as their finding resolved
+}
+
+// Exporter tracks findings as draft issues of a GitHub Projects board, moving them to done as they resolve
+type Exporter struct {
+	config config.ProjectsConfig
+	token  string
+	url    string
+	client *http.Client
+}
+
+// New creates an Exporter for the configured board, authenticating with token
+func New(cfg config.ProjectsConfig, token string) *Exporter {
+	url := cfg.GraphQLURL
+	if url == "" {
+		url = DefaultGraphQLURL
+	}
+	return &Exporter{config: cfg, token: token, url: url, client: &http.Client{Timeout: requestTimeout}}
+}
+
+// project is the board and its status field
+type project struct {
+	id      string
+	fieldID string
+	open    string // ID of the status option of open findings
+	done    string // ID of the status option of resolved findings
+}
+
+// item is an item of the board created for a finding
+type item struct {
+	id          string
+	draftID     string
+	title       string
+	body        string
+	status      string // ID of the item's status option, empty when it has none
+	fingerprint string
+	key         string // State key of the monitor run that found the finding
+}
+
+// Sync adds an item for each open finding without one, updates the items of findings that changed,
+// and moves the items of resolved findings to done. Items whose finding is found again are moved back,
+// unless they were moved to another status in the meantime
+func (e *Exporter) Sync(ctx context.Context, run Run) (Result, error) {
+	var result Result
+
+	p, err := e.project(ctx)
+	if err != nil {
+		return result, err
+	}
+	items, err := e.items(ctx, p.id)
+	if err != nil {
+		return result, err
+	}
+
+	found := make(map[string]bool, len(run.Found))
+	for _, f := range run.Found {
+		found[f.Fingerprint()] = true
+	}
+
+	open := make(map[string]bool, len(run.Open))
+	for _, f := range run.Open {
+		fingerprint := f.Fingerprint()
+		if open[fingerprint] {
+			continue
+		}
+		open[fingerprint] = true
+		title, body := Title(f), Body(f)
+
+		existing, ok := items[fingerprint]
+		if !ok {
+			id, err := e.addItem(ctx, p.id, title, body)
+			if err != nil {
+				return result, err
+			}
+			if err := e.setStatus(ctx, p, id, p.open); err != nil {
+				return result, err
+			}
+			result.Created++
+			continue
+		}
+
+		if existing.title != title || existing.body != body {
+			if err := e.updateItem(ctx, existing.draftID, title, body); err != nil {
+				return result, err
+			}
+			result.Updated++
+		}
+		if existing.status == p.done {
+			if err := e.setStatus(ctx, p, existing.id, p.open); err != nil {
+				return result, err
+			}
+			result.Reopened++
+		}
+	}
+
+	// Findings missing from monitor runs that did not cover their whole scope may still be open
+	for fingerprint, existing := range items {
+		if open[fingerprint] || found[fingerprint] || !run.Complete[existing.key] || existing.status == p.done {
+			continue
+		}
+		if err := e.setStatus(ctx, p, existing.id, p.done); err != nil {
+			return result, err
+		}
+		result.Resolved++
+	}
+
+	return result, nil
+}
+
+// Title returns the title of the item of a finding
+func Title(f findings.Finding) string {
+	title := fmt.Sprintf("[%s] %s", f.Monitor, f.Repository)
+	if f.Subject != "" {
+		title += ": " + f.Subject
+	}
+	return title
+}
+
+// Body returns the body of the item of a finding, which records the finding's fingerprint to find the item again
+func Body(f findings.Finding) string {
+	var b strings.Builder
+	fmt.Fprintln(&b, f.Summary)
+	fmt.Fprintln(&b, "")
+	fmt.Fprintf(&b, "- Monitor: %s\n", f.Monitor)
+	if f.Account != "" {
+		fmt.Fprintf(&b, "- Account: %s\n", f.Account)
+	}
+	fmt.Fprintf(&b, "- Repository: %s\n", f.Repository)
+	if f.Severity != "" {
+		fmt.Fprintf(&b, "- Severity: %s\n", f.Severity)
+	}
+	if len(f.Controls) > 0 {
+		fmt.Fprintf(&b, "- Controls: %s\n", strings.Join(f.Controls, ", "))
+	}
+	if f.URL != "" {
+		fmt.Fprintf(&b, "- Link: %s\n", f.URL)
+	}
+	fmt.Fprintln(&b, "")
+	fmt.Fprintf(&b, "<!-- git-monitor fingerprint=%s key=%s -->", f.Fingerprint(), state.Key(f.Account, f.Monitor))
+	return b.String()
+}
+
+// project looks up the board and the options of its status field
+func (e *Exporter) project(ctx context.Context) (*project, error) {
+	const query = `query($owner: String!, $number: Int!, $field: String!) {
+  repositoryOwner(login: $owner) {
+    ... on ProjectV2Owner {
+      projectV2(number: $number) {
+        id
+        field(name: $field) {
+          ... on ProjectV2SingleSelectField { id options { id name } }
+        }
+      }
+    }
+  }
+}`
+	var data struct {
+		RepositoryOwner *struct {
+			ProjectV2 *struct {
+				ID    string `json:"id"`
+				Field *struct {
+					ID      string `json:"id"`
+					Options []struct {
+						ID   string `json:"id"`
+						Name string `json:"name"`
+					} `json:"options"`
+				} `json:"field"`
+			} `json:"projectV2"`
+		} `json:"repositoryOwner"`
+	}
+	vars := map[string]interface{}{"owner": e.config.Owner, "number": e.config.Number, "field": e.config.StatusField}
+	if err := e.do(ctx, query, vars, &data); err != nil {
+		return nil, fmt.Errorf("error looking up project %d of %s: %w", e.config.Number, e.config.Owner, err)
+	}
+	if data.RepositoryOwner == nil || data.RepositoryOwner.ProjectV2 == nil {
+		return nil, fmt.Errorf("project %d of %s not found", e.config.Number, e.config.Owner)
+	}
+	board := data.RepositoryOwner.ProjectV2
+	if board.Field == nil || board.Field.ID == "" {
+		return nil, fmt.Errorf("project %d of %s has no single select field %q", e.config.Number, e.config.Owner, e.config.StatusField)
+	}
+
+	p := &project{id: board.ID, fieldID: board.Field.ID}
+	for _, option := range board.Field.Options {
+		switch {
+		case strings.EqualFold(option.Name, e.config.OpenStatus):
+			p.open = option.ID
+		case strings.EqualFold(option.Name, e.config.DoneStatus):
+			p.done = option.ID
+		}
+	}
+	if p.open == "" || p.done == "" {
+		return nil, fmt.Errorf("field %q of project %d of %s must have the options %q and %q",
+			e.config.StatusField, e.config.Number, e.config.Owner, e.config.OpenStatus, e.config.DoneStatus)
+	}
+	return p, nil
+}
+
+// items lists the items of the board created for findings, by fingerprint
+// Archived items are left out, so archiving an item lets a finding found again get a new one
+func (e *Exporter) items(ctx context.Context, projectID string) (map[string]item, error) {
+	const query = `query($project: ID!, $field: String!, $cursor: String) {
+  node(id: $project) {
+    ... on ProjectV2 {
+      items(first: 100, after: $cursor) {
+        nodes {
+          id
+          isArchived
+          content { ... on DraftIssue { id title body } }
+          fieldValueByName(name: $field) {
+            ... on ProjectV2ItemFieldSingleSelectValue { optionId }
+          }
+        }
+        pageInfo { hasNextPage endCursor }
+      }
+    }
+  }
+}`
+	items := make(map[string]item)
+	var cursor *string
+	for {
+		var data struct {
+			Node struct {
+				Items struct {
+					Nodes []struct {
+						ID         string `json:"id"`
+						IsArchived bool   `json:"isArchived"`
+						Content    *struct {
+							ID    string `json:"id"`
+							Title string `json:"title"`
+							Body  string `json:"body"`
+						} `json:"content"`
+						FieldValueByName *struct {
+							OptionID string `json:"optionId"`
+						} `json:"fieldValueByName"`
+					} `json:"nodes"`
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+				} `json:"items"`
+			} `json:"node"`
+		}
+		vars := map[string]interface{}{"project": projectID, "field": e.config.StatusField, "cursor": cursor}
+		if err := e.do(ctx, query, vars, &data); err != nil {
+			return nil, fmt.Errorf("error listing the items of project %d of %s: %w", e.config.Number, e.config.Owner, err)
+		}
+
+		for _, node := range data.Node.Items.Nodes {
+			if node.IsArchived || node.Content == nil {
+				continue
+			}
+			match := markerPattern.FindStringSubmatch(node.Content.Body)
+			if match == nil {
+				continue // Not an item of a finding
+			}
+			it := item{id: node.ID, draftID: node.Content.ID, title: node.Content.Title, body: node.Content.Body, fingerprint: match[1], key: match[2]}
+			if node.FieldValueByName != nil {
+				it.status = node.FieldValueByName.OptionID
+			}
+			items[it.fingerprint] = it
+		}
+
+		if !data.Node.Items.PageInfo.HasNextPage {
+			return items, nil
+		}
+		endCursor := data.Node.Items.PageInfo.EndCursor
+		cursor = &endCursor
+	}
+}
+
+// addItem adds a draft issue to the board and returns the ID of its item
+func (e *Exporter) addItem(ctx context.Context, projectID, title, body string) (string, error) {
+	const mutation = `mutation($project: ID!, $title: String!, $body: String!) {
+  addProjectV2DraftIssue(input: {projectId: $project, title: $title, body: $body}) { projectItem { id } }
+}`
+	var data struct {
+		AddProjectV2DraftIssue struct {
+			ProjectItem struct {
+				ID string `json:"id"`
+			} `json:"projectItem"`
+		} `json:"addProjectV2DraftIssue"`
+	}
+	if err := e.do(ctx, mutation, map[string]interface{}{"project": projectID, "title": title, "body": body}, &data); err != nil {
+		return "", fmt.Errorf("error adding item %q: %w", title, err)
+	}
+	return data.AddProjectV2DraftIssue.ProjectItem.ID, nil
+}
+
+// updateItem updates the title and body of the draft issue of an item
+func (e *Exporter) updateItem(ctx context.Context, draftID, title, body string) error {
+	const mutation = `mutation($draft: ID!, $title: String!, $body: String!) {
+  updateProjectV2DraftIssue(input: {draftIssueId: $draft, title: $title, body: $body}) { draftIssue { id } }
+}`
+	if err := e.do(ctx, mutation, map[string]interface{}{"draft": draftID, "title": title, "body": body}, nil); err != nil {
+		return fmt.Errorf("error updating item %q: %w", title, err)
+	}
+	return nil
+}
+
+// setStatus sets the status of an item to the given option
+func (e *Exporter) setStatus(ctx context.Context, p *project, itemID, optionID string) error {
+	const mutation = `mutation($project: ID!, $item: ID!, $field: ID!, $option: String!) {
+  updateProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $field, value: {singleSelectOptionId: $option}}) { projectV2Item { id } }
+}`
+	vars := map[string]interface{}{"project": p.id, "item": itemID, "field": p.fieldID, "option": optionID}
+	if err := e.do(ctx, mutation, vars, nil); err != nil {
+		return fmt.Errorf("error setting the status of item %s: %w", itemID, err)
+	}
+	return nil
+}
+
+// do sends a GraphQL request and decodes the data of its response into out, when it is not nil
+func (e *Exporter) do(ctx context.Context, query string, vars map[string]interface{}, out interface{}) error {
+	payload, err := json.Marshal(map[string]interface{}{"query": query, "variables": vars})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+e.token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", common.UserAgent(ctx))
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GraphQL request failed: HTTP %d", resp.StatusCode)
+	}
+
+	var decoded struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return fmt.Errorf("error decoding GraphQL response: %w", err)
+	}
+	if len(decoded.Errors) > 0 {
+		messages := make([]string, 0, len(decoded.Errors))
+		for _, gqlErr := range decoded.Errors {
+			messages = append(messages, gqlErr.Message)
+		}
+		return fmt.Errorf("GraphQL request failed: %s", strings.Join(messages, "; "))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(decoded.Data, out)
+}
@@ -0,0 +1,200 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/projects"
+)
+
+// boardItem is an item of the fake board
+type boardItem struct {
+	title, body, status string
+	archived            bool
+}
+
+// board is a fake GitHub Projects board served over GraphQL
+type board struct {
+	items []*boardItem
+	token string
+}
+
+func (b *board) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.token = r.Header.Get("Authorization")
+	var req struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	vars := req.Variables
+
+	var data interface{}
+	switch {
+	case strings.Contains(req.Query, "repositoryOwner"):
+		data = map[string]interface{}{"repositoryOwner": map[string]interface{}{"projectV2": map[string]interface{}{
+			"id": "project",
+			"field": map[string]interface{}{"id": "status", "options": []map[string]string{
+				{"id": "todo", "name": "Todo"}, {"id": "doing", "name": "In Progress"}, {"id": "done", "name": "Done"},
+			}},
+		}}}
+	case strings.Contains(req.Query, "items(first"):
+		nodes := make([]map[string]interface{}, 0, len(b.items))
+		for i, it := range b.items {
+			node := map[string]interface{}{
+				"id":         fmt.Sprintf("item-%d", i),
+				"isArchived": it.archived,
+				"content":    map[string]string{"id": fmt.Sprintf("draft-%d", i), "title": it.title, "body": it.body},
+			}
+			if it.status != "" {
+				node["fieldValueByName"] = map[string]string{"optionId": it.status}
+			}
+			nodes = append(nodes, node)
+		}
+		data = map[string]interface{}{"node": map[string]interface{}{"items": map[string]interface{}{
+			"nodes": nodes, "pageInfo": map[string]interface{}{"hasNextPage": false},
+		}}}
+	case strings.Contains(req.Query, "addProjectV2DraftIssue"):
+		b.items = append(b.items, &boardItem{title: vars["title"].(string), body: vars["body"].(string)})
+		data = map[string]interface{}{"addProjectV2DraftIssue": map[string]interface{}{
+			"projectItem": map[string]string{"id": fmt.Sprintf("item-%d", len(b.items)-1)},
+		}}
+	case strings.Contains(req.Query, "updateProjectV2DraftIssue"):
+		it := b.item(vars["draft"].(string), "draft-")
+		it.title, it.body = vars["title"].(string), vars["body"].(string)
+		data = map[string]interface{}{}
+	case strings.Contains(req.Query, "updateProjectV2ItemFieldValue"):
+		b.item(vars["item"].(string), "item-").status = vars["option"].(string)
+		data = map[string]interface{}{}
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+}
+
+// item returns the item with the given ID
+func (b *board) item(id, prefix string) *boardItem {
+	var i int
+	fmt.Sscanf(strings.TrimPrefix(id, prefix), "%d", &i)
+	return b.items[i]
+}
+
+func newExporter(url string) *projects.Exporter {
+	return projects.New(config.ProjectsConfig{
+		Owner:       "example-org",
+		Number:      3,
+		StatusField: "Status",
+		OpenStatus:  "Todo",
+		DoneStatus:  "Done",
+		GraphQLURL:  url,
+	}, "projects-token")
+}
+
+func TestSync(t *testing.T) {
+	b := &board{}
+	server := httptest.NewServer(b)
+	defer server.Close()
+	exporter := newExporter(server.URL)
+
+	stale := findings.Finding{Monitor: "pr_checker", Repository: "example-org/api", Subject: "PR #1", Summary: "Merged without approval"}
+	open := findings.Finding{Monitor: "pr_checker", Repository: "example-org/web", Subject: "PR #2", Summary: "Merged without approval"}
+	partial := findings.Finding{Monitor: "rulesets", Repository: "example-org/web", Summary: "No ruleset"}
+	suppressed := findings.Finding{Monitor: "pr_checker", Repository: "example-org/docs", Subject: "PR #3", Summary: "Merged without approval"}
+
+	// The first run adds an item for each open finding
+	result, err := exporter.Sync(context.Background(), projects.Run{
+		Open:     []findings.Finding{stale, open, partial, suppressed},
+		Found:    []findings.Finding{stale, open, partial, suppressed},
+		Complete: map[string]bool{"pr_checker": true, "rulesets": true},
+	})
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if result != (projects.Result{Created: 4}) {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if b.token != "Bearer projects-token" {
+		t.Errorf("Expected the projects token, got %q", b.token)
+	}
+	for _, it := range b.items {
+		if it.status != "todo" {
+			t.Errorf("Expected item %q to be todo, got %q", it.title, it.status)
+		}
+	}
+	if b.items[0].title != "[pr_checker] example-org/api: PR #1" {
+		t.Errorf("Unexpected title: %s", b.items[0].title)
+	}
+
+	// A finding moved in progress is left there while it stays open
+	b.items[1].status = "doing"
+
+	// The finding missing from a complete run resolves, the one missing from a partial run and the suppressed one stay,
+	// and the changed finding is updated
+	open.Summary = "Merged without review"
+	result, err = exporter.Sync(context.Background(), projects.Run{
+		Open:     []findings.Finding{open},
+		Found:    []findings.Finding{open, suppressed},
+		Complete: map[string]bool{"pr_checker": true},
+	})
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if result != (projects.Result{Updated: 1, Resolved: 1}) {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if got := []string{b.items[0].status, b.items[1].status, b.items[2].status, b.items[3].status}; strings.Join(got, ",") != "done,doing,todo,todo" {
+		t.Errorf("Unexpected statuses: %v", got)
+	}
+	if !strings.HasPrefix(b.items[1].body, "Merged without review") {
+		t.Errorf("Expected the body to be updated, got %q", b.items[1].body)
+	}
+
+	// A resolved finding found again is moved back, unless its item was archived
+	result, err = exporter.Sync(context.Background(), projects.Run{Open: []findings.Finding{stale, open}, Found: []findings.Finding{stale, open}})
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if result != (projects.Result{Reopened: 1}) || b.items[0].status != "todo" {
+		t.Errorf("Unexpected result: %+v, status %q", result, b.items[0].status)
+	}
+
+	b.items[0].archived = true
+	result, err = exporter.Sync(context.Background(), projects.Run{Open: []findings.Finding{stale}, Found: []findings.Finding{stale}})
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if result != (projects.Result{Created: 1}) || len(b.items) != 5 {
+		t.Errorf("Expected a new item for the finding of the archived item, got %+v", result)
+	}
+}
+
+func TestSyncErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"repositoryOwner": null}, "errors": [{"message": "Could not resolve to a ProjectV2"}]}`))
+	}))
+	defer server.Close()
+
+	_, err := newExporter(server.URL).Sync(context.Background(), projects.Run{})
+	if err == nil || !strings.Contains(err.Error(), "Could not resolve to a ProjectV2") {
+		t.Errorf("Expected the GraphQL error, got %v", err)
+	}
+}
+
+func TestBody(t *testing.T) {
+	f := findings.Finding{Monitor: "pr_checker", Account: "acme", Repository: "example-org/api", Subject: "PR #1",
+		Summary: "Merged without approval", URL: "https://github.com/example-org/api/pull/1", Controls: []string{"SOC2 CC8.1"}}
+	body := projects.Body(f)
+	for _, want := range []string{"- Account: acme", "- Controls: SOC2 CC8.1", "- Link: https://github.com/example-org/api/pull/1",
+		"<!-- git-monitor fingerprint=" + f.Fingerprint() + " key=acme/pr_checker -->"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected body to contain %q, got %q", want, body)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package provenance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+// Identity is the user a token authenticates as
+type Identity struct {
+	Account string `json:"account,omitempty"` // Account the token belongs to, empty with a single account
+	Login   string `json:"login,omitempty"`
+	Error   string `json:"error,omitempty"` // Why the login could not be determined, e.g. for GitHub App tokens
+}
+
+// Metadata describes how a report was produced, for reproducibility and audit trails
+type Metadata struct {
+	RunID        string     `json:"run_id,omitempty"` // Run the GitHub API requests were tagged with in their User-Agent
+	Version      string     `json:"version"`          // Version of the git-monitor binary
+	ConfigSHA256 string     `json:"config_sha256"`    // Hash of the configuration file
+	StartedAt    time.Time  `json:"started_at"`
+	FinishedAt   time.Time  `json:"finished_at"` // When the report was produced
+	APICalls     int64      `json:"api_calls"`   // GitHub API requests sent until the report was produced
+	Identities   []Identity `json:"identities"`  // Who the tokens authenticate as
+
+	// Shard of the scan the run checked, e.g. "2/5", empty when it checked every repository
+	Shard string `json:"shard,omitempty"`
+}
+
+// Run holds the provenance of a run, from which the metadata of each of its reports is taken
+type Run struct {
+	id           string
+	version      string
+	configSHA256 string
+	startedAt    time.Time
+	identities   []Identity
+}
+
+// NewRun records the provenance of a run started at the given time
+func NewRun(id, version, configSHA256 string, startedAt time.Time, identities []Identity) *Run {
+	if identities == nil {
+		identities = []Identity{}
+	}
+	return &Run{
+		id:           id,
+		version:      version,
+		configSHA256: configSHA256,
+		startedAt:    startedAt,
+		identities:   identities,
+	}
+}
+
+// Identities returns who the tokens of the run authenticate as
+func (r *Run) Identities() []Identity {
+	return r.identities
+}
+
+// Metadata returns the metadata of a report produced now
+func (r *Run) Metadata() Metadata {
+	return Metadata{
+		RunID:        r.id,
+		Version:      r.version,
+		ConfigSHA256: r.configSHA256,
+		StartedAt:    r.startedAt.UTC().Truncate(time.Second),
+		FinishedAt:   time.Now().UTC().Truncate(time.Second),
+		APICalls:     common.APICalls(),
+		Identities:   r.identities,
+		Shard:        r.shard(),
+	}
+}
+
+// shard returns the shard of the scan the run checks, empty when it checks every repository
+func (r *Run) shard() string {
+	if s := common.CurrentShard(); s != nil {
+		return s.String()
+	}
+	return ""
+}
+
+// Logins describes who the tokens authenticate as, e.g. "monitor-bot" or "acme: bot-a, globex: bot-b"
+func (m Metadata) Logins() string {
+	logins := make([]string, 0, len(m.Identities))
+	for _, identity := range m.Identities {
+		login := identity.Login
+		if login == "" {
+			login = "unknown"
+		}
+		if identity.Account != "" {
+			login = identity.Account + ": " + login
+		}
+		logins = append(logins, login)
+	}
+	if len(logins) == 0 {
+		return "unknown"
+	}
+	return strings.Join(logins, ", ")
+}
+
+// lines returns the metadata as "name: value" lines
+func (m Metadata) lines() []string {
+	lines := []string{
+		"git-monitor version: " + m.Version,
+		"Config SHA-256: " + m.ConfigSHA256,
+		"Scan: " + m.StartedAt.Format(time.RFC3339) + " to " + m.FinishedAt.Format(time.RFC3339),
+		fmt.Sprintf("GitHub API calls: %d", m.APICalls),
+		"Authenticated as: " + m.Logins(),
+	}
+	if m.RunID != "" {
+		lines = append(lines, "Run ID: "+m.RunID)
+	}
+	if m.Shard != "" {
+		lines = append(lines, "Shard: "+m.Shard)
+	}
+	return lines
+}
+
+// WriteMarkdown writes the metadata as a footer of a markdown report
+func (m Metadata) WriteMarkdown(w io.Writer) {
+	fmt.Fprintln(w, "---")
+	fmt.Fprintln(w, "```")
+	for _, line := range m.lines() {
+		fmt.Fprintln(w, line)
+	}
+	fmt.Fprintln(w, "```")
+}
+
+// WriteCSVFooter writes the metadata as "#" comment lines after the rows of a CSV report
+func (m Metadata) WriteCSVFooter(w io.Writer) error {
+	for _, line := range m.lines() {
+		if _, err := fmt.Fprintf(w, "# %s\n", line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HashFile returns the hex encoded SHA-256 of a file
+func HashFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading %s: %v", path, err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
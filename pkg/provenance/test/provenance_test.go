@@ -0,0 +1,82 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/provenance"
+)
+
+func TestMetadata(t *testing.T) {
+	started := time.Date(2024, 1, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	run := provenance.NewRun("20240101T110000Z-1a2b3c4d", "v1.2.3", "abc123", started, []provenance.Identity{
+		{Account: "acme", Login: "bot-a"},
+		{Account: "globex", Error: "403 Resource not accessible by integration"},
+	})
+
+	metadata := run.Metadata()
+	if metadata.RunID != "20240101T110000Z-1a2b3c4d" || metadata.Version != "v1.2.3" || metadata.ConfigSHA256 != "abc123" {
+		t.Errorf("Unexpected metadata: %+v", metadata)
+	}
+	if !metadata.StartedAt.Equal(started) || metadata.StartedAt.Location() != time.UTC {
+		t.Errorf("Expected the start time in UTC, got %v", metadata.StartedAt)
+	}
+	if metadata.FinishedAt.Before(metadata.StartedAt) {
+		t.Errorf("Expected the report to be produced after the start, got %v", metadata.FinishedAt)
+	}
+	if got := metadata.Logins(); got != "acme: bot-a, globex: unknown" {
+		t.Errorf("Unexpected logins: %q", got)
+	}
+
+	if got := provenance.NewRun("", "dev", "", started, nil).Metadata().Logins(); got != "unknown" {
+		t.Errorf("Expected an unknown login without identities, got %q", got)
+	}
+}
+
+func TestWriteCSVFooter(t *testing.T) {
+	started := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	metadata := provenance.Metadata{
+		Version:      "v1.2.3",
+		ConfigSHA256: "abc123",
+		StartedAt:    started,
+		FinishedAt:   started.Add(5 * time.Minute),
+		APICalls:     42,
+		Identities:   []provenance.Identity{{Login: "monitor-bot"}},
+	}
+
+	var buf strings.Builder
+	if err := metadata.WriteCSVFooter(&buf); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	expected := "# git-monitor version: v1.2.3\n" +
+		"# Config SHA-256: abc123\n" +
+		"# Scan: 2030-01-01T12:00:00Z to 2030-01-01T12:05:00Z\n" +
+		"# GitHub API calls: 42\n" +
+		"# Authenticated as: monitor-bot\n"
+	if buf.String() != expected {
+		t.Errorf("Expected footer:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestHashFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("[github]\n"), 0600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	hash, err := provenance.HashFile(path)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if hash != "622aa585adb557ede9f1f713410a7543e42dbfc8047063090225321bb10f3bad" {
+		t.Errorf("Expected a hex encoded SHA-256, got %q", hash)
+	}
+
+	if _, err := provenance.HashFile(filepath.Join(t.TempDir(), "missing.toml")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
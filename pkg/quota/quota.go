@@ -0,0 +1,114 @@
+package quota
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+// window is how far back API calls count against a share, GitHub's rate limit resets hourly
+const window = time.Hour
+
+// Coordinator budgets the hourly API calls of each account's token across monitors in server mode,
+// so a monitor that needs many calls cannot starve the others
+// Each monitor has a share of the hourly calls, and is deferred once its calls within the last hour use it up.
+// When the token has fewer calls left than the unused shares, monitors with larger shares come first
+type Coordinator struct {
+	mu     sync.Mutex
+	shares map[string]int64 // Calls per hour of each monitor, by key
+	order  []string         // Monitors by priority, largest share first
+	spent  []spend          // Calls sent within the last hour, oldest first
+}
+
+// spend is the API calls a monitor run sent for an account
+type spend struct {
+	account, monitor string
+	calls            int64
+	at               time.Time
+}
+
+// NewCoordinator splits hourly API calls among monitors: those with a percentage in shares get that share,
+// the rest is split equally among the other monitors. Monitors in neither get no calls
+func NewCoordinator(hourly int64, shares map[string]float64, monitors []string) *Coordinator {
+	c := &Coordinator{shares: make(map[string]int64)}
+
+	var assigned float64
+	var others []string
+	for _, key := range monitors {
+		if share, ok := shares[key]; ok {
+			c.shares[key] = int64(float64(hourly) * share / 100)
+			assigned += share
+		} else {
+			others = append(others, key)
+		}
+	}
+	if len(others) > 0 && assigned < 100 {
+		each := int64(float64(hourly) * (100 - assigned) / 100 / float64(len(others)))
+		for _, key := range others {
+			c.shares[key] = each
+		}
+	}
+
+	c.order = append([]string(nil), monitors...)
+	sort.SliceStable(c.order, func(i, j int) bool {
+		return c.shares[c.order[i]] > c.shares[c.order[j]]
+	})
+	return c
+}
+
+// Share returns the API calls per hour of a monitor
+func (c *Coordinator) Share(monitor string) int64 {
+	return c.shares[monitor]
+}
+
+// Allowance returns the API calls a monitor may send for an account now: what is left of its share within
+// the last hour, less what the monitors before it still need when the token's rate limit cannot cover them all
+// limit is the last rate limit reported for the token, nil when unknown. 0 means the monitor is deferred
+func (c *Coordinator) Allowance(account, monitor string, limit *common.RateLimit, now time.Time) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.forget(now)
+
+	used := make(map[string]int64)
+	for _, s := range c.spent {
+		if s.account == account {
+			used[s.monitor] += s.calls
+		}
+	}
+	left := func(key string) int64 {
+		return max(c.shares[key]-used[key], 0)
+	}
+
+	allowance := left(monitor)
+	if limit == nil || !limit.Reset.After(now) {
+		return allowance // A rate limit past its reset says nothing about the calls left
+	}
+
+	available := int64(limit.Remaining)
+	for _, key := range c.order {
+		if key == monitor {
+			break
+		}
+		available -= left(key)
+	}
+	return max(min(allowance, available), 0)
+}
+
+// Record counts the API calls a monitor run sent for an account against the monitor's share
+func (c *Coordinator) Record(account, monitor string, calls int64, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.forget(now)
+	c.spent = append(c.spent, spend{account: account, monitor: monitor, calls: calls, at: now})
+}
+
+// forget drops the calls sent more than an hour ago
+func (c *Coordinator) forget(now time.Time) {
+	i := 0
+	for i < len(c.spent) && now.Sub(c.spent[i].at) >= window {
+		i++
+	}
+	c.spent = c.spent[i:]
+}
@@ -0,0 +1,81 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/quota"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+func TestShares(t *testing.T) {
+	c := quota.NewCoordinator(5000, map[string]float64{"pr_checker": 60}, []string{"pr_checker", "rulesets", "dependabot_alerts"})
+
+	if share := c.Share("pr_checker"); share != 3000 {
+		t.Errorf("Expected the PR checker to get 3000 calls, got %d", share)
+	}
+	// The other monitors split the remaining 40%
+	if share := c.Share("rulesets"); share != 1000 {
+		t.Errorf("Expected rulesets to get 1000 calls, got %d", share)
+	}
+	if share := c.Share("repo_visibility"); share != 0 {
+		t.Errorf("Expected monitors that are not enabled to get no calls, got %d", share)
+	}
+}
+
+func TestAllowanceDefersMonitorsOverTheirShare(t *testing.T) {
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := quota.NewCoordinator(5000, map[string]float64{"pr_checker": 60}, []string{"pr_checker", "rulesets", "dependabot_alerts"})
+
+	c.Record("", "rulesets", 700, now.Add(-50*time.Minute))
+	c.Record("", "rulesets", 300, now.Add(-10*time.Minute))
+	c.Record("", "pr_checker", 2500, now.Add(-5*time.Minute))
+
+	if allowance := c.Allowance("", "rulesets", nil, now); allowance != 0 {
+		t.Errorf("Expected rulesets to be deferred, got an allowance of %d", allowance)
+	}
+	if allowance := c.Allowance("", "pr_checker", nil, now); allowance != 500 {
+		t.Errorf("Expected the PR checker to have 500 calls left, got %d", allowance)
+	}
+	if allowance := c.Allowance("", "dependabot_alerts", nil, now); allowance != 1000 {
+		t.Errorf("Expected dependabot_alerts to keep its share, got %d", allowance)
+	}
+
+	// Calls older than an hour no longer count
+	if allowance := c.Allowance("", "rulesets", nil, now.Add(15*time.Minute)); allowance != 700 {
+		t.Errorf("Expected rulesets to have 700 calls again, got %d", allowance)
+	}
+
+	// Each account has a token of its own
+	if allowance := c.Allowance("globex", "rulesets", nil, now); allowance != 1000 {
+		t.Errorf("Expected rulesets of another account to keep its share, got %d", allowance)
+	}
+}
+
+func TestAllowanceWhenTheRateLimitIsTight(t *testing.T) {
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := quota.NewCoordinator(5000, map[string]float64{"pr_checker": 60}, []string{"rulesets", "pr_checker", "dependabot_alerts"})
+
+	// Other automation sharing the token left 3500 calls: the PR checker comes first
+	limit := &common.RateLimit{Limit: 5000, Remaining: 3500, Reset: now.Add(30 * time.Minute)}
+	if allowance := c.Allowance("", "pr_checker", limit, now); allowance != 3000 {
+		t.Errorf("Expected the PR checker to get its share, got %d", allowance)
+	}
+	if allowance := c.Allowance("", "rulesets", limit, now); allowance != 500 {
+		t.Errorf("Expected rulesets to get what the PR checker leaves, got %d", allowance)
+	}
+
+	limit.Remaining = 2000
+	if allowance := c.Allowance("", "rulesets", limit, now); allowance != 0 {
+		t.Errorf("Expected rulesets to be deferred, got an allowance of %d", allowance)
+	}
+	if allowance := c.Allowance("", "pr_checker", limit, now); allowance != 2000 {
+		t.Errorf("Expected the PR checker to get the calls left, got %d", allowance)
+	}
+
+	// A rate limit past its reset is ignored
+	limit.Reset = now.Add(-time.Minute)
+	if allowance := c.Allowance("", "rulesets", limit, now); allowance != 1000 {
+		t.Errorf("Expected a stale rate limit to be ignored, got %d", allowance)
+	}
+}
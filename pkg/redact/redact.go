@@ -0,0 +1,157 @@
+package redact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+)
+
+// aliasesFile is the file mapping repository names to aliases
+type aliasesFile struct {
+	Aliases map[string]string `toml:"aliases"`
+}
+
+// Redactor replaces repository names with aliases or salted hashes in notifications
+type Redactor struct {
+	aliases  map[string]string
+	salt     string
+	redacted map[string]string // Redacted names used in this run, by repository
+}
+
+// NewRedactor creates a redactor from the redaction configuration
+// The salt is required, as hashes of names without one can be matched by hashing known repository names
+func NewRedactor(cfg config.RedactionConfig) (*Redactor, error) {
+	if strings.TrimSpace(cfg.Salt) == "" {
+		return nil, fmt.Errorf("salt is required to redact repository names")
+	}
+
+	r := &Redactor{
+		aliases:  make(map[string]string),
+		salt:     cfg.Salt,
+		redacted: make(map[string]string),
+	}
+
+	if cfg.AliasesFile != "" {
+		var file aliasesFile
+		if _, err := toml.DecodeFile(cfg.AliasesFile, &file); err != nil {
+			return nil, fmt.Errorf("error decoding aliases file: %v", err)
+		}
+		for repository, alias := range file.Aliases {
+			r.aliases[strings.ToLower(repository)] = alias
+		}
+	}
+
+	return r, nil
+}
+
+// Repository returns the alias of a repository, or a salted hash of its name when it has no alias
+// Organization-wide targets such as "org:owner" are redacted the same way
+func (r *Redactor) Repository(name string) string {
+	key := strings.ToLower(name)
+	redacted, ok := r.aliases[key]
+	if !ok {
+		sum := sha256.Sum256([]byte(r.salt + "\x00" + key))
+		redacted = "repo-" + hex.EncodeToString(sum[:4])
+	}
+
+	r.redacted[name] = redacted
+	return redacted
+}
+
+// WriteFindingsMarkdown writes redacted findings under the given heading in a code block format suitable for Slack
+func (r *Redactor) WriteFindingsMarkdown(w io.Writer, heading string, list []findings.Finding) {
+	if len(list) == 0 {
+		return // No results to display
+	}
+
+	fmt.Fprintf(w, "## :lock: %s\n", heading)
+	fmt.Fprintf(w, "%d findings. Repository names are redacted, see the full report for details.\n\n", len(list))
+
+	// Start code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "Repository                Subject")
+	fmt.Fprintln(w, "---------------------------------------------")
+	for _, f := range list {
+		fmt.Fprintf(w, "%-25s %s\n", r.Repository(f.Repository), f.Subject)
+	}
+	// End code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
+
+// WriteChangesMarkdown writes redacted changes since the previous run in a code block format suitable for Slack
+func (r *Redactor) WriteChangesMarkdown(w io.Writer, changes findings.Changes) {
+	if changes.Empty() {
+		return // No changes to display
+	}
+
+	fmt.Fprintln(w, "## :arrows_counterclockwise: Changes Since Last Run")
+	fmt.Fprintf(w, "%d new and %d resolved findings since the previous run.\n\n", len(changes.New), len(changes.Resolved))
+
+	// Start code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "Change    Monitor                   Repository                Subject")
+	fmt.Fprintln(w, "---------------------------------------------------------------------------")
+	for _, f := range changes.New {
+		fmt.Fprintf(w, "%-9s %-25s %-25s %s\n", "new", f.Monitor, r.Repository(f.Repository), f.Subject)
+	}
+	for _, f := range changes.Resolved {
+		fmt.Fprintf(w, "%-9s %-25s %-25s %s\n", "resolved", f.Monitor, r.Repository(f.Repository), f.Subject)
+	}
+	// End code block
+	fmt.Fprintln(w, "```")
+
+	if len(changes.NewlyAffected) > 0 {
+		fmt.Fprintf(w, "Newly affected repositories: %s\n", strings.Join(r.repositories(changes.NewlyAffected), ", "))
+	}
+	if len(changes.NowClean) > 0 {
+		fmt.Fprintf(w, "Repositories now clean: %s\n", strings.Join(r.repositories(changes.NowClean), ", "))
+	}
+	fmt.Fprintln(w, "")
+}
+
+// repositories redacts a list of repository names
+func (r *Redactor) repositories(names []string) []string {
+	redacted := make([]string, 0, len(names))
+	for _, name := range names {
+		redacted = append(redacted, r.Repository(name))
+	}
+	return redacted
+}
+
+// WriteMappingMarkdown writes the repositories redacted so far with their redacted names
+// It belongs in the restricted full report, so readers can look up the findings sent to shared channels
+func (r *Redactor) WriteMappingMarkdown(w io.Writer) {
+	if len(r.redacted) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(r.redacted))
+	for name := range r.redacted {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "## :key: Redacted Repository Names")
+	fmt.Fprintln(w, "Names used for these repositories in shared notifications.")
+	fmt.Fprintln(w, "")
+
+	// Start code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "Redacted                  Repository")
+	fmt.Fprintln(w, "---------------------------------------------")
+	for _, name := range names {
+		fmt.Fprintf(w, "%-25s %s\n", r.redacted[name], name)
+	}
+	// End code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
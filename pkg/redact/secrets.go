@@ -0,0 +1,159 @@
+package redact
+
+import (
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Mask replaces secrets in logs, errors and reports
+const Mask = "[REDACTED]"
+
+// minSecretLength is the length below which registered values are not masked, as they would mask common words
+const minSecretLength = 8
+
+// secretPatterns match secrets by their shape, including those never registered, e.g. from API responses
+var secretPatterns = []*regexp.Regexp{
+	// GitHub personal access, OAuth, app installation and refresh tokens
+	regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`),
+	// Slack bot, user and app tokens
+	regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`),
+	// Slack and Microsoft Teams webhooks, whose path is the credential
+	regexp.MustCompile(`https://hooks\.slack\.com/(?:services|workflows|triggers)/[^\s"'<>)\]]+`),
+	regexp.MustCompile(`https://[A-Za-z0-9.-]+\.webhook\.office\.com/[^\s"'<>)\]]+`),
+}
+
+// credentialPatterns match secrets next to what names them, the groups around the secret are kept
+var credentialPatterns = []*regexp.Regexp{
+	// Passwords in the user information of URLs, e.g. of state stores
+	regexp.MustCompile(`(://[^/\s:@"']+:)[^/\s@"']+(@)`),
+}
+
+// headerPattern matches Authorization headers and keys of cloud services sent as headers, as dumped by HTTP clients,
+// e.g. "Authorization: Bearer ..." or "Authorization:[token ...]", with the scheme or credentials and the word after them
+var headerPattern = regexp.MustCompile(`(?i)(\b(?:(?:proxy-)?authorization|aeg-sas-key)["']?\s*[:=]\s*\[?["']?)([^\s"',\[\]]+)(?:(\s+)([^\s"',\[\]]+))?`)
+
+// authSchemes are the schemes that precede the credentials of Authorization headers
+var authSchemes = map[string]bool{"basic": true, "bearer": true, "token": true, "sharedaccesssignature": true}
+
+// secrets are the registered secret values, longest first so a secret containing another is masked whole
+var (
+	secretsMu sync.RWMutex
+	secrets   []string
+)
+
+// Register adds secrets to mask wherever they appear, such as the tokens and webhooks of the configuration
+// Empty and very short values are ignored
+func Register(values ...string) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if len(value) < minSecretLength || contains(secrets, value) {
+			continue
+		}
+		secrets = append(secrets, value)
+	}
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+}
+
+// Reset forgets the registered secrets
+func Reset() {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	secrets = nil
+}
+
+// Secrets returns the text with registered secrets and anything shaped like a token, webhook URL,
+// Authorization header or URL password masked
+func Secrets(text string) string {
+	secretsMu.RLock()
+	for _, secret := range secrets {
+		text = strings.ReplaceAll(text, secret, Mask)
+	}
+	secretsMu.RUnlock()
+
+	for _, pattern := range secretPatterns {
+		text = pattern.ReplaceAllString(text, Mask)
+	}
+	for _, pattern := range credentialPatterns {
+		text = pattern.ReplaceAllString(text, "${1}"+Mask+"${2}")
+	}
+	return maskHeaders(text)
+}
+
+// maskHeaders masks the credentials of headers, keeping the scheme of Authorization headers
+// Credentials that were already masked are left as they are, so masking twice changes nothing
+func maskHeaders(text string) string {
+	return headerPattern.ReplaceAllStringFunc(text, func(match string) string {
+		groups := headerPattern.FindStringSubmatch(match)
+		header, first, space, second := groups[1], groups[2], groups[3], groups[4]
+		if strings.HasSuffix(header, "[") && "["+first+"]" == Mask {
+			return match
+		}
+		if !authSchemes[strings.ToLower(first)] {
+			return header + Mask + space + second
+		}
+		if second == "" {
+			return match
+		}
+		return header + first + space + Mask
+	})
+}
+
+// Error returns the error with secrets masked in its message, nil for nil
+// The original error is still matched by errors.Is and errors.As
+func Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	message := Secrets(err.Error())
+	if message == err.Error() {
+		return err
+	}
+	return &maskedError{message: message, err: err}
+}
+
+// maskedError is an error whose message had secrets masked
+type maskedError struct {
+	message string
+	err     error
+}
+
+func (e *maskedError) Error() string { return e.message }
+
+func (e *maskedError) Unwrap() error { return e.err }
+
+// writer masks secrets in what is written through it
+type writer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriter returns a writer masking secrets before writing to w, for loggers
+// Each write is masked on its own, which suits loggers writing a line at a time
+func NewWriter(w io.Writer) io.Writer {
+	return &writer{w: w}
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, err := io.WriteString(w.w, Secrets(string(p))); err != nil {
+		return 0, err
+	}
+	// Report the length written by the caller, loggers treat short writes as errors
+	return len(p), nil
+}
+
+// contains reports whether the list holds the value
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/redact"
+)
+
+func TestRepository(t *testing.T) {
+	aliases := filepath.Join(t.TempDir(), "aliases.toml")
+	if err := os.WriteFile(aliases, []byte("[aliases]\n\"Owner/Secret\" = \"project-falcon\"\n"), 0600); err != nil {
+		t.Fatalf("Failed to write aliases file: %v", err)
+	}
+
+	redactor, err := redact.NewRedactor(config.RedactionConfig{Enabled: true, AliasesFile: aliases, Salt: "salt"})
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	if got := redactor.Repository("owner/secret"); got != "project-falcon" {
+		t.Errorf("Expected the alias regardless of case, got %q", got)
+	}
+
+	hashed := redactor.Repository("owner/other")
+	if !strings.HasPrefix(hashed, "repo-") || strings.Contains(hashed, "other") {
+		t.Errorf("Expected a hash for repositories without an alias, got %q", hashed)
+	}
+	if redactor.Repository("owner/other") != hashed {
+		t.Error("Expected the same repository to get the same hash")
+	}
+	if redactor.Repository("owner/another") == hashed {
+		t.Error("Expected different repositories to get different hashes")
+	}
+
+	// Hashes depend on the salt, so they cannot be matched against hashes of known names
+	other, err := redact.NewRedactor(config.RedactionConfig{Enabled: true, Salt: "other-salt"})
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if other.Repository("owner/other") == hashed {
+		t.Error("Expected hashes to depend on the salt")
+	}
+}
+
+func TestNewRedactorErrors(t *testing.T) {
+	if _, err := redact.NewRedactor(config.RedactionConfig{Enabled: true, Salt: " "}); err == nil {
+		t.Error("Expected an error for a blank salt")
+	}
+
+	if _, err := redact.NewRedactor(config.RedactionConfig{Enabled: true, AliasesFile: "missing.toml", Salt: "salt"}); err == nil {
+		t.Error("Expected an error for a missing aliases file")
+	}
+
+	invalid := filepath.Join(t.TempDir(), "aliases.toml")
+	if err := os.WriteFile(invalid, []byte("[aliases\n"), 0600); err != nil {
+		t.Fatalf("Failed to write aliases file: %v", err)
+	}
+	if _, err := redact.NewRedactor(config.RedactionConfig{Enabled: true, AliasesFile: invalid, Salt: "salt"}); err == nil {
+		t.Error("Expected an error for an invalid aliases file")
+	}
+}
@@ -0,0 +1,108 @@
+package remediation
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/anupsv/git-monitoring/pkg/findings"
+)
+
+// Link is a page where a finding can be looked into or fixed
+type Link struct {
+	Name string // What the page is, e.g. "Runbook" or "Branch protection"
+	URL  string
+}
+
+// page is the GitHub settings page the findings of a monitor are fixed at
+type page struct {
+	name string
+	repo string // URL format of the page of a repository, given "owner/repo"
+	org  string // URL format of the page of an organization, given its name, empty when there is none
+}
+
+// pages are the settings pages by monitor key, monitors without one only link their runbooks and PRs
+var pages = map[string]page{
+	"pr_checker":               {name: "Branch protection", repo: "https://github.com/%s/settings/branches"},
+	"admin_enforcement":        {name: "Branch protection", repo: "https://github.com/%s/settings/branches"},
+	"repo_visibility":          {name: "Repository settings", repo: "https://github.com/%s/settings"},
+	"dormant_repositories":     {name: "Repository settings", repo: "https://github.com/%s/settings"},
+	"issue_hygiene":            {name: "Repository settings", repo: "https://github.com/%s/settings"},
+	"repo_creation":            {name: "Repository settings", repo: "https://github.com/%s/settings"},
+	"rulesets":                 {name: "Rulesets", repo: "https://github.com/%s/settings/rules", org: "https://github.com/organizations/%s/settings/rules"},
+	"code_scanning_dismissals": {name: "Security settings", repo: "https://github.com/%s/settings/security_analysis"},
+	"dependabot_dismissals":    {name: "Security settings", repo: "https://github.com/%s/settings/security_analysis"},
+	"push_protection_bypasses": {name: "Security settings", repo: "https://github.com/%s/settings/security_analysis"},
+	"ghas_utilization":         {name: "Security settings", repo: "https://github.com/%s/settings/security_analysis"},
+	"dormant_accounts":         {name: "Access", repo: "https://github.com/%s/settings/access", org: "https://github.com/orgs/%s/people"},
+	"workflow_permissions":     {name: "Actions settings", repo: "https://github.com/%s/settings/actions", org: "https://github.com/organizations/%s/settings/actions"},
+	"fork_workflow_approvals":  {name: "Actions settings", repo: "https://github.com/%s/settings/actions", org: "https://github.com/organizations/%s/settings/actions"},
+	"environment_protection":   {name: "Environments", repo: "https://github.com/%s/settings/environments"},
+}
+
+// Runbook returns the runbook of a finding: the URL template of its monitor and rule, e.g. "pr_checker.unapproved",
+// or else of its monitor, with {monitor}, {rule} and {repository} replaced. It is empty for findings without a runbook
+func Runbook(runbooks map[string]string, f findings.Finding) string {
+	template, ok := "", false
+	if f.Rule != "" {
+		template, ok = runbooks[f.Monitor+"."+f.Rule]
+	}
+	if !ok {
+		template = runbooks[f.Monitor]
+	}
+	return strings.NewReplacer("{monitor}", f.Monitor, "{rule}", f.Rule, "{repository}", f.Repository).Replace(template)
+}
+
+// Links returns where a finding can be fixed: its runbook, the pull request to review for findings about one,
+// and the settings page of its repository or organization where its monitor's settings are changed
+func Links(runbooks map[string]string, f findings.Finding) []Link {
+	var links []Link
+	if runbook := Runbook(runbooks, f); runbook != "" {
+		links = append(links, Link{Name: "Runbook", URL: runbook})
+	}
+	if strings.Contains(f.URL, "/pull/") {
+		links = append(links, Link{Name: "Review", URL: strings.TrimSuffix(f.URL, "/") + "/files"})
+	}
+
+	p, ok := pages[f.Monitor]
+	if !ok {
+		return links
+	}
+	if org, found := strings.CutPrefix(f.Repository, "org:"); found {
+		if p.org != "" {
+			links = append(links, Link{Name: p.name, URL: fmt.Sprintf(p.org, org)})
+		}
+	} else if strings.Contains(f.Repository, "/") {
+		links = append(links, Link{Name: p.name, URL: fmt.Sprintf(p.repo, f.Repository)})
+	}
+	return links
+}
+
+// WriteMarkdown writes where to fix each finding, for the report and its notification
+// Findings without links are left out, and nothing is written when none has any
+func WriteMarkdown(w io.Writer, runbooks map[string]string, list []findings.Finding) {
+	type remediation struct {
+		finding findings.Finding
+		links   []Link
+	}
+	var remediations []remediation
+	for _, f := range list {
+		if links := Links(runbooks, f); len(links) > 0 {
+			remediations = append(remediations, remediation{finding: f, links: links})
+		}
+	}
+	if len(remediations) == 0 {
+		return // No results to display
+	}
+
+	fmt.Fprintln(w, "## :wrench: Remediation")
+	fmt.Fprintf(w, "Where to fix %d findings.\n\n", len(remediations))
+	for _, r := range remediations {
+		links := make([]string, 0, len(r.links))
+		for _, link := range r.links {
+			links = append(links, link.Name+": "+link.URL)
+		}
+		fmt.Fprintf(w, "- %s %s %s: %s\n", r.finding.Monitor, r.finding.Repository, r.finding.Subject, strings.Join(links, " | "))
+	}
+	fmt.Fprintln(w, "")
+}
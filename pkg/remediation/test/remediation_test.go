@@ -0,0 +1,106 @@
+package test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/remediation"
+)
+
+func TestRunbook(t *testing.T) {
+	runbooks := map[string]string{
+		"pr_checker":            "https://wiki.example.com/runbooks/{monitor}",
+		"pr_checker.unapproved": "https://wiki.example.com/runbooks/{monitor}/{rule}?repo={repository}",
+	}
+
+	tests := []struct {
+		finding  findings.Finding
+		expected string
+	}{
+		{findings.Finding{Monitor: "pr_checker", Repository: "acme/api", Rule: "unapproved"}, "https://wiki.example.com/runbooks/pr_checker/unapproved?repo=acme/api"},
+		{findings.Finding{Monitor: "pr_checker", Repository: "acme/api", Rule: "rubber_stamp"}, "https://wiki.example.com/runbooks/pr_checker"},
+		{findings.Finding{Monitor: "repo_visibility", Repository: "acme/api"}, ""},
+	}
+	for _, tc := range tests {
+		if runbook := remediation.Runbook(runbooks, tc.finding); runbook != tc.expected {
+			t.Errorf("Expected the runbook of %s/%s to be %q, got %q", tc.finding.Monitor, tc.finding.Rule, tc.expected, runbook)
+		}
+	}
+}
+
+func TestLinks(t *testing.T) {
+	runbooks := map[string]string{"pr_checker.unapproved": "https://wiki.example.com/unapproved"}
+
+	tests := []struct {
+		name     string
+		finding  findings.Finding
+		expected []remediation.Link
+	}{
+		{
+			name:    "Unapproved PR",
+			finding: findings.Finding{Monitor: "pr_checker", Repository: "acme/api", Subject: "PR #12", Rule: "unapproved", URL: "https://github.com/acme/api/pull/12"},
+			expected: []remediation.Link{
+				{Name: "Runbook", URL: "https://wiki.example.com/unapproved"},
+				{Name: "Review", URL: "https://github.com/acme/api/pull/12/files"},
+				{Name: "Branch protection", URL: "https://github.com/acme/api/settings/branches"},
+			},
+		},
+		{
+			name:     "Repository made public",
+			finding:  findings.Finding{Monitor: "repo_visibility", Repository: "acme/api", Subject: "visibility"},
+			expected: []remediation.Link{{Name: "Repository settings", URL: "https://github.com/acme/api/settings"}},
+		},
+		{
+			name:     "Organization ruleset",
+			finding:  findings.Finding{Monitor: "rulesets", Repository: "org:acme", Subject: "ruleset main"},
+			expected: []remediation.Link{{Name: "Rulesets", URL: "https://github.com/organizations/acme/settings/rules"}},
+		},
+		{
+			name:    "Monitor without an organization page",
+			finding: findings.Finding{Monitor: "environment_protection", Repository: "org:acme", Subject: "production"},
+		},
+		{
+			name:    "Monitor without a settings page",
+			finding: findings.Finding{Monitor: "token_health", Repository: "org:acme", Subject: "token"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if links := remediation.Links(runbooks, tc.finding); !reflect.DeepEqual(links, tc.expected) {
+				t.Errorf("Expected links %+v, got %+v", tc.expected, links)
+			}
+		})
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	remediation.WriteMarkdown(&buf, nil, []findings.Finding{
+		{Monitor: "token_health", Repository: "org:acme", Subject: "token"},
+		{Monitor: "repo_visibility", Repository: "acme/api", Subject: "visibility"},
+	})
+	output := buf.String()
+
+	// Findings without links are left out
+	for _, expected := range []string{
+		"## :wrench: Remediation",
+		"Where to fix 1 findings.",
+		"- repo_visibility acme/api visibility: Repository settings: https://github.com/acme/api/settings",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "token_health") {
+		t.Errorf("Expected findings without links to be left out, got:\n%s", output)
+	}
+
+	buf.Reset()
+	remediation.WriteMarkdown(&buf, nil, []findings.Finding{{Monitor: "token_health", Repository: "org:acme", Subject: "token"}})
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output without links, got:\n%s", buf.String())
+	}
+}
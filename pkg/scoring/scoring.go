@@ -0,0 +1,133 @@
+package scoring
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/anupsv/git-monitoring/pkg/findings"
+)
+
+// defaultWeight is the weight of findings of monitors without a configured weight
+const defaultWeight = 1
+
+// Score is the risk score of a run
+type Score struct {
+	Total        int               // Sum of the weights of all findings
+	Repositories []RepositoryScore // Scores of the affected repositories, highest first
+}
+
+// RepositoryScore is the risk score of a single repository
+type RepositoryScore struct {
+	Account    string // Account the repository was scanned with, empty with a single account
+	Repository string
+	Score      int // Sum of the weights of the repository's findings
+	Findings   int // Number of findings
+}
+
+// severityMultipliers multiply the weight of findings escalated to a severity
+var severityMultipliers = map[string]int{
+	findings.SeverityMedium:   2,
+	findings.SeverityHigh:     3,
+	findings.SeverityCritical: 4,
+}
+
+// Weight returns the weight of a finding
+// Low-severity findings weigh nothing, so conventions do not page anyone, and escalated findings weigh more
+func Weight(f findings.Finding, weights map[string]int) int {
+	if f.Severity == findings.SeverityLow {
+		return 0
+	}
+	weight, ok := weights[f.Monitor]
+	if !ok {
+		weight = defaultWeight
+	}
+	if multiplier, ok := severityMultipliers[f.Severity]; ok {
+		weight *= multiplier
+	}
+	return weight
+}
+
+// Compute calculates the per-repository and per-run scores of the findings
+func Compute(list []findings.Finding, weights map[string]int) Score {
+	type repositoryKey struct {
+		account    string
+		repository string
+	}
+
+	var score Score
+	byRepository := make(map[repositoryKey]*RepositoryScore)
+	for _, f := range list {
+		weight := Weight(f, weights)
+		score.Total += weight
+
+		key := repositoryKey{f.Account, f.Repository}
+		repo, ok := byRepository[key]
+		if !ok {
+			repo = &RepositoryScore{Account: f.Account, Repository: f.Repository}
+			byRepository[key] = repo
+		}
+		repo.Score += weight
+		repo.Findings++
+	}
+
+	score.Repositories = make([]RepositoryScore, 0, len(byRepository))
+	for _, repo := range byRepository {
+		score.Repositories = append(score.Repositories, *repo)
+	}
+	sort.Slice(score.Repositories, func(i, j int) bool {
+		a, b := score.Repositories[i], score.Repositories[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Account != b.Account {
+			return a.Account < b.Account
+		}
+		return a.Repository < b.Repository
+	})
+
+	return score
+}
+
+// Exceeds reports whether the run score reached the threshold
+func (s Score) Exceeds(threshold int) bool {
+	return threshold > 0 && s.Total >= threshold
+}
+
+// WriteMarkdown writes the risk scores in a code block format suitable for Slack
+// Repository names are passed through name, e.g. to redact them, when it is not nil
+func WriteMarkdown(w io.Writer, s Score, threshold int, name func(string) string) {
+	if s.Total == 0 {
+		return // No results to display
+	}
+
+	if name == nil {
+		name = func(repository string) string { return repository }
+	}
+
+	// Print header for risk scores
+	if s.Exceeds(threshold) {
+		fmt.Fprintln(w, "## :rotating_light: Risk Score Above Threshold")
+	} else {
+		fmt.Fprintln(w, "## :bar_chart: Risk Score")
+	}
+	fmt.Fprintf(w, "Run score %d (threshold %d) across %d repositories.\n\n", s.Total, threshold, len(s.Repositories))
+
+	// Start code block
+	fmt.Fprintln(w, "```")
+	// Create fixed-width headers with proper spacing for code block
+	fmt.Fprintln(w, "Score  Findings  Repository")
+	fmt.Fprintln(w, "---------------------------------------------")
+
+	for _, repo := range s.Repositories {
+		repoStr := name(repo.Repository)
+		if repo.Account != "" {
+			repoStr = fmt.Sprintf("%s (%s)", repoStr, repo.Account)
+		}
+		fmt.Fprintf(w, "%-6d %-9d %s\n", repo.Score, repo.Findings, repoStr)
+	}
+
+	// End code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
@@ -0,0 +1,81 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/scoring"
+)
+
+func TestCompute(t *testing.T) {
+	weights := map[string]int{
+		"push_protection_bypasses": 10,
+		"dormant_repositories":     0,
+	}
+	list := []findings.Finding{
+		{Monitor: "pr_checker", Repository: "owner/api", Subject: "PR #1"},
+		{Monitor: "pr_checker", Repository: "owner/api", Subject: "PR #2"},
+		{Monitor: "push_protection_bypasses", Repository: "owner/web", Subject: "alert #1"},
+		{Monitor: "dormant_repositories", Repository: "owner/old", Subject: "activity"},
+		{Monitor: "pr_checker", Repository: "owner/old", Subject: "PR #3 title", Severity: findings.SeverityLow},
+		{Monitor: "pr_checker", Account: "globex", Repository: "owner/api", Subject: "PR #1"},
+	}
+
+	score := scoring.Compute(list, weights)
+	if score.Total != 13 {
+		t.Errorf("Expected a run score of 13, got %d", score.Total)
+	}
+
+	expected := []scoring.RepositoryScore{
+		{Repository: "owner/web", Score: 10, Findings: 1},
+		{Repository: "owner/api", Score: 2, Findings: 2},
+		{Account: "globex", Repository: "owner/api", Score: 1, Findings: 1},
+		{Repository: "owner/old", Score: 0, Findings: 2},
+	}
+	if len(score.Repositories) != len(expected) {
+		t.Fatalf("Expected %d repository scores, got %+v", len(expected), score.Repositories)
+	}
+	for i, repo := range expected {
+		if score.Repositories[i] != repo {
+			t.Errorf("Expected repository score %d to be %+v, got %+v", i, repo, score.Repositories[i])
+		}
+	}
+}
+
+func TestExceeds(t *testing.T) {
+	score := scoring.Score{Total: 10}
+
+	tests := []struct {
+		threshold int
+		exceeds   bool
+	}{
+		{5, true},
+		{10, true},
+		{11, false},
+		{0, false},
+	}
+
+	for _, tc := range tests {
+		if got := score.Exceeds(tc.threshold); got != tc.exceeds {
+			t.Errorf("Expected Exceeds(%d) to be %v, got %v", tc.threshold, tc.exceeds, got)
+		}
+	}
+}
+
+func TestWeightOfEscalatedFindings(t *testing.T) {
+	weights := map[string]int{"pr_checker": 3}
+
+	tests := map[string]int{
+		"":                        3,
+		findings.SeverityLow:      0,
+		findings.SeverityMedium:   6,
+		findings.SeverityHigh:     9,
+		findings.SeverityCritical: 12,
+	}
+	for severity, expected := range tests {
+		f := findings.Finding{Monitor: "pr_checker", Repository: "owner/api", Subject: "PR #1", Severity: severity}
+		if weight := scoring.Weight(f, weights); weight != expected {
+			t.Errorf("Expected a %q finding to weigh %d, got %d", severity, expected, weight)
+		}
+	}
+}
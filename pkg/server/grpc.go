@@ -0,0 +1,201 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/anupsv/git-monitoring/pkg/api/gitmonitorv1"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+)
+
+// grpcService implements the gRPC API on top of the scan queue
+type grpcService struct {
+	gitmonitorv1.UnimplementedGitMonitorServiceServer
+
+	server *Server
+	config *gitmonitorv1.GetConfigResponse
+}
+
+// NewGRPCServer returns a gRPC server exposing the scan queue
+// config is returned by GetConfig and must not contain secrets
+func (s *Server) NewGRPCServer(config *gitmonitorv1.GetConfigResponse) *grpc.Server {
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(s.authenticateUnary),
+		grpc.StreamInterceptor(s.authenticateStream),
+	)
+	gitmonitorv1.RegisterGitMonitorServiceServer(grpcServer, &grpcService{
+		server: s,
+		config: config,
+	})
+	return grpcServer
+}
+
+// RunScan queues a scan and waits for it to finish
+func (g *grpcService) RunScan(ctx context.Context, req *gitmonitorv1.RunScanRequest) (*gitmonitorv1.ScanRun, error) {
+	run, err := g.submit(req.GetScope())
+	if err != nil {
+		return nil, err
+	}
+
+	run, err = g.server.Wait(ctx, run.ID)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return toProtoRun(run), nil
+}
+
+// StreamFindings streams the findings of an existing run or of a new scan, once the scan has finished
+func (g *grpcService) StreamFindings(req *gitmonitorv1.StreamFindingsRequest, stream gitmonitorv1.GitMonitorService_StreamFindingsServer) error {
+	id := req.GetRunId()
+	if id == "" {
+		run, err := g.submit(req.GetScope())
+		if err != nil {
+			return err
+		}
+		id = run.ID
+	}
+
+	run, err := g.server.Wait(stream.Context(), id)
+	if err != nil {
+		return toStatusError(err)
+	}
+
+	if run.Status == StatusFailed {
+		return status.Errorf(codes.Internal, "scan failed: %s", run.Error)
+	}
+
+	for _, f := range run.Result.Findings {
+		if err := stream.Send(toProtoFinding(f)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetConfig returns the monitor configuration
+func (g *grpcService) GetConfig(ctx context.Context, req *gitmonitorv1.GetConfigRequest) (*gitmonitorv1.GetConfigResponse, error) {
+	return proto.Clone(g.config).(*gitmonitorv1.GetConfigResponse), nil
+}
+
+// submit queues a scan for the given scope
+func (g *grpcService) submit(scope *gitmonitorv1.ScanScope) (*Run, error) {
+	run, err := g.server.Submit(ScanRequest{
+		Monitors:     scope.GetMonitors(),
+		Repositories: scope.GetRepositories(),
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return run, nil
+}
+
+// authenticateUnary requires the configured bearer token on unary calls
+func (s *Server) authenticateUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.authorizeGRPC(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// authenticateStream requires the configured bearer token on streaming calls
+func (s *Server) authenticateStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.authorizeGRPC(stream.Context()); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+// authorizeGRPC checks the bearer token in the authorization metadata
+func (s *Server) authorizeGRPC(ctx context.Context) error {
+	if s.options.AuthToken == "" {
+		return nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		token := strings.TrimPrefix(value, "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.options.AuthToken)) == 1 {
+			return nil
+		}
+	}
+
+	return status.Error(codes.Unauthenticated, "invalid or missing bearer token")
+}
+
+// toStatusError maps scan queue errors to gRPC status errors
+func toStatusError(err error) error {
+	switch {
+	case errors.Is(err, errQueueFull):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, errNotLeader):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, errRunNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+}
+
+// toProtoRun converts a run to its gRPC representation
+func toProtoRun(run *Run) *gitmonitorv1.ScanRun {
+	out := &gitmonitorv1.ScanRun{
+		Id:     run.ID,
+		Status: run.Status,
+		Scope: &gitmonitorv1.ScanScope{
+			Monitors:     run.Request.Monitors,
+			Repositories: run.Request.Repositories,
+		},
+		CreatedAt:  timestamppb.New(run.CreatedAt),
+		StartedAt:  toProtoTime(run.StartedAt),
+		FinishedAt: toProtoTime(run.FinishedAt),
+		Error:      run.Error,
+	}
+
+	if run.Result != nil {
+		out.Report = run.Result.Report
+		out.FailedMonitors = run.Result.Failed
+		for _, f := range run.Result.Findings {
+			out.Findings = append(out.Findings, toProtoFinding(f))
+		}
+	}
+
+	return out
+}
+
+// toProtoFinding converts a finding to its gRPC representation
+func toProtoFinding(f findings.Finding) *gitmonitorv1.Finding {
+	return &gitmonitorv1.Finding{
+		Monitor:     f.Monitor,
+		Repository:  f.Repository,
+		Subject:     f.Subject,
+		Summary:     f.Summary,
+		Url:         f.URL,
+		Fingerprint: f.Fingerprint(),
+		Account:     f.Account,
+		Controls:    f.Controls,
+	}
+}
+
+// toProtoTime converts an optional time to a timestamp
+func toProtoTime(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,422 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/findings"
+)
+
+// Status of a scan run
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+// queueSize is the number of scans that can wait for the worker before new requests are rejected
+const queueSize = 16
+
+// ScanRequest scopes an on-demand scan
+// Empty lists run all enabled monitors against their configured targets
+type ScanRequest struct {
+	Monitors     []string `json:"monitors,omitempty"`
+	Repositories []string `json:"repositories,omitempty"`
+}
+
+// ScanResult is the outcome of a scan
+type ScanResult struct {
+	Report   string             `json:"report"`   // Markdown report, as sent to Slack
+	Findings []findings.Finding `json:"findings"` // Findings of all monitors that ran
+	Failed   []string           `json:"failed"`   // Monitors that encountered processing errors
+
+	// Monitors not run because their share of the hourly API calls is used up
+	Deferred []string `json:"deferred,omitempty"`
+}
+
+// ScanFunc runs a scan
+type ScanFunc func(ctx context.Context, req ScanRequest) (*ScanResult, error)
+
+// Run is a scan triggered through the API
+type Run struct {
+	ID         string      `json:"id"`
+	Status     string      `json:"status"`
+	Request    ScanRequest `json:"request"`
+	CreatedAt  time.Time   `json:"created_at"`
+	StartedAt  *time.Time  `json:"started_at,omitempty"`
+	FinishedAt *time.Time  `json:"finished_at,omitempty"`
+	Result     *ScanResult `json:"result,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// Options configures a Server
+type Options struct {
+	// Bearer token required for API requests. Empty disables authentication
+	AuthToken string
+
+	// Monitors that can be requested by key. Requests naming other monitors are rejected
+	Monitors []string
+
+	// Reports whether a repository can be scanned, e.g. whether it is a target of the enabled monitors
+	// Requests naming other repositories are rejected. Nil allows any repository
+	InScope func(repository string) bool
+
+	// Number of finished runs kept for polling. Older runs are forgotten
+	MaxRuns int
+
+	// Slack slash command settings. Nil disables the Slack endpoint
+	Slack *SlackOptions
+
+	// GitHub webhook receiver settings. Nil disables the webhook endpoint
+	GitHubWebhook *GitHubWebhookOptions
+
+	// Writes the metrics of the monitoring in the Prometheus text format. Nil disables the metrics endpoint
+	Metrics func(w io.Writer) error
+
+	// Reports whether this replica is the elected leader, the only one running scans. Nil runs scans on every replica
+	IsLeader func() bool
+}
+
+// Server queues on-demand scans and serves their status
+// Scans are run one at a time in the order they were requested
+type Server struct {
+	scan    ScanFunc
+	options Options
+	queue   chan string
+
+	mu       sync.Mutex
+	runs     map[string]*Run
+	done     map[string]chan struct{} // Closed when the run has finished
+	finished []string                 // IDs of finished runs, oldest first
+
+	webhookHandlers map[string]WebhookHandler // Handlers of GitHub webhook deliveries, by event type
+	deliveries      map[string]time.Time      // Webhook deliveries handled, by ID, when they are not kept in the state
+}
+
+// New creates a server that runs scans with the given function
+func New(scan ScanFunc, options Options) *Server {
+	return &Server{
+		scan:    scan,
+		options: options,
+		queue:   make(chan string, queueSize),
+		runs:    make(map[string]*Run),
+		done:    make(map[string]chan struct{}),
+
+		webhookHandlers: make(map[string]WebhookHandler),
+		deliveries:      make(map[string]time.Time),
+	}
+}
+
+// Start processes queued scans until the context is cancelled
+func (s *Server) Start(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-s.queue:
+			s.execute(ctx, id)
+		}
+	}
+}
+
+// Submit validates a scan request and queues it
+func (s *Server) Submit(req ScanRequest) (*Run, error) {
+	if err := s.validate(req); err != nil {
+		return nil, err
+	}
+	if !s.leading() {
+		return nil, errNotLeader
+	}
+
+	id, err := newRunID()
+	if err != nil {
+		return nil, fmt.Errorf("error generating run ID: %v", err)
+	}
+
+	run := &Run{
+		ID:        id,
+		Status:    StatusQueued,
+		Request:   req,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case s.queue <- id:
+		s.runs[id] = run
+		s.done[id] = make(chan struct{})
+	default:
+		return nil, errQueueFull
+	}
+
+	copied := *run
+	return &copied, nil
+}
+
+// Get returns a snapshot of a run
+func (s *Server) Get(id string) (*Run, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run, ok := s.runs[id]
+	if !ok {
+		return nil, false
+	}
+
+	copied := *run
+	return &copied, true
+}
+
+// Wait blocks until a run has finished or the context is cancelled, and returns the finished run
+func (s *Server) Wait(ctx context.Context, id string) (*Run, error) {
+	s.mu.Lock()
+	done, ok := s.done[id]
+	s.mu.Unlock()
+	if !ok {
+		return nil, errRunNotFound
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-done:
+	}
+
+	run, ok := s.Get(id)
+	if !ok {
+		return nil, errRunNotFound
+	}
+	return run, nil
+}
+
+// Handler returns the HTTP handler of the API
+func (s *Server) Handler() http.Handler {
+	api := http.NewServeMux()
+	api.HandleFunc("POST /api/v1/scan", s.handleScan)
+	api.HandleFunc("GET /api/v1/scans/{id}", s.handleGetRun)
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/", s.authenticate(api))
+	if s.options.Metrics != nil {
+		mux.Handle("GET /metrics", s.authenticate(http.HandlerFunc(s.handleMetrics)))
+	}
+	// Slack requests are verified with the signing secret instead of the API token
+	if s.options.Slack != nil {
+		mux.HandleFunc("POST /slack/commands", s.handleSlackCommand)
+		mux.HandleFunc("POST /slack/actions", s.handleSlackAction)
+	}
+	// GitHub webhook deliveries are verified with the webhook secret
+	if s.options.GitHubWebhook != nil {
+		mux.HandleFunc("POST /github/webhook", s.handleGitHubWebhook)
+	}
+	// Readiness probe of the replicas, so load balancers only route requests to the leader
+	if s.options.IsLeader != nil {
+		mux.HandleFunc("GET /leader", s.handleLeader)
+	}
+	return mux
+}
+
+// errQueueFull is returned when too many scans are waiting
+var errQueueFull = fmt.Errorf("too many scans are queued, try again later")
+
+// errRunNotFound is returned for unknown or forgotten runs
+var errRunNotFound = fmt.Errorf("scan not found")
+
+// errNotLeader is returned by replicas that are not the leader, which leave scans to the leader
+var errNotLeader = fmt.Errorf("this replica is not the leader, send the request to the leader")
+
+// leading reports whether this replica runs scans
+func (s *Server) leading() bool {
+	return s.options.IsLeader == nil || s.options.IsLeader()
+}
+
+// validate ensures a scan request only names known monitors and well-formed repositories in scope
+func (s *Server) validate(req ScanRequest) error {
+	known := make(map[string]bool)
+	for _, m := range s.options.Monitors {
+		known[m] = true
+	}
+
+	for _, m := range req.Monitors {
+		if !known[m] {
+			return fmt.Errorf("unknown or disabled monitor: %s. Available monitors: %s", m, strings.Join(s.options.Monitors, ", "))
+		}
+	}
+
+	for _, repo := range req.Repositories {
+		parts := strings.Split(repo, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("invalid repository format: %s, expected 'owner/repo'", repo)
+		}
+		if s.options.InScope != nil && !s.options.InScope(repo) {
+			return fmt.Errorf("repository %s is not monitored by this server", repo)
+		}
+	}
+
+	return nil
+}
+
+// execute runs a queued scan and records its outcome
+func (s *Server) execute(ctx context.Context, id string) {
+	s.mu.Lock()
+	run := s.runs[id]
+	started := time.Now().UTC()
+	run.Status = StatusRunning
+	run.StartedAt = &started
+	req := run.Request
+	s.mu.Unlock()
+
+	// A replica that lost its leadership while the scan was queued leaves it to the new leader
+	var result *ScanResult
+	var err error
+	if s.leading() {
+		log.Printf("Starting scan %s", id)
+		result, err = s.scan(context.WithValue(ctx, runIDKey{}, id), req)
+	} else {
+		err = errNotLeader
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	finished := time.Now().UTC()
+	run.FinishedAt = &finished
+	if err != nil {
+		log.Printf("Scan %s failed: %v", id, err)
+		run.Status = StatusFailed
+		run.Error = err.Error()
+	} else {
+		log.Printf("Scan %s completed with %d findings", id, len(result.Findings))
+		run.Status = StatusCompleted
+		run.Result = result
+	}
+	close(s.done[id])
+
+	// Forget the oldest finished runs
+	s.finished = append(s.finished, id)
+	for s.options.MaxRuns > 0 && len(s.finished) > s.options.MaxRuns {
+		delete(s.runs, s.finished[0])
+		delete(s.done, s.finished[0])
+		s.finished = s.finished[1:]
+	}
+}
+
+// authenticate requires the configured bearer token on every request
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	if s.options.AuthToken == "" {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.options.AuthToken)) != 1 {
+			writeError(w, http.StatusUnauthorized, "invalid or missing bearer token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleMetrics serves the metrics of the monitoring for Prometheus
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	if err := s.options.Metrics(&buf); err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("error writing metrics: %v", err))
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+// handleScan queues a scan
+func (s *Server) handleScan(w http.ResponseWriter, r *http.Request) {
+	var req ScanRequest
+	// An empty body requests a full scan
+	if r.ContentLength != 0 {
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+			return
+		}
+	}
+
+	run, err := s.Submit(req)
+	if err == errQueueFull || err == errNotLeader {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/scans/"+run.ID)
+	writeJSON(w, http.StatusAccepted, run)
+}
+
+// handleLeader answers 200 on the leader and 503 on the other replicas
+func (s *Server) handleLeader(w http.ResponseWriter, r *http.Request) {
+	if !s.leading() {
+		writeError(w, http.StatusServiceUnavailable, errNotLeader.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"leader": true})
+}
+
+// handleGetRun returns the status and results of a run
+func (s *Server) handleGetRun(w http.ResponseWriter, r *http.Request) {
+	run, ok := s.Get(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, errRunNotFound.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, run)
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Error writing response: %v", err)
+	}
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// runIDKey is the context key of the ID of the run a scan executes
+type runIDKey struct{}
+
+// RunID returns the ID of the run the scan of the context executes, empty outside scans
+func RunID(ctx context.Context) string {
+	id, _ := ctx.Value(runIDKey{}).(string)
+	return id
+}
+
+// newRunID returns a random run identifier
+func newRunID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
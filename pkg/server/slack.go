@@ -0,0 +1,499 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/state"
+)
+
+// slackAPIURL is the base URL of the Slack Web API
+const slackAPIURL = "https://slack.com/api"
+
+// slackResponseURLPrefix is where slash command response URLs point to
+const slackResponseURLPrefix = "https://hooks.slack.com/"
+
+// slackMaxRequestAge is how old a signed Slack request may be before it is rejected as a replay
+const slackMaxRequestAge = 5 * time.Minute
+
+// slackMaxTextLength keeps messages below Slack's block text limit
+const slackMaxTextLength = 3000
+
+// slackMaxActionFindings is the number of findings that get triage buttons, keeping messages below Slack's block limit
+const slackMaxActionFindings = 20
+
+// Triage buttons on Slack finding messages
+const (
+	slackActionAcknowledge = "acknowledge"
+	slackActionSuppress    = "suppress_7d"
+)
+
+// slackSuppressPeriod is how long the suppress button suppresses a finding
+const slackSuppressPeriod = 7 * 24 * time.Hour
+
+// SlackOptions configures the Slack slash command
+type SlackOptions struct {
+	// Signing secret of the Slack app, used to verify requests
+	SigningSecret string
+
+	// Bot token used to reply in a thread. Without it, results are sent to the command's response URL
+	BotToken string
+
+	// Base URL of the Slack Web API. Defaults to https://slack.com/api
+	APIURL string
+
+	// Prefix response URLs must have, so requests cannot make the server post elsewhere
+	// Defaults to https://hooks.slack.com/
+	ResponseURLPrefix string
+
+	// State file triage from the Acknowledge and Suppress buttons is recorded in
+	// Empty leaves the buttons out of finding messages
+	StatePath string
+
+	// IDs of the Slack users and channels commands and buttons are accepted from. Empty lists accept any
+	AllowedUsers    []string
+	AllowedChannels []string
+}
+
+// allowed reports whether a Slack user may use the command or buttons in a channel
+// Buttons do not name their channel, so an empty channel only checks the user
+func (o *SlackOptions) allowed(userID, channelID string) bool {
+	if len(o.AllowedUsers) > 0 && !slices.Contains(o.AllowedUsers, userID) {
+		return false
+	}
+	return channelID == "" || len(o.AllowedChannels) == 0 || slices.Contains(o.AllowedChannels, channelID)
+}
+
+// slackMessage is a Slack message payload
+type slackMessage struct {
+	Channel         string       `json:"channel,omitempty"`
+	ThreadTS        string       `json:"thread_ts,omitempty"`
+	ResponseType    string       `json:"response_type,omitempty"`
+	ReplaceOriginal *bool        `json:"replace_original,omitempty"`
+	Text            string       `json:"text"`
+	Blocks          []slackBlock `json:"blocks,omitempty"`
+}
+
+// slackBlock is a Block Kit layout block
+type slackBlock struct {
+	Type     string         `json:"type"`
+	Text     *slackText     `json:"text,omitempty"`
+	Elements []slackElement `json:"elements,omitempty"`
+}
+
+// slackText is a Block Kit text object
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// slackElement is a Block Kit element, such as a button
+type slackElement struct {
+	Type     string     `json:"type"`
+	Text     *slackText `json:"text,omitempty"`
+	ActionID string     `json:"action_id,omitempty"`
+	Value    string     `json:"value,omitempty"`
+	Style    string     `json:"style,omitempty"`
+}
+
+// slackActionPayload is the payload of a block_actions interaction
+type slackActionPayload struct {
+	Type string `json:"type"`
+	User struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"user"`
+	ResponseURL string `json:"response_url"`
+	Actions     []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+}
+
+// slackCommand is a parsed slash command invocation
+type slackCommand struct {
+	Command     string
+	Text        string
+	UserID      string
+	ChannelID   string
+	ResponseURL string
+}
+
+// handleSlackCommand handles slash commands such as "/git-monitor check owner/repo"
+// Slack expects an answer within three seconds, so the scan is queued and the results are posted when it finishes
+func (s *Server) handleSlackCommand(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := verifySlackSignature(s.options.Slack.SigningSecret, r.Header, body, time.Now()); err != nil {
+		log.Printf("Rejected Slack request: %v", err)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	cmd := slackCommand{
+		Command:     form.Get("command"),
+		Text:        strings.TrimSpace(form.Get("text")),
+		UserID:      form.Get("user_id"),
+		ChannelID:   form.Get("channel_id"),
+		ResponseURL: form.Get("response_url"),
+	}
+
+	if !s.options.Slack.allowed(cmd.UserID, cmd.ChannelID) {
+		log.Printf("Rejected Slack command of user %s in channel %s, not allowed", cmd.UserID, cmd.ChannelID)
+		writeJSON(w, http.StatusOK, slackMessage{
+			ResponseType: "ephemeral",
+			Text:         "You are not allowed to request scans here",
+		})
+		return
+	}
+
+	fields := strings.Fields(cmd.Text)
+	if len(fields) < 2 || fields[0] != "check" {
+		writeJSON(w, http.StatusOK, slackMessage{
+			ResponseType: "ephemeral",
+			Text:         fmt.Sprintf("Usage: `%s check owner/repo [owner/repo ...]`", cmd.Command),
+		})
+		return
+	}
+
+	req := ScanRequest{Repositories: fields[1:]}
+	run, err := s.Submit(req)
+	if err != nil {
+		writeJSON(w, http.StatusOK, slackMessage{
+			ResponseType: "ephemeral",
+			Text:         fmt.Sprintf("Could not start scan: %v", err),
+		})
+		return
+	}
+
+	log.Printf("Slack user %s requested scan %s of %s", cmd.UserID, run.ID, strings.Join(req.Repositories, ", "))
+
+	// Start a thread for the results when a bot token is available
+	threadTS := ""
+	if s.options.Slack.BotToken != "" {
+		threadTS, err = s.postSlackMessage(r.Context(), slackMessage{
+			Channel: cmd.ChannelID,
+			Text:    fmt.Sprintf("<@%s> requested a scan of %s (run `%s`)", cmd.UserID, strings.Join(req.Repositories, ", "), run.ID),
+		})
+		if err != nil {
+			log.Printf("Error starting Slack thread, replying to the command instead: %v", err)
+		}
+	}
+
+	go s.replyToSlackCommand(cmd, run.ID, threadTS)
+
+	if threadTS != "" {
+		// The thread announces the scan, an empty response keeps the channel quiet
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, slackMessage{
+		ResponseType: "in_channel",
+		Text:         fmt.Sprintf("Scanning %s (run `%s`), results will follow", strings.Join(req.Repositories, ", "), run.ID),
+	})
+}
+
+// replyToSlackCommand waits for a scan requested through Slack and posts its results
+func (s *Server) replyToSlackCommand(cmd slackCommand, id, threadTS string) {
+	ctx := context.Background()
+
+	var text string
+	run, err := s.Wait(ctx, id)
+	switch {
+	case err != nil:
+		text = fmt.Sprintf("Scan `%s` could not be completed: %v", id, err)
+	case run.Status == StatusFailed:
+		text = fmt.Sprintf("Scan `%s` failed: %s", id, run.Error)
+	default:
+		text = formatSlackReport(run.Result)
+	}
+
+	msg := slackMessage{Text: text}
+	if run != nil && run.Result != nil && s.options.Slack.StatePath != "" {
+		msg.Blocks = slackFindingBlocks(text, run.Result.Findings)
+	}
+
+	if threadTS != "" {
+		msg.Channel = cmd.ChannelID
+		msg.ThreadTS = threadTS
+		_, err = s.postSlackMessage(ctx, msg)
+	} else {
+		msg.ResponseType = "in_channel"
+		err = s.respondToSlack(ctx, cmd.ResponseURL, msg)
+	}
+	if err != nil {
+		log.Printf("Error posting results of scan %s to Slack: %v", id, err)
+	}
+}
+
+// formatSlackReport formats scan results as a Slack message
+func formatSlackReport(result *ScanResult) string {
+	summary := "Git Monitoring Results"
+	for _, line := range strings.Split(result.Report, "\n") {
+		if strings.HasPrefix(line, "## ") {
+			summary = strings.TrimPrefix(line, "## ")
+			break
+		}
+	}
+
+	text := fmt.Sprintf("*%s*\n\n```\n%s\n```", summary, result.Report)
+	if len(result.Failed) > 0 {
+		text = fmt.Sprintf(":x: Monitors with errors: %s\n%s", strings.Join(result.Failed, ", "), text)
+	}
+
+	if len(text) > slackMaxTextLength {
+		text = text[:slackMaxTextLength-50] + "...\n```\n(Content truncated due to size limits)"
+	}
+
+	return text
+}
+
+// slackFindingBlocks lays out a report followed by triage buttons for each finding
+func slackFindingBlocks(report string, list []findings.Finding) []slackBlock {
+	blocks := []slackBlock{
+		{Type: "section", Text: &slackText{Type: "mrkdwn", Text: report}},
+	}
+
+	for i, f := range list {
+		if i == slackMaxActionFindings {
+			blocks = append(blocks, slackBlock{
+				Type:     "context",
+				Elements: []slackElement{{Type: "mrkdwn", Text: &slackText{Type: "mrkdwn", Text: fmt.Sprintf("%d more findings can be triaged in the next report", len(list)-i)}}},
+			})
+			break
+		}
+
+		blocks = append(blocks,
+			slackBlock{
+				Type: "section",
+				Text: &slackText{Type: "mrkdwn", Text: fmt.Sprintf("*%s* %s: %s", f.Repository, f.Subject, f.Summary)},
+			},
+			slackBlock{
+				Type: "actions",
+				Elements: []slackElement{
+					{Type: "button", Text: &slackText{Type: "plain_text", Text: "Acknowledge"}, ActionID: slackActionAcknowledge, Value: f.Fingerprint(), Style: "primary"},
+					{Type: "button", Text: &slackText{Type: "plain_text", Text: "Suppress 7 days"}, ActionID: slackActionSuppress, Value: f.Fingerprint()},
+				},
+			},
+		)
+	}
+
+	return blocks
+}
+
+// handleSlackAction handles the Acknowledge and Suppress buttons on finding messages
+// The triage is recorded in the state file and announced in the channel
+func (s *Server) handleSlackAction(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := verifySlackSignature(s.options.Slack.SigningSecret, r.Header, body, time.Now()); err != nil {
+		log.Printf("Rejected Slack action: %v", err)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var payload slackActionPayload
+	if err := json.Unmarshal([]byte(form.Get("payload")), &payload); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	if payload.Type != "block_actions" || len(payload.Actions) == 0 {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if s.options.Slack.StatePath == "" {
+		http.Error(w, "state is not enabled", http.StatusNotFound)
+		return
+	}
+
+	if !s.options.Slack.allowed(payload.User.ID, "") {
+		log.Printf("Rejected Slack action of user %s, not allowed", payload.User.ID)
+		http.Error(w, "not allowed", http.StatusForbidden)
+		return
+	}
+
+	action := payload.Actions[0]
+	now := time.Now().UTC()
+	ack := state.Acknowledgement{
+		Action: state.ActionAcknowledged,
+		By:     payload.User.Username,
+		At:     now,
+	}
+	if ack.By == "" {
+		ack.By = payload.User.ID
+	}
+
+	var text string
+	switch action.ActionID {
+	case slackActionAcknowledge:
+		text = fmt.Sprintf(":white_check_mark: <@%s> acknowledged `%s`", payload.User.ID, action.Value)
+	case slackActionSuppress:
+		until := now.Add(slackSuppressPeriod)
+		ack.Action = state.ActionSuppressed
+		ack.Until = &until
+		text = fmt.Sprintf(":zzz: <@%s> suppressed `%s` until %s", payload.User.ID, action.Value, until.Format("2006-01-02 15:04 MST"))
+	default:
+		http.Error(w, "unknown action", http.StatusBadRequest)
+		return
+	}
+
+	err = state.Update(s.options.Slack.StatePath, func(st *state.State) error {
+		return st.Acknowledge(action.Value, ack)
+	})
+	if err != nil {
+		log.Printf("Error recording Slack triage of %s: %v", action.Value, err)
+		text = fmt.Sprintf("Could not record the triage of `%s`: %v", action.Value, err)
+	} else {
+		log.Printf("Slack user %s %s %s", ack.By, ack.Action, action.Value)
+	}
+
+	replaceOriginal := false
+	if err := s.respondToSlack(r.Context(), payload.ResponseURL, slackMessage{
+		ResponseType:    "in_channel",
+		ReplaceOriginal: &replaceOriginal,
+		Text:            text,
+	}); err != nil {
+		log.Printf("Error confirming Slack triage: %v", err)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// verifySlackSignature checks the X-Slack-Signature header of a request
+// See https://api.slack.com/authentication/verifying-requests-from-slack
+func verifySlackSignature(secret string, header http.Header, body []byte, now time.Time) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid request timestamp: %q", timestamp)
+	}
+
+	age := now.Sub(time.Unix(seconds, 0))
+	if age > slackMaxRequestAge || age < -slackMaxRequestAge {
+		return fmt.Errorf("request timestamp is too far from the current time")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature"))) {
+		return fmt.Errorf("signature mismatch")
+	}
+
+	return nil
+}
+
+// postSlackMessage posts a message with chat.postMessage and returns its timestamp
+func (s *Server) postSlackMessage(ctx context.Context, msg slackMessage) (string, error) {
+	apiURL := s.options.Slack.APIURL
+	if apiURL == "" {
+		apiURL = slackAPIURL
+	}
+
+	resp, err := postJSON(ctx, apiURL+"/chat.postMessage", s.options.Slack.BotToken, msg)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+		TS    string `json:"ts"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return "", fmt.Errorf("error decoding Slack response: %v", err)
+	}
+	if !result.OK {
+		return "", fmt.Errorf("slack API error: %s", result.Error)
+	}
+
+	return result.TS, nil
+}
+
+// respondToSlack sends a message to the response URL of a slash command
+func (s *Server) respondToSlack(ctx context.Context, responseURL string, msg slackMessage) error {
+	prefix := s.options.Slack.ResponseURLPrefix
+	if prefix == "" {
+		prefix = slackResponseURLPrefix
+	}
+
+	if !strings.HasPrefix(responseURL, prefix) {
+		return fmt.Errorf("invalid response URL: %q", responseURL)
+	}
+
+	_, err := postJSON(ctx, responseURL, "", msg)
+	return err
+}
+
+// postJSON posts a JSON payload and returns the response body
+func postJSON(ctx context.Context, url, token string, payload interface{}) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding payload: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return body, nil
+}
@@ -0,0 +1,185 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/anupsv/git-monitoring/pkg/api/gitmonitorv1"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/server"
+)
+
+// newGRPCClient starts a gRPC server for srv on an in-memory listener and returns a client for it
+func newGRPCClient(t *testing.T, srv *server.Server, config *gitmonitorv1.GetConfigResponse) gitmonitorv1.GitMonitorServiceClient {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go srv.Start(ctx)
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := srv.NewGRPCServer(config)
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		grpcServer.Stop()
+		cancel()
+	})
+
+	return gitmonitorv1.NewGitMonitorServiceClient(conn)
+}
+
+func testScan(ctx context.Context, req server.ScanRequest) (*server.ScanResult, error) {
+	if len(req.Repositories) > 0 && req.Repositories[0] == "owner/broken" {
+		return nil, errors.New("GitHub API unavailable")
+	}
+
+	return &server.ScanResult{
+		Report: "## report",
+		Findings: []findings.Finding{
+			{Monitor: "pr_checker", Repository: "owner/repo", Subject: "#1", Summary: "Merged without approval"},
+			{Monitor: "pr_checker", Repository: "owner/repo", Subject: "#2", Summary: "Merged without approval"},
+		},
+	}, nil
+}
+
+func TestGRPCRunScan(t *testing.T) {
+	client := newGRPCClient(t, server.New(testScan, server.Options{Monitors: []string{"pr_checker"}}), nil)
+
+	run, err := client.RunScan(context.Background(), &gitmonitorv1.RunScanRequest{
+		Scope: &gitmonitorv1.ScanScope{Monitors: []string{"pr_checker"}},
+	})
+	if err != nil {
+		t.Fatalf("RunScan failed: %v", err)
+	}
+
+	if run.GetStatus() != server.StatusCompleted {
+		t.Errorf("Expected status %s, got %s", server.StatusCompleted, run.GetStatus())
+	}
+	if len(run.GetFindings()) != 2 || run.GetReport() != "## report" {
+		t.Errorf("Expected the scan results, got %v", run)
+	}
+	if run.GetFinishedAt() == nil {
+		t.Error("Expected the finish time to be set")
+	}
+
+	// Failed scans are reported in the run
+	run, err = client.RunScan(context.Background(), &gitmonitorv1.RunScanRequest{
+		Scope: &gitmonitorv1.ScanScope{Repositories: []string{"owner/broken"}},
+	})
+	if err != nil {
+		t.Fatalf("RunScan failed: %v", err)
+	}
+	if run.GetStatus() != server.StatusFailed || run.GetError() == "" {
+		t.Errorf("Expected a failed run with an error, got %v", run)
+	}
+
+	// Invalid scopes are rejected
+	_, err = client.RunScan(context.Background(), &gitmonitorv1.RunScanRequest{
+		Scope: &gitmonitorv1.ScanScope{Monitors: []string{"unknown"}},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an unknown monitor, got %v", err)
+	}
+}
+
+func TestGRPCStreamFindings(t *testing.T) {
+	client := newGRPCClient(t, server.New(testScan, server.Options{}), nil)
+
+	stream, err := client.StreamFindings(context.Background(), &gitmonitorv1.StreamFindingsRequest{
+		Source: &gitmonitorv1.StreamFindingsRequest_Scope{Scope: &gitmonitorv1.ScanScope{}},
+	})
+	if err != nil {
+		t.Fatalf("StreamFindings failed: %v", err)
+	}
+
+	var received []*gitmonitorv1.Finding
+	for {
+		f, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Receiving findings failed: %v", err)
+		}
+		received = append(received, f)
+	}
+
+	if len(received) != 2 || received[1].GetSubject() != "#2" {
+		t.Errorf("Expected 2 findings, got %v", received)
+	}
+
+	// Unknown runs are reported as not found
+	stream, err = client.StreamFindings(context.Background(), &gitmonitorv1.StreamFindingsRequest{
+		Source: &gitmonitorv1.StreamFindingsRequest_RunId{RunId: "missing"},
+	})
+	if err != nil {
+		t.Fatalf("StreamFindings failed: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for an unknown run, got %v", err)
+	}
+}
+
+func TestGRPCGetConfig(t *testing.T) {
+	config := &gitmonitorv1.GetConfigResponse{
+		Timezone: "Europe/Berlin",
+		Monitors: []*gitmonitorv1.MonitorConfig{
+			{Key: "pr_checker", Enabled: true, Repositories: []string{"owner/repo"}},
+		},
+	}
+	client := newGRPCClient(t, server.New(testScan, server.Options{}), config)
+
+	resp, err := client.GetConfig(context.Background(), &gitmonitorv1.GetConfigRequest{})
+	if err != nil {
+		t.Fatalf("GetConfig failed: %v", err)
+	}
+
+	if resp.GetTimezone() != "Europe/Berlin" || len(resp.GetMonitors()) != 1 || resp.GetMonitors()[0].GetKey() != "pr_checker" {
+		t.Errorf("Expected the configured summary, got %v", resp)
+	}
+}
+
+func TestGRPCAuthentication(t *testing.T) {
+	client := newGRPCClient(t, server.New(testScan, server.Options{AuthToken: "secret"}), &gitmonitorv1.GetConfigResponse{})
+
+	_, err := client.GetConfig(context.Background(), &gitmonitorv1.GetConfigRequest{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated without a token, got %v", err)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+	if _, err := client.GetConfig(ctx, &gitmonitorv1.GetConfigRequest{}); err != nil {
+		t.Errorf("Expected GetConfig to succeed with the token, got %v", err)
+	}
+
+	stream, err := client.StreamFindings(context.Background(), &gitmonitorv1.StreamFindingsRequest{})
+	if err != nil {
+		t.Fatalf("StreamFindings failed: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated for a stream without a token, got %v", err)
+	}
+}
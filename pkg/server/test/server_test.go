@@ -0,0 +1,260 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/server"
+)
+
+// doRequest sends a request to the handler and decodes the JSON response into v
+func doRequest(t *testing.T, handler http.Handler, method, path, body, token string, v interface{}) int {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if v != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("Failed to decode response %q: %v", rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+// waitForRun polls a run until it has finished
+func waitForRun(t *testing.T, handler http.Handler, id string) server.Run {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		var run server.Run
+		if code := doRequest(t, handler, http.MethodGet, "/api/v1/scans/"+id, "", "", &run); code != http.StatusOK {
+			t.Fatalf("Expected status 200 polling run, got %d", code)
+		}
+		if run.Status == server.StatusCompleted || run.Status == server.StatusFailed {
+			return run
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("Run %s did not finish", id)
+	return server.Run{}
+}
+
+func TestScanLifecycle(t *testing.T) {
+	var received server.ScanRequest
+	scan := func(ctx context.Context, req server.ScanRequest) (*server.ScanResult, error) {
+		received = req
+		return &server.ScanResult{
+			Report: "## report",
+			Findings: []findings.Finding{
+				{Monitor: "pr_checker", Repository: "owner/repo", Subject: "#1", Summary: "Merged without approval"},
+			},
+		}, nil
+	}
+
+	srv := server.New(scan, server.Options{Monitors: []string{"pr_checker", "rulesets"}})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go srv.Start(ctx)
+	handler := srv.Handler()
+
+	var run server.Run
+	body := `{"monitors": ["pr_checker"], "repositories": ["owner/repo"]}`
+	if code := doRequest(t, handler, http.MethodPost, "/api/v1/scan", body, "", &run); code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d", code)
+	}
+	if run.ID == "" || run.Status != server.StatusQueued {
+		t.Fatalf("Expected a queued run with an ID, got %+v", run)
+	}
+
+	finished := waitForRun(t, handler, run.ID)
+	if finished.Status != server.StatusCompleted {
+		t.Fatalf("Expected run to complete, got %s (%s)", finished.Status, finished.Error)
+	}
+	if finished.Result == nil || len(finished.Result.Findings) != 1 {
+		t.Fatalf("Expected 1 finding in the result, got %+v", finished.Result)
+	}
+	if finished.StartedAt == nil || finished.FinishedAt == nil {
+		t.Error("Expected start and finish times to be recorded")
+	}
+	if len(received.Monitors) != 1 || received.Monitors[0] != "pr_checker" ||
+		len(received.Repositories) != 1 || received.Repositories[0] != "owner/repo" {
+		t.Errorf("Expected the scan to be scoped to the request, got %+v", received)
+	}
+}
+
+func TestScanFailure(t *testing.T) {
+	scan := func(ctx context.Context, req server.ScanRequest) (*server.ScanResult, error) {
+		return nil, context.DeadlineExceeded
+	}
+
+	srv := server.New(scan, server.Options{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go srv.Start(ctx)
+	handler := srv.Handler()
+
+	// An empty body requests a full scan
+	var run server.Run
+	if code := doRequest(t, handler, http.MethodPost, "/api/v1/scan", "", "", &run); code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d", code)
+	}
+
+	finished := waitForRun(t, handler, run.ID)
+	if finished.Status != server.StatusFailed || finished.Error == "" {
+		t.Errorf("Expected a failed run with an error, got %+v", finished)
+	}
+}
+
+func TestScanValidation(t *testing.T) {
+	srv := server.New(func(ctx context.Context, req server.ScanRequest) (*server.ScanResult, error) {
+		return &server.ScanResult{}, nil
+	}, server.Options{
+		Monitors: []string{"pr_checker"},
+		InScope:  func(repository string) bool { return strings.HasPrefix(repository, "owner/") },
+	})
+	handler := srv.Handler()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"Unknown monitor", `{"monitors": ["unknown"]}`},
+		{"Invalid repository", `{"repositories": ["invalid-repo"]}`},
+		{"Repository out of scope", `{"repositories": ["owner/repo", "stranger/repo"]}`},
+		{"Unknown field", `{"repos": ["owner/repo"]}`},
+		{"Malformed body", `{`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var resp map[string]string
+			if code := doRequest(t, handler, http.MethodPost, "/api/v1/scan", tc.body, "", &resp); code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d", code)
+			}
+			if resp["error"] == "" {
+				t.Error("Expected an error message")
+			}
+		})
+	}
+
+	var resp map[string]string
+	if code := doRequest(t, handler, http.MethodGet, "/api/v1/scans/missing", "", "", &resp); code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown run, got %d", code)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	srv := server.New(func(ctx context.Context, req server.ScanRequest) (*server.ScanResult, error) {
+		return &server.ScanResult{}, nil
+	}, server.Options{AuthToken: "secret"})
+	handler := srv.Handler()
+
+	if code := doRequest(t, handler, http.MethodPost, "/api/v1/scan", "", "", nil); code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 without a token, got %d", code)
+	}
+	if code := doRequest(t, handler, http.MethodPost, "/api/v1/scan", "", "wrong", nil); code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 with a wrong token, got %d", code)
+	}
+	if code := doRequest(t, handler, http.MethodPost, "/api/v1/scan", "", "secret", nil); code != http.StatusAccepted {
+		t.Errorf("Expected status 202 with the token, got %d", code)
+	}
+}
+
+func TestMaxRuns(t *testing.T) {
+	srv := server.New(func(ctx context.Context, req server.ScanRequest) (*server.ScanResult, error) {
+		return &server.ScanResult{}, nil
+	}, server.Options{MaxRuns: 1})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go srv.Start(ctx)
+	handler := srv.Handler()
+
+	var first, second server.Run
+	doRequest(t, handler, http.MethodPost, "/api/v1/scan", "", "", &first)
+	waitForRun(t, handler, first.ID)
+	doRequest(t, handler, http.MethodPost, "/api/v1/scan", "", "", &second)
+	waitForRun(t, handler, second.ID)
+
+	if _, ok := srv.Get(first.ID); ok {
+		t.Error("Expected the oldest run to be forgotten")
+	}
+	if _, ok := srv.Get(second.ID); !ok {
+		t.Error("Expected the latest run to be kept")
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	scan := func(ctx context.Context, req server.ScanRequest) (*server.ScanResult, error) {
+		return &server.ScanResult{}, nil
+	}
+
+	// Without metrics the endpoint is not served
+	if code := doRequest(t, server.New(scan, server.Options{}).Handler(), http.MethodGet, "/metrics", "", "", nil); code != http.StatusNotFound {
+		t.Errorf("Expected status 404 without metrics, got %d", code)
+	}
+
+	handler := server.New(scan, server.Options{AuthToken: "secret", Metrics: func(w io.Writer) error {
+		_, err := io.WriteString(w, "git_monitor_last_run_timestamp_seconds 1\n")
+		return err
+	}}).Handler()
+
+	if code := doRequest(t, handler, http.MethodGet, "/metrics", "", "", nil); code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 without a token, got %d", code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || rec.Body.String() != "git_monitor_last_run_timestamp_seconds 1\n" {
+		t.Errorf("Unexpected metrics response %d: %q", rec.Code, rec.Body.String())
+	}
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+		t.Errorf("Expected a text content type, got %q", rec.Header().Get("Content-Type"))
+	}
+}
+
+func TestFollowerRejectsScans(t *testing.T) {
+	var leading atomic.Bool
+	srv := server.New(func(ctx context.Context, req server.ScanRequest) (*server.ScanResult, error) {
+		return &server.ScanResult{}, nil
+	}, server.Options{IsLeader: leading.Load})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go srv.Start(ctx)
+	handler := srv.Handler()
+
+	if code := doRequest(t, handler, http.MethodPost, "/api/v1/scan", "", "", nil); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503 for a scan on a follower, got %d", code)
+	}
+	if code := doRequest(t, handler, http.MethodGet, "/leader", "", "", nil); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected the leader probe of a follower to fail, got %d", code)
+	}
+
+	leading.Store(true)
+	if code := doRequest(t, handler, http.MethodGet, "/leader", "", "", nil); code != http.StatusOK {
+		t.Errorf("Expected the leader probe of the leader to succeed, got %d", code)
+	}
+	var run server.Run
+	if code := doRequest(t, handler, http.MethodPost, "/api/v1/scan", "", "", &run); code != http.StatusAccepted {
+		t.Fatalf("Expected status 202 for a scan on the leader, got %d", code)
+	}
+	if run = waitForRun(t, handler, run.ID); run.Status != server.StatusCompleted {
+		t.Errorf("Expected the scan to complete on the leader, got %+v", run)
+	}
+}
@@ -0,0 +1,302 @@
+package test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/server"
+	"github.com/anupsv/git-monitoring/pkg/state"
+)
+
+const signingSecret = "slack-signing-secret"
+
+// fakeSlack records the messages posted to the Slack API and response URLs
+type fakeSlack struct {
+	mu       sync.Mutex
+	messages []map[string]interface{}
+	paths    []string
+	received chan struct{}
+}
+
+func newFakeSlack(t *testing.T) (*fakeSlack, *httptest.Server) {
+	fake := &fakeSlack{received: make(chan struct{}, 10)}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var msg map[string]interface{}
+		_ = json.Unmarshal(body, &msg)
+
+		fake.mu.Lock()
+		fake.messages = append(fake.messages, msg)
+		fake.paths = append(fake.paths, r.URL.Path)
+		fake.mu.Unlock()
+
+		_, _ = w.Write([]byte(`{"ok": true, "ts": "1700000000.000100"}`))
+		fake.received <- struct{}{}
+	}))
+	t.Cleanup(ts.Close)
+	return fake, ts
+}
+
+// wait waits for n messages and returns all messages received so far
+func (f *fakeSlack) wait(t *testing.T, n int) ([]map[string]interface{}, []string) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-f.received:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for Slack message %d", i+1)
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]map[string]interface{}(nil), f.messages...), append([]string(nil), f.paths...)
+}
+
+// slashCommand builds a signed slash command request
+func slashCommand(t *testing.T, text, responseURL string, timestamp time.Time, secret string) *http.Request {
+	t.Helper()
+
+	return signedSlackRequest("/slack/commands", url.Values{
+		"command":      {"/git-monitor"},
+		"text":         {text},
+		"user_id":      {"U123"},
+		"channel_id":   {"C456"},
+		"response_url": {responseURL},
+	}, timestamp, secret)
+}
+
+// signedSlackRequest builds a form request signed like Slack does
+func signedSlackRequest(path string, form url.Values, timestamp time.Time, secret string) *http.Request {
+	body := form.Encode()
+
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + ts + ":" + body))
+
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", ts)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+// newSlackServer starts a server whose scans report one finding per requested repository
+func newSlackServer(t *testing.T, slack *server.SlackOptions) http.Handler {
+	scan := func(ctx context.Context, req server.ScanRequest) (*server.ScanResult, error) {
+		result := &server.ScanResult{Report: "## :warning: Unapproved Pull Requests\n\nowner/repo #1"}
+		for _, repo := range req.Repositories {
+			result.Findings = append(result.Findings, findings.Finding{Monitor: "pr_checker", Repository: repo, Subject: "#1"})
+		}
+		return result, nil
+	}
+
+	srv := server.New(scan, server.Options{AuthToken: "api-token", Slack: slack})
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go srv.Start(ctx)
+	return srv.Handler()
+}
+
+func TestSlackCommandRepliesInThread(t *testing.T) {
+	fake, slackAPI := newFakeSlack(t)
+	handler := newSlackServer(t, &server.SlackOptions{
+		SigningSecret: signingSecret,
+		BotToken:      "xoxb-token",
+		APIURL:        slackAPI.URL,
+		StatePath:     filepath.Join(t.TempDir(), "state.json"),
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, slashCommand(t, "check owner/repo", slackAPI.URL+"/response", time.Now(), signingSecret))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	messages, paths := fake.wait(t, 2)
+	if paths[0] != "/chat.postMessage" || messages[0]["channel"] != "C456" || messages[0]["thread_ts"] != nil {
+		t.Errorf("Expected the scan to be announced in the channel, got %s %v", paths[0], messages[0])
+	}
+	if !strings.Contains(fmt.Sprint(messages[0]["text"]), "owner/repo") {
+		t.Errorf("Expected the announcement to name the repository, got %q", messages[0]["text"])
+	}
+	if paths[1] != "/chat.postMessage" || messages[1]["thread_ts"] != "1700000000.000100" {
+		t.Errorf("Expected the results to be posted in the thread, got %s %v", paths[1], messages[1])
+	}
+	if !strings.Contains(fmt.Sprint(messages[1]["text"]), "Unapproved Pull Requests") {
+		t.Errorf("Expected the results in the reply, got %q", messages[1]["text"])
+	}
+
+	// Each finding gets triage buttons when state is enabled
+	blocks, _ := messages[1]["blocks"].([]interface{})
+	if len(blocks) != 3 || !strings.Contains(fmt.Sprint(blocks[2]), "suppress_7d") {
+		t.Errorf("Expected the report and the finding's buttons, got %v", blocks)
+	}
+}
+
+func TestSlackActions(t *testing.T) {
+	fake, slackAPI := newFakeSlack(t)
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	handler := newSlackServer(t, &server.SlackOptions{
+		SigningSecret:     signingSecret,
+		ResponseURLPrefix: slackAPI.URL,
+		StatePath:         statePath,
+	})
+
+	finding := findings.Finding{Monitor: "pr_checker", Repository: "owner/repo", Subject: "#1"}
+	action := func(actionID string) *http.Request {
+		payload := fmt.Sprintf(`{"type": "block_actions", "user": {"id": "U123", "username": "alice"}, "response_url": %q,
+			"actions": [{"action_id": %q, "value": %q}]}`, slackAPI.URL+"/response", actionID, finding.Fingerprint())
+		return signedSlackRequest("/slack/actions", url.Values{"payload": {payload}}, time.Now(), signingSecret)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, action("suppress_7d"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	s, err := state.Load(statePath)
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+	ack := s.Acknowledgements[finding.Fingerprint()]
+	if ack.Action != state.ActionSuppressed || ack.By != "alice" || ack.Until == nil {
+		t.Errorf("Expected a 7 day suppression by alice, got %+v", ack)
+	}
+	if !s.Suppressed(finding.Fingerprint(), time.Now().Add(6*24*time.Hour)) || s.Suppressed(finding.Fingerprint(), time.Now().Add(8*24*time.Hour)) {
+		t.Error("Expected the suppression to last 7 days")
+	}
+
+	messages, _ := fake.wait(t, 1)
+	if messages[0]["replace_original"] != false || !strings.Contains(fmt.Sprint(messages[0]["text"]), "suppressed") {
+		t.Errorf("Expected the suppression to be announced, got %v", messages[0])
+	}
+
+	// Acknowledging replaces the suppression
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, action("acknowledge"))
+	fake.wait(t, 1)
+	s, _ = state.Load(statePath)
+	if s.Acknowledgements[finding.Fingerprint()].Action != state.ActionAcknowledged {
+		t.Errorf("Expected the finding to be acknowledged, got %+v", s.Acknowledgements[finding.Fingerprint()])
+	}
+
+	// Unsigned actions are rejected
+	rec = httptest.NewRecorder()
+	req := action("acknowledge")
+	req.Header.Set("X-Slack-Signature", "v0=invalid")
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 for an unsigned action, got %d", rec.Code)
+	}
+}
+
+func TestSlackCommandUsesResponseURL(t *testing.T) {
+	fake, slackAPI := newFakeSlack(t)
+	handler := newSlackServer(t, &server.SlackOptions{
+		SigningSecret:     signingSecret,
+		APIURL:            slackAPI.URL,
+		ResponseURLPrefix: slackAPI.URL,
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, slashCommand(t, "check owner/repo", slackAPI.URL+"/response", time.Now(), signingSecret))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Scanning owner/repo") {
+		t.Fatalf("Expected an acknowledgement, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	messages, paths := fake.wait(t, 1)
+	if paths[0] != "/response" || messages[0]["response_type"] != "in_channel" {
+		t.Errorf("Expected the results to be sent to the response URL, got %s %v", paths[0], messages[0])
+	}
+}
+
+func TestSlackCommandUsage(t *testing.T) {
+	handler := newSlackServer(t, &server.SlackOptions{SigningSecret: signingSecret})
+
+	for _, text := range []string{"", "check", "status owner/repo"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, slashCommand(t, text, "", time.Now(), signingSecret))
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Usage") {
+			t.Errorf("Expected usage for %q, got %d: %s", text, rec.Code, rec.Body.String())
+		}
+	}
+
+	// Invalid repositories are reported back to the user
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, slashCommand(t, "check invalid-repo", "", time.Now(), signingSecret))
+	if !strings.Contains(rec.Body.String(), "Could not start scan") {
+		t.Errorf("Expected an error for an invalid repository, got %s", rec.Body.String())
+	}
+}
+
+func TestSlackAllowlist(t *testing.T) {
+	tests := []struct {
+		name    string
+		slack   server.SlackOptions
+		allowed bool
+	}{
+		{"Allowed user and channel", server.SlackOptions{AllowedUsers: []string{"U123"}, AllowedChannels: []string{"C456"}}, true},
+		{"Other user", server.SlackOptions{AllowedUsers: []string{"U999"}}, false},
+		{"Other channel", server.SlackOptions{AllowedChannels: []string{"C999"}}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			options := tc.slack
+			options.SigningSecret = signingSecret
+			handler := newSlackServer(t, &options)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, slashCommand(t, "check owner/repo", "", time.Now(), signingSecret))
+			if started := strings.Contains(rec.Body.String(), "Scanning owner/repo"); started != tc.allowed {
+				t.Errorf("Expected the scan started %v, got %d: %s", tc.allowed, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestSlackSignatureVerification(t *testing.T) {
+	handler := newSlackServer(t, &server.SlackOptions{SigningSecret: signingSecret})
+
+	tests := []struct {
+		name string
+		req  *http.Request
+	}{
+		{"Wrong secret", slashCommand(t, "check owner/repo", "", time.Now(), "wrong-secret")},
+		{"Replayed request", slashCommand(t, "check owner/repo", "", time.Now().Add(-10*time.Minute), signingSecret)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, tc.req)
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("Expected status 401, got %d", rec.Code)
+			}
+		})
+	}
+
+	// The Slack endpoint is not served when Slack is not configured
+	rec := httptest.NewRecorder()
+	newSlackServer(t, nil).ServeHTTP(rec, slashCommand(t, "check owner/repo", "", time.Now(), signingSecret))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 without Slack configuration, got %d", rec.Code)
+	}
+}
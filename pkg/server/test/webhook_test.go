@@ -0,0 +1,196 @@
+package test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/server"
+	"github.com/anupsv/git-monitoring/pkg/state"
+)
+
+const webhookSecret = "github-webhook-secret"
+
+// signedDelivery builds a webhook delivery signed like GitHub does
+func signedDelivery(eventType, delivery, body, secret string) *http.Request {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+
+	req := httptest.NewRequest(http.MethodPost, "/github/webhook", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", eventType)
+	req.Header.Set("X-GitHub-Delivery", delivery)
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+// pullRequestPayload builds the payload of a pull_request delivery of an event at updatedAt
+func pullRequestPayload(updatedAt time.Time) string {
+	return fmt.Sprintf(`{"action":"closed","pull_request":{"number":1,"updated_at":%q}}`, updatedAt.UTC().Format(time.RFC3339))
+}
+
+// newWebhookServer creates a server receiving GitHub webhooks, with scans that find nothing
+func newWebhookServer(options *server.GitHubWebhookOptions) *server.Server {
+	scan := func(ctx context.Context, req server.ScanRequest) (*server.ScanResult, error) {
+		return &server.ScanResult{}, nil
+	}
+	return server.New(scan, server.Options{GitHubWebhook: options})
+}
+
+func TestGitHubWebhookSignature(t *testing.T) {
+	srv := newWebhookServer(&server.GitHubWebhookOptions{Secret: webhookSecret})
+	var handled atomic.Int32
+	srv.HandleWebhook("pull_request", func(ctx context.Context, event server.WebhookEvent) error {
+		handled.Add(1)
+		return nil
+	})
+	handler := srv.Handler()
+
+	body := pullRequestPayload(time.Now())
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+	}{
+		{"Valid signature", signedDelivery("pull_request", "delivery-1", body, webhookSecret), http.StatusAccepted},
+		{"Wrong secret", signedDelivery("pull_request", "delivery-2", body, "other-secret"), http.StatusUnauthorized},
+		{"Missing signature", func() *http.Request {
+			req := signedDelivery("pull_request", "delivery-3", body, webhookSecret)
+			req.Header.Del("X-Hub-Signature-256")
+			return req
+		}(), http.StatusUnauthorized},
+		{"Tampered body", func() *http.Request {
+			req := signedDelivery("pull_request", "delivery-4", body, webhookSecret)
+			req.Body = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"action":"opened"}`)).Body
+			return req
+		}(), http.StatusUnauthorized},
+		{"Missing delivery ID", signedDelivery("pull_request", "", body, webhookSecret), http.StatusBadRequest},
+		{"Ping", signedDelivery("ping", "delivery-5", `{"zen":"Keep it logically awesome."}`, webhookSecret), http.StatusOK},
+		{"Event without handler", signedDelivery("issues", "delivery-6", body, webhookSecret), http.StatusAccepted},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, tc.req)
+			if rec.Code != tc.status {
+				t.Errorf("Expected status %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	if handled.Load() != 1 {
+		t.Errorf("Expected only the validly signed pull_request delivery to be handled, got %d", handled.Load())
+	}
+}
+
+func TestGitHubWebhookReplay(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	options := &server.GitHubWebhookOptions{Secret: webhookSecret, StatePath: statePath}
+
+	var handled atomic.Int32
+	failing := atomic.Bool{}
+	register := func(srv *server.Server) http.Handler {
+		srv.HandleWebhook("pull_request", func(ctx context.Context, event server.WebhookEvent) error {
+			if failing.Load() {
+				return errors.New("queue full")
+			}
+			handled.Add(1)
+			return nil
+		})
+		return srv.Handler()
+	}
+	deliver := func(handler http.Handler, delivery string) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, signedDelivery("pull_request", delivery, pullRequestPayload(time.Now()), webhookSecret))
+		return rec.Code
+	}
+
+	handler := register(newWebhookServer(options))
+	if status := deliver(handler, "delivery-1"); status != http.StatusAccepted {
+		t.Fatalf("Expected the first delivery to be accepted, got %d", status)
+	}
+	if status := deliver(handler, "delivery-1"); status != http.StatusOK || handled.Load() != 1 {
+		t.Errorf("Expected the replayed delivery to be ignored, got status %d and %d handled", status, handled.Load())
+	}
+
+	// Delivery IDs are kept in the state, so replays are also ignored after a restart
+	restarted := register(newWebhookServer(options))
+	if status := deliver(restarted, "delivery-1"); status != http.StatusOK || handled.Load() != 1 {
+		t.Errorf("Expected the replay after a restart to be ignored, got status %d and %d handled", status, handled.Load())
+	}
+
+	// A delivery that could not be handled can be redelivered
+	failing.Store(true)
+	if status := deliver(restarted, "delivery-2"); status != http.StatusInternalServerError {
+		t.Errorf("Expected the failed delivery to return an error, got %d", status)
+	}
+	failing.Store(false)
+	if status := deliver(restarted, "delivery-2"); status != http.StatusAccepted || handled.Load() != 2 {
+		t.Errorf("Expected the redelivery to be handled, got status %d and %d handled", status, handled.Load())
+	}
+
+	st, err := state.Load(statePath)
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+	if len(st.Deliveries) != 2 {
+		t.Errorf("Expected 2 deliveries in the state, got %v", st.Deliveries)
+	}
+}
+
+func TestGitHubWebhookEventTime(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	srv := newWebhookServer(&server.GitHubWebhookOptions{Secret: webhookSecret, StatePath: statePath, DeliveryRetention: time.Hour})
+	var handled atomic.Int32
+	for _, eventType := range []string{"pull_request", "deployment"} {
+		srv.HandleWebhook(eventType, func(ctx context.Context, event server.WebhookEvent) error {
+			handled.Add(1)
+			return nil
+		})
+	}
+	handler := srv.Handler()
+
+	// Deliveries of events older than the retention are rejected, as their IDs may have been forgotten
+	tests := []struct {
+		name      string
+		eventType string
+		body      string
+		status    int
+	}{
+		{"Recent event", "pull_request", pullRequestPayload(time.Now().Add(-30 * time.Minute)), http.StatusAccepted},
+		{"Event older than the retention", "pull_request", pullRequestPayload(time.Now().Add(-2 * time.Hour)), http.StatusBadRequest},
+		{"Event without a time", "pull_request", `{"action":"closed","pull_request":{"number":1}}`, http.StatusBadRequest},
+		{"Event type that cannot be dated", "deployment", `{"action":"created"}`, http.StatusBadRequest},
+	}
+	for i, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, signedDelivery(tc.eventType, fmt.Sprintf("delivery-%d", i), tc.body, webhookSecret))
+			if rec.Code != tc.status {
+				t.Errorf("Expected status %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	if handled.Load() != 1 {
+		t.Errorf("Expected only the recent event to be handled, got %d", handled.Load())
+	}
+	st, err := state.Load(statePath)
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+	if len(st.Deliveries) != 1 {
+		t.Errorf("Expected only the handled delivery in the state, got %v", st.Deliveries)
+	}
+}
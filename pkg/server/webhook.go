@@ -0,0 +1,245 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/state"
+)
+
+// webhookMaxPayload is the largest payload GitHub delivers
+const webhookMaxPayload = 25 << 20
+
+// defaultDeliveryRetention is how long delivery IDs are remembered when no retention is configured
+// GitHub only offers redeliveries of the last three days
+const defaultDeliveryRetention = 72 * time.Hour
+
+// eventTimes names the timestamp in the payload that dates the deliveries of each event type, as the payload
+// object and its field. Deliveries are only handled for these event types, see handleGitHubWebhook
+var eventTimes = map[string][2]string{
+	"pull_request":        {"pull_request", "updated_at"},
+	"pull_request_review": {"review", "submitted_at"},
+	"issues":              {"issue", "updated_at"},
+	"issue_comment":       {"comment", "updated_at"},
+	"workflow_run":        {"workflow_run", "updated_at"},
+}
+
+// GitHubWebhookOptions configures the GitHub webhook receiver
+type GitHubWebhookOptions struct {
+	// Secret of the webhook, used to verify the X-Hub-Signature-256 header of deliveries
+	Secret string
+
+	// State file the IDs of handled deliveries are recorded in, so replays are ignored across restarts
+	// Empty remembers them in memory only
+	StatePath string
+
+	// How long delivery IDs are remembered. Defaults to 72 hours
+	// Deliveries of events older than that are rejected, as their IDs may have been forgotten
+	DeliveryRetention time.Duration
+}
+
+// WebhookEvent is a verified delivery of a GitHub webhook
+type WebhookEvent struct {
+	Type     string          // Event type from the X-GitHub-Event header, e.g. "pull_request"
+	Delivery string          // Unique ID of the delivery from the X-GitHub-Delivery header
+	Payload  json.RawMessage // Body of the delivery
+}
+
+// WebhookHandler handles the verified deliveries of an event type
+// Handlers must return quickly, GitHub gives up on deliveries after ten seconds, so scans are queued with Submit
+type WebhookHandler func(ctx context.Context, event WebhookEvent) error
+
+// HandleWebhook registers the handler of an event type, replacing an earlier one
+// Deliveries of event types without a handler are accepted and ignored
+func (s *Server) HandleWebhook(eventType string, handler WebhookHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.webhookHandlers[eventType] = handler
+}
+
+// handleGitHubWebhook verifies a webhook delivery, ignores replays and passes it to the handler of its event type
+// The signature does not cover when a delivery was sent, so replays are recognized by their delivery ID, which is
+// only remembered for the retention. Deliveries of events older than the retention are rejected, so a replay
+// cannot be accepted once its ID is forgotten
+func (s *Server) handleGitHubWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, webhookMaxPayload))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := verifyGitHubSignature(s.options.GitHubWebhook.Secret, r.Header, body); err != nil {
+		log.Printf("Rejected GitHub webhook delivery: %v", err)
+		writeError(w, http.StatusUnauthorized, "invalid signature")
+		return
+	}
+
+	event := WebhookEvent{
+		Type:     r.Header.Get("X-GitHub-Event"),
+		Delivery: r.Header.Get("X-GitHub-Delivery"),
+		Payload:  body,
+	}
+	if event.Type == "" || event.Delivery == "" {
+		writeError(w, http.StatusBadRequest, "missing X-GitHub-Event or X-GitHub-Delivery header")
+		return
+	}
+
+	// GitHub sends a ping when the webhook is created
+	if event.Type == "ping" {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "pong"})
+		return
+	}
+
+	s.mu.Lock()
+	handler, ok := s.webhookHandlers[event.Type]
+	s.mu.Unlock()
+	if !ok {
+		writeJSON(w, http.StatusAccepted, map[string]string{"status": "ignored"})
+		return
+	}
+
+	now := time.Now().UTC()
+	happened, err := eventTime(event)
+	if err != nil {
+		log.Printf("Rejected GitHub webhook delivery %s: %v", event.Delivery, err)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if now.Sub(happened) > s.deliveryRetention() {
+		log.Printf("Rejected GitHub webhook delivery %s of an event at %s, older than the delivery retention", event.Delivery, happened.Format(time.RFC3339))
+		writeError(w, http.StatusBadRequest, "event is older than the delivery retention")
+		return
+	}
+
+	first, err := s.recordDelivery(event.Delivery, now)
+	if err != nil {
+		log.Printf("Error recording GitHub webhook delivery %s: %v", event.Delivery, err)
+		writeError(w, http.StatusInternalServerError, "could not record delivery")
+		return
+	}
+	if !first {
+		log.Printf("Ignoring replayed GitHub webhook delivery %s", event.Delivery)
+		writeJSON(w, http.StatusOK, map[string]string{"status": "duplicate"})
+		return
+	}
+
+	if err := handler(r.Context(), event); err != nil {
+		log.Printf("Error handling %s delivery %s: %v", event.Type, event.Delivery, err)
+		// Forget the delivery so it can be redelivered from GitHub
+		if err := s.forgetDelivery(event.Delivery); err != nil {
+			log.Printf("Error forgetting GitHub webhook delivery %s: %v", event.Delivery, err)
+		}
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("error handling delivery: %v", err))
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]string{"status": "accepted"})
+}
+
+// recordDelivery remembers a delivery ID and reports whether it was seen for the first time
+// IDs older than the retention are forgotten at the same time
+func (s *Server) recordDelivery(id string, now time.Time) (bool, error) {
+	retention := s.deliveryRetention()
+
+	record := func(deliveries map[string]time.Time) bool {
+		for seen, at := range deliveries {
+			if now.Sub(at) > retention {
+				delete(deliveries, seen)
+			}
+		}
+		if _, ok := deliveries[id]; ok {
+			return false
+		}
+		deliveries[id] = now
+		return true
+	}
+
+	if s.options.GitHubWebhook.StatePath == "" {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return record(s.deliveries), nil
+	}
+
+	var first bool
+	err := state.Update(s.options.GitHubWebhook.StatePath, func(st *state.State) error {
+		if st.Deliveries == nil {
+			st.Deliveries = make(map[string]time.Time)
+		}
+		first = record(st.Deliveries)
+		return nil
+	})
+	return first, err
+}
+
+// deliveryRetention returns how long delivery IDs are remembered
+func (s *Server) deliveryRetention() time.Duration {
+	if retention := s.options.GitHubWebhook.DeliveryRetention; retention > 0 {
+		return retention
+	}
+	return defaultDeliveryRetention
+}
+
+// eventTime returns when the event of a delivery happened, from the timestamp named in eventTimes
+func eventTime(event WebhookEvent) (time.Time, error) {
+	field, ok := eventTimes[event.Type]
+	if !ok {
+		return time.Time{}, fmt.Errorf("deliveries of %s events cannot be dated", event.Type)
+	}
+
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s payload: %v", event.Type, err)
+	}
+	var object map[string]json.RawMessage
+	var at time.Time
+	if json.Unmarshal(payload[field[0]], &object) != nil || json.Unmarshal(object[field[1]], &at) != nil || at.IsZero() {
+		return time.Time{}, fmt.Errorf("%s payload without %s.%s", event.Type, field[0], field[1])
+	}
+	return at, nil
+}
+
+// forgetDelivery forgets a delivery ID, e.g. when handling the delivery failed
+func (s *Server) forgetDelivery(id string) error {
+	if s.options.GitHubWebhook.StatePath == "" {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.deliveries, id)
+		return nil
+	}
+
+	return state.Update(s.options.GitHubWebhook.StatePath, func(st *state.State) error {
+		delete(st.Deliveries, id)
+		return nil
+	})
+}
+
+// verifyGitHubSignature checks the X-Hub-Signature-256 header of a webhook delivery
+// See https://docs.github.com/en/webhooks/using-webhooks/validating-webhook-deliveries
+func verifyGitHubSignature(secret string, header http.Header, body []byte) error {
+	signature, ok := strings.CutPrefix(header.Get("X-Hub-Signature-256"), "sha256=")
+	if !ok {
+		return fmt.Errorf("missing X-Hub-Signature-256 header")
+	}
+
+	received, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(mac.Sum(nil), received) {
+		return fmt.Errorf("signature mismatch")
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+package servicenow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+)
+
+// requestTimeout bounds each Table API request
+const requestTimeout = 30 * time.Second
+
+// maxShortDescription is the length of the short description field of ServiceNow tables
+const maxShortDescription = 160
+
+// severityRank orders the severities records are opened for
+var severityRank = map[string]int{
+	findings.SeverityMedium:   1,
+	findings.SeverityHigh:     2,
+	findings.SeverityCritical: 3,
+}
+
+// priorities are the impact and urgency of the records of each severity, 1 being the highest
+var priorities = map[string]string{
+	findings.SeverityMedium:   "3",
+	findings.SeverityHigh:     "2",
+	findings.SeverityCritical: "1",
+}
+
+// Result counts the findings records were opened for
+type Result struct {
+	Opened   []string // Numbers of the records opened, e.g. "INC0010001"
+	Existing int      // Findings that already had an active record
+}
+
+// Notifier opens ServiceNow records for severe findings through the Table API
+type Notifier struct {
+	config config.ServiceNowConfig
+	client *http.Client
+}
+
+// New creates a Notifier for the configured instance
+func New(cfg config.ServiceNowConfig) *Notifier {
+	return &Notifier{config: cfg, client: &http.Client{Timeout: requestTimeout}}
+}
+
+// Severe returns the findings at or above the minimum severity, once each
+func Severe(list []findings.Finding, minSeverity string) []findings.Finding {
+	minimum := severityRank[minSeverity]
+	seen := make(map[string]bool)
+	var severe []findings.Finding
+	for _, f := range list {
+		rank, ok := severityRank[f.Severity]
+		if !ok || rank < minimum || seen[f.Fingerprint()] {
+			continue
+		}
+		seen[f.Fingerprint()] = true
+		severe = append(severe, f)
+	}
+	return severe
+}
+
+// Notify opens a record for each finding at or above the minimum severity without an active one
+// Records carry the finding's fingerprint as their correlation ID, so a finding reported by every run
+// gets a single record until it is resolved
+func (n *Notifier) Notify(ctx context.Context, list []findings.Finding) (Result, error) {
+	var result Result
+	for _, f := range Severe(list, n.config.MinSeverity) {
+		active, err := n.active(ctx, f.Fingerprint())
+		if err != nil {
+			return result, err
+		}
+		if active {
+			result.Existing++
+			continue
+		}
+		number, err := n.create(ctx, Record(n.config, f))
+		if err != nil {
+			return result, err
+		}
+		result.Opened = append(result.Opened, number)
+	}
+	return result, nil
+}
+
+// Record returns the fields of the record of a finding
+func Record(cfg config.ServiceNowConfig, f findings.Finding) map[string]string {
+	shortDescription := fmt.Sprintf("[%s] %s", f.Monitor, f.Repository)
+	if f.Subject != "" {
+		shortDescription += ": " + f.Subject
+	}
+	if len(shortDescription) > maxShortDescription {
+		shortDescription = shortDescription[:maxShortDescription-3] + "..."
+	}
+
+	var description strings.Builder
+	fmt.Fprintln(&description, f.Summary)
+	fmt.Fprintln(&description, "")
+	fmt.Fprintf(&description, "Monitor: %s\n", f.Monitor)
+	if f.Account != "" {
+		fmt.Fprintf(&description, "Account: %s\n", f.Account)
+	}
+	fmt.Fprintf(&description, "Repository: %s\n", f.Repository)
+	fmt.Fprintf(&description, "Severity: %s\n", f.Severity)
+	if len(f.Controls) > 0 {
+		fmt.Fprintf(&description, "Controls: %s\n", strings.Join(f.Controls, ", "))
+	}
+	if f.URL != "" {
+		fmt.Fprintf(&description, "Link: %s\n", f.URL)
+	}
+	fmt.Fprintf(&description, "Fingerprint: %s", f.Fingerprint())
+
+	record := map[string]string{
+		"short_description":   shortDescription,
+		"description":         description.String(),
+		"impact":              priorities[f.Severity],
+		"urgency":             priorities[f.Severity],
+		"correlation_id":      f.Fingerprint(),
+		"correlation_display": "git-monitor",
+	}
+	if cfg.AssignmentGroup != "" {
+		record["assignment_group"] = cfg.AssignmentGroup
+	}
+	if cfg.ConfigurationItem != "" {
+		record["cmdb_ci"] = cfg.ConfigurationItem
+	}
+	for field, value := range cfg.Fields {
+		record[field] = value
+	}
+	return record
+}
+
+// active reports whether an active record has the correlation ID
+func (n *Notifier) active(ctx context.Context, correlationID string) (bool, error) {
+	query := url.Values{}
+	query.Set("sysparm_query", "active=true^correlation_id="+correlationID)
+	query.Set("sysparm_fields", "sys_id")
+	query.Set("sysparm_limit", "1")
+
+	var records []struct {
+		SysID string `json:"sys_id"`
+	}
+	if err := n.do(ctx, http.MethodGet, "?"+query.Encode(), nil, &records); err != nil {
+		return false, fmt.Errorf("error looking up the %s of finding %s: %w", n.config.Table, correlationID, err)
+	}
+	return len(records) > 0, nil
+}
+
+// create creates a record and returns its number
+func (n *Notifier) create(ctx context.Context, record map[string]string) (string, error) {
+	var created struct {
+		Number string `json:"number"`
+	}
+	if err := n.do(ctx, http.MethodPost, "", record, &created); err != nil {
+		return "", fmt.Errorf("error opening %s for finding %s: %w", n.config.Table, record["correlation_id"], err)
+	}
+	return created.Number, nil
+}
+
+// do sends a request to the table and decodes the result of its response into out
+func (n *Notifier) do(ctx context.Context, method, query string, payload interface{}, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(encoded)
+	}
+	endpoint := strings.TrimSuffix(n.config.InstanceURL, "/") + "/api/now/table/" + url.PathEscape(n.config.Table) + query
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(n.config.Username, n.config.Password)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		// The Table API explains errors in the message of its error object
+		var failure struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(respBody, &failure) == nil && failure.Error.Message != "" {
+			return fmt.Errorf("HTTP %d: %s", resp.StatusCode, failure.Error.Message)
+		}
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	var decoded struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(respBody, &decoded); err != nil {
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+	return json.Unmarshal(decoded.Result, out)
+}
@@ -0,0 +1,147 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/servicenow"
+)
+
+// instance is a fake ServiceNow incident table
+type instance struct {
+	records []map[string]string
+}
+
+func (i *instance) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if user, password, ok := r.BasicAuth(); !ok || user != "git-monitor" || password != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error": {"message": "User Not Authenticated"}}`))
+		return
+	}
+	if r.URL.Path != "/api/now/table/incident" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query().Get("sysparm_query")
+		var active []map[string]string
+		for _, record := range i.records {
+			if query == "active=true^correlation_id="+record["correlation_id"] && record["active"] == "true" {
+				active = append(active, map[string]string{"sys_id": record["sys_id"]})
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"result": active})
+	case http.MethodPost:
+		var record map[string]string
+		json.NewDecoder(r.Body).Decode(&record)
+		record["sys_id"] = fmt.Sprintf("sys-%d", len(i.records))
+		record["number"] = fmt.Sprintf("INC%07d", len(i.records)+1)
+		record["active"] = "true"
+		i.records = append(i.records, record)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{"result": record})
+	}
+}
+
+func newConfig(url string) config.ServiceNowConfig {
+	return config.ServiceNowConfig{
+		InstanceURL:       url,
+		Table:             "incident",
+		Username:          "git-monitor",
+		Password:          "secret",
+		MinSeverity:       findings.SeverityHigh,
+		AssignmentGroup:   "Security Operations",
+		ConfigurationItem: "GitHub",
+		Fields:            map[string]string{"category": "security", "impact": "2"},
+	}
+}
+
+func TestNotify(t *testing.T) {
+	inst := &instance{}
+	server := httptest.NewServer(inst)
+	defer server.Close()
+	notifier := servicenow.New(newConfig(server.URL))
+
+	critical := findings.Finding{Monitor: "token_health", Repository: "org:example-org", Summary: "Token expires in 2 days", Severity: findings.SeverityCritical}
+	high := findings.Finding{Monitor: "pr_checker", Repository: "example-org/api", Subject: "PR #1", Summary: "Merged without approval", Severity: findings.SeverityHigh}
+	medium := findings.Finding{Monitor: "pr_checker", Repository: "example-org/web", Subject: "PR #2", Summary: "Merged without approval", Severity: findings.SeverityMedium}
+	unescalated := findings.Finding{Monitor: "rulesets", Repository: "example-org/web", Summary: "No ruleset"}
+
+	result, err := notifier.Notify(context.Background(), []findings.Finding{critical, high, medium, unescalated, high})
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if strings.Join(result.Opened, ",") != "INC0000001,INC0000002" || result.Existing != 0 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	record := inst.records[0]
+	for field, want := range map[string]string{
+		"short_description": "[token_health] org:example-org",
+		"urgency":           "1",
+		"impact":            "2", // Configured fields override those set by git-monitor
+		"assignment_group":  "Security Operations",
+		"cmdb_ci":           "GitHub",
+		"category":          "security",
+		"correlation_id":    critical.Fingerprint(),
+	} {
+		if record[field] != want {
+			t.Errorf("Expected %s %q, got %q", field, want, record[field])
+		}
+	}
+
+	// Findings with an active record do not get another, those whose record was resolved do
+	inst.records[1]["active"] = "false"
+	result, err = notifier.Notify(context.Background(), []findings.Finding{critical, high})
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if strings.Join(result.Opened, ",") != "INC0000003" || result.Existing != 1 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
+
+func TestNotifyErrors(t *testing.T) {
+	server := httptest.NewServer(&instance{})
+	defer server.Close()
+	cfg := newConfig(server.URL)
+	cfg.Password = "wrong"
+
+	severe := findings.Finding{Monitor: "token_health", Repository: "org:example-org", Severity: findings.SeverityCritical}
+	_, err := servicenow.New(cfg).Notify(context.Background(), []findings.Finding{severe})
+	if err == nil || !strings.Contains(err.Error(), "User Not Authenticated") {
+		t.Errorf("Expected the authentication error, got %v", err)
+	}
+
+	// Without severe findings the instance is not contacted
+	if _, err := servicenow.New(cfg).Notify(context.Background(), []findings.Finding{{Monitor: "rulesets"}}); err != nil {
+		t.Errorf("Did not expect an error but got: %v", err)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	f := findings.Finding{Monitor: "pr_checker", Account: "acme", Repository: "example-org/api", Subject: strings.Repeat("x", 200),
+		Summary: "Merged without approval", URL: "https://github.com/example-org/api/pull/1", Severity: findings.SeverityCritical}
+	record := servicenow.Record(config.ServiceNowConfig{}, f)
+
+	if len(record["short_description"]) != 160 || !strings.HasSuffix(record["short_description"], "...") {
+		t.Errorf("Expected the short description to be truncated, got %q", record["short_description"])
+	}
+	for _, want := range []string{"Account: acme", "Severity: critical", "Link: https://github.com/example-org/api/pull/1", "Fingerprint: " + f.Fingerprint()} {
+		if !strings.Contains(record["description"], want) {
+			t.Errorf("Expected description to contain %q, got %q", want, record["description"])
+		}
+	}
+	if _, ok := record["assignment_group"]; ok {
+		t.Error("Expected no assignment group when none is configured")
+	}
+}
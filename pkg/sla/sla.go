@@ -0,0 +1,165 @@
+package sla
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/policy"
+	"github.com/anupsv/git-monitoring/pkg/state"
+)
+
+// Breach is a finding still reported after its remediation deadline passed
+type Breach struct {
+	Finding   findings.Finding
+	FirstSeen time.Time     // When the finding was first reported
+	SLA       time.Duration // Time the finding had to be remediated in
+	Deadline  time.Time     // When the time to remediate it ran out
+	New       bool          // Whether the deadline passed since the previous run
+}
+
+// Overdue returns how long the finding has been open past its deadline
+func (b Breach) Overdue(now time.Time) time.Duration {
+	return now.Sub(b.Deadline)
+}
+
+// Deadline returns the time to remediate a finding: the deadline of its monitor and rule, e.g.
+// "pr_checker.unapproved", or else of its monitor. It reports false for findings without a deadline
+func Deadline(deadlines map[string]config.Duration, f findings.Finding) (time.Duration, bool) {
+	if f.Rule != "" {
+		if deadline, ok := deadlines[f.Monitor+"."+f.Rule]; ok {
+			return deadline.Duration, true
+		}
+	}
+	deadline, ok := deadlines[f.Monitor]
+	return deadline.Duration, ok
+}
+
+// RepositoryDeadline returns the time to remediate a finding with the deadlines set by repositories in their
+// policy files, by lowercased "owner/repo": the shorter of the repository's and the central deadline, as
+// repositories can only tighten deadlines. It reports false for findings without a deadline
+func RepositoryDeadline(deadlines map[string]config.Duration, repoDeadlines map[string]time.Duration, f findings.Finding) (time.Duration, bool) {
+	sla, ok := Deadline(deadlines, f)
+	if repo, set := repoDeadlines[strings.ToLower(f.Repository)]; set && repo > 0 && (!ok || repo < sla) {
+		return repo, true
+	}
+	return sla, ok
+}
+
+// RepositoryDeadlines returns the remediation deadlines set in the policy files of the repositories with findings,
+// by lowercased "owner/repo", for RepositoryDeadline. Deadlines shorter than minDays are raised to it
+// Each policy file is read with the loader of the account the finding was reported for, by account name
+func RepositoryDeadlines(ctx context.Context, loaders map[string]*policy.Loader, records []state.Record, minDays int) map[string]time.Duration {
+	deadlines := make(map[string]time.Duration)
+	for _, record := range records {
+		repository := record.Finding.Repository
+		loader, ok := loaders[record.Finding.Account]
+		if !ok || strings.HasPrefix(repository, "org:") || !strings.Contains(repository, "/") {
+			continue
+		}
+		if override := loader.Load(ctx, repository).SLA.Days; override > 0 {
+			days := max(override, minDays)
+			if days != override {
+				log.Printf("Policy of %s sets %d SLA days, raised to %d", repository, override, days)
+			}
+			deadlines[strings.ToLower(repository)] = time.Duration(days) * 24 * time.Hour
+		}
+	}
+	return deadlines
+}
+
+// Check returns the findings reported in this run that are open past their deadline, most overdue first
+// repoDeadlines are the deadlines of repositories setting their own, by lowercased "owner/repo" (optional)
+// Breaches found for the first time are recorded in the state, so they are notified once
+func Check(tracker *state.Tracker, deadlines map[string]config.Duration, repoDeadlines map[string]time.Duration, now time.Time) []Breach {
+	var breaches []Breach
+	for _, record := range tracker.Reported() {
+		sla, ok := RepositoryDeadline(deadlines, repoDeadlines, record.Finding)
+		if !ok {
+			continue
+		}
+		deadline := record.FirstSeen.Add(sla)
+		if now.Before(deadline) {
+			continue
+		}
+
+		breach := Breach{Finding: record.Finding, FirstSeen: record.FirstSeen, SLA: sla, Deadline: deadline}
+		if record.BreachedAt == nil {
+			tracker.Breach(record.Finding, now)
+			breach.New = true
+		}
+		breaches = append(breaches, breach)
+	}
+
+	sort.SliceStable(breaches, func(i, j int) bool {
+		return breaches[i].Deadline.Before(breaches[j].Deadline)
+	})
+	return breaches
+}
+
+// NewBreaches returns the breaches whose deadline passed since the previous run
+func NewBreaches(breaches []Breach) []Breach {
+	var result []Breach
+	for _, b := range breaches {
+		if b.New {
+			result = append(result, b)
+		}
+	}
+	return result
+}
+
+// WriteMarkdown writes the SLA breaches in a code block format suitable for Slack
+// repoName renders repository names, e.g. redacted, and nil keeps them
+func WriteMarkdown(w io.Writer, breaches []Breach, repoName func(string) string, now time.Time) {
+	if len(breaches) == 0 {
+		return // No results to display
+	}
+
+	fmt.Fprintln(w, "## :hourglass: SLA Breaches")
+	fmt.Fprintf(w, "%d findings are open past their remediation deadline, %d since the previous run.\n\n", len(breaches), len(NewBreaches(breaches)))
+	writeTable(w, breaches, repoName, now)
+}
+
+// WriteNotificationMarkdown writes the findings whose deadline passed since the previous run, for the SLA webhook
+func WriteNotificationMarkdown(w io.Writer, breaches []Breach, repoName func(string) string, now time.Time) {
+	fmt.Fprintln(w, "## :hourglass: Remediation Deadlines Passed")
+	fmt.Fprintf(w, "%d findings were not remediated within their SLA.\n\n", len(breaches))
+	writeTable(w, breaches, repoName, now)
+}
+
+// writeTable writes a table of SLA breaches
+func writeTable(w io.Writer, breaches []Breach, repoName func(string) string, now time.Time) {
+	// Start code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "SLA    Overdue  Monitor                   Repository                Subject")
+	fmt.Fprintln(w, "-------------------------------------------------------------------------------------")
+	for _, b := range breaches {
+		repository := b.Finding.Repository
+		if repoName != nil {
+			repository = repoName(repository)
+		}
+		overdue := duration(b.Overdue(now))
+		if b.New {
+			overdue += "*"
+		}
+		fmt.Fprintf(w, "%-6s %-8s %-25s %-25s %s\n", duration(b.SLA), overdue, b.Finding.Monitor, repository, b.Finding.Subject)
+	}
+	fmt.Fprintln(w, "* deadline passed since the previous run")
+	// End code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
+
+// duration renders a duration in whole days, or hours within the first day
+func duration(d time.Duration) string {
+	if d < 24*time.Hour {
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
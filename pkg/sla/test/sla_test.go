@@ -0,0 +1,166 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/policy"
+	"github.com/anupsv/git-monitoring/pkg/sla"
+	"github.com/anupsv/git-monitoring/pkg/state"
+	mockgithub "github.com/anupsv/git-monitoring/pkg/tools/common/test"
+)
+
+func TestDeadline(t *testing.T) {
+	deadlines := map[string]config.Duration{
+		"pr_checker":            config.Hours(7 * 24),
+		"pr_checker.unapproved": config.Hours(3 * 24),
+	}
+
+	tests := []struct {
+		finding  findings.Finding
+		expected time.Duration
+		ok       bool
+	}{
+		{findings.Finding{Monitor: "pr_checker", Rule: "unapproved"}, 3 * 24 * time.Hour, true},
+		{findings.Finding{Monitor: "pr_checker", Rule: "rubber_stamp"}, 7 * 24 * time.Hour, true},
+		{findings.Finding{Monitor: "repo_visibility"}, 0, false},
+	}
+	for _, tc := range tests {
+		deadline, ok := sla.Deadline(deadlines, tc.finding)
+		if deadline != tc.expected || ok != tc.ok {
+			t.Errorf("Expected the deadline of %s/%s to be %v (%v), got %v (%v)", tc.finding.Monitor, tc.finding.Rule, tc.expected, tc.ok, deadline, ok)
+		}
+	}
+}
+
+func TestRepositoryDeadline(t *testing.T) {
+	deadlines := map[string]config.Duration{"pr_checker": config.Hours(7 * 24)}
+	repoDeadlines := map[string]time.Duration{
+		"owner/strict": 2 * 24 * time.Hour,
+		"owner/loose":  30 * 24 * time.Hour,
+	}
+
+	tests := []struct {
+		name     string
+		finding  findings.Finding
+		expected time.Duration
+		ok       bool
+	}{
+		{"Central deadline", findings.Finding{Monitor: "pr_checker", Repository: "owner/api"}, 7 * 24 * time.Hour, true},
+		{"Shorter repository deadline", findings.Finding{Monitor: "pr_checker", Repository: "Owner/Strict"}, 2 * 24 * time.Hour, true},
+		{"Longer repository deadline capped", findings.Finding{Monitor: "pr_checker", Repository: "owner/loose"}, 7 * 24 * time.Hour, true},
+		{"Repository deadline without a central one", findings.Finding{Monitor: "repo_visibility", Repository: "owner/strict"}, 2 * 24 * time.Hour, true},
+		{"No deadline", findings.Finding{Monitor: "repo_visibility", Repository: "owner/api"}, 0, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			deadline, ok := sla.RepositoryDeadline(deadlines, repoDeadlines, tc.finding)
+			if deadline != tc.expected || ok != tc.ok {
+				t.Errorf("Expected a deadline of %v (%v), got %v (%v)", tc.expected, tc.ok, deadline, ok)
+			}
+		})
+	}
+}
+
+func TestRepositoryDeadlines(t *testing.T) {
+	mockClient := &mockgithub.MockGitHubClient{
+		MockFileContents: map[string]string{
+			"owner/strict/.github/git-monitor.toml": "[sla]\ndays = 1\n",
+			"Owner/Tuned/.github/git-monitor.toml":  "[sla]\ndays = 5\n",
+		},
+	}
+	loaders := map[string]*policy.Loader{"": policy.NewLoader(mockClient, ".github/git-monitor.toml")}
+	records := []state.Record{
+		{Finding: findings.Finding{Monitor: "pr_checker", Repository: "owner/strict"}},
+		{Finding: findings.Finding{Monitor: "pr_checker", Repository: "Owner/Tuned"}},
+		{Finding: findings.Finding{Monitor: "pr_checker", Repository: "owner/default"}},
+		{Finding: findings.Finding{Monitor: "repo_creation", Repository: "org:owner"}},
+		{Finding: findings.Finding{Monitor: "pr_checker", Account: "other", Repository: "owner/strict"}},
+	}
+
+	deadlines := sla.RepositoryDeadlines(context.Background(), loaders, records, 3)
+	expected := map[string]time.Duration{
+		"owner/strict": 3 * 24 * time.Hour,
+		"owner/tuned":  5 * 24 * time.Hour,
+	}
+	if len(deadlines) != len(expected) {
+		t.Fatalf("Expected %d repository deadlines, got %v", len(expected), deadlines)
+	}
+	for repository, deadline := range expected {
+		if deadlines[repository] != deadline {
+			t.Errorf("Expected a deadline of %v for %s, got %v", deadline, repository, deadlines[repository])
+		}
+	}
+}
+
+// run records the findings of a run in the state and checks their deadlines
+func run(t *testing.T, path string, deadlines map[string]config.Duration, now time.Time, list ...findings.Finding) []sla.Breach {
+	t.Helper()
+	tracker, err := state.NewTracker(path)
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+	tracker.Record("pr_checker", list)
+	breaches := sla.Check(tracker, deadlines, nil, now)
+	if err := tracker.Save(); err != nil {
+		t.Fatalf("Failed to save state: %v", err)
+	}
+	return breaches
+}
+
+func TestCheck(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	deadlines := map[string]config.Duration{"pr_checker.unapproved": config.Hours(4)}
+	unapproved := findings.Finding{Monitor: "pr_checker", Repository: "owner/api", Subject: "PR #1", Rule: "unapproved"}
+	stamped := findings.Finding{Monitor: "pr_checker", Repository: "owner/api", Subject: "PR #2 rubber_stamp", Rule: "rubber_stamp"}
+
+	if breaches := run(t, path, deadlines, time.Now(), unapproved, stamped); len(breaches) != 0 {
+		t.Fatalf("Expected no breaches within the deadline, got %+v", breaches)
+	}
+
+	breaches := run(t, path, deadlines, time.Now().Add(5*time.Hour), unapproved, stamped)
+	if len(breaches) != 1 || breaches[0].Finding.Subject != "PR #1" || !breaches[0].New {
+		t.Fatalf("Expected a new breach of PR #1, got %+v", breaches)
+	}
+	if overdue := breaches[0].Overdue(time.Now().Add(5 * time.Hour)); overdue < time.Hour-time.Minute || overdue > time.Hour+time.Minute {
+		t.Errorf("Expected PR #1 to be an hour overdue, got %v", overdue)
+	}
+
+	// Breaches are notified once, and reported while the finding is open
+	breaches = run(t, path, deadlines, time.Now().Add(6*time.Hour), unapproved)
+	if len(breaches) != 1 || breaches[0].New {
+		t.Errorf("Expected the breach of PR #1 to be reported but not new, got %+v", breaches)
+	}
+	if len(sla.NewBreaches(breaches)) != 0 {
+		t.Errorf("Expected no new breaches to notify, got %+v", sla.NewBreaches(breaches))
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	now := time.Now()
+	breaches := []sla.Breach{{
+		Finding:   findings.Finding{Monitor: "repo_visibility", Repository: "owner/secret", Subject: "visibility"},
+		FirstSeen: now.Add(-30 * time.Hour),
+		SLA:       4 * time.Hour,
+		Deadline:  now.Add(-26 * time.Hour),
+		New:       true,
+	}}
+
+	var buf bytes.Buffer
+	sla.WriteMarkdown(&buf, breaches, func(string) string { return "repo-1" }, now)
+	output := buf.String()
+	for _, expected := range []string{"SLA Breaches", "1 since the previous run", "4h", "1d*", "repo-1"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %q in the breaches, got %s", expected, output)
+		}
+	}
+	if strings.Contains(output, "owner/secret") {
+		t.Errorf("Expected repository names to be rendered by repoName, got %s", output)
+	}
+}
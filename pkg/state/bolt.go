@@ -0,0 +1,68 @@
+package state
+
+import (
+	"strconv"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// boltBucket holds the encoded state and its version
+var boltBucket = []byte("state")
+
+// boltStore keeps the state in a local bbolt database
+// bbolt locks the database file while it is open, so other processes give up after waiting storeTimeout for it
+type boltStore struct {
+	db *bolt.DB
+}
+
+// openBolt opens the bbolt database at path, creating it when missing
+func openBolt(path string) (*boltStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: storeTimeout})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &boltStore{db: db}, nil
+}
+
+// Read reads the state from the database
+func (b *boltStore) Read() ([]byte, string, error) {
+	var data []byte
+	var version string
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucket)
+		// Values are only valid during the transaction
+		data = append([]byte(nil), bucket.Get([]byte("data"))...)
+		version = string(bucket.Get([]byte("version")))
+		return nil
+	})
+	if err != nil || version == "" {
+		return nil, "", err
+	}
+	return data, version, nil
+}
+
+// Write replaces the state in the database if it is still at version
+func (b *boltStore) Write(data []byte, version string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucket)
+		current := string(bucket.Get([]byte("version")))
+		if current != version {
+			return ErrConflict
+		}
+
+		n, _ := strconv.ParseInt(current, 10, 64)
+		next := strconv.FormatInt(n+1, 10)
+		if err := bucket.Put([]byte("data"), data); err != nil {
+			return err
+		}
+		return bucket.Put([]byte("version"), []byte(next))
+	})
+}
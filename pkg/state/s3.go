@@ -0,0 +1,78 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/anupsv/git-monitoring/pkg/s3"
+)
+
+// s3Store keeps the state as a JSON snapshot in an S3 object, or one of an S3-compatible service such as MinIO
+// Writes are conditional on the ETag of the snapshot read, so servers sharing it do not overwrite each other
+type s3Store struct {
+	client *s3.Client
+	key    string
+}
+
+// openS3 returns the store of the object at "bucket/key", with the credentials of the standard AWS environment variables
+func openS3(location string) (*s3Store, error) {
+	bucket, key, ok := strings.Cut(location, "/")
+	if bucket == "" || !ok || key == "" {
+		return nil, fmt.Errorf("location must be s3://bucket/key")
+	}
+
+	client, err := s3.FromEnv(bucket, storeTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return &s3Store{client: client, key: key}, nil
+}
+
+// Read downloads the snapshot, whose ETag is its version
+func (s *s3Store) Read() ([]byte, string, error) {
+	resp, data, err := s.do(http.MethodGet, nil, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("reading s3://%s/%s returned %s: %s", s.client.Bucket, s.key, resp.Status, data)
+	}
+	return data, resp.Header.Get("ETag"), nil
+}
+
+// Write uploads the snapshot if the object still has the ETag version, or does not exist yet without a version
+func (s *s3Store) Write(data []byte, version string) error {
+	header := http.Header{"Content-Type": {"application/json"}}
+	if version == "" {
+		header.Set("If-None-Match", "*")
+	} else {
+		header.Set("If-Match", version)
+	}
+
+	resp, body, err := s.do(http.MethodPut, data, header)
+	if err != nil {
+		return err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusPreconditionFailed, http.StatusConflict:
+		// 409 is returned when a concurrent conditional write is still in progress
+		return ErrConflict
+	default:
+		return fmt.Errorf("writing s3://%s/%s returned %s: %s", s.client.Bucket, s.key, resp.Status, body)
+	}
+}
+
+// do sends a request for the object
+func (s *s3Store) do(method string, body []byte, header http.Header) (*http.Response, []byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+	return s.client.Do(ctx, method, s.key, nil, body, header)
+}
@@ -0,0 +1,94 @@
+package state
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+
+	_ "github.com/lib/pq"  // Registers the "postgres" driver
+	_ "modernc.org/sqlite" // Registers the "sqlite" driver
+)
+
+// sqlSchema creates the table holding the state in a single row, in PostgreSQL and SQLite alike
+const sqlSchema = `CREATE TABLE IF NOT EXISTS git_monitor_state (
+	id INTEGER PRIMARY KEY,
+	version BIGINT NOT NULL,
+	data TEXT NOT NULL
+)`
+
+// sqlStore keeps the state in a PostgreSQL or SQLite database
+type sqlStore struct {
+	db *sql.DB
+}
+
+// openSQL connects to the database with the driver and creates the state table when missing
+func openSQL(driver, dsn string) (*sqlStore, error) {
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+	if _, err := db.ExecContext(ctx, sqlSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &sqlStore{db: db}, nil
+}
+
+// sqliteDSN returns the data source name of the SQLite database at path
+// Other processes writing the database are waited for instead of failing with "database is locked"
+func sqliteDSN(path string) string {
+	if strings.Contains(path, "?") {
+		return path
+	}
+	return path + "?_pragma=busy_timeout(30000)"
+}
+
+// Read reads the state row
+func (s *sqlStore) Read() ([]byte, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	var version int64
+	var data string
+	err := s.db.QueryRowContext(ctx, "SELECT version, data FROM git_monitor_state WHERE id = 1").Scan(&version, &data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return []byte(data), strconv.FormatInt(version, 10), nil
+}
+
+// Write replaces the state row if it is still at version, inserting it when no state was saved yet
+func (s *sqlStore) Write(data []byte, version string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	var result sql.Result
+	var err error
+	if version == "" {
+		result, err = s.db.ExecContext(ctx,
+			"INSERT INTO git_monitor_state (id, version, data) VALUES (1, 1, $1) ON CONFLICT (id) DO NOTHING", string(data))
+	} else {
+		result, err = s.db.ExecContext(ctx,
+			"UPDATE git_monitor_state SET version = version + 1, data = $1 WHERE id = 1 AND version = $2", string(data), version)
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrConflict
+	}
+	return nil
+}
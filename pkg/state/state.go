@@ -0,0 +1,509 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/encryption"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+)
+
+// State is the persisted outcome of previous runs
+type State struct {
+	LastRun  time.Time                     `json:"last_run"`
+	Findings map[string][]findings.Finding `json:"findings"` // Findings of the last successful run of each monitor, by Key
+	History  []Record                      `json:"history"`  // Every occurrence of a finding, oldest first
+
+	// Triage of findings, keyed by finding fingerprint
+	Acknowledgements map[string]Acknowledgement `json:"acknowledgements,omitempty"`
+
+	// Notifications that could not be delivered, oldest first, retried by later runs
+	Undelivered []Notification `json:"undelivered,omitempty"`
+
+	// Digest of the findings last notified to each notification target, by target, to only notify changes
+	Notified map[string]string `json:"notified,omitempty"`
+
+	// GitHub webhook deliveries handled by the server, by delivery ID, so replays are ignored
+	Deliveries map[string]time.Time `json:"deliveries,omitempty"`
+
+	// Verdicts of merged pull requests checked by the PR checker, by repository, number and merge commit
+	Verdicts map[string]Verdict `json:"verdicts,omitempty"`
+
+	// Targets in the scope of the last run of each monitor that was not sampled, by Key, to tell which targets
+	// were discovered or disappeared since
+	Scopes map[string][]string `json:"scopes,omitempty"`
+
+	// Rate limit headroom of the tokens checked by the token health monitor at its recent runs, by login,
+	// oldest first, to tell whether the headroom shrinks
+	RateLimits map[string][]RateLimitSample `json:"rate_limits,omitempty"`
+
+	// Lease of the server replica elected leader, when leader election uses the state
+	Leader *Lease `json:"leader,omitempty"`
+}
+
+// Lease is the leadership of a server replica, until it expires unless renewed
+type Lease struct {
+	Holder  string    `json:"holder"` // Identity of the replica, e.g. its pod name
+	Expires time.Time `json:"expires"`
+}
+
+// RateLimitSample is the share of its rate limit a token had left when the token health monitor checked it
+type RateLimitSample struct {
+	At       time.Time `json:"at"`
+	Headroom int       `json:"headroom"` // Percentage of the rate limit remaining
+}
+
+// Verdict is the outcome of checking a merged pull request, reused by later runs instead of checking it again
+// A merged PR does not change, so the verdict holds as long as the rules it was checked against
+type Verdict struct {
+	Rules      string          `json:"rules"` // Fingerprint of the rules the PR was checked against
+	MergedAt   time.Time       `json:"merged_at"`
+	InScope    bool            `json:"in_scope"` // Whether the PR changes a path the rules check
+	Approved   bool            `json:"approved"`
+	MergedBy   string          `json:"merged_by,omitempty"`  // Who merged the PR, recorded when it was flagged
+	Violations json.RawMessage `json:"violations,omitempty"` // Review rules the PR broke, as recorded by the PR checker
+}
+
+// Notification is a notification that could not be delivered
+// The target names where it is sent rather than holding the webhook URL, so the state holds no secrets
+type Notification struct {
+	Target      string    `json:"target"`  // Where the notification is sent, e.g. "slack"
+	Content     string    `json:"content"` // Markdown content of the notification
+	QueuedAt    time.Time `json:"queued_at"`
+	Attempts    int       `json:"attempts"` // Failed deliveries, including the first
+	LastAttempt time.Time `json:"last_attempt"`
+}
+
+// Triage actions
+const (
+	ActionAcknowledged = "acknowledged"
+	ActionSuppressed   = "suppressed"
+)
+
+// Acknowledgement records that someone triaged a finding
+type Acknowledgement struct {
+	Action string     `json:"action"`          // ActionAcknowledged or ActionSuppressed
+	By     string     `json:"by"`              // Who triaged the finding
+	At     time.Time  `json:"at"`              // When the finding was triaged
+	Until  *time.Time `json:"until,omitempty"` // When a suppression expires
+}
+
+// Active reports whether the acknowledgement still applies at the given time
+func (a Acknowledgement) Active(now time.Time) bool {
+	return a.Until == nil || now.Before(*a.Until)
+}
+
+// Acknowledge records the triage of a finding, replacing any earlier triage
+// Suppressions require an owner and an expiry
+func (s *State) Acknowledge(fingerprint string, ack Acknowledgement) error {
+	if ack.Action == ActionSuppressed && (ack.By == "" || ack.Until == nil) {
+		return fmt.Errorf("suppression of %s requires an owner and an expiry", fingerprint)
+	}
+
+	if s.Acknowledgements == nil {
+		s.Acknowledgements = make(map[string]Acknowledgement)
+	}
+	s.Acknowledgements[fingerprint] = ack
+	return nil
+}
+
+// Suppression returns the suppression of a finding at the given time, or nil
+// Suppressions without an owner or expiry, recorded by earlier versions, do not apply
+func (s *State) Suppression(fingerprint string, now time.Time) *Acknowledgement {
+	ack, ok := s.Acknowledgements[fingerprint]
+	if !ok || ack.Action != ActionSuppressed || ack.By == "" || ack.Until == nil || !ack.Active(now) {
+		return nil
+	}
+	return &ack
+}
+
+// Suppressed reports whether a finding is suppressed at the given time
+func (s *State) Suppressed(fingerprint string, now time.Time) bool {
+	return s.Suppression(fingerprint, now) != nil
+}
+
+// Record is an occurrence of a finding across consecutive runs
+// A finding that is resolved and later reported again starts a new record
+type Record struct {
+	Finding    findings.Finding `json:"finding"`
+	FirstSeen  time.Time        `json:"first_seen"`
+	LastSeen   time.Time        `json:"last_seen"`
+	ResolvedAt *time.Time       `json:"resolved_at,omitempty"`
+
+	// Consecutive runs that reported the finding, counted since records kept them
+	Runs int `json:"runs,omitempty"`
+	// Escalations of the finding by escalation policies, oldest first
+	Escalations []Escalation `json:"escalations,omitempty"`
+	// When the finding was first reported past its remediation deadline
+	BreachedAt *time.Time `json:"breached_at,omitempty"`
+}
+
+// Escalation records that an escalation policy escalated a finding that stayed unresolved
+type Escalation struct {
+	Policy   string    `json:"policy"`   // Name of the escalation policy
+	Severity string    `json:"severity"` // Severity the finding escalated to
+	At       time.Time `json:"at"`
+	Runs     int       `json:"runs"` // Consecutive runs that had reported the finding
+}
+
+// Escalated reports whether the record was escalated by the named policy
+func (r Record) Escalated(policy string) bool {
+	for _, e := range r.Escalations {
+		if e.Policy == policy {
+			return true
+		}
+	}
+	return false
+}
+
+// Query selects records from the history
+type Query struct {
+	Repository string    // Only records of this repository, empty for all
+	Monitor    string    // Only records of this monitor, empty for all
+	Since      time.Time // Only records seen at or after this time, zero for all
+}
+
+// Matches reports whether a record is selected by the query
+func (q Query) Matches(r Record) bool {
+	if q.Repository != "" && r.Finding.Repository != q.Repository {
+		return false
+	}
+	if q.Monitor != "" && r.Finding.Monitor != q.Monitor {
+		return false
+	}
+	if !q.Since.IsZero() && r.LastSeen.Before(q.Since) {
+		return false
+	}
+	return true
+}
+
+// Query returns the history records selected by the query, oldest first
+func (s *State) Query(q Query) []Record {
+	records := make([]Record, 0)
+	for _, r := range s.History {
+		if q.Matches(r) {
+			records = append(records, r)
+		}
+	}
+	return records
+}
+
+// Load reads the state from the given path, or the URL of a store (see Open)
+// A missing state is not an error and results in an empty state
+func Load(path string) (*State, error) {
+	store, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	s, _, err := read(store, path)
+	return s, err
+}
+
+// read reads the state from store with its version
+func read(store Store, path string) (*State, string, error) {
+	s := &State{Findings: make(map[string][]findings.Finding)}
+
+	data, version, err := store.Read()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read state %s: %w", Describe(path), err)
+	}
+	if data == nil {
+		return s, version, nil
+	}
+	if data, err = encryption.Unseal(data); err != nil {
+		return nil, "", fmt.Errorf("failed to decrypt state %s: %w", Describe(path), err)
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, "", fmt.Errorf("failed to parse state %s: %w", Describe(path), err)
+	}
+	if s.Findings == nil {
+		s.Findings = make(map[string][]findings.Finding)
+	}
+
+	// Earlier versions keyed acknowledgements by "monitor|repository|subject"
+	for key, ack := range s.Acknowledgements {
+		parts := strings.SplitN(key, "|", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		delete(s.Acknowledgements, key)
+		s.Acknowledgements[findings.Fingerprint(parts[0], parts[1], parts[2])] = ack
+	}
+
+	return s, version, nil
+}
+
+// updateAttempts is how many times Update applies its changes to a state other processes keep changing
+const updateAttempts = 5
+
+// updateMu serializes updates of state files within this process
+var updateMu sync.Mutex
+
+// Update loads the state from path, applies fn and saves the result
+// Concurrent updates within the process are serialized, so they do not overwrite each other. When another process
+// sharing the store changed the state in the meantime, fn is applied again to the state it saved
+func Update(path string, fn func(s *State) error) error {
+	updateMu.Lock()
+	defer updateMu.Unlock()
+
+	store, err := Open(path)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		s, version, err := read(store, path)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(s); err != nil {
+			return err
+		}
+
+		data, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode state: %w", err)
+		}
+		if data, err = encryption.Seal(data); err != nil {
+			return fmt.Errorf("failed to encrypt state: %w", err)
+		}
+		err = store.Write(data, version)
+		if errors.Is(err, ErrConflict) && attempt < updateAttempts {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save state %s: %w", Describe(path), err)
+		}
+		return nil
+	}
+}
+
+// Save writes the state to the given path, replacing the state saved there
+func (s *State) Save(path string) error {
+	return Update(path, func(current *State) error {
+		*current = *s
+		return nil
+	})
+}
+
+// Tracker compares the findings of the current run with the previous run
+// Methods on a nil Tracker are no-ops, so callers do not need to check whether state is enabled
+type Tracker struct {
+	path     string
+	now      time.Time
+	previous *State
+	current  *State
+	recorded []string
+	open     map[string]int // Index in the history of the unresolved record of each finding, by fingerprint
+}
+
+// NewTracker loads the previous state from path
+func NewTracker(path string) (*Tracker, error) {
+	previous, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := &State{
+		Findings: make(map[string][]findings.Finding),
+		History:  append([]Record(nil), previous.History...),
+		Scopes:   make(map[string][]string),
+	}
+	for monitor, list := range previous.Findings {
+		current.Findings[monitor] = list
+	}
+	for monitor, targets := range previous.Scopes {
+		current.Scopes[monitor] = targets
+	}
+
+	open := make(map[string]int)
+	for i, r := range current.History {
+		if r.ResolvedAt == nil {
+			open[r.Finding.Fingerprint()] = i
+		}
+	}
+
+	return &Tracker{
+		path:     path,
+		now:      time.Now(),
+		previous: previous,
+		current:  current,
+		open:     open,
+	}, nil
+}
+
+// Key returns the key under which the findings of a monitor are recorded for an account
+// Without multiple accounts, the key is the monitor
+func Key(account, monitor string) string {
+	if account == "" {
+		return monitor
+	}
+	return account + "/" + monitor
+}
+
+// recordKey returns the key under which a finding is recorded
+func recordKey(f findings.Finding) string {
+	return Key(f.Account, f.Monitor)
+}
+
+// Record stores the findings of a monitor for this run and returns the changes since the previous run
+// monitor is the key returned by Key. Monitors that are not recorded keep the findings of their last successful run
+func (t *Tracker) Record(monitor string, list []findings.Finding) findings.Changes {
+	if t == nil {
+		return findings.Changes{}
+	}
+
+	if list == nil {
+		list = make([]findings.Finding, 0)
+	}
+	t.current.Findings[monitor] = list
+	t.recorded = append(t.recorded, monitor)
+
+	changes := findings.Compare(t.previous.Findings[monitor], list)
+	t.updateHistory(monitor, list)
+
+	return t.withoutSuppressed(changes)
+}
+
+// Suppression returns the suppression of a finding recorded before this run, or nil
+func (t *Tracker) Suppression(f findings.Finding) *Acknowledgement {
+	if t == nil {
+		return nil
+	}
+	return t.previous.Suppression(f.Fingerprint(), t.now)
+}
+
+// withoutSuppressed leaves suppressed findings out of the new findings, so they are not announced again
+func (t *Tracker) withoutSuppressed(changes findings.Changes) findings.Changes {
+	var reported []findings.Finding
+	for _, f := range changes.New {
+		if !t.previous.Suppressed(f.Fingerprint(), t.now) {
+			reported = append(reported, f)
+		}
+	}
+	changes.New = reported
+	return changes
+}
+
+// updateHistory extends the records of findings that are still reported, starts records
+// for new findings and resolves the records of the monitor's findings that are gone
+func (t *Tracker) updateHistory(monitor string, list []findings.Finding) {
+	reported := make(map[string]bool)
+	for _, f := range list {
+		fingerprint := f.Fingerprint()
+		reported[fingerprint] = true
+
+		if i, ok := t.open[fingerprint]; ok {
+			t.current.History[i].Finding = f
+			t.current.History[i].LastSeen = t.now
+			t.current.History[i].Runs++
+			continue
+		}
+
+		t.current.History = append(t.current.History, Record{
+			Finding:   f,
+			FirstSeen: t.now,
+			LastSeen:  t.now,
+			Runs:      1,
+		})
+		t.open[fingerprint] = len(t.current.History) - 1
+	}
+
+	for fingerprint, i := range t.open {
+		if recordKey(t.current.History[i].Finding) != monitor || reported[fingerprint] {
+			continue
+		}
+		resolvedAt := t.now
+		t.current.History[i].ResolvedAt = &resolvedAt
+		delete(t.open, fingerprint)
+	}
+}
+
+// Reported returns the unresolved records of the findings this run reported, oldest first
+func (t *Tracker) Reported() []Record {
+	if t == nil {
+		return nil
+	}
+
+	var records []Record
+	for _, r := range t.current.History {
+		if r.ResolvedAt == nil && r.LastSeen.Equal(t.now) {
+			records = append(records, r)
+		}
+	}
+	return records
+}
+
+// Escalate records the escalation of an unresolved finding, so it is escalated once per policy
+func (t *Tracker) Escalate(f findings.Finding, escalation Escalation) {
+	if t == nil {
+		return
+	}
+	if i, ok := t.open[f.Fingerprint()]; ok {
+		t.current.History[i].Escalations = append(t.current.History[i].Escalations, escalation)
+	}
+}
+
+// Breach records that an unresolved finding passed its remediation deadline, so the breach is notified once
+func (t *Tracker) Breach(f findings.Finding, at time.Time) {
+	if t == nil {
+		return
+	}
+	if i, ok := t.open[f.Fingerprint()]; ok {
+		t.current.History[i].BreachedAt = &at
+	}
+}
+
+// Scope stores the targets in the scope of a monitor for this run and returns those of its previous run,
+// reporting false when the previous run is not known. monitor is the key returned by Key
+func (t *Tracker) Scope(monitor string, targets []string) ([]string, bool) {
+	if t == nil {
+		return nil, false
+	}
+
+	previous, ok := t.previous.Scopes[monitor]
+	t.current.Scopes[monitor] = targets
+	return previous, ok
+}
+
+// Changes returns the changes since the previous run across all recorded monitors
+func (t *Tracker) Changes() findings.Changes {
+	if t == nil {
+		return findings.Changes{}
+	}
+
+	var previous, current []findings.Finding
+	for _, monitor := range t.recorded {
+		previous = append(previous, t.previous.Findings[monitor]...)
+		current = append(current, t.current.Findings[monitor]...)
+	}
+
+	return t.withoutSuppressed(findings.Compare(previous, current))
+}
+
+// Save persists the findings of this run
+func (t *Tracker) Save() error {
+	if t == nil {
+		return nil
+	}
+
+	return Update(t.path, func(s *State) error {
+		// Keep findings triaged, notifications queued and sent, webhook deliveries handled, PR verdicts recorded
+		// and the leader elected while this run was in progress
+		t.current.Acknowledgements = s.Acknowledgements
+		t.current.Undelivered = s.Undelivered
+		t.current.Notified = s.Notified
+		t.current.Deliveries = s.Deliveries
+		t.current.Verdicts = s.Verdicts
+		t.current.Leader = s.Leader
+		t.current.LastRun = t.now
+		*s = *t.current
+		return nil
+	})
+}
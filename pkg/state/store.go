@@ -0,0 +1,106 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// storeTimeout bounds each read and write of an external store
+const storeTimeout = 30 * time.Second
+
+// ErrConflict is returned by Store.Write when the state was changed since it was read
+var ErrConflict = errors.New("state was changed by another process")
+
+// Store holds the encoded state, in a local file or in a backend shared by several servers
+// Versions let Update detect a state changed by another process between reading and writing it
+type Store interface {
+	// Read returns the encoded state and its version, nil data and an empty version when none was saved yet
+	Read() ([]byte, string, error)
+	// Write replaces the encoded state if it is still at version, and returns ErrConflict otherwise
+	Write(data []byte, version string) error
+}
+
+var (
+	storesMu sync.Mutex
+	stores   = make(map[string]Store) // Stores opened by this process, by location
+)
+
+// Open returns the store at location, opened once per process
+// Locations are URLs of a backend, "bbolt://", "sqlite://", "postgres://" or "s3://", or paths of local JSON files
+func Open(location string) (Store, error) {
+	storesMu.Lock()
+	defer storesMu.Unlock()
+
+	if store, ok := stores[location]; ok {
+		return store, nil
+	}
+
+	var store Store
+	var err error
+	scheme, rest, _ := strings.Cut(location, "://")
+	switch scheme {
+	case "bbolt":
+		store, err = openBolt(rest)
+	case "sqlite":
+		store, err = openSQL("sqlite", sqliteDSN(rest))
+	case "postgres", "postgresql":
+		store, err = openSQL("postgres", location)
+	case "s3":
+		store, err = openS3(rest)
+	default:
+		store = fileStore{path: location}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state store %s: %w", Describe(location), err)
+	}
+
+	stores[location] = store
+	return store, nil
+}
+
+// Describe returns the location of a store without the password it may contain, for messages
+func Describe(location string) string {
+	if u, err := url.Parse(location); err == nil && u.Scheme != "" {
+		return u.Redacted()
+	}
+	return location
+}
+
+// fileStore keeps the state in a local JSON file
+// Only processes on the same host can share it, so versions are not tracked
+type fileStore struct {
+	path string
+}
+
+// Read reads the state file
+func (f fileStore) Read() ([]byte, string, error) {
+	data, err := os.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return nil, "", nil
+	}
+	return data, "", err
+}
+
+// Write replaces the state file atomically so an interrupted run never leaves a truncated state behind
+func (f fileStore) Write(data []byte, _ string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), ".state-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
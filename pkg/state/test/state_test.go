@@ -0,0 +1,293 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/state"
+)
+
+func TestTrackerAcrossRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	first := []findings.Finding{
+		{Monitor: "pr_checker", Repository: "owner/repo", Subject: "PR #1"},
+	}
+	second := []findings.Finding{
+		{Monitor: "pr_checker", Repository: "owner/repo", Subject: "PR #2"},
+	}
+
+	// First run: everything is new
+	tracker, err := state.NewTracker(path)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	changes := tracker.Record("pr_checker", first)
+	if len(changes.New) != 1 {
+		t.Errorf("Expected 1 new finding in the first run, got %d", len(changes.New))
+	}
+	tracker.Record("repo_visibility", []findings.Finding{
+		{Monitor: "repo_visibility", Repository: "owner/public", Subject: "visibility"},
+	})
+	if err := tracker.Save(); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	// Second run: only the PR checker runs
+	tracker, err = state.NewTracker(path)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	tracker.Record("pr_checker", second)
+
+	changes = tracker.Changes()
+	if len(changes.New) != 1 || changes.New[0].Subject != "PR #2" {
+		t.Errorf("Expected PR #2 to be new, got %+v", changes.New)
+	}
+	if len(changes.Resolved) != 1 || changes.Resolved[0].Subject != "PR #1" {
+		t.Errorf("Expected PR #1 to be resolved, got %+v", changes.Resolved)
+	}
+	if err := tracker.Save(); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	// Monitors that did not run keep their findings
+	s, err := state.Load(path)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if len(s.Findings["repo_visibility"]) != 1 {
+		t.Errorf("Expected repo_visibility findings to be kept, got %+v", s.Findings["repo_visibility"])
+	}
+}
+
+func TestNilTracker(t *testing.T) {
+	var tracker *state.Tracker
+
+	if changes := tracker.Record("pr_checker", nil); !changes.Empty() {
+		t.Errorf("Expected no changes from a nil tracker")
+	}
+	if err := tracker.Save(); err != nil {
+		t.Errorf("Did not expect an error but got: %v", err)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	public := findings.Finding{Monitor: "repo_visibility", Repository: "owner/public", Subject: "visibility"}
+
+	runs := [][]findings.Finding{
+		{public},
+		{public},
+		{},
+		{public},
+	}
+
+	for _, list := range runs {
+		tracker, err := state.NewTracker(path)
+		if err != nil {
+			t.Fatalf("Did not expect an error but got: %v", err)
+		}
+		tracker.Record("repo_visibility", list)
+		if err := tracker.Save(); err != nil {
+			t.Fatalf("Did not expect an error but got: %v", err)
+		}
+	}
+
+	s, err := state.Load(path)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	records := s.Query(state.Query{Repository: "owner/public"})
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 occurrences of the finding, got %d", len(records))
+	}
+	if records[0].ResolvedAt == nil {
+		t.Error("Expected the first occurrence to be resolved")
+	}
+	if records[1].ResolvedAt != nil {
+		t.Error("Expected the second occurrence to be open")
+	}
+
+	if records := s.Query(state.Query{Repository: "owner/other"}); len(records) != 0 {
+		t.Errorf("Expected no records for another repository, got %d", len(records))
+	}
+}
+
+func TestAcknowledgements(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	finding := findings.Finding{Monitor: "repo_visibility", Repository: "owner/public", Subject: "visibility"}
+
+	// A run is in progress while the finding is suppressed from Slack
+	tracker, err := state.NewTracker(path)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	tracker.Record("repo_visibility", []findings.Finding{finding})
+
+	until := time.Now().Add(7 * 24 * time.Hour)
+	err = state.Update(path, func(s *state.State) error {
+		return s.Acknowledge(finding.Fingerprint(), state.Acknowledgement{
+			Action: state.ActionSuppressed,
+			By:     "alice",
+			At:     time.Now(),
+			Until:  &until,
+		})
+	})
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	// Saving the run keeps the suppression
+	if err := tracker.Save(); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	s, err := state.Load(path)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if !s.Suppressed(finding.Fingerprint(), time.Now()) {
+		t.Fatal("Expected the finding to be suppressed")
+	}
+	if s.Suppressed(finding.Fingerprint(), until.Add(time.Minute)) {
+		t.Error("Expected the suppression to expire")
+	}
+	if len(s.History) != 1 {
+		t.Errorf("Expected the run's history to be saved, got %d records", len(s.History))
+	}
+
+	// A suppressed finding that reappears is not announced as new
+	tracker, err = state.NewTracker(path)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	tracker.Record("repo_visibility", nil)
+	if err := tracker.Save(); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	tracker, err = state.NewTracker(path)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	changes := tracker.Record("repo_visibility", []findings.Finding{finding})
+	if len(changes.New) != 0 {
+		t.Errorf("Expected no new findings while suppressed, got %+v", changes.New)
+	}
+
+	if ack := tracker.Suppression(finding); ack == nil || ack.By != "alice" {
+		t.Errorf("Expected the tracker to report alice's suppression, got %+v", ack)
+	}
+
+	// Acknowledged findings are still reported
+	ack := state.Acknowledgement{Action: state.ActionAcknowledged, By: "bob", At: time.Now()}
+	if err := s.Acknowledge(finding.Fingerprint(), ack); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if s.Suppressed(finding.Fingerprint(), time.Now()) {
+		t.Error("Expected an acknowledged finding not to be suppressed")
+	}
+
+	// Suppressions require an owner and an expiry
+	if err := s.Acknowledge(finding.Fingerprint(), state.Acknowledgement{Action: state.ActionSuppressed, By: "bob", At: time.Now()}); err == nil {
+		t.Error("Expected an error for a suppression without expiry")
+	}
+	if err := s.Acknowledge(finding.Fingerprint(), state.Acknowledgement{Action: state.ActionSuppressed, At: time.Now(), Until: &until}); err == nil {
+		t.Error("Expected an error for a suppression without owner")
+	}
+}
+
+func TestLegacyAcknowledgementKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	legacy := `{"acknowledgements": {"repo_visibility|owner/public|visibility": {"action": "acknowledged", "by": "alice"}}}`
+	if err := os.WriteFile(path, []byte(legacy), 0o600); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
+
+	s, err := state.Load(path)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	fingerprint := findings.Fingerprint("repo_visibility", "owner/public", "visibility")
+	if len(s.Acknowledgements) != 1 || s.Acknowledgements[fingerprint].By != "alice" {
+		t.Errorf("Expected the acknowledgement to be keyed by fingerprint, got %+v", s.Acknowledgements)
+	}
+}
+
+func TestTrackerAccounts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	finding := func(account string) findings.Finding {
+		return findings.Finding{Monitor: "pr_checker", Account: account, Repository: "owner/repo", Subject: "PR #1"}
+	}
+
+	tracker, err := state.NewTracker(path)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	tracker.Record(state.Key("acme", "pr_checker"), []findings.Finding{finding("acme")})
+	changes := tracker.Record(state.Key("globex", "pr_checker"), []findings.Finding{finding("globex")})
+	if len(changes.New) != 1 {
+		t.Errorf("Expected the same finding of another account to be new, got %+v", changes.New)
+	}
+	if err := tracker.Save(); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	// Accounts do not resolve each other's findings
+	tracker, err = state.NewTracker(path)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	changes = tracker.Record(state.Key("acme", "pr_checker"), nil)
+	if len(changes.Resolved) != 1 || changes.Resolved[0].Account != "acme" {
+		t.Errorf("Expected only the finding of acme to be resolved, got %+v", changes.Resolved)
+	}
+	changes = tracker.Record(state.Key("globex", "pr_checker"), []findings.Finding{finding("globex")})
+	if len(changes.New) != 0 || len(changes.Resolved) != 0 {
+		t.Errorf("Expected no changes for globex, got %+v", changes)
+	}
+
+	if finding("acme").Fingerprint() == finding("globex").Fingerprint() {
+		t.Error("Expected findings of different accounts to have different fingerprints")
+	}
+	if finding("").Fingerprint() != findings.Fingerprint("pr_checker", "owner/repo", "PR #1") {
+		t.Error("Expected findings without an account to keep their fingerprint")
+	}
+}
+
+func TestScopes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	tracker, err := state.NewTracker(path)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if _, compared := tracker.Scope("pr_checker", []string{"owner/a", "owner/b"}); compared {
+		t.Errorf("Expected no previous scope in the first run")
+	}
+	if err := tracker.Save(); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	tracker, err = state.NewTracker(path)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	previous, compared := tracker.Scope("pr_checker", []string{"owner/a"})
+	if !compared || len(previous) != 2 {
+		t.Errorf("Expected the scope of the previous run, got %v", previous)
+	}
+	if _, compared := tracker.Scope("acme/pr_checker", nil); compared {
+		t.Errorf("Expected scopes to be kept per account")
+	}
+
+	var nilTracker *state.Tracker
+	if _, compared := nilTracker.Scope("pr_checker", nil); compared {
+		t.Errorf("Expected no previous scope from a nil tracker")
+	}
+}
@@ -0,0 +1,226 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/anupsv/git-monitoring/pkg/encryption"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/state"
+)
+
+func TestStores(t *testing.T) {
+	dir := t.TempDir()
+	locations := map[string]string{
+		"file":   filepath.Join(dir, "state.json"),
+		"bbolt":  "bbolt://" + filepath.Join(dir, "state.db"),
+		"sqlite": "sqlite://" + filepath.Join(dir, "state.sqlite"),
+	}
+
+	for name, location := range locations {
+		t.Run(name, func(t *testing.T) {
+			s, err := state.Load(location)
+			if err != nil {
+				t.Fatalf("Failed to load a missing state: %v", err)
+			}
+			if len(s.Findings) != 0 {
+				t.Errorf("Expected an empty state, got %+v", s)
+			}
+
+			for _, subject := range []string{"PR #1", "PR #2"} {
+				err := state.Update(location, func(s *state.State) error {
+					s.Findings["pr_checker"] = append(s.Findings["pr_checker"], findings.Finding{Monitor: "pr_checker", Subject: subject})
+					return nil
+				})
+				if err != nil {
+					t.Fatalf("Failed to update state: %v", err)
+				}
+			}
+
+			s, err = state.Load(location)
+			if err != nil {
+				t.Fatalf("Failed to load state: %v", err)
+			}
+			if len(s.Findings["pr_checker"]) != 2 {
+				t.Errorf("Expected both updates to be saved, got %+v", s.Findings)
+			}
+		})
+	}
+}
+
+func TestStoreConflicts(t *testing.T) {
+	dir := t.TempDir()
+	for _, location := range []string{"bbolt://" + filepath.Join(dir, "state.db"), "sqlite://" + filepath.Join(dir, "state.sqlite")} {
+		store, err := state.Open(location)
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", location, err)
+		}
+
+		if err := store.Write([]byte(`{}`), ""); err != nil {
+			t.Fatalf("Failed to create state in %s: %v", location, err)
+		}
+		_, version, err := store.Read()
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", location, err)
+		}
+
+		// Another server saved the state since it was read
+		if err := store.Write([]byte(`{"last_run":"2030-01-01T00:00:00Z"}`), version); err != nil {
+			t.Fatalf("Failed to write %s: %v", location, err)
+		}
+		if err := store.Write([]byte(`{}`), version); !errors.Is(err, state.ErrConflict) {
+			t.Errorf("Expected a conflict writing a stale version of %s, got %v", location, err)
+		}
+		if err := store.Write([]byte(`{}`), ""); !errors.Is(err, state.ErrConflict) {
+			t.Errorf("Expected a conflict creating the existing state of %s, got %v", location, err)
+		}
+	}
+}
+
+// fakeS3 serves objects with ETags and honors conditional writes
+type fakeS3 struct {
+	mu       sync.Mutex
+	objects  map[string][]byte
+	versions map[string]int
+	puts     int
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+		http.Error(w, "unsigned request", http.StatusForbidden)
+		return
+	}
+
+	data, exists := f.objects[r.URL.Path]
+	etag := fmt.Sprintf(`"%d"`, f.versions[r.URL.Path])
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write(data)
+	case http.MethodPut:
+		if (r.Header.Get("If-None-Match") == "*" && exists) || (r.Header.Get("If-Match") != "" && r.Header.Get("If-Match") != etag) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = body
+		f.versions[r.URL.Path]++
+		f.puts++
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	fake := &fakeS3{objects: make(map[string][]byte), versions: make(map[string]int)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	t.Setenv("AWS_ENDPOINT_URL_S3", server.URL)
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	location := "s3://monitoring/git-monitor/state.json"
+
+	for _, subject := range []string{"PR #1", "PR #2"} {
+		err := state.Update(location, func(s *state.State) error {
+			s.Findings["pr_checker"] = append(s.Findings["pr_checker"], findings.Finding{Monitor: "pr_checker", Subject: subject})
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Failed to update state: %v", err)
+		}
+	}
+	if _, ok := fake.objects["/monitoring/git-monitor/state.json"]; !ok {
+		t.Fatalf("Expected the state to be saved path-style in the bucket, got %v", fake.objects)
+	}
+
+	// Another server saves the state between the read and write of an update, which is then applied again
+	updates := 0
+	err := state.Update(location, func(s *state.State) error {
+		updates++
+		if updates == 1 {
+			fake.mu.Lock()
+			fake.versions["/monitoring/git-monitor/state.json"]++
+			fake.mu.Unlock()
+		}
+		s.Findings["pr_checker"] = append(s.Findings["pr_checker"], findings.Finding{Monitor: "pr_checker", Subject: "PR #3"})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to update state: %v", err)
+	}
+	if updates != 2 || fake.puts != 3 {
+		t.Errorf("Expected the update to be applied again after the conflict, got %d updates and %d writes", updates, fake.puts)
+	}
+
+	s, err := state.Load(location)
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+	if len(s.Findings["pr_checker"]) != 3 {
+		t.Errorf("Expected 3 findings, got %+v", s.Findings)
+	}
+}
+
+func TestDescribeRedactsPasswords(t *testing.T) {
+	if described := state.Describe("postgres://monitor:hunter2@db:5432/monitoring"); strings.Contains(described, "hunter2") {
+		t.Errorf("Expected the password to be redacted, got %s", described)
+	}
+	if described := state.Describe("git-monitor-state.json"); described != "git-monitor-state.json" {
+		t.Errorf("Expected paths to be kept, got %s", described)
+	}
+}
+
+func TestEncryptedState(t *testing.T) {
+	location := filepath.Join(t.TempDir(), "state.json")
+	plain := findings.Finding{Monitor: "pr_checker", Repository: "example-org/api", Subject: "PR #1"}
+	if err := state.Update(location, func(s *state.State) error {
+		s.Findings["pr_checker"] = []findings.Finding{plain}
+		return nil
+	}); err != nil {
+		t.Fatalf("Failed to update state: %v", err)
+	}
+
+	// A state saved before encryption was enabled is read, and encrypted when it is saved again
+	cipher, _ := encryption.New(bytes.Repeat([]byte{7}, 32))
+	encryption.Enable(cipher)
+	defer encryption.Enable(nil)
+	if err := state.Update(location, func(s *state.State) error {
+		if len(s.Findings["pr_checker"]) != 1 {
+			t.Errorf("Expected the plaintext state to be read, got %+v", s.Findings)
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("Failed to update state: %v", err)
+	}
+
+	data, err := os.ReadFile(location)
+	if err != nil {
+		t.Fatalf("Failed to read state file: %v", err)
+	}
+	if !encryption.Encrypted(data) || bytes.Contains(data, []byte("example-org/api")) {
+		t.Errorf("Expected the state file to be encrypted, got %q", data)
+	}
+	if s, err := state.Load(location); err != nil || len(s.Findings["pr_checker"]) != 1 {
+		t.Errorf("Expected the encrypted state to be read, got %+v (%v)", s, err)
+	}
+
+	encryption.Enable(nil)
+	if _, err := state.Load(location); !errors.Is(err, encryption.ErrNoKey) {
+		t.Errorf("Expected ErrNoKey without the key, got %v", err)
+	}
+}
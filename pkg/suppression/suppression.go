@@ -0,0 +1,248 @@
+package suppression
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+// dateLayout is the format of expiry dates
+const dateLayout = "2006-01-02"
+
+// File is a suppression file committed in a repository
+type File struct {
+	Suppressions []Rule `yaml:"suppressions"`
+}
+
+// Rule suppresses the findings of a monitor in the repository
+type Rule struct {
+	Monitor       string `yaml:"monitor"`       // Configuration key of the monitor (e.g. "dormant_repositories")
+	Subject       string `yaml:"subject"`       // Glob matched against the finding subject (e.g. "PR #*"), empty for all
+	Owner         string `yaml:"owner"`         // Who is accountable for the exception, required
+	Justification string `yaml:"justification"` // Why the findings are acceptable, required
+	Expires       string `yaml:"expires"`       // Last day the rule applies as YYYY-MM-DD, or an RFC 3339 timestamp, required
+}
+
+// Suppression is a suppressed finding with the owner and expiry of its suppression
+type Suppression struct {
+	Finding       findings.Finding `json:"finding"`
+	Owner         string           `json:"owner"`
+	Justification string           `json:"justification,omitempty"`
+	Expires       time.Time        `json:"expires"`
+	Source        string           `json:"source"` // Where the suppression was made, e.g. ".git-monitor.yml" or "Slack"
+}
+
+// Parse parses and validates a suppression file
+func Parse(data []byte) (*File, error) {
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("error decoding suppression file: %v", err)
+	}
+
+	for i, rule := range file.Suppressions {
+		if rule.Monitor == "" {
+			return nil, fmt.Errorf("suppression %d: monitor is required", i+1)
+		}
+		if strings.TrimSpace(rule.Owner) == "" {
+			return nil, fmt.Errorf("suppression %d: owner is required", i+1)
+		}
+		if strings.TrimSpace(rule.Justification) == "" {
+			return nil, fmt.Errorf("suppression %d: justification is required", i+1)
+		}
+		if _, err := parseExpiry(rule.Expires); err != nil {
+			return nil, fmt.Errorf("suppression %d: invalid expiry %q. Must be YYYY-MM-DD or an RFC 3339 timestamp", i+1, rule.Expires)
+		}
+		if _, err := path.Match(rule.Subject, ""); err != nil {
+			return nil, fmt.Errorf("suppression %d: invalid subject pattern %q", i+1, rule.Subject)
+		}
+	}
+
+	return &file, nil
+}
+
+// parseExpiry parses an expiry date or timestamp into the time at which a rule stops applying
+// A date expires at the end of that day in UTC
+func parseExpiry(expires string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, expires); err == nil {
+		return t, nil
+	}
+
+	day, err := time.Parse(dateLayout, expires)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return day.AddDate(0, 0, 1), nil
+}
+
+// ExpiresAt returns the time at which the rule stops applying
+func (r Rule) ExpiresAt() time.Time {
+	t, _ := parseExpiry(r.Expires)
+	return t
+}
+
+// Matches reports whether the rule suppresses a finding at the given time
+func (r Rule) Matches(f findings.Finding, now time.Time) bool {
+	if r.Monitor != f.Monitor || !now.Before(r.ExpiresAt()) {
+		return false
+	}
+	if r.Subject == "" {
+		return true
+	}
+	matched, _ := path.Match(r.Subject, f.Subject)
+	return matched
+}
+
+// Match returns the rule suppressing a finding at the given time, or nil
+func (f *File) Match(finding findings.Finding, now time.Time) *Rule {
+	if f == nil {
+		return nil
+	}
+	for i := range f.Suppressions {
+		if f.Suppressions[i].Matches(finding, now) {
+			return &f.Suppressions[i]
+		}
+	}
+	return nil
+}
+
+// Loader fetches the suppression files of repositories
+// Each repository's file is fetched at most once per Loader
+type Loader struct {
+	client common.GitHubClientInterface
+	path   string
+	now    time.Time
+	files  map[string]*File
+}
+
+// NewLoader creates a loader reading the suppression file at path in each repository
+func NewLoader(client common.GitHubClientInterface, path string) *Loader {
+	return &Loader{
+		client: client,
+		path:   path,
+		now:    time.Now(),
+		files:  make(map[string]*File),
+	}
+}
+
+// Load returns the suppression file of a repository ("owner/repo")
+// Repositories without a file have no suppressions
+func (l *Loader) Load(ctx context.Context, repository string) (*File, error) {
+	if file, ok := l.files[repository]; ok {
+		return file, nil
+	}
+
+	owner, repo, ok := common.ParseRepository(repository)
+	if !ok {
+		return nil, nil
+	}
+
+	data, err := l.client.GetFileContent(ctx, owner, repo, l.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file *File
+	if data != nil {
+		file, err = Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s in %s: %v", l.path, repository, err)
+		}
+	}
+
+	l.files[repository] = file
+	return file, nil
+}
+
+// Match returns the rule of the repository's suppression file that suppresses a finding, or nil
+// Files that cannot be fetched or parsed are logged and ignored, so findings are reported rather than lost
+func (l *Loader) Match(ctx context.Context, f findings.Finding) *Rule {
+	file, err := l.Load(ctx, f.Repository)
+	if err != nil {
+		log.Printf("Error loading suppressions, reporting findings of %s: %v", f.Repository, err)
+		l.files[f.Repository] = nil
+		return nil
+	}
+
+	rule := file.Match(f, l.now)
+	if rule == nil {
+		return nil
+	}
+
+	log.Printf("Suppressed %s finding %s in %s until %s by %s: %s", f.Monitor, f.Subject, f.Repository, rule.Expires, rule.Owner, rule.Justification)
+	return rule
+}
+
+// Suppression describes the suppression of a finding by a rule of the given suppression file
+func (r Rule) Suppression(f findings.Finding, source string) Suppression {
+	return Suppression{
+		Finding:       f,
+		Owner:         r.Owner,
+		Justification: r.Justification,
+		Expires:       r.ExpiresAt(),
+		Source:        source,
+	}
+}
+
+// ExpiringSoon returns the suppressions that expire within the given period, soonest first
+func ExpiringSoon(suppressions []Suppression, now time.Time, within time.Duration) []Suppression {
+	expiring := make([]Suppression, 0)
+	for _, s := range suppressions {
+		if s.Expires.After(now) && !s.Expires.After(now.Add(within)) {
+			expiring = append(expiring, s)
+		}
+	}
+
+	sort.SliceStable(expiring, func(i, j int) bool {
+		return expiring[i].Expires.Before(expiring[j].Expires)
+	})
+
+	return expiring
+}
+
+// WriteExpiringMarkdown writes suppressions that expire soon in a code block format suitable for Slack
+// Expiry times are shown in the given location, UTC when it is nil
+func WriteExpiringMarkdown(w io.Writer, suppressions []Suppression, loc *time.Location) {
+	if len(suppressions) == 0 {
+		return // No results to display
+	}
+
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	// Print header for expiring suppressions
+	fmt.Fprintln(w, "## :hourglass: Suppressions Expiring Soon")
+	fmt.Fprintf(w, "%d suppressed findings will be reported again when their suppression expires.\n\n", len(suppressions))
+
+	// Start code block
+	fmt.Fprintln(w, "```")
+	// Create fixed-width headers with proper spacing for code block
+	fmt.Fprintln(w, "Expires           Owner                Monitor                   Repository                Subject")
+	fmt.Fprintln(w, "---------------------------------------------------------------------------------------------------")
+
+	for _, s := range suppressions {
+		// Format repository name with padding
+		repoStr := s.Finding.Repository
+		if len(repoStr) > 24 {
+			repoStr = repoStr[:21] + "..."
+		} else {
+			repoStr = fmt.Sprintf("%-24s", repoStr)
+		}
+
+		fmt.Fprintf(w, "%-17s %-20s %-25s %s %s\n", s.Expires.In(loc).Format("2006-01-02 15:04"), s.Owner, s.Finding.Monitor, repoStr, s.Finding.Subject)
+	}
+
+	// End code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
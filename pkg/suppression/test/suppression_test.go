@@ -0,0 +1,158 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/suppression"
+	mockgithub "github.com/anupsv/git-monitoring/pkg/tools/common/test"
+)
+
+const suppressionFile = `
+suppressions:
+  - monitor: dormant_repositories
+    owner: platform-team
+    justification: Reference implementation kept for history
+    expires: 2030-01-31
+  - monitor: pr_checker
+    subject: "PR #1*"
+    owner: release-team
+    justification: Release PRs are approved out of band
+    expires: 2030-01-31T12:00:00Z
+`
+
+func TestParse(t *testing.T) {
+	file, err := suppression.Parse([]byte(suppressionFile))
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if len(file.Suppressions) != 2 {
+		t.Fatalf("Expected 2 suppressions, got %d", len(file.Suppressions))
+	}
+
+	tests := []struct {
+		name          string
+		content       string
+		errorContains string
+	}{
+		{"Missing monitor", "suppressions:\n  - owner: x\n    justification: x\n    expires: 2030-01-31\n", "monitor is required"},
+		{"Missing owner", "suppressions:\n  - monitor: pr_checker\n    justification: x\n    expires: 2030-01-31\n", "owner is required"},
+		{"Missing justification", "suppressions:\n  - monitor: pr_checker\n    owner: x\n    expires: 2030-01-31\n", "justification is required"},
+		{"Missing expiry", "suppressions:\n  - monitor: pr_checker\n    owner: x\n    justification: x\n", "invalid expiry"},
+		{"Invalid expiry", "suppressions:\n  - monitor: pr_checker\n    owner: x\n    justification: x\n    expires: soon\n", "invalid expiry"},
+		{"Invalid subject", "suppressions:\n  - monitor: pr_checker\n    subject: \"[\"\n    owner: x\n    justification: x\n    expires: 2030-01-31\n", "invalid subject pattern"},
+		{"Invalid YAML", "suppressions: [", "error decoding suppression file"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := suppression.Parse([]byte(tc.content))
+			if err == nil || !strings.Contains(err.Error(), tc.errorContains) {
+				t.Errorf("Expected error containing %q, got %v", tc.errorContains, err)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	file, err := suppression.Parse([]byte(suppressionFile))
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	now := time.Date(2030, 1, 31, 23, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		finding    findings.Finding
+		now        time.Time
+		suppressed bool
+	}{
+		{"Any subject", findings.Finding{Monitor: "dormant_repositories", Subject: "activity"}, now, true},
+		{"Matching subject", findings.Finding{Monitor: "pr_checker", Subject: "PR #12"}, now.Add(-12 * time.Hour), true},
+		{"Expired timestamp", findings.Finding{Monitor: "pr_checker", Subject: "PR #12"}, now, false},
+		{"Other subject", findings.Finding{Monitor: "pr_checker", Subject: "PR #2"}, now, false},
+		{"Other monitor", findings.Finding{Monitor: "repo_visibility", Subject: "visibility"}, now, false},
+		{"Expired", findings.Finding{Monitor: "dormant_repositories", Subject: "activity"}, now.Add(time.Hour), false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := file.Match(tc.finding, tc.now) != nil; got != tc.suppressed {
+				t.Errorf("Expected suppressed to be %v, got %v", tc.suppressed, got)
+			}
+		})
+	}
+
+	// Repositories without a file suppress nothing
+	var none *suppression.File
+	if none.Match(findings.Finding{Monitor: "pr_checker"}, now) != nil {
+		t.Error("Expected no suppression without a file")
+	}
+}
+
+func TestLoader(t *testing.T) {
+	mockClient := &mockgithub.MockGitHubClient{
+		MockFileContents: map[string]string{
+			"owner/suppressed/.git-monitor.yml": suppressionFile,
+			"owner/invalid/.git-monitor.yml":    "suppressions:\n  - monitor: dormant_repositories\n",
+		},
+	}
+	loader := suppression.NewLoader(mockClient, ".git-monitor.yml")
+	ctx := context.Background()
+
+	dormant := func(repo string) findings.Finding {
+		return findings.Finding{Monitor: "dormant_repositories", Repository: repo, Subject: "activity"}
+	}
+
+	if rule := loader.Match(ctx, dormant("owner/suppressed")); rule == nil || rule.Owner != "platform-team" {
+		t.Errorf("Expected the finding to be suppressed by the repository's file, got %+v", rule)
+	}
+	if loader.Match(ctx, dormant("owner/other")) != nil {
+		t.Error("Expected findings of repositories without a file to be reported")
+	}
+	if loader.Match(ctx, dormant("owner/invalid")) != nil {
+		t.Error("Expected findings of repositories with an invalid file to be reported")
+	}
+	if loader.Match(ctx, findings.Finding{Monitor: "dormant_accounts", Repository: "org:owner", Subject: "alice"}) != nil {
+		t.Error("Expected organization-wide findings to be reported")
+	}
+
+	// Each repository's file is only fetched once
+	loader.Match(ctx, dormant("owner/suppressed"))
+	loader.Match(ctx, dormant("owner/invalid"))
+	if mockClient.GetFileContentCalls != 3 {
+		t.Errorf("Expected 3 file lookups, got %d", mockClient.GetFileContentCalls)
+	}
+
+	// Errors fetching the file do not suppress findings
+	failing := suppression.NewLoader(&mockgithub.MockGitHubClient{MockFileContentErr: errors.New("API error")}, ".git-monitor.yml")
+	if failing.Match(ctx, dormant("owner/suppressed")) != nil {
+		t.Error("Expected findings to be reported when the file cannot be fetched")
+	}
+}
+
+func TestExpiringSoon(t *testing.T) {
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	suppressed := func(subject string, expires time.Time) suppression.Suppression {
+		return suppression.Suppression{
+			Finding: findings.Finding{Monitor: "pr_checker", Repository: "owner/repo", Subject: subject},
+			Owner:   "alice",
+			Expires: expires,
+		}
+	}
+
+	expiring := suppression.ExpiringSoon([]suppression.Suppression{
+		suppressed("later", now.Add(30*24*time.Hour)),
+		suppressed("friday", now.Add(4*24*time.Hour)),
+		suppressed("tomorrow", now.Add(24*time.Hour)),
+		suppressed("expired", now.Add(-time.Hour)),
+	}, now, 7*24*time.Hour)
+
+	if len(expiring) != 2 || expiring[0].Finding.Subject != "tomorrow" || expiring[1].Finding.Subject != "friday" {
+		t.Errorf("Expected the suppressions expiring within 7 days, soonest first, got %+v", expiring)
+	}
+}
@@ -0,0 +1,278 @@
+package adminenforcement
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	"github.com/google/go-github/v45/github"
+)
+
+const (
+	// DefaultCheckWindow is the default time window to look for branch protection overrides
+	DefaultCheckWindow = 7 * 24 * time.Hour
+
+	// overrideAction is the audit log action of an administrator merging or pushing past branch protection
+	overrideAction = "protected_branch.policy_override"
+)
+
+// Repository is a repository whose branch protection administrators can bypass, with how often they did
+type Repository struct {
+	Name       string
+	Branches   []string  // Protected branches whose protection does not apply to administrators
+	Overrides  int       // Branch protection overrides within the check window
+	Overriders []string  // Who overrode branch protection, most overrides first
+	LastAt     time.Time // Last override, zero without overrides
+	URL        string
+}
+
+// Checker is a service that reports protected branches whose protection does not apply to administrators
+type Checker struct {
+	client      common.GitHubClientInterface
+	checkWindow time.Duration
+	config      *config.Config
+}
+
+// NewAdminEnforcementChecker creates a new Checker
+func NewAdminEnforcementChecker(client common.GitHubClientInterface, config *config.Config) *Checker {
+	checkWindow := DefaultCheckWindow
+	if config.Monitors.AdminEnforcement.CheckWindow.Duration > 0 {
+		checkWindow = config.Monitors.AdminEnforcement.CheckWindow.Duration
+	}
+
+	return &Checker{
+		client:      client,
+		checkWindow: checkWindow,
+		config:      config,
+	}
+}
+
+// Run checks all configured organizations, returning their repositories ranked by how often administrators
+// bypassed branch protection, then by how many of their branches they can bypass
+func (c *Checker) Run(ctx context.Context) ([]Repository, error) {
+	allRepos := make([]Repository, 0)
+
+	for _, org := range c.config.Monitors.AdminEnforcement.Organizations {
+		if common.SkipIfStopped(ctx, "org:"+org) {
+			continue
+		}
+		repos, err := c.CheckOrganization(ctx, org)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, "org:"+org, err) {
+				continue
+			}
+			log.Printf("Error checking admin enforcement for organization %s: %v", org, err)
+			continue
+		}
+		allRepos = append(allRepos, repos...)
+	}
+
+	Rank(allRepos)
+	return allRepos, nil
+}
+
+// CheckOrganization reports the repositories of an organization with protected branches whose protection
+// does not apply to administrators, with the overrides of branch protection in the audit log
+// Archived repositories take no pushes and are skipped
+func (c *Checker) CheckOrganization(ctx context.Context, org string) ([]Repository, error) {
+	log.Printf("Checking admin enforcement of protected branches in %s organization", org)
+
+	repos, err := c.client.ListOrganizationRepositories(ctx, org, "all")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization repositories: %w", err)
+	}
+	repos, filtered := common.FilterRepositories(ctx, repos, c.config.RepoFilters)
+	if filtered > 0 {
+		log.Printf("Filtered out %d repositories of %s not matching repo_filters", filtered, org)
+	}
+
+	overrides, err := c.listOverrides(ctx, org)
+	if err != nil {
+		// The audit log needs GitHub Enterprise Cloud, so the repositories are still reported without it
+		log.Printf("Error listing branch protection overrides of organization %s, reporting without them: %v", org, err)
+	}
+
+	exempt := make([]Repository, 0)
+	for _, repo := range repos {
+		if repo.GetArchived() {
+			continue
+		}
+
+		result, err := c.CheckRepository(ctx, repo.GetFullName())
+		if err != nil {
+			log.Printf("Error checking admin enforcement of repository %s: %v", repo.GetFullName(), err)
+			continue
+		}
+		if result == nil {
+			continue
+		}
+
+		result.URL = repo.GetHTMLURL()
+		addOverrides(result, overrides[strings.ToLower(repo.GetFullName())])
+		exempt = append(exempt, *result)
+	}
+
+	return exempt, nil
+}
+
+// CheckRepository returns the protected branches of a repository whose protection does not apply to
+// administrators, nil when there are none
+func (c *Checker) CheckRepository(ctx context.Context, repository string) (*Repository, error) {
+	owner, repo, ok := common.ParseRepository(repository)
+	if !ok {
+		return nil, fmt.Errorf("invalid repository name %q", repository)
+	}
+
+	branches, err := c.client.ListProtectedBranches(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var exempt []string
+	for _, branch := range branches {
+		enforced, err := c.client.GetAdminEnforcement(ctx, owner, repo, branch.GetName())
+		if err != nil {
+			return nil, err
+		}
+		if !enforced {
+			exempt = append(exempt, branch.GetName())
+		}
+	}
+	if len(exempt) == 0 {
+		return nil, nil
+	}
+
+	return &Repository{Name: repository, Branches: exempt}, nil
+}
+
+// listOverrides returns the branch protection overrides of an organization within the check window,
+// by lowercase repository name
+func (c *Checker) listOverrides(ctx context.Context, org string) (map[string][]*github.AuditEntry, error) {
+	now := time.Now()
+	since := common.WindowStart(now, c.checkWindow, c.config.WindowLocation())
+	until := common.WindowEnd(now, c.checkWindow, c.config.WindowLocation())
+	phrase := fmt.Sprintf("action:%s created:>=%s", overrideAction, since.UTC().Format("2006-01-02"))
+
+	entries, err := c.client.ListAuditLog(ctx, org, phrase)
+	if err != nil {
+		return nil, err
+	}
+
+	// The search matches whole days, so overrides earlier on the first day, or since the window ended, are left out here
+	overrides := make(map[string][]*github.AuditEntry)
+	for _, entry := range entries {
+		if entry.GetAction() != overrideAction || entry.GetCreatedAt().Before(since) || !entry.GetCreatedAt().Before(until) {
+			continue
+		}
+		repo := strings.ToLower(entry.GetRepo())
+		overrides[repo] = append(overrides[repo], entry)
+	}
+	return overrides, nil
+}
+
+// addOverrides records the overrides of a repository, naming those who overrode most often first
+func addOverrides(repo *Repository, entries []*github.AuditEntry) {
+	counts := make(map[string]int)
+	for _, entry := range entries {
+		actor := entry.GetActor()
+		if counts[actor] == 0 {
+			repo.Overriders = append(repo.Overriders, actor)
+		}
+		counts[actor]++
+		if at := entry.GetCreatedAt().Time; at.After(repo.LastAt) {
+			repo.LastAt = at
+		}
+	}
+	repo.Overrides = len(entries)
+
+	sort.SliceStable(repo.Overriders, func(i, j int) bool {
+		return counts[repo.Overriders[i]] > counts[repo.Overriders[j]]
+	})
+}
+
+// Rank orders repositories by their branch protection overrides, then by how many branches administrators
+// can bypass, then by name
+func Rank(repos []Repository) {
+	sort.SliceStable(repos, func(i, j int) bool {
+		if repos[i].Overrides != repos[j].Overrides {
+			return repos[i].Overrides > repos[j].Overrides
+		}
+		if len(repos[i].Branches) != len(repos[j].Branches) {
+			return len(repos[i].Branches) > len(repos[j].Branches)
+		}
+		return repos[i].Name < repos[j].Name
+	})
+}
+
+// Summary describes which branches administrators can bypass and how often they did
+func (r Repository) Summary() string {
+	summary := fmt.Sprintf("protection of %s not enforced for administrators", strings.Join(r.Branches, ", "))
+	if r.Overrides == 0 {
+		return summary
+	}
+	return fmt.Sprintf("%s, overridden %d times by %s", summary, r.Overrides, strings.Join(r.Overriders, ", "))
+}
+
+// Findings converts repositories whose branch protection administrators can bypass into findings
+func Findings(repos []Repository) []findings.Finding {
+	list := make([]findings.Finding, 0, len(repos))
+	for _, r := range repos {
+		list = append(list, findings.Finding{
+			Monitor:    "admin_enforcement",
+			Repository: r.Name,
+			Subject:    "enforce_admins",
+			Summary:    r.Summary(),
+			URL:        r.URL + "/settings/branches",
+		})
+	}
+	return list
+}
+
+// WriteResultsMarkdown writes the ranked repositories whose branch protection administrators can bypass
+// in a code block format suitable for Slack notifications
+func WriteResultsMarkdown(w io.Writer, repos []Repository) {
+	if len(repos) == 0 {
+		return // No results to display
+	}
+
+	overridden := 0
+	for _, r := range repos {
+		if r.Overrides > 0 {
+			overridden++
+		}
+	}
+
+	// Print header for repositories administrators can bypass
+	fmt.Fprintln(w, "## :crown: Branch Protection Not Enforced for Administrators")
+	fmt.Fprintf(w, "Found %d repositories whose branch protection administrators can bypass, %d with overrides in the audit log.\n\n", len(repos), overridden)
+
+	// Start code block
+	fmt.Fprintln(w, "```")
+	// Create fixed-width headers with proper spacing for code block
+	fmt.Fprintln(w, "Repository                Overrides  Branches")
+	fmt.Fprintln(w, "---------------------------------------------------------------------")
+
+	// Print each repository in a fixed-width format for code blocks, most overridden first
+	for _, r := range repos {
+		// Format repository name with padding
+		repoStr := r.Name
+		if len(repoStr) > 24 {
+			repoStr = repoStr[:21] + "..."
+		} else {
+			repoStr = fmt.Sprintf("%-24s", repoStr)
+		}
+
+		fmt.Fprintf(w, "%s  %9d  %s\n", repoStr, r.Overrides, strings.Join(r.Branches, ", "))
+	}
+
+	// End code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
@@ -0,0 +1,124 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/tools/adminenforcement"
+	mockgithub "github.com/anupsv/git-monitoring/pkg/tools/common/test"
+)
+
+// createRepo creates a repository of testorg
+func createRepo(name string) *github.Repository {
+	return &github.Repository{
+		FullName: github.String("testorg/" + name),
+		HTMLURL:  github.String("https://github.com/testorg/" + name),
+	}
+}
+
+// createBranches creates protected branches
+func createBranches(names ...string) []*github.Branch {
+	branches := make([]*github.Branch, 0, len(names))
+	for _, name := range names {
+		branches = append(branches, &github.Branch{Name: github.String(name), Protected: github.Bool(true)})
+	}
+	return branches
+}
+
+// createOverride creates an audit log entry of an administrator overriding branch protection
+func createOverride(repo, actor string, at time.Time) *github.AuditEntry {
+	return &github.AuditEntry{
+		Action:    github.String("protected_branch.policy_override"),
+		Actor:     github.String(actor),
+		Repo:      github.String(repo),
+		CreatedAt: &github.Timestamp{Time: at},
+	}
+}
+
+// newConfig creates a configuration auditing testorg
+func newConfig() *config.Config {
+	return &config.Config{
+		Monitors: config.MonitorsConfig{
+			AdminEnforcement: config.AdminEnforcementConfig{
+				Enabled:       true,
+				Organizations: []string{"testorg"},
+				CheckWindow:   config.Hours(7 * 24),
+			},
+		},
+	}
+}
+
+func TestRunRanksRepositories(t *testing.T) {
+	now := time.Now()
+	mockClient := &mockgithub.MockGitHubClient{
+		MockOrgRepositories: []*github.Repository{
+			createRepo("enforced"),
+			createRepo("exempt"),
+			createRepo("overridden"),
+			createRepo("two-branches"),
+		},
+		MockProtectedBranches: map[string][]*github.Branch{
+			"testorg/enforced":     createBranches("main"),
+			"testorg/exempt":       createBranches("main"),
+			"testorg/overridden":   createBranches("main"),
+			"testorg/two-branches": createBranches("main", "release"),
+		},
+		MockAdminEnforcement: map[string]bool{"testorg/enforced:main": true},
+		MockAuditLog: []*github.AuditEntry{
+			createOverride("testorg/overridden", "alice", now.Add(-time.Hour)),
+			createOverride("testorg/Overridden", "bob", now.Add(-2*time.Hour)),
+			createOverride("testorg/overridden", "bob", now.Add(-3*time.Hour)),
+			// Outside the check window
+			createOverride("testorg/exempt", "alice", now.Add(-30*24*time.Hour)),
+		},
+	}
+
+	checker := adminenforcement.NewAdminEnforcementChecker(mockClient, newConfig())
+	repos, err := checker.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	var names []string
+	for _, r := range repos {
+		names = append(names, r.Name)
+	}
+	if got := strings.Join(names, ","); got != "testorg/overridden,testorg/two-branches,testorg/exempt" {
+		t.Fatalf("Unexpected ranking %s", got)
+	}
+
+	if summary := repos[0].Summary(); summary != "protection of main not enforced for administrators, overridden 3 times by bob, alice" {
+		t.Errorf("Unexpected summary %q", summary)
+	}
+	if repos[2].Overrides != 0 {
+		t.Errorf("Expected overrides outside the check window to be left out, got %+v", repos[2])
+	}
+	if mockClient.ListAuditLogCalls != 1 {
+		t.Errorf("Expected the audit log to be listed once, got %d calls", mockClient.ListAuditLogCalls)
+	}
+}
+
+func TestRunWithoutAuditLog(t *testing.T) {
+	mockClient := &mockgithub.MockGitHubClient{
+		MockOrgRepositories:   []*github.Repository{createRepo("exempt")},
+		MockProtectedBranches: map[string][]*github.Branch{"testorg/exempt": createBranches("main")},
+		MockAuditLogErr:       errors.New("audit log not available"),
+	}
+
+	checker := adminenforcement.NewAdminEnforcementChecker(mockClient, newConfig())
+	repos, err := checker.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	// Repositories are still reported when the audit log is not available
+	if len(repos) != 1 || repos[0].Overrides != 0 {
+		t.Errorf("Expected the repository without overrides, got %+v", repos)
+	}
+}
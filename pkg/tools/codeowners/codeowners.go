@@ -0,0 +1,107 @@
+package codeowners
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Locations are where GitHub looks for the CODEOWNERS file of a repository, the first one found is used
+var Locations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// Rule is a line of a CODEOWNERS file: a pattern and the owners of the paths it matches
+type Rule struct {
+	Pattern string
+	Owners  []string // Empty for rules that leave the matched paths without owners
+	match   *regexp.Regexp
+}
+
+// Parse parses the rules of a CODEOWNERS file, in the order of the file
+func Parse(content string) []Rule {
+	var rules []Rule
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		rules = append(rules, Rule{Pattern: fields[0], Owners: fields[1:], match: compilePattern(fields[0])})
+	}
+	return rules
+}
+
+// Owned reports whether a path has owners
+func Owned(rules []Rule, path string) bool {
+	return len(Owners(rules, path)) > 0
+}
+
+// Owners returns the owners of a path: the last rule matching it wins, as on GitHub
+func Owners(rules []Rule, path string) []string {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if rules[i].match.MatchString(path) {
+			return rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// Patterns are compiled patterns in CODEOWNERS syntax, e.g. the critical paths of repositories
+type Patterns []*regexp.Regexp
+
+// CompilePatterns compiles patterns in CODEOWNERS syntax
+func CompilePatterns(patterns []string) Patterns {
+	compiled := make(Patterns, 0, len(patterns))
+	for _, pattern := range patterns {
+		compiled = append(compiled, compilePattern(pattern))
+	}
+	return compiled
+}
+
+// Match reports whether any of the patterns matches a path
+func (p Patterns) Match(path string) bool {
+	for _, pattern := range p {
+		if pattern.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// compilePattern compiles a CODEOWNERS pattern, which follows the gitignore rules: a pattern with a slash
+// other than a trailing one is relative to the root, others match at any depth. A pattern matches a path
+// or the directories above it, and one ending in "/" matches directories only
+func compilePattern(pattern string) *regexp.Regexp {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	trimmed := strings.Trim(pattern, "/")
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(trimmed, "/")
+
+	var expr strings.Builder
+	if anchored {
+		expr.WriteString("^")
+	} else {
+		expr.WriteString("^(.*/)?")
+	}
+	for i := 0; i < len(trimmed); i++ {
+		switch {
+		case strings.HasPrefix(trimmed[i:], "**/"):
+			expr.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(trimmed[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case trimmed[i] == '*':
+			expr.WriteString("[^/]*")
+		case trimmed[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(trimmed[i : i+1]))
+		}
+	}
+	if dirOnly {
+		expr.WriteString("/.*$")
+	} else {
+		expr.WriteString("(/.*)?$")
+	}
+	return regexp.MustCompile(expr.String())
+}
@@ -0,0 +1,298 @@
+package codeowners
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	"github.com/google/go-github/v45/github"
+)
+
+const (
+	// DefaultMinCoverage is the default share of files CODEOWNERS must cover, in percent
+	DefaultMinCoverage = 80.0
+
+	// DefaultSampleSize is the default number of files sampled per repository
+	DefaultSampleSize = 1000
+
+	// maxUncoveredExamples is how many uncovered critical paths are named per repository
+	maxUncoveredExamples = 5
+)
+
+// Repository is a repository whose CODEOWNERS file leaves too many files, or critical files, without owners
+type Repository struct {
+	Name              string
+	File              string   // Location of the CODEOWNERS file, empty without one
+	Sampled           int      // Files sampled from the tree
+	Covered           int      // Sampled files with owners
+	UncoveredCritical []string // Sampled files under critical paths without owners, at most a few
+	URL               string
+}
+
+// Coverage returns the share of the sampled files with owners, in percent
+func (r Repository) Coverage() float64 {
+	if r.Sampled == 0 {
+		return 0
+	}
+	return float64(r.Covered) * 100 / float64(r.Sampled)
+}
+
+// Summary describes the CODEOWNERS coverage of the repository
+func (r Repository) Summary() string {
+	summary := "no CODEOWNERS file"
+	if r.File != "" {
+		summary = fmt.Sprintf("CODEOWNERS covers %.0f%% of %d sampled files", r.Coverage(), r.Sampled)
+	}
+	if len(r.UncoveredCritical) > 0 {
+		summary += fmt.Sprintf(", critical paths without owners: %s", strings.Join(r.UncoveredCritical, ", "))
+	}
+	return summary
+}
+
+// Checker is a service that reports repositories whose CODEOWNERS file covers too little of their tree
+type Checker struct {
+	client      common.GitHubClientInterface
+	minCoverage float64
+	sampleSize  int
+	config      *config.Config
+}
+
+// NewCodeownersChecker creates a new Checker
+func NewCodeownersChecker(client common.GitHubClientInterface, config *config.Config) *Checker {
+	minCoverage := DefaultMinCoverage
+	if config.Monitors.Codeowners.MinCoverage > 0 {
+		minCoverage = config.Monitors.Codeowners.MinCoverage
+	}
+	sampleSize := DefaultSampleSize
+	if config.Monitors.Codeowners.SampleSize > 0 {
+		sampleSize = config.Monitors.Codeowners.SampleSize
+	}
+
+	return &Checker{
+		client:      client,
+		minCoverage: minCoverage,
+		sampleSize:  sampleSize,
+		config:      config,
+	}
+}
+
+// Run checks all configured organizations and repositories for CODEOWNERS coverage below the threshold
+func (c *Checker) Run(ctx context.Context) ([]Repository, error) {
+	allRepos := make([]Repository, 0)
+
+	for _, org := range c.config.Monitors.Codeowners.Organizations {
+		if common.SkipIfStopped(ctx, "org:"+org) {
+			continue
+		}
+		repos, err := c.CheckOrganization(ctx, org)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, "org:"+org, err) {
+				continue
+			}
+			log.Printf("Error checking CODEOWNERS coverage for organization %s: %v", org, err)
+			continue
+		}
+		allRepos = append(allRepos, repos...)
+	}
+
+	for _, repository := range c.config.Monitors.Codeowners.Repositories {
+		if common.SkipIfStopped(ctx, repository) {
+			continue
+		}
+		result, err := c.CheckRepository(ctx, repository)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, repository, err) {
+				continue
+			}
+			log.Printf("Error checking CODEOWNERS coverage for repository %s: %v", repository, err)
+			continue
+		}
+		if result != nil {
+			allRepos = append(allRepos, *result)
+		}
+	}
+
+	return allRepos, nil
+}
+
+// CheckOrganization reports the repositories of an organization whose CODEOWNERS coverage is below the threshold
+// Archived repositories are no longer changed and are skipped
+func (c *Checker) CheckOrganization(ctx context.Context, org string) ([]Repository, error) {
+	log.Printf("Checking CODEOWNERS coverage of repositories in %s organization", org)
+
+	repos, err := c.client.ListOrganizationRepositories(ctx, org, "all")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization repositories: %w", err)
+	}
+	repos, filtered := common.FilterRepositories(ctx, repos, c.config.RepoFilters)
+	if filtered > 0 {
+		log.Printf("Filtered out %d repositories of %s not matching repo_filters", filtered, org)
+	}
+
+	uncovered := make([]Repository, 0)
+	for _, repo := range repos {
+		if repo.GetArchived() {
+			continue
+		}
+		result, err := c.checkRepository(ctx, repo)
+		if err != nil {
+			log.Printf("Error checking CODEOWNERS coverage for repository %s: %v", repo.GetFullName(), err)
+			continue
+		}
+		if result != nil {
+			uncovered = append(uncovered, *result)
+		}
+	}
+
+	return uncovered, nil
+}
+
+// CheckRepository checks the CODEOWNERS coverage of a single repository given as "owner/repo"
+func (c *Checker) CheckRepository(ctx context.Context, repository string) (*Repository, error) {
+	owner, name, ok := common.ParseRepository(repository)
+	if !ok {
+		return nil, fmt.Errorf("invalid repository format %s, expected 'owner/repo'", repository)
+	}
+
+	repo, err := c.client.GetRepository(ctx, owner, name)
+	if err != nil {
+		return nil, err
+	}
+	if repo == nil {
+		return nil, fmt.Errorf("repository %s not found", repository)
+	}
+	return c.checkRepository(ctx, repo)
+}
+
+// checkRepository samples the files of a repository's default branch and returns the repository when
+// CODEOWNERS covers less of them than the threshold or leaves a critical path without owners, nil otherwise
+func (c *Checker) checkRepository(ctx context.Context, repo *github.Repository) (*Repository, error) {
+	owner, name, ok := common.ParseRepository(repo.GetFullName())
+	if !ok {
+		return nil, fmt.Errorf("invalid repository name %q", repo.GetFullName())
+	}
+
+	branch := repo.GetDefaultBranch()
+	if branch == "" {
+		branch = "HEAD"
+	}
+	files, truncated, err := c.client.ListRepositoryFiles(ctx, owner, name, branch)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, nil // Empty repositories have nothing to own
+	}
+	if truncated {
+		log.Printf("File list of %s is truncated, sampling the files GitHub returned", repo.GetFullName())
+	}
+
+	result := &Repository{Name: repo.GetFullName(), URL: repo.GetHTMLURL()}
+	var rules []Rule
+	for _, location := range Locations {
+		content, err := c.client.GetFileContent(ctx, owner, name, location)
+		if err != nil {
+			return nil, err
+		}
+		if content != nil {
+			result.File = location
+			rules = Parse(string(content))
+			break
+		}
+	}
+
+	critical := CompilePatterns(c.config.Monitors.Codeowners.CriticalPaths)
+	for _, path := range sampleFiles(files, c.sampleSize) {
+		result.Sampled++
+		if Owned(rules, path) {
+			result.Covered++
+			continue
+		}
+		if len(result.UncoveredCritical) < maxUncoveredExamples && critical.Match(path) {
+			result.UncoveredCritical = append(result.UncoveredCritical, path)
+		}
+	}
+
+	if result.Coverage() >= c.minCoverage && len(result.UncoveredCritical) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// sampleFiles picks up to size files spread evenly over the list, so every directory is represented
+// and repeated runs sample the same files of an unchanged tree
+func sampleFiles(files []string, size int) []string {
+	if len(files) <= size {
+		return files
+	}
+
+	sample := make([]string, 0, size)
+	for i := 0; i < size; i++ {
+		sample = append(sample, files[i*len(files)/size])
+	}
+	return sample
+}
+
+// Findings converts repositories with too little CODEOWNERS coverage into findings
+func Findings(repos []Repository) []findings.Finding {
+	list := make([]findings.Finding, 0, len(repos))
+	for _, r := range repos {
+		list = append(list, findings.Finding{
+			Monitor:    "codeowners_coverage",
+			Repository: r.Name,
+			Subject:    "codeowners",
+			Summary:    r.Summary(),
+			URL:        r.URL,
+		})
+	}
+	return list
+}
+
+// WriteResultsMarkdown writes repositories with too little CODEOWNERS coverage in a code block format
+// suitable for Slack notifications
+func WriteResultsMarkdown(w io.Writer, repos []Repository) {
+	if len(repos) == 0 {
+		return // No results to display
+	}
+
+	// Print header for repositories without enough owners
+	fmt.Fprintln(w, "## :busts_in_silhouette: CODEOWNERS Coverage Gaps")
+	fmt.Fprintf(w, "Found %d repositories whose CODEOWNERS file leaves files without reviewers.\n\n", len(repos))
+
+	// Start code block
+	fmt.Fprintln(w, "```")
+	// Create fixed-width headers with proper spacing for code block
+	fmt.Fprintln(w, "Repository                Coverage  Sampled  Critical paths without owners")
+	fmt.Fprintln(w, "---------------------------------------------------------------------")
+
+	// Print each repository in a fixed-width format for code blocks
+	for _, r := range repos {
+		// Format repository name with padding
+		repoStr := r.Name
+		if len(repoStr) > 24 {
+			repoStr = repoStr[:21] + "..."
+		} else {
+			repoStr = fmt.Sprintf("%-24s", repoStr)
+		}
+
+		coverageStr := "none"
+		if r.File != "" {
+			coverageStr = fmt.Sprintf("%.0f%%", r.Coverage())
+		}
+		criticalStr := strings.Join(r.UncoveredCritical, ", ")
+		if criticalStr == "" {
+			criticalStr = "-"
+		}
+
+		fmt.Fprintf(w, "%s  %8s  %7d  %s\n", repoStr, coverageStr, r.Sampled, criticalStr)
+	}
+
+	// End code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
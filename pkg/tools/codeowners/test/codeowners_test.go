@@ -0,0 +1,132 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-github/v45/github"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/tools/codeowners"
+	mockgithub "github.com/anupsv/git-monitoring/pkg/tools/common/test"
+)
+
+func TestOwned(t *testing.T) {
+	rules := codeowners.Parse(`# Owners of the monorepo
+*.md          @acme/docs
+/infra/       @acme/sre
+apps/*/api    @acme/backend # APIs of every app
+**/secrets    @acme/security
+/infra/sandbox/
+`)
+
+	tests := []struct {
+		path  string
+		owned bool
+	}{
+		{"README.md", true},
+		{"docs/guide.md", true},
+		{"infra/main.tf", true},
+		{"infra/modules/vpc/main.tf", true},
+		{"services/infra/main.go", false}, // Anchored to the root
+		{"infra/sandbox/main.tf", false},  // Last matching rule has no owners
+		{"apps/web/api/handler.go", true},
+		{"apps/web/ui/index.ts", false},
+		{"config/prod/secrets/db.yaml", true},
+		{"main.go", false},
+	}
+
+	for _, tc := range tests {
+		if owned := codeowners.Owned(rules, tc.path); owned != tc.owned {
+			t.Errorf("Expected %s owned %v, got %v", tc.path, tc.owned, owned)
+		}
+	}
+}
+
+func TestCheckOrganization(t *testing.T) {
+	repo := func(name string) *github.Repository {
+		return &github.Repository{
+			FullName:      github.String("testorg/" + name),
+			HTMLURL:       github.String("https://github.com/testorg/" + name),
+			DefaultBranch: github.String("main"),
+		}
+	}
+
+	mockClient := &mockgithub.MockGitHubClient{
+		MockOrgRepositories: []*github.Repository{repo("owned"), repo("partial"), repo("unowned"), repo("empty")},
+		MockRepoFiles: map[string][]string{
+			"testorg/owned":   {"README.md", "main.go", "infra/main.tf"},
+			"testorg/partial": {"README.md", "main.go", "infra/main.tf", "docs/guide.md"},
+			"testorg/unowned": {"main.go"},
+		},
+		MockFileContents: map[string]string{
+			"testorg/owned/.github/CODEOWNERS": "* @acme/team\n",
+			"testorg/partial/CODEOWNERS":       "* @acme/team\n/infra/\n",
+		},
+	}
+
+	cfg := &config.Config{
+		Monitors: config.MonitorsConfig{
+			Codeowners: config.CodeownersConfig{
+				Enabled:       true,
+				Organizations: []string{"testorg"},
+				MinCoverage:   50,
+				SampleSize:    1000,
+				CriticalPaths: []string{"/infra/"},
+			},
+		},
+	}
+
+	checker := codeowners.NewCodeownersChecker(mockClient, cfg)
+	repos, err := checker.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	if len(repos) != 2 {
+		t.Fatalf("Expected 2 repositories, got %+v", repos)
+	}
+
+	// Above the threshold, but a critical path has no owners
+	if summary := repos[0].Summary(); repos[0].Name != "testorg/partial" ||
+		summary != "CODEOWNERS covers 75% of 4 sampled files, critical paths without owners: infra/main.tf" {
+		t.Errorf("Unexpected repository %s: %s", repos[0].Name, summary)
+	}
+	if summary := repos[1].Summary(); repos[1].Name != "testorg/unowned" || summary != "no CODEOWNERS file" {
+		t.Errorf("Unexpected repository %s: %s", repos[1].Name, summary)
+	}
+}
+
+func TestSampleSize(t *testing.T) {
+	files := make([]string, 0, 100)
+	for i := 0; i < 100; i++ {
+		files = append(files, "src/file.go")
+	}
+
+	mockClient := &mockgithub.MockGitHubClient{
+		MockRepository: map[string]*github.Repository{
+			"testorg/big": {FullName: github.String("testorg/big"), DefaultBranch: github.String("main")},
+		},
+		MockRepoFiles: map[string][]string{"testorg/big": files},
+	}
+
+	cfg := &config.Config{
+		Monitors: config.MonitorsConfig{
+			Codeowners: config.CodeownersConfig{
+				Enabled:      true,
+				Repositories: []string{"testorg/big"},
+				MinCoverage:  80,
+				SampleSize:   10,
+			},
+		},
+	}
+
+	checker := codeowners.NewCodeownersChecker(mockClient, cfg)
+	repo, err := checker.CheckRepository(context.Background(), "testorg/big")
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if repo == nil || repo.Sampled != 10 {
+		t.Errorf("Expected 10 sampled files, got %+v", repo)
+	}
+}
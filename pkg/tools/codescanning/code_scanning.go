@@ -0,0 +1,223 @@
+package codescanning
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	"github.com/google/go-github/v45/github"
+)
+
+const (
+	// DefaultCheckWindow is the default time window to look for dismissed alerts
+	DefaultCheckWindow = 24 * time.Hour
+)
+
+// Dismissal represents a code scanning alert that was dismissed
+type Dismissal struct {
+	Repository  string
+	Number      int
+	Rule        string
+	Severity    string
+	DismissedBy string
+	Reason      string
+	DismissedAt time.Time
+	URL         string
+}
+
+// Checker is a service that reports code scanning alerts dismissed within the check window
+type Checker struct {
+	client      common.GitHubClientInterface
+	checkWindow time.Duration
+	config      *config.Config
+}
+
+// NewCodeScanningChecker creates a new Checker
+func NewCodeScanningChecker(client common.GitHubClientInterface, config *config.Config) *Checker {
+	checkWindow := DefaultCheckWindow
+	if config.Monitors.CodeScanning.CheckWindow.Duration > 0 {
+		checkWindow = config.Monitors.CodeScanning.CheckWindow.Duration
+	}
+
+	return &Checker{
+		client:      client,
+		checkWindow: checkWindow,
+		config:      config,
+	}
+}
+
+// Run checks all configured organizations and repositories for dismissed alerts
+func (c *Checker) Run(ctx context.Context) ([]Dismissal, error) {
+	allDismissals := make([]Dismissal, 0)
+
+	for _, org := range c.config.Monitors.CodeScanning.Organizations {
+		if common.SkipIfStopped(ctx, "org:"+org) {
+			continue
+		}
+		dismissals, err := c.CheckOrganization(ctx, org)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, "org:"+org, err) {
+				continue
+			}
+			log.Printf("Error checking code scanning alerts for organization %s: %v", org, err)
+			continue
+		}
+		allDismissals = append(allDismissals, dismissals...)
+	}
+
+	for _, repository := range c.config.Monitors.CodeScanning.Repositories {
+		if common.SkipIfStopped(ctx, repository) {
+			continue
+		}
+		dismissals, err := c.CheckRepository(ctx, repository)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, repository, err) {
+				continue
+			}
+			log.Printf("Error checking code scanning alerts for repository %s: %v", repository, err)
+			continue
+		}
+		allDismissals = append(allDismissals, dismissals...)
+	}
+
+	return allDismissals, nil
+}
+
+// CheckOrganization reports code scanning alerts dismissed across an organization
+func (c *Checker) CheckOrganization(ctx context.Context, org string) ([]Dismissal, error) {
+	log.Printf("Checking for dismissed code scanning alerts in %s organization within the last %v", org, c.checkWindow)
+
+	alerts, err := c.client.ListOrganizationCodeScanningAlerts(common.WithPagination(ctx, c.config.Monitors.CodeScanning.Pagination), org, "dismissed")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization code scanning alerts: %w", err)
+	}
+
+	return c.filterDismissals(alerts, ""), nil
+}
+
+// CheckRepository reports code scanning alerts dismissed in a single repository
+func (c *Checker) CheckRepository(ctx context.Context, repository string) ([]Dismissal, error) {
+	owner, repo, ok := common.ParseRepository(repository)
+	if !ok {
+		return nil, fmt.Errorf("invalid repository format, expected 'owner/repo'")
+	}
+
+	log.Printf("Checking for dismissed code scanning alerts in %s within the last %v", repository, c.checkWindow)
+
+	alerts, err := c.client.ListRepositoryCodeScanningAlerts(common.WithPagination(ctx, c.config.Monitors.CodeScanning.Pagination), owner, repo, "dismissed")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repository code scanning alerts: %w", err)
+	}
+
+	return c.filterDismissals(alerts, repository), nil
+}
+
+// filterDismissals keeps the alerts dismissed within the check window
+// The repository name is taken from the alert when it is not known by the caller
+func (c *Checker) filterDismissals(alerts []*github.Alert, repository string) []Dismissal {
+	dismissals := make([]Dismissal, 0)
+	now := time.Now()
+	cutoffTime := common.WindowStart(now, c.checkWindow, c.config.WindowLocation())
+	windowEnd := common.WindowEnd(now, c.checkWindow, c.config.WindowLocation())
+
+	for _, alert := range alerts {
+		if alert.DismissedAt == nil || alert.GetDismissedAt().Before(cutoffTime) || !alert.GetDismissedAt().Before(windowEnd) {
+			continue
+		}
+
+		repoName := repository
+		if repoName == "" {
+			repoName = alert.GetRepository().GetFullName()
+		}
+
+		severity := alert.GetRuleSeverity()
+		if severity == "" {
+			severity = alert.GetRule().GetSeverity()
+		}
+
+		ruleID := alert.GetRuleID()
+		if ruleID == "" {
+			ruleID = alert.GetRule().GetID()
+		}
+
+		dismissals = append(dismissals, Dismissal{
+			Repository:  repoName,
+			Number:      alert.GetNumber(),
+			Rule:        ruleID,
+			Severity:    severity,
+			DismissedBy: alert.GetDismissedBy().GetLogin(),
+			Reason:      alert.GetDismissedReason(),
+			DismissedAt: common.LocalTime(alert.GetDismissedAt().Time, c.config.Location()),
+			URL:         alert.GetHTMLURL(),
+		})
+	}
+
+	return dismissals
+}
+
+// Findings converts dismissed code scanning alerts into findings
+func Findings(dismissals []Dismissal) []findings.Finding {
+	list := make([]findings.Finding, 0, len(dismissals))
+	for _, d := range dismissals {
+		list = append(list, findings.Finding{
+			Monitor:    "code_scanning_dismissals",
+			Repository: d.Repository,
+			Subject:    fmt.Sprintf("alert #%d", d.Number),
+			Summary:    fmt.Sprintf("%s dismissed by %s as %q", d.Rule, d.DismissedBy, d.Reason),
+			URL:        d.URL,
+		})
+	}
+	return list
+}
+
+// WriteResultsMarkdown writes dismissed code scanning alerts in a code block format
+// suitable for Slack notifications
+func WriteResultsMarkdown(w io.Writer, dismissals []Dismissal) {
+	if len(dismissals) == 0 {
+		return // No results to display
+	}
+
+	// Print header for dismissed alerts
+	fmt.Fprintln(w, "## :warning: Dismissed Code Scanning Alerts")
+	fmt.Fprintf(w, "Found %d code scanning alerts that were recently dismissed.\n\n", len(dismissals))
+
+	// Start code block
+	fmt.Fprintln(w, "```")
+	// Create fixed-width headers with proper spacing for code block
+	fmt.Fprintln(w, "Repository                Alert   Dismissed By        Reason          Link")
+	fmt.Fprintln(w, "---------------------------------------------------------------------")
+
+	// Print each dismissal in a fixed-width format for code blocks
+	for _, d := range dismissals {
+		// Format repository name with padding
+		repoStr := d.Repository
+		if len(repoStr) > 24 {
+			repoStr = repoStr[:21] + "..."
+		} else {
+			repoStr = fmt.Sprintf("%-24s", repoStr)
+		}
+
+		// Format alert number
+		alertStr := fmt.Sprintf("#%-6d", d.Number)
+
+		// Format actor with padding
+		actorStr := d.DismissedBy
+		if len(actorStr) > 18 {
+			actorStr = actorStr[:15] + "..."
+		} else {
+			actorStr = fmt.Sprintf("%-18s", actorStr)
+		}
+
+		// Format the output row with fixed-width fields
+		fmt.Fprintf(w, "%s %s %s %-15s %s\n", repoStr, alertStr, actorStr, d.Reason, d.URL)
+	}
+
+	// End code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
@@ -0,0 +1,128 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/tools/codescanning"
+	mockgithub "github.com/anupsv/git-monitoring/pkg/tools/common/test"
+)
+
+func createMockAlert(number int, repo string, dismissedBy, reason string, dismissedAt time.Time) *github.Alert {
+	ruleID := "go/sql-injection"
+	severity := "error"
+	url := "https://github.com/" + repo + "/security/code-scanning/1"
+	return &github.Alert{
+		Number:          &number,
+		Repository:      &github.Repository{FullName: &repo},
+		RuleID:          &ruleID,
+		RuleSeverity:    &severity,
+		DismissedBy:     &github.User{Login: &dismissedBy},
+		DismissedReason: &reason,
+		DismissedAt:     &github.Timestamp{Time: dismissedAt},
+		HTMLURL:         &url,
+	}
+}
+
+func newConfig() *config.Config {
+	return &config.Config{
+		Monitors: config.MonitorsConfig{
+			CodeScanning: config.CodeScanningConfig{
+				Enabled:       true,
+				Organizations: []string{"testorg"},
+				CheckWindow:   config.Hours(24),
+			},
+		},
+	}
+}
+
+func TestCheckOrganization(t *testing.T) {
+	now := time.Now()
+
+	mockClient := &mockgithub.MockGitHubClient{
+		MockOrgCodeScanAlerts: []*github.Alert{
+			createMockAlert(1, "testorg/recent", "alice", "false positive", now.Add(-2*time.Hour)),
+			createMockAlert(2, "testorg/old", "bob", "won't fix", now.Add(-48*time.Hour)),
+		},
+	}
+
+	checker := codescanning.NewCodeScanningChecker(mockClient, newConfig())
+	dismissals, err := checker.CheckOrganization(context.Background(), "testorg")
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	if len(dismissals) != 1 {
+		t.Fatalf("Expected 1 dismissal within the window, got %d", len(dismissals))
+	}
+
+	d := dismissals[0]
+	if d.Repository != "testorg/recent" {
+		t.Errorf("Expected repository %q, got %q", "testorg/recent", d.Repository)
+	}
+	if d.DismissedBy != "alice" {
+		t.Errorf("Expected dismissed by %q, got %q", "alice", d.DismissedBy)
+	}
+	if d.Reason != "false positive" {
+		t.Errorf("Expected reason %q, got %q", "false positive", d.Reason)
+	}
+	if d.Severity != "error" {
+		t.Errorf("Expected severity %q, got %q", "error", d.Severity)
+	}
+}
+
+func TestCheckRepository(t *testing.T) {
+	tests := []struct {
+		name          string
+		repository    string
+		alerts        []*github.Alert
+		alertsErr     error
+		expectError   bool
+		expectedCount int
+	}{
+		{
+			name:          "Recent dismissal",
+			repository:    "owner/repo",
+			alerts:        []*github.Alert{createMockAlert(3, "owner/repo", "carol", "used in tests", time.Now())},
+			expectedCount: 1,
+		},
+		{
+			name:        "Error listing alerts",
+			repository:  "owner/repo",
+			alertsErr:   errors.New("API error"),
+			expectError: true,
+		},
+		{
+			name:        "Invalid repository format",
+			repository:  "invalid-format",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &mockgithub.MockGitHubClient{
+				MockRepoCodeScanAlerts: tc.alerts,
+				MockRepoCodeScanErr:    tc.alertsErr,
+			}
+
+			checker := codescanning.NewCodeScanningChecker(mockClient, newConfig())
+			dismissals, err := checker.CheckRepository(context.Background(), tc.repository)
+
+			if tc.expectError && err == nil {
+				t.Error("Expected an error but got nil")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("Did not expect an error but got: %v", err)
+			}
+			if len(dismissals) != tc.expectedCount {
+				t.Errorf("Expected %d dismissals, got %d", tc.expectedCount, len(dismissals))
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// ListProtectedBranches lists the protected branches of a repository
+func (c *GitHubClient) ListProtectedBranches(ctx context.Context, owner, repo string) ([]*github.Branch, error) {
+	opts := &github.BranchListOptions{
+		Protected:   github.Bool(true),
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var allBranches []*github.Branch
+	for {
+		var branches []*github.Branch
+		var resp *github.Response
+		err := c.ExecuteWithRateLimit(ctx, func() error {
+			var apiErr error
+			branches, resp, apiErr = c.Client.Repositories.ListBranches(ctx, owner, repo, opts)
+			return apiErr
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing protected branches of %s/%s: %v", owner, repo, err)
+		}
+
+		allBranches = append(allBranches, branches...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return allBranches, nil
+}
+
+// GetAdminEnforcement reports whether the protection of a branch applies to administrators too,
+// false when the branch is not protected. Reading it needs admin access to the repository
+func (c *GitHubClient) GetAdminEnforcement(ctx context.Context, owner, repo, branch string) (bool, error) {
+	var enforcement *github.AdminEnforcement
+	err := c.ExecuteWithRateLimit(ctx, func() error {
+		var apiErr error
+		enforcement, _, apiErr = c.Client.Repositories.GetAdminEnforcement(ctx, owner, repo, url.PathEscape(branch))
+		return apiErr
+	})
+
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error getting admin enforcement of branch %s of %s/%s: %v", branch, owner, repo, err)
+	}
+
+	return enforcement.Enabled, nil
+}
+
+// GetRequiredApprovingReviewCount gets how many approving reviews the protection of a branch requires,
+// 0 when the branch is not protected or requires no reviews. Reading it needs admin access to the repository
+func (c *GitHubClient) GetRequiredApprovingReviewCount(ctx context.Context, owner, repo, branch string) (int, error) {
+	var enforcement *github.PullRequestReviewsEnforcement
+	err := c.ExecuteWithRateLimit(ctx, func() error {
+		var apiErr error
+		enforcement, _, apiErr = c.Client.Repositories.GetPullRequestReviewEnforcement(ctx, owner, repo, url.PathEscape(branch))
+		return apiErr
+	})
+
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error getting required reviews of branch %s of %s/%s: %v", branch, owner, repo, err)
+	}
+
+	return enforcement.RequiredApprovingReviewCount, nil
+}
+
+// ListAuditLog lists the audit log events of an organization matching a search phrase, newest first
+// The audit log is only available to owners of organizations on GitHub Enterprise Cloud
+func (c *GitHubClient) ListAuditLog(ctx context.Context, org, phrase string) ([]*github.AuditEntry, error) {
+	opts := &github.GetAuditLogOptions{
+		Phrase:            github.String(phrase),
+		Include:           github.String("all"),
+		ListCursorOptions: github.ListCursorOptions{PerPage: 100},
+	}
+
+	var allEntries []*github.AuditEntry
+	for {
+		var entries []*github.AuditEntry
+		var resp *github.Response
+		err := c.ExecuteWithRateLimit(ctx, func() error {
+			var apiErr error
+			entries, resp, apiErr = c.Client.Organizations.GetAuditLog(ctx, org, opts)
+			return apiErr
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing audit log of organization %s: %v", org, err)
+		}
+
+		allEntries = append(allEntries, entries...)
+
+		// The audit log pages with cursors rather than page numbers
+		if resp.After == "" {
+			break
+		}
+		opts.After = resp.After
+	}
+
+	return allEntries, nil
+}
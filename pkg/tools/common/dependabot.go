@@ -0,0 +1,125 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// DependabotAlert represents a Dependabot alert
+// The Dependabot alerts API is newer than the go-github version we depend on,
+// so the type is declared here and requests are issued through the raw client
+type DependabotAlert struct {
+	Number                int                      `json:"number"`
+	State                 string                   `json:"state"`
+	HTMLURL               string                   `json:"html_url"`
+	Repository            *github.Repository       `json:"repository,omitempty"`
+	Dependency            *DependabotDependency    `json:"dependency,omitempty"`
+	SecurityAdvisory      *DependabotAdvisory      `json:"security_advisory,omitempty"`
+	SecurityVulnerability *DependabotVulnerability `json:"security_vulnerability,omitempty"`
+	DismissedBy           *github.User             `json:"dismissed_by,omitempty"`
+	DismissedReason       string                   `json:"dismissed_reason,omitempty"`
+	DismissedComment      string                   `json:"dismissed_comment,omitempty"`
+	DismissedAt           *github.Timestamp        `json:"dismissed_at,omitempty"`
+}
+
+// DependabotDependency describes the vulnerable dependency of an alert
+type DependabotDependency struct {
+	Package      *DependabotPackage `json:"package,omitempty"`
+	ManifestPath string             `json:"manifest_path,omitempty"`
+}
+
+// DependabotPackage identifies a package in an ecosystem
+type DependabotPackage struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+}
+
+// DependabotAdvisory describes the security advisory behind an alert
+type DependabotAdvisory struct {
+	GHSAID   string `json:"ghsa_id"`
+	CVEID    string `json:"cve_id,omitempty"`
+	Summary  string `json:"summary"`
+	Severity string `json:"severity"`
+}
+
+// DependabotVulnerability describes the vulnerable version range of an alert
+type DependabotVulnerability struct {
+	Severity string `json:"severity"`
+}
+
+// dependabotAlertListOptions specifies the parameters for listing Dependabot alerts
+// The endpoints use cursor based pagination
+type dependabotAlertListOptions struct {
+	State   string `url:"state,omitempty"`
+	PerPage int    `url:"per_page,omitempty"`
+	After   string `url:"after,omitempty"`
+}
+
+// ListOrganizationDependabotAlerts lists Dependabot alerts in the given state across an organization
+func (c *GitHubClient) ListOrganizationDependabotAlerts(ctx context.Context, org, state string) ([]*DependabotAlert, error) {
+	if org == "" {
+		return nil, fmt.Errorf("organization name cannot be empty")
+	}
+
+	alerts, err := c.listDependabotAlerts(ctx, fmt.Sprintf("orgs/%s/dependabot/alerts", org), state)
+	if err != nil {
+		return nil, fmt.Errorf("error listing Dependabot alerts for organization %s: %v", org, err)
+	}
+
+	return alerts, nil
+}
+
+// ListRepositoryDependabotAlerts lists Dependabot alerts in the given state for a repository
+func (c *GitHubClient) ListRepositoryDependabotAlerts(ctx context.Context, owner, repo, state string) ([]*DependabotAlert, error) {
+	alerts, err := c.listDependabotAlerts(ctx, fmt.Sprintf("repos/%s/%s/dependabot/alerts", owner, repo), state)
+	if err != nil {
+		return nil, fmt.Errorf("error listing Dependabot alerts for %s/%s: %v", owner, repo, err)
+	}
+
+	return alerts, nil
+}
+
+// listDependabotAlerts fetches every page of a Dependabot alerts listing endpoint
+func (c *GitHubClient) listDependabotAlerts(ctx context.Context, path, state string) ([]*DependabotAlert, error) {
+	opts := &dependabotAlertListOptions{
+		State:   state,
+		PerPage: 100,
+	}
+
+	var allAlerts []*DependabotAlert
+
+	for {
+		u, err := addOptions(path, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		var alerts []*DependabotAlert
+		var resp *github.Response
+
+		err = c.ExecuteWithRateLimit(ctx, func() error {
+			req, reqErr := c.Client.NewRequest("GET", u, nil)
+			if reqErr != nil {
+				return reqErr
+			}
+			var apiErr error
+			resp, apiErr = c.Client.Do(ctx, req, &alerts)
+			return apiErr
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		allAlerts = append(allAlerts, alerts...)
+
+		if resp.After == "" {
+			break
+		}
+		opts.After = resp.After
+	}
+
+	return allAlerts, nil
+}
@@ -0,0 +1,155 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// Environment is a deployment environment of a repository with its protection rules
+// The go-github version we depend on leaves the required reviewers untyped, so the type is declared here
+type Environment struct {
+	Name                   string                  `json:"name"`
+	HTMLURL                string                  `json:"html_url"`
+	ProtectionRules        []*EnvironmentRule      `json:"protection_rules"`
+	DeploymentBranchPolicy *DeploymentBranchPolicy `json:"deployment_branch_policy"` // nil when any branch can deploy
+}
+
+// EnvironmentRule is a protection rule of an environment
+type EnvironmentRule struct {
+	Type      string                 `json:"type"`       // "required_reviewers", "wait_timer" or "branch_policy"
+	WaitTimer int                    `json:"wait_timer"` // Minutes to wait before deploying, for wait timers
+	Reviewers []*EnvironmentReviewer `json:"reviewers"`  // For required reviewers
+}
+
+// EnvironmentReviewer is a user or team that can approve deployments to an environment
+type EnvironmentReviewer struct {
+	Type     string `json:"type"` // "User" or "Team"
+	Reviewer struct {
+		Login string `json:"login"` // Set for users
+		Slug  string `json:"slug"`  // Set for teams
+	} `json:"reviewer"`
+}
+
+// DeploymentBranchPolicy restricts the branches that can deploy to an environment
+type DeploymentBranchPolicy struct {
+	ProtectedBranches    bool `json:"protected_branches"`
+	CustomBranchPolicies bool `json:"custom_branch_policies"`
+}
+
+// WaitTimer returns the wait timer of the environment, 0 without one
+func (e *Environment) WaitTimer() time.Duration {
+	for _, rule := range e.ProtectionRules {
+		if rule.Type == "wait_timer" {
+			return time.Duration(rule.WaitTimer) * time.Minute
+		}
+	}
+	return 0
+}
+
+// Reviewers returns the required reviewers of the environment, none without the rule
+func (e *Environment) Reviewers() []*EnvironmentReviewer {
+	for _, rule := range e.ProtectionRules {
+		if rule.Type == "required_reviewers" {
+			return rule.Reviewers
+		}
+	}
+	return nil
+}
+
+// GetEnvironment gets an environment of a repository with its protection rules, nil when it does not exist
+func (c *GitHubClient) GetEnvironment(ctx context.Context, owner, repo, name string) (*Environment, error) {
+	environment := new(Environment)
+	err := c.ExecuteWithRateLimit(ctx, func() error {
+		req, reqErr := c.Client.NewRequest("GET", fmt.Sprintf("repos/%s/%s/environments/%s", owner, repo, url.PathEscape(name)), nil)
+		if reqErr != nil {
+			return reqErr
+		}
+		_, apiErr := c.Client.Do(ctx, req, environment)
+		return apiErr
+	})
+
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting environment %s of %s/%s: %v", name, owner, repo, err)
+	}
+
+	return environment, nil
+}
+
+// ListDeployments lists the deployments of a repository to an environment created since the given time, newest first
+// Pages are fetched with the pagination of the context, see WithPagination
+func (c *GitHubClient) ListDeployments(ctx context.Context, owner, repo, environment string, since time.Time) ([]*github.Deployment, error) {
+	pagination := paginationOf(ctx)
+	opts := &github.DeploymentsListOptions{
+		Environment: environment,
+		ListOptions: github.ListOptions{PerPage: pagination.PerPage()},
+	}
+
+	var allDeployments []*github.Deployment
+	for pages := 1; ; pages++ {
+		var deployments []*github.Deployment
+		var resp *github.Response
+		err := c.ExecuteWithRateLimit(ctx, func() error {
+			var apiErr error
+			deployments, resp, apiErr = c.Client.Repositories.ListDeployments(ctx, owner, repo, opts)
+			return apiErr
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing deployments of %s/%s to %s: %v", owner, repo, environment, err)
+		}
+
+		// Deployments are returned newest first, so older pages can be left out
+		older := false
+		for _, deployment := range deployments {
+			if deployment.GetCreatedAt().Before(since) {
+				older = true
+				break
+			}
+			allDeployments = append(allDeployments, deployment)
+		}
+
+		if older || !morePages(pagination, pages, resp, fmt.Sprintf("deployments of %s/%s to %s", owner, repo, environment)) {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return allDeployments, nil
+}
+
+// ListDeploymentStatuses lists the statuses of a deployment, newest first
+func (c *GitHubClient) ListDeploymentStatuses(ctx context.Context, owner, repo string, id int64) ([]*github.DeploymentStatus, error) {
+	opts := &github.ListOptions{PerPage: 100}
+
+	var allStatuses []*github.DeploymentStatus
+	for {
+		var statuses []*github.DeploymentStatus
+		var resp *github.Response
+		err := c.ExecuteWithRateLimit(ctx, func() error {
+			var apiErr error
+			statuses, resp, apiErr = c.Client.Repositories.ListDeploymentStatuses(ctx, owner, repo, id, opts)
+			return apiErr
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing statuses of deployment %d of %s/%s: %v", id, owner, repo, err)
+		}
+
+		allStatuses = append(allStatuses, statuses...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return allStatuses, nil
+}
@@ -0,0 +1,90 @@
+package common
+
+import (
+	"context"
+	"strings"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/google/go-github/v45/github"
+)
+
+// FilterRepositories keeps the discovered repositories that match the repository filters and are in the
+// shard and sample of the run, see SetShard and SetSample, and returns how many were left out
+// Archived repositories left out by exclude_archived and repositories left to other shards or out of the sample
+// count towards the coverage of the monitor run the context belongs to, so reports can note them
+func FilterRepositories(ctx context.Context, repos []*github.Repository, filters config.Filters) ([]*github.Repository, int) {
+	excluded := make(map[string]bool, len(filters.Exclusions))
+	for _, repo := range filters.Exclusions {
+		excluded[strings.ToLower(repo)] = true
+	}
+
+	kept := make([]*github.Repository, 0, len(repos))
+	archived := 0
+	for _, repo := range repos {
+		if filters.ExcludeArchived && filters.IsArchived == nil && repo.GetArchived() {
+			archived++
+			continue
+		}
+		if !excluded[strings.ToLower(repo.GetFullName())] && matchesFilters(repo, filters) {
+			kept = append(kept, repo)
+		}
+	}
+
+	// Sample the repositories of the shard, so the sample's size is what each shard checks
+	matching := len(kept)
+	kept = shardRepositories(kept)
+	sharded := len(kept)
+	kept = sampleRepositories(kept)
+	otherShards, unsampled := matching-sharded, sharded-len(kept)
+
+	if archived > 0 || otherShards > 0 || unsampled > 0 {
+		s := coverageScope(ctx)
+		s.mu.Lock()
+		s.coverage.Archived += archived
+		s.coverage.OtherShards += otherShards
+		s.coverage.Unsampled += unsampled
+		s.mu.Unlock()
+	}
+	return kept, len(repos) - len(kept)
+}
+
+// matchesFilters reports whether a repository has the attributes the filters require
+func matchesFilters(repo *github.Repository, filters config.Filters) bool {
+	if filters.Topic != "" && !hasTopic(repo, filters.Topic) {
+		return false
+	}
+	if len(filters.Languages) > 0 && !containsFold(filters.Languages, repo.GetLanguage()) {
+		return false
+	}
+	if filters.MinSizeKB > 0 && repo.GetSize() < filters.MinSizeKB {
+		return false
+	}
+	if filters.MaxSizeKB > 0 && repo.GetSize() > filters.MaxSizeKB {
+		return false
+	}
+	if filters.IsFork != nil && repo.GetFork() != *filters.IsFork {
+		return false
+	}
+	if filters.IsArchived != nil && repo.GetArchived() != *filters.IsArchived {
+		return false
+	}
+	if filters.IsTemplate != nil && repo.GetIsTemplate() != *filters.IsTemplate {
+		return false
+	}
+	return true
+}
+
+// hasTopic reports whether a repository is tagged with the topic
+func hasTopic(repo *github.Repository, topic string) bool {
+	return containsFold(repo.Topics, topic)
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
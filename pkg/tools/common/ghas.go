@@ -0,0 +1,98 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// AdvancedSecurityCommitters is the GitHub Advanced Security seat usage of an organization
+// The go-github version we depend on leaves out the purchased seats and paging, so the type is declared here
+type AdvancedSecurityCommitters struct {
+	TotalCommitters int `json:"total_advanced_security_committers"` // Active committers consuming seats
+	// Seats purchased for the organization's enterprise, 0 when GitHub does not tell
+	PurchasedCommitters int                               `json:"purchased_advanced_security_committers"`
+	TotalRepositories   int                               `json:"total_count"`
+	Repositories        []*AdvancedSecurityRepoCommitters `json:"repositories"`
+}
+
+// AdvancedSecurityRepoCommitters is the number of active committers of a repository consuming seats
+type AdvancedSecurityRepoCommitters struct {
+	Name       string `json:"name"` // "owner/repo"
+	Committers int    `json:"advanced_security_committers"`
+}
+
+// advancedSecurityListOptions specifies the parameters for listing the seat usage of repositories
+type advancedSecurityListOptions struct {
+	PerPage int `url:"per_page,omitempty"`
+	Page    int `url:"page,omitempty"`
+}
+
+// GetAdvancedSecurityCommitters gets the GitHub Advanced Security seat usage of an organization, with the
+// repositories consuming seats. Reading it needs an organization owner or billing manager
+func (c *GitHubClient) GetAdvancedSecurityCommitters(ctx context.Context, org string) (*AdvancedSecurityCommitters, error) {
+	opts := &advancedSecurityListOptions{PerPage: 100}
+
+	var usage *AdvancedSecurityCommitters
+	for {
+		u, err := addOptions(fmt.Sprintf("orgs/%s/settings/billing/advanced-security", org), opts)
+		if err != nil {
+			return nil, err
+		}
+
+		page := new(AdvancedSecurityCommitters)
+		var resp *github.Response
+		err = c.ExecuteWithRateLimit(ctx, func() error {
+			req, reqErr := c.Client.NewRequest("GET", u, nil)
+			if reqErr != nil {
+				return reqErr
+			}
+			var apiErr error
+			resp, apiErr = c.Client.Do(ctx, req, page)
+			return apiErr
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error getting advanced security committers of organization %s: %v", org, err)
+		}
+
+		if usage == nil {
+			usage = page
+		} else {
+			usage.Repositories = append(usage.Repositories, page.Repositories...)
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return usage, nil
+}
+
+// HasCodeScanningAnalyses reports whether code scanning analyzed a repository at least once,
+// which is how far code scanning is known to be turned on
+func (c *GitHubClient) HasCodeScanningAnalyses(ctx context.Context, owner, repo string) (bool, error) {
+	opts := &github.AnalysesListOptions{ListOptions: github.ListOptions{PerPage: 1}}
+
+	var analyses []*github.ScanningAnalysis
+	err := c.ExecuteWithRateLimit(ctx, func() error {
+		var apiErr error
+		analyses, _, apiErr = c.Client.CodeScanning.ListAnalysesForRepo(ctx, owner, repo, opts)
+		return apiErr
+	})
+
+	// GitHub answers 404 for repositories without analyses
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error listing code scanning analyses of %s/%s: %v", owner, repo, err)
+	}
+
+	return len(analyses) > 0, nil
+}
@@ -2,12 +2,21 @@ package common
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/go-github/v45/github"
+	"github.com/google/go-querystring/query"
 	"golang.org/x/oauth2"
 	"golang.org/x/time/rate"
 )
@@ -18,17 +27,175 @@ type GitHubClientInterface interface {
 	ExecuteWithRateLimit(ctx context.Context, f func() error) error
 	GetPullRequests(ctx context.Context, owner, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error)
 	ListPullRequestReviews(ctx context.Context, owner, repo string, number int, opts *github.ListOptions) ([]*github.PullRequestReview, *github.Response, error)
+	SearchMergedPullRequests(ctx context.Context, org string, from, to time.Time) ([]*github.Issue, int, error)
+	ListPullRequestCommits(ctx context.Context, owner, repo string, number int) ([]*github.RepositoryCommit, error)
+	ListPullRequestFiles(ctx context.Context, owner, repo string, number int) ([]string, error)
+	GetPullRequest(ctx context.Context, owner, repo string, number int) (*github.PullRequest, error)
+	GetCommit(ctx context.Context, owner, repo, sha string) (*github.RepositoryCommit, error)
+	ListIssueEvents(ctx context.Context, owner, repo string, number int) ([]*github.IssueEvent, error)
+	GetRequiredStatusChecks(ctx context.Context, owner, repo, branch string) ([]*github.RequiredStatusCheck, error)
+	ListCommitStatuses(ctx context.Context, owner, repo, ref string) ([]*github.RepoStatus, error)
+	ListCheckRuns(ctx context.Context, owner, repo, ref string) ([]*github.CheckRun, error)
+	ListProtectedBranches(ctx context.Context, owner, repo string) ([]*github.Branch, error)
+	GetAdminEnforcement(ctx context.Context, owner, repo, branch string) (bool, error)
+	GetRequiredApprovingReviewCount(ctx context.Context, owner, repo, branch string) (int, error)
+	ListAuditLog(ctx context.Context, org, phrase string) ([]*github.AuditEntry, error)
+	GetAdvancedSecurityCommitters(ctx context.Context, org string) (*AdvancedSecurityCommitters, error)
+	HasCodeScanningAnalyses(ctx context.Context, owner, repo string) (bool, error)
 	ListUserRepositories(ctx context.Context, visibility string) ([]*github.Repository, error)
 	ListOrganizationRepositories(ctx context.Context, org string, visibility string) ([]*github.Repository, error)
+	ListTeamRepositories(ctx context.Context, org, team string, visibility string) ([]*github.Repository, error)
 	ListRepositoryEvents(ctx context.Context, owner, repo string) ([]*github.Event, error)
 	ListUserEventsForOrganization(ctx context.Context, org, user string) ([]*github.Event, error)
 	ListRepositoryPublicEvents(ctx context.Context) ([]*github.Event, error)
+	ListOrganizationRulesets(ctx context.Context, org string) ([]*Ruleset, error)
+	ListRepositoryRulesets(ctx context.Context, owner, repo string) ([]*Ruleset, error)
+	GetOrganizationRuleset(ctx context.Context, org string, id int64) (*Ruleset, error)
+	GetRepositoryRuleset(ctx context.Context, owner, repo string, id int64) (*Ruleset, error)
+	ListOrganizationCodeScanningAlerts(ctx context.Context, org, state string) ([]*github.Alert, error)
+	ListRepositoryCodeScanningAlerts(ctx context.Context, owner, repo, state string) ([]*github.Alert, error)
+	ListOrganizationDependabotAlerts(ctx context.Context, org, state string) ([]*DependabotAlert, error)
+	ListRepositoryDependabotAlerts(ctx context.Context, owner, repo, state string) ([]*DependabotAlert, error)
+	ListOrganizationSecretScanningAlerts(ctx context.Context, org string) ([]*SecretScanningAlert, error)
+	ListRepositorySecretScanningAlerts(ctx context.Context, owner, repo string) ([]*SecretScanningAlert, error)
+	GetOrganizationWorkflowPermissions(ctx context.Context, org string) (*WorkflowPermissions, error)
+	GetRepositoryWorkflowPermissions(ctx context.Context, owner, repo string) (*WorkflowPermissions, error)
+	GetEnvironment(ctx context.Context, owner, repo, name string) (*Environment, error)
+	ListDeployments(ctx context.Context, owner, repo, environment string, since time.Time) ([]*github.Deployment, error)
+	ListDeploymentStatuses(ctx context.Context, owner, repo string, id int64) ([]*github.DeploymentStatus, error)
+	ListWorkflowRuns(ctx context.Context, owner, repo, event string, since time.Time) ([]*WorkflowRun, error)
+	ListActionsSecretNames(ctx context.Context, owner, repo string) ([]string, error)
+	ListOrganizationMembers(ctx context.Context, org, role string) ([]*github.User, error)
+	IsOrganizationMember(ctx context.Context, org, user string) (bool, error)
+	IsTeamMember(ctx context.Context, org, team, user string) (bool, error)
+	ListRepositoryCollaborators(ctx context.Context, owner, repo string) ([]*github.User, error)
+	ListRepositoryTeams(ctx context.Context, owner, repo string) ([]*github.Team, error)
+	GetLatestUserEvent(ctx context.Context, user string) (*github.Event, error)
+	GetRepository(ctx context.Context, owner, repo string) (*github.Repository, error)
+	GetLatestIssueActivity(ctx context.Context, owner, repo string) (*github.Issue, error)
+	ListOpenIssues(ctx context.Context, owner, repo string, createdBefore time.Time) ([]*github.Issue, error)
+	GetFileContent(ctx context.Context, owner, repo, path string) ([]byte, error)
+	ListRepositoryFiles(ctx context.Context, owner, repo, branch string) ([]string, bool, error)
+	GetAuthenticatedUser(ctx context.Context) (*github.User, error)
+	GetTokenInfo(ctx context.Context) (*TokenInfo, error)
+	GetOrganizationAccess(ctx context.Context, org string) (*OrganizationAccess, error)
 }
 
 // GitHubClient wraps the GitHub client with rate limiting
 type GitHubClient struct {
 	Client      *github.Client
 	RateLimiter *rate.Limiter
+
+	tokenKey string // Identifies the token in caches shared by all clients
+}
+
+// apiCalls counts the GitHub API requests sent by all clients of the process
+var apiCalls atomic.Int64
+
+// limiterWait and apiTime accumulate the time all clients spent waiting for the rate limiter
+// and for GitHub to respond, in nanoseconds
+var limiterWait, apiTime atomic.Int64
+
+// Timings is where the time of GitHub API calls went
+type Timings struct {
+	Limiter time.Duration // Waiting for the rate limiter
+	API     time.Duration // Waiting for GitHub to respond, until the response headers arrive
+}
+
+// RateLimit is the core API rate limit of a token, as last reported by GitHub
+type RateLimit struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+}
+
+// rateLimits holds the last rate limit reported for each token, by tokenKey
+var (
+	rateLimitsMu sync.Mutex
+	rateLimits   = make(map[string]RateLimit)
+)
+
+// tokenKey identifies a token without keeping it
+func tokenKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// countingTransport counts the requests sent through it in apiCalls
+// and records the rate limit reported in the responses
+type countingTransport struct {
+	base     http.RoundTripper
+	tokenKey string
+}
+
+// RoundTrip counts and sends a request, unless the API call budget or the cap of its monitor run is used up
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	scope := scopeOf(req.Context())
+	if budgetExceeded() || scope.budgetExceeded() {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, ErrBudgetExceeded
+	}
+	apiCalls.Add(1)
+	if scope != nil {
+		scope.apiCalls.Add(1)
+	}
+
+	// Tag the request with the monitor and its run, round trippers must not modify the request they are given
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", UserAgent(req.Context()))
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	apiTime.Add(int64(time.Since(start)))
+	if scope != nil {
+		scope.apiTime.Add(int64(time.Since(start)))
+	}
+	if err == nil {
+		t.recordRateLimit(resp.Header)
+	}
+	return resp, err
+}
+
+// recordRateLimit records the core rate limit from the headers of a response
+func (t *countingTransport) recordRateLimit(header http.Header) {
+	if resource := header.Get("X-RateLimit-Resource"); resource != "" && resource != "core" {
+		return
+	}
+
+	limit, limitErr := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	remaining, remainingErr := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	reset, resetErr := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if limitErr != nil || remainingErr != nil || resetErr != nil {
+		return
+	}
+
+	rateLimitsMu.Lock()
+	defer rateLimitsMu.Unlock()
+	rateLimits[t.tokenKey] = RateLimit{Limit: limit, Remaining: remaining, Reset: time.Unix(reset, 0).UTC()}
+}
+
+// APICalls returns the number of GitHub API requests sent so far, including rate limit checks
+func APICalls() int64 {
+	return apiCalls.Load()
+}
+
+// APITimings returns the time spent on GitHub API calls so far, by all clients of the process
+func APITimings() Timings {
+	return Timings{
+		Limiter: time.Duration(limiterWait.Load()),
+		API:     time.Duration(apiTime.Load()),
+	}
+}
+
+// LatestRateLimit returns the core rate limit of a token as reported by the last response to its requests
+// It does not call the API, so it is false until a client with the token has sent a request
+func LatestRateLimit(token string) (RateLimit, bool) {
+	rateLimitsMu.Lock()
+	defer rateLimitsMu.Unlock()
+	limit, ok := rateLimits[tokenKey(token)]
+	return limit, ok
 }
 
 // NewGitHubClient creates a new authenticated GitHub client with rate limiting
@@ -37,23 +204,26 @@ func NewGitHubClient(ctx context.Context, token string) *GitHubClient {
 		&oauth2.Token{AccessToken: token},
 	)
 	tc := oauth2.NewClient(ctx, ts)
+	tc.Transport = &countingTransport{base: tc.Transport, tokenKey: tokenKey(token)}
 	client := github.NewClient(tc)
 
-	// GitHub's API allows 5000 requests per hour for authenticated requests
-	// We'll set a conservative limit of 4500 per hour (1.25 per second)
-	limiter := rate.NewLimiter(rate.Limit(1.25), 1)
-
 	return &GitHubClient{
 		Client:      client,
-		RateLimiter: limiter,
+		RateLimiter: rateLimiter(ctx, tokenKey(token)),
+		tokenKey:    tokenKey(token),
 	}
 }
 
 // ExecuteWithRateLimit executes a GitHub API call with rate limiting
 func (c *GitHubClient) ExecuteWithRateLimit(ctx context.Context, f func() error) error {
+	waitStart := time.Now()
 	if err := c.RateLimiter.Wait(ctx); err != nil {
 		return err
 	}
+	limiterWait.Add(int64(time.Since(waitStart)))
+	if scope := scopeOf(ctx); scope != nil {
+		scope.limiterWait.Add(int64(time.Since(waitStart)))
+	}
 
 	err := f()
 
@@ -93,6 +263,127 @@ func (c *GitHubClient) ListPullRequestReviews(ctx context.Context, owner, repo s
 	return reviews, resp, err
 }
 
+// ListPullRequestCommits lists the commits of a pull request, oldest first
+// GitHub lists at most 250 commits of a pull request
+func (c *GitHubClient) ListPullRequestCommits(ctx context.Context, owner, repo string, number int) ([]*github.RepositoryCommit, error) {
+	opts := &github.ListOptions{PerPage: 100}
+
+	var allCommits []*github.RepositoryCommit
+	for {
+		var commits []*github.RepositoryCommit
+		var resp *github.Response
+		err := c.ExecuteWithRateLimit(ctx, func() error {
+			var apiErr error
+			commits, resp, apiErr = c.Client.PullRequests.ListCommits(ctx, owner, repo, number, opts)
+			return apiErr
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing commits of pull request %s/%s#%d: %v", owner, repo, number, err)
+		}
+
+		allCommits = append(allCommits, commits...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return allCommits, nil
+}
+
+// ListPullRequestFiles lists the paths of the files a pull request changes, with the previous paths of renamed files
+// GitHub lists at most 3000 files of a pull request
+func (c *GitHubClient) ListPullRequestFiles(ctx context.Context, owner, repo string, number int) ([]string, error) {
+	opts := &github.ListOptions{PerPage: 100}
+
+	var allPaths []string
+	for {
+		var files []*github.CommitFile
+		var resp *github.Response
+		err := c.ExecuteWithRateLimit(ctx, func() error {
+			var apiErr error
+			files, resp, apiErr = c.Client.PullRequests.ListFiles(ctx, owner, repo, number, opts)
+			return apiErr
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing files of pull request %s/%s#%d: %v", owner, repo, number, err)
+		}
+
+		for _, file := range files {
+			allPaths = append(allPaths, file.GetFilename())
+			if previous := file.GetPreviousFilename(); previous != "" {
+				allPaths = append(allPaths, previous)
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return allPaths, nil
+}
+
+// GetPullRequest gets a single pull request, e.g. for the details search results leave out
+func (c *GitHubClient) GetPullRequest(ctx context.Context, owner, repo string, number int) (*github.PullRequest, error) {
+	var pr *github.PullRequest
+	err := c.ExecuteWithRateLimit(ctx, func() error {
+		var apiErr error
+		pr, _, apiErr = c.Client.PullRequests.Get(ctx, owner, repo, number)
+		return apiErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting pull request %s/%s#%d: %v", owner, repo, number, err)
+	}
+
+	return pr, nil
+}
+
+// GetCommit gets a commit with its parents, e.g. the commit a pull request was merged as
+func (c *GitHubClient) GetCommit(ctx context.Context, owner, repo, sha string) (*github.RepositoryCommit, error) {
+	var commit *github.RepositoryCommit
+	err := c.ExecuteWithRateLimit(ctx, func() error {
+		var apiErr error
+		commit, _, apiErr = c.Client.Repositories.GetCommit(ctx, owner, repo, sha, nil)
+		return apiErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting commit %s of %s/%s: %v", sha, owner, repo, err)
+	}
+
+	return commit, nil
+}
+
+// ListIssueEvents lists all events of an issue or pull request, e.g. the dismissals of its reviews
+func (c *GitHubClient) ListIssueEvents(ctx context.Context, owner, repo string, number int) ([]*github.IssueEvent, error) {
+	opts := &github.ListOptions{PerPage: 100}
+
+	var allEvents []*github.IssueEvent
+	for {
+		var events []*github.IssueEvent
+		var resp *github.Response
+		err := c.ExecuteWithRateLimit(ctx, func() error {
+			var apiErr error
+			events, resp, apiErr = c.Client.Issues.ListIssueEvents(ctx, owner, repo, number, opts)
+			return apiErr
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing events of %s/%s#%d: %v", owner, repo, number, err)
+		}
+
+		allEvents = append(allEvents, events...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return allEvents, nil
+}
+
 // ListUserRepositories lists repositories for the authenticated user based on visibility
 func (c *GitHubClient) ListUserRepositories(ctx context.Context, visibility string) ([]*github.Repository, error) {
 	opts := &github.RepositoryListOptions{
@@ -191,6 +482,60 @@ func (c *GitHubClient) ListOrganizationRepositories(ctx context.Context, org str
 	return allRepos, nil
 }
 
+// ListTeamRepositories lists the repositories the team with the given slug in org has access to, based on visibility
+// GitHub cannot filter team repositories by visibility, so they are filtered after listing
+func (c *GitHubClient) ListTeamRepositories(ctx context.Context, org, team string, visibility string) ([]*github.Repository, error) {
+	if org == "" || team == "" {
+		return nil, fmt.Errorf("organization and team cannot be empty")
+	}
+
+	var keep func(repo *github.Repository) bool
+	switch visibility {
+	case "public-only":
+		keep = func(repo *github.Repository) bool { return !repo.GetPrivate() }
+	case "private-only":
+		keep = func(repo *github.Repository) bool { return repo.GetPrivate() }
+	case "all":
+		keep = func(repo *github.Repository) bool { return true }
+	default:
+		return nil, fmt.Errorf("invalid repository visibility: %s", visibility)
+	}
+
+	opts := &github.ListOptions{PerPage: 100}
+
+	var allRepos []*github.Repository
+	page := 1
+
+	for {
+		opts.Page = page
+		var repos []*github.Repository
+		var resp *github.Response
+
+		err := c.ExecuteWithRateLimit(ctx, func() error {
+			var apiErr error
+			repos, resp, apiErr = c.Client.Teams.ListTeamReposBySlug(ctx, org, team, opts)
+			return apiErr
+		})
+
+		if err != nil {
+			return nil, fmt.Errorf("error listing repositories of team %s/%s: %v", org, team, err)
+		}
+
+		for _, repo := range repos {
+			if keep(repo) {
+				allRepos = append(allRepos, repo)
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+
+	return allRepos, nil
+}
+
 // ListRepositoryEvents lists events for a specific repository
 func (c *GitHubClient) ListRepositoryEvents(ctx context.Context, owner, repo string) ([]*github.Event, error) {
 	opts := &github.ListOptions{
@@ -296,6 +641,394 @@ func (c *GitHubClient) ListRepositoryPublicEvents(ctx context.Context) ([]*githu
 	return allEvents, nil
 }
 
+// ListOrganizationCodeScanningAlerts lists code scanning alerts in the given state across an organization
+// Pages are fetched with the pagination of the context, see WithPagination
+func (c *GitHubClient) ListOrganizationCodeScanningAlerts(ctx context.Context, org, state string) ([]*github.Alert, error) {
+	pagination := paginationOf(ctx)
+	opts := &github.AlertListOptions{
+		State:       state,
+		ListOptions: github.ListOptions{PerPage: pagination.PerPage()},
+	}
+
+	var allAlerts []*github.Alert
+	page := 1
+
+	for pages := 1; ; pages++ {
+		opts.Page = page
+		var alerts []*github.Alert
+		var resp *github.Response
+
+		err := c.ExecuteWithRateLimit(ctx, func() error {
+			var apiErr error
+			alerts, resp, apiErr = c.Client.CodeScanning.ListAlertsForOrg(ctx, org, opts)
+			return apiErr
+		})
+
+		if err != nil {
+			return nil, fmt.Errorf("error listing code scanning alerts for organization %s: %v", org, err)
+		}
+
+		allAlerts = append(allAlerts, alerts...)
+
+		if !morePages(pagination, pages, resp, fmt.Sprintf("code scanning alerts of organization %s", org)) {
+			break
+		}
+		page = resp.NextPage
+	}
+
+	return allAlerts, nil
+}
+
+// ListRepositoryCodeScanningAlerts lists code scanning alerts in the given state for a repository
+// Pages are fetched with the pagination of the context, see WithPagination
+func (c *GitHubClient) ListRepositoryCodeScanningAlerts(ctx context.Context, owner, repo, state string) ([]*github.Alert, error) {
+	pagination := paginationOf(ctx)
+	opts := &github.AlertListOptions{
+		State:       state,
+		ListOptions: github.ListOptions{PerPage: pagination.PerPage()},
+	}
+
+	var allAlerts []*github.Alert
+	page := 1
+
+	for pages := 1; ; pages++ {
+		opts.Page = page
+		var alerts []*github.Alert
+		var resp *github.Response
+
+		err := c.ExecuteWithRateLimit(ctx, func() error {
+			var apiErr error
+			alerts, resp, apiErr = c.Client.CodeScanning.ListAlertsForRepo(ctx, owner, repo, opts)
+			return apiErr
+		})
+
+		if err != nil {
+			return nil, fmt.Errorf("error listing code scanning alerts for %s/%s: %v", owner, repo, err)
+		}
+
+		allAlerts = append(allAlerts, alerts...)
+
+		if !morePages(pagination, pages, resp, fmt.Sprintf("code scanning alerts of %s/%s", owner, repo)) {
+			break
+		}
+		page = resp.NextPage
+	}
+
+	return allAlerts, nil
+}
+
+// ListOrganizationMembers lists members of an organization with the given role ("all", "admin" or "member")
+func (c *GitHubClient) ListOrganizationMembers(ctx context.Context, org, role string) ([]*github.User, error) {
+	if org == "" {
+		return nil, fmt.Errorf("organization name cannot be empty")
+	}
+
+	opts := &github.ListMembersOptions{
+		Role:        role,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var allMembers []*github.User
+	page := 1
+
+	for {
+		opts.Page = page
+		var members []*github.User
+		var resp *github.Response
+
+		err := c.ExecuteWithRateLimit(ctx, func() error {
+			var apiErr error
+			members, resp, apiErr = c.Client.Organizations.ListMembers(ctx, org, opts)
+			return apiErr
+		})
+
+		if err != nil {
+			return nil, fmt.Errorf("error listing members for organization %s: %v", org, err)
+		}
+
+		allMembers = append(allMembers, members...)
+		c.cacheOrganizationMembers(org, members)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+
+	return allMembers, nil
+}
+
+// ListRepositoryCollaborators lists the collaborators of a repository along with their permissions
+func (c *GitHubClient) ListRepositoryCollaborators(ctx context.Context, owner, repo string) ([]*github.User, error) {
+	opts := &github.ListCollaboratorsOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var allCollaborators []*github.User
+	page := 1
+
+	for {
+		opts.Page = page
+		var collaborators []*github.User
+		var resp *github.Response
+
+		err := c.ExecuteWithRateLimit(ctx, func() error {
+			var apiErr error
+			collaborators, resp, apiErr = c.Client.Repositories.ListCollaborators(ctx, owner, repo, opts)
+			return apiErr
+		})
+
+		if err != nil {
+			return nil, fmt.Errorf("error listing collaborators for %s/%s: %v", owner, repo, err)
+		}
+
+		allCollaborators = append(allCollaborators, collaborators...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+
+	return allCollaborators, nil
+}
+
+// ListRepositoryTeams lists the teams with access to a repository, with their permission on it
+func (c *GitHubClient) ListRepositoryTeams(ctx context.Context, owner, repo string) ([]*github.Team, error) {
+	opts := &github.ListOptions{PerPage: 100}
+
+	var allTeams []*github.Team
+	for {
+		var teams []*github.Team
+		var resp *github.Response
+
+		err := c.ExecuteWithRateLimit(ctx, func() error {
+			var apiErr error
+			teams, resp, apiErr = c.Client.Repositories.ListTeams(ctx, owner, repo, opts)
+			return apiErr
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing teams for %s/%s: %v", owner, repo, err)
+		}
+
+		allTeams = append(allTeams, teams...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return allTeams, nil
+}
+
+// GetLatestUserEvent gets the most recent event performed by a user that is visible to the token
+// It returns nil when the user has no events within the events API retention period
+func (c *GitHubClient) GetLatestUserEvent(ctx context.Context, user string) (*github.Event, error) {
+	opts := &github.ListOptions{PerPage: 1}
+
+	var events []*github.Event
+	err := c.ExecuteWithRateLimit(ctx, func() error {
+		var apiErr error
+		events, _, apiErr = c.Client.Activity.ListEventsPerformedByUser(ctx, user, false, opts)
+		return apiErr
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing events for user %s: %v", user, err)
+	}
+
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	return events[0], nil
+}
+
+// GetRepository gets a single repository
+func (c *GitHubClient) GetRepository(ctx context.Context, owner, repo string) (*github.Repository, error) {
+	var repository *github.Repository
+	err := c.ExecuteWithRateLimit(ctx, func() error {
+		var apiErr error
+		repository, _, apiErr = c.Client.Repositories.Get(ctx, owner, repo)
+		return apiErr
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting repository %s/%s: %v", owner, repo, err)
+	}
+
+	return repository, nil
+}
+
+// GetLatestIssueActivity gets the most recently updated issue or pull request of a repository
+// It returns nil when the repository has no issues or pull requests
+func (c *GitHubClient) GetLatestIssueActivity(ctx context.Context, owner, repo string) (*github.Issue, error) {
+	opts := &github.IssueListByRepoOptions{
+		State:       "all",
+		Sort:        "updated",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: 1},
+	}
+
+	var issues []*github.Issue
+	err := c.ExecuteWithRateLimit(ctx, func() error {
+		var apiErr error
+		issues, _, apiErr = c.Client.Issues.ListByRepo(ctx, owner, repo, opts)
+		return apiErr
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing issues for %s/%s: %v", owner, repo, err)
+	}
+
+	if len(issues) == 0 {
+		return nil, nil
+	}
+
+	return issues[0], nil
+}
+
+// ListOpenIssues lists the open issues of a repository created before the given time, oldest first
+// Pull requests, which the issues endpoint includes, are left out
+func (c *GitHubClient) ListOpenIssues(ctx context.Context, owner, repo string, createdBefore time.Time) ([]*github.Issue, error) {
+	opts := &github.IssueListByRepoOptions{
+		State:       "open",
+		Sort:        "created",
+		Direction:   "asc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var allIssues []*github.Issue
+	for {
+		var issues []*github.Issue
+		var resp *github.Response
+		err := c.ExecuteWithRateLimit(ctx, func() error {
+			var apiErr error
+			issues, resp, apiErr = c.Client.Issues.ListByRepo(ctx, owner, repo, opts)
+			return apiErr
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing open issues for %s/%s: %v", owner, repo, err)
+		}
+
+		// Issues are returned oldest first, so newer pages can be left out
+		newer := false
+		for _, issue := range issues {
+			if !issue.GetCreatedAt().Before(createdBefore) {
+				newer = true
+				break
+			}
+			if !issue.IsPullRequest() {
+				allIssues = append(allIssues, issue)
+			}
+		}
+
+		if newer || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return allIssues, nil
+}
+
+// GetFileContent gets the content of a file on the default branch of a repository
+// It returns nil when the file does not exist
+func (c *GitHubClient) GetFileContent(ctx context.Context, owner, repo, path string) ([]byte, error) {
+	var file *github.RepositoryContent
+	err := c.ExecuteWithRateLimit(ctx, func() error {
+		var apiErr error
+		file, _, _, apiErr = c.Client.Repositories.GetContents(ctx, owner, repo, path, nil)
+		return apiErr
+	})
+
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting %s in %s/%s: %v", path, owner, repo, err)
+	}
+
+	if file == nil {
+		return nil, fmt.Errorf("%s in %s/%s is not a file", path, owner, repo)
+	}
+
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s in %s/%s: %v", path, owner, repo, err)
+	}
+
+	return []byte(content), nil
+}
+
+// ListRepositoryFiles lists the paths of the files of a repository at a branch, and whether GitHub truncated the list,
+// which it does for trees of more than 100,000 entries. It returns no files for empty repositories
+func (c *GitHubClient) ListRepositoryFiles(ctx context.Context, owner, repo, branch string) ([]string, bool, error) {
+	var tree *github.Tree
+	err := c.ExecuteWithRateLimit(ctx, func() error {
+		var apiErr error
+		tree, _, apiErr = c.Client.Git.GetTree(ctx, owner, repo, branch, true)
+		return apiErr
+	})
+
+	// Empty repositories have no tree: GitHub answers 404, or 409 for repositories without commits
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil &&
+		(errResp.Response.StatusCode == http.StatusNotFound || errResp.Response.StatusCode == http.StatusConflict) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("error listing files of %s/%s at %s: %v", owner, repo, branch, err)
+	}
+
+	var paths []string
+	for _, entry := range tree.Entries {
+		if entry.GetType() == "blob" {
+			paths = append(paths, entry.GetPath())
+		}
+	}
+	return paths, tree.GetTruncated(), nil
+}
+
+// GetAuthenticatedUser gets the user the token authenticates as
+func (c *GitHubClient) GetAuthenticatedUser(ctx context.Context) (*github.User, error) {
+	var user *github.User
+	err := c.ExecuteWithRateLimit(ctx, func() error {
+		var apiErr error
+		user, _, apiErr = c.Client.Users.Get(ctx, "")
+		return apiErr
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting authenticated user: %v", err)
+	}
+
+	return user, nil
+}
+
+// addOptions adds the parameters in opts as URL query parameters to path
+// It is used for endpoints that are not covered by the go-github client
+func addOptions(path string, opts interface{}) (string, error) {
+	u, err := url.Parse(path)
+	if err != nil {
+		return path, err
+	}
+
+	qs, err := query.Values(opts)
+	if err != nil {
+		return path, err
+	}
+
+	values := u.Query()
+	for key, value := range qs {
+		values[key] = value
+	}
+	u.RawQuery = values.Encode()
+
+	return u.String(), nil
+}
+
 // ParseRepository parses an "owner/repo" string into separate owner and repo components
 func ParseRepository(repository string) (string, string, bool) {
 	parts := strings.Split(repository, "/")
@@ -0,0 +1,233 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// Reasons targets are skipped when the scan stops early
+const (
+	StopBudget      = "budget"      // The API call budget is used up
+	StopDeadline    = "deadline"    // The scan deadline has passed
+	StopInterrupted = "interrupted" // The process was asked to stop
+)
+
+// ErrBudgetExceeded is returned for requests that are not sent because the API call budget is used up
+var ErrBudgetExceeded = errors.New("API call budget exceeded")
+
+// maxAPICalls is the API call budget of the process, unlimited when 0
+var maxAPICalls atomic.Int64
+
+// deadline is when the scan stops checking new targets, in Unix nanoseconds, none when 0
+var deadline atomic.Int64
+
+// interrupted is set once the process was asked to stop
+var interrupted atomic.Bool
+
+// SkippedTarget is a repository or organization that was not checked because the scan stopped
+type SkippedTarget struct {
+	Target string `json:"target"` // Repository, or "org:name" for an organization
+	Reason string `json:"reason"` // Why the scan stopped, e.g. StopBudget
+}
+
+// Cursor is the progress made within a target before the scan stopped, to resume it where it stopped
+type Cursor struct {
+	Page int             `json:"page"` // Next page to fetch
+	Data json.RawMessage `json:"data"` // Results of the pages fetched so far
+}
+
+// Coverage is the targets checked and skipped by a monitor
+type Coverage struct {
+	Checked []string          `json:"checked"`           // Targets checked, including those that failed
+	Skipped []SkippedTarget   `json:"skipped"`           // Targets not checked because the scan stopped
+	Errored []string          `json:"errored,omitempty"` // Checked targets whose check failed
+	Cursors map[string]Cursor `json:"cursors,omitempty"` // Progress within skipped targets, by target
+
+	Archived    int `json:"archived,omitempty"`     // Archived repositories left out by exclude_archived
+	Unsampled   int `json:"unsampled,omitempty"`    // Repositories left out of the sample of the run
+	OtherShards int `json:"other_shards,omitempty"` // Repositories left to the other shards of the scan
+}
+
+// SetAPICallBudget limits the GitHub API requests sent by all clients of the process, including rate limit checks
+// Requests beyond the budget fail with ErrBudgetExceeded. A budget of 0 removes the limit
+func SetAPICallBudget(max int64) {
+	maxAPICalls.Store(max)
+}
+
+// budgetExceeded reports whether the API call budget is used up
+func budgetExceeded() bool {
+	max := maxAPICalls.Load()
+	return max > 0 && apiCalls.Load() >= max
+}
+
+// SetDeadline stops the scan from checking new targets after t, letting the checks in flight finish
+// A zero time removes the deadline
+func SetDeadline(t time.Time) {
+	if t.IsZero() {
+		deadline.Store(0)
+		return
+	}
+	deadline.Store(t.UnixNano())
+}
+
+// Interrupt stops the scan from checking new targets, letting the checks in flight finish
+func Interrupt() {
+	interrupted.Store(true)
+}
+
+// Stopped returns why the scan stopped, empty while targets may still be checked
+func Stopped() string {
+	if budgetExceeded() {
+		return StopBudget
+	}
+	if interrupted.Load() {
+		return StopInterrupted
+	}
+	if d := deadline.Load(); d != 0 && time.Now().UnixNano() >= d {
+		return StopDeadline
+	}
+	return ""
+}
+
+// StoppedIn returns why the scan stopped for the monitor run the context belongs to, empty while targets
+// may still be checked. Besides the reasons of Stopped, the run stops when it used up its API call cap, see LimitScope
+func StoppedIn(ctx context.Context) string {
+	if reason := Stopped(); reason != "" {
+		return reason
+	}
+	if scopeOf(ctx).budgetExceeded() {
+		return StopBudget
+	}
+	return ""
+}
+
+// SkipIfStopped returns true when target must not be checked: because the scan stopped, in which case it is
+// recorded as skipped, because the run being resumed checked it already, or because it is a repository of another
+// shard of the scan, see SetShard. Otherwise it is recorded as checked
+// Targets are recorded in the coverage of the monitor run the context belongs to
+func SkipIfStopped(ctx context.Context, target string) bool {
+	reason := StoppedIn(ctx)
+
+	s := coverageScope(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !inShard(target) {
+		s.coverage.OtherShards++
+		return true
+	}
+	if s.resumed[target] {
+		return true
+	}
+	if reason == "" {
+		s.coverage.Checked = append(s.coverage.Checked, target)
+		return false
+	}
+
+	s.coverage.Skipped = append(s.coverage.Skipped, SkippedTarget{Target: target, Reason: reason})
+	return true
+}
+
+// Skip records target as skipped for the given reason, for targets that are not checked through SkipIfStopped
+func Skip(ctx context.Context, target, reason string) {
+	s := coverageScope(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.coverage.Skipped = append(s.coverage.Skipped, SkippedTarget{Target: target, Reason: reason})
+}
+
+// SkipIfBudgetExceeded records target as skipped instead of checked and returns true
+// when checking it failed with the budget used up, so it is reported as skipped rather than failed
+// Other failures are recorded as errored. Errors are not always wrapped on their way up,
+// so the budget is checked rather than the error itself
+func SkipIfBudgetExceeded(ctx context.Context, target string, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	s := coverageScope(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !budgetExceeded() && !s.budgetExceeded() {
+		s.coverage.Errored = append(s.coverage.Errored, target)
+		return false
+	}
+
+	for i := len(s.coverage.Checked) - 1; i >= 0; i-- {
+		if s.coverage.Checked[i] == target {
+			s.coverage.Checked = append(s.coverage.Checked[:i], s.coverage.Checked[i+1:]...)
+			break
+		}
+	}
+	s.coverage.Skipped = append(s.coverage.Skipped, SkippedTarget{Target: target, Reason: StopBudget})
+	return true
+}
+
+// SaveCursor records the progress made within target before the scan stopped: the next page to fetch
+// and the results of the pages fetched so far
+func SaveCursor(ctx context.Context, target string, page int, data interface{}) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	s := coverageScope(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.coverage.Cursors == nil {
+		s.coverage.Cursors = make(map[string]Cursor)
+	}
+	s.coverage.Cursors[target] = Cursor{Page: page, Data: encoded}
+	return nil
+}
+
+// ResumeCursor returns the page to resume target from and decodes the results of earlier pages into data
+// It returns 0 when there is no progress to resume. A cursor is only handed out once
+func ResumeCursor(ctx context.Context, target string, data interface{}) int {
+	s := coverageScope(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cursor, ok := s.coverage.Cursors[target]
+	if !ok {
+		return 0
+	}
+	delete(s.coverage.Cursors, target)
+
+	if err := json.Unmarshal(cursor.Data, data); err != nil {
+		return 0
+	}
+	return cursor.Page
+}
+
+// ResumeCoverage starts the coverage of a monitor from that of an interrupted run, before the monitor runs:
+// the targets it checked are not checked again, and targets it stopped within resume from their cursors
+func ResumeCoverage(ctx context.Context, c Coverage) {
+	s := coverageScope(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resumed = make(map[string]bool, len(c.Checked))
+	for _, target := range c.Checked {
+		s.resumed[target] = true
+	}
+	s.coverage = Coverage{Checked: append([]string(nil), c.Checked...), Errored: append([]string(nil), c.Errored...)}
+	for target, cursor := range c.Cursors {
+		if s.coverage.Cursors == nil {
+			s.coverage.Cursors = make(map[string]Cursor)
+		}
+		s.coverage.Cursors[target] = cursor
+	}
+}
+
+// TakeCoverage returns the targets checked and skipped with the context since the last call and forgets them
+// Monitor runs each take the coverage of their own scope, see WithScope
+func TakeCoverage(ctx context.Context) Coverage {
+	s := coverageScope(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	taken := s.coverage
+	s.coverage = Coverage{}
+	s.resumed = nil
+	return taken
+}
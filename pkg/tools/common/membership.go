@@ -0,0 +1,224 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// membershipCacheVersion is the version of the membership cache file format
+const membershipCacheVersion = 1
+
+// membershipEntry is the result of a membership lookup
+type membershipEntry struct {
+	Member    bool      `json:"member"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// membershipFile is the membership cache as saved between runs
+type membershipFile struct {
+	Version int                        `json:"version"`
+	Entries map[string]membershipEntry `json:"entries"`
+}
+
+// membership caches user, team and organization membership lookups of all clients of the process,
+// by tokenKey and member, as what a token can see of an organization depends on the token
+var (
+	membershipMu  sync.Mutex
+	membership    = make(map[string]membershipEntry)
+	membershipTTL time.Duration // How long entries stay valid, forever within a run when 0
+)
+
+// membershipHits and membershipMisses count the lookups answered from the cache and those sent to the API
+var membershipHits, membershipMisses atomic.Int64
+
+// orgMemberKey is the cache key of a user's membership of an organization
+func orgMemberKey(token, org, user string) string {
+	return token + "|org:" + strings.ToLower(org) + "|user:" + strings.ToLower(user)
+}
+
+// teamMemberKey is the cache key of a user's membership of a team, by its slug
+func teamMemberKey(token, org, team, user string) string {
+	return token + "|team:" + strings.ToLower(org) + "/" + strings.ToLower(team) + "|user:" + strings.ToLower(user)
+}
+
+// cachedMembership returns the cached result of a membership lookup, and whether there is a valid one
+// Hits and misses count towards the monitor run the context belongs to
+func cachedMembership(ctx context.Context, key string) (bool, bool) {
+	membershipMu.Lock()
+	entry, ok := membership[key]
+	hit := ok && (membershipTTL == 0 || time.Since(entry.CheckedAt) < membershipTTL)
+	membershipMu.Unlock()
+
+	scope := scopeOf(ctx)
+	if !hit {
+		membershipMisses.Add(1)
+		if scope != nil {
+			scope.cacheMisses.Add(1)
+		}
+		return false, false
+	}
+
+	membershipHits.Add(1)
+	if scope != nil {
+		scope.cacheHits.Add(1)
+	}
+	return entry.Member, true
+}
+
+// cacheMembership records the result of a membership lookup
+func cacheMembership(key string, member bool) {
+	membershipMu.Lock()
+	defer membershipMu.Unlock()
+	membership[key] = membershipEntry{Member: member, CheckedAt: time.Now()}
+}
+
+// ResetMembershipCache forgets all cached membership lookups, e.g. between scans of a long-running server
+func ResetMembershipCache() {
+	membershipMu.Lock()
+	defer membershipMu.Unlock()
+	membership = make(map[string]membershipEntry)
+	membershipTTL = 0
+}
+
+// LoadMembershipCache replaces the membership cache with the lookups saved by an earlier run,
+// keeping those made within ttl. New lookups also expire after ttl. A missing file starts an empty cache
+func LoadMembershipCache(path string, ttl time.Duration) error {
+	entries := make(map[string]membershipEntry)
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read membership cache %s: %w", path, err)
+	}
+	if err == nil {
+		var saved membershipFile
+		if err := json.Unmarshal(data, &saved); err != nil {
+			return fmt.Errorf("failed to parse membership cache %s: %w", path, err)
+		}
+		// Entries of another format version are dropped rather than misread
+		if saved.Version == membershipCacheVersion {
+			for key, entry := range saved.Entries {
+				if time.Since(entry.CheckedAt) < ttl {
+					entries[key] = entry
+				}
+			}
+		}
+	}
+
+	membershipMu.Lock()
+	defer membershipMu.Unlock()
+	membership = entries
+	membershipTTL = ttl
+	return nil
+}
+
+// SaveMembershipCache writes the valid membership lookups to path, for LoadMembershipCache in the next run
+// The file is replaced atomically so a run interrupted while saving never leaves a truncated cache behind
+func SaveMembershipCache(path string) error {
+	membershipMu.Lock()
+	saved := membershipFile{Version: membershipCacheVersion, Entries: make(map[string]membershipEntry, len(membership))}
+	for key, entry := range membership {
+		if membershipTTL == 0 || time.Since(entry.CheckedAt) < membershipTTL {
+			saved.Entries[key] = entry
+		}
+	}
+	membershipMu.Unlock()
+
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode membership cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".membership-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create membership cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write membership cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write membership cache: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace membership cache %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// IsOrganizationMember reports whether user is a member of org, as far as the client's token can see
+// Results are cached, so repeated checks of the same user do not call the API again
+func (c *GitHubClient) IsOrganizationMember(ctx context.Context, org, user string) (bool, error) {
+	if org == "" || user == "" {
+		return false, fmt.Errorf("organization and user cannot be empty")
+	}
+
+	key := orgMemberKey(c.tokenKey, org, user)
+	if member, ok := cachedMembership(ctx, key); ok {
+		return member, nil
+	}
+
+	var member bool
+	err := c.ExecuteWithRateLimit(ctx, func() error {
+		var apiErr error
+		member, _, apiErr = c.Client.Organizations.IsMember(ctx, org, user)
+		return apiErr
+	})
+	if err != nil {
+		return false, fmt.Errorf("error checking membership of %s in organization %s: %v", user, org, err)
+	}
+
+	cacheMembership(key, member)
+	return member, nil
+}
+
+// IsTeamMember reports whether user is an active member of the team with the given slug in org
+// Pending invitations do not count. Results are cached like IsOrganizationMember
+func (c *GitHubClient) IsTeamMember(ctx context.Context, org, team, user string) (bool, error) {
+	if org == "" || team == "" || user == "" {
+		return false, fmt.Errorf("organization, team and user cannot be empty")
+	}
+
+	key := teamMemberKey(c.tokenKey, org, team, user)
+	if member, ok := cachedMembership(ctx, key); ok {
+		return member, nil
+	}
+
+	var teamMembership *github.Membership
+	var resp *github.Response
+	err := c.ExecuteWithRateLimit(ctx, func() error {
+		var apiErr error
+		teamMembership, resp, apiErr = c.Client.Teams.GetTeamMembershipBySlug(ctx, org, team, user)
+		return apiErr
+	})
+	// GitHub answers 404 for users who are not members of the team
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return false, fmt.Errorf("error checking membership of %s in team %s/%s: %v", user, org, team, err)
+	}
+
+	member := err == nil && teamMembership.GetState() == "active"
+	cacheMembership(key, member)
+	return member, nil
+}
+
+// cacheOrganizationMembers records the listed users as members of org, so later lookups need no API call
+func (c *GitHubClient) cacheOrganizationMembers(org string, members []*github.User) {
+	for _, m := range members {
+		if m.GetLogin() != "" {
+			cacheMembership(orgMemberKey(c.tokenKey, org, m.GetLogin()), true)
+		}
+	}
+}
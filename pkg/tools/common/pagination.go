@@ -0,0 +1,39 @@
+package common
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/go-github/v45/github"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+)
+
+// paginationKey is the context key of the pagination of a monitor's listings
+type paginationKey struct{}
+
+// WithPagination returns a context in which the listings of a monitor's targets use its page size and page cap:
+// workflow runs, deployments, commit statuses and check runs, and code scanning alerts
+// Outside WithPagination they fetch every page, of the largest size
+func WithPagination(ctx context.Context, pagination config.PaginationConfig) context.Context {
+	return context.WithValue(ctx, paginationKey{}, pagination)
+}
+
+// paginationOf returns the pagination of the listings sent with the context
+func paginationOf(ctx context.Context) config.PaginationConfig {
+	pagination, _ := ctx.Value(paginationKey{}).(config.PaginationConfig)
+	return pagination
+}
+
+// morePages reports whether a listing goes on to the next page after fetching its first pages: there is one,
+// and the page cap is not reached. Listings stopped by the cap are logged, naming what they list
+func morePages(pagination config.PaginationConfig, pages int, resp *github.Response, listing string) bool {
+	if resp.NextPage == 0 {
+		return false
+	}
+	if pagination.MaxPages > 0 && pages >= pagination.MaxPages {
+		log.Printf("Reached the limit of %d pages listing %s, later pages are not checked", pagination.MaxPages, listing)
+		return false
+	}
+	return true
+}
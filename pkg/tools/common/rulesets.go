@@ -0,0 +1,137 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// Ruleset represents a repository or organization ruleset
+// The rulesets API is newer than the go-github version we depend on, so the
+// type is declared here and requests are issued through the raw client
+type Ruleset struct {
+	ID           int64                 `json:"id"`
+	Name         string                `json:"name"`
+	Target       string                `json:"target,omitempty"`
+	SourceType   string                `json:"source_type,omitempty"`
+	Source       string                `json:"source,omitempty"`
+	Enforcement  string                `json:"enforcement"`
+	BypassActors []*RulesetBypassActor `json:"bypass_actors,omitempty"`
+	Rules        []*RulesetRule        `json:"rules,omitempty"`
+}
+
+// RulesetBypassActor represents an actor that is allowed to bypass a ruleset
+type RulesetBypassActor struct {
+	ActorID    int64  `json:"actor_id"`
+	ActorType  string `json:"actor_type"`
+	BypassMode string `json:"bypass_mode,omitempty"`
+}
+
+// RulesetRule represents a single rule within a ruleset
+type RulesetRule struct {
+	Type string `json:"type"`
+}
+
+// ListOrganizationRulesets lists the rulesets defined at the organization level
+func (c *GitHubClient) ListOrganizationRulesets(ctx context.Context, org string) ([]*Ruleset, error) {
+	if org == "" {
+		return nil, fmt.Errorf("organization name cannot be empty")
+	}
+
+	rulesets, err := c.listRulesets(ctx, fmt.Sprintf("orgs/%s/rulesets", org))
+	if err != nil {
+		return nil, fmt.Errorf("error listing rulesets for organization %s: %v", org, err)
+	}
+
+	return rulesets, nil
+}
+
+// ListRepositoryRulesets lists the rulesets that apply to a repository,
+// including rulesets inherited from the organization
+func (c *GitHubClient) ListRepositoryRulesets(ctx context.Context, owner, repo string) ([]*Ruleset, error) {
+	rulesets, err := c.listRulesets(ctx, fmt.Sprintf("repos/%s/%s/rulesets?includes_parents=true", owner, repo))
+	if err != nil {
+		return nil, fmt.Errorf("error listing rulesets for %s/%s: %v", owner, repo, err)
+	}
+
+	return rulesets, nil
+}
+
+// GetOrganizationRuleset gets the full definition of an organization ruleset
+func (c *GitHubClient) GetOrganizationRuleset(ctx context.Context, org string, id int64) (*Ruleset, error) {
+	ruleset, err := c.getRuleset(ctx, fmt.Sprintf("orgs/%s/rulesets/%d", org, id))
+	if err != nil {
+		return nil, fmt.Errorf("error getting ruleset %d for organization %s: %v", id, org, err)
+	}
+
+	return ruleset, nil
+}
+
+// GetRepositoryRuleset gets the full definition of a ruleset that applies to a repository
+func (c *GitHubClient) GetRepositoryRuleset(ctx context.Context, owner, repo string, id int64) (*Ruleset, error) {
+	ruleset, err := c.getRuleset(ctx, fmt.Sprintf("repos/%s/%s/rulesets/%d?includes_parents=true", owner, repo, id))
+	if err != nil {
+		return nil, fmt.Errorf("error getting ruleset %d for %s/%s: %v", id, owner, repo, err)
+	}
+
+	return ruleset, nil
+}
+
+// listRulesets fetches every page of a rulesets listing endpoint
+func (c *GitHubClient) listRulesets(ctx context.Context, path string) ([]*Ruleset, error) {
+	var allRulesets []*Ruleset
+	page := 1
+
+	for {
+		u, err := addOptions(path, &github.ListOptions{PerPage: 100, Page: page})
+		if err != nil {
+			return nil, err
+		}
+
+		var rulesets []*Ruleset
+		var resp *github.Response
+
+		err = c.ExecuteWithRateLimit(ctx, func() error {
+			req, reqErr := c.Client.NewRequest("GET", u, nil)
+			if reqErr != nil {
+				return reqErr
+			}
+			var apiErr error
+			resp, apiErr = c.Client.Do(ctx, req, &rulesets)
+			return apiErr
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		allRulesets = append(allRulesets, rulesets...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+
+	return allRulesets, nil
+}
+
+// getRuleset fetches a single ruleset definition
+func (c *GitHubClient) getRuleset(ctx context.Context, path string) (*Ruleset, error) {
+	ruleset := new(Ruleset)
+	err := c.ExecuteWithRateLimit(ctx, func() error {
+		req, reqErr := c.Client.NewRequest("GET", path, nil)
+		if reqErr != nil {
+			return reqErr
+		}
+		_, apiErr := c.Client.Do(ctx, req, ruleset)
+		return apiErr
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ruleset, nil
+}
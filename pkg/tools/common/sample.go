@@ -0,0 +1,97 @@
+package common
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// Sample is the share of the discovered repositories a run checks, for estates too large to scan in one run
+// Repositories are put in a fixed pseudo-random order and split into slices of the sample's size,
+// each round checking the next slice, so consecutive rounds cover every repository
+type Sample struct {
+	Count   int     // Repositories checked per listing, 0 when Percent is set
+	Percent float64 // Percentage of the repositories checked per listing, 0 when Count is set
+	Seed    int64   // Round of the rotation, the same seed checks the same repositories
+}
+
+// sample is the sample of the process, nil when every repository is checked
+var sample atomic.Pointer[Sample]
+
+// ParseSample parses a sample size given as a number of repositories, e.g. "500", or a percentage, e.g. "10%"
+func ParseSample(value string, seed int64) (Sample, error) {
+	if percent, ok := strings.CutSuffix(value, "%"); ok {
+		p, err := strconv.ParseFloat(percent, 64)
+		if err != nil || p <= 0 || p > 100 {
+			return Sample{}, fmt.Errorf("invalid sample %q: percentage must be above 0 and at most 100", value)
+		}
+		return Sample{Percent: p, Seed: seed}, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return Sample{}, fmt.Errorf("invalid sample %q: must be a positive number of repositories or a percentage", value)
+	}
+	return Sample{Count: n, Seed: seed}, nil
+}
+
+// SetSample checks only a sample of the repositories discovered by all monitors of the process
+// A nil sample checks every repository
+func SetSample(s *Sample) {
+	sample.Store(s)
+}
+
+// sampleRepositories keeps the repositories of the sample's current slice, in their listed order
+func sampleRepositories(repos []*github.Repository) []*github.Repository {
+	s := sample.Load()
+	if s == nil || len(repos) == 0 {
+		return repos
+	}
+
+	size := s.Count
+	if s.Percent > 0 {
+		size = int(math.Ceil(float64(len(repos)) * s.Percent / 100))
+	}
+	if size >= len(repos) {
+		return repos
+	}
+
+	// Order the repositories by a hash of their name, so the order is random but the same in every run
+	order := make([]int, len(repos))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return sampleHash(repos[order[a]]) < sampleHash(repos[order[b]])
+	})
+
+	slices := (len(repos) + size - 1) / size
+	slice := int(((s.Seed % int64(slices)) + int64(slices)) % int64(slices))
+	end := min((slice+1)*size, len(repos))
+
+	selected := make(map[int]bool, size)
+	for _, i := range order[slice*size : end] {
+		selected[i] = true
+	}
+
+	kept := make([]*github.Repository, 0, len(selected))
+	for i, repo := range repos {
+		if selected[i] {
+			kept = append(kept, repo)
+		}
+	}
+	return kept
+}
+
+// sampleHash places a repository in the order of the sample
+func sampleHash(repo *github.Repository) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(strings.ToLower(repo.GetFullName())))
+	return h.Sum64()
+}
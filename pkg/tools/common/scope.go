@@ -0,0 +1,136 @@
+package common
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// scopeKey is the context key of the scope of a monitor run
+type scopeKey struct{}
+
+// scope holds the coverage and API usage of a monitor run, so monitors running at the same time
+// each report their own
+type scope struct {
+	mu       sync.Mutex
+	coverage Coverage
+	resumed  map[string]bool // Targets checked by the run being resumed
+
+	apiCalls, limiterWait, apiTime atomic.Int64
+	cacheHits, cacheMisses         atomic.Int64
+	maxCalls                       atomic.Int64 // API call cap of the run, unlimited when 0
+}
+
+// processScope holds the coverage of targets checked outside the scope of a monitor run, e.g. by tests
+var processScope = &scope{}
+
+// WithScope returns a context that keeps the coverage and API usage of a monitor run apart from those of
+// monitors running at the same time. The monitor must check its targets and send its requests with the context
+func WithScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, scopeKey{}, &scope{})
+}
+
+// LimitScope caps the API calls of the monitor run the context belongs to, e.g. to its share of the hourly
+// rate limit. Beyond the cap the run stops like it does when the process budget is used up. 0 removes the cap
+func LimitScope(ctx context.Context, max int64) {
+	if s := scopeOf(ctx); s != nil {
+		s.maxCalls.Store(max)
+	}
+}
+
+// budgetExceeded reports whether the run used up its API call cap, false outside monitor runs
+func (s *scope) budgetExceeded() bool {
+	if s == nil {
+		return false
+	}
+	max := s.maxCalls.Load()
+	return max > 0 && s.apiCalls.Load() >= max
+}
+
+// scopeOf returns the scope of a monitor run the context belongs to, nil outside monitor runs
+func scopeOf(ctx context.Context) *scope {
+	if ctx == nil {
+		return nil
+	}
+	s, _ := ctx.Value(scopeKey{}).(*scope)
+	return s
+}
+
+// coverageScope returns the scope whose coverage targets checked with the context count towards
+func coverageScope(ctx context.Context) *scope {
+	if s := scopeOf(ctx); s != nil {
+		return s
+	}
+	return processScope
+}
+
+// Usage is the GitHub API usage of a monitor run or of the whole process
+type Usage struct {
+	APICalls    int64   // Requests sent, including rate limit checks
+	Timings     Timings // Where the time of the requests went
+	CacheHits   int64   // Membership lookups answered from the cache
+	CacheMisses int64   // Membership lookups that needed an API call
+}
+
+// UsageOf returns the API usage so far of the monitor run the context belongs to,
+// or that of all clients of the process outside monitor runs
+func UsageOf(ctx context.Context) Usage {
+	s := scopeOf(ctx)
+	if s == nil {
+		return Usage{
+			APICalls:    apiCalls.Load(),
+			Timings:     APITimings(),
+			CacheHits:   membershipHits.Load(),
+			CacheMisses: membershipMisses.Load(),
+		}
+	}
+	return Usage{
+		APICalls:    s.apiCalls.Load(),
+		Timings:     Timings{Limiter: time.Duration(s.limiterWait.Load()), API: time.Duration(s.apiTime.Load())},
+		CacheHits:   s.cacheHits.Load(),
+		CacheMisses: s.cacheMisses.Load(),
+	}
+}
+
+// limitersKey is the context key of the rate limiters shared by the clients of a run
+type limitersKey struct{}
+
+// sharedLimiters holds a rate limiter per token, by tokenKey
+type sharedLimiters struct {
+	mu      sync.Mutex
+	byToken map[string]*rate.Limiter
+}
+
+// WithSharedRateLimiters returns a context in which clients created by NewGitHubClient share a rate limiter
+// per token, so monitors running at the same time do not multiply the request rate of their token
+func WithSharedRateLimiters(ctx context.Context) context.Context {
+	return context.WithValue(ctx, limitersKey{}, &sharedLimiters{byToken: make(map[string]*rate.Limiter)})
+}
+
+// rateLimiter returns the rate limiter of a new client with the token: the one shared by the clients created
+// with the context when there is one, otherwise a limiter of its own
+func rateLimiter(ctx context.Context, key string) *rate.Limiter {
+	shared, _ := ctx.Value(limitersKey{}).(*sharedLimiters)
+	if shared == nil {
+		return newRateLimiter()
+	}
+
+	shared.mu.Lock()
+	defer shared.mu.Unlock()
+	limiter, ok := shared.byToken[key]
+	if !ok {
+		limiter = newRateLimiter()
+		shared.byToken[key] = limiter
+	}
+	return limiter
+}
+
+// newRateLimiter creates the rate limiter of a token
+// GitHub's API allows 5000 requests per hour for authenticated requests
+// We'll set a conservative limit of 4500 per hour (1.25 per second)
+func newRateLimiter() *rate.Limiter {
+	return rate.NewLimiter(rate.Limit(1.25), 1)
+}
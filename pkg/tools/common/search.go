@@ -0,0 +1,85 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+	"golang.org/x/time/rate"
+)
+
+// MaxSearchResults is the most results the search API returns for a query, however many match
+const MaxSearchResults = 1000
+
+// searchLimiters holds the search rate limiter of each token, by tokenKey
+// GitHub allows 30 search requests per minute, separately from the core rate limit
+var (
+	searchLimitersMu sync.Mutex
+	searchLimiters   = make(map[string]*rate.Limiter)
+)
+
+// searchLimiter returns the search rate limiter shared by all clients of the token
+func searchLimiter(key string) *rate.Limiter {
+	searchLimitersMu.Lock()
+	defer searchLimitersMu.Unlock()
+	limiter, ok := searchLimiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(0.5), 1)
+		searchLimiters[key] = limiter
+	}
+	return limiter
+}
+
+// searchTimeLayout formats times in search qualifiers
+const searchTimeLayout = "2006-01-02T15:04:05Z"
+
+// SearchMergedPullRequests finds the pull requests of org merged from from up to to with the search API,
+// in a few requests instead of listing the pull requests of each repository
+// It also returns how many pull requests matched, more than were returned when there are over
+// MaxSearchResults, in which case the caller should search shorter periods
+func (c *GitHubClient) SearchMergedPullRequests(ctx context.Context, org string, from, to time.Time) ([]*github.Issue, int, error) {
+	if org == "" {
+		return nil, 0, fmt.Errorf("organization name cannot be empty")
+	}
+
+	query := fmt.Sprintf("is:pr is:merged org:%s merged:%s..%s", org,
+		from.UTC().Format(searchTimeLayout), to.UTC().Format(searchTimeLayout))
+	opts := &github.SearchOptions{
+		Sort:        "updated",
+		Order:       "desc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var issues []*github.Issue
+	total := 0
+	page := 1
+	for {
+		opts.Page = page
+		if err := searchLimiter(c.tokenKey).Wait(ctx); err != nil {
+			return nil, 0, err
+		}
+
+		var result *github.IssuesSearchResult
+		var resp *github.Response
+		err := c.ExecuteWithRateLimit(ctx, func() error {
+			var apiErr error
+			result, resp, apiErr = c.Client.Search.Issues(ctx, query, opts)
+			return apiErr
+		})
+		if err != nil {
+			return nil, 0, fmt.Errorf("error searching merged pull requests of organization %s: %v", org, err)
+		}
+
+		total = result.GetTotal()
+		issues = append(issues, result.Issues...)
+
+		if resp.NextPage == 0 || len(issues) >= MaxSearchResults {
+			break
+		}
+		page = resp.NextPage
+	}
+
+	return issues, total, nil
+}
@@ -0,0 +1,89 @@
+package common
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// Shard is the part of the repositories a run checks when a scan is split across parallel runs, e.g. CI jobs
+// Repositories are assigned to shards by a hash of their name, so the shards of a scan check each repository once
+// and every run of a shard checks the same repositories
+type Shard struct {
+	Index int // Shard checked by the run, from 1 to Count
+	Count int // Shards the scan is split into
+}
+
+// shard is the shard of the process, nil when every repository is checked
+var shard atomic.Pointer[Shard]
+
+// ParseShard parses a shard given as its index and the number of shards, e.g. "2/5"
+func ParseShard(value string) (Shard, error) {
+	index, count, ok := strings.Cut(value, "/")
+	if !ok {
+		return Shard{}, fmt.Errorf("invalid shard %q: must be the shard and the number of shards, e.g. 2/5", value)
+	}
+	i, err := strconv.Atoi(index)
+	if err != nil {
+		return Shard{}, fmt.Errorf("invalid shard %q: %q is not a number", value, index)
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 1 {
+		return Shard{}, fmt.Errorf("invalid shard %q: the number of shards must be a positive number", value)
+	}
+	if i < 1 || i > n {
+		return Shard{}, fmt.Errorf("invalid shard %q: the shard must be between 1 and %d", value, n)
+	}
+	return Shard{Index: i, Count: n}, nil
+}
+
+// String returns the shard as given to --shard, e.g. "2/5"
+func (s Shard) String() string {
+	return fmt.Sprintf("%d/%d", s.Index, s.Count)
+}
+
+// Contains reports whether a repository, named "owner/repo", belongs to the shard
+func (s Shard) Contains(repository string) bool {
+	h := fnv.New64a()
+	h.Write([]byte(strings.ToLower(repository)))
+	return int(h.Sum64()%uint64(s.Count)) == s.Index-1
+}
+
+// SetShard checks only the repositories of a shard, for all monitors of the process
+// A nil shard checks every repository
+func SetShard(s *Shard) {
+	shard.Store(s)
+}
+
+// CurrentShard returns the shard of the process, nil when every repository is checked
+func CurrentShard() *Shard {
+	return shard.Load()
+}
+
+// inShard reports whether a target is checked by the shard of the process
+// Organizations are checked by every shard, as the repositories they list are split between the shards
+func inShard(target string) bool {
+	s := shard.Load()
+	if s == nil || strings.HasPrefix(target, "org:") || !strings.Contains(target, "/") {
+		return true
+	}
+	return s.Contains(target)
+}
+
+// shardRepositories keeps the repositories of the shard of the process, in their listed order
+func shardRepositories(repos []*github.Repository) []*github.Repository {
+	if shard.Load() == nil {
+		return repos
+	}
+	kept := make([]*github.Repository, 0, len(repos))
+	for _, repo := range repos {
+		if inShard(repo.GetFullName()) {
+			kept = append(kept, repo)
+		}
+	}
+	return kept
+}
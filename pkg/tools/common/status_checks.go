@@ -0,0 +1,118 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// branchProtection is the protection summary of a branch, as returned with the branch to users with read access
+// The go-github version we depend on leaves it out of Branch, so the type is declared here
+type branchProtection struct {
+	Protection struct {
+		RequiredStatusChecks *github.RequiredStatusChecks `json:"required_status_checks"`
+	} `json:"protection"`
+}
+
+// GetRequiredStatusChecks gets the status checks branch protection requires on a branch, none when the branch
+// is unprotected or does not exist. Checks required by the deprecated contexts list have no app
+func (c *GitHubClient) GetRequiredStatusChecks(ctx context.Context, owner, repo, branch string) ([]*github.RequiredStatusCheck, error) {
+	branchInfo := new(branchProtection)
+	err := c.ExecuteWithRateLimit(ctx, func() error {
+		req, reqErr := c.Client.NewRequest("GET", fmt.Sprintf("repos/%s/%s/branches/%s", owner, repo, url.PathEscape(branch)), nil)
+		if reqErr != nil {
+			return reqErr
+		}
+		_, apiErr := c.Client.Do(ctx, req, branchInfo)
+		return apiErr
+	})
+
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting branch %s of %s/%s: %v", branch, owner, repo, err)
+	}
+
+	required := branchInfo.Protection.RequiredStatusChecks
+	if required == nil {
+		return nil, nil
+	}
+	if len(required.Checks) > 0 {
+		return required.Checks, nil
+	}
+
+	checks := make([]*github.RequiredStatusCheck, 0, len(required.Contexts))
+	for _, name := range required.Contexts {
+		checks = append(checks, &github.RequiredStatusCheck{Context: name})
+	}
+	return checks, nil
+}
+
+// ListCommitStatuses lists the statuses posted on a commit, newest first
+// Pages are fetched with the pagination of the context, see WithPagination
+func (c *GitHubClient) ListCommitStatuses(ctx context.Context, owner, repo, ref string) ([]*github.RepoStatus, error) {
+	pagination := paginationOf(ctx)
+	opts := &github.ListOptions{PerPage: pagination.PerPage()}
+
+	var allStatuses []*github.RepoStatus
+	for pages := 1; ; pages++ {
+		var statuses []*github.RepoStatus
+		var resp *github.Response
+		err := c.ExecuteWithRateLimit(ctx, func() error {
+			var apiErr error
+			statuses, resp, apiErr = c.Client.Repositories.ListStatuses(ctx, owner, repo, ref, opts)
+			return apiErr
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing statuses of %s in %s/%s: %v", ref, owner, repo, err)
+		}
+
+		allStatuses = append(allStatuses, statuses...)
+
+		if !morePages(pagination, pages, resp, fmt.Sprintf("statuses of %s in %s/%s", ref, owner, repo)) {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return allStatuses, nil
+}
+
+// ListCheckRuns lists the latest check runs of a commit, with the app that created each
+// Pages are fetched with the pagination of the context, see WithPagination
+func (c *GitHubClient) ListCheckRuns(ctx context.Context, owner, repo, ref string) ([]*github.CheckRun, error) {
+	pagination := paginationOf(ctx)
+	opts := &github.ListCheckRunsOptions{
+		Filter:      github.String("latest"),
+		ListOptions: github.ListOptions{PerPage: pagination.PerPage()},
+	}
+
+	var allRuns []*github.CheckRun
+	for pages := 1; ; pages++ {
+		var runs *github.ListCheckRunsResults
+		var resp *github.Response
+		err := c.ExecuteWithRateLimit(ctx, func() error {
+			var apiErr error
+			runs, resp, apiErr = c.Client.Checks.ListCheckRunsForRef(ctx, owner, repo, ref, opts)
+			return apiErr
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing check runs of %s in %s/%s: %v", ref, owner, repo, err)
+		}
+
+		allRuns = append(allRuns, runs.CheckRuns...)
+
+		if !morePages(pagination, pages, resp, fmt.Sprintf("check runs of %s in %s/%s", ref, owner, repo)) {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return allRuns, nil
+}
@@ -0,0 +1,95 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	"github.com/google/go-github/v45/github"
+)
+
+func TestFilterRepositories(t *testing.T) {
+	yes, no := true, false
+	repos := []*github.Repository{
+		{FullName: github.String("org/api"), Language: github.String("Go"), Size: github.Int(500), Topics: []string{"service"}},
+		{FullName: github.String("org/web"), Language: github.String("TypeScript"), Size: github.Int(5000)},
+		{FullName: github.String("org/api-fork"), Language: github.String("Go"), Size: github.Int(500), Fork: github.Bool(true)},
+		{FullName: github.String("org/legacy"), Language: github.String("Go"), Size: github.Int(20000), Archived: github.Bool(true)},
+		{FullName: github.String("org/starter"), Language: github.String("Go"), Size: github.Int(10), IsTemplate: github.Bool(true)},
+	}
+
+	tests := []struct {
+		name     string
+		filters  config.Filters
+		expected []string
+	}{
+		{
+			name:     "No filters keep every repository",
+			expected: []string{"org/api", "org/web", "org/api-fork", "org/legacy", "org/starter"},
+		},
+		{
+			name:     "Non-fork Go repositories",
+			filters:  config.Filters{Languages: []string{"go"}, IsFork: &no},
+			expected: []string{"org/api", "org/legacy", "org/starter"},
+		},
+		{
+			name:     "Size bounds",
+			filters:  config.Filters{MinSizeKB: 100, MaxSizeKB: 10000},
+			expected: []string{"org/api", "org/web", "org/api-fork"},
+		},
+		{
+			name:     "Only archived repositories",
+			filters:  config.Filters{IsArchived: &yes},
+			expected: []string{"org/legacy"},
+		},
+		{
+			name:     "No templates",
+			filters:  config.Filters{IsTemplate: &no},
+			expected: []string{"org/api", "org/web", "org/api-fork", "org/legacy"},
+		},
+		{
+			name:     "Exclude archived",
+			filters:  config.Filters{ExcludeArchived: true},
+			expected: []string{"org/api", "org/web", "org/api-fork", "org/starter"},
+		},
+		{
+			name:     "Only archived overrides exclude archived",
+			filters:  config.Filters{ExcludeArchived: true, IsArchived: &yes},
+			expected: []string{"org/legacy"},
+		},
+		{
+			name:     "Topic and exclusions",
+			filters:  config.Filters{Topic: "Service", Exclusions: []string{"org/web"}},
+			expected: []string{"org/api"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := common.WithScope(context.Background())
+			kept, filtered := common.FilterRepositories(ctx, repos, tc.filters)
+
+			if len(kept) != len(tc.expected) {
+				t.Fatalf("Expected %d repositories, got %d", len(tc.expected), len(kept))
+			}
+			for i, repo := range kept {
+				if repo.GetFullName() != tc.expected[i] {
+					t.Errorf("Expected repository %d to be %s, got %s", i, tc.expected[i], repo.GetFullName())
+				}
+			}
+			if filtered != len(repos)-len(tc.expected) {
+				t.Errorf("Expected %d repositories filtered out, got %d", len(repos)-len(tc.expected), filtered)
+			}
+
+			// Only archived repositories left out by exclude_archived are counted in the coverage
+			archived := 0
+			if tc.filters.ExcludeArchived && tc.filters.IsArchived == nil {
+				archived = 1
+			}
+			if got := common.TakeCoverage(ctx).Archived; got != archived {
+				t.Errorf("Expected %d archived repositories in the coverage, got %d", archived, got)
+			}
+		})
+	}
+}
@@ -3,10 +3,15 @@ package test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/anupsv/git-monitoring/pkg/config"
 	"github.com/anupsv/git-monitoring/pkg/tools/common"
 	"github.com/google/go-github/v45/github"
 	"golang.org/x/time/rate"
@@ -212,6 +217,16 @@ func TestListRepositoryMethods(t *testing.T) {
 			t.Errorf("Expected error to contain 'organization name cannot be empty', got %v", err)
 		}
 	})
+
+	// Team repositories are validated before any request
+	t.Run("Team repositories", func(t *testing.T) {
+		if _, err := client.ListTeamRepositories(ctx, "testorg", "", "all"); err == nil || !strings.Contains(err.Error(), "cannot be empty") {
+			t.Errorf("Expected an error for an empty team, got %v", err)
+		}
+		if _, err := client.ListTeamRepositories(ctx, "testorg", "platform", "invalid"); err == nil || !strings.Contains(err.Error(), "invalid repository visibility") {
+			t.Errorf("Expected an error for an invalid visibility, got %v", err)
+		}
+	})
 }
 
 func TestParseRepositoryEdgeCases(t *testing.T) {
@@ -267,3 +282,269 @@ func TestParseRepositoryEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestAPICalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/rate_limit" {
+			w.Write([]byte(`{"resources": {"core": {"limit": 5000, "remaining": 4999}}}`))
+			return
+		}
+		w.Write([]byte(`{"login": "monitor-bot"}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := common.NewGitHubClient(ctx, "token")
+	client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	before := common.APICalls()
+	user, err := client.GetAuthenticatedUser(ctx)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if user.GetLogin() != "monitor-bot" {
+		t.Errorf("Expected login monitor-bot, got %q", user.GetLogin())
+	}
+
+	// The request and the rate limit check are both counted
+	if calls := common.APICalls() - before; calls != 2 {
+		t.Errorf("Expected 2 API calls, got %d", calls)
+	}
+}
+
+func TestLatestRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/rate_limit" {
+			w.Write([]byte(`{"resources": {"core": {"limit": 5000, "remaining": 4999}}}`))
+			return
+		}
+		w.Header().Set("X-RateLimit-Resource", "core")
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4321")
+		w.Header().Set("X-RateLimit-Reset", "1700000000")
+		w.Write([]byte(`{"login": "monitor-bot"}`))
+	}))
+	defer server.Close()
+
+	if _, ok := common.LatestRateLimit("rate-limit-token"); ok {
+		t.Fatalf("Expected no rate limit before any request")
+	}
+
+	ctx := context.Background()
+	client := common.NewGitHubClient(ctx, "rate-limit-token")
+	client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+	if _, err := client.GetAuthenticatedUser(ctx); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	limit, ok := common.LatestRateLimit("rate-limit-token")
+	if !ok {
+		t.Fatalf("Expected the rate limit of the response to be recorded")
+	}
+	if limit.Limit != 5000 || limit.Remaining != 4321 || !limit.Reset.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Unexpected rate limit: %+v", limit)
+	}
+
+	// Rate limits are kept per token
+	if _, ok := common.LatestRateLimit("other-token"); ok {
+		t.Errorf("Expected no rate limit for a token without requests")
+	}
+}
+
+func TestAPICallBudget(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/rate_limit" {
+			w.Write([]byte(`{"resources": {"core": {"limit": 5000, "remaining": 4999}}}`))
+			return
+		}
+		w.Write([]byte(`{"login": "monitor-bot"}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := common.NewGitHubClient(ctx, "token")
+	client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	// Allow a single call with its rate limit check
+	common.SetAPICallBudget(common.APICalls() + 2)
+	defer common.SetAPICallBudget(0)
+
+	common.TakeCoverage(ctx)
+	if common.SkipIfStopped(ctx, "owner/first") {
+		t.Fatalf("Did not expect the scan to be stopped before the budget is used up")
+	}
+	if _, err := client.GetAuthenticatedUser(ctx); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	before := common.APICalls()
+	_, _, err := client.Client.Users.Get(ctx, "")
+	if !errors.Is(err, common.ErrBudgetExceeded) {
+		t.Fatalf("Expected the budget to be exceeded, got %v", err)
+	}
+	if _, err := client.GetAuthenticatedUser(ctx); err == nil {
+		t.Fatalf("Expected an error beyond the budget")
+	}
+	if calls := common.APICalls() - before; calls != 0 {
+		t.Errorf("Expected no requests beyond the budget, got %d", calls)
+	}
+	if common.Stopped() != common.StopBudget {
+		t.Errorf("Expected the scan to be stopped by the budget, got %q", common.Stopped())
+	}
+
+	// The budget ran out on the first target, which is skipped rather than checked
+	if !common.SkipIfBudgetExceeded(ctx, "owner/first", err) || !common.SkipIfStopped(ctx, "owner/second") {
+		t.Errorf("Expected the targets to be skipped")
+	}
+	coverage := common.TakeCoverage(ctx)
+	if len(coverage.Checked) != 0 || len(coverage.Skipped) != 2 || coverage.Skipped[0].Target != "owner/first" || coverage.Skipped[1].Reason != common.StopBudget {
+		t.Errorf("Unexpected coverage: %+v", coverage)
+	}
+	if coverage := common.TakeCoverage(ctx); len(coverage.Checked) != 0 || len(coverage.Skipped) != 0 {
+		t.Errorf("Expected the coverage to be forgotten once taken, got %+v", coverage)
+	}
+}
+
+func TestDeadline(t *testing.T) {
+	ctx := context.Background()
+	common.TakeCoverage(ctx)
+	defer common.SetDeadline(time.Time{})
+
+	common.SetDeadline(time.Now().Add(time.Hour))
+	if common.SkipIfStopped(ctx, "owner/first") {
+		t.Errorf("Did not expect the scan to be stopped before the deadline")
+	}
+
+	common.SetDeadline(time.Now().Add(-time.Second))
+	if common.Stopped() != common.StopDeadline {
+		t.Errorf("Expected the scan to be stopped by the deadline, got %q", common.Stopped())
+	}
+	if !common.SkipIfStopped(ctx, "owner/second") {
+		t.Errorf("Expected targets to be skipped after the deadline")
+	}
+
+	// Checks failing after the deadline are failures, only the budget turns them into skips
+	if common.SkipIfBudgetExceeded(ctx, "owner/first", errors.New("not found")) {
+		t.Errorf("Did not expect a failure to be skipped without a budget")
+	}
+
+	coverage := common.TakeCoverage(ctx)
+	if len(coverage.Checked) != 1 || len(coverage.Skipped) != 1 || coverage.Skipped[0].Reason != common.StopDeadline || len(coverage.Errored) != 1 {
+		t.Errorf("Unexpected coverage: %+v", coverage)
+	}
+
+	common.SetDeadline(time.Time{})
+	if common.Stopped() != "" {
+		t.Errorf("Expected no deadline once removed, got %q", common.Stopped())
+	}
+}
+
+func TestResumeCoverage(t *testing.T) {
+	ctx := context.Background()
+	common.TakeCoverage(ctx)
+	if err := common.SaveCursor(ctx, "owner/b", 3, []int{1, 2}); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	interrupted := common.TakeCoverage(ctx)
+
+	interrupted.Checked = []string{"owner/a"}
+	common.ResumeCoverage(ctx, interrupted)
+
+	if !common.SkipIfStopped(ctx, "owner/a") {
+		t.Errorf("Expected a target checked by the resumed run not to be checked again")
+	}
+	if common.SkipIfStopped(ctx, "owner/b") {
+		t.Errorf("Expected a target the resumed run stopped within to be checked")
+	}
+
+	var data []int
+	if page := common.ResumeCursor(ctx, "owner/b", &data); page != 3 || len(data) != 2 {
+		t.Errorf("Expected to resume from page 3 with the earlier results, got page %d with %v", page, data)
+	}
+	if page := common.ResumeCursor(ctx, "owner/b", &data); page != 0 {
+		t.Errorf("Expected a cursor to be handed out once, got page %d", page)
+	}
+
+	coverage := common.TakeCoverage(ctx)
+	if len(coverage.Checked) != 2 || coverage.Checked[0] != "owner/a" || coverage.Checked[1] != "owner/b" || len(coverage.Cursors) != 0 {
+		t.Errorf("Unexpected coverage: %+v", coverage)
+	}
+
+	// Resumed targets are only skipped for the monitor that resumed them
+	if common.SkipIfStopped(ctx, "owner/a") {
+		t.Errorf("Did not expect targets to be skipped once the coverage is taken")
+	}
+	common.TakeCoverage(ctx)
+}
+
+func TestAPITimings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/rate_limit" {
+			w.Write([]byte(`{"resources": {"core": {"limit": 5000, "remaining": 4999}}}`))
+			return
+		}
+		w.Write([]byte(`{"login": "monitor-bot"}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := common.NewGitHubClient(ctx, "token")
+	client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	before := common.APITimings()
+	err := client.ExecuteWithRateLimit(ctx, func() error {
+		_, _, err := client.Client.Users.Get(ctx, "")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	// Time spent waiting for GitHub is attributed to the API, not to the limiter
+	after := common.APITimings()
+	if api := after.API - before.API; api < 20*time.Millisecond {
+		t.Errorf("Expected at least 20ms of API time, got %v", api)
+	}
+	if after.Limiter < before.Limiter {
+		t.Errorf("Expected limiter time not to decrease, got %v after %v", after.Limiter, before.Limiter)
+	}
+}
+
+func TestListingPagination(t *testing.T) {
+	// Every page links to a next one, so only the page cap ends the listing
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/rate_limit" {
+			w.Write([]byte(`{"resources": {"core": {"limit": 5000, "remaining": 4999}}}`))
+			return
+		}
+		requests = append(requests, r.URL.Query().Get("per_page"))
+		w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=%d>; rel="next"`, "http://"+r.Host, r.URL.Path, len(requests)+1))
+		w.Write([]byte(`{"total_count": 1, "workflow_runs": [{"id": 1}]}`))
+	}))
+	defer server.Close()
+
+	client := common.NewGitHubClient(context.Background(), "token")
+	client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+	client.RateLimiter = rate.NewLimiter(rate.Inf, 1)
+
+	ctx := common.WithPagination(context.Background(), config.PaginationConfig{PageSize: 10, MaxPages: 2})
+	runs, err := client.ListWorkflowRuns(ctx, "owner", "repo", "pull_request_target", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if len(runs) != 2 || len(requests) != 2 {
+		t.Errorf("Expected 2 pages of runs, got %d runs in %d requests", len(runs), len(requests))
+	}
+	for _, perPage := range requests {
+		if perPage != "10" {
+			t.Errorf("Expected pages of 10 runs, got per_page=%s", perPage)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+// newMembershipServer serves the membership endpoints for alice, a member of acme and its core team,
+// and bob, who is neither, counting the lookups
+func newMembershipServer(t *testing.T, lookups *atomic.Int64) *common.GitHubClient {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/rate_limit":
+			w.Write([]byte(`{"resources": {"core": {"limit": 5000, "remaining": 4999}}}`))
+		case "/orgs/acme/members/alice":
+			lookups.Add(1)
+			w.WriteHeader(http.StatusNoContent)
+		case "/orgs/acme/teams/core/memberships/alice":
+			lookups.Add(1)
+			w.Write([]byte(`{"state": "active", "role": "member"}`))
+		case "/orgs/acme/teams/core/memberships/carol":
+			lookups.Add(1)
+			w.Write([]byte(`{"state": "pending", "role": "member"}`))
+		case "/orgs/acme/members":
+			lookups.Add(1)
+			w.Write([]byte(`[{"login": "dave"}]`))
+		default:
+			lookups.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Not Found"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := common.NewGitHubClient(context.Background(), "membership-token")
+	client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+	return client
+}
+
+func TestMembershipLookups(t *testing.T) {
+	common.ResetMembershipCache()
+	defer common.ResetMembershipCache()
+
+	var lookups atomic.Int64
+	client := newMembershipServer(t, &lookups)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		lookup func() (bool, error)
+		member bool
+	}{
+		{"Organization member", func() (bool, error) { return client.IsOrganizationMember(ctx, "acme", "alice") }, true},
+		{"Not an organization member", func() (bool, error) { return client.IsOrganizationMember(ctx, "acme", "bob") }, false},
+		{"Team member", func() (bool, error) { return client.IsTeamMember(ctx, "acme", "core", "alice") }, true},
+		{"Not a team member", func() (bool, error) { return client.IsTeamMember(ctx, "acme", "core", "bob") }, false},
+		{"Pending team invitation", func() (bool, error) { return client.IsTeamMember(ctx, "acme", "core", "carol") }, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			before := lookups.Load()
+			for i := 0; i < 3; i++ {
+				member, err := tc.lookup()
+				if err != nil {
+					t.Fatalf("Did not expect an error but got: %v", err)
+				}
+				if member != tc.member {
+					t.Errorf("Expected member %v, got %v", tc.member, member)
+				}
+			}
+
+			// Only the first lookup calls the API
+			if calls := lookups.Load() - before; calls != 1 {
+				t.Errorf("Expected 1 lookup, got %d", calls)
+			}
+		})
+	}
+
+	// Listing the members of an organization answers later lookups of its members
+	if _, err := client.ListOrganizationMembers(ctx, "acme", "all"); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	before := lookups.Load()
+	member, err := client.IsOrganizationMember(ctx, "ACME", "Dave")
+	if err != nil || !member {
+		t.Errorf("Expected dave to be a member, got %v, %v", member, err)
+	}
+	if calls := lookups.Load() - before; calls != 0 {
+		t.Errorf("Expected no lookup for a listed member, got %d", calls)
+	}
+}
+
+func TestMembershipCacheAcrossRuns(t *testing.T) {
+	common.ResetMembershipCache()
+	defer common.ResetMembershipCache()
+
+	var lookups atomic.Int64
+	client := newMembershipServer(t, &lookups)
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "membership.json")
+
+	// A missing file starts an empty cache
+	if err := common.LoadMembershipCache(path, time.Hour); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if _, err := client.IsOrganizationMember(ctx, "acme", "alice"); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if err := common.SaveMembershipCache(path); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	// The next run reuses the saved lookup
+	common.ResetMembershipCache()
+	if err := common.LoadMembershipCache(path, time.Hour); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	before := lookups.Load()
+	if member, err := client.IsOrganizationMember(ctx, "acme", "alice"); err != nil || !member {
+		t.Errorf("Expected alice to be a member, got %v, %v", member, err)
+	}
+	if calls := lookups.Load() - before; calls != 0 {
+		t.Errorf("Expected the saved lookup to be reused, got %d lookups", calls)
+	}
+
+	// Lookups older than the ttl are looked up again
+	time.Sleep(10 * time.Millisecond)
+	if err := common.LoadMembershipCache(path, 5*time.Millisecond); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	before = lookups.Load()
+	if _, err := client.IsOrganizationMember(ctx, "acme", "alice"); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if calls := lookups.Load() - before; calls != 1 {
+		t.Errorf("Expected an expired lookup to be looked up again, got %d lookups", calls)
+	}
+
+	// A corrupt cache is reported
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := common.LoadMembershipCache(path, time.Hour); err == nil {
+		t.Error("Expected an error for a corrupt cache")
+	}
+}
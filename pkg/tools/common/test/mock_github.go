@@ -2,49 +2,180 @@ package test
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
 	"github.com/google/go-github/v45/github"
 )
 
 // MockGitHubClient is a mock implementation of GitHubClientInterface for testing
 type MockGitHubClient struct {
 	// Mock return values
-	MockPullRequests        []*github.PullRequest
-	MockPullRequestResp     *github.Response
-	MockPullRequestErr      error
-	MockReviews             []*github.PullRequestReview
-	MockReviewResp          *github.Response
-	MockReviewErr           error
-	MockExecuteRateLimitErr error
-	MockRepositories        []*github.Repository
-	MockRepositoriesErr     error
-	MockOrgRepositories     []*github.Repository
-	MockOrgRepositoriesErr  error
-	MockRepoEvents          []*github.Event
-	MockRepoEventsErr       error
-	MockUserOrgEvents       []*github.Event
-	MockUserOrgEventsErr    error
-	MockPublicEvents        []*github.Event
-	MockPublicEventsErr     error
+	MockPullRequests         []*github.PullRequest
+	MockPullRequestResp      *github.Response
+	MockPullRequestErr       error
+	MockReviews              []*github.PullRequestReview
+	MockReviewResp           *github.Response
+	MockReviewErr            error
+	MockSearchIssues         []*github.Issue
+	MockSearchErr            error
+	MockPRCommits            map[int][]*github.RepositoryCommit // Keyed by PR number
+	MockPRCommitsErr         error
+	MockPRFiles              map[int][]string // Changed paths keyed by PR number
+	MockPullRequestsByNumber map[int]*github.PullRequest
+	MockGetPullRequestErr    error
+	MockCommits              map[string]*github.RepositoryCommit // Commits by SHA
+	MockIssueEvents          map[int][]*github.IssueEvent        // Keyed by issue or PR number
+	MockIssueEventsErr       error
+	MockRequiredChecks       map[string][]*github.RequiredStatusCheck // Keyed by "owner/repo:branch"
+	MockCommitStatuses       map[string][]*github.RepoStatus          // Keyed by commit SHA, newest first
+	MockCheckRuns            map[string][]*github.CheckRun            // Keyed by commit SHA
+	MockProtectedBranches    map[string][]*github.Branch              // Keyed by "owner/repo"
+	MockAdminEnforcement     map[string]bool                          // Keyed by "owner/repo:branch", false when missing
+	MockRequiredReviews      map[string]int                           // Required approving reviews keyed by "owner/repo:branch", 0 when missing
+	MockAuditLog             []*github.AuditEntry
+	MockAuditLogErr          error
+	MockGHASCommitters       map[string]*common.AdvancedSecurityCommitters // Keyed by organization
+	MockCodeScanningRepos    map[string]bool                               // Repositories ("owner/repo") with analyses
+	MockExecuteRateLimitErr  error
+	MockRepositories         []*github.Repository
+	MockRepositoriesErr      error
+	MockOrgRepositories      []*github.Repository
+	MockOrgRepositoriesErr   error
+	MockTeamRepositories     map[string][]*github.Repository // Keyed by "org/team"
+	MockTeamRepositoriesErr  error
+	MockRepoEvents           []*github.Event
+	MockRepoEventsErr        error
+	MockUserOrgEvents        []*github.Event
+	MockUserOrgEventsErr     error
+	MockPublicEvents         []*github.Event
+	MockPublicEventsErr      error
+	MockOrgRulesets          []*common.Ruleset
+	MockOrgRulesetsErr       error
+	MockRepoRulesets         []*common.Ruleset
+	MockRepoRulesetsErr      error
+	MockOrgCodeScanAlerts    []*github.Alert
+	MockOrgCodeScanErr       error
+	MockRepoCodeScanAlerts   []*github.Alert
+	MockRepoCodeScanErr      error
+	MockOrgDependabotAlerts  []*common.DependabotAlert
+	MockOrgDependabotErr     error
+	MockRepoDependabotAlerts []*common.DependabotAlert
+	MockRepoDependabotErr    error
+	MockOrgSecretAlerts      []*common.SecretScanningAlert
+	MockOrgSecretAlertsErr   error
+	MockRepoSecretAlerts     []*common.SecretScanningAlert
+	MockRepoSecretAlertsErr  error
+	MockOrgWorkflowPerms     map[string]*common.WorkflowPermissions // Keyed by organization
+	MockRepoWorkflowPerms    map[string]*common.WorkflowPermissions // Keyed by "owner/repo"
+	MockWorkflowPermsErr     error
+	MockEnvironments         map[string]*common.Environment // Keyed by "owner/repo/environment"
+	MockEnvironmentErr       error
+	MockDeployments          map[string][]*github.Deployment // Keyed by "owner/repo/environment"
+	MockDeploymentsErr       error
+	MockDeploymentStatuses   map[int64][]*github.DeploymentStatus // Keyed by deployment ID
+	MockDeploymentStatusErr  error
+	MockWorkflowRuns         map[string][]*common.WorkflowRun // Keyed by "owner/repo/event"
+	MockWorkflowRunsErr      error
+	MockSecretNames          map[string][]string // Keyed by "owner/repo"
+	MockSecretNamesErr       error
+	MockOpenIssues           map[string][]*github.Issue // Keyed by "owner/repo"
+	MockOpenIssuesErr        error
+	MockOrgMembers           []*github.User
+	MockOrgMembersErr        error
+	MockOrgMemberships       map[string]bool // Keyed by "org/user"
+	MockTeamMemberships      map[string]bool // Keyed by "org/team/user"
+	MockMembershipErr        error
+	MockCollaborators        []*github.User
+	MockCollaboratorsErr     error
+	MockRepoTeams            map[string][]*github.Team // Keyed by "owner/repo"
+	MockRepoTeamsErr         error
+	MockLatestUserEvents     map[string]*github.Event
+	MockLatestUserEventErr   error
+	MockRepository           map[string]*github.Repository
+	MockRepositoryErr        error
+	MockLatestIssues         map[string]*github.Issue
+	MockLatestIssueErr       error
+	MockFileContents         map[string]string
+	MockFileContentErr       error
+	MockRepoFiles            map[string][]string // Keyed by "owner/repo"
+	MockRepoFilesTruncated   bool
+	MockAuthenticatedUser    *github.User
+	MockAuthenticatedUserErr error
+	MockTokenInfo            *common.TokenInfo
+	MockTokenInfoErr         error
+	MockOrgAccess            map[string]*common.OrganizationAccess // Keyed by organization, accessible when missing
 
 	// Custom mock functions
 	GetPullRequestsFunc        func(ctx context.Context, owner, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error)
 	ListPullRequestReviewsFunc func(ctx context.Context, owner, repo string, number int, opts *github.ListOptions) ([]*github.PullRequestReview, *github.Response, error)
+	SearchMergedPRsFunc        func(ctx context.Context, org string, from, to time.Time) ([]*github.Issue, int, error)
 	ListUserRepositoriesFunc   func(ctx context.Context, visibility string) ([]*github.Repository, error)
 	ListOrgRepositoriesFunc    func(ctx context.Context, org string, visibility string) ([]*github.Repository, error)
 	ListRepositoryEventsFunc   func(ctx context.Context, owner, repo string) ([]*github.Event, error)
 	ListUserOrgEventsFunc      func(ctx context.Context, org, user string) ([]*github.Event, error)
 	ListPublicEventsFunc       func(ctx context.Context) ([]*github.Event, error)
+	GetOrgRulesetFunc          func(ctx context.Context, org string, id int64) (*common.Ruleset, error)
+	GetRepoRulesetFunc         func(ctx context.Context, owner, repo string, id int64) (*common.Ruleset, error)
+	ListRepoCodeScanAlertsFunc func(ctx context.Context, owner, repo, state string) ([]*github.Alert, error)
 
 	// Tracking calls
-	GetPullRequestsCalls              int
-	ListPullRequestReviewsCalls       int
-	ExecuteWithRateLimitCalls         int
-	ListUserRepositoriesCalls         int
-	ListOrganizationRepositoriesCalls int
-	ListRepositoryEventsCalls         int
-	ListUserOrgEventsCalls            int
-	ListPublicEventsCalls             int
+	GetPullRequestsCalls                 int
+	ListPullRequestReviewsCalls          int
+	SearchMergedPullRequestsCalls        int
+	ListPullRequestCommitsCalls          int
+	ListPullRequestFilesCalls            int
+	GetPullRequestCalls                  int
+	GetCommitCalls                       int
+	ListIssueEventsCalls                 int
+	GetRequiredStatusChecksCalls         int
+	ListCommitStatusesCalls              int
+	ListCheckRunsCalls                   int
+	ListProtectedBranchesCalls           int
+	GetAdminEnforcementCalls             int
+	GetRequiredApprovingReviewCountCalls int
+	ListAuditLogCalls                    int
+	GetAdvancedSecurityCommittersCalls   int
+	HasCodeScanningAnalysesCalls         int
+	ExecuteWithRateLimitCalls            int
+	ListUserRepositoriesCalls            int
+	ListOrganizationRepositoriesCalls    int
+	ListTeamRepositoriesCalls            int
+	ListRepositoryEventsCalls            int
+	ListUserOrgEventsCalls               int
+	ListPublicEventsCalls                int
+	ListOrgRulesetsCalls                 int
+	ListRepoRulesetsCalls                int
+	GetOrgRulesetCalls                   int
+	GetRepoRulesetCalls                  int
+	ListOrgCodeScanAlertsCalls           int
+	ListRepoCodeScanAlertsCalls          int
+	ListOrgDependabotAlertsCalls         int
+	ListRepoDependabotAlertsCalls        int
+	ListOrgSecretAlertsCalls             int
+	ListRepoSecretAlertsCalls            int
+	GetOrgWorkflowPermsCalls             int
+	GetRepoWorkflowPermsCalls            int
+	GetEnvironmentCalls                  int
+	ListDeploymentsCalls                 int
+	ListDeploymentStatusesCalls          int
+	ListWorkflowRunsCalls                int
+	ListActionsSecretNamesCalls          int
+	ListOpenIssuesCalls                  int
+	ListOrgMembersCalls                  int
+	IsOrgMemberCalls                     int
+	IsTeamMemberCalls                    int
+	ListCollaboratorsCalls               int
+	ListRepositoryTeamsCalls             int
+	GetLatestUserEventCalls              int
+	GetRepositoryCalls                   int
+	GetLatestIssueActivityCalls          int
+	GetFileContentCalls                  int
+	ListRepositoryFilesCalls             int
+	GetAuthenticatedUserCalls            int
+	GetTokenInfoCalls                    int
+	GetOrganizationAccessCalls           int
 }
 
 // ExecuteWithRateLimit is a mock implementation
@@ -104,6 +235,135 @@ func (m *MockGitHubClient) ListOrganizationRepositories(ctx context.Context, org
 	return m.MockOrgRepositories, m.MockOrgRepositoriesErr
 }
 
+// SearchMergedPullRequests is a mock implementation
+func (m *MockGitHubClient) SearchMergedPullRequests(ctx context.Context, org string, from, to time.Time) ([]*github.Issue, int, error) {
+	m.SearchMergedPullRequestsCalls++
+
+	// Use custom function if provided
+	if m.SearchMergedPRsFunc != nil {
+		return m.SearchMergedPRsFunc(ctx, org, from, to)
+	}
+
+	return m.MockSearchIssues, len(m.MockSearchIssues), m.MockSearchErr
+}
+
+// ListPullRequestCommits is a mock implementation
+func (m *MockGitHubClient) ListPullRequestCommits(ctx context.Context, owner, repo string, number int) ([]*github.RepositoryCommit, error) {
+	m.ListPullRequestCommitsCalls++
+	if m.MockPRCommitsErr != nil {
+		return nil, m.MockPRCommitsErr
+	}
+	return m.MockPRCommits[number], nil
+}
+
+// ListPullRequestFiles is a mock implementation
+func (m *MockGitHubClient) ListPullRequestFiles(ctx context.Context, owner, repo string, number int) ([]string, error) {
+	m.ListPullRequestFilesCalls++
+	return m.MockPRFiles[number], nil
+}
+
+// GetPullRequest is a mock implementation
+func (m *MockGitHubClient) GetPullRequest(ctx context.Context, owner, repo string, number int) (*github.PullRequest, error) {
+	m.GetPullRequestCalls++
+	if m.MockGetPullRequestErr != nil {
+		return nil, m.MockGetPullRequestErr
+	}
+	if pr, ok := m.MockPullRequestsByNumber[number]; ok {
+		return pr, nil
+	}
+	return nil, fmt.Errorf("pull request %s/%s#%d not found", owner, repo, number)
+}
+
+// GetCommit is a mock implementation
+func (m *MockGitHubClient) GetCommit(ctx context.Context, owner, repo, sha string) (*github.RepositoryCommit, error) {
+	m.GetCommitCalls++
+	if commit, ok := m.MockCommits[sha]; ok {
+		return commit, nil
+	}
+	return nil, fmt.Errorf("commit %s of %s/%s not found", sha, owner, repo)
+}
+
+// ListIssueEvents is a mock implementation
+func (m *MockGitHubClient) ListIssueEvents(ctx context.Context, owner, repo string, number int) ([]*github.IssueEvent, error) {
+	m.ListIssueEventsCalls++
+	if m.MockIssueEventsErr != nil {
+		return nil, m.MockIssueEventsErr
+	}
+	return m.MockIssueEvents[number], nil
+}
+
+// GetRequiredStatusChecks is a mock implementation
+func (m *MockGitHubClient) GetRequiredStatusChecks(ctx context.Context, owner, repo, branch string) ([]*github.RequiredStatusCheck, error) {
+	m.GetRequiredStatusChecksCalls++
+	return m.MockRequiredChecks[owner+"/"+repo+":"+branch], nil
+}
+
+// ListCommitStatuses is a mock implementation
+func (m *MockGitHubClient) ListCommitStatuses(ctx context.Context, owner, repo, ref string) ([]*github.RepoStatus, error) {
+	m.ListCommitStatusesCalls++
+	return m.MockCommitStatuses[ref], nil
+}
+
+// ListCheckRuns is a mock implementation
+func (m *MockGitHubClient) ListCheckRuns(ctx context.Context, owner, repo, ref string) ([]*github.CheckRun, error) {
+	m.ListCheckRunsCalls++
+	return m.MockCheckRuns[ref], nil
+}
+
+// ListProtectedBranches is a mock implementation
+func (m *MockGitHubClient) ListProtectedBranches(ctx context.Context, owner, repo string) ([]*github.Branch, error) {
+	m.ListProtectedBranchesCalls++
+	return m.MockProtectedBranches[owner+"/"+repo], nil
+}
+
+// GetAdminEnforcement is a mock implementation
+func (m *MockGitHubClient) GetAdminEnforcement(ctx context.Context, owner, repo, branch string) (bool, error) {
+	m.GetAdminEnforcementCalls++
+	return m.MockAdminEnforcement[owner+"/"+repo+":"+branch], nil
+}
+
+// GetRequiredApprovingReviewCount is a mock implementation
+func (m *MockGitHubClient) GetRequiredApprovingReviewCount(ctx context.Context, owner, repo, branch string) (int, error) {
+	m.GetRequiredApprovingReviewCountCalls++
+	return m.MockRequiredReviews[owner+"/"+repo+":"+branch], nil
+}
+
+// ListAuditLog is a mock implementation
+func (m *MockGitHubClient) ListAuditLog(ctx context.Context, org, phrase string) ([]*github.AuditEntry, error) {
+	m.ListAuditLogCalls++
+	if m.MockAuditLogErr != nil {
+		return nil, m.MockAuditLogErr
+	}
+	return m.MockAuditLog, nil
+}
+
+// GetAdvancedSecurityCommitters is a mock implementation
+// It returns the seat usage registered for the organization in MockGHASCommitters, or an error without one
+func (m *MockGitHubClient) GetAdvancedSecurityCommitters(_ context.Context, org string) (*common.AdvancedSecurityCommitters, error) {
+	m.GetAdvancedSecurityCommittersCalls++
+	usage, ok := m.MockGHASCommitters[org]
+	if !ok {
+		return nil, fmt.Errorf("advanced security not available for %s", org)
+	}
+	return usage, nil
+}
+
+// HasCodeScanningAnalyses is a mock implementation
+// It reports whether "owner/repo" is registered in MockCodeScanningRepos
+func (m *MockGitHubClient) HasCodeScanningAnalyses(_ context.Context, owner, repo string) (bool, error) {
+	m.HasCodeScanningAnalysesCalls++
+	return m.MockCodeScanningRepos[owner+"/"+repo], nil
+}
+
+// ListTeamRepositories is a mock implementation
+func (m *MockGitHubClient) ListTeamRepositories(ctx context.Context, org, team string, visibility string) ([]*github.Repository, error) {
+	m.ListTeamRepositoriesCalls++
+	if m.MockTeamRepositoriesErr != nil {
+		return nil, m.MockTeamRepositoriesErr
+	}
+	return m.MockTeamRepositories[org+"/"+team], nil
+}
+
 // ListRepositoryEvents is a mock implementation
 func (m *MockGitHubClient) ListRepositoryEvents(ctx context.Context, owner, repo string) ([]*github.Event, error) {
 	m.ListRepositoryEventsCalls++
@@ -139,3 +399,298 @@ func (m *MockGitHubClient) ListRepositoryPublicEvents(ctx context.Context) ([]*g
 
 	return m.MockPublicEvents, m.MockPublicEventsErr
 }
+
+// ListOrganizationRulesets is a mock implementation
+func (m *MockGitHubClient) ListOrganizationRulesets(_ context.Context, _ string) ([]*common.Ruleset, error) {
+	m.ListOrgRulesetsCalls++
+	return m.MockOrgRulesets, m.MockOrgRulesetsErr
+}
+
+// ListRepositoryRulesets is a mock implementation
+func (m *MockGitHubClient) ListRepositoryRulesets(_ context.Context, _, _ string) ([]*common.Ruleset, error) {
+	m.ListRepoRulesetsCalls++
+	return m.MockRepoRulesets, m.MockRepoRulesetsErr
+}
+
+// GetOrganizationRuleset is a mock implementation
+// Without a custom function it returns the matching ruleset from MockOrgRulesets
+func (m *MockGitHubClient) GetOrganizationRuleset(ctx context.Context, org string, id int64) (*common.Ruleset, error) {
+	m.GetOrgRulesetCalls++
+
+	// Use custom function if provided
+	if m.GetOrgRulesetFunc != nil {
+		return m.GetOrgRulesetFunc(ctx, org, id)
+	}
+
+	return findRuleset(m.MockOrgRulesets, id), m.MockOrgRulesetsErr
+}
+
+// GetRepositoryRuleset is a mock implementation
+// Without a custom function it returns the matching ruleset from MockRepoRulesets
+func (m *MockGitHubClient) GetRepositoryRuleset(ctx context.Context, owner, repo string, id int64) (*common.Ruleset, error) {
+	m.GetRepoRulesetCalls++
+
+	// Use custom function if provided
+	if m.GetRepoRulesetFunc != nil {
+		return m.GetRepoRulesetFunc(ctx, owner, repo, id)
+	}
+
+	return findRuleset(m.MockRepoRulesets, id), m.MockRepoRulesetsErr
+}
+
+// findRuleset returns the ruleset with the given ID, or nil if it is not present
+func findRuleset(rulesets []*common.Ruleset, id int64) *common.Ruleset {
+	for _, ruleset := range rulesets {
+		if ruleset.ID == id {
+			return ruleset
+		}
+	}
+	return nil
+}
+
+// ListOrganizationCodeScanningAlerts is a mock implementation
+func (m *MockGitHubClient) ListOrganizationCodeScanningAlerts(_ context.Context, _, _ string) ([]*github.Alert, error) {
+	m.ListOrgCodeScanAlertsCalls++
+	return m.MockOrgCodeScanAlerts, m.MockOrgCodeScanErr
+}
+
+// ListRepositoryCodeScanningAlerts is a mock implementation
+func (m *MockGitHubClient) ListRepositoryCodeScanningAlerts(ctx context.Context, owner, repo, state string) ([]*github.Alert, error) {
+	m.ListRepoCodeScanAlertsCalls++
+
+	// Use custom function if provided
+	if m.ListRepoCodeScanAlertsFunc != nil {
+		return m.ListRepoCodeScanAlertsFunc(ctx, owner, repo, state)
+	}
+
+	return m.MockRepoCodeScanAlerts, m.MockRepoCodeScanErr
+}
+
+// ListOrganizationDependabotAlerts is a mock implementation
+func (m *MockGitHubClient) ListOrganizationDependabotAlerts(_ context.Context, _, _ string) ([]*common.DependabotAlert, error) {
+	m.ListOrgDependabotAlertsCalls++
+	return m.MockOrgDependabotAlerts, m.MockOrgDependabotErr
+}
+
+// ListRepositoryDependabotAlerts is a mock implementation
+func (m *MockGitHubClient) ListRepositoryDependabotAlerts(_ context.Context, _, _, _ string) ([]*common.DependabotAlert, error) {
+	m.ListRepoDependabotAlertsCalls++
+	return m.MockRepoDependabotAlerts, m.MockRepoDependabotErr
+}
+
+// ListOrganizationSecretScanningAlerts is a mock implementation
+func (m *MockGitHubClient) ListOrganizationSecretScanningAlerts(_ context.Context, _ string) ([]*common.SecretScanningAlert, error) {
+	m.ListOrgSecretAlertsCalls++
+	return m.MockOrgSecretAlerts, m.MockOrgSecretAlertsErr
+}
+
+// ListRepositorySecretScanningAlerts is a mock implementation
+func (m *MockGitHubClient) ListRepositorySecretScanningAlerts(_ context.Context, _, _ string) ([]*common.SecretScanningAlert, error) {
+	m.ListRepoSecretAlertsCalls++
+	return m.MockRepoSecretAlerts, m.MockRepoSecretAlertsErr
+}
+
+// GetOrganizationWorkflowPermissions is a mock implementation
+func (m *MockGitHubClient) GetOrganizationWorkflowPermissions(_ context.Context, org string) (*common.WorkflowPermissions, error) {
+	m.GetOrgWorkflowPermsCalls++
+	if m.MockWorkflowPermsErr != nil {
+		return nil, m.MockWorkflowPermsErr
+	}
+	if permissions, ok := m.MockOrgWorkflowPerms[org]; ok {
+		return permissions, nil
+	}
+	return &common.WorkflowPermissions{DefaultWorkflowPermissions: "read"}, nil
+}
+
+// GetRepositoryWorkflowPermissions is a mock implementation
+func (m *MockGitHubClient) GetRepositoryWorkflowPermissions(_ context.Context, owner, repo string) (*common.WorkflowPermissions, error) {
+	m.GetRepoWorkflowPermsCalls++
+	if m.MockWorkflowPermsErr != nil {
+		return nil, m.MockWorkflowPermsErr
+	}
+	if permissions, ok := m.MockRepoWorkflowPerms[owner+"/"+repo]; ok {
+		return permissions, nil
+	}
+	return &common.WorkflowPermissions{DefaultWorkflowPermissions: "read"}, nil
+}
+
+// GetEnvironment is a mock implementation
+func (m *MockGitHubClient) GetEnvironment(ctx context.Context, owner, repo, name string) (*common.Environment, error) {
+	m.GetEnvironmentCalls++
+	if m.MockEnvironmentErr != nil {
+		return nil, m.MockEnvironmentErr
+	}
+	return m.MockEnvironments[owner+"/"+repo+"/"+name], nil
+}
+
+// ListDeployments is a mock implementation
+func (m *MockGitHubClient) ListDeployments(ctx context.Context, owner, repo, environment string, since time.Time) ([]*github.Deployment, error) {
+	m.ListDeploymentsCalls++
+	if m.MockDeploymentsErr != nil {
+		return nil, m.MockDeploymentsErr
+	}
+	var deployments []*github.Deployment
+	for _, d := range m.MockDeployments[owner+"/"+repo+"/"+environment] {
+		if !d.GetCreatedAt().Before(since) {
+			deployments = append(deployments, d)
+		}
+	}
+	return deployments, nil
+}
+
+// ListDeploymentStatuses is a mock implementation
+func (m *MockGitHubClient) ListDeploymentStatuses(ctx context.Context, owner, repo string, id int64) ([]*github.DeploymentStatus, error) {
+	m.ListDeploymentStatusesCalls++
+	if m.MockDeploymentStatusErr != nil {
+		return nil, m.MockDeploymentStatusErr
+	}
+	return m.MockDeploymentStatuses[id], nil
+}
+
+// ListWorkflowRuns is a mock implementation
+func (m *MockGitHubClient) ListWorkflowRuns(ctx context.Context, owner, repo, event string, since time.Time) ([]*common.WorkflowRun, error) {
+	m.ListWorkflowRunsCalls++
+	if m.MockWorkflowRunsErr != nil {
+		return nil, m.MockWorkflowRunsErr
+	}
+	var runs []*common.WorkflowRun
+	for _, run := range m.MockWorkflowRuns[owner+"/"+repo+"/"+event] {
+		if !run.CreatedAt.Before(since) {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+// ListActionsSecretNames is a mock implementation
+func (m *MockGitHubClient) ListActionsSecretNames(ctx context.Context, owner, repo string) ([]string, error) {
+	m.ListActionsSecretNamesCalls++
+	if m.MockSecretNamesErr != nil {
+		return nil, m.MockSecretNamesErr
+	}
+	return m.MockSecretNames[owner+"/"+repo], nil
+}
+
+// ListOrganizationMembers is a mock implementation
+func (m *MockGitHubClient) ListOrganizationMembers(_ context.Context, _, _ string) ([]*github.User, error) {
+	m.ListOrgMembersCalls++
+	return m.MockOrgMembers, m.MockOrgMembersErr
+}
+
+// IsOrganizationMember is a mock implementation
+// It reports the membership registered for "org/user" in MockOrgMemberships
+func (m *MockGitHubClient) IsOrganizationMember(_ context.Context, org, user string) (bool, error) {
+	m.IsOrgMemberCalls++
+	if m.MockMembershipErr != nil {
+		return false, m.MockMembershipErr
+	}
+	return m.MockOrgMemberships[org+"/"+user], nil
+}
+
+// IsTeamMember is a mock implementation
+// It reports the membership registered for "org/team/user" in MockTeamMemberships
+func (m *MockGitHubClient) IsTeamMember(_ context.Context, org, team, user string) (bool, error) {
+	m.IsTeamMemberCalls++
+	if m.MockMembershipErr != nil {
+		return false, m.MockMembershipErr
+	}
+	return m.MockTeamMemberships[org+"/"+team+"/"+user], nil
+}
+
+// ListRepositoryCollaborators is a mock implementation
+func (m *MockGitHubClient) ListRepositoryCollaborators(_ context.Context, _, _ string) ([]*github.User, error) {
+	m.ListCollaboratorsCalls++
+	return m.MockCollaborators, m.MockCollaboratorsErr
+}
+
+// ListRepositoryTeams is a mock implementation
+// It returns the teams registered for "owner/repo" in MockRepoTeams
+func (m *MockGitHubClient) ListRepositoryTeams(_ context.Context, owner, repo string) ([]*github.Team, error) {
+	m.ListRepositoryTeamsCalls++
+	return m.MockRepoTeams[owner+"/"+repo], m.MockRepoTeamsErr
+}
+
+// GetLatestUserEvent is a mock implementation
+// It returns the event registered for the user in MockLatestUserEvents, or nil
+func (m *MockGitHubClient) GetLatestUserEvent(_ context.Context, user string) (*github.Event, error) {
+	m.GetLatestUserEventCalls++
+	return m.MockLatestUserEvents[user], m.MockLatestUserEventErr
+}
+
+// GetRepository is a mock implementation
+// It returns the repository registered for "owner/repo" in MockRepository, or nil
+func (m *MockGitHubClient) GetRepository(_ context.Context, owner, repo string) (*github.Repository, error) {
+	m.GetRepositoryCalls++
+	return m.MockRepository[owner+"/"+repo], m.MockRepositoryErr
+}
+
+// GetLatestIssueActivity is a mock implementation
+// It returns the issue registered for "owner/repo" in MockLatestIssues, or nil
+func (m *MockGitHubClient) GetLatestIssueActivity(_ context.Context, owner, repo string) (*github.Issue, error) {
+	m.GetLatestIssueActivityCalls++
+	return m.MockLatestIssues[owner+"/"+repo], m.MockLatestIssueErr
+}
+
+// ListOpenIssues is a mock implementation
+// It returns the issues registered for "owner/repo" in MockOpenIssues created before the given time
+func (m *MockGitHubClient) ListOpenIssues(_ context.Context, owner, repo string, createdBefore time.Time) ([]*github.Issue, error) {
+	m.ListOpenIssuesCalls++
+	if m.MockOpenIssuesErr != nil {
+		return nil, m.MockOpenIssuesErr
+	}
+	var issues []*github.Issue
+	for _, issue := range m.MockOpenIssues[owner+"/"+repo] {
+		if issue.GetCreatedAt().Before(createdBefore) {
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
+
+// GetFileContent is a mock implementation
+// It returns the content registered for "owner/repo/path" in MockFileContents, or nil
+func (m *MockGitHubClient) GetFileContent(_ context.Context, owner, repo, path string) ([]byte, error) {
+	m.GetFileContentCalls++
+	if m.MockFileContentErr != nil {
+		return nil, m.MockFileContentErr
+	}
+	content, ok := m.MockFileContents[owner+"/"+repo+"/"+path]
+	if !ok {
+		return nil, nil
+	}
+	return []byte(content), nil
+}
+
+// ListRepositoryFiles is a mock implementation
+func (m *MockGitHubClient) ListRepositoryFiles(_ context.Context, owner, repo, branch string) ([]string, bool, error) {
+	m.ListRepositoryFilesCalls++
+	return m.MockRepoFiles[owner+"/"+repo], m.MockRepoFilesTruncated, nil
+}
+
+// GetAuthenticatedUser is a mock implementation
+func (m *MockGitHubClient) GetAuthenticatedUser(_ context.Context) (*github.User, error) {
+	m.GetAuthenticatedUserCalls++
+	return m.MockAuthenticatedUser, m.MockAuthenticatedUserErr
+}
+
+// GetTokenInfo is a mock implementation
+func (m *MockGitHubClient) GetTokenInfo(_ context.Context) (*common.TokenInfo, error) {
+	m.GetTokenInfoCalls++
+	if m.MockTokenInfoErr != nil {
+		return nil, m.MockTokenInfoErr
+	}
+	if m.MockTokenInfo == nil {
+		return nil, fmt.Errorf("no token info")
+	}
+	return m.MockTokenInfo, nil
+}
+
+// GetOrganizationAccess is a mock implementation
+// Organizations not registered in MockOrgAccess are accessible
+func (m *MockGitHubClient) GetOrganizationAccess(_ context.Context, org string) (*common.OrganizationAccess, error) {
+	m.GetOrganizationAccessCalls++
+	if access, ok := m.MockOrgAccess[org]; ok {
+		return access, nil
+	}
+	return &common.OrganizationAccess{Accessible: true}, nil
+}
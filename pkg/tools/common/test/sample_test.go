@@ -0,0 +1,67 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	"github.com/google/go-github/v45/github"
+)
+
+func TestParseSample(t *testing.T) {
+	if s, err := common.ParseSample("500", 7); err != nil || s.Count != 500 || s.Seed != 7 {
+		t.Errorf("Expected a sample of 500 repositories, got %+v, %v", s, err)
+	}
+	if s, err := common.ParseSample("12.5%", 7); err != nil || s.Percent != 12.5 {
+		t.Errorf("Expected a sample of 12.5%%, got %+v, %v", s, err)
+	}
+	for _, invalid := range []string{"0", "-3", "abc", "0%", "150%", "%"} {
+		if _, err := common.ParseSample(invalid, 0); err == nil {
+			t.Errorf("Expected an error for sample %q", invalid)
+		}
+	}
+}
+
+func TestSampleRotation(t *testing.T) {
+	defer common.SetSample(nil)
+
+	repos := make([]*github.Repository, 0, 25)
+	for i := 0; i < 25; i++ {
+		repos = append(repos, &github.Repository{FullName: github.String(fmt.Sprintf("org/repo%02d", i))})
+	}
+
+	// Five rounds of five repositories check every repository once
+	seen := make(map[string]int)
+	for round := int64(0); round < 5; round++ {
+		common.SetSample(&common.Sample{Count: 5, Seed: round})
+		ctx := common.WithScope(context.Background())
+		kept, filtered := common.FilterRepositories(ctx, repos, config.Filters{})
+		if len(kept) != 5 || filtered != 20 {
+			t.Fatalf("Round %d: expected 5 repositories kept and 20 left out, got %d and %d", round, len(kept), filtered)
+		}
+		if unsampled := common.TakeCoverage(ctx).Unsampled; unsampled != 20 {
+			t.Errorf("Round %d: expected 20 unsampled repositories in the coverage, got %d", round, unsampled)
+		}
+		for i, repo := range kept {
+			seen[repo.GetFullName()]++
+			// The sample keeps the listed order
+			if i > 0 && kept[i-1].GetFullName() > repo.GetFullName() {
+				t.Errorf("Round %d: expected repositories in their listed order, got %s before %s", round, kept[i-1].GetFullName(), repo.GetFullName())
+			}
+		}
+	}
+	if len(seen) != len(repos) {
+		t.Errorf("Expected the rounds to cover all %d repositories, covered %d", len(repos), len(seen))
+	}
+
+	// The same seed checks the same repositories, and percentages round up
+	common.SetSample(&common.Sample{Percent: 10, Seed: 3})
+	first, _ := common.FilterRepositories(context.Background(), repos, config.Filters{})
+	second, _ := common.FilterRepositories(context.Background(), repos, config.Filters{})
+	if len(first) != 3 || fmt.Sprint(first) != fmt.Sprint(second) {
+		t.Errorf("Expected the same 3 repositories for the same seed, got %v and %v", first, second)
+	}
+	common.TakeCoverage(context.Background())
+}
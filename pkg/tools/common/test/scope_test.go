@@ -0,0 +1,120 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+func TestScope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"login": "monitor-bot"}`))
+	}))
+	defer server.Close()
+
+	// Two monitors run at the same time, each in its own scope
+	scopes := []context.Context{common.WithScope(context.Background()), common.WithScope(context.Background())}
+	targets := [][]string{{"owner/a", "owner/b", "owner/c"}, {"owner/d"}}
+
+	var wg sync.WaitGroup
+	for i, ctx := range scopes {
+		wg.Add(1)
+		go func(ctx context.Context, targets []string) {
+			defer wg.Done()
+			client := common.NewGitHubClient(ctx, "token")
+			client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+			for _, target := range targets {
+				if common.SkipIfStopped(ctx, target) {
+					continue
+				}
+				if _, _, err := client.Client.Users.Get(ctx, ""); err != nil {
+					t.Errorf("Did not expect an error but got: %v", err)
+				}
+			}
+		}(ctx, targets[i])
+	}
+	wg.Wait()
+
+	for i, ctx := range scopes {
+		coverage := common.TakeCoverage(ctx)
+		if len(coverage.Checked) != len(targets[i]) {
+			t.Errorf("Expected scope %d to have checked %v, got %+v", i, targets[i], coverage)
+		}
+		if calls := common.UsageOf(ctx).APICalls; calls != int64(len(targets[i])) {
+			t.Errorf("Expected scope %d to have sent %d requests, got %d", i, len(targets[i]), calls)
+		}
+	}
+
+	// Each scope forgets its coverage once taken
+	if coverage := common.TakeCoverage(scopes[0]); len(coverage.Checked) != 0 {
+		t.Errorf("Expected the coverage to be forgotten once taken, got %+v", coverage)
+	}
+}
+
+func TestSharedRateLimiters(t *testing.T) {
+	ctx := common.WithSharedRateLimiters(context.Background())
+
+	first := common.NewGitHubClient(ctx, "token")
+	second := common.NewGitHubClient(common.WithScope(ctx), "token")
+	other := common.NewGitHubClient(ctx, "other-token")
+	if first.RateLimiter != second.RateLimiter {
+		t.Errorf("Expected clients with the same token to share their rate limiter")
+	}
+	if first.RateLimiter == other.RateLimiter {
+		t.Errorf("Did not expect clients with different tokens to share their rate limiter")
+	}
+
+	// Without shared limiters every client has its own
+	if common.NewGitHubClient(context.Background(), "token").RateLimiter == common.NewGitHubClient(context.Background(), "token").RateLimiter {
+		t.Errorf("Did not expect clients to share a rate limiter by default")
+	}
+}
+
+func TestLimitScope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"login": "monitor-bot"}`))
+	}))
+	defer server.Close()
+
+	// A run capped at two calls, next to a run without a cap
+	capped := common.WithScope(context.Background())
+	common.LimitScope(capped, 2)
+	other := common.WithScope(context.Background())
+
+	client := common.NewGitHubClient(capped, "token")
+	client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	var err error
+	for _, target := range []string{"owner/a", "owner/b", "owner/c"} {
+		if common.SkipIfStopped(capped, target) {
+			continue
+		}
+		if _, _, err = client.Client.Users.Get(capped, ""); err != nil {
+			break
+		}
+	}
+	if common.StoppedIn(capped) != common.StopBudget {
+		t.Errorf("Expected the capped run to be stopped by its budget, got %q", common.StoppedIn(capped))
+	}
+	if calls := common.UsageOf(capped).APICalls; calls != 2 {
+		t.Errorf("Expected the capped run to send 2 requests, got %d", calls)
+	}
+	if coverage := common.TakeCoverage(capped); len(coverage.Checked) != 2 || len(coverage.Skipped) != 1 || coverage.Skipped[0].Reason != common.StopBudget {
+		t.Errorf("Unexpected coverage: %+v", coverage)
+	}
+
+	// The cap of a run does not stop the others
+	if common.StoppedIn(other) != "" || common.Stopped() != "" {
+		t.Errorf("Expected only the capped run to be stopped")
+	}
+	if _, _, err := client.Client.Users.Get(other, ""); err != nil {
+		t.Errorf("Did not expect an error but got: %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	"github.com/google/go-github/v45/github"
+)
+
+func TestParseShard(t *testing.T) {
+	if s, err := common.ParseShard("2/5"); err != nil || s.Index != 2 || s.Count != 5 || s.String() != "2/5" {
+		t.Errorf("Expected shard 2 of 5, got %+v, %v", s, err)
+	}
+	for _, invalid := range []string{"", "2", "0/5", "6/5", "1/0", "a/5", "2/b", "-1/5"} {
+		if _, err := common.ParseShard(invalid); err == nil {
+			t.Errorf("Expected an error for shard %q", invalid)
+		}
+	}
+}
+
+func TestShardPartition(t *testing.T) {
+	defer common.SetShard(nil)
+
+	repos := make([]*github.Repository, 0, 40)
+	for i := 0; i < 40; i++ {
+		repos = append(repos, &github.Repository{FullName: github.String(fmt.Sprintf("org/repo%02d", i))})
+	}
+
+	// The shards check every repository once, leaving the others to the other shards
+	seen := make(map[string]int)
+	for index := 1; index <= 3; index++ {
+		common.SetShard(&common.Shard{Index: index, Count: 3})
+		ctx := common.WithScope(context.Background())
+		kept, filtered := common.FilterRepositories(ctx, repos, config.Filters{})
+		if otherShards := common.TakeCoverage(ctx).OtherShards; otherShards != filtered || len(kept)+filtered != len(repos) {
+			t.Errorf("Shard %d: expected the %d repositories left out to count as other shards, got %d", index, filtered, otherShards)
+		}
+		for _, repo := range kept {
+			seen[repo.GetFullName()]++
+		}
+	}
+	if len(seen) != len(repos) {
+		t.Errorf("Expected the shards to cover all %d repositories, covered %d", len(repos), len(seen))
+	}
+	for repo, n := range seen {
+		if n != 1 {
+			t.Errorf("Expected %s to be checked by one shard, checked by %d", repo, n)
+		}
+	}
+
+	// Repositories listed explicitly are left to their shard too, organizations are checked by every shard
+	common.SetShard(&common.Shard{Index: 1, Count: 2})
+	inShard, otherShard := "", ""
+	for _, repo := range repos {
+		if common.CurrentShard().Contains(repo.GetFullName()) {
+			inShard = repo.GetFullName()
+		} else {
+			otherShard = repo.GetFullName()
+		}
+	}
+	ctx := common.WithScope(context.Background())
+	if common.SkipIfStopped(ctx, inShard) || !common.SkipIfStopped(ctx, otherShard) || common.SkipIfStopped(ctx, "org:org") {
+		t.Errorf("Expected %s and the organization to be checked and %s to be left to the other shard", inShard, otherShard)
+	}
+	if c := common.TakeCoverage(ctx); len(c.Checked) != 2 || c.OtherShards != 1 || len(c.Skipped) != 0 {
+		t.Errorf("Expected 2 targets checked and 1 left to the other shard, got %+v", c)
+	}
+}
@@ -0,0 +1,93 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+func TestGetTokenInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/rate_limit" {
+			w.Write([]byte(`{"resources": {"core": {"limit": 5000, "remaining": 4999}}}`))
+			return
+		}
+		w.Header().Set("X-OAuth-Scopes", "repo, read:org")
+		w.Header().Set("GitHub-Authentication-Token-Expiration", "2030-01-02 03:04:05 UTC")
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "1200")
+		w.Header().Set("X-RateLimit-Reset", "1700000000")
+		w.Write([]byte(`{"login": "monitor-bot"}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := common.NewGitHubClient(ctx, "token")
+	client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	info, err := client.GetTokenInfo(ctx)
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if info.Login != "monitor-bot" {
+		t.Errorf("Expected login monitor-bot, got %q", info.Login)
+	}
+	if !info.ScopesKnown || strings.Join(info.Scopes, ",") != "repo,read:org" {
+		t.Errorf("Unexpected scopes: %v (known: %v)", info.Scopes, info.ScopesKnown)
+	}
+	if !info.Expires.Equal(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("Unexpected expiration: %v", info.Expires)
+	}
+	if info.RateLimit.Limit != 5000 || info.RateLimit.Remaining != 1200 {
+		t.Errorf("Unexpected rate limit: %+v", info.RateLimit)
+	}
+}
+
+func TestGetOrganizationAccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/rate_limit":
+			w.Write([]byte(`{"resources": {"core": {"limit": 5000, "remaining": 4999}}}`))
+		case "/orgs/open/repos":
+			w.Write([]byte(`[]`))
+		case "/orgs/sso/repos":
+			w.Header().Set("X-GitHub-SSO", "required; url=https://github.com/orgs/sso/sso?authorization_request=abc")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "Resource protected by organization SAML enforcement."}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Not Found"}`))
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := common.NewGitHubClient(ctx, "token")
+	client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	access, err := client.GetOrganizationAccess(ctx, "open")
+	if err != nil || !access.Accessible || access.SSORequired {
+		t.Errorf("Expected open to be accessible, got %+v (%v)", access, err)
+	}
+
+	access, err = client.GetOrganizationAccess(ctx, "sso")
+	if err != nil || access.Accessible || !access.SSORequired {
+		t.Fatalf("Expected sso to require SSO authorization, got %+v (%v)", access, err)
+	}
+	if access.SSOURL != "https://github.com/orgs/sso/sso?authorization_request=abc" {
+		t.Errorf("Unexpected SSO URL: %q", access.SSOURL)
+	}
+
+	access, err = client.GetOrganizationAccess(ctx, "missing")
+	if err != nil || access.Accessible || access.SSORequired {
+		t.Errorf("Expected missing to be inaccessible without SSO, got %+v (%v)", access, err)
+	}
+}
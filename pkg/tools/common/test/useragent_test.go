@@ -0,0 +1,60 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+func TestUserAgent(t *testing.T) {
+	var mu sync.Mutex
+	var agents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		agents = append(agents, r.Header.Get("User-Agent"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/rate_limit" {
+			w.Write([]byte(`{"resources": {"core": {"limit": 5000, "remaining": 4999}}}`))
+			return
+		}
+		w.Write([]byte(`{"login": "monitor-bot"}`))
+	}))
+	defer server.Close()
+
+	common.SetUserAgent("acme-git-monitor/1.0")
+	common.SetRunID("20260301T090000Z-1a2b3c4d")
+	defer common.SetUserAgent(common.DefaultUserAgent)
+	defer common.SetRunID("")
+
+	ctx := context.Background()
+	client := common.NewGitHubClient(ctx, "token")
+	client.Client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	if _, err := client.GetAuthenticatedUser(ctx); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	// Requests of scans with a run of their own are tagged with it
+	if _, err := client.GetAuthenticatedUser(common.WithRunID(ctx, "scan-42")); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(agents) == 0 || agents[0] != "acme-git-monitor/1.0 (run 20260301T090000Z-1a2b3c4d)" {
+		t.Errorf("Unexpected User-Agent: %v", agents)
+	}
+	if agents[len(agents)-2] != "acme-git-monitor/1.0 (run scan-42)" {
+		t.Errorf("Expected the run of the context, got %v", agents)
+	}
+
+	common.SetRunID("")
+	if got := common.UserAgent(ctx); got != "acme-git-monitor/1.0" {
+		t.Errorf("Expected the product alone without a run, got %q", got)
+	}
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+func TestWindowStart(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone data not available: %v", err)
+	}
+
+	// 2024-03-15 09:30 in Berlin (UTC+1)
+	now := time.Date(2024, 3, 15, 8, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		window   time.Duration
+		loc      *time.Location
+		expected time.Time
+	}{
+		{
+			name:     "No location keeps the exact window",
+			window:   24 * time.Hour,
+			expected: time.Date(2024, 3, 14, 8, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "Daily window aligns to local midnight",
+			window:   24 * time.Hour,
+			loc:      berlin,
+			expected: time.Date(2024, 3, 14, 0, 0, 0, 0, berlin),
+		},
+		{
+			name:     "Multi-day window aligns to local midnight",
+			window:   72 * time.Hour,
+			loc:      berlin,
+			expected: time.Date(2024, 3, 12, 0, 0, 0, 0, berlin),
+		},
+		{
+			name:     "Partial day window is not aligned",
+			window:   6 * time.Hour,
+			loc:      berlin,
+			expected: time.Date(2024, 3, 15, 2, 30, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			start := common.WindowStart(now, tc.window, tc.loc)
+			if !start.Equal(tc.expected) {
+				t.Errorf("Expected window start %v, got %v", tc.expected, start)
+			}
+		})
+	}
+}
+
+func TestWindowEnd(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone data not available: %v", err)
+	}
+
+	// 2024-03-15 09:30 in Berlin (UTC+1)
+	now := time.Date(2024, 3, 15, 8, 30, 0, 0, time.UTC)
+
+	if end := common.WindowEnd(now, 24*time.Hour, berlin); !end.Equal(time.Date(2024, 3, 15, 0, 0, 0, 0, berlin)) {
+		t.Errorf("Expected a daily window to end at local midnight, got %v", end)
+	}
+	if end := common.WindowEnd(now, 24*time.Hour, nil); !end.Equal(now) {
+		t.Errorf("Expected a window without a location to end now, got %v", end)
+	}
+	if end := common.WindowEnd(now, 6*time.Hour, berlin); !end.Equal(now) {
+		t.Errorf("Expected a partial day window to end now, got %v", end)
+	}
+}
+
+func TestConsecutiveDailyWindows(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone data not available: %v", err)
+	}
+
+	// Daily runs at 10:00 in Berlin, across the switch to daylight saving time on 2024-03-31
+	first := time.Date(2024, 3, 29, 10, 0, 0, 0, berlin)
+	for day := 0; day < 4; day++ {
+		run := first.AddDate(0, 0, day)
+		next := run.AddDate(0, 0, 1)
+
+		start, end := common.WindowStart(run, 24*time.Hour, berlin), common.WindowEnd(run, 24*time.Hour, berlin)
+		if want := time.Date(run.Year(), run.Month(), run.Day()-1, 0, 0, 0, 0, berlin); !start.Equal(want) || !end.Equal(want.AddDate(0, 0, 1)) {
+			t.Errorf("Expected the run of %s to cover the previous local day, got %v to %v", run.Format("2006-01-02"), start, end)
+		}
+		if nextStart := common.WindowStart(next, 24*time.Hour, berlin); !nextStart.Equal(end) {
+			t.Errorf("Expected the run of %s to start where the previous one ended (%v), got %v", next.Format("2006-01-02"), end, nextStart)
+		}
+	}
+}
+
+func TestLocalTime(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("timezone data not available: %v", err)
+	}
+
+	ts := time.Date(2024, 3, 15, 20, 0, 0, 0, time.UTC)
+
+	if got := common.LocalTime(ts, tokyo); got.Day() != 16 || got.Hour() != 5 {
+		t.Errorf("Expected 2024-03-16 05:00 in Tokyo, got %v", got)
+	}
+	if got := common.LocalTime(ts, nil); got.Location() != time.UTC {
+		t.Errorf("Expected UTC without a location, got %v", got.Location())
+	}
+	if got := common.LocalTime(time.Time{}, tokyo); !got.IsZero() {
+		t.Errorf("Expected zero time to stay zero, got %v", got)
+	}
+}
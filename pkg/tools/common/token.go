@@ -0,0 +1,102 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// TokenInfo is what GitHub tells about the token of a client in the headers of its responses
+type TokenInfo struct {
+	Login string
+	// OAuth scopes of a classic token, nil for fine-grained tokens, which have permissions instead
+	Scopes      []string
+	ScopesKnown bool      // Whether GitHub listed the scopes, only for classic tokens
+	Expires     time.Time // When the token expires, zero when it does not
+	RateLimit   RateLimit
+}
+
+// OrganizationAccess is whether the token of a client can read an organization's resources
+type OrganizationAccess struct {
+	Accessible  bool   // Whether the organization's repositories could be listed
+	SSORequired bool   // Whether SAML single sign-on authorization of the token is missing
+	SSOURL      string // Where to authorize the token, when GitHub tells
+}
+
+// tokenExpirationLayouts are the formats of the GitHub-Authentication-Token-Expiration header
+var tokenExpirationLayouts = []string{"2006-01-02 15:04:05 MST", "2006-01-02 15:04:05 -0700"}
+
+// GetTokenInfo gets the user, scopes, expiration and rate limit of the client's token
+// Tokens of GitHub Apps cannot read the authenticated user and fail
+func (c *GitHubClient) GetTokenInfo(ctx context.Context) (*TokenInfo, error) {
+	var user *github.User
+	var resp *github.Response
+	err := c.ExecuteWithRateLimit(ctx, func() error {
+		var apiErr error
+		user, resp, apiErr = c.Client.Users.Get(ctx, "")
+		return apiErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting the authenticated user: %v", err)
+	}
+
+	info := &TokenInfo{
+		Login:     user.GetLogin(),
+		RateLimit: RateLimit{Limit: resp.Rate.Limit, Remaining: resp.Rate.Remaining, Reset: resp.Rate.Reset.Time},
+	}
+	if values, ok := resp.Header["X-Oauth-Scopes"]; ok {
+		info.ScopesKnown = true
+		for _, scope := range strings.Split(strings.Join(values, ","), ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				info.Scopes = append(info.Scopes, scope)
+			}
+		}
+	}
+	if expiration := resp.Header.Get("GitHub-Authentication-Token-Expiration"); expiration != "" {
+		for _, layout := range tokenExpirationLayouts {
+			if expires, err := time.Parse(layout, expiration); err == nil {
+				info.Expires = expires
+				break
+			}
+		}
+	}
+
+	return info, nil
+}
+
+// GetOrganizationAccess checks whether the client's token can list the repositories of an organization
+// Organizations enforcing SAML single sign-on answer 403 with an X-GitHub-SSO header to tokens not authorized for it
+func (c *GitHubClient) GetOrganizationAccess(ctx context.Context, org string) (*OrganizationAccess, error) {
+	opts := &github.RepositoryListByOrgOptions{ListOptions: github.ListOptions{PerPage: 1}}
+	err := c.ExecuteWithRateLimit(ctx, func() error {
+		_, _, apiErr := c.Client.Repositories.ListByOrg(ctx, org, opts)
+		return apiErr
+	})
+	if err == nil {
+		return &OrganizationAccess{Accessible: true}, nil
+	}
+
+	var errResp *github.ErrorResponse
+	if !errors.As(err, &errResp) || errResp.Response == nil {
+		return nil, fmt.Errorf("error listing repositories of organization %s: %v", org, err)
+	}
+	switch errResp.Response.StatusCode {
+	case http.StatusForbidden:
+		if sso := errResp.Response.Header.Get("X-GitHub-SSO"); strings.HasPrefix(sso, "required") {
+			access := &OrganizationAccess{SSORequired: true}
+			if _, url, ok := strings.Cut(sso, "url="); ok {
+				access.SSOURL = strings.TrimSpace(url)
+			}
+			return access, nil
+		}
+		return &OrganizationAccess{}, nil
+	case http.StatusNotFound:
+		return &OrganizationAccess{}, nil
+	}
+	return nil, fmt.Errorf("error listing repositories of organization %s: %v", org, err)
+}
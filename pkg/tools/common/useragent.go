@@ -0,0 +1,54 @@
+package common
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultUserAgent is the product GitHub API requests identify as when none is configured
+const DefaultUserAgent = "git-monitor"
+
+var (
+	userAgentMu sync.RWMutex
+	userAgent   = DefaultUserAgent
+	runID       string // Run requests are tagged with unless their context names another
+)
+
+// runIDKey is the context key of the run requests are tagged with
+type runIDKey struct{}
+
+// SetUserAgent sets the product GitHub API requests identify as, e.g. "acme-git-monitor/1.4.0"
+func SetUserAgent(product string) {
+	userAgentMu.Lock()
+	defer userAgentMu.Unlock()
+	userAgent = product
+}
+
+// SetRunID sets the run GitHub API requests are tagged with, unless their context names another
+func SetRunID(id string) {
+	userAgentMu.Lock()
+	defer userAgentMu.Unlock()
+	runID = id
+}
+
+// WithRunID tags the GitHub API requests sent with the context with a run of their own,
+// e.g. a scan requested through the API
+func WithRunID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, runIDKey{}, id)
+}
+
+// UserAgent returns the User-Agent of GitHub API requests sent with the context,
+// e.g. "git-monitor/1.4.0 (run 20260301T090000Z-1a2b3c4d)", which GitHub records in audit logs
+func UserAgent(ctx context.Context) string {
+	userAgentMu.RLock()
+	product, id := userAgent, runID
+	userAgentMu.RUnlock()
+
+	if scoped, ok := ctx.Value(runIDKey{}).(string); ok && scoped != "" {
+		id = scoped
+	}
+	if id == "" {
+		return product
+	}
+	return product + " (run " + id + ")"
+}
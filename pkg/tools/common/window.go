@@ -0,0 +1,41 @@
+package common
+
+import "time"
+
+// WindowStart returns the start of a look-back window that ends at now
+// When a location is given, windows spanning whole days cover the complete local days before
+// the one now falls in, see WindowEnd. A nil location keeps the window exactly as long as requested
+func WindowStart(now time.Time, window time.Duration, loc *time.Location) time.Time {
+	if !alignedWindow(window, loc) {
+		return now.Add(-window)
+	}
+	return WindowEnd(now, window, loc).AddDate(0, 0, -int(window/(24*time.Hour)))
+}
+
+// WindowEnd returns the end of a look-back window that ends at now, exclusive
+// Windows spanning whole days end at the last midnight in the location, so daily reports cover complete
+// local days and consecutive daily runs neither overlap nor leave gaps. Events since that midnight are
+// reported by the next run. Other windows end at now
+func WindowEnd(now time.Time, window time.Duration, loc *time.Location) time.Time {
+	if !alignedWindow(window, loc) {
+		return now
+	}
+	local := now.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+}
+
+// alignedWindow reports whether a window is aligned to local days
+func alignedWindow(window time.Duration, loc *time.Location) bool {
+	return loc != nil && window > 0 && window%(24*time.Hour) == 0
+}
+
+// LocalTime converts a timestamp to the reporting location, defaulting to UTC
+func LocalTime(t time.Time, loc *time.Location) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	if loc == nil {
+		return t.UTC()
+	}
+	return t.In(loc)
+}
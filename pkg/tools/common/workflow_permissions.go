@@ -0,0 +1,58 @@
+package common
+
+import (
+	"context"
+	"fmt"
+)
+
+// WorkflowPermissions are the default permissions of the GITHUB_TOKEN of workflows in an organization or repository
+// The go-github version we depend on does not expose these endpoints, so the type is declared here
+type WorkflowPermissions struct {
+	DefaultWorkflowPermissions   string `json:"default_workflow_permissions"`     // "read" or "write"
+	CanApprovePullRequestReviews bool   `json:"can_approve_pull_request_reviews"` // Whether workflows can create and approve PRs
+}
+
+// GetOrganizationWorkflowPermissions gets the default workflow token permissions of an organization
+// The token needs the admin:org scope
+func (c *GitHubClient) GetOrganizationWorkflowPermissions(ctx context.Context, org string) (*WorkflowPermissions, error) {
+	if org == "" {
+		return nil, fmt.Errorf("organization name cannot be empty")
+	}
+
+	permissions, err := c.getWorkflowPermissions(ctx, fmt.Sprintf("orgs/%s/actions/permissions/workflow", org))
+	if err != nil {
+		return nil, fmt.Errorf("error getting workflow permissions for organization %s: %v", org, err)
+	}
+
+	return permissions, nil
+}
+
+// GetRepositoryWorkflowPermissions gets the default workflow token permissions of a repository
+// The token needs admin access to the repository
+func (c *GitHubClient) GetRepositoryWorkflowPermissions(ctx context.Context, owner, repo string) (*WorkflowPermissions, error) {
+	permissions, err := c.getWorkflowPermissions(ctx, fmt.Sprintf("repos/%s/%s/actions/permissions/workflow", owner, repo))
+	if err != nil {
+		return nil, fmt.Errorf("error getting workflow permissions for %s/%s: %v", owner, repo, err)
+	}
+
+	return permissions, nil
+}
+
+// getWorkflowPermissions fetches workflow token permissions from an endpoint
+func (c *GitHubClient) getWorkflowPermissions(ctx context.Context, path string) (*WorkflowPermissions, error) {
+	permissions := new(WorkflowPermissions)
+	err := c.ExecuteWithRateLimit(ctx, func() error {
+		req, reqErr := c.Client.NewRequest("GET", path, nil)
+		if reqErr != nil {
+			return reqErr
+		}
+		_, apiErr := c.Client.Do(ctx, req, permissions)
+		return apiErr
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
@@ -0,0 +1,126 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// WorkflowRun is a run of a GitHub Actions workflow
+// The go-github version we depend on does not expose the triggering actor, so the type is declared here
+type WorkflowRun struct {
+	ID              int64              `json:"id"`
+	Name            string             `json:"name"`
+	Event           string             `json:"event"` // Event that triggered the run, e.g. "pull_request_target"
+	Status          string             `json:"status"`
+	Conclusion      string             `json:"conclusion"`
+	HTMLURL         string             `json:"html_url"`
+	CreatedAt       *github.Timestamp  `json:"created_at,omitempty"`
+	Actor           *github.User       `json:"actor,omitempty"`            // Who caused the run, e.g. the author of the PR
+	TriggeringActor *github.User       `json:"triggering_actor,omitempty"` // Who started the run, e.g. by approving or re-running it
+	Repository      *github.Repository `json:"repository,omitempty"`
+	HeadRepository  *github.Repository `json:"head_repository,omitempty"` // Repository the run's commit came from, a fork for fork PRs
+}
+
+// workflowRuns is a page of workflow runs
+type workflowRuns struct {
+	TotalCount   int            `json:"total_count"`
+	WorkflowRuns []*WorkflowRun `json:"workflow_runs"`
+}
+
+// workflowRunListOptions specifies the parameters for listing workflow runs
+type workflowRunListOptions struct {
+	Event   string `url:"event,omitempty"`
+	Created string `url:"created,omitempty"`
+	PerPage int    `url:"per_page,omitempty"`
+	Page    int    `url:"page,omitempty"`
+}
+
+// ListWorkflowRuns lists the workflow runs of a repository triggered by an event since the given time, newest first
+// Pages are fetched with the pagination of the context, see WithPagination
+func (c *GitHubClient) ListWorkflowRuns(ctx context.Context, owner, repo, event string, since time.Time) ([]*WorkflowRun, error) {
+	pagination := paginationOf(ctx)
+	opts := &workflowRunListOptions{
+		Event:   event,
+		Created: ">=" + since.UTC().Format(time.RFC3339),
+		PerPage: pagination.PerPage(),
+	}
+
+	var allRuns []*WorkflowRun
+	for pages := 1; ; pages++ {
+		u, err := addOptions(fmt.Sprintf("repos/%s/%s/actions/runs", owner, repo), opts)
+		if err != nil {
+			return nil, err
+		}
+
+		var runs workflowRuns
+		var resp *github.Response
+		err = c.ExecuteWithRateLimit(ctx, func() error {
+			req, reqErr := c.Client.NewRequest("GET", u, nil)
+			if reqErr != nil {
+				return reqErr
+			}
+			var apiErr error
+			resp, apiErr = c.Client.Do(ctx, req, &runs)
+			return apiErr
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing %s workflow runs of %s/%s: %v", event, owner, repo, err)
+		}
+
+		allRuns = append(allRuns, runs.WorkflowRuns...)
+
+		if !morePages(pagination, pages, resp, fmt.Sprintf("%s workflow runs of %s/%s", event, owner, repo)) {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return allRuns, nil
+}
+
+// ListActionsSecretNames lists the names of the Actions secrets workflows of a repository can access,
+// its own and those its organization shares with it. Secret values are never returned by the API
+func (c *GitHubClient) ListActionsSecretNames(ctx context.Context, owner, repo string) ([]string, error) {
+	var names []string
+	for _, path := range []string{
+		fmt.Sprintf("repos/%s/%s/actions/secrets", owner, repo),
+		fmt.Sprintf("repos/%s/%s/actions/organization-secrets", owner, repo),
+	} {
+		opts := &github.ListOptions{PerPage: 100}
+		for {
+			u, err := addOptions(path, opts)
+			if err != nil {
+				return nil, err
+			}
+
+			var secrets github.Secrets
+			var resp *github.Response
+			err = c.ExecuteWithRateLimit(ctx, func() error {
+				req, reqErr := c.Client.NewRequest("GET", u, nil)
+				if reqErr != nil {
+					return reqErr
+				}
+				var apiErr error
+				resp, apiErr = c.Client.Do(ctx, req, &secrets)
+				return apiErr
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error listing Actions secrets of %s/%s: %v", owner, repo, err)
+			}
+
+			for _, secret := range secrets.Secrets {
+				names = append(names, secret.Name)
+			}
+
+			if resp.NextPage == 0 {
+				break
+			}
+			opts.Page = resp.NextPage
+		}
+	}
+
+	return names, nil
+}
@@ -0,0 +1,253 @@
+package dependabot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+const (
+	// DefaultCheckWindow is the default time window to look for dismissed alerts
+	DefaultCheckWindow = 24 * time.Hour
+)
+
+// severityRank orders Dependabot severities from least to most severe
+var severityRank = map[string]int{
+	"low":      1,
+	"medium":   2,
+	"high":     3,
+	"critical": 4,
+}
+
+// Dismissal represents a Dependabot alert that was dismissed
+type Dismissal struct {
+	Repository  string
+	Number      int
+	Package     string
+	Advisory    string
+	Severity    string
+	DismissedBy string
+	Reason      string
+	Comment     string
+	DismissedAt time.Time
+	URL         string
+}
+
+// Checker is a service that reports Dependabot alerts dismissed within the check window
+type Checker struct {
+	client      common.GitHubClientInterface
+	checkWindow time.Duration
+	config      *config.Config
+}
+
+// NewDependabotChecker creates a new Checker
+func NewDependabotChecker(client common.GitHubClientInterface, config *config.Config) *Checker {
+	checkWindow := DefaultCheckWindow
+	if config.Monitors.Dependabot.CheckWindow.Duration > 0 {
+		checkWindow = config.Monitors.Dependabot.CheckWindow.Duration
+	}
+
+	return &Checker{
+		client:      client,
+		checkWindow: checkWindow,
+		config:      config,
+	}
+}
+
+// Run checks all configured organizations and repositories for dismissed alerts
+// Results are ordered by severity, most severe first
+func (c *Checker) Run(ctx context.Context) ([]Dismissal, error) {
+	allDismissals := make([]Dismissal, 0)
+
+	for _, org := range c.config.Monitors.Dependabot.Organizations {
+		if common.SkipIfStopped(ctx, "org:"+org) {
+			continue
+		}
+		dismissals, err := c.CheckOrganization(ctx, org)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, "org:"+org, err) {
+				continue
+			}
+			log.Printf("Error checking Dependabot alerts for organization %s: %v", org, err)
+			continue
+		}
+		allDismissals = append(allDismissals, dismissals...)
+	}
+
+	for _, repository := range c.config.Monitors.Dependabot.Repositories {
+		if common.SkipIfStopped(ctx, repository) {
+			continue
+		}
+		dismissals, err := c.CheckRepository(ctx, repository)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, repository, err) {
+				continue
+			}
+			log.Printf("Error checking Dependabot alerts for repository %s: %v", repository, err)
+			continue
+		}
+		allDismissals = append(allDismissals, dismissals...)
+	}
+
+	sort.SliceStable(allDismissals, func(i, j int) bool {
+		return severityRank[allDismissals[i].Severity] > severityRank[allDismissals[j].Severity]
+	})
+
+	return allDismissals, nil
+}
+
+// CheckOrganization reports Dependabot alerts dismissed across an organization
+func (c *Checker) CheckOrganization(ctx context.Context, org string) ([]Dismissal, error) {
+	log.Printf("Checking for dismissed Dependabot alerts in %s organization within the last %v", org, c.checkWindow)
+
+	alerts, err := c.client.ListOrganizationDependabotAlerts(ctx, org, "dismissed")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization Dependabot alerts: %w", err)
+	}
+
+	return c.filterDismissals(alerts, ""), nil
+}
+
+// CheckRepository reports Dependabot alerts dismissed in a single repository
+func (c *Checker) CheckRepository(ctx context.Context, repository string) ([]Dismissal, error) {
+	owner, repo, ok := common.ParseRepository(repository)
+	if !ok {
+		return nil, fmt.Errorf("invalid repository format, expected 'owner/repo'")
+	}
+
+	log.Printf("Checking for dismissed Dependabot alerts in %s within the last %v", repository, c.checkWindow)
+
+	alerts, err := c.client.ListRepositoryDependabotAlerts(ctx, owner, repo, "dismissed")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repository Dependabot alerts: %w", err)
+	}
+
+	return c.filterDismissals(alerts, repository), nil
+}
+
+// filterDismissals keeps the alerts dismissed within the check window at or above the minimum severity
+// The repository name is taken from the alert when it is not known by the caller
+func (c *Checker) filterDismissals(alerts []*common.DependabotAlert, repository string) []Dismissal {
+	dismissals := make([]Dismissal, 0)
+	now := time.Now()
+	cutoffTime := common.WindowStart(now, c.checkWindow, c.config.WindowLocation())
+	windowEnd := common.WindowEnd(now, c.checkWindow, c.config.WindowLocation())
+	minimumRank := severityRank[c.config.Monitors.Dependabot.MinimumSeverity]
+
+	for _, alert := range alerts {
+		if alert.DismissedAt == nil || alert.DismissedAt.Before(cutoffTime) || !alert.DismissedAt.Before(windowEnd) {
+			continue
+		}
+
+		severity := alertSeverity(alert)
+		if severityRank[severity] < minimumRank {
+			continue
+		}
+
+		repoName := repository
+		if repoName == "" {
+			repoName = alert.Repository.GetFullName()
+		}
+
+		dismissal := Dismissal{
+			Repository:  repoName,
+			Number:      alert.Number,
+			Severity:    severity,
+			DismissedBy: alert.DismissedBy.GetLogin(),
+			Reason:      alert.DismissedReason,
+			Comment:     alert.DismissedComment,
+			DismissedAt: common.LocalTime(alert.DismissedAt.Time, c.config.Location()),
+			URL:         alert.HTMLURL,
+		}
+		if alert.Dependency != nil && alert.Dependency.Package != nil {
+			dismissal.Package = fmt.Sprintf("%s:%s", alert.Dependency.Package.Ecosystem, alert.Dependency.Package.Name)
+		}
+		if alert.SecurityAdvisory != nil {
+			dismissal.Advisory = alert.SecurityAdvisory.GHSAID
+		}
+
+		dismissals = append(dismissals, dismissal)
+	}
+
+	return dismissals
+}
+
+// alertSeverity returns the severity of an alert, preferring the vulnerability severity
+func alertSeverity(alert *common.DependabotAlert) string {
+	if alert.SecurityVulnerability != nil && alert.SecurityVulnerability.Severity != "" {
+		return alert.SecurityVulnerability.Severity
+	}
+	if alert.SecurityAdvisory != nil {
+		return alert.SecurityAdvisory.Severity
+	}
+	return ""
+}
+
+// Findings converts dismissed Dependabot alerts into findings
+func Findings(dismissals []Dismissal) []findings.Finding {
+	list := make([]findings.Finding, 0, len(dismissals))
+	for _, d := range dismissals {
+		list = append(list, findings.Finding{
+			Monitor:    "dependabot_dismissals",
+			Repository: d.Repository,
+			Subject:    fmt.Sprintf("alert #%d", d.Number),
+			Summary:    fmt.Sprintf("%s %s in %s dismissed by %s as %q", d.Severity, d.Advisory, d.Package, d.DismissedBy, d.Reason),
+			URL:        d.URL,
+		})
+	}
+	return list
+}
+
+// WriteResultsMarkdown writes dismissed Dependabot alerts in a code block format
+// suitable for Slack notifications
+func WriteResultsMarkdown(w io.Writer, dismissals []Dismissal) {
+	if len(dismissals) == 0 {
+		return // No results to display
+	}
+
+	// Print header for dismissed alerts
+	fmt.Fprintln(w, "## :warning: Dismissed Dependabot Alerts")
+	fmt.Fprintf(w, "Found %d Dependabot alerts that were recently dismissed.\n\n", len(dismissals))
+
+	// Start code block
+	fmt.Fprintln(w, "```")
+	// Create fixed-width headers with proper spacing for code block
+	fmt.Fprintln(w, "Repository                Alert   Severity  Dismissed By        Reason          Link")
+	fmt.Fprintln(w, "---------------------------------------------------------------------")
+
+	// Print each dismissal in a fixed-width format for code blocks
+	for _, d := range dismissals {
+		// Format repository name with padding
+		repoStr := d.Repository
+		if len(repoStr) > 24 {
+			repoStr = repoStr[:21] + "..."
+		} else {
+			repoStr = fmt.Sprintf("%-24s", repoStr)
+		}
+
+		// Format alert number
+		alertStr := fmt.Sprintf("#%-6d", d.Number)
+
+		// Format actor with padding
+		actorStr := d.DismissedBy
+		if len(actorStr) > 18 {
+			actorStr = actorStr[:15] + "..."
+		} else {
+			actorStr = fmt.Sprintf("%-18s", actorStr)
+		}
+
+		// Format the output row with fixed-width fields
+		fmt.Fprintf(w, "%s %s %-9s %s %-15s %s\n", repoStr, alertStr, d.Severity, actorStr, d.Reason, d.URL)
+	}
+
+	// End code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
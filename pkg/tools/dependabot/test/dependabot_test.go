@@ -0,0 +1,183 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	mockgithub "github.com/anupsv/git-monitoring/pkg/tools/common/test"
+	"github.com/anupsv/git-monitoring/pkg/tools/dependabot"
+)
+
+func createMockAlert(number int, repo, severity, dismissedBy string, dismissedAt time.Time) *common.DependabotAlert {
+	return &common.DependabotAlert{
+		Number:     number,
+		State:      "dismissed",
+		HTMLURL:    "https://github.com/" + repo + "/security/dependabot/1",
+		Repository: &github.Repository{FullName: &repo},
+		Dependency: &common.DependabotDependency{
+			Package: &common.DependabotPackage{Ecosystem: "go", Name: "golang.org/x/net"},
+		},
+		SecurityAdvisory:      &common.DependabotAdvisory{GHSAID: "GHSA-xxxx-yyyy-zzzz", Severity: severity},
+		SecurityVulnerability: &common.DependabotVulnerability{Severity: severity},
+		DismissedBy:           &github.User{Login: &dismissedBy},
+		DismissedReason:       "tolerable_risk",
+		DismissedAt:           &github.Timestamp{Time: dismissedAt},
+	}
+}
+
+func TestRun(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name             string
+		minimumSeverity  string
+		alerts           []*common.DependabotAlert
+		alertsErr        error
+		expectedCount    int
+		expectedSeverity string
+	}{
+		{
+			name: "Dismissals sorted by severity",
+			alerts: []*common.DependabotAlert{
+				createMockAlert(1, "testorg/repo1", "low", "alice", now.Add(-time.Hour)),
+				createMockAlert(2, "testorg/repo2", "critical", "bob", now.Add(-time.Hour)),
+			},
+			expectedCount:    2,
+			expectedSeverity: "critical",
+		},
+		{
+			name: "Dismissals outside window are ignored",
+			alerts: []*common.DependabotAlert{
+				createMockAlert(1, "testorg/repo1", "critical", "alice", now.Add(-72*time.Hour)),
+			},
+			expectedCount: 0,
+		},
+		{
+			name:            "Minimum severity filter",
+			minimumSeverity: "high",
+			alerts: []*common.DependabotAlert{
+				createMockAlert(1, "testorg/repo1", "medium", "alice", now),
+				createMockAlert(2, "testorg/repo2", "high", "bob", now),
+			},
+			expectedCount:    1,
+			expectedSeverity: "high",
+		},
+		{
+			name:          "Organization errors are logged and skipped",
+			alertsErr:     errors.New("API error"),
+			expectedCount: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &mockgithub.MockGitHubClient{
+				MockOrgDependabotAlerts: tc.alerts,
+				MockOrgDependabotErr:    tc.alertsErr,
+			}
+
+			cfg := &config.Config{
+				Monitors: config.MonitorsConfig{
+					Dependabot: config.DependabotConfig{
+						Enabled:         true,
+						Organizations:   []string{"testorg"},
+						MinimumSeverity: tc.minimumSeverity,
+						CheckWindow:     config.Hours(24),
+					},
+				},
+			}
+
+			checker := dependabot.NewDependabotChecker(mockClient, cfg)
+			dismissals, err := checker.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Did not expect an error but got: %v", err)
+			}
+
+			if len(dismissals) != tc.expectedCount {
+				t.Fatalf("Expected %d dismissals, got %d", tc.expectedCount, len(dismissals))
+			}
+
+			if tc.expectedSeverity != "" && dismissals[0].Severity != tc.expectedSeverity {
+				t.Errorf("Expected first dismissal to have severity %q, got %q", tc.expectedSeverity, dismissals[0].Severity)
+			}
+		})
+	}
+}
+
+func TestCheckRepository(t *testing.T) {
+	mockClient := &mockgithub.MockGitHubClient{
+		MockRepoDependabotAlerts: []*common.DependabotAlert{
+			createMockAlert(9, "owner/repo", "high", "carol", time.Now()),
+		},
+	}
+
+	cfg := &config.Config{
+		Monitors: config.MonitorsConfig{
+			Dependabot: config.DependabotConfig{
+				Enabled:      true,
+				Repositories: []string{"owner/repo"},
+				CheckWindow:  config.Hours(24),
+			},
+		},
+	}
+
+	checker := dependabot.NewDependabotChecker(mockClient, cfg)
+	dismissals, err := checker.CheckRepository(context.Background(), "owner/repo")
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	if len(dismissals) != 1 {
+		t.Fatalf("Expected 1 dismissal, got %d", len(dismissals))
+	}
+
+	d := dismissals[0]
+	if d.Package != "go:golang.org/x/net" {
+		t.Errorf("Expected package %q, got %q", "go:golang.org/x/net", d.Package)
+	}
+	if d.DismissedBy != "carol" {
+		t.Errorf("Expected dismissed by %q, got %q", "carol", d.DismissedBy)
+	}
+	if d.Advisory != "GHSA-xxxx-yyyy-zzzz" {
+		t.Errorf("Expected advisory %q, got %q", "GHSA-xxxx-yyyy-zzzz", d.Advisory)
+	}
+}
+
+func TestCheckRepositoryLocalDays(t *testing.T) {
+	now := time.Now().UTC()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	mockClient := &mockgithub.MockGitHubClient{
+		MockRepoDependabotAlerts: []*common.DependabotAlert{
+			createMockAlert(1, "owner/repo", "high", "alice", midnight.Add(-12*time.Hour)),
+			createMockAlert(2, "owner/repo", "high", "bob", now),
+		},
+	}
+
+	cfg := &config.Config{
+		Timezone: "UTC",
+		Monitors: config.MonitorsConfig{
+			Dependabot: config.DependabotConfig{
+				Enabled:      true,
+				Repositories: []string{"owner/repo"},
+				CheckWindow:  config.Hours(24),
+			},
+		},
+	}
+
+	// The window covers yesterday, today's dismissals are reported by the next run
+	checker := dependabot.NewDependabotChecker(mockClient, cfg)
+	dismissals, err := checker.CheckRepository(context.Background(), "owner/repo")
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if len(dismissals) != 1 || dismissals[0].Number != 1 {
+		t.Errorf("Expected only the dismissal of yesterday, got %+v", dismissals)
+	}
+}
@@ -0,0 +1,257 @@
+package dormantaccess
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	"github.com/google/go-github/v45/github"
+)
+
+const (
+	// DefaultInactivePeriod is the default period without activity before an account is reported
+	DefaultInactivePeriod = 90 * 24 * time.Hour
+)
+
+// Account represents a privileged account without recent activity
+type Account struct {
+	Login        string
+	Scope        string    // "org:<name>" for organization owners, "owner/repo" for collaborators
+	Role         string    // "owner", "admin" or "maintain"
+	LastActivity time.Time // Zero when no activity is visible within the events API retention
+}
+
+// Checker is a service that reports privileged accounts without recent activity
+type Checker struct {
+	client         common.GitHubClientInterface
+	inactivePeriod time.Duration
+	config         *config.Config
+	excluded       map[string]bool
+	// lastActivity caches the latest activity per user, so accounts that are
+	// privileged in several places are only looked up once
+	lastActivity map[string]time.Time
+}
+
+// NewDormantAccessChecker creates a new Checker
+func NewDormantAccessChecker(client common.GitHubClientInterface, config *config.Config) *Checker {
+	inactivePeriod := DefaultInactivePeriod
+	if config.Monitors.DormantAccess.InactiveDays > 0 {
+		inactivePeriod = time.Duration(config.Monitors.DormantAccess.InactiveDays) * 24 * time.Hour
+	}
+
+	excluded := make(map[string]bool)
+	for _, user := range config.Monitors.DormantAccess.ExcludedUsers {
+		excluded[user] = true
+	}
+
+	return &Checker{
+		client:         client,
+		inactivePeriod: inactivePeriod,
+		config:         config,
+		excluded:       excluded,
+		lastActivity:   make(map[string]time.Time),
+	}
+}
+
+// Run checks all configured organizations and repositories for dormant privileged accounts
+func (c *Checker) Run(ctx context.Context) ([]Account, error) {
+	allAccounts := make([]Account, 0)
+
+	for _, org := range c.config.Monitors.DormantAccess.Organizations {
+		if common.SkipIfStopped(ctx, "org:"+org) {
+			continue
+		}
+		accounts, err := c.CheckOrganization(ctx, org)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, "org:"+org, err) {
+				continue
+			}
+			log.Printf("Error checking dormant owners for organization %s: %v", org, err)
+			continue
+		}
+		allAccounts = append(allAccounts, accounts...)
+	}
+
+	for _, repository := range c.config.Monitors.DormantAccess.Repositories {
+		if common.SkipIfStopped(ctx, repository) {
+			continue
+		}
+		accounts, err := c.CheckRepository(ctx, repository)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, repository, err) {
+				continue
+			}
+			log.Printf("Error checking dormant collaborators for repository %s: %v", repository, err)
+			continue
+		}
+		allAccounts = append(allAccounts, accounts...)
+	}
+
+	return allAccounts, nil
+}
+
+// CheckOrganization reports organization owners without recent activity
+func (c *Checker) CheckOrganization(ctx context.Context, org string) ([]Account, error) {
+	log.Printf("Checking for owners of %s organization inactive for more than %v", org, c.inactivePeriod)
+
+	owners, err := c.client.ListOrganizationMembers(ctx, org, "admin")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization owners: %w", err)
+	}
+
+	accounts := make([]Account, 0)
+	for _, owner := range owners {
+		account, dormant, err := c.checkUser(ctx, owner.GetLogin(), "org:"+org, "owner")
+		if err != nil {
+			return nil, err
+		}
+		if dormant {
+			accounts = append(accounts, account)
+		}
+	}
+
+	return accounts, nil
+}
+
+// CheckRepository reports admin and maintain collaborators of a repository without recent activity
+func (c *Checker) CheckRepository(ctx context.Context, repository string) ([]Account, error) {
+	owner, repo, ok := common.ParseRepository(repository)
+	if !ok {
+		return nil, fmt.Errorf("invalid repository format, expected 'owner/repo'")
+	}
+
+	log.Printf("Checking for privileged collaborators of %s inactive for more than %v", repository, c.inactivePeriod)
+
+	collaborators, err := c.client.ListRepositoryCollaborators(ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repository collaborators: %w", err)
+	}
+
+	accounts := make([]Account, 0)
+	for _, collaborator := range collaborators {
+		role := privilegedRole(collaborator)
+		if role == "" {
+			continue
+		}
+
+		account, dormant, err := c.checkUser(ctx, collaborator.GetLogin(), repository, role)
+		if err != nil {
+			return nil, err
+		}
+		if dormant {
+			accounts = append(accounts, account)
+		}
+	}
+
+	return accounts, nil
+}
+
+// checkUser determines whether a privileged user has been inactive for longer than the inactive period
+func (c *Checker) checkUser(ctx context.Context, login, scope, role string) (Account, bool, error) {
+	account := Account{
+		Login: login,
+		Scope: scope,
+		Role:  role,
+	}
+
+	if login == "" || c.excluded[login] {
+		return account, false, nil
+	}
+
+	lastActivity, cached := c.lastActivity[login]
+	if !cached {
+		event, err := c.client.GetLatestUserEvent(ctx, login)
+		if err != nil {
+			return account, false, fmt.Errorf("failed to get activity for %s: %w", login, err)
+		}
+		if event != nil {
+			lastActivity = event.GetCreatedAt()
+		}
+		c.lastActivity[login] = lastActivity
+	}
+
+	account.LastActivity = common.LocalTime(lastActivity, c.config.Location())
+	cutoffTime := common.WindowStart(time.Now(), c.inactivePeriod, c.config.Location())
+
+	return account, lastActivity.Before(cutoffTime), nil
+}
+
+// privilegedRole returns the privileged role a collaborator holds, or an empty string
+func privilegedRole(user *github.User) string {
+	switch {
+	case user.GetRoleName() == "admin" || user.Permissions["admin"]:
+		return "admin"
+	case user.GetRoleName() == "maintain" || user.Permissions["maintain"]:
+		return "maintain"
+	default:
+		return ""
+	}
+}
+
+// Findings converts dormant privileged accounts into findings
+func Findings(accounts []Account) []findings.Finding {
+	list := make([]findings.Finding, 0, len(accounts))
+	for _, a := range accounts {
+		list = append(list, findings.Finding{
+			Monitor:    "dormant_accounts",
+			Repository: a.Scope,
+			Subject:    a.Login,
+			Summary:    fmt.Sprintf("%s %s has no recent activity", a.Role, a.Login),
+		})
+	}
+	return list
+}
+
+// WriteResultsMarkdown writes dormant privileged accounts in a code block format
+// suitable for Slack notifications
+func WriteResultsMarkdown(w io.Writer, accounts []Account) {
+	if len(accounts) == 0 {
+		return // No results to display
+	}
+
+	// Print header for dormant accounts
+	fmt.Fprintln(w, "## :warning: Dormant Privileged Accounts")
+	fmt.Fprintf(w, "Found %d privileged accounts without recent activity that should be reviewed.\n\n", len(accounts))
+
+	// Start code block
+	fmt.Fprintln(w, "```")
+	// Create fixed-width headers with proper spacing for code block
+	fmt.Fprintln(w, "Account             Role      Scope                     Last Activity")
+	fmt.Fprintln(w, "---------------------------------------------------------------------")
+
+	// Print each account in a fixed-width format for code blocks
+	for _, a := range accounts {
+		// Format login with padding
+		loginStr := a.Login
+		if len(loginStr) > 18 {
+			loginStr = loginStr[:15] + "..."
+		} else {
+			loginStr = fmt.Sprintf("%-18s", loginStr)
+		}
+
+		// Format scope with padding
+		scopeStr := a.Scope
+		if len(scopeStr) > 24 {
+			scopeStr = scopeStr[:21] + "..."
+		} else {
+			scopeStr = fmt.Sprintf("%-24s", scopeStr)
+		}
+
+		lastActivity := "none visible"
+		if !a.LastActivity.IsZero() {
+			lastActivity = a.LastActivity.Format("2006-01-02")
+		}
+
+		// Format the output row with fixed-width fields
+		fmt.Fprintf(w, "%s %-9s %s %s\n", loginStr, a.Role, scopeStr, lastActivity)
+	}
+
+	// End code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
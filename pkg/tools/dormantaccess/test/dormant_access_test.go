@@ -0,0 +1,120 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	mockgithub "github.com/anupsv/git-monitoring/pkg/tools/common/test"
+	"github.com/anupsv/git-monitoring/pkg/tools/dormantaccess"
+)
+
+func createMockUser(login string, permissions map[string]bool) *github.User {
+	return &github.User{
+		Login:       &login,
+		Permissions: permissions,
+	}
+}
+
+func createMockEvent(createdAt time.Time) *github.Event {
+	return &github.Event{CreatedAt: &createdAt}
+}
+
+func newConfig(excluded ...string) *config.Config {
+	return &config.Config{
+		Monitors: config.MonitorsConfig{
+			DormantAccess: config.DormantAccessConfig{
+				Enabled:       true,
+				Organizations: []string{"testorg"},
+				InactiveDays:  30,
+				ExcludedUsers: excluded,
+			},
+		},
+	}
+}
+
+func TestCheckOrganization(t *testing.T) {
+	now := time.Now()
+
+	mockClient := &mockgithub.MockGitHubClient{
+		MockOrgMembers: []*github.User{
+			createMockUser("active", nil),
+			createMockUser("dormant", nil),
+			createMockUser("silent", nil),
+			createMockUser("breakglass", nil),
+		},
+		MockLatestUserEvents: map[string]*github.Event{
+			"active":  createMockEvent(now.Add(-24 * time.Hour)),
+			"dormant": createMockEvent(now.Add(-60 * 24 * time.Hour)),
+		},
+	}
+
+	checker := dormantaccess.NewDormantAccessChecker(mockClient, newConfig("breakglass"))
+	accounts, err := checker.CheckOrganization(context.Background(), "testorg")
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	if len(accounts) != 2 {
+		t.Fatalf("Expected 2 dormant accounts, got %d: %+v", len(accounts), accounts)
+	}
+	if accounts[0].Login != "dormant" || accounts[0].Role != "owner" || accounts[0].Scope != "org:testorg" {
+		t.Errorf("Unexpected first account: %+v", accounts[0])
+	}
+	if accounts[1].Login != "silent" || !accounts[1].LastActivity.IsZero() {
+		t.Errorf("Expected silent account without visible activity, got %+v", accounts[1])
+	}
+}
+
+func TestCheckRepository(t *testing.T) {
+	old := time.Now().Add(-365 * 24 * time.Hour)
+
+	mockClient := &mockgithub.MockGitHubClient{
+		MockCollaborators: []*github.User{
+			createMockUser("admin-user", map[string]bool{"admin": true, "maintain": true, "push": true}),
+			createMockUser("maintainer", map[string]bool{"maintain": true, "push": true}),
+			createMockUser("writer", map[string]bool{"push": true}),
+		},
+		MockLatestUserEvents: map[string]*github.Event{
+			"admin-user": createMockEvent(old),
+			"maintainer": createMockEvent(old),
+			"writer":     createMockEvent(old),
+		},
+	}
+
+	checker := dormantaccess.NewDormantAccessChecker(mockClient, newConfig())
+	accounts, err := checker.CheckRepository(context.Background(), "owner/repo")
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	if len(accounts) != 2 {
+		t.Fatalf("Expected 2 dormant privileged accounts, got %d", len(accounts))
+	}
+	if accounts[0].Role != "admin" || accounts[1].Role != "maintain" {
+		t.Errorf("Expected roles admin and maintain, got %q and %q", accounts[0].Role, accounts[1].Role)
+	}
+
+	// Lookups are cached, so a second check does not query activity again
+	if _, err := checker.CheckRepository(context.Background(), "owner/repo"); err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if mockClient.GetLatestUserEventCalls != 2 {
+		t.Errorf("Expected 2 activity lookups, got %d", mockClient.GetLatestUserEventCalls)
+	}
+}
+
+func TestCheckOrganizationError(t *testing.T) {
+	mockClient := &mockgithub.MockGitHubClient{
+		MockOrgMembersErr: errors.New("API error"),
+	}
+
+	checker := dormantaccess.NewDormantAccessChecker(mockClient, newConfig())
+	if _, err := checker.CheckOrganization(context.Background(), "testorg"); err == nil {
+		t.Error("Expected an error but got nil")
+	}
+}
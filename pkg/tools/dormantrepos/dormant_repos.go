@@ -0,0 +1,280 @@
+package dormantrepos
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/policy"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	"github.com/google/go-github/v45/github"
+)
+
+const (
+	// DefaultInactivePeriod is the default period without activity before a repository is reported
+	DefaultInactivePeriod = 180 * 24 * time.Hour
+)
+
+// Repository represents a repository without recent activity
+type Repository struct {
+	Name              string
+	LastPush          time.Time
+	LastIssueActivity time.Time // Zero when the repository has no issues or pull requests
+	Suggestion        string    // "archive" when archiving is suggested, empty otherwise
+}
+
+// LastActivity returns the most recent push, issue or pull request activity
+func (r Repository) LastActivity() time.Time {
+	if r.LastIssueActivity.After(r.LastPush) {
+		return r.LastIssueActivity
+	}
+	return r.LastPush
+}
+
+// Checker is a service that reports repositories without recent activity
+type Checker struct {
+	client         common.GitHubClientInterface
+	inactivePeriod time.Duration
+	config         *config.Config
+	policies       *policy.Loader // Repository policies, nil when they are disabled
+}
+
+// NewDormantReposChecker creates a new Checker
+func NewDormantReposChecker(client common.GitHubClientInterface, config *config.Config) *Checker {
+	inactivePeriod := DefaultInactivePeriod
+	if config.Monitors.DormantRepos.InactiveDays > 0 {
+		inactivePeriod = time.Duration(config.Monitors.DormantRepos.InactiveDays) * 24 * time.Hour
+	}
+
+	checker := &Checker{
+		client:         client,
+		inactivePeriod: inactivePeriod,
+		config:         config,
+	}
+	if config.RepoPolicy.Enabled {
+		checker.policies = policy.NewLoader(client, config.RepoPolicy.Path)
+	}
+
+	return checker
+}
+
+// Run checks all configured organizations and repositories for dormant repositories
+func (c *Checker) Run(ctx context.Context) ([]Repository, error) {
+	allRepos := make([]Repository, 0)
+
+	for _, org := range c.config.Monitors.DormantRepos.Organizations {
+		if common.SkipIfStopped(ctx, "org:"+org) {
+			continue
+		}
+		repos, err := c.CheckOrganization(ctx, org)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, "org:"+org, err) {
+				continue
+			}
+			log.Printf("Error checking dormant repositories for organization %s: %v", org, err)
+			continue
+		}
+		allRepos = append(allRepos, repos...)
+	}
+
+	for _, repository := range c.config.Monitors.DormantRepos.Repositories {
+		if common.SkipIfStopped(ctx, repository) {
+			continue
+		}
+		repo, dormant, err := c.CheckRepository(ctx, repository)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, repository, err) {
+				continue
+			}
+			log.Printf("Error checking activity for repository %s: %v", repository, err)
+			continue
+		}
+		if dormant {
+			allRepos = append(allRepos, repo)
+		}
+	}
+
+	return allRepos, nil
+}
+
+// CheckOrganization reports the dormant repositories of an organization
+// Repositories that are already archived are skipped
+func (c *Checker) CheckOrganization(ctx context.Context, org string) ([]Repository, error) {
+	log.Printf("Checking for repositories in %s organization inactive for more than %v", org, c.inactivePeriod)
+
+	repos, err := c.client.ListOrganizationRepositories(ctx, org, "all")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization repositories: %w", err)
+	}
+	repos, filtered := common.FilterRepositories(ctx, repos, c.config.RepoFilters)
+	if filtered > 0 {
+		log.Printf("Filtered out %d repositories of %s not matching repo_filters", filtered, org)
+	}
+
+	dormantRepos := make([]Repository, 0)
+	for _, repo := range repos {
+		result, dormant, err := c.checkActivity(ctx, repo)
+		if err != nil {
+			log.Printf("Error checking activity for repository %s: %v", repo.GetFullName(), err)
+			continue
+		}
+		if dormant {
+			dormantRepos = append(dormantRepos, result)
+		}
+	}
+
+	return dormantRepos, nil
+}
+
+// CheckRepository determines whether a single repository is dormant
+func (c *Checker) CheckRepository(ctx context.Context, repository string) (Repository, bool, error) {
+	owner, repo, ok := common.ParseRepository(repository)
+	if !ok {
+		return Repository{}, false, fmt.Errorf("invalid repository format, expected 'owner/repo'")
+	}
+
+	log.Printf("Checking for activity in %s within the last %v", repository, c.inactivePeriod)
+
+	repoInfo, err := c.client.GetRepository(ctx, owner, repo)
+	if err != nil {
+		return Repository{}, false, fmt.Errorf("failed to get repository: %w", err)
+	}
+	if repoInfo == nil {
+		return Repository{}, false, fmt.Errorf("repository %s not found", repository)
+	}
+
+	return c.checkActivity(ctx, repoInfo)
+}
+
+// checkActivity compares the latest push, issue and pull request activity of a repository with the inactive period
+func (c *Checker) checkActivity(ctx context.Context, repo *github.Repository) (Repository, bool, error) {
+	result := Repository{
+		Name:     repo.GetFullName(),
+		LastPush: common.LocalTime(repo.GetPushedAt().Time, c.config.Location()),
+	}
+
+	if repo.GetArchived() {
+		return result, false, nil
+	}
+
+	cutoffTime := common.WindowStart(time.Now(), c.inactivePeriodFor(ctx, result.Name), c.config.Location())
+
+	// A recent push is enough to consider the repository active, which saves an API call
+	if result.LastPush.After(cutoffTime) {
+		return result, false, nil
+	}
+
+	owner, name, ok := common.ParseRepository(result.Name)
+	if !ok {
+		return result, false, fmt.Errorf("invalid repository name %q", result.Name)
+	}
+
+	// The issues endpoint includes pull requests
+	issue, err := c.client.GetLatestIssueActivity(ctx, owner, name)
+	if err != nil {
+		return result, false, err
+	}
+	if issue != nil {
+		result.LastIssueActivity = common.LocalTime(issue.GetUpdatedAt(), c.config.Location())
+	}
+
+	if result.LastActivity().After(cutoffTime) {
+		return result, false, nil
+	}
+
+	if c.config.Monitors.DormantRepos.SuggestArchive {
+		result.Suggestion = "archive"
+	}
+
+	return result, true, nil
+}
+
+// inactivePeriodFor returns the inactive period of a repository, applying its policy override within the central cap
+func (c *Checker) inactivePeriodFor(ctx context.Context, repository string) time.Duration {
+	if c.policies == nil {
+		return c.inactivePeriod
+	}
+
+	override := c.policies.Load(ctx, repository).DormantRepositories.InactiveDays
+	if override == 0 {
+		return c.inactivePeriod
+	}
+
+	central := int(c.inactivePeriod / (24 * time.Hour))
+	max := c.config.RepoPolicy.MaxDormantInactiveDays
+	if max == 0 {
+		max = central
+	}
+
+	days := policy.Limit(central, override, max)
+	if days != override {
+		log.Printf("Policy of %s sets %d inactive days, limited to %d", repository, override, days)
+	}
+
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// Findings converts dormant repositories into findings
+func Findings(repos []Repository) []findings.Finding {
+	list := make([]findings.Finding, 0, len(repos))
+	for _, r := range repos {
+		list = append(list, findings.Finding{
+			Monitor:    "dormant_repositories",
+			Repository: r.Name,
+			Subject:    "activity",
+			Summary:    "Repository has no recent pushes, issues or pull requests",
+			URL:        "https://github.com/" + r.Name,
+		})
+	}
+	return list
+}
+
+// WriteResultsMarkdown writes dormant repositories in a code block format
+// suitable for Slack notifications
+func WriteResultsMarkdown(w io.Writer, repos []Repository) {
+	if len(repos) == 0 {
+		return // No results to display
+	}
+
+	// Print header for dormant repositories
+	fmt.Fprintln(w, "## :warning: Dormant Repositories")
+	fmt.Fprintf(w, "Found %d repositories without recent pushes, issues or pull requests.\n\n", len(repos))
+
+	// Start code block
+	fmt.Fprintln(w, "```")
+	// Create fixed-width headers with proper spacing for code block
+	fmt.Fprintln(w, "Repository                Last Push   Last Activity  Suggestion")
+	fmt.Fprintln(w, "---------------------------------------------------------------------")
+
+	// Print each repository in a fixed-width format for code blocks
+	for _, r := range repos {
+		// Format repository name with padding
+		repoStr := r.Name
+		if len(repoStr) > 24 {
+			repoStr = repoStr[:21] + "..."
+		} else {
+			repoStr = fmt.Sprintf("%-24s", repoStr)
+		}
+
+		lastPush := "never"
+		if !r.LastPush.IsZero() {
+			lastPush = r.LastPush.Format("2006-01-02")
+		}
+
+		lastActivity := "never"
+		if !r.LastActivity().IsZero() {
+			lastActivity = r.LastActivity().Format("2006-01-02")
+		}
+
+		// Format the output row with fixed-width fields
+		fmt.Fprintf(w, "%s %-11s %-14s %s\n", repoStr, lastPush, lastActivity, r.Suggestion)
+	}
+
+	// End code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
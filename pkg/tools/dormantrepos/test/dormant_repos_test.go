@@ -0,0 +1,179 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	mockgithub "github.com/anupsv/git-monitoring/pkg/tools/common/test"
+	"github.com/anupsv/git-monitoring/pkg/tools/dormantrepos"
+)
+
+func createMockRepo(fullName string, pushedAt time.Time, archived bool) *github.Repository {
+	return &github.Repository{
+		FullName: &fullName,
+		PushedAt: &github.Timestamp{Time: pushedAt},
+		Archived: &archived,
+	}
+}
+
+func createMockIssue(updatedAt time.Time) *github.Issue {
+	return &github.Issue{UpdatedAt: &updatedAt}
+}
+
+func newConfig(suggestArchive bool) *config.Config {
+	return &config.Config{
+		Monitors: config.MonitorsConfig{
+			DormantRepos: config.DormantReposConfig{
+				Enabled:        true,
+				Organizations:  []string{"testorg"},
+				InactiveDays:   30,
+				SuggestArchive: suggestArchive,
+			},
+		},
+	}
+}
+
+func TestCheckOrganization(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-90 * 24 * time.Hour)
+
+	mockClient := &mockgithub.MockGitHubClient{
+		MockOrgRepositories: []*github.Repository{
+			createMockRepo("testorg/pushed", now.Add(-time.Hour), false),
+			createMockRepo("testorg/discussed", old, false),
+			createMockRepo("testorg/dormant", old, false),
+			createMockRepo("testorg/archived", old, true),
+		},
+		MockLatestIssues: map[string]*github.Issue{
+			"testorg/discussed": createMockIssue(now.Add(-24 * time.Hour)),
+			"testorg/dormant":   createMockIssue(old),
+		},
+	}
+
+	checker := dormantrepos.NewDormantReposChecker(mockClient, newConfig(true))
+	repos, err := checker.CheckOrganization(context.Background(), "testorg")
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	if len(repos) != 1 {
+		t.Fatalf("Expected 1 dormant repository, got %d: %+v", len(repos), repos)
+	}
+	if repos[0].Name != "testorg/dormant" {
+		t.Errorf("Expected repository %q, got %q", "testorg/dormant", repos[0].Name)
+	}
+	if repos[0].Suggestion != "archive" {
+		t.Errorf("Expected archive suggestion, got %q", repos[0].Suggestion)
+	}
+
+	// Only repositories without a recent push need their issues checked
+	if mockClient.GetLatestIssueActivityCalls != 2 {
+		t.Errorf("Expected 2 issue lookups, got %d", mockClient.GetLatestIssueActivityCalls)
+	}
+}
+
+func TestCheckRepository(t *testing.T) {
+	old := time.Now().Add(-90 * 24 * time.Hour)
+
+	tests := []struct {
+		name          string
+		repository    string
+		repo          *github.Repository
+		repoErr       error
+		expectError   bool
+		expectDormant bool
+	}{
+		{
+			name:          "Dormant repository",
+			repository:    "owner/repo",
+			repo:          createMockRepo("owner/repo", old, false),
+			expectDormant: true,
+		},
+		{
+			name:          "Active repository",
+			repository:    "owner/repo",
+			repo:          createMockRepo("owner/repo", time.Now(), false),
+			expectDormant: false,
+		},
+		{
+			name:        "Error getting repository",
+			repository:  "owner/repo",
+			repoErr:     errors.New("API error"),
+			expectError: true,
+		},
+		{
+			name:        "Invalid repository format",
+			repository:  "invalid-format",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &mockgithub.MockGitHubClient{
+				MockRepository:    map[string]*github.Repository{"owner/repo": tc.repo},
+				MockRepositoryErr: tc.repoErr,
+			}
+
+			checker := dormantrepos.NewDormantReposChecker(mockClient, newConfig(false))
+			repo, dormant, err := checker.CheckRepository(context.Background(), tc.repository)
+
+			if tc.expectError && err == nil {
+				t.Error("Expected an error but got nil")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("Did not expect an error but got: %v", err)
+			}
+			if dormant != tc.expectDormant {
+				t.Errorf("Expected dormant %v, got %v", tc.expectDormant, dormant)
+			}
+			if dormant && repo.Suggestion != "" {
+				t.Errorf("Expected no suggestion when archiving is not suggested, got %q", repo.Suggestion)
+			}
+		})
+	}
+}
+
+func TestRepositoryPolicy(t *testing.T) {
+	now := time.Now()
+
+	mockClient := &mockgithub.MockGitHubClient{
+		MockOrgRepositories: []*github.Repository{
+			createMockRepo("testorg/strict", now.Add(-10*24*time.Hour), false),
+			createMockRepo("testorg/relaxed", now.Add(-45*24*time.Hour), false),
+			createMockRepo("testorg/capped", now.Add(-90*24*time.Hour), false),
+		},
+		MockFileContents: map[string]string{
+			"testorg/strict/.github/git-monitor.toml":  "[dormant_repositories]\ninactive_days = 7\n",
+			"testorg/relaxed/.github/git-monitor.toml": "[dormant_repositories]\ninactive_days = 60\n",
+			"testorg/capped/.github/git-monitor.toml":  "[dormant_repositories]\ninactive_days = 365\n",
+		},
+	}
+
+	cfg := newConfig(false)
+	cfg.RepoPolicy = config.RepoPolicyConfig{
+		Enabled:                true,
+		Path:                   ".github/git-monitor.toml",
+		MaxDormantInactiveDays: 60,
+	}
+
+	checker := dormantrepos.NewDormantReposChecker(mockClient, cfg)
+	repos, err := checker.CheckOrganization(context.Background(), "testorg")
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	// strict is dormant after 7 days, relaxed is allowed 60 days, capped asks for 365 but is limited to 60
+	var names []string
+	for _, r := range repos {
+		names = append(names, r.Name)
+	}
+	if len(names) != 2 || names[0] != "testorg/strict" || names[1] != "testorg/capped" {
+		t.Errorf("Expected testorg/strict and testorg/capped to be dormant, got %v", names)
+	}
+}
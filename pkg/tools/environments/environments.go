@@ -0,0 +1,355 @@
+package environments
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	"github.com/google/go-github/v45/github"
+)
+
+const (
+	// DefaultCheckWindow is the default time window to look for deployments
+	DefaultCheckWindow = 24 * time.Hour
+)
+
+// Rules deployments and environments can break
+const (
+	RuleUnlistedActor    = "unlisted_actor"    // Deployed by someone who is not a required reviewer of the environment
+	RuleWaitTimerBypass  = "wait_timer_bypass" // Deployment started before the wait timer of the environment ran out
+	RuleMissingReviewers = "missing_reviewers" // Environment without required reviewers
+	RuleShortWaitTimer   = "short_wait_timer"  // Environment with a wait timer below the configured minimum
+	RuleAnyBranch        = "any_branch"        // Environment any branch can deploy to
+)
+
+// Violation is a deployment to a protected environment, or an environment protection rule, breaking a rule
+type Violation struct {
+	Repository   string
+	Environment  string
+	Rule         string // Rule broken, e.g. RuleUnlistedActor
+	DeploymentID int64  // 0 for environment protection drift
+	Actor        string // Who deployed, empty for environment protection drift
+	Detail       string
+	URL          string
+}
+
+// Checker is a service that audits deployments to protected environments and their protection rules
+type Checker struct {
+	client      common.GitHubClientInterface
+	checkWindow time.Duration
+	config      *config.Config
+}
+
+// NewEnvironmentsChecker creates a new Checker
+func NewEnvironmentsChecker(client common.GitHubClientInterface, config *config.Config) *Checker {
+	checkWindow := DefaultCheckWindow
+	if config.Monitors.Environments.CheckWindow.Duration > 0 {
+		checkWindow = config.Monitors.Environments.CheckWindow.Duration
+	}
+
+	return &Checker{
+		client:      client,
+		checkWindow: checkWindow,
+		config:      config,
+	}
+}
+
+// Run checks the environments of all configured organizations and repositories
+func (c *Checker) Run(ctx context.Context) ([]Violation, error) {
+	allViolations := make([]Violation, 0)
+
+	for _, org := range c.config.Monitors.Environments.Organizations {
+		if common.SkipIfStopped(ctx, "org:"+org) {
+			continue
+		}
+		violations, err := c.CheckOrganization(ctx, org)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, "org:"+org, err) {
+				continue
+			}
+			log.Printf("Error checking environments for organization %s: %v", org, err)
+			continue
+		}
+		allViolations = append(allViolations, violations...)
+	}
+
+	for _, repository := range c.config.Monitors.Environments.Repositories {
+		if common.SkipIfStopped(ctx, repository) {
+			continue
+		}
+		violations, err := c.CheckRepository(ctx, repository)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, repository, err) {
+				continue
+			}
+			log.Printf("Error checking environments for repository %s: %v", repository, err)
+			continue
+		}
+		allViolations = append(allViolations, violations...)
+	}
+
+	return allViolations, nil
+}
+
+// CheckOrganization checks the environments of the repositories of an organization
+// Archived repositories no longer deploy and are skipped
+func (c *Checker) CheckOrganization(ctx context.Context, org string) ([]Violation, error) {
+	log.Printf("Checking environments of repositories in %s organization", org)
+
+	repos, err := c.client.ListOrganizationRepositories(ctx, org, "all")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization repositories: %w", err)
+	}
+	repos, filtered := common.FilterRepositories(ctx, repos, c.config.RepoFilters)
+	if filtered > 0 {
+		log.Printf("Filtered out %d repositories of %s not matching repo_filters", filtered, org)
+	}
+
+	allViolations := make([]Violation, 0)
+	for _, repo := range repos {
+		if repo.GetArchived() {
+			continue
+		}
+		violations, err := c.CheckRepository(ctx, repo.GetFullName())
+		if err != nil {
+			log.Printf("Error checking environments for repository %s: %v", repo.GetFullName(), err)
+			continue
+		}
+		allViolations = append(allViolations, violations...)
+	}
+
+	return allViolations, nil
+}
+
+// CheckRepository checks the configured environments of a repository
+// Repositories without one of the environments do not deploy to it, which is not reported
+func (c *Checker) CheckRepository(ctx context.Context, repository string) ([]Violation, error) {
+	owner, repo, ok := common.ParseRepository(repository)
+	if !ok {
+		return nil, fmt.Errorf("invalid repository format, expected 'owner/repo'")
+	}
+
+	now := time.Now()
+	cutoffTime := common.WindowStart(now, c.checkWindow, c.config.WindowLocation())
+	windowEnd := common.WindowEnd(now, c.checkWindow, c.config.WindowLocation())
+
+	violations := make([]Violation, 0)
+	for _, name := range c.config.Monitors.Environments.Environments {
+		environment, err := c.client.GetEnvironment(ctx, owner, repo, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get environment %s: %w", name, err)
+		}
+		if environment == nil {
+			continue
+		}
+
+		violations = append(violations, c.checkProtection(repository, environment)...)
+
+		deployments, err := c.client.ListDeployments(common.WithPagination(ctx, c.config.Monitors.Environments.Pagination), owner, repo, name, cutoffTime)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list deployments to %s: %w", name, err)
+		}
+		for _, deployment := range deployments {
+			// Deployments since the window ended are left to the next run
+			if !deployment.GetCreatedAt().Before(windowEnd) {
+				continue
+			}
+			found, err := c.checkDeployment(ctx, owner, repo, environment, deployment)
+			if err != nil {
+				return nil, err
+			}
+			violations = append(violations, found...)
+		}
+	}
+
+	return violations, nil
+}
+
+// checkProtection compares the protection rules of an environment with the configured ones
+func (c *Checker) checkProtection(repository string, environment *common.Environment) []Violation {
+	cfg := c.config.Monitors.Environments
+	settingsURL := fmt.Sprintf("https://github.com/%s/settings/environments", repository)
+
+	drift := func(rule, detail string) Violation {
+		return Violation{Repository: repository, Environment: environment.Name, Rule: rule, Detail: detail, URL: settingsURL}
+	}
+
+	var violations []Violation
+	if cfg.RequireReviewers && len(environment.Reviewers()) == 0 {
+		violations = append(violations, drift(RuleMissingReviewers, "deployments need no approval of required reviewers"))
+	}
+	if minimum := time.Duration(cfg.MinWaitTimer) * time.Minute; minimum > 0 && environment.WaitTimer() < minimum {
+		violations = append(violations, drift(RuleShortWaitTimer,
+			fmt.Sprintf("wait timer of %v, below the minimum of %v", environment.WaitTimer(), minimum)))
+	}
+	if cfg.RequireBranchPolicy && environment.DeploymentBranchPolicy == nil {
+		violations = append(violations, drift(RuleAnyBranch, "any branch can deploy"))
+	}
+	return violations
+}
+
+// checkDeployment checks who created a deployment and whether it waited for the wait timer of the environment
+// The statuses of the deployment are only fetched when the environment has a wait timer
+func (c *Checker) checkDeployment(ctx context.Context, owner, repo string, environment *common.Environment, deployment *github.Deployment) ([]Violation, error) {
+	repository := owner + "/" + repo
+	actor := deployment.GetCreator().GetLogin()
+	violation := func(rule, detail, link string) Violation {
+		if link == "" {
+			link = fmt.Sprintf("https://github.com/%s/deployments/%s", repository, url.PathEscape(environment.Name))
+		}
+		return Violation{
+			Repository:   repository,
+			Environment:  environment.Name,
+			Rule:         rule,
+			DeploymentID: deployment.GetID(),
+			Actor:        actor,
+			Detail:       detail,
+			URL:          link,
+		}
+	}
+
+	var violations []Violation
+
+	if reviewers := environment.Reviewers(); len(reviewers) > 0 {
+		listed, err := c.isReviewer(ctx, owner, reviewers, actor)
+		if err != nil {
+			return nil, err
+		}
+		if !listed {
+			violations = append(violations, violation(RuleUnlistedActor,
+				fmt.Sprintf("deployed by %s, who is not a required reviewer of %s", actor, environment.Name), ""))
+		}
+	}
+
+	waitTimer := environment.WaitTimer()
+	if waitTimer == 0 {
+		return violations, nil
+	}
+
+	statuses, err := c.client.ListDeploymentStatuses(ctx, owner, repo, deployment.GetID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statuses of deployment %d: %w", deployment.GetID(), err)
+	}
+
+	// Statuses are returned newest first, the last one that is not waiting is when the deployment started
+	var started *github.DeploymentStatus
+	for _, status := range statuses {
+		switch status.GetState() {
+		case "queued", "in_progress", "success", "failure", "error":
+			started = status
+		}
+	}
+	if started == nil {
+		return violations, nil
+	}
+
+	waited := started.GetCreatedAt().Sub(deployment.GetCreatedAt().Time)
+	if waited < waitTimer {
+		link := started.GetLogURL()
+		if link == "" {
+			link = started.GetTargetURL()
+		}
+		violations = append(violations, violation(RuleWaitTimerBypass,
+			fmt.Sprintf("started %v after it was created, before the wait timer of %v", waited.Round(time.Second), waitTimer), link))
+	}
+
+	return violations, nil
+}
+
+// isReviewer reports whether a user is one of the required reviewers, directly or as a member of a team
+func (c *Checker) isReviewer(ctx context.Context, org string, reviewers []*common.EnvironmentReviewer, user string) (bool, error) {
+	for _, reviewer := range reviewers {
+		if reviewer.Type == "User" && strings.EqualFold(reviewer.Reviewer.Login, user) {
+			return true, nil
+		}
+	}
+
+	for _, reviewer := range reviewers {
+		if reviewer.Type != "Team" {
+			continue
+		}
+		member, err := c.client.IsTeamMember(ctx, org, reviewer.Reviewer.Slug, user)
+		if err != nil {
+			return false, err
+		}
+		if member {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Findings converts environment violations into findings
+func Findings(violations []Violation) []findings.Finding {
+	list := make([]findings.Finding, 0, len(violations))
+	for _, v := range violations {
+		subject := fmt.Sprintf("%s (%s)", v.Environment, v.Rule)
+		if v.DeploymentID != 0 {
+			subject = fmt.Sprintf("deployment #%d to %s", v.DeploymentID, subject)
+		}
+		list = append(list, findings.Finding{
+			Monitor:    "environment_protection",
+			Repository: v.Repository,
+			Subject:    subject,
+			Summary:    v.Detail,
+			URL:        v.URL,
+		})
+	}
+	return list
+}
+
+// WriteResultsMarkdown writes environment violations in a code block format
+// suitable for Slack notifications
+func WriteResultsMarkdown(w io.Writer, violations []Violation) {
+	if len(violations) == 0 {
+		return // No results to display
+	}
+
+	// Print header for environment violations
+	fmt.Fprintln(w, "## :rocket: Deployment Environment Protection")
+	fmt.Fprintf(w, "Found %d deployments or environments breaking protection rules.\n\n", len(violations))
+
+	// Start code block
+	fmt.Fprintln(w, "```")
+	// Create fixed-width headers with proper spacing for code block
+	fmt.Fprintln(w, "Repository                Environment   Rule               Actor               Link")
+	fmt.Fprintln(w, "---------------------------------------------------------------------")
+
+	// Print each violation in a fixed-width format for code blocks
+	for _, v := range violations {
+		// Format repository name with padding
+		repoStr := v.Repository
+		if len(repoStr) > 24 {
+			repoStr = repoStr[:21] + "..."
+		} else {
+			repoStr = fmt.Sprintf("%-24s", repoStr)
+		}
+
+		// Format environment name with padding
+		envStr := v.Environment
+		if len(envStr) > 12 {
+			envStr = envStr[:9] + "..."
+		} else {
+			envStr = fmt.Sprintf("%-12s", envStr)
+		}
+
+		actorStr := v.Actor
+		if actorStr == "" {
+			actorStr = "-"
+		}
+
+		fmt.Fprintf(w, "%s  %s  %-17s  %-18s  %s\n", repoStr, envStr, v.Rule, actorStr, v.URL)
+	}
+
+	// End code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
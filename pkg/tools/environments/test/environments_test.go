@@ -0,0 +1,216 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	mockgithub "github.com/anupsv/git-monitoring/pkg/tools/common/test"
+	"github.com/anupsv/git-monitoring/pkg/tools/environments"
+)
+
+// createEnvironment creates a production environment with the given reviewers and wait timer
+func createEnvironment(waitTimer int, reviewers ...*common.EnvironmentReviewer) *common.Environment {
+	env := &common.Environment{Name: "production"}
+	if len(reviewers) > 0 {
+		env.ProtectionRules = append(env.ProtectionRules, &common.EnvironmentRule{Type: "required_reviewers", Reviewers: reviewers})
+	}
+	if waitTimer > 0 {
+		env.ProtectionRules = append(env.ProtectionRules, &common.EnvironmentRule{Type: "wait_timer", WaitTimer: waitTimer})
+	}
+	return env
+}
+
+// createReviewer creates a required reviewer, a user or a team by its slug
+func createReviewer(reviewerType, name string) *common.EnvironmentReviewer {
+	reviewer := &common.EnvironmentReviewer{Type: reviewerType}
+	if reviewerType == "Team" {
+		reviewer.Reviewer.Slug = name
+	} else {
+		reviewer.Reviewer.Login = name
+	}
+	return reviewer
+}
+
+// createDeployment creates a deployment created by creator at the given time
+func createDeployment(id int64, creator string, createdAt time.Time) *github.Deployment {
+	return &github.Deployment{
+		ID:        github.Int64(id),
+		Creator:   &github.User{Login: github.String(creator)},
+		CreatedAt: &github.Timestamp{Time: createdAt},
+	}
+}
+
+// createStatus creates a deployment status in the given state
+func createStatus(state string, createdAt time.Time) *github.DeploymentStatus {
+	return &github.DeploymentStatus{
+		State:     github.String(state),
+		CreatedAt: &github.Timestamp{Time: createdAt},
+		LogURL:    github.String("https://github.com/testorg/repo1/actions/runs/1"),
+	}
+}
+
+// newConfig creates a config auditing the production environment of testorg/repo1
+func newConfig() *config.Config {
+	return &config.Config{
+		Monitors: config.MonitorsConfig{
+			Environments: config.EnvironmentsConfig{
+				Enabled:      true,
+				Repositories: []string{"testorg/repo1"},
+				Environments: []string{"production"},
+				CheckWindow:  config.Hours(24),
+			},
+		},
+	}
+}
+
+func TestDeployments(t *testing.T) {
+	created := time.Now().Add(-2 * time.Hour)
+
+	tests := []struct {
+		name          string
+		environment   *common.Environment
+		deployment    *github.Deployment
+		statuses      []*github.DeploymentStatus
+		expectedRules []string
+	}{
+		{
+			name:        "Deployed by a required reviewer",
+			environment: createEnvironment(0, createReviewer("User", "alice")),
+			deployment:  createDeployment(1, "Alice", created),
+		},
+		{
+			name:        "Deployed by a member of a required team",
+			environment: createEnvironment(0, createReviewer("Team", "release")),
+			deployment:  createDeployment(1, "bob", created),
+		},
+		{
+			name:          "Deployed by someone else",
+			environment:   createEnvironment(0, createReviewer("User", "alice"), createReviewer("Team", "release")),
+			deployment:    createDeployment(1, "mallory", created),
+			expectedRules: []string{environments.RuleUnlistedActor},
+		},
+		{
+			name:        "Environment without required reviewers",
+			environment: createEnvironment(0),
+			deployment:  createDeployment(1, "mallory", created),
+		},
+		{
+			name:        "Waited for the wait timer",
+			environment: createEnvironment(30),
+			deployment:  createDeployment(1, "alice", created),
+			statuses: []*github.DeploymentStatus{
+				createStatus("success", created.Add(40*time.Minute)),
+				createStatus("in_progress", created.Add(31*time.Minute)),
+				createStatus("waiting", created),
+			},
+		},
+		{
+			name:        "Wait timer bypassed",
+			environment: createEnvironment(30),
+			deployment:  createDeployment(1, "alice", created),
+			statuses: []*github.DeploymentStatus{
+				createStatus("success", created.Add(10*time.Minute)),
+				createStatus("in_progress", created.Add(2*time.Minute)),
+				createStatus("waiting", created),
+			},
+			expectedRules: []string{environments.RuleWaitTimerBypass},
+		},
+		{
+			name:        "Deployment outside the window",
+			environment: createEnvironment(30, createReviewer("User", "alice")),
+			deployment:  createDeployment(1, "mallory", created.Add(-48*time.Hour)),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &mockgithub.MockGitHubClient{
+				MockEnvironments:       map[string]*common.Environment{"testorg/repo1/production": tc.environment},
+				MockDeployments:        map[string][]*github.Deployment{"testorg/repo1/production": {tc.deployment}},
+				MockDeploymentStatuses: map[int64][]*github.DeploymentStatus{1: tc.statuses},
+				MockTeamMemberships:    map[string]bool{"testorg/release/bob": true},
+			}
+
+			checker := environments.NewEnvironmentsChecker(mockClient, newConfig())
+			violations, err := checker.CheckRepository(context.Background(), "testorg/repo1")
+			if err != nil {
+				t.Fatalf("Did not expect an error but got: %v", err)
+			}
+
+			if len(violations) != len(tc.expectedRules) {
+				t.Fatalf("Expected %d violations, got %+v", len(tc.expectedRules), violations)
+			}
+			for i, v := range violations {
+				if v.Rule != tc.expectedRules[i] {
+					t.Errorf("Expected rule %q, got %q", tc.expectedRules[i], v.Rule)
+				}
+				if v.DeploymentID != 1 || v.Environment != "production" {
+					t.Errorf("Unexpected violation %+v", v)
+				}
+			}
+			// Statuses are only needed to check wait timers
+			if tc.environment.WaitTimer() == 0 && mockClient.ListDeploymentStatusesCalls != 0 {
+				t.Errorf("Expected no statuses to be fetched, got %d calls", mockClient.ListDeploymentStatusesCalls)
+			}
+		})
+	}
+}
+
+func TestProtectionDrift(t *testing.T) {
+	cfg := newConfig()
+	cfg.Monitors.Environments.RequireReviewers = true
+	cfg.Monitors.Environments.MinWaitTimer = 15
+	cfg.Monitors.Environments.RequireBranchPolicy = true
+
+	mockClient := &mockgithub.MockGitHubClient{
+		MockEnvironments: map[string]*common.Environment{
+			"testorg/repo1/production": createEnvironment(5),
+			"testorg/repo2/production": func() *common.Environment {
+				env := createEnvironment(30, createReviewer("User", "alice"))
+				env.DeploymentBranchPolicy = &common.DeploymentBranchPolicy{ProtectedBranches: true}
+				return env
+			}(),
+		},
+	}
+
+	checker := environments.NewEnvironmentsChecker(mockClient, cfg)
+
+	violations, err := checker.CheckRepository(context.Background(), "testorg/repo1")
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	expected := []string{environments.RuleMissingReviewers, environments.RuleShortWaitTimer, environments.RuleAnyBranch}
+	if len(violations) != len(expected) {
+		t.Fatalf("Expected %d violations, got %+v", len(expected), violations)
+	}
+	for i, v := range violations {
+		if v.Rule != expected[i] {
+			t.Errorf("Expected rule %q, got %q", expected[i], v.Rule)
+		}
+	}
+	if violations[1].Detail != "wait timer of 5m0s, below the minimum of 15m0s" {
+		t.Errorf("Unexpected detail %q", violations[1].Detail)
+	}
+	if list := environments.Findings(violations); list[0].Subject != "production (missing_reviewers)" {
+		t.Errorf("Unexpected finding subject %q", list[0].Subject)
+	}
+
+	violations, err = checker.CheckRepository(context.Background(), "testorg/repo2")
+	if err != nil || len(violations) != 0 {
+		t.Errorf("Expected no violations for a protected environment, got %+v, %v", violations, err)
+	}
+
+	// Repositories without the environment do not deploy to it
+	violations, err = checker.CheckRepository(context.Background(), "testorg/repo3")
+	if err != nil || len(violations) != 0 {
+		t.Errorf("Expected no violations without the environment, got %+v, %v", violations, err)
+	}
+	if mockClient.ListDeploymentsCalls != 2 {
+		t.Errorf("Expected deployments to be listed for existing environments only, got %d calls", mockClient.ListDeploymentsCalls)
+	}
+}
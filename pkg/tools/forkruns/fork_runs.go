@@ -0,0 +1,248 @@
+package forkruns
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+const (
+	// DefaultCheckWindow is the default time window to look for approved workflow runs
+	DefaultCheckWindow = 24 * time.Hour
+)
+
+// pullRequestEvents are the events fork PRs trigger workflows with
+// Only pull_request_target runs get the secrets of the base repository
+var pullRequestEvents = []string{"pull_request", "pull_request_target"}
+
+// Approval is a workflow run of a fork PR started by someone else than its contributor
+type Approval struct {
+	Repository  string
+	RunID       int64
+	Workflow    string
+	Event       string
+	Fork        string // Repository the PR came from
+	Contributor string
+	ApprovedBy  string
+	ApprovedAt  time.Time
+	Secrets     []string // Actions secrets the run could access
+	URL         string
+}
+
+// Checker is a service that reports approved workflow runs of fork PRs within the check window
+type Checker struct {
+	client      common.GitHubClientInterface
+	checkWindow time.Duration
+	config      *config.Config
+}
+
+// NewForkRunsChecker creates a new Checker
+func NewForkRunsChecker(client common.GitHubClientInterface, config *config.Config) *Checker {
+	checkWindow := DefaultCheckWindow
+	if config.Monitors.ForkRuns.CheckWindow.Duration > 0 {
+		checkWindow = config.Monitors.ForkRuns.CheckWindow.Duration
+	}
+
+	return &Checker{
+		client:      client,
+		checkWindow: checkWindow,
+		config:      config,
+	}
+}
+
+// Run checks all configured organizations and repositories for approved fork workflow runs
+func (c *Checker) Run(ctx context.Context) ([]Approval, error) {
+	allApprovals := make([]Approval, 0)
+
+	for _, org := range c.config.Monitors.ForkRuns.Organizations {
+		if common.SkipIfStopped(ctx, "org:"+org) {
+			continue
+		}
+		approvals, err := c.CheckOrganization(ctx, org)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, "org:"+org, err) {
+				continue
+			}
+			log.Printf("Error checking fork workflow approvals for organization %s: %v", org, err)
+			continue
+		}
+		allApprovals = append(allApprovals, approvals...)
+	}
+
+	for _, repository := range c.config.Monitors.ForkRuns.Repositories {
+		if common.SkipIfStopped(ctx, repository) {
+			continue
+		}
+		approvals, err := c.CheckRepository(ctx, repository)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, repository, err) {
+				continue
+			}
+			log.Printf("Error checking fork workflow approvals for repository %s: %v", repository, err)
+			continue
+		}
+		allApprovals = append(allApprovals, approvals...)
+	}
+
+	return allApprovals, nil
+}
+
+// CheckOrganization checks the repositories of an organization for approved fork workflow runs
+// Archived repositories run no workflows and are skipped
+func (c *Checker) CheckOrganization(ctx context.Context, org string) ([]Approval, error) {
+	log.Printf("Checking for approved fork workflow runs in %s organization", org)
+
+	repos, err := c.client.ListOrganizationRepositories(ctx, org, "all")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization repositories: %w", err)
+	}
+	repos, filtered := common.FilterRepositories(ctx, repos, c.config.RepoFilters)
+	if filtered > 0 {
+		log.Printf("Filtered out %d repositories of %s not matching repo_filters", filtered, org)
+	}
+
+	allApprovals := make([]Approval, 0)
+	for _, repo := range repos {
+		if repo.GetArchived() {
+			continue
+		}
+		approvals, err := c.CheckRepository(ctx, repo.GetFullName())
+		if err != nil {
+			log.Printf("Error checking fork workflow approvals for repository %s: %v", repo.GetFullName(), err)
+			continue
+		}
+		allApprovals = append(allApprovals, approvals...)
+	}
+
+	return allApprovals, nil
+}
+
+// CheckRepository checks a repository for workflow runs of fork PRs started by someone else than the contributor
+// The secrets of the repository are only listed when a pull_request_target run was approved
+func (c *Checker) CheckRepository(ctx context.Context, repository string) ([]Approval, error) {
+	owner, repo, ok := common.ParseRepository(repository)
+	if !ok {
+		return nil, fmt.Errorf("invalid repository format, expected 'owner/repo'")
+	}
+
+	log.Printf("Checking for approved fork workflow runs in %s", repository)
+
+	now := time.Now()
+	cutoffTime := common.WindowStart(now, c.checkWindow, c.config.WindowLocation())
+	windowEnd := common.WindowEnd(now, c.checkWindow, c.config.WindowLocation())
+
+	var secrets []string
+	secretsListed := false
+
+	approvals := make([]Approval, 0)
+	for _, event := range pullRequestEvents {
+		runs, err := c.client.ListWorkflowRuns(common.WithPagination(ctx, c.config.Monitors.ForkRuns.Pagination), owner, repo, event, cutoffTime)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list workflow runs: %w", err)
+		}
+
+		for _, run := range runs {
+			// Runs since the window ended are left to the next run
+			if run.CreatedAt != nil && !run.CreatedAt.Before(windowEnd) {
+				continue
+			}
+			fork := run.HeadRepository.GetFullName()
+			if fork == "" || strings.EqualFold(fork, repository) {
+				continue
+			}
+			contributor := run.Actor.GetLogin()
+			approver := run.TriggeringActor.GetLogin()
+			if approver == "" || strings.EqualFold(approver, contributor) {
+				continue
+			}
+
+			approval := Approval{
+				Repository:  repository,
+				RunID:       run.ID,
+				Workflow:    run.Name,
+				Event:       run.Event,
+				Fork:        fork,
+				Contributor: contributor,
+				ApprovedBy:  approver,
+				ApprovedAt:  common.LocalTime(run.CreatedAt.Time, c.config.Location()),
+				URL:         run.HTMLURL,
+			}
+
+			if run.Event == "pull_request_target" {
+				if !secretsListed {
+					secrets, err = c.client.ListActionsSecretNames(ctx, owner, repo)
+					if err != nil {
+						return nil, fmt.Errorf("failed to list Actions secrets: %w", err)
+					}
+					secretsListed = true
+				}
+				approval.Secrets = secrets
+			}
+
+			approvals = append(approvals, approval)
+		}
+	}
+
+	return approvals, nil
+}
+
+// Findings converts approved fork workflow runs into findings
+func Findings(approvals []Approval) []findings.Finding {
+	list := make([]findings.Finding, 0, len(approvals))
+	for _, a := range approvals {
+		summary := fmt.Sprintf("%s run of %s from %s approved by %s", a.Workflow, a.Contributor, a.Fork, a.ApprovedBy)
+		if len(a.Secrets) > 0 {
+			summary += fmt.Sprintf(", with access to secrets %s", strings.Join(a.Secrets, ", "))
+		}
+		list = append(list, findings.Finding{
+			Monitor:    "fork_workflow_approvals",
+			Repository: a.Repository,
+			Subject:    fmt.Sprintf("run #%d", a.RunID),
+			Summary:    summary,
+			URL:        a.URL,
+		})
+	}
+	return list
+}
+
+// WriteResultsMarkdown writes approved fork workflow runs in a code block format
+// suitable for Slack notifications
+func WriteResultsMarkdown(w io.Writer, approvals []Approval) {
+	if len(approvals) == 0 {
+		return // No results to display
+	}
+
+	// Print header for approved fork workflow runs
+	fmt.Fprintln(w, "## :fork_and_knife: Approved Fork Workflow Runs")
+	fmt.Fprintf(w, "Found %d workflow runs of outside contributions approved to run.\n\n", len(approvals))
+
+	// Start code block
+	fmt.Fprintln(w, "```")
+	// Create fixed-width headers with proper spacing for code block
+	fmt.Fprintln(w, "Repository                Contributor         Approved By         Secrets  Link")
+	fmt.Fprintln(w, "---------------------------------------------------------------------")
+
+	// Print each approval in a fixed-width format for code blocks
+	for _, a := range approvals {
+		// Format repository name with padding
+		repoStr := a.Repository
+		if len(repoStr) > 24 {
+			repoStr = repoStr[:21] + "..."
+		} else {
+			repoStr = fmt.Sprintf("%-24s", repoStr)
+		}
+
+		fmt.Fprintf(w, "%s  %-18s  %-18s  %-7d  %s\n", repoStr, a.Contributor, a.ApprovedBy, len(a.Secrets), a.URL)
+	}
+
+	// End code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
@@ -0,0 +1,153 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	mockgithub "github.com/anupsv/git-monitoring/pkg/tools/common/test"
+	"github.com/anupsv/git-monitoring/pkg/tools/forkruns"
+)
+
+// createRun creates a workflow run of a PR from head, caused by actor and started by triggeringActor
+func createRun(id int64, event, head, actor, triggeringActor string, createdAt time.Time) *common.WorkflowRun {
+	return &common.WorkflowRun{
+		ID:              id,
+		Name:            "CI",
+		Event:           event,
+		HTMLURL:         "https://github.com/testorg/repo1/actions/runs/1",
+		CreatedAt:       &github.Timestamp{Time: createdAt},
+		Actor:           &github.User{Login: github.String(actor)},
+		TriggeringActor: &github.User{Login: github.String(triggeringActor)},
+		HeadRepository:  &github.Repository{FullName: github.String(head)},
+	}
+}
+
+// newConfig creates a config auditing the workflow runs of testorg/repo1
+func newConfig() *config.Config {
+	return &config.Config{
+		Monitors: config.MonitorsConfig{
+			ForkRuns: config.ForkRunsConfig{
+				Enabled:      true,
+				Repositories: []string{"testorg/repo1"},
+				CheckWindow:  config.Hours(24),
+			},
+		},
+	}
+}
+
+func TestCheckRepository(t *testing.T) {
+	recent := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name           string
+		runs           map[string][]*common.WorkflowRun
+		expectedCount  int
+		expectSecrets  bool
+		expectApprover string
+	}{
+		{
+			name: "Approved fork run",
+			runs: map[string][]*common.WorkflowRun{
+				"testorg/repo1/pull_request": {createRun(1, "pull_request", "outsider/repo1", "outsider", "maintainer", recent)},
+			},
+			expectedCount:  1,
+			expectApprover: "maintainer",
+		},
+		{
+			name: "Approved pull_request_target run has secrets",
+			runs: map[string][]*common.WorkflowRun{
+				"testorg/repo1/pull_request_target": {createRun(1, "pull_request_target", "outsider/repo1", "outsider", "maintainer", recent)},
+			},
+			expectedCount:  1,
+			expectSecrets:  true,
+			expectApprover: "maintainer",
+		},
+		{
+			name: "Fork run started by its contributor",
+			runs: map[string][]*common.WorkflowRun{
+				"testorg/repo1/pull_request": {createRun(1, "pull_request", "outsider/repo1", "outsider", "outsider", recent)},
+			},
+		},
+		{
+			name: "Run of a branch of the repository",
+			runs: map[string][]*common.WorkflowRun{
+				"testorg/repo1/pull_request": {createRun(1, "pull_request", "TestOrg/Repo1", "alice", "bob", recent)},
+			},
+		},
+		{
+			name: "Approved run outside the window",
+			runs: map[string][]*common.WorkflowRun{
+				"testorg/repo1/pull_request": {createRun(1, "pull_request", "outsider/repo1", "outsider", "maintainer", recent.Add(-48*time.Hour))},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &mockgithub.MockGitHubClient{
+				MockWorkflowRuns: tc.runs,
+				MockSecretNames:  map[string][]string{"testorg/repo1": {"DEPLOY_KEY", "NPM_TOKEN"}},
+			}
+
+			checker := forkruns.NewForkRunsChecker(mockClient, newConfig())
+			approvals, err := checker.CheckRepository(context.Background(), "testorg/repo1")
+			if err != nil {
+				t.Fatalf("Did not expect an error but got: %v", err)
+			}
+			if len(approvals) != tc.expectedCount {
+				t.Fatalf("Expected %d approvals, got %+v", tc.expectedCount, approvals)
+			}
+
+			if tc.expectedCount > 0 {
+				if approvals[0].ApprovedBy != tc.expectApprover || approvals[0].Contributor != "outsider" {
+					t.Errorf("Unexpected approval %+v", approvals[0])
+				}
+				if (len(approvals[0].Secrets) > 0) != tc.expectSecrets {
+					t.Errorf("Expected secrets %v, got %v", tc.expectSecrets, approvals[0].Secrets)
+				}
+			}
+			// Secrets are only listed for runs that get them
+			if (mockClient.ListActionsSecretNamesCalls > 0) != tc.expectSecrets {
+				t.Errorf("Expected secrets listed %v, got %d calls", tc.expectSecrets, mockClient.ListActionsSecretNamesCalls)
+			}
+		})
+	}
+}
+
+func TestFindings(t *testing.T) {
+	mockClient := &mockgithub.MockGitHubClient{
+		MockWorkflowRuns: map[string][]*common.WorkflowRun{
+			"testorg/repo1/pull_request_target": {createRun(7, "pull_request_target", "outsider/repo1", "outsider", "maintainer", time.Now())},
+		},
+		MockSecretNames: map[string][]string{"testorg/repo1": {"DEPLOY_KEY"}},
+	}
+
+	approvals, err := forkruns.NewForkRunsChecker(mockClient, newConfig()).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	list := forkruns.Findings(approvals)
+	if len(list) != 1 {
+		t.Fatalf("Expected 1 finding, got %d", len(list))
+	}
+	if list[0].Subject != "run #7" || !strings.Contains(list[0].Summary, "with access to secrets DEPLOY_KEY") {
+		t.Errorf("Unexpected finding %+v", list[0])
+	}
+}
+
+func TestListError(t *testing.T) {
+	mockClient := &mockgithub.MockGitHubClient{MockWorkflowRunsErr: errors.New("API error")}
+
+	_, err := forkruns.NewForkRunsChecker(mockClient, newConfig()).CheckRepository(context.Background(), "testorg/repo1")
+	if err == nil {
+		t.Error("Expected an error but got nil")
+	}
+}
@@ -0,0 +1,242 @@
+package ghas
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	"github.com/google/go-github/v45/github"
+)
+
+// Organization is the GitHub Advanced Security seat utilization of an organization
+type Organization struct {
+	Name         string
+	Committers   int          // Active committers consuming seats
+	Purchased    int          // Purchased seats, 0 when GitHub does not tell
+	Repositories []Repository // Repositories consuming seats, most committers first
+}
+
+// Repository is a repository consuming GitHub Advanced Security seats, with the features it has turned on
+type Repository struct {
+	Name           string
+	Private        bool
+	Committers     int // Active committers consuming seats
+	SecretScanning bool
+	CodeScanning   bool // Code scanning analyzed the repository at least once
+	URL            string
+}
+
+// Unused reports whether the repository consumes seats without making use of them: a private repository
+// with secret scanning or code scanning turned off. Public repositories get both for free
+func (r Repository) Unused() bool {
+	return r.Private && r.Committers > 0 && (!r.SecretScanning || !r.CodeScanning)
+}
+
+// Missing returns the GitHub Advanced Security features the repository has not turned on
+func (r Repository) Missing() []string {
+	var missing []string
+	if !r.SecretScanning {
+		missing = append(missing, "secret scanning")
+	}
+	if !r.CodeScanning {
+		missing = append(missing, "code scanning")
+	}
+	return missing
+}
+
+// Summary describes the seats the repository consumes and the features it leaves unused
+func (r Repository) Summary() string {
+	return fmt.Sprintf("private repository consuming %d GHAS seats without %s", r.Committers, strings.Join(r.Missing(), " or "))
+}
+
+// Unused returns the repositories of the organization consuming seats without making use of them
+func (o Organization) Unused() []Repository {
+	var unused []Repository
+	for _, r := range o.Repositories {
+		if r.Unused() {
+			unused = append(unused, r)
+		}
+	}
+	return unused
+}
+
+// Checker is a service that reports the GitHub Advanced Security seat utilization of organizations
+type Checker struct {
+	client common.GitHubClientInterface
+	config *config.Config
+}
+
+// NewGHASChecker creates a new Checker
+func NewGHASChecker(client common.GitHubClientInterface, config *config.Config) *Checker {
+	return &Checker{
+		client: client,
+		config: config,
+	}
+}
+
+// Run reports the seat utilization of all configured organizations
+func (c *Checker) Run(ctx context.Context) ([]Organization, error) {
+	orgs := make([]Organization, 0)
+
+	for _, org := range c.config.Monitors.GHAS.Organizations {
+		if common.SkipIfStopped(ctx, "org:"+org) {
+			continue
+		}
+		result, err := c.CheckOrganization(ctx, org)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, "org:"+org, err) {
+				continue
+			}
+			log.Printf("Error checking GHAS utilization for organization %s: %v", org, err)
+			continue
+		}
+		orgs = append(orgs, *result)
+	}
+
+	return orgs, nil
+}
+
+// CheckOrganization reports the seat utilization of an organization and which GitHub Advanced Security
+// features each repository consuming seats has turned on
+func (c *Checker) CheckOrganization(ctx context.Context, org string) (*Organization, error) {
+	log.Printf("Checking GitHub Advanced Security utilization in %s organization", org)
+
+	usage, err := c.client.GetAdvancedSecurityCommitters(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Organization{Name: org, Committers: usage.TotalCommitters, Purchased: usage.PurchasedCommitters}
+	for _, committers := range usage.Repositories {
+		repo, err := c.checkRepository(ctx, committers)
+		if err != nil {
+			log.Printf("Error checking GHAS features of repository %s: %v", committers.Name, err)
+			continue
+		}
+		if repo != nil {
+			result.Repositories = append(result.Repositories, *repo)
+		}
+	}
+
+	sort.SliceStable(result.Repositories, func(i, j int) bool {
+		if result.Repositories[i].Committers != result.Repositories[j].Committers {
+			return result.Repositories[i].Committers > result.Repositories[j].Committers
+		}
+		return result.Repositories[i].Name < result.Repositories[j].Name
+	})
+	return result, nil
+}
+
+// checkRepository looks up which features a repository consuming seats has turned on,
+// nil when the repository is left out by repo_filters
+func (c *Checker) checkRepository(ctx context.Context, committers *common.AdvancedSecurityRepoCommitters) (*Repository, error) {
+	owner, name, ok := common.ParseRepository(committers.Name)
+	if !ok {
+		return nil, fmt.Errorf("invalid repository name %q", committers.Name)
+	}
+
+	repo, err := c.client.GetRepository(ctx, owner, name)
+	if err != nil {
+		return nil, err
+	}
+	if repo == nil {
+		return nil, fmt.Errorf("repository %s not found", committers.Name)
+	}
+	if kept, _ := common.FilterRepositories(ctx, []*github.Repository{repo}, c.config.RepoFilters); len(kept) == 0 {
+		return nil, nil
+	}
+
+	codeScanning, err := c.client.HasCodeScanningAnalyses(ctx, owner, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Repository{
+		Name:           repo.GetFullName(),
+		Private:        repo.GetPrivate(),
+		Committers:     committers.Committers,
+		SecretScanning: repo.GetSecurityAndAnalysis().GetSecretScanning().GetStatus() == "enabled",
+		CodeScanning:   codeScanning,
+		URL:            repo.GetHTMLURL(),
+	}, nil
+}
+
+// Findings converts private repositories consuming seats without secret or code scanning into findings
+func Findings(orgs []Organization) []findings.Finding {
+	list := make([]findings.Finding, 0)
+	for _, o := range orgs {
+		for _, r := range o.Unused() {
+			list = append(list, findings.Finding{
+				Monitor:    "ghas_utilization",
+				Repository: r.Name,
+				Subject:    "ghas_seats",
+				Summary:    r.Summary(),
+				URL:        r.URL + "/settings/security_analysis",
+			})
+		}
+	}
+	return list
+}
+
+// WriteResultsMarkdown writes the seat utilization of each organization in a code block format
+// suitable for Slack notifications
+func WriteResultsMarkdown(w io.Writer, orgs []Organization) {
+	if len(orgs) == 0 {
+		return // No results to display
+	}
+
+	// Print header for the seat utilization
+	fmt.Fprintln(w, "## :shield: GitHub Advanced Security Utilization")
+	for _, o := range orgs {
+		seats := fmt.Sprintf("%d seats consumed", o.Committers)
+		if o.Purchased > 0 {
+			seats = fmt.Sprintf("%d of %d purchased seats consumed", o.Committers, o.Purchased)
+		}
+		fmt.Fprintf(w, "%s: %s by %d repositories, %d private ones without secret or code scanning.\n\n",
+			o.Name, seats, len(o.Repositories), len(o.Unused()))
+		if len(o.Repositories) == 0 {
+			continue
+		}
+
+		// Start code block
+		fmt.Fprintln(w, "```")
+		// Create fixed-width headers with proper spacing for code block
+		fmt.Fprintln(w, "Repository                Seats  Secret scanning  Code scanning")
+		fmt.Fprintln(w, "---------------------------------------------------------------------")
+
+		// Print each repository in a fixed-width format for code blocks, most seats first
+		for _, r := range o.Repositories {
+			// Format repository name with padding
+			repoStr := r.Name
+			if len(repoStr) > 24 {
+				repoStr = repoStr[:21] + "..."
+			} else {
+				repoStr = fmt.Sprintf("%-24s", repoStr)
+			}
+
+			flag := ""
+			if r.Unused() {
+				flag = "  <- unused seats"
+			}
+			fmt.Fprintf(w, "%s  %5d  %-15s  %-13s%s\n", repoStr, r.Committers, status(r.SecretScanning), status(r.CodeScanning), flag)
+		}
+
+		// End code block
+		fmt.Fprintln(w, "```")
+		fmt.Fprintln(w, "")
+	}
+}
+
+// status describes whether a feature is turned on
+func status(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+	return "off"
+}
@@ -0,0 +1,176 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v45/github"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	mockgithub "github.com/anupsv/git-monitoring/pkg/tools/common/test"
+	"github.com/anupsv/git-monitoring/pkg/tools/ghas"
+)
+
+// createRepo creates a repository of testorg with or without secret scanning
+func createRepo(name string, private, secretScanning bool) *github.Repository {
+	status := "disabled"
+	if secretScanning {
+		status = "enabled"
+	}
+	return &github.Repository{
+		FullName: github.String("testorg/" + name),
+		Private:  github.Bool(private),
+		HTMLURL:  github.String("https://github.com/testorg/" + name),
+		SecurityAndAnalysis: &github.SecurityAndAnalysis{
+			SecretScanning: &github.SecretScanning{Status: github.String(status)},
+		},
+	}
+}
+
+// newConfig creates a configuration reporting the utilization of testorg
+func newConfig() *config.Config {
+	return &config.Config{
+		Monitors: config.MonitorsConfig{
+			GHAS: config.GHASConfig{
+				Enabled:       true,
+				Organizations: []string{"testorg"},
+			},
+		},
+	}
+}
+
+// newMockClient creates a client with four repositories consuming seats of testorg
+func newMockClient() *mockgithub.MockGitHubClient {
+	return &mockgithub.MockGitHubClient{
+		MockGHASCommitters: map[string]*common.AdvancedSecurityCommitters{
+			"testorg": {
+				TotalCommitters:     14,
+				PurchasedCommitters: 20,
+				Repositories: []*common.AdvancedSecurityRepoCommitters{
+					{Name: "testorg/scanned", Committers: 3},
+					{Name: "testorg/no-code-scanning", Committers: 8},
+					{Name: "testorg/nothing", Committers: 2},
+					{Name: "testorg/public", Committers: 1},
+				},
+			},
+		},
+		MockRepository: map[string]*github.Repository{
+			"testorg/scanned":          createRepo("scanned", true, true),
+			"testorg/no-code-scanning": createRepo("no-code-scanning", true, true),
+			"testorg/nothing":          createRepo("nothing", true, false),
+			"testorg/public":           createRepo("public", false, false),
+		},
+		MockCodeScanningRepos: map[string]bool{"testorg/scanned": true},
+	}
+}
+
+func TestRunReportsUtilization(t *testing.T) {
+	mockClient := newMockClient()
+	checker := ghas.NewGHASChecker(mockClient, newConfig())
+
+	orgs, err := checker.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run returned an error: %v", err)
+	}
+	if len(orgs) != 1 {
+		t.Fatalf("Expected 1 organization, got %d", len(orgs))
+	}
+
+	org := orgs[0]
+	if org.Committers != 14 || org.Purchased != 20 {
+		t.Errorf("Expected 14 of 20 seats consumed, got %d of %d", org.Committers, org.Purchased)
+	}
+
+	var names []string
+	for _, r := range org.Repositories {
+		names = append(names, r.Name)
+	}
+	expected := "testorg/no-code-scanning,testorg/scanned,testorg/nothing,testorg/public"
+	if strings.Join(names, ",") != expected {
+		t.Errorf("Expected repositories ordered by seats %s, got %v", expected, names)
+	}
+
+	// Public repositories get both features for free, so only private ones waste seats
+	unused := org.Unused()
+	if len(unused) != 2 {
+		t.Fatalf("Expected 2 repositories with unused seats, got %+v", unused)
+	}
+	if unused[0].Summary() != "private repository consuming 8 GHAS seats without code scanning" {
+		t.Errorf("Unexpected summary: %s", unused[0].Summary())
+	}
+	if unused[1].Summary() != "private repository consuming 2 GHAS seats without secret scanning or code scanning" {
+		t.Errorf("Unexpected summary: %s", unused[1].Summary())
+	}
+
+	list := ghas.Findings(orgs)
+	if len(list) != 2 {
+		t.Fatalf("Expected 2 findings, got %d", len(list))
+	}
+	if list[0].Monitor != "ghas_utilization" || list[0].URL != "https://github.com/testorg/no-code-scanning/settings/security_analysis" {
+		t.Errorf("Unexpected finding: %+v", list[0])
+	}
+}
+
+func TestRunSkipsOrganizationWithoutAccess(t *testing.T) {
+	cfg := newConfig()
+	cfg.Monitors.GHAS.Organizations = []string{"otherorg", "testorg"}
+	checker := ghas.NewGHASChecker(newMockClient(), cfg)
+
+	orgs, err := checker.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run returned an error: %v", err)
+	}
+	if len(orgs) != 1 || orgs[0].Name != "testorg" {
+		t.Errorf("Expected only testorg to be reported, got %+v", orgs)
+	}
+}
+
+func TestRepoFiltersApply(t *testing.T) {
+	cfg := newConfig()
+	cfg.RepoFilters = config.Filters{Exclusions: []string{"testorg/nothing"}}
+	mockClient := newMockClient()
+	checker := ghas.NewGHASChecker(mockClient, cfg)
+
+	org, err := checker.CheckOrganization(context.Background(), "testorg")
+	if err != nil {
+		t.Fatalf("CheckOrganization returned an error: %v", err)
+	}
+	if len(org.Repositories) != 3 {
+		t.Errorf("Expected the excluded repository to be left out, got %+v", org.Repositories)
+	}
+	if mockClient.HasCodeScanningAnalysesCalls != 3 {
+		t.Errorf("Expected code scanning to be looked up for 3 repositories, got %d", mockClient.HasCodeScanningAnalysesCalls)
+	}
+}
+
+func TestWriteResultsMarkdown(t *testing.T) {
+	checker := ghas.NewGHASChecker(newMockClient(), newConfig())
+	orgs, err := checker.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run returned an error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	ghas.WriteResultsMarkdown(&buf, orgs)
+	output := buf.String()
+
+	for _, want := range []string{
+		"GitHub Advanced Security Utilization",
+		"testorg: 14 of 20 purchased seats consumed by 4 repositories, 2 private ones without secret or code scanning",
+		"testorg/nothing",
+		"<- unused seats",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	buf.Reset()
+	ghas.WriteResultsMarkdown(&buf, nil)
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output without organizations, got %q", buf.String())
+	}
+}
@@ -0,0 +1,271 @@
+package issuehygiene
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	"github.com/google/go-github/v45/github"
+)
+
+const (
+	// DefaultTriageSLA is the default time external issues can stay untriaged
+	DefaultTriageSLA = 7 * 24 * time.Hour
+)
+
+// Problems public repositories can have with their issues
+const (
+	ProblemIssuesDisabled = "issues_disabled" // The community cannot report issues
+	ProblemUntriaged      = "untriaged"       // External issues left untriaged for longer than the SLA
+)
+
+// insiderAssociations are the author associations of issue authors who belong to the repository
+var insiderAssociations = map[string]bool{
+	"OWNER":        true,
+	"MEMBER":       true,
+	"COLLABORATOR": true,
+}
+
+// Repository is a public repository with an issue hygiene problem
+type Repository struct {
+	Name    string
+	Problem string // e.g. ProblemUntriaged
+
+	// Untriaged external issues older than the SLA and the oldest of them, for ProblemUntriaged
+	Untriaged   int
+	OldestIssue int
+	OldestAge   time.Duration
+
+	URL string
+}
+
+// Checker is a service that reports public repositories neglecting their community's issues
+type Checker struct {
+	client    common.GitHubClientInterface
+	triageSLA time.Duration
+	config    *config.Config
+}
+
+// NewIssueHygieneChecker creates a new Checker
+func NewIssueHygieneChecker(client common.GitHubClientInterface, config *config.Config) *Checker {
+	triageSLA := DefaultTriageSLA
+	if config.Monitors.IssueHygiene.TriageSLA.Duration > 0 {
+		triageSLA = config.Monitors.IssueHygiene.TriageSLA.Duration
+	}
+
+	return &Checker{
+		client:    client,
+		triageSLA: triageSLA,
+		config:    config,
+	}
+}
+
+// Run checks all configured organizations and repositories
+func (c *Checker) Run(ctx context.Context) ([]Repository, error) {
+	allRepos := make([]Repository, 0)
+
+	for _, org := range c.config.Monitors.IssueHygiene.Organizations {
+		if common.SkipIfStopped(ctx, "org:"+org) {
+			continue
+		}
+		repos, err := c.CheckOrganization(ctx, org)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, "org:"+org, err) {
+				continue
+			}
+			log.Printf("Error checking issue hygiene for organization %s: %v", org, err)
+			continue
+		}
+		allRepos = append(allRepos, repos...)
+	}
+
+	for _, repository := range c.config.Monitors.IssueHygiene.Repositories {
+		if common.SkipIfStopped(ctx, repository) {
+			continue
+		}
+		repos, err := c.CheckRepository(ctx, repository)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, repository, err) {
+				continue
+			}
+			log.Printf("Error checking issue hygiene for repository %s: %v", repository, err)
+			continue
+		}
+		allRepos = append(allRepos, repos...)
+	}
+
+	return allRepos, nil
+}
+
+// CheckOrganization checks the public repositories of an organization
+// Archived repositories no longer take issues and are skipped
+func (c *Checker) CheckOrganization(ctx context.Context, org string) ([]Repository, error) {
+	log.Printf("Checking issue hygiene of public repositories in %s organization", org)
+
+	repos, err := c.client.ListOrganizationRepositories(ctx, org, "public-only")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization repositories: %w", err)
+	}
+	repos, filtered := common.FilterRepositories(ctx, repos, c.config.RepoFilters)
+	if filtered > 0 {
+		log.Printf("Filtered out %d repositories of %s not matching repo_filters", filtered, org)
+	}
+
+	allRepos := make([]Repository, 0)
+	for _, repo := range repos {
+		found, err := c.checkRepository(ctx, repo)
+		if err != nil {
+			log.Printf("Error checking issue hygiene for repository %s: %v", repo.GetFullName(), err)
+			continue
+		}
+		allRepos = append(allRepos, found...)
+	}
+
+	return allRepos, nil
+}
+
+// CheckRepository checks a single repository, which is skipped unless it is public
+func (c *Checker) CheckRepository(ctx context.Context, repository string) ([]Repository, error) {
+	owner, repo, ok := common.ParseRepository(repository)
+	if !ok {
+		return nil, fmt.Errorf("invalid repository format, expected 'owner/repo'")
+	}
+
+	repoInfo, err := c.client.GetRepository(ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository: %w", err)
+	}
+	if repoInfo == nil {
+		return nil, fmt.Errorf("repository %s not found", repository)
+	}
+
+	return c.checkRepository(ctx, repoInfo)
+}
+
+// checkRepository reports whether a public repository has issues disabled or untriaged external issues
+func (c *Checker) checkRepository(ctx context.Context, repo *github.Repository) ([]Repository, error) {
+	if repo.GetPrivate() || repo.GetArchived() {
+		return nil, nil
+	}
+
+	name := repo.GetFullName()
+	if !repo.GetHasIssues() {
+		return []Repository{{Name: name, Problem: ProblemIssuesDisabled, URL: repo.GetHTMLURL() + "/settings"}}, nil
+	}
+
+	owner, repoName, ok := common.ParseRepository(name)
+	if !ok {
+		return nil, fmt.Errorf("invalid repository name %q", name)
+	}
+
+	now := time.Now()
+	issues, err := c.client.ListOpenIssues(ctx, owner, repoName, now.Add(-c.triageSLA))
+	if err != nil {
+		return nil, err
+	}
+
+	result := Repository{Name: name, Problem: ProblemUntriaged, URL: repo.GetHTMLURL() + "/issues"}
+	for _, issue := range issues {
+		if insiderAssociations[issue.GetAuthorAssociation()] || c.triaged(issue) {
+			continue
+		}
+		if result.Untriaged == 0 {
+			result.OldestIssue = issue.GetNumber()
+			result.OldestAge = now.Sub(issue.GetCreatedAt())
+		}
+		result.Untriaged++
+	}
+	if result.Untriaged == 0 {
+		return nil, nil
+	}
+
+	return []Repository{result}, nil
+}
+
+// triaged reports whether an issue was triaged: assigned, or labeled with a label other than the triage labels
+func (c *Checker) triaged(issue *github.Issue) bool {
+	if len(issue.Assignees) > 0 || issue.GetAssignee() != nil {
+		return true
+	}
+
+	for _, label := range issue.Labels {
+		pending := false
+		for _, triage := range c.config.Monitors.IssueHygiene.TriageLabels {
+			if strings.EqualFold(label.GetName(), triage) {
+				pending = true
+				break
+			}
+		}
+		if !pending {
+			return true
+		}
+	}
+	return false
+}
+
+// Summary describes the problem of a repository
+func (r Repository) Summary() string {
+	if r.Problem == ProblemIssuesDisabled {
+		return "public repository with issues disabled"
+	}
+	return fmt.Sprintf("%d external issues untriaged, the oldest #%d for %d days",
+		r.Untriaged, r.OldestIssue, int(r.OldestAge.Hours()/24))
+}
+
+// Findings converts issue hygiene problems into findings
+// They are about community responsiveness rather than security, so they are low severity
+func Findings(repos []Repository) []findings.Finding {
+	list := make([]findings.Finding, 0, len(repos))
+	for _, r := range repos {
+		list = append(list, findings.Finding{
+			Monitor:    "issue_hygiene",
+			Repository: r.Name,
+			Subject:    r.Problem,
+			Summary:    r.Summary(),
+			URL:        r.URL,
+			Severity:   findings.SeverityLow,
+		})
+	}
+	return list
+}
+
+// WriteResultsMarkdown writes issue hygiene problems in a code block format
+// suitable for Slack notifications
+func WriteResultsMarkdown(w io.Writer, repos []Repository) {
+	if len(repos) == 0 {
+		return // No results to display
+	}
+
+	// Print header for issue hygiene problems
+	fmt.Fprintln(w, "## :speech_balloon: Issue Hygiene")
+	fmt.Fprintf(w, "Found %d public repositories not responding to their community.\n\n", len(repos))
+
+	// Start code block
+	fmt.Fprintln(w, "```")
+	// Create fixed-width headers with proper spacing for code block
+	fmt.Fprintln(w, "Repository                Problem")
+	fmt.Fprintln(w, "---------------------------------------------------------------------")
+
+	// Print each repository in a fixed-width format for code blocks
+	for _, r := range repos {
+		// Format repository name with padding
+		repoStr := r.Name
+		if len(repoStr) > 24 {
+			repoStr = repoStr[:21] + "..."
+		} else {
+			repoStr = fmt.Sprintf("%-24s", repoStr)
+		}
+
+		fmt.Fprintf(w, "%s  %s\n", repoStr, r.Summary())
+	}
+
+	// End code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
@@ -0,0 +1,105 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	mockgithub "github.com/anupsv/git-monitoring/pkg/tools/common/test"
+	"github.com/anupsv/git-monitoring/pkg/tools/issuehygiene"
+)
+
+// createRepo creates a repository of testorg
+func createRepo(name string, private, hasIssues bool) *github.Repository {
+	return &github.Repository{
+		FullName:  github.String("testorg/" + name),
+		HTMLURL:   github.String("https://github.com/testorg/" + name),
+		Private:   github.Bool(private),
+		HasIssues: github.Bool(hasIssues),
+	}
+}
+
+// createIssue creates an open issue opened the given number of days ago
+func createIssue(number int, association string, daysAgo int, labels ...string) *github.Issue {
+	createdAt := time.Now().Add(-time.Duration(daysAgo) * 24 * time.Hour)
+	issue := &github.Issue{
+		Number:            github.Int(number),
+		AuthorAssociation: github.String(association),
+		CreatedAt:         &createdAt,
+	}
+	for _, label := range labels {
+		issue.Labels = append(issue.Labels, &github.Label{Name: github.String(label)})
+	}
+	return issue
+}
+
+func TestCheckOrganization(t *testing.T) {
+	assigned := createIssue(5, "NONE", 30)
+	assigned.Assignees = []*github.User{{Login: github.String("maintainer")}}
+
+	mockClient := &mockgithub.MockGitHubClient{
+		MockOrgRepositories: []*github.Repository{
+			createRepo("no-issues", false, false),
+			createRepo("neglected", false, true),
+			createRepo("tidy", false, true),
+			createRepo("internal", true, false),
+		},
+		MockOpenIssues: map[string][]*github.Issue{
+			"testorg/neglected": {
+				createIssue(1, "NONE", 20),
+				createIssue(2, "CONTRIBUTOR", 10, "needs-triage"),
+				createIssue(3, "MEMBER", 30),
+				createIssue(4, "NONE", 2),
+				assigned,
+			},
+			"testorg/tidy": {
+				createIssue(1, "NONE", 20, "bug", "needs-triage"),
+			},
+		},
+	}
+
+	cfg := &config.Config{
+		Monitors: config.MonitorsConfig{
+			IssueHygiene: config.IssueHygieneConfig{
+				Enabled:       true,
+				Organizations: []string{"testorg"},
+				TriageSLA:     config.Hours(7 * 24),
+				TriageLabels:  []string{"Needs-Triage"},
+			},
+		},
+	}
+
+	checker := issuehygiene.NewIssueHygieneChecker(mockClient, cfg)
+	repos, err := checker.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	if len(repos) != 2 {
+		t.Fatalf("Expected 2 repositories, got %+v", repos)
+	}
+	if repos[0].Name != "testorg/no-issues" || repos[0].Problem != issuehygiene.ProblemIssuesDisabled {
+		t.Errorf("Unexpected repository %+v", repos[0])
+	}
+	if repos[1].Name != "testorg/neglected" || repos[1].Untriaged != 2 || repos[1].OldestIssue != 1 {
+		t.Errorf("Unexpected repository %+v", repos[1])
+	}
+	if summary := repos[1].Summary(); summary != "2 external issues untriaged, the oldest #1 for 20 days" {
+		t.Errorf("Unexpected summary %q", summary)
+	}
+
+	// Repositories with issues disabled need no further requests
+	if mockClient.ListOpenIssuesCalls != 2 {
+		t.Errorf("Expected issues to be listed for 2 repositories, got %d calls", mockClient.ListOpenIssuesCalls)
+	}
+
+	for _, f := range issuehygiene.Findings(repos) {
+		if f.Severity != findings.SeverityLow {
+			t.Errorf("Expected low severity findings, got %+v", f)
+		}
+	}
+}
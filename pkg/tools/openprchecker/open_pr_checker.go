@@ -0,0 +1,251 @@
+package openprchecker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	"github.com/google/go-github/v45/github"
+)
+
+const (
+	// DefaultMinAge is the default time an open PR can wait for its first review
+	DefaultMinAge = 24 * time.Hour
+)
+
+// PullRequest is an open pull request that nobody reviewed yet
+type PullRequest struct {
+	Repository string
+	Number     int
+	Title      string
+	Author     string
+	Draft      bool
+	Age        time.Duration // Time since the PR was opened
+	URL        string
+}
+
+// Summary describes how long the PR has been waiting for a review
+func (p PullRequest) Summary() string {
+	return fmt.Sprintf("open for %s without any review: %s (by %s)", age(p.Age), p.Title, p.Author)
+}
+
+// Checker is a service that reports open pull requests nobody reviewed
+type Checker struct {
+	client common.GitHubClientInterface
+	minAge time.Duration
+	config *config.Config
+}
+
+// NewOpenPRChecker creates a new Checker
+func NewOpenPRChecker(client common.GitHubClientInterface, config *config.Config) *Checker {
+	minAge := DefaultMinAge
+	if config.Monitors.OpenPRChecker.MinAge.Duration > 0 {
+		minAge = config.Monitors.OpenPRChecker.MinAge.Duration
+	}
+
+	return &Checker{
+		client: client,
+		minAge: minAge,
+		config: config,
+	}
+}
+
+// Run checks all configured organizations and repositories
+func (c *Checker) Run(ctx context.Context) ([]PullRequest, error) {
+	allPRs := make([]PullRequest, 0)
+
+	for _, org := range c.config.Monitors.OpenPRChecker.Organizations {
+		if common.SkipIfStopped(ctx, "org:"+org) {
+			continue
+		}
+		prs, err := c.CheckOrganization(ctx, org)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, "org:"+org, err) {
+				continue
+			}
+			log.Printf("Error checking open PRs for organization %s: %v", org, err)
+			continue
+		}
+		allPRs = append(allPRs, prs...)
+	}
+
+	for _, repository := range c.config.Monitors.OpenPRChecker.Repositories {
+		if common.SkipIfStopped(ctx, repository) {
+			continue
+		}
+		prs, err := c.CheckRepository(ctx, repository)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, repository, err) {
+				continue
+			}
+			log.Printf("Error checking open PRs for repository %s: %v", repository, err)
+			continue
+		}
+		allPRs = append(allPRs, prs...)
+	}
+
+	return allPRs, nil
+}
+
+// CheckOrganization checks the repositories of an organization
+// Archived repositories take no pull requests and are skipped
+func (c *Checker) CheckOrganization(ctx context.Context, org string) ([]PullRequest, error) {
+	log.Printf("Checking open PRs without reviews in %s organization", org)
+
+	repos, err := c.client.ListOrganizationRepositories(ctx, org, "all")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization repositories: %w", err)
+	}
+	repos, filtered := common.FilterRepositories(ctx, repos, c.config.RepoFilters)
+	if filtered > 0 {
+		log.Printf("Filtered out %d repositories of %s not matching repo_filters", filtered, org)
+	}
+
+	allPRs := make([]PullRequest, 0)
+	for _, repo := range repos {
+		if repo.GetArchived() {
+			continue
+		}
+		prs, err := c.CheckRepository(ctx, repo.GetFullName())
+		if err != nil {
+			log.Printf("Error checking open PRs for repository %s: %v", repo.GetFullName(), err)
+			continue
+		}
+		allPRs = append(allPRs, prs...)
+	}
+
+	return allPRs, nil
+}
+
+// CheckRepository reports the open PRs of a repository older than the minimum age without any review
+// PRs are listed oldest first, so listing stops at the first PR younger than the minimum age
+func (c *Checker) CheckRepository(ctx context.Context, repository string) ([]PullRequest, error) {
+	owner, repo, ok := common.ParseRepository(repository)
+	if !ok {
+		return nil, fmt.Errorf("invalid repository format, expected 'owner/repo'")
+	}
+
+	now := time.Now()
+	opts := &github.PullRequestListOptions{
+		State:       "open",
+		Sort:        "created",
+		Direction:   "asc",
+		ListOptions: github.ListOptions{PerPage: c.config.Monitors.OpenPRChecker.Pagination.PerPage()},
+	}
+	maxPages := c.config.Monitors.OpenPRChecker.Pagination.MaxPages
+
+	prs := make([]PullRequest, 0)
+	for fetched := 1; ; fetched++ {
+		page, resp, err := c.client.GetPullRequests(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error getting pull requests: %v", err)
+		}
+
+		for _, pr := range page {
+			opened := now.Sub(pr.GetCreatedAt())
+			if opened < c.minAge {
+				return prs, nil
+			}
+			if pr.GetDraft() && !c.config.Monitors.OpenPRChecker.IncludeDrafts {
+				continue
+			}
+
+			reviews, _, err := c.client.ListPullRequestReviews(ctx, owner, repo, pr.GetNumber(), &github.ListOptions{PerPage: 1})
+			if err != nil {
+				return nil, fmt.Errorf("error getting reviews of PR #%d: %v", pr.GetNumber(), err)
+			}
+			if len(reviews) > 0 {
+				continue
+			}
+
+			prs = append(prs, PullRequest{
+				Repository: owner + "/" + repo,
+				Number:     pr.GetNumber(),
+				Title:      pr.GetTitle(),
+				Author:     pr.GetUser().GetLogin(),
+				Draft:      pr.GetDraft(),
+				Age:        opened,
+				URL:        pr.GetHTMLURL(),
+			})
+		}
+
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		// Newer PRs are left unchecked beyond the page cap, they waited the least for a review
+		if maxPages > 0 && fetched >= maxPages {
+			log.Printf("Reached the limit of %d pages for %s, open PRs on later pages are not checked", maxPages, repository)
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return prs, nil
+}
+
+// age formats how long a PR has been open, in hours below two days and in days beyond
+func age(d time.Duration) string {
+	if d < 48*time.Hour {
+		return fmt.Sprintf("%d hours", int(d.Hours()))
+	}
+	return fmt.Sprintf("%d days", int(d.Hours()/24))
+}
+
+// Findings converts open PRs without reviews into findings
+func Findings(prs []PullRequest) []findings.Finding {
+	list := make([]findings.Finding, 0, len(prs))
+	for _, p := range prs {
+		list = append(list, findings.Finding{
+			Monitor:    "open_pr_checker",
+			Repository: p.Repository,
+			Subject:    fmt.Sprintf("#%d", p.Number),
+			Summary:    p.Summary(),
+			URL:        p.URL,
+		})
+	}
+	return list
+}
+
+// WriteResultsMarkdown writes open PRs without reviews in a code block format
+// suitable for Slack notifications
+func WriteResultsMarkdown(w io.Writer, prs []PullRequest) {
+	if len(prs) == 0 {
+		return // No results to display
+	}
+
+	// Print header for open PRs without reviews
+	fmt.Fprintln(w, "## :hourglass: Open PRs Without Reviews")
+	fmt.Fprintf(w, "Found %d open PRs nobody reviewed yet.\n\n", len(prs))
+
+	// Start code block
+	fmt.Fprintln(w, "```")
+	// Create fixed-width headers with proper spacing for code block
+	fmt.Fprintln(w, "Repository                PR       Open for   Author            Title")
+	fmt.Fprintln(w, "----------------------------------------------------------------------------------")
+
+	// Print each PR in a fixed-width format for code blocks
+	for _, p := range prs {
+		// Format repository name with padding
+		repoStr := p.Repository
+		if len(repoStr) > 24 {
+			repoStr = repoStr[:21] + "..."
+		} else {
+			repoStr = fmt.Sprintf("%-24s", repoStr)
+		}
+
+		title := p.Title
+		if p.Draft {
+			title = "[draft] " + title
+		}
+		fmt.Fprintf(w, "%s  %-7s  %-9s  %-16s  %s\n", repoStr, fmt.Sprintf("#%d", p.Number), age(p.Age), p.Author, title)
+	}
+
+	// End code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
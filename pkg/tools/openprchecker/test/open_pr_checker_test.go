@@ -0,0 +1,209 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	mockgithub "github.com/anupsv/git-monitoring/pkg/tools/common/test"
+	"github.com/anupsv/git-monitoring/pkg/tools/openprchecker"
+)
+
+// createPR creates an open PR opened the given number of hours ago
+func createPR(number int, hoursAgo int, draft bool) *github.PullRequest {
+	createdAt := time.Now().Add(-time.Duration(hoursAgo) * time.Hour)
+	return &github.PullRequest{
+		Number:    github.Int(number),
+		Title:     github.String(fmt.Sprintf("Change %d", number)),
+		User:      &github.User{Login: github.String("dev")},
+		Draft:     github.Bool(draft),
+		CreatedAt: &createdAt,
+		HTMLURL:   github.String(fmt.Sprintf("https://github.com/testorg/api/pull/%d", number)),
+	}
+}
+
+// newConfig creates a configuration checking testorg/api
+func newConfig() *config.Config {
+	return &config.Config{
+		Monitors: config.MonitorsConfig{
+			OpenPRChecker: config.OpenPRCheckerConfig{
+				Enabled:      true,
+				Repositories: []string{"testorg/api"},
+				MinAge:       config.Hours(24),
+			},
+		},
+	}
+}
+
+// newMockClient creates a client whose testorg/api has open PRs oldest first, of which #2 was reviewed
+func newMockClient() *mockgithub.MockGitHubClient {
+	return &mockgithub.MockGitHubClient{
+		MockPullRequests: []*github.PullRequest{
+			createPR(1, 100, false),
+			createPR(2, 72, false),
+			createPR(3, 48, true),
+			createPR(4, 30, false),
+			createPR(5, 2, false),
+			createPR(6, 1, false),
+		},
+		ListPullRequestReviewsFunc: func(_ context.Context, _, _ string, number int, _ *github.ListOptions) ([]*github.PullRequestReview, *github.Response, error) {
+			if number == 2 {
+				return []*github.PullRequestReview{{State: github.String("COMMENTED")}}, nil, nil
+			}
+			return nil, nil, nil
+		},
+	}
+}
+
+// numbers returns the numbers of the PRs
+func numbers(prs []openprchecker.PullRequest) []int {
+	result := make([]int, 0, len(prs))
+	for _, p := range prs {
+		result = append(result, p.Number)
+	}
+	return result
+}
+
+func TestOpenPRsWithoutReviews(t *testing.T) {
+	mockClient := newMockClient()
+
+	prs, err := openprchecker.NewOpenPRChecker(mockClient, newConfig()).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	// #2 was reviewed, #3 is a draft, and #5 and #6 are younger than a day
+	if got := numbers(prs); len(got) != 2 || got[0] != 1 || got[1] != 4 {
+		t.Fatalf("Expected PRs #1 and #4, got %v", got)
+	}
+	if prs[0].Repository != "testorg/api" || prs[0].Author != "dev" {
+		t.Errorf("Unexpected PR: %+v", prs[0])
+	}
+	if !strings.HasPrefix(prs[0].Summary(), "open for 4 days without any review") {
+		t.Errorf("Unexpected summary: %q", prs[0].Summary())
+	}
+	if !strings.HasPrefix(prs[1].Summary(), "open for 30 hours without any review") {
+		t.Errorf("Unexpected summary: %q", prs[1].Summary())
+	}
+
+	// Listing stops at the first PR younger than the minimum age, whose reviews are not fetched
+	if mockClient.ListPullRequestReviewsCalls != 3 {
+		t.Errorf("Expected the reviews of 3 PRs to be fetched, got %d", mockClient.ListPullRequestReviewsCalls)
+	}
+
+	list := openprchecker.Findings(prs)
+	if len(list) != 2 || list[0].Monitor != "open_pr_checker" || list[0].Subject != "#1" {
+		t.Errorf("Unexpected findings: %+v", list)
+	}
+}
+
+func TestOpenDraftPRs(t *testing.T) {
+	cfg := newConfig()
+	cfg.Monitors.OpenPRChecker.IncludeDrafts = true
+	cfg.Monitors.OpenPRChecker.MinAge = config.Hours(40)
+
+	prs, err := openprchecker.NewOpenPRChecker(newMockClient(), cfg).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if got := numbers(prs); len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Fatalf("Expected PRs #1 and #3, got %v", got)
+	}
+
+	var buf bytes.Buffer
+	openprchecker.WriteResultsMarkdown(&buf, prs)
+	output := buf.String()
+	for _, expected := range []string{"Open PRs Without Reviews", "Found 2 open PRs", "#3       2 days", "[draft] Change 3"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestOpenPRsOfOrganization(t *testing.T) {
+	mockClient := newMockClient()
+	mockClient.MockOrgRepositories = []*github.Repository{
+		{FullName: github.String("testorg/api")},
+		{FullName: github.String("testorg/legacy"), Archived: github.Bool(true)},
+	}
+	cfg := newConfig()
+	cfg.Monitors.OpenPRChecker.Repositories = nil
+	cfg.Monitors.OpenPRChecker.Organizations = []string{"testorg"}
+
+	prs, err := openprchecker.NewOpenPRChecker(mockClient, cfg).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if len(prs) != 2 {
+		t.Errorf("Expected 2 PRs, got %v", numbers(prs))
+	}
+
+	// Archived repositories are not checked
+	if mockClient.GetPullRequestsCalls != 1 {
+		t.Errorf("Expected the PRs of 1 repository to be listed, got %d", mockClient.GetPullRequestsCalls)
+	}
+}
+
+func TestOpenPRPagination(t *testing.T) {
+	tests := []struct {
+		name          string
+		pagination    config.PaginationConfig
+		expectPerPage int
+		expectCalls   int
+		expectPRs     []int
+	}{
+		{
+			name:          "Default page size without a page cap",
+			expectPerPage: 100,
+			expectCalls:   3,
+			expectPRs:     []int{1, 2, 3},
+		},
+		{
+			name:          "Capped at two pages",
+			pagination:    config.PaginationConfig{PageSize: 1, MaxPages: 2},
+			expectPerPage: 1,
+			expectCalls:   2,
+			expectPRs:     []int{1, 2},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var perPage int
+			mockClient := &mockgithub.MockGitHubClient{
+				// Each page holds one PR opened days ago, the last page has no next one
+				GetPullRequestsFunc: func(_ context.Context, _, _ string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error) {
+					perPage = opts.PerPage
+					page := max(opts.Page, 1)
+					next := page + 1
+					if page == 3 {
+						next = 0
+					}
+					return []*github.PullRequest{createPR(page, 100-page, false)}, &github.Response{NextPage: next}, nil
+				},
+			}
+			cfg := newConfig()
+			cfg.Monitors.OpenPRChecker.Pagination = tc.pagination
+
+			prs, err := openprchecker.NewOpenPRChecker(mockClient, cfg).Run(context.Background())
+			if err != nil {
+				t.Fatalf("Did not expect an error but got: %v", err)
+			}
+			if perPage != tc.expectPerPage {
+				t.Errorf("Expected %d PRs per page, got %d", tc.expectPerPage, perPage)
+			}
+			if mockClient.GetPullRequestsCalls != tc.expectCalls {
+				t.Errorf("Expected %d pages fetched, got %d", tc.expectCalls, mockClient.GetPullRequestsCalls)
+			}
+			if fmt.Sprint(numbers(prs)) != fmt.Sprint(tc.expectPRs) {
+				t.Errorf("Expected PRs %v, got %v", tc.expectPRs, numbers(prs))
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package prchecker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+// requiredReviewsCache caches the approvals required on base branches by "owner/repo:branch",
+// as the merged PRs of a repository mostly share their base branch
+type requiredReviewsCache struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// get returns the approvals required on a branch, fetching them on first use
+func (c *requiredReviewsCache) get(ctx context.Context, client common.GitHubClientInterface, owner, repo, branch string) (int, error) {
+	key := owner + "/" + repo + ":" + branch
+	c.mu.Lock()
+	count, ok := c.counts[key]
+	c.mu.Unlock()
+	if ok {
+		return count, nil
+	}
+
+	count, err := client.GetRequiredApprovingReviewCount(ctx, owner, repo, branch)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	c.counts[key] = count
+	c.mu.Unlock()
+	return count, nil
+}
+
+// protectionBypass returns who merged a PR with fewer approvals than the protection of its base branch requires,
+// and empty otherwise. GitHub only lets admins and users allowed to bypass the protection merge such PRs
+// Who merged the PR is only fetched when it is short of approvals
+func protectionBypass(ctx context.Context, client common.GitHubClientInterface, owner, repo string, rules Rules,
+	withHead func() (mergedPR, error), withMerger func() (string, error)) (string, error) {
+	pr, err := withHead()
+	if err != nil {
+		return "", err
+	}
+	if pr.BaseBranch == "" {
+		return "", nil
+	}
+
+	required, err := rules.requiredReviews.get(ctx, client, owner, repo, pr.BaseBranch)
+	if err != nil {
+		return "", err
+	}
+	if len(pr.Approvals) >= required {
+		return "", nil
+	}
+
+	merger, err := withMerger()
+	if err != nil {
+		return "", err
+	}
+	if merger == "" {
+		merger = "an unknown user"
+	}
+
+	return fmt.Sprintf("merged by %s with %d of the %d approvals required by the protection of %s",
+		merger, len(pr.Approvals), required, pr.BaseBranch), nil
+}
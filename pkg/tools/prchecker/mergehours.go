@@ -0,0 +1,90 @@
+package prchecker
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+)
+
+// weekdays are the days of the week as configured, e.g. "mon"
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// MergeHours are the business hours PRs are expected to be merged in, e.g. for change-management audits
+type MergeHours struct {
+	Days     []string       // Days merges are expected on, e.g. "mon"
+	Start    time.Duration  // Start of business hours, since midnight
+	End      time.Duration  // End of business hours, since midnight
+	Location *time.Location // Timezone of business hours
+}
+
+// mergeHoursFromConfig returns the merge hours of the configuration, nil when the rule is disabled
+// Invalid days and times are rejected when the configuration is validated, and disable the rule here
+func mergeHoursFromConfig(cfg *config.Config) *MergeHours {
+	hours := cfg.Monitors.PRChecker.MergeHours
+	if !hours.Enabled {
+		return nil
+	}
+	start, err := time.Parse("15:04", hours.Start)
+	if err != nil {
+		return nil
+	}
+	end, err := time.Parse("15:04", hours.End)
+	if err != nil {
+		return nil
+	}
+
+	location := cfg.Location()
+	if hours.Timezone != "" {
+		if location, err = time.LoadLocation(hours.Timezone); err != nil {
+			return nil
+		}
+	}
+	if location == nil {
+		location = time.UTC
+	}
+	return &MergeHours{
+		Days:     hours.Days,
+		Start:    time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute,
+		End:      time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute,
+		Location: location,
+	}
+}
+
+// Contains reports whether a time falls within the merge hours
+func (h MergeHours) Contains(t time.Time) bool {
+	local := t.In(h.Location)
+	day := false
+	for _, d := range h.Days {
+		if weekday, ok := weekdays[d]; ok && weekday == local.Weekday() {
+			day = true
+		}
+	}
+	since := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+	return day && since >= h.Start && since < h.End
+}
+
+// String describes the merge hours, e.g. "mon, tue, wed, thu, fri 09:00-17:00 Europe/Berlin"
+func (h MergeHours) String() string {
+	clock := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%s %s-%s %s", strings.Join(h.Days, ", "), clock(h.Start), clock(h.End), h.Location)
+}
+
+// outsideMergeHours returns when a PR was merged if it was outside the merge hours, and empty otherwise
+func outsideMergeHours(pr mergedPR, hours MergeHours) string {
+	if pr.MergedAt.IsZero() || hours.Contains(pr.MergedAt) {
+		return ""
+	}
+	return fmt.Sprintf("merged %s, outside the merge hours (%s)", pr.MergedAt.In(hours.Location).Format("Mon 2006-01-02 15:04 MST"), hours)
+}
@@ -0,0 +1,65 @@
+package prchecker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v45/github"
+
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+// mergeMethodNames describe how PRs merged with each merge method landed
+var mergeMethodNames = map[string]string{
+	"merge":  "a merge commit",
+	"squash": "a squash merge",
+	"rebase": "a rebase merge",
+}
+
+// disallowedMergeMethod returns how and by whom a PR was merged when its merge method is not allowed, and empty otherwise
+// The commit the PR was merged as tells the method: merge commits have two parents, rebasing copies the last commit
+// of the PR with its message, and squashing writes a commit of its own, by default titled after the PR
+func disallowedMergeMethod(ctx context.Context, client common.GitHubClientInterface, owner, repo string, allowed []string,
+	withHead func() (mergedPR, error), withMerger func() (string, error), listCommits func() ([]*github.RepositoryCommit, error)) (string, error) {
+	pr, err := withHead()
+	if err != nil {
+		return "", err
+	}
+	if pr.MergeSHA == "" {
+		return "", nil
+	}
+
+	merged, err := client.GetCommit(ctx, owner, repo, pr.MergeSHA)
+	if err != nil {
+		return "", err
+	}
+	method := "merge"
+	if len(merged.Parents) < 2 {
+		commits, err := listCommits()
+		if err != nil {
+			return "", err
+		}
+		method = "squash"
+		if len(commits) > 0 && commits[len(commits)-1].GetCommit().GetMessage() == merged.GetCommit().GetMessage() {
+			method = "rebase"
+		}
+	}
+	for _, m := range allowed {
+		if m == method {
+			return "", nil
+		}
+	}
+
+	// Listed PRs come without who merged them, which is only fetched for PRs breaking the rule
+	merger, err := withMerger()
+	if err != nil {
+		return "", err
+	}
+	if merger == "" {
+		merger = "an unknown user"
+	}
+
+	return fmt.Sprintf("merged with %s by %s, the allowed merge methods are %s",
+		mergeMethodNames[method], merger, strings.Join(allowed, ", ")), nil
+}
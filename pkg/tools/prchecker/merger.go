@@ -0,0 +1,86 @@
+package prchecker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+// withMergeDetails returns a flagged PR as reported: with how long after it was opened and last approved it was
+// merged, and who merged it, fetched when not known yet, as listed PRs come without it
+// Who merged the PR only helps triage, so the PR is reported without it when it cannot be fetched
+func withMergeDetails(ctx context.Context, client common.GitHubClientInterface, owner, repo string, pr mergedPR) PR {
+	reported := pr.PR
+	if !pr.CreatedAt.IsZero() && !pr.MergedAt.IsZero() {
+		reported.MergeLatency = pr.MergedAt.Sub(pr.CreatedAt)
+	}
+	// Approvals are the latest approving review of each reviewer, oldest first
+	if len(pr.Approvals) > 0 && !pr.MergedAt.IsZero() {
+		if approved := pr.Approvals[len(pr.Approvals)-1].GetSubmittedAt(); !approved.IsZero() {
+			reported.ApprovalLatency = pr.MergedAt.Sub(approved)
+		}
+	}
+
+	reported.MergedBy = pr.MergedBy
+	if reported.MergedBy == "" && !pr.Fetched {
+		full, err := client.GetPullRequest(ctx, owner, repo, pr.Number)
+		if err != nil {
+			fmt.Printf("  Could not get who merged PR #%d of %s/%s: %v\n", pr.Number, owner, repo, err)
+			return reported
+		}
+		reported.MergedBy = full.GetMergedBy().GetLogin()
+	}
+	return reported
+}
+
+// mergeSummary describes who merged the PR and how long after it was opened and approved,
+// e.g. "merged by bob 3h after opening, 5m after approval", empty when nothing is known
+func (p PR) mergeSummary() string {
+	latencies := p.latencies()
+	switch {
+	case p.MergedBy != "" && latencies != "":
+		return "merged by " + p.MergedBy + " " + latencies
+	case p.MergedBy != "":
+		return "merged by " + p.MergedBy
+	case latencies != "":
+		return "merged " + latencies
+	}
+	return ""
+}
+
+// latencies describes how long after the PR was opened and approved it was merged,
+// e.g. "3h after opening, 5m after approval", empty when not known
+func (p PR) latencies() string {
+	var parts []string
+	if p.MergeLatency > 0 {
+		parts = append(parts, latency(p.MergeLatency)+" after opening")
+	}
+	if p.ApprovalLatency > 0 {
+		parts = append(parts, latency(p.ApprovalLatency)+" after approval")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// bypassDetail describes a branch protection bypass, which names who merged the PR, with how long after
+// it was opened and approved
+func bypassDetail(v Violation) string {
+	if latencies := v.PR.latencies(); latencies != "" {
+		return v.Detail + ", " + latencies
+	}
+	return v.Detail
+}
+
+// latency renders a merge latency in minutes within the first hour, hours within the first two days, and days beyond
+func latency(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
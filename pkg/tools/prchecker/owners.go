@@ -0,0 +1,142 @@
+package prchecker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/anupsv/git-monitoring/pkg/tools/codeowners"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+// maxUnapprovedOwnedFiles is how many files without a code owner approval are named in a violation
+const maxUnapprovedOwnedFiles = 3
+
+// codeOwnersCache caches the CODEOWNERS rules of repositories by "owner/repo", as a repository's merged PRs share them
+// Repositories without a CODEOWNERS file have no rules
+type codeOwnersCache struct {
+	mu    sync.Mutex
+	rules map[string][]codeowners.Rule
+}
+
+// get returns the CODEOWNERS rules of a repository's default branch, fetching them on first use
+func (c *codeOwnersCache) get(ctx context.Context, client common.GitHubClientInterface, owner, repo string) ([]codeowners.Rule, error) {
+	key := owner + "/" + repo
+	c.mu.Lock()
+	rules, ok := c.rules[key]
+	c.mu.Unlock()
+	if ok {
+		return rules, nil
+	}
+
+	for _, location := range codeowners.Locations {
+		content, err := client.GetFileContent(ctx, owner, repo, location)
+		if err != nil {
+			return nil, err
+		}
+		if content != nil {
+			rules = codeowners.Parse(string(content))
+			break
+		}
+	}
+
+	c.mu.Lock()
+	c.rules[key] = rules
+	c.mu.Unlock()
+	return rules, nil
+}
+
+// missingCodeOwnerApproval returns the changed files of an approved PR that have code owners but were approved
+// by none of them, and empty otherwise. Like GitHub's "Require review from Code Owners", every file with owners
+// needs an approval by one of its owners. Owners are users ("@login") or teams ("@org/team"); owners given by
+// email cannot be matched with reviewers and never approve
+func missingCodeOwnerApproval(ctx context.Context, client common.GitHubClientInterface, owner, repo string, pr mergedPR, cache *codeOwnersCache) (string, error) {
+	rules, err := cache.get(ctx, client, owner, repo)
+	if err != nil {
+		return "", err
+	}
+	if len(rules) == 0 {
+		return "", nil
+	}
+
+	files := pr.Files
+	if files == nil {
+		files, err = client.ListPullRequestFiles(ctx, owner, repo, pr.Number)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// Whether an owner approved, looked up once per owner as many files share their owners
+	approvedBy := make(map[string]bool)
+	approved := func(codeOwner string) (bool, error) {
+		if result, ok := approvedBy[codeOwner]; ok {
+			return result, nil
+		}
+		result, err := ownerApproved(ctx, client, codeOwner, pr)
+		if err != nil {
+			return false, err
+		}
+		approvedBy[codeOwner] = result
+		return result, nil
+	}
+
+	var unapproved []string
+	for _, file := range files {
+		owners := codeowners.Owners(rules, file)
+		if len(owners) == 0 {
+			continue
+		}
+
+		ok := false
+		for _, codeOwner := range owners {
+			if ok, err = approved(codeOwner); err != nil {
+				return "", err
+			}
+			if ok {
+				break
+			}
+		}
+		if !ok {
+			unapproved = append(unapproved, fmt.Sprintf("%s (%s)", file, strings.Join(owners, " ")))
+		}
+	}
+
+	if len(unapproved) == 0 {
+		return "", nil
+	}
+	detail := "approved by no code owner of " + strings.Join(unapproved[:min(len(unapproved), maxUnapprovedOwnedFiles)], ", ")
+	if len(unapproved) > maxUnapprovedOwnedFiles {
+		detail += fmt.Sprintf(" and %d more files", len(unapproved)-maxUnapprovedOwnedFiles)
+	}
+	return detail, nil
+}
+
+// ownerApproved reports whether a code owner, a user or the member of a team, approved the PR
+func ownerApproved(ctx context.Context, client common.GitHubClientInterface, codeOwner string, pr mergedPR) (bool, error) {
+	name, ok := strings.CutPrefix(codeOwner, "@")
+	if !ok {
+		return false, nil // Email addresses
+	}
+	org, team, isTeam := strings.Cut(name, "/")
+
+	for _, approval := range pr.Approvals {
+		approver := approval.GetUser().GetLogin()
+		if !isTeam {
+			if strings.EqualFold(approver, name) {
+				return true, nil
+			}
+			continue
+		}
+
+		member, err := client.IsTeamMember(ctx, org, team, approver)
+		if err != nil {
+			return false, err
+		}
+		if member {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,68 @@
+package prchecker
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+// pathGlob compiles a glob of repository paths: "*" matches within a directory, "**" across directories and
+// "?" a single character. A glob ending in "/" matches everything beneath the directory
+func pathGlob(glob string) *regexp.Regexp {
+	glob = strings.TrimPrefix(glob, "/")
+	if strings.HasSuffix(glob, "/") {
+		glob += "**"
+	}
+
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			// "a/**/b" also matches "a/b"
+			pattern.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			pattern.WriteString(".*")
+			i++
+		case glob[i] == '*':
+			pattern.WriteString("[^/]*")
+		case glob[i] == '?':
+			pattern.WriteString("[^/]")
+		default:
+			pattern.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	pattern.WriteString("$")
+	return regexp.MustCompile(pattern.String())
+}
+
+// matchesPaths reports whether any of the paths matches any of the globs
+func matchesPaths(paths, globs []string) bool {
+	for _, glob := range globs {
+		pattern := pathGlob(glob)
+		for _, path := range paths {
+			if pattern.MatchString(path) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inScope reports whether a merged PR is checked under the path scope of the rules: always without one,
+// otherwise only when it changes a file matching one of the globs. The files are only fetched with a scope,
+// and returned for the rules that need them too
+func inScope(ctx context.Context, client common.GitHubClientInterface, owner, repo string, number int, rules Rules) (bool, []string, error) {
+	if len(rules.Paths) == 0 {
+		return true, nil, nil
+	}
+
+	files, err := client.ListPullRequestFiles(ctx, owner, repo, number)
+	if err != nil {
+		return false, nil, err
+	}
+	return matchesPaths(files, rules.Paths), files, nil
+}
@@ -3,10 +3,13 @@ package prchecker
 import (
 	"context"
 	"fmt"
+	"io"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
 	"github.com/anupsv/git-monitoring/pkg/tools/common"
 	"github.com/google/go-github/v45/github"
 )
@@ -15,6 +18,7 @@ import (
 type Result struct {
 	Repository    string
 	UnapprovedPRs []PR
+	Violations    []Violation // Merged PRs breaking review rules other than approval, including branch protection bypasses
 	Error         error
 }
 
@@ -24,16 +28,53 @@ type PR struct {
 	Title  string
 	Author string
 	URL    string
+
+	// Who merged the PR and how long after it was opened and last approved, known for flagged PRs
+	MergedBy        string
+	MergeLatency    time.Duration
+	ApprovalLatency time.Duration // 0 without approval
 }
 
 // MonitorService is the interface for the PR checker service
 type MonitorService interface {
-	CheckRepository(repository string, token string, timeWindow int) Result
+	CheckRepository(ctx context.Context, repository string, token string, timeWindow time.Duration) Result
 }
 
 // Service implements the MonitorService interface
 type Service struct {
 	NewClient func(ctx context.Context, token string) common.GitHubClientInterface
+	// Location is the reporting timezone used to align daily time windows (optional)
+	Location *time.Location
+
+	windowsEndNow     bool                    // Whether time windows end now rather than at local midnight, see config.Config.WindowLocation
+	rules             Rules                   // Review rules checked besides approval
+	repoPolicy        config.RepoPolicyConfig // Repository policies overriding the rules
+	repoTicketKeys    map[string][]string     // Project keys of ticket references by repository
+	repoPaths         map[string][]string     // Globs of the paths merged PRs must change to be checked, by repository
+	repoReviewerTeams map[string][]string     // Teams whose members' approvals are counted, by repository
+	verdicts          *verdictCache           // Verdicts of merged PRs checked by earlier runs, nil when not cached
+
+	excludedAuthors map[string]bool // Lowercased logins of the authors whose merged PRs are not checked
+	excludeBots     bool            // Whether merged PRs authored by bots are not checked
+
+	overrides map[string]repoOverride // Policies overriding the global settings, by lowercased "owner/repo"
+
+	pagination             config.PaginationConfig // Page size and page cap of listing the PRs of each repository
+	statusChecksPagination config.PaginationConfig // Page size and page cap of listing the statuses and check runs of each PR
+	outOfWindowThreshold   int                     // Consecutive PRs merged before the time window after which listing stops
+}
+
+// defaultOutOfWindowThreshold is the number of consecutive PRs merged before the time window after which
+// listing a repository stops, when not configured
+const defaultOutOfWindowThreshold = 20
+
+// repoOverride is the policy of a repository overriding the global settings, unset settings are zero or nil
+// It is resolved before the repository's policy file, which can only tighten it, see rulesFor
+type repoOverride struct {
+	timeWindow        time.Duration
+	requiredApprovals *int
+	excludedAuthors   map[string]bool // Lowercased logins, replacing the global ones when not nil
+	excludeBots       *bool
 }
 
 // NewService creates a new PR checker service
@@ -45,24 +86,130 @@ func NewService() *Service {
 	}
 }
 
+// excludedAuthor reports whether the merged PRs of an author in a repository are not checked: authors listed in
+// excluded_authors, with or without the "[bot]" suffix of apps, and bots when exclude_bots is set
+// The repository's override replaces either setting
+func (s *Service) excludedAuthor(repository string, author *github.User) bool {
+	excluded, bots := s.excludedAuthors, s.excludeBots
+	if override, ok := s.overrides[strings.ToLower(repository)]; ok {
+		if override.excludedAuthors != nil {
+			excluded = override.excludedAuthors
+		}
+		if override.excludeBots != nil {
+			bots = *override.excludeBots
+		}
+	}
+
+	login := strings.ToLower(author.GetLogin())
+	if excluded[login] || excluded[strings.TrimSuffix(login, "[bot]")] {
+		return true
+	}
+	return bots && (author.GetType() == "Bot" || strings.HasSuffix(login, "[bot]"))
+}
+
+// timeWindow returns the time window of a repository's merged PRs, its override's or the global one
+func (s *Service) timeWindow(repository string, global time.Duration) time.Duration {
+	if window := s.overrides[strings.ToLower(repository)].timeWindow; window > 0 {
+		return window
+	}
+	return global
+}
+
+// longestTimeWindow returns the longest time window of the repositories, which spans the merged PRs of all
+func (s *Service) longestTimeWindow(global time.Duration) time.Duration {
+	longest := global
+	for _, override := range s.overrides {
+		longest = max(longest, override.timeWindow)
+	}
+	return longest
+}
+
+// lowercaseSet returns the set of the lowercased values
+func lowercaseSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[strings.ToLower(value)] = true
+	}
+	return set
+}
+
+// checkedBase reports whether PRs merged into a branch are checked: always without base branches,
+// otherwise only when the branch matches one of the globs, e.g. "release/*"
+func (r Rules) checkedBase(branch string) bool {
+	if len(r.BaseBranches) == 0 {
+		return true
+	}
+	return matchesPaths([]string{branch}, r.BaseBranches)
+}
+
+// excludeForks leaves out the forks among the repositories of an organization when exclude_forks is set
+// Forks duplicate the pull request history of their upstream, whose merges were not reviewed in the organization
+func excludeForks(cfg *config.Config, repos []*github.Repository) []*github.Repository {
+	if !cfg.Monitors.PRChecker.ExcludeForks {
+		return repos
+	}
+
+	kept := make([]*github.Repository, 0, len(repos))
+	for _, repo := range repos {
+		if !repo.GetFork() {
+			kept = append(kept, repo)
+		}
+	}
+	if forks := len(repos) - len(kept); forks > 0 {
+		fmt.Printf("Excluding %d forks (exclude_forks is set)\n", forks)
+	}
+	return kept
+}
+
+// skipNewAndInactive leaves out listed repositories created within min_repo_age_hours, which are still being
+// set up, and those without pushes in skip_inactive_days, which have nothing new to check
+func skipNewAndInactive(cfg *config.Config, repos []*github.Repository, now time.Time) []*github.Repository {
+	minAge := cfg.Monitors.PRChecker.MinRepoAge.Duration
+	inactiveDays := cfg.Monitors.PRChecker.SkipInactiveDays
+	if minAge <= 0 && inactiveDays <= 0 {
+		return repos
+	}
+
+	kept := make([]*github.Repository, 0, len(repos))
+	young, inactive := 0, 0
+	for _, repo := range repos {
+		// Repositories without a creation or push time are kept, rather than skipped on missing data
+		if minAge > 0 && repo.CreatedAt != nil && now.Sub(repo.GetCreatedAt().Time) < minAge {
+			young++
+			continue
+		}
+		if inactiveDays > 0 && repo.PushedAt != nil && now.Sub(repo.GetPushedAt().Time) > time.Duration(inactiveDays)*24*time.Hour {
+			inactive++
+			continue
+		}
+		kept = append(kept, repo)
+	}
+
+	if young > 0 {
+		fmt.Printf("Skipping %d repositories created within the last %v (min_repo_age_hours)\n", young, minAge)
+	}
+	if inactive > 0 {
+		fmt.Printf("Skipping %d repositories without pushes in the last %d days (skip_inactive_days)\n", inactive, inactiveDays)
+	}
+	return kept
+}
+
 // Monitor checks all repositories in the configuration for unapproved PRs
-func Monitor(cfg *config.Config) []Result {
+func Monitor(ctx context.Context, cfg *config.Config) []Result {
 	if !cfg.Monitors.PRChecker.Enabled {
 		return nil
 	}
 
-	return MonitorWithService(cfg, NewService())
+	return MonitorWithService(ctx, cfg, NewService())
 }
 
 // MonitorWithService is a testable version of Monitor that accepts a custom service
 // This makes it easier to test with mock services
-func MonitorWithService(cfg *config.Config, service *Service) []Result {
+func MonitorWithService(ctx context.Context, cfg *config.Config, service *Service) []Result {
 	if !cfg.Monitors.PRChecker.Enabled {
 		return nil
 	}
 
-	ctx := context.Background()
-
 	var repositories []string
 
 	// Determine which repositories to check based on visibility setting
@@ -76,11 +223,44 @@ func MonitorWithService(cfg *config.Config, service *Service) []Result {
 		var repos []*github.Repository
 		var err error
 
-		if cfg.Monitors.PRChecker.Organization != "" {
+		if org, team := cfg.Monitors.PRChecker.Organization, cfg.Monitors.PRChecker.TeamSlug; org != "" && team != "" {
+			target := "team:" + org + "/" + team
+			if reason := common.StoppedIn(ctx); reason != "" {
+				common.Skip(ctx, target, reason)
+				return nil
+			}
+			// Fetch the repositories the team has access to, so the team's estate is checked without listing it
+			fmt.Printf("Fetching repositories of team '%s' in organization '%s' with visibility '%s'...\n",
+				team, org, cfg.Monitors.PRChecker.RepoVisibility)
+			repos, err = client.ListTeamRepositories(ctx, org, team, cfg.Monitors.PRChecker.RepoVisibility)
+			if err != nil && common.StoppedIn(ctx) == common.StopBudget {
+				common.Skip(ctx, target, common.StopBudget)
+				return nil
+			}
+			if err != nil {
+				return []Result{
+					{
+						Repository: target,
+						Error:      fmt.Errorf("failed to fetch team repositories: %v", err),
+					},
+				}
+			}
+			fmt.Printf("Found %d repositories of team '%s' in organization '%s' with visibility '%s'\n",
+				len(repos), team, org, cfg.Monitors.PRChecker.RepoVisibility)
+			repos = excludeForks(cfg, repos)
+		} else if cfg.Monitors.PRChecker.Organization != "" {
+			if reason := common.StoppedIn(ctx); reason != "" {
+				common.Skip(ctx, "org:"+cfg.Monitors.PRChecker.Organization, reason)
+				return nil
+			}
 			// Fetch repositories from the specified organization
 			fmt.Printf("Fetching repositories for organization '%s' with visibility '%s'...\n",
 				cfg.Monitors.PRChecker.Organization, cfg.Monitors.PRChecker.RepoVisibility)
 			repos, err = client.ListOrganizationRepositories(ctx, cfg.Monitors.PRChecker.Organization, cfg.Monitors.PRChecker.RepoVisibility)
+			if err != nil && common.StoppedIn(ctx) == common.StopBudget {
+				common.Skip(ctx, "org:"+cfg.Monitors.PRChecker.Organization, common.StopBudget)
+				return nil
+			}
 			if err != nil {
 				return []Result{
 					{
@@ -91,11 +271,20 @@ func MonitorWithService(cfg *config.Config, service *Service) []Result {
 			}
 			fmt.Printf("Found %d repositories for organization '%s' with visibility '%s'\n",
 				len(repos), cfg.Monitors.PRChecker.Organization, cfg.Monitors.PRChecker.RepoVisibility)
+			repos = excludeForks(cfg, repos)
 		} else {
+			if reason := common.StoppedIn(ctx); reason != "" {
+				common.Skip(ctx, "user-repositories", reason)
+				return nil
+			}
 			// Fetch repositories for the authenticated user
 			fmt.Printf("Fetching repositories for authenticated user with visibility '%s'...\n",
 				cfg.Monitors.PRChecker.RepoVisibility)
 			repos, err = client.ListUserRepositories(ctx, cfg.Monitors.PRChecker.RepoVisibility)
+			if err != nil && common.StoppedIn(ctx) == common.StopBudget {
+				common.Skip(ctx, "user-repositories", common.StopBudget)
+				return nil
+			}
 			if err != nil {
 				return []Result{
 					{
@@ -108,6 +297,14 @@ func MonitorWithService(cfg *config.Config, service *Service) []Result {
 				len(repos), cfg.Monitors.PRChecker.RepoVisibility)
 		}
 
+		repos = skipNewAndInactive(cfg, repos, time.Now())
+
+		// Apply the repository attribute filters shared by all monitors
+		repos, filtered := common.FilterRepositories(ctx, repos, cfg.RepoFilters)
+		if filtered > 0 {
+			fmt.Printf("Filtered out %d repositories not matching repo_filters\n", filtered)
+		}
+
 		// Create a map of excluded repositories for faster lookup
 		excludedRepos := make(map[string]bool)
 		for _, repo := range cfg.Monitors.PRChecker.ExcludedRepositories {
@@ -137,15 +334,74 @@ func MonitorWithService(cfg *config.Config, service *Service) []Result {
 		}
 	}
 
+	if service.Location == nil {
+		service.Location = cfg.Location()
+	}
+	service.windowsEndNow = cfg.WindowsEndNow
+	service.rules = rulesFromConfig(cfg)
+	service.repoPolicy = cfg.RepoPolicy
+	service.repoTicketKeys = cfg.Monitors.PRChecker.RepoTicketKeys
+	service.repoPaths = cfg.Monitors.PRChecker.RepoPaths
+	service.repoReviewerTeams = cfg.Monitors.PRChecker.RepoReviewerTeams
+	service.excludedAuthors = lowercaseSet(cfg.Monitors.PRChecker.ExcludedAuthors)
+	service.excludeBots = cfg.Monitors.PRChecker.ExcludeBots
+	service.pagination = cfg.Monitors.PRChecker.Pagination
+	service.statusChecksPagination = cfg.Monitors.PRChecker.StatusChecksPagination
+	service.outOfWindowThreshold = cfg.Monitors.PRChecker.OutOfWindowThreshold
+	service.overrides = make(map[string]repoOverride, len(cfg.Monitors.PRChecker.RepoOverrides))
+	for _, override := range cfg.Monitors.PRChecker.RepoOverrides {
+		policy := repoOverride{
+			timeWindow:        override.TimeWindow.Duration,
+			requiredApprovals: override.RequiredApprovals,
+			excludeBots:       override.ExcludeBots,
+		}
+		if override.ExcludedAuthors != nil {
+			policy.excludedAuthors = lowercaseSet(override.ExcludedAuthors)
+		}
+		service.overrides[strings.ToLower(override.Repository)] = policy
+	}
+
+	// Verdicts of merged PRs are kept in the state, so PRs in the overlap of consecutive time windows are
+	// checked once. Search results leave out the merge commit the verdicts are keyed by
+	service.verdicts = nil
+	if cfg.State.Enabled && cfg.Monitors.PRChecker.CacheVerdicts && cfg.Monitors.PRChecker.Discovery != "search" {
+		verdicts, err := loadVerdicts(cfg.State.Path)
+		if err != nil {
+			fmt.Printf("Could not load cached PR verdicts, checking all merged PRs: %v\n", err)
+		}
+		service.verdicts = verdicts
+		defer func() {
+			if verdicts == nil {
+				return
+			}
+			fmt.Printf("Reused the verdicts of %d merged PRs checked by earlier runs\n", verdicts.hits)
+			since := common.WindowStart(time.Now(), service.longestTimeWindow(cfg.Monitors.PRChecker.TimeWindow.Duration), service.windowLocation())
+			if err := verdicts.save(since); err != nil {
+				fmt.Printf("Could not save PR verdicts: %v\n", err)
+			}
+		}()
+	}
+
+	if cfg.Monitors.PRChecker.Discovery == "search" {
+		return service.checkSearchedRepositories(ctx, cfg, repositories)
+	}
+
 	results := make([]Result, 0, len(repositories))
 
 	fmt.Printf("Processing %d repositories...\n", len(repositories))
 	for i, repo := range repositories {
+		if common.SkipIfStopped(ctx, repo) {
+			continue
+		}
 		fmt.Printf("[%d/%d] Checking repository: %s\n", i+1, len(repositories), repo)
-		result := service.CheckRepository(repo, cfg.GitHub.Token, cfg.Monitors.PRChecker.TimeWindow, cfg.Monitors.PRChecker.DebugLogging)
+		timeWindow := service.timeWindow(repo, cfg.Monitors.PRChecker.TimeWindow.Duration)
+		result := service.CheckRepository(ctx, repo, cfg.GitHub.Token, timeWindow, cfg.Monitors.PRChecker.DebugLogging)
+		if common.SkipIfBudgetExceeded(ctx, repo, result.Error) {
+			continue
+		}
 		results = append(results, result)
 	}
-	fmt.Printf("Completed checking all %d repositories\n", len(repositories))
+	fmt.Printf("Completed checking %d of %d repositories\n", len(results), len(repositories))
 
 	return results
 }
@@ -171,9 +427,13 @@ func PrintResults(results []Result) bool {
 		if len(result.UnapprovedPRs) > 0 {
 			reposWithUnapprovedPRs = append(reposWithUnapprovedPRs, result.Repository)
 			for _, pr := range result.UnapprovedPRs {
+				details := "created by " + pr.Author
+				if summary := pr.mergeSummary(); summary != "" {
+					details += ", " + summary
+				}
 				unapprovedPRsList = append(unapprovedPRsList,
-					fmt.Sprintf("- %s #%d: %s (created by %s) %s",
-						result.Repository, pr.Number, pr.Title, pr.Author, pr.URL))
+					fmt.Sprintf("- %s #%d: %s (%s) %s",
+						result.Repository, pr.Number, pr.Title, details, pr.URL))
 			}
 			allApproved = false
 		} else {
@@ -197,6 +457,43 @@ func PrintResults(results []Result) bool {
 		}
 	}
 
+	// Output PRs merged by bypassing branch protection
+	protectionBypasses := 0
+	for _, result := range results {
+		if result.Error != nil {
+			continue
+		}
+		for _, v := range bypasses(result.Violations) {
+			if protectionBypasses == 0 {
+				fmt.Println("\n🚨 BRANCH PROTECTION BYPASSES:")
+			}
+			protectionBypasses++
+			fmt.Printf("- %s #%d: %s (%s) %s\n", result.Repository, v.PR.Number, v.PR.Title, bypassDetail(v), v.PR.URL)
+		}
+	}
+
+	// Output PRs breaking other review rules
+	violations := 0
+	for _, result := range results {
+		if result.Error != nil {
+			continue
+		}
+		for _, v := range result.Violations {
+			if v.Rule == RuleProtectionBypass {
+				continue
+			}
+			if violations == 0 {
+				fmt.Println("\n🔍 REVIEW RULE VIOLATIONS:")
+			}
+			violations++
+			detail := v.Detail
+			if summary := v.PR.mergeSummary(); summary != "" {
+				detail += "; " + summary
+			}
+			fmt.Printf("- %s #%d: %s (%s: %s) %s\n", result.Repository, v.PR.Number, v.PR.Title, v.Rule, detail, v.PR.URL)
+		}
+	}
+
 	// Print summary
 	fmt.Println("\n📊 SUMMARY:")
 	if len(reposWithErrors) > 0 {
@@ -205,6 +502,12 @@ func PrintResults(results []Result) bool {
 	if len(reposWithUnapprovedPRs) > 0 {
 		fmt.Printf("  Repositories with unapproved PRs: %d\n", len(reposWithUnapprovedPRs))
 	}
+	if protectionBypasses > 0 {
+		fmt.Printf("  PRs merged by bypassing branch protection: %d\n", protectionBypasses)
+	}
+	if violations > 0 {
+		fmt.Printf("  PRs breaking review rules: %d\n", violations)
+	}
 	fmt.Printf("  Repositories with all PRs approved: %d\n", len(approvedRepos))
 	fmt.Printf("  Total repositories checked: %d\n", len(results))
 
@@ -217,9 +520,46 @@ func PrintResults(results []Result) bool {
 	return allApproved
 }
 
-// PrintResultsMarkdown outputs PR check results in a code block format suitable for Slack
-// It only includes repositories with unapproved PRs (problematic results)
-func PrintResultsMarkdown(results []Result) bool {
+// Findings converts PR check results into findings
+// Repositories that could not be checked are not findings and are skipped
+func Findings(results []Result) []findings.Finding {
+	list := make([]findings.Finding, 0)
+	for _, result := range results {
+		for _, pr := range result.UnapprovedPRs {
+			list = append(list, findings.Finding{
+				Monitor:    "pr_checker",
+				Repository: result.Repository,
+				Subject:    fmt.Sprintf("PR #%d", pr.Number),
+				Summary:    fmt.Sprintf("%s by %s merged without approval", pr.Title, pr.Author),
+				URL:        pr.URL,
+				Rule:       RuleUnapproved,
+			})
+		}
+		for _, v := range result.Violations {
+			finding := findings.Finding{
+				Monitor:    "pr_checker",
+				Repository: result.Repository,
+				Subject:    fmt.Sprintf("PR #%d %s", v.PR.Number, v.Rule),
+				Summary:    fmt.Sprintf("%s by %s %s", v.PR.Title, v.PR.Author, v.Detail),
+				URL:        v.PR.URL,
+				Rule:       v.Rule,
+			}
+			// Titles are a convention, not a risk, while bypassing branch protection circumvents review entirely
+			switch v.Rule {
+			case RuleTitle:
+				finding.Severity = findings.SeverityLow
+			case RuleProtectionBypass:
+				finding.Severity = findings.SeverityHigh
+			}
+			list = append(list, finding)
+		}
+	}
+	return list
+}
+
+// WriteResultsMarkdown writes PR check results in a code block format suitable for Slack
+// It only includes repositories with unapproved PRs or PRs breaking review rules (problematic results)
+func WriteResultsMarkdown(w io.Writer, results []Result) bool {
 	// Count total unapproved PRs
 	totalUnapprovedPRs := 0
 	for _, result := range results {
@@ -229,18 +569,20 @@ func PrintResultsMarkdown(results []Result) bool {
 	}
 
 	if totalUnapprovedPRs == 0 {
-		return true // No problematic results to display
+		writeBypassesMarkdown(w, results)
+		writeViolationsMarkdown(w, results)
+		return true // No unapproved PRs to display
 	}
 
 	// Print header for PR issues with proper spacing
-	fmt.Println("## :warning: Unapproved Pull Requests")
-	fmt.Printf("Found %d unapproved pull requests that require attention.\n\n", totalUnapprovedPRs)
+	fmt.Fprintln(w, "## :warning: Unapproved Pull Requests")
+	fmt.Fprintf(w, "Found %d unapproved pull requests that require attention.\n\n", totalUnapprovedPRs)
 
 	// Start code block
-	fmt.Println("```")
+	fmt.Fprintln(w, "```")
 	// Create fixed-width headers with proper spacing for code block
-	fmt.Println("Repository                PR      Author              Link")
-	fmt.Println("--------------------------------------------------------")
+	fmt.Fprintln(w, "Repository                PR      Author              Link")
+	fmt.Fprintln(w, "--------------------------------------------------------")
 
 	// Print each unapproved PR in a fixed-width format for code blocks
 	for _, result := range results {
@@ -275,29 +617,125 @@ func PrintResultsMarkdown(results []Result) bool {
 			}
 
 			// Format the output row with fixed-width fields
-			fmt.Printf("%s %s %s %s\n",
+			fmt.Fprintf(w, "%s %s %s %s\n",
 				repoStr,
 				prStr,
 				authorStr,
 				pr.URL)
+			if summary := pr.mergeSummary(); summary != "" {
+				fmt.Fprintf(w, "  %s\n", summary)
+			}
 		}
 	}
 
 	// End code block
-	fmt.Println("```")
-	fmt.Println("")
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+	writeBypassesMarkdown(w, results)
+	writeViolationsMarkdown(w, results)
 	return true
 }
 
+// writeBypassesMarkdown writes the PRs merged by bypassing branch protection, if any
+func writeBypassesMarkdown(w io.Writer, results []Result) {
+	total := 0
+	for _, result := range results {
+		if result.Error == nil {
+			total += len(bypasses(result.Violations))
+		}
+	}
+	if total == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "## :rotating_light: Branch Protection Bypasses")
+	fmt.Fprintf(w, "Found %d pull requests merged with fewer approvals than branch protection requires.\n\n", total)
+
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "Repository                PR      Author              Link")
+	fmt.Fprintln(w, "--------------------------------------------------------")
+	for _, result := range results {
+		if result.Error != nil {
+			continue
+		}
+		for _, v := range bypasses(result.Violations) {
+			repoStr := result.Repository
+			if len(repoStr) > 24 {
+				repoStr = repoStr[:21] + "..."
+			} else {
+				repoStr = fmt.Sprintf("%-24s", repoStr)
+			}
+			fmt.Fprintf(w, "%s #%-6d %-18s %s\n", repoStr, v.PR.Number, v.PR.Author, v.PR.URL)
+			fmt.Fprintf(w, "  %s\n", bypassDetail(v))
+		}
+	}
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
+
+// bypasses returns the violations that are branch protection bypasses, reported as a category of their own
+func bypasses(violations []Violation) []Violation {
+	var result []Violation
+	for _, v := range violations {
+		if v.Rule == RuleProtectionBypass {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// writeViolationsMarkdown writes the PRs breaking review rules other than approval, if any
+// Branch protection bypasses are written by writeBypassesMarkdown
+func writeViolationsMarkdown(w io.Writer, results []Result) {
+	total := 0
+	for _, result := range results {
+		if result.Error == nil {
+			total += len(result.Violations) - len(bypasses(result.Violations))
+		}
+	}
+	if total == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "## :mag: Review Rule Violations")
+	fmt.Fprintf(w, "Found %d merged pull requests breaking review rules.\n\n", total)
+
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "Repository                PR      Rule                Link")
+	fmt.Fprintln(w, "----------------------------------------------------------")
+	for _, result := range results {
+		if result.Error != nil {
+			continue
+		}
+		for _, v := range result.Violations {
+			if v.Rule == RuleProtectionBypass {
+				continue
+			}
+			repoStr := result.Repository
+			if len(repoStr) > 24 {
+				repoStr = repoStr[:21] + "..."
+			} else {
+				repoStr = fmt.Sprintf("%-24s", repoStr)
+			}
+			fmt.Fprintf(w, "%s #%-6d %-19s %s\n", repoStr, v.PR.Number, v.Rule, v.PR.URL)
+			fmt.Fprintf(w, "  %s\n", v.Detail)
+			if summary := v.PR.mergeSummary(); summary != "" {
+				fmt.Fprintf(w, "  %s\n", summary)
+			}
+		}
+	}
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
+
 // CheckRepository checks a single repository for unapproved PRs
 // nolint:gocyclo // This function has high complexity due to numerous edge cases and conditions
-func (s *Service) CheckRepository(repository, token string, timeWindow int, debugLogging bool) Result {
+func (s *Service) CheckRepository(ctx context.Context, repository, token string, timeWindow time.Duration, debugLogging bool) Result {
 	result := Result{
 		Repository: repository,
 	}
 
 	// Create an authenticated GitHub client
-	ctx := context.Background()
 	client := s.NewClient(ctx, token)
 
 	// Parse owner and repo
@@ -309,7 +747,12 @@ func (s *Service) CheckRepository(repository, token string, timeWindow int, debu
 
 	// Calculate the time window
 	now := time.Now()
-	cutoffTime := now.Add(-time.Duration(timeWindow) * time.Hour)
+	cutoffTime := common.WindowStart(now, timeWindow, s.windowLocation())
+	// PRs merged since the window ended are left to the next run
+	windowEnd := common.WindowEnd(now, timeWindow, s.windowLocation())
+	// The review rules are looked up with the first merged PR, so quiet repositories cost no policy lookup
+	var rules *Rules
+	var rulesKey string // Fingerprint of the rules, identifying the verdicts reached with them
 
 	// Get pull requests that were updated within our time window
 	// This is more efficient than fetching all PRs and filtering locally
@@ -318,16 +761,21 @@ func (s *Service) CheckRepository(repository, token string, timeWindow int, debu
 		Sort:      "updated", // Sort by last updated
 		Direction: "desc",    // Most recently updated first
 		ListOptions: github.ListOptions{
-			PerPage: 100,
+			PerPage: s.pagination.PerPage(),
 		},
 	}
 
 	if debugLogging {
-		fmt.Printf("  Using time window: PRs merged since %s\n", cutoffTime.Format(time.RFC3339))
+		fmt.Printf("  Using time window: PRs merged since %s\n", common.LocalTime(cutoffTime, s.Location).Format(time.RFC3339))
 	}
 
-	unapprovedPRs := []PR{}
+	found := progress{UnapprovedPRs: []PR{}}
 	page := 1
+	// Continue from where a scan stopped within this repository, with the PRs it found
+	if resumePage := common.ResumeCursor(ctx, repository, &found); resumePage > 0 {
+		fmt.Printf("  Resuming %s from page %d\n", repository, resumePage)
+		page = resumePage
+	}
 	totalPRs := 0
 	totalMergedPRsInWindow := 0
 	stopFetching := false
@@ -335,20 +783,37 @@ func (s *Service) CheckRepository(repository, token string, timeWindow int, debu
 	// Counter for consecutive PRs outside our time window
 	consecutivePRsOutsideWindow := 0
 	// Threshold for how many consecutive PRs outside window before stopping
-	const outOfWindowThreshold = 20
+	outOfWindowThreshold := s.outOfWindowThreshold
+	if outOfWindowThreshold <= 0 {
+		outOfWindowThreshold = defaultOutOfWindowThreshold
+	}
+	// Counter for pages fetched, capped by max_pages
+	fetchedPages := 0
 	// Counter for skipped PRs (either not merged or merged before cutoff)
 	skippedPRs := 0
+	// Counter for merged PRs not checked because of their author
+	excludedPRs := 0
+	// Counter for merged PRs into base branches not checked
+	otherBasePRs := 0
 
 	for {
 		if stopFetching {
 			break
 		}
 
+		// Older PRs are left unchecked beyond the page cap, which bounds the cost of repositories merging a lot
+		if limit := s.pagination.MaxPages; limit > 0 && fetchedPages >= limit {
+			fmt.Printf("  Reached the limit of %d pages for %s, PRs on later pages are not checked\n", limit, repository)
+			break
+		}
+		fetchedPages++
+
 		opts.Page = page
 		fmt.Printf("  Fetching PRs from %s/%s (page %d)...\n", owner, repo, page)
 
 		prs, resp, err := client.GetPullRequests(ctx, owner, repo, opts)
 		if err != nil {
+			saveProgress(ctx, repository, page, found)
 			result.Error = fmt.Errorf("error getting pull requests: %v", err)
 			return result
 		}
@@ -360,6 +825,8 @@ func (s *Service) CheckRepository(repository, token string, timeWindow int, debu
 
 		pageSkippedPRs := 0
 		mergedPRsInWindow := 0
+		// PRs found on earlier pages, kept when the scan stops within this page
+		pageStart := found.upTo()
 
 		// Check each PR
 		for _, pr := range prs {
@@ -371,7 +838,7 @@ func (s *Service) CheckRepository(repository, token string, timeWindow int, debu
 			if updatedAt.Before(cutoffTime) {
 				if debugLogging {
 					fmt.Printf("  Found PR #%d updated at %s (before cutoff), stopping further requests\n",
-						pr.GetNumber(), updatedAt.Format(time.RFC3339))
+						pr.GetNumber(), common.LocalTime(updatedAt, s.Location).Format(time.RFC3339))
 				}
 				stopFetching = true
 				break
@@ -407,30 +874,129 @@ func (s *Service) CheckRepository(repository, token string, timeWindow int, debu
 
 			// This PR is in our time window, reset the counter
 			consecutivePRsOutsideWindow = 0
+			if !mergedAt.Before(windowEnd) {
+				pageSkippedPRs++
+				skippedPRs++
+				continue
+			}
 			mergedPRsInWindow++
 			totalMergedPRsInWindow++
 
+			// PRs of excluded authors, such as dependency update bots, are not checked
+			if s.excludedAuthor(repository, pr.GetUser()) {
+				if debugLogging {
+					fmt.Printf("  PR #%d was authored by excluded author %s, skipping\n", pr.GetNumber(), pr.GetUser().GetLogin())
+				}
+				excludedPRs++
+				continue
+			}
+
+			if rules == nil {
+				repositoryRules := s.rulesFor(ctx, client, repository)
+				rules = &repositoryRules
+				rulesKey = rules.fingerprint()
+			}
+
+			// PRs merged into other branches than the base branches, such as feature branches, are not checked
+			if !rules.checkedBase(pr.GetBase().GetRef()) {
+				if debugLogging {
+					fmt.Printf("  PR #%d was merged into %s, not a checked base branch, skipping\n", pr.GetNumber(), pr.GetBase().GetRef())
+				}
+				otherBasePRs++
+				continue
+			}
+
 			// Debug logging
 			if debugLogging {
 				fmt.Printf("  Checking PR #%d in %s/%s: %s (merged at %s)\n",
-					pr.GetNumber(), owner, repo, pr.GetTitle(), mergedAt.Format(time.RFC3339))
+					pr.GetNumber(), owner, repo, pr.GetTitle(), common.LocalTime(mergedAt, s.Location).Format(time.RFC3339))
+			}
+
+			// PRs checked by an earlier run against the same rules are not checked again
+			key := verdictKey(repository, pr.GetNumber(), pr.GetMergeCommitSHA())
+			if cached, ok := s.verdicts.lookup(key, rulesKey); ok {
+				if debugLogging {
+					fmt.Printf("  PR #%d was checked by an earlier run, reusing its verdict\n", pr.GetNumber())
+				}
+				if cached.InScope && !cached.Approved {
+					found.UnapprovedPRs = append(found.UnapprovedPRs, PR{
+						Number:       pr.GetNumber(),
+						Title:        pr.GetTitle(),
+						Author:       pr.GetUser().GetLogin(),
+						URL:          pr.GetHTMLURL(),
+						MergedBy:     cached.MergedBy,
+						MergeLatency: mergedAt.Sub(pr.GetCreatedAt()),
+					})
+				}
+				found.Violations = append(found.Violations, cached.Violations...)
+				continue
+			}
+
+			// PRs changing no path in the scope of the repository are not checked
+			checked, files, err := inScope(ctx, client, owner, repo, pr.GetNumber(), *rules)
+			if err != nil {
+				saveProgress(ctx, repository, page, pageStart)
+				result.Error = fmt.Errorf("error listing PR files: %v", err)
+				return result
+			}
+			if !checked {
+				if debugLogging {
+					fmt.Printf("  PR #%d changes no path in scope, skipping\n", pr.GetNumber())
+				}
+				s.verdicts.record(key, rulesKey, mergedAt, verdict{})
+				continue
 			}
 
 			// Check if this PR is approved
-			isApproved, err := isPRApproved(ctx, client, owner, repo, pr.GetNumber(), debugLogging)
+			isApproved, approvals, dismissed, err := isPRApproved(ctx, client, owner, repo, pr.GetNumber(), debugLogging)
+			if err == nil && isApproved {
+				approvals, err = teamApprovals(ctx, client, approvals, rules.ReviewerTeams, debugLogging)
+				isApproved = len(approvals) > 0
+			}
 			if err != nil {
+				saveProgress(ctx, repository, page, pageStart)
 				result.Error = fmt.Errorf("error checking PR approval: %v", err)
 				return result
 			}
 
-			if !isApproved {
-				unapprovedPRs = append(unapprovedPRs, PR{
+			merged := mergedPR{
+				PR: PR{
 					Number: pr.GetNumber(),
 					Title:  pr.GetTitle(),
 					Author: pr.GetUser().GetLogin(),
 					URL:    pr.GetHTMLURL(),
-				})
+				},
+				Body:       pr.GetBody(),
+				HeadBranch: pr.GetHead().GetRef(),
+				HeadSHA:    pr.GetHead().GetSHA(),
+				MergeSHA:   pr.GetMergeCommitSHA(),
+				BaseBranch: pr.GetBase().GetRef(),
+				CreatedAt:  pr.GetCreatedAt(),
+				MergedAt:   mergedAt,
+				Approved:   isApproved,
+				Approvals:  approvals,
+				Dismissed:  dismissed,
+				Files:      files,
+			}
+			violations, err := checkRules(common.WithPagination(ctx, s.statusChecksPagination), client, owner, repo, &merged, *rules)
+			if err != nil {
+				saveProgress(ctx, repository, page, pageStart)
+				result.Error = fmt.Errorf("error checking review rules: %v", err)
+				return result
+			}
+
+			// Flagged PRs are reported with who merged them and how long after they were opened and approved
+			if !isApproved || len(violations) > 0 {
+				merged.PR = withMergeDetails(ctx, client, owner, repo, merged)
+				for i := range violations {
+					violations[i].PR = merged.PR
+				}
+			}
+			if !isApproved {
+				found.UnapprovedPRs = append(found.UnapprovedPRs, merged.PR)
 			}
+			found.Violations = append(found.Violations, violations...)
+			s.verdicts.record(key, rulesKey, mergedAt, verdict{InScope: true, Approved: isApproved, MergedBy: merged.MergedBy, Violations: violations})
 		}
 
 		fmt.Printf("  Found %d PRs on page %d, %d merged within time window, %d skipped\n",
@@ -456,19 +1022,43 @@ func (s *Service) CheckRepository(repository, token string, timeWindow int, debu
 		page = resp.NextPage
 	}
 
-	fmt.Printf("  Completed checking %s: %d total PRs examined, %d merged within time window, %d skipped, %d unapproved\n",
-		repository, totalPRs, totalMergedPRsInWindow, skippedPRs, len(unapprovedPRs))
+	fmt.Printf("  Completed checking %s: %d total PRs examined, %d merged within time window, %d skipped, %d by excluded authors, %d into other base branches, %d unapproved, %d breaking review rules\n",
+		repository, totalPRs, totalMergedPRsInWindow, skippedPRs, excludedPRs, otherBasePRs, len(found.UnapprovedPRs), len(found.Violations))
 
-	result.UnapprovedPRs = unapprovedPRs
+	result.UnapprovedPRs = found.UnapprovedPRs
+	result.Violations = found.Violations
 	return result
 }
 
+// progress is what the check of a repository found before it stopped, saved to resume it
+type progress struct {
+	UnapprovedPRs []PR        `json:"unapproved_prs"`
+	Violations    []Violation `json:"violations,omitempty"`
+}
+
+// upTo returns how much has been found so far, to keep only that with saveProgress
+func (p *progress) upTo() progress {
+	return progress{UnapprovedPRs: p.UnapprovedPRs[:len(p.UnapprovedPRs):len(p.UnapprovedPRs)], Violations: p.Violations[:len(p.Violations):len(p.Violations)]}
+}
+
+// saveProgress records the page a repository was being checked at when the API call budget ran out,
+// with what was found on the earlier pages, so a resumed scan continues from that page
+func saveProgress(ctx context.Context, repository string, page int, found progress) {
+	if common.StoppedIn(ctx) != common.StopBudget {
+		return
+	}
+	if err := common.SaveCursor(ctx, repository, page, found); err != nil {
+		fmt.Printf("  Could not save progress of %s: %v\n", repository, err)
+	}
+}
+
 // isPRApproved checks if a specific PR has been approved
+// It also returns the latest approving review of each reviewer and the dismissed reviews, for the review rules
 // nolint:gocyclo // Contains necessary logic for handling various review states
-func isPRApproved(ctx context.Context, client common.GitHubClientInterface, owner, repo string, prNumber int, debugLogging bool) (bool, error) {
+func isPRApproved(ctx context.Context, client common.GitHubClientInterface, owner, repo string, prNumber int, debugLogging bool) (bool, []*github.PullRequestReview, []*github.PullRequestReview, error) {
 	reviews, _, err := client.ListPullRequestReviews(ctx, owner, repo, prNumber, nil)
 	if err != nil {
-		return false, err
+		return false, nil, nil, err
 	}
 
 	if debugLogging {
@@ -476,7 +1066,8 @@ func isPRApproved(ctx context.Context, client common.GitHubClientInterface, owne
 	}
 
 	// Track the latest review from each reviewer
-	latestReviewByReviewer := make(map[string]string)
+	latestReviewByReviewer := make(map[string]*github.PullRequestReview)
+	var dismissed []*github.PullRequestReview
 
 	// Process all reviews in order (GitHub returns them chronologically)
 	for _, review := range reviews {
@@ -493,29 +1084,36 @@ func isPRApproved(ctx context.Context, client common.GitHubClientInterface, owne
 			continue
 		}
 
+		// Dismissed reviews no longer count, the dismissal rule checks what they were
+		if state == "DISMISSED" {
+			dismissed = append(dismissed, review)
+			continue
+		}
+
 		// Only track reviews that represent a clear state (APPROVED or CHANGES_REQUESTED)
 		// Ignore COMMENTED reviews as they don't change approval status
 		if state == "APPROVED" || state == "CHANGES_REQUESTED" {
-			latestReviewByReviewer[reviewer] = state
+			latestReviewByReviewer[reviewer] = review
 		}
 	}
 
 	// Check if there's at least one approval and no pending requested changes
-	hasApproval := false
-	for reviewer, state := range latestReviewByReviewer {
-		if state == "APPROVED" {
-			hasApproval = true
+	var approvals []*github.PullRequestReview
+	for reviewer, review := range latestReviewByReviewer {
+		if review.GetState() == "APPROVED" {
+			approvals = append(approvals, review)
 			if debugLogging {
 				fmt.Printf("PR #%d: Has approval from %s\n", prNumber, reviewer)
 			}
-		} else if state == "CHANGES_REQUESTED" {
+		} else if review.GetState() == "CHANGES_REQUESTED" {
 			// If any reviewer's latest review is CHANGES_REQUESTED, PR is not approved
 			if debugLogging {
 				fmt.Printf("PR #%d: Changes requested by %s, PR not approved\n", prNumber, reviewer)
 			}
-			return false, nil
+			return false, nil, dismissed, nil
 		}
 	}
+	hasApproval := len(approvals) > 0
 
 	if debugLogging {
 		if hasApproval {
@@ -525,5 +1123,18 @@ func isPRApproved(ctx context.Context, client common.GitHubClientInterface, owne
 		}
 	}
 
-	return hasApproval, nil
+	// Order the approvals by when they were submitted, as maps have no order
+	sort.Slice(approvals, func(i, j int) bool {
+		return approvals[i].GetSubmittedAt().Before(approvals[j].GetSubmittedAt())
+	})
+
+	return hasApproval, approvals, dismissed, nil
+}
+
+// windowLocation returns the location time windows are aligned to, nil when they end now
+func (s *Service) windowLocation() *time.Location {
+	if s.windowsEndNow {
+		return nil
+	}
+	return s.Location
 }
@@ -0,0 +1,67 @@
+package prchecker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	"github.com/google/go-github/v45/github"
+)
+
+// teamApprovals returns the approvals given by members of the reviewer teams, all approvals without teams
+// Memberships are looked up through the membership cache, so each reviewer is looked up once per team
+func teamApprovals(ctx context.Context, client common.GitHubClientInterface, approvals []*github.PullRequestReview, teams []string, debugLogging bool) ([]*github.PullRequestReview, error) {
+	if len(teams) == 0 {
+		return approvals, nil
+	}
+
+	var counted []*github.PullRequestReview
+	for _, approval := range approvals {
+		approver := approval.GetUser().GetLogin()
+		member, err := teamMember(ctx, client, teams, approver)
+		if err != nil {
+			return nil, err
+		}
+		if member {
+			counted = append(counted, approval)
+		} else if debugLogging {
+			fmt.Printf("  Not counting the approval of %s, who is in none of the reviewer teams %s\n", approver, strings.Join(teams, ", "))
+		}
+	}
+	return counted, nil
+}
+
+// teamMember reports whether a user is a member of any of the teams, given as "org/team"
+func teamMember(ctx context.Context, client common.GitHubClientInterface, teams []string, user string) (bool, error) {
+	for _, team := range teams {
+		org, slug, ok := strings.Cut(team, "/")
+		if !ok {
+			continue
+		}
+		member, err := client.IsTeamMember(ctx, org, slug, user)
+		if err != nil {
+			return false, err
+		}
+		if member {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// outsideApprovals returns who approved a PR when none of its approvers is a member of the organization owning
+// the repository, such as outside collaborators, and empty otherwise
+// Memberships are looked up through the membership cache, and only until an approver is found to be a member
+func outsideApprovals(ctx context.Context, client common.GitHubClientInterface, org string, approvals []*github.PullRequestReview) (string, error) {
+	for _, approval := range approvals {
+		member, err := client.IsOrganizationMember(ctx, org, approval.GetUser().GetLogin())
+		if err != nil {
+			return "", err
+		}
+		if member {
+			return "", nil
+		}
+	}
+	return fmt.Sprintf("approved only by reviewers outside the %s organization (%s)", org, approverLogins(approvals)), nil
+}
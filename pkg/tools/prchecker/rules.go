@@ -0,0 +1,951 @@
+package prchecker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/policy"
+	"github.com/anupsv/git-monitoring/pkg/tools/codeowners"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	"github.com/google/go-github/v45/github"
+)
+
+// RuleUnapproved is the rule of findings of PRs merged without approval
+const RuleUnapproved = "unapproved"
+
+// Review rules merged PRs can break besides approval
+const (
+	RuleRubberStamp       = "rubber_stamp"       // Approved too soon after the PR was opened or last pushed to
+	RuleCommitterApproval = "committer_approval" // Approved only by reviewers who also authored or committed changes
+	RuleTemplate          = "template"           // Description with required template sections missing or empty
+	RuleTitle             = "title"              // Title not following the title convention, a low-severity finding
+	RuleTicket            = "ticket"             // No issue tracker key in the title or head branch
+	RuleDismissedReview   = "dismissed_review"   // Merged after a review requesting changes was dismissed
+	RuleStatusPoster      = "status_poster"      // Required status check passed by a poster not allowed to post it
+	RuleApprovals         = "approvals"          // Approved by fewer distinct reviewers than required
+	RuleCodeOwner         = "code_owner"         // Changed files owned in CODEOWNERS but approved by none of their owners
+	RuleStaleApproval     = "stale_approval"     // Approved before commits pushed since, so the merged code was not approved
+	RuleProtectionBypass  = "protection_bypass"  // Merged with fewer approvals than branch protection requires, by an admin bypassing it
+	RuleFailingChecks     = "failing_checks"     // Merged while required status checks were failing or missing
+	RuleMergeMethod       = "merge_method"       // Merged with a merge method the policy does not allow
+	RuleOutsideApproval   = "outside_approval"   // Approved only by reviewers outside the organization owning the repository
+	RuleMergeHours        = "merge_hours"        // Merged outside business hours, e.g. at night or on a weekend
+)
+
+// Violation is a merged PR that breaks a review rule other than approval
+type Violation struct {
+	PR     PR
+	Rule   string // Rule broken, e.g. RuleRubberStamp
+	Detail string // How the PR breaks the rule
+}
+
+// Rules are the review rules checked for merged PRs besides approval
+type Rules struct {
+	// Distinct reviewers who must approve merged PRs, 0 and 1 accept a single approval
+	RequiredApprovals int
+	// Approvals submitted sooner after the PR was opened or last pushed to are rubber stamps, 0 disables the rule
+	MinReviewTime time.Duration
+	// Flag PRs approved only by reviewers who authored, co-authored or committed commits of the PR
+	CommitterApprovals bool
+	// Require an approval by a code owner of each changed file with owners in the repository's CODEOWNERS file
+	CodeOwnerApproval bool
+	// Flag PRs merged after a review requesting changes was dismissed instead of approved by its reviewer
+	DismissedReviews bool
+	// Only dismissals this long or less before the merge are flagged, any dismissal when 0
+	DismissalWindow time.Duration
+	// Flag PRs whose last commit was pushed after the approvals, so they were approved on stale code
+	StaleApprovals bool
+	// Flag PRs merged with fewer approvals than the protection of their base branch requires
+	ProtectionBypasses bool
+	// Teams ("org/team") whose members' approvals are the only ones counted, all approvals count when empty
+	ReviewerTeams []string
+	// Headings of description template sections merged PRs must fill in, matched against each line of the description
+	RequiredSections []*regexp.Regexp
+	// Convention merged PR titles must match, e.g. Conventional Commits, nil disables the rule
+	TitlePattern *regexp.Regexp
+	// Name of the title convention in violation details, e.g. "conventional_commits", the pattern when empty
+	TitleConvention string
+	// Require an issue tracker key such as "ABC-123" in the title or head branch of merged PRs
+	RequireTicket bool
+	// Project keys a ticket reference must use, any uppercase key when empty
+	TicketKeys []string
+	// Globs of the paths merged PRs must change to be checked, e.g. "infra/**", all merged PRs when empty
+	Paths []string
+	// Apps and users allowed to pass required status checks, by check name or "*" for any check, nil disables the rule
+	StatusPosters map[string][]string
+	// Flag PRs merged while status checks required on their base branch were failing or missing on their last commit
+	FailingChecks bool
+	// Merge methods merged PRs may use, "merge", "squash" or "rebase", any when empty
+	MergeMethods []string
+	// Flag PRs approved only by reviewers who are not members of the organization owning the repository
+	OutsideApprovals bool
+	// Business hours PRs are expected to be merged in, nil disables the rule
+	MergeHours *MergeHours
+	// Globs of the base branches merged PRs are checked for, e.g. "release/*", all when empty
+	BaseBranches []string
+
+	requiredChecks  *requiredChecksCache  // Status checks required on base branches, shared by the repositories of a run
+	codeOwners      *codeOwnersCache      // CODEOWNERS rules of repositories, shared by the repositories of a run
+	requiredReviews *requiredReviewsCache // Approvals required on base branches, shared by the repositories of a run
+}
+
+// rulesFromConfig returns the review rules of the central configuration
+// Invalid section patterns are rejected when the configuration is validated, and logged and ignored here
+func rulesFromConfig(cfg *config.Config) Rules {
+	rules := Rules{
+		RequiredApprovals:  cfg.Monitors.PRChecker.RequiredApprovals,
+		MinReviewTime:      cfg.Monitors.PRChecker.MinReviewTime.Duration,
+		CommitterApprovals: cfg.Monitors.PRChecker.FlagCommitterApprovals,
+		DismissedReviews:   cfg.Monitors.PRChecker.FlagDismissedReviews,
+		DismissalWindow:    cfg.Monitors.PRChecker.DismissalWindow.Duration,
+		StaleApprovals:     cfg.Monitors.PRChecker.FlagStaleApprovals,
+		OutsideApprovals:   cfg.Monitors.PRChecker.FlagOutsideApprovals,
+		MergeHours:         mergeHoursFromConfig(cfg),
+		BaseBranches:       cfg.Monitors.PRChecker.BaseBranches,
+		ReviewerTeams:      cfg.Monitors.PRChecker.RequiredReviewerTeams,
+	}
+	for _, pattern := range cfg.Monitors.PRChecker.RequiredSections {
+		section, err := regexp.Compile(pattern)
+		if err != nil {
+			log.Printf("Ignoring invalid required section pattern %q: %v", pattern, err)
+			continue
+		}
+		rules.RequiredSections = append(rules.RequiredSections, section)
+	}
+	rules.RequireTicket = cfg.Monitors.PRChecker.RequireTicket
+	if cfg.Monitors.PRChecker.FlagProtectionBypasses {
+		rules.ProtectionBypasses = true
+		rules.requiredReviews = &requiredReviewsCache{counts: make(map[string]int)}
+	}
+	if cfg.Monitors.PRChecker.RequireCodeOwners {
+		rules.CodeOwnerApproval = true
+		rules.codeOwners = &codeOwnersCache{rules: make(map[string][]codeowners.Rule)}
+	}
+	rules.FailingChecks = cfg.Monitors.PRChecker.FlagFailingChecks
+	if posters := cfg.Monitors.PRChecker.StatusPosters; len(posters) > 0 {
+		rules.StatusPosters = posters
+	}
+	if rules.FailingChecks || len(rules.StatusPosters) > 0 {
+		rules.requiredChecks = &requiredChecksCache{checks: make(map[string][]*github.RequiredStatusCheck)}
+	}
+	rules.TicketKeys = cfg.Monitors.PRChecker.TicketKeys
+	rules.MergeMethods = cfg.Monitors.PRChecker.AllowedMergeMethods
+	if preset := cfg.Monitors.PRChecker.TitlePreset; preset != "" {
+		if title, ok := titlePresets[preset]; ok {
+			rules.TitlePattern = title
+			rules.TitleConvention = preset
+		} else {
+			log.Printf("Ignoring unknown title preset %q", preset)
+		}
+	}
+	if pattern := cfg.Monitors.PRChecker.TitlePattern; pattern != "" {
+		title, err := regexp.Compile(pattern)
+		if err != nil {
+			log.Printf("Ignoring invalid title pattern %q: %v", pattern, err)
+		} else {
+			rules.TitlePattern = title
+		}
+	}
+	return rules
+}
+
+// rulesFor returns the review rules of a repository, resolved in order of precedence:
+//  1. the central rules
+//  2. the repository's settings in the central configuration, its repo_overrides entry, repo_ticket_keys, repo_paths
+//     and repo_reviewer_teams, which replace the central rules
+//  3. the repository's policy file, which can only tighten the rules resolved so far
+func (s *Service) rulesFor(ctx context.Context, client common.GitHubClientInterface, repository string) Rules {
+	rules := s.rules
+	if approvals := s.overrides[strings.ToLower(repository)].requiredApprovals; approvals != nil {
+		rules.RequiredApprovals = *approvals
+	}
+	for repo, keys := range s.repoTicketKeys {
+		if strings.EqualFold(repo, repository) {
+			rules.TicketKeys = keys
+		}
+	}
+	for repo, paths := range s.repoPaths {
+		if strings.EqualFold(repo, repository) {
+			rules.Paths = paths
+		}
+	}
+	for repo, teams := range s.repoReviewerTeams {
+		if strings.EqualFold(repo, repository) {
+			rules.ReviewerTeams = teams
+		}
+	}
+	if !s.repoPolicy.Enabled {
+		return rules
+	}
+	return tightenRules(rules, policy.NewLoader(client, s.repoPolicy.Path).Load(ctx, repository).PRChecker, s.repoPolicy, repository)
+}
+
+// tightenRules applies the policy file of a repository to its rules, never loosening them: base branches checked are
+// only added, required approvals only raised up to max_required_approvals, and the min review time cannot go below
+// min_review_time_floor, or the rules' own when no floor is set. Overrides beyond these are logged and limited
+func tightenRules(rules Rules, p policy.PRCheckerPolicy, caps config.RepoPolicyConfig, repository string) Rules {
+	if override := p.MinReviewTime.Duration; override > 0 {
+		floor := caps.MinReviewTimeFloor.Duration
+		if floor == 0 {
+			floor = rules.MinReviewTime
+		}
+		rules.MinReviewTime = max(override, floor)
+		if rules.MinReviewTime != override {
+			log.Printf("Policy of %s sets a min review time of %v, raised to %v", repository, override, rules.MinReviewTime)
+		}
+	}
+
+	if override := p.RequiredApprovals; override > 0 {
+		limit := caps.MaxRequiredApprovals
+		if limit == 0 {
+			limit = rules.RequiredApprovals
+		}
+		rules.RequiredApprovals = max(policy.Limit(rules.RequiredApprovals, override, limit), rules.RequiredApprovals)
+		if rules.RequiredApprovals != override {
+			log.Printf("Policy of %s sets %d required approvals, limited to %d", repository, override, rules.RequiredApprovals)
+		}
+	}
+
+	if len(p.BaseBranches) > 0 {
+		// Without base branches every branch is checked already, and a policy cannot narrow them
+		if len(rules.BaseBranches) == 0 {
+			log.Printf("Policy of %s sets base branches, ignored as PRs merged into every branch are checked", repository)
+		} else {
+			rules.BaseBranches = append(append([]string{}, rules.BaseBranches...), p.BaseBranches...)
+		}
+	}
+	return rules
+}
+
+// mergedPR is a PR merged within the time window, with what the review rules need to know about it
+type mergedPR struct {
+	PR
+	Body       string // Description of the PR
+	HeadBranch string // Branch the PR was merged from, empty when not known yet, e.g. for search results
+	HeadSHA    string // Last commit of the PR, known with the head branch
+	MergeSHA   string // Commit the PR was merged as, known with the head branch
+	BaseBranch string // Branch the PR was merged into, known with the head branch
+	MergedBy   string // Who merged the PR, empty until fetched, e.g. for listed PRs
+	Fetched    bool   // Whether the full PR was fetched, so who merged it is known
+	CreatedAt  time.Time
+	MergedAt   time.Time
+	Approved   bool
+	Approvals  []*github.PullRequestReview // Latest approving review of each reviewer, oldest first
+	Dismissed  []*github.PullRequestReview // Dismissed reviews, whatever they were before
+	Files      []string                    // Changed files, nil until a rule needs them
+}
+
+// checkRules returns the review rules a merged PR breaks
+// The commits of the PR are fetched at most once, and only when a rule needs them
+// What the rules fetch about the PR, such as who merged it, is kept in pr
+func checkRules(ctx context.Context, client common.GitHubClientInterface, owner, repo string, pr *mergedPR, rules Rules) ([]Violation, error) {
+	var violations []Violation
+
+	// The PR is fetched at most once, and only when a rule needs what listed or searched PRs come without
+	fetch := func() error {
+		full, err := client.GetPullRequest(ctx, owner, repo, pr.Number)
+		if err != nil {
+			return err
+		}
+		pr.HeadBranch = full.GetHead().GetRef()
+		pr.HeadSHA = full.GetHead().GetSHA()
+		pr.MergeSHA = full.GetMergeCommitSHA()
+		pr.BaseBranch = full.GetBase().GetRef()
+		pr.MergedBy = full.GetMergedBy().GetLogin()
+		pr.Fetched = true
+		return nil
+	}
+	// Searched PRs come without their branches
+	withHead := func() (mergedPR, error) {
+		if pr.HeadBranch == "" && !pr.Fetched {
+			if err := fetch(); err != nil {
+				return *pr, err
+			}
+		}
+		return *pr, nil
+	}
+	// Listed PRs come without who merged them
+	withMerger := func() (string, error) {
+		if pr.MergedBy == "" && !pr.Fetched {
+			if err := fetch(); err != nil {
+				return "", err
+			}
+		}
+		return pr.MergedBy, nil
+	}
+
+	if len(rules.RequiredSections) > 0 {
+		if detail := templateSections(pr.Body, rules.RequiredSections); detail != "" {
+			violations = append(violations, Violation{PR: pr.PR, Rule: RuleTemplate, Detail: detail})
+		}
+	}
+
+	if rules.TitlePattern != nil && !rules.TitlePattern.MatchString(pr.Title) {
+		convention := rules.TitleConvention
+		if convention == "" {
+			convention = rules.TitlePattern.String()
+		}
+		violations = append(violations, Violation{PR: pr.PR, Rule: RuleTitle,
+			Detail: fmt.Sprintf("titled %q, not matching the title convention %s", pr.Title, convention)})
+	}
+
+	if rules.MergeHours != nil {
+		if detail := outsideMergeHours(*pr, *rules.MergeHours); detail != "" {
+			violations = append(violations, Violation{PR: pr.PR, Rule: RuleMergeHours, Detail: detail})
+		}
+	}
+
+	if rules.RequireTicket {
+		detail, err := missingTicket(*pr, rules.TicketKeys, withHead)
+		if err != nil {
+			return nil, err
+		}
+		if detail != "" {
+			violations = append(violations, Violation{PR: pr.PR, Rule: RuleTicket, Detail: detail})
+		}
+	}
+
+	var commits []*github.RepositoryCommit
+	fetched := false
+	listCommits := func() ([]*github.RepositoryCommit, error) {
+		if !fetched {
+			var err error
+			commits, err = client.ListPullRequestCommits(ctx, owner, repo, pr.Number)
+			if err != nil {
+				return nil, err
+			}
+			fetched = true
+		}
+		return commits, nil
+	}
+
+	if len(rules.MergeMethods) > 0 {
+		detail, err := disallowedMergeMethod(ctx, client, owner, repo, rules.MergeMethods, withHead, withMerger, listCommits)
+		if err != nil {
+			return nil, err
+		}
+		if detail != "" {
+			violations = append(violations, Violation{PR: pr.PR, Rule: RuleMergeMethod, Detail: detail})
+		}
+	}
+
+	if rules.DismissedReviews {
+		detail, err := dismissedChangeRequests(ctx, client, owner, repo, *pr, rules.DismissalWindow)
+		if err != nil {
+			return nil, err
+		}
+		if detail != "" {
+			violations = append(violations, Violation{PR: pr.PR, Rule: RuleDismissedReview, Detail: detail})
+		}
+	}
+
+	// The statuses and check runs of the merged commit are fetched at most once, for both rules about required checks
+	var fetchedChecks *commitChecks
+	withChecks := func(sha string) (*commitChecks, error) {
+		if fetchedChecks == nil {
+			checks, err := listCommitChecks(ctx, client, owner, repo, sha)
+			if err != nil {
+				return nil, err
+			}
+			fetchedChecks = checks
+		}
+		return fetchedChecks, nil
+	}
+
+	if len(rules.StatusPosters) > 0 {
+		detail, err := spoofedStatusChecks(ctx, client, owner, repo, rules, withHead, withChecks)
+		if err != nil {
+			return nil, err
+		}
+		if detail != "" {
+			violations = append(violations, Violation{PR: pr.PR, Rule: RuleStatusPoster, Detail: detail})
+		}
+	}
+
+	if rules.FailingChecks {
+		detail, err := failingStatusChecks(ctx, client, owner, repo, rules, withHead, withChecks)
+		if err != nil {
+			return nil, err
+		}
+		if detail != "" {
+			violations = append(violations, Violation{PR: pr.PR, Rule: RuleFailingChecks, Detail: detail})
+		}
+	}
+
+	// Unapproved PRs bypassed protection too when their base branch requires approvals
+	if rules.ProtectionBypasses {
+		detail, err := protectionBypass(ctx, client, owner, repo, rules, withHead, withMerger)
+		if err != nil {
+			return nil, err
+		}
+		if detail != "" {
+			violations = append(violations, Violation{PR: pr.PR, Rule: RuleProtectionBypass, Detail: detail})
+		}
+	}
+
+	// The remaining rules are about the approvals, unapproved PRs are already reported as such
+	if !pr.Approved {
+		return violations, nil
+	}
+
+	if len(pr.Approvals) < rules.RequiredApprovals {
+		violations = append(violations, Violation{PR: pr.PR, Rule: RuleApprovals,
+			Detail: fmt.Sprintf("approved by %d of the %d required reviewers (%s)", len(pr.Approvals), rules.RequiredApprovals, approverLogins(pr.Approvals))})
+	}
+
+	if rules.OutsideApprovals {
+		detail, err := outsideApprovals(ctx, client, owner, pr.Approvals)
+		if err != nil {
+			return nil, err
+		}
+		if detail != "" {
+			violations = append(violations, Violation{PR: pr.PR, Rule: RuleOutsideApproval, Detail: detail})
+		}
+	}
+
+	if rules.CodeOwnerApproval {
+		detail, err := missingCodeOwnerApproval(ctx, client, owner, repo, *pr, rules.codeOwners)
+		if err != nil {
+			return nil, err
+		}
+		if detail != "" {
+			violations = append(violations, Violation{PR: pr.PR, Rule: RuleCodeOwner, Detail: detail})
+		}
+	}
+
+	if rules.MinReviewTime > 0 {
+		detail, err := rubberStamp(*pr, rules.MinReviewTime, listCommits)
+		if err != nil {
+			return nil, err
+		}
+		if detail != "" {
+			violations = append(violations, Violation{PR: pr.PR, Rule: RuleRubberStamp, Detail: detail})
+		}
+	}
+
+	if rules.CommitterApprovals {
+		commits, err := listCommits()
+		if err != nil {
+			return nil, err
+		}
+		if detail := committerApproval(*pr, commits); detail != "" {
+			violations = append(violations, Violation{PR: pr.PR, Rule: RuleCommitterApproval, Detail: detail})
+		}
+	}
+
+	if rules.StaleApprovals {
+		commits, err := listCommits()
+		if err != nil {
+			return nil, err
+		}
+		if detail := staleApproval(*pr, commits, rules.RequiredApprovals); detail != "" {
+			violations = append(violations, Violation{PR: pr.PR, Rule: RuleStaleApproval, Detail: detail})
+		}
+	}
+
+	return violations, nil
+}
+
+// rubberStamp returns how fast a PR was approved when every approval was submitted sooner than minReviewTime
+// after the PR was opened or after its last commit before the approval, whichever is later, and empty otherwise
+// The commits are only fetched when an approval came later than minReviewTime after the PR was opened
+func rubberStamp(pr mergedPR, minReviewTime time.Duration, listCommits func() ([]*github.RepositoryCommit, error)) (string, error) {
+	reviewedAfterOpening := false
+	for _, approval := range pr.Approvals {
+		if approval.GetSubmittedAt().Sub(pr.CreatedAt) >= minReviewTime {
+			reviewedAfterOpening = true
+			break
+		}
+	}
+
+	var commits []*github.RepositoryCommit
+	if reviewedAfterOpening {
+		var err error
+		commits, err = listCommits()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var slowest time.Duration
+	for _, approval := range pr.Approvals {
+		submitted := approval.GetSubmittedAt()
+		lastPush := pr.CreatedAt
+		for _, commit := range commits {
+			if pushed := commit.GetCommit().GetCommitter().GetDate(); pushed.After(lastPush) && !pushed.After(submitted) {
+				lastPush = pushed
+			}
+		}
+
+		took := submitted.Sub(lastPush)
+		if took >= minReviewTime {
+			return "", nil
+		}
+		slowest = max(slowest, took)
+	}
+
+	return fmt.Sprintf("approved %v after it was opened or last pushed to, under the minimum review time of %v",
+		slowest.Round(time.Second), minReviewTime), nil
+}
+
+// dismissedChangeRequests returns who dismissed reviews requesting changes of a PR before it was merged, and how long
+// before, when their reviewers did not approve the PR afterwards, and empty otherwise
+// With a window, only dismissals within the window before the merge are returned, dismissing objections just before
+// merging being the common way around them
+// Dismissed reviews no longer tell what they were, so the events of the PR are only fetched when it has some
+func dismissedChangeRequests(ctx context.Context, client common.GitHubClientInterface, owner, repo string, pr mergedPR, window time.Duration) (string, error) {
+	if len(pr.Dismissed) == 0 {
+		return "", nil
+	}
+
+	reviewers := make(map[int64]string, len(pr.Dismissed))
+	for _, review := range pr.Dismissed {
+		reviewers[review.GetID()] = review.GetUser().GetLogin()
+	}
+
+	events, err := client.ListIssueEvents(ctx, owner, repo, pr.Number)
+	if err != nil {
+		return "", err
+	}
+
+	var dismissals []string
+	for _, event := range events {
+		if event.GetEvent() != "review_dismissed" || event.GetDismissedReview().GetState() != "changes_requested" {
+			continue
+		}
+		dismissedAt := event.GetCreatedAt()
+		if !pr.MergedAt.IsZero() && dismissedAt.After(pr.MergedAt) {
+			continue
+		}
+		if window > 0 && !pr.MergedAt.IsZero() && pr.MergedAt.Sub(dismissedAt) > window {
+			continue
+		}
+
+		reviewer, ok := reviewers[event.GetDismissedReview().GetReviewID()]
+		if !ok {
+			continue
+		}
+
+		// A later approval of the reviewer resolves the requested changes
+		resolved := false
+		for _, approval := range pr.Approvals {
+			if strings.EqualFold(approval.GetUser().GetLogin(), reviewer) && approval.GetSubmittedAt().After(dismissedAt) {
+				resolved = true
+				break
+			}
+		}
+		if resolved {
+			continue
+		}
+
+		dismissal := fmt.Sprintf("changes requested by %s were dismissed by %s", reviewer, event.GetActor().GetLogin())
+		if !pr.MergedAt.IsZero() {
+			dismissal += fmt.Sprintf(" %s before the merge", latency(pr.MergedAt.Sub(dismissedAt)))
+		}
+		if message := event.GetDismissedReview().GetDismissalMessage(); message != "" {
+			dismissal += fmt.Sprintf(" (%q)", message)
+		}
+		dismissals = append(dismissals, dismissal)
+	}
+
+	return strings.Join(dismissals, "; "), nil
+}
+
+// staleApproval returns who approved a PR before its last commit was pushed, when too few approvals were given
+// after it, and empty otherwise. An approval is current when it was submitted after the last commit's committer
+// date or on the last commit itself, and a PR needs as many current approvals as it requires, at least one
+// PRs with fewer approvals than required are already reported, so they only need current ones for their approvals
+func staleApproval(pr mergedPR, commits []*github.RepositoryCommit, requiredApprovals int) string {
+	if len(commits) == 0 {
+		return ""
+	}
+	last := commits[len(commits)-1]
+	pushed := last.GetCommit().GetCommitter().GetDate()
+
+	var stale []*github.PullRequestReview
+	for _, approval := range pr.Approvals {
+		if approval.GetCommitID() != last.GetSHA() && approval.GetSubmittedAt().Before(pushed) {
+			stale = append(stale, approval)
+		}
+	}
+	needed := min(max(requiredApprovals, 1), len(pr.Approvals))
+	if len(pr.Approvals)-len(stale) >= needed {
+		return ""
+	}
+
+	latest := stale[len(stale)-1].GetSubmittedAt()
+	return fmt.Sprintf("approved on stale code: approved by %s before the last commit %s, pushed %v later",
+		approverLogins(stale), shortSHA(last.GetSHA()), pushed.Sub(latest).Round(time.Second))
+}
+
+// shortSHA abbreviates a commit SHA the way GitHub shows it
+func shortSHA(sha string) string {
+	return sha[:min(len(sha), 7)]
+}
+
+// committerApproval returns who approved a PR when every approver also authored, co-authored or committed
+// one of its commits, and empty otherwise
+func committerApproval(pr mergedPR, commits []*github.RepositoryCommit) string {
+	contributors := commitContributors(commits)
+
+	var approvers []string
+	for _, approval := range pr.Approvals {
+		approver := approval.GetUser().GetLogin()
+		if !contributors[strings.ToLower(approver)] {
+			return ""
+		}
+		approvers = append(approvers, approver)
+	}
+	if len(approvers) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("approved only by %s, who also contributed commits", strings.Join(approvers, ", "))
+}
+
+// webFlowLogin is the committer of commits made on github.com, e.g. with the "Update branch" button
+const webFlowLogin = "web-flow"
+
+// coAuthorPattern matches the email of a Co-authored-by trailer in a commit message
+var coAuthorPattern = regexp.MustCompile(`(?im)^co-authored-by:.*<([^>]+)>\s*$`)
+
+// noreplyPattern matches GitHub noreply emails, "login@users.noreply.github.com" with an optional "id+" prefix
+var noreplyPattern = regexp.MustCompile(`(?i)^(?:\d+\+)?([a-z0-9-]+)@users\.noreply\.github\.com$`)
+
+// commitContributors returns the lowercase logins of the authors, committers and co-authors of commits
+// Co-authors are only known by email, so those with a GitHub noreply email are recognized
+func commitContributors(commits []*github.RepositoryCommit) map[string]bool {
+	contributors := make(map[string]bool)
+	for _, commit := range commits {
+		if login := commit.GetAuthor().GetLogin(); login != "" {
+			contributors[strings.ToLower(login)] = true
+		}
+		if login := commit.GetCommitter().GetLogin(); login != "" && login != webFlowLogin {
+			contributors[strings.ToLower(login)] = true
+		}
+		for _, match := range coAuthorPattern.FindAllStringSubmatch(commit.GetCommit().GetMessage(), -1) {
+			if login := noreplyPattern.FindStringSubmatch(strings.TrimSpace(match[1])); login != nil {
+				contributors[strings.ToLower(login[1])] = true
+			}
+		}
+	}
+	return contributors
+}
+
+// htmlCommentPattern matches HTML comments, which templates use for instructions that do not count as content
+var htmlCommentPattern = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+// headingPattern matches a markdown heading line, which ends the section before it
+var headingPattern = regexp.MustCompile(`^ {0,3}#{1,6}(\s|$)`)
+
+// templateSections returns which required sections a PR description is missing or left empty, and empty otherwise
+// A section is the first line matching its pattern, and its content the lines up to the next heading
+func templateSections(body string, required []*regexp.Regexp) string {
+	lines := strings.Split(htmlCommentPattern.ReplaceAllString(body, ""), "\n")
+
+	var missing, empty []string
+	for _, section := range required {
+		start := -1
+		for i, line := range lines {
+			if section.MatchString(strings.TrimRight(line, "\r")) {
+				start = i
+				break
+			}
+		}
+		if start < 0 {
+			missing = append(missing, section.String())
+			continue
+		}
+
+		filled := false
+		for _, line := range lines[start+1:] {
+			if headingPattern.MatchString(line) {
+				break
+			}
+			if strings.TrimSpace(line) != "" {
+				filled = true
+				break
+			}
+		}
+		if !filled {
+			empty = append(empty, section.String())
+		}
+	}
+
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, "missing "+strings.Join(missing, ", "))
+	}
+	if len(empty) > 0 {
+		problems = append(problems, "empty "+strings.Join(empty, ", "))
+	}
+	if len(problems) == 0 {
+		return ""
+	}
+	return "description template sections " + strings.Join(problems, "; ")
+}
+
+// anyTicketPattern matches a ticket reference with any uppercase project key, e.g. "ABC-123"
+var anyTicketPattern = regexp.MustCompile(`(^|[^A-Za-z0-9])[A-Z][A-Z0-9]+-[0-9]+([^0-9]|$)`)
+
+// ticketPattern returns the pattern of ticket references with one of the keys, case-insensitively
+// as branch names are often lowercase, or of references with any uppercase key when there are none
+func ticketPattern(keys []string) *regexp.Regexp {
+	if len(keys) == 0 {
+		return anyTicketPattern
+	}
+	quoted := make([]string, 0, len(keys))
+	for _, key := range keys {
+		quoted = append(quoted, regexp.QuoteMeta(key))
+	}
+	return regexp.MustCompile(`(?i)(^|[^a-z0-9])(` + strings.Join(quoted, "|") + `)-[0-9]+([^0-9]|$)`)
+}
+
+// missingTicket returns why a PR is untracked when neither its title nor its head branch references a ticket
+// with one of the keys, and empty otherwise. The head branch of searched PRs is only fetched when the title
+// has no reference
+func missingTicket(pr mergedPR, keys []string, withHead func() (mergedPR, error)) (string, error) {
+	pattern := ticketPattern(keys)
+	if pattern.MatchString(pr.Title) {
+		return "", nil
+	}
+
+	pr, err := withHead()
+	if err != nil {
+		return "", err
+	}
+	branch := pr.HeadBranch
+	if pattern.MatchString(branch) {
+		return "", nil
+	}
+
+	expected := "an issue tracker key"
+	if len(keys) > 0 {
+		expected = "a ticket of " + strings.Join(keys, ", ")
+	}
+	return fmt.Sprintf("references %s in neither its title nor its branch %q", expected, branch), nil
+}
+
+// requiredChecksCache caches the status checks required on base branches by "owner/repo:branch",
+// as the merged PRs of a repository mostly share their base branch
+type requiredChecksCache struct {
+	mu     sync.Mutex
+	checks map[string][]*github.RequiredStatusCheck
+}
+
+// get returns the status checks required on a branch, fetching them on first use
+func (c *requiredChecksCache) get(ctx context.Context, client common.GitHubClientInterface, owner, repo, branch string) ([]*github.RequiredStatusCheck, error) {
+	key := owner + "/" + repo + ":" + branch
+	c.mu.Lock()
+	checks, ok := c.checks[key]
+	c.mu.Unlock()
+	if ok {
+		return checks, nil
+	}
+
+	checks, err := client.GetRequiredStatusChecks(ctx, owner, repo, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.checks[key] = checks
+	c.mu.Unlock()
+	return checks, nil
+}
+
+// passingConclusions are the check run conclusions that satisfy a required check
+var passingConclusions = map[string]bool{"success": true, "neutral": true, "skipped": true}
+
+// commitChecks are the statuses and check runs of a commit
+type commitChecks struct {
+	latest map[string]*github.RepoStatus // Latest status of each check, by name
+	runs   []*github.CheckRun
+}
+
+// listCommitChecks fetches the statuses and check runs of a commit
+func listCommitChecks(ctx context.Context, client common.GitHubClientInterface, owner, repo, sha string) (*commitChecks, error) {
+	statuses, err := client.ListCommitStatuses(ctx, owner, repo, sha)
+	if err != nil {
+		return nil, err
+	}
+	runs, err := client.ListCheckRuns(ctx, owner, repo, sha)
+	if err != nil {
+		return nil, err
+	}
+
+	// Statuses are listed newest first, and only the latest status of a check counts
+	latest := make(map[string]*github.RepoStatus)
+	for _, status := range statuses {
+		if _, ok := latest[status.GetContext()]; !ok {
+			latest[status.GetContext()] = status
+		}
+	}
+	return &commitChecks{latest: latest, runs: runs}, nil
+}
+
+// posterName normalizes the login of a status creator or the slug of a check run app, so "ci-bot[bot]"
+// posting statuses and the "ci-bot" app creating check runs are allowed by the same name
+func posterName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), "[bot]")
+}
+
+// allowedPosters returns the normalized posters allowed to pass a required check, nil when the check has no allowlist
+func allowedPosters(posters map[string][]string, check string) map[string]bool {
+	names, ok := posters[check]
+	if !ok {
+		names, ok = posters["*"]
+	}
+	if !ok {
+		return nil
+	}
+
+	allowed := make(map[string]bool, len(names))
+	for _, name := range names {
+		allowed[posterName(name)] = true
+	}
+	return allowed
+}
+
+// spoofedStatusChecks returns which required checks of a PR's base branch were passed on its head commit by
+// statuses or check runs from posters not allowed to post them, and empty otherwise. Anyone with write access
+// can post a passing status with any name, faking green CI for a required check
+// Statuses and check runs are only fetched when the base branch requires checks with allowed posters
+func spoofedStatusChecks(ctx context.Context, client common.GitHubClientInterface, owner, repo string, rules Rules, withHead func() (mergedPR, error), withChecks func(sha string) (*commitChecks, error)) (string, error) {
+	pr, err := withHead()
+	if err != nil {
+		return "", err
+	}
+	if pr.BaseBranch == "" || pr.HeadSHA == "" {
+		return "", nil
+	}
+
+	required, err := rules.requiredChecks.get(ctx, client, owner, repo, pr.BaseBranch)
+	if err != nil {
+		return "", err
+	}
+
+	allowed := make(map[string]map[string]bool)
+	for _, check := range required {
+		if posters := allowedPosters(rules.StatusPosters, check.Context); posters != nil {
+			allowed[check.Context] = posters
+		}
+	}
+	if len(allowed) == 0 {
+		return "", nil
+	}
+
+	checks, err := withChecks(pr.HeadSHA)
+	if err != nil {
+		return "", err
+	}
+
+	var spoofed []string
+	for _, check := range required {
+		posters, ok := allowed[check.Context]
+		if !ok {
+			continue
+		}
+
+		if status, ok := checks.latest[check.Context]; ok && status.GetState() == "success" {
+			if poster := status.GetCreator().GetLogin(); !posters[posterName(poster)] {
+				spoofed = append(spoofed, fmt.Sprintf("%q passed by a status from %s", check.Context, unknownPoster(poster)))
+			}
+		}
+		for _, run := range checks.runs {
+			if run.GetName() != check.Context || !passingConclusions[run.GetConclusion()] {
+				continue
+			}
+			if poster := run.GetApp().GetSlug(); !posters[posterName(poster)] {
+				spoofed = append(spoofed, fmt.Sprintf("%q passed by a check run from %s", check.Context, unknownPoster(poster)))
+			}
+		}
+	}
+
+	if len(spoofed) == 0 {
+		return "", nil
+	}
+	return "required check " + strings.Join(spoofed, "; ") + ", not allowed to pass it", nil
+}
+
+// failingStatusChecks returns which checks required on a PR's base branch were failing or missing when it merged,
+// and empty otherwise. Checks are read from the PR's head commit, which branch protection evaluates, as the merge
+// commit of a squash or rebase merge is created at merge time and never has checks of its own
+// A required check passes with a successful latest status or a passing check run
+// Statuses and check runs are only fetched when the base branch requires checks
+func failingStatusChecks(ctx context.Context, client common.GitHubClientInterface, owner, repo string, rules Rules, withHead func() (mergedPR, error), withChecks func(sha string) (*commitChecks, error)) (string, error) {
+	pr, err := withHead()
+	if err != nil {
+		return "", err
+	}
+	if pr.BaseBranch == "" || pr.HeadSHA == "" {
+		return "", nil
+	}
+
+	required, err := rules.requiredChecks.get(ctx, client, owner, repo, pr.BaseBranch)
+	if err != nil || len(required) == 0 {
+		return "", err
+	}
+	checks, err := withChecks(pr.HeadSHA)
+	if err != nil {
+		return "", err
+	}
+
+	var failing, missing []string
+	for _, check := range required {
+		passed, reported := false, false
+		if status, ok := checks.latest[check.Context]; ok {
+			reported = true
+			passed = status.GetState() == "success"
+		}
+		for _, run := range checks.runs {
+			if run.GetName() == check.Context {
+				reported = true
+				passed = passed || passingConclusions[run.GetConclusion()]
+			}
+		}
+
+		switch {
+		case passed:
+		case reported:
+			failing = append(failing, fmt.Sprintf("%q", check.Context))
+		default:
+			missing = append(missing, fmt.Sprintf("%q", check.Context))
+		}
+	}
+
+	var details []string
+	if len(failing) > 0 {
+		details = append(details, "failing required checks "+strings.Join(failing, ", "))
+	}
+	if len(missing) > 0 {
+		details = append(details, "missing required checks "+strings.Join(missing, ", "))
+	}
+	if len(details) == 0 {
+		return "", nil
+	}
+	return "merged with " + strings.Join(details, " and "), nil
+}
+
+// unknownPoster names a poster GitHub did not tell
+func unknownPoster(poster string) string {
+	if poster == "" {
+		return "an unknown poster"
+	}
+	return poster
+}
+
+// approverLogins returns the logins of the reviewers of approvals
+func approverLogins(approvals []*github.PullRequestReview) string {
+	logins := make([]string, 0, len(approvals))
+	for _, approval := range approvals {
+		logins = append(logins, approval.GetUser().GetLogin())
+	}
+	return strings.Join(logins, ", ")
+}
@@ -0,0 +1,244 @@
+package prchecker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	"github.com/google/go-github/v45/github"
+)
+
+// minSearchPeriod is the shortest period searched for merged PRs. Periods with more merges than the search API
+// returns are split in two until they are this short, below which the results are reported as incomplete
+const minSearchPeriod = time.Minute
+
+// checkSearchedRepositories checks the repositories for unapproved PRs found with the search API:
+// the PRs merged across the organization within the time window are found in a few requests,
+// and only the reviews of those in the repositories are fetched
+func (s *Service) checkSearchedRepositories(ctx context.Context, cfg *config.Config, repositories []string) []Result {
+	org := cfg.Monitors.PRChecker.Organization
+	if reason := common.StoppedIn(ctx); reason != "" {
+		common.Skip(ctx, "org:"+org, reason)
+		return nil
+	}
+
+	client := s.NewClient(ctx, cfg.GitHub.Token)
+	now := time.Now()
+	// The search spans the longest time window, PRs of repositories with shorter ones are dropped afterwards
+	cutoffTime := common.WindowStart(now, s.longestTimeWindow(cfg.Monitors.PRChecker.TimeWindow.Duration), s.windowLocation())
+
+	fmt.Printf("Searching PRs of organization '%s' merged since %s...\n", org, common.LocalTime(cutoffTime, s.Location).Format(time.RFC3339))
+	merged, err := searchMergedPRs(ctx, client, org, cutoffTime, now)
+	if err != nil && common.StoppedIn(ctx) == common.StopBudget {
+		common.Skip(ctx, "org:"+org, common.StopBudget)
+		return nil
+	}
+	if err != nil {
+		return []Result{
+			{
+				Repository: "org:" + org,
+				Error:      fmt.Errorf("failed to search merged pull requests: %v", err),
+			},
+		}
+	}
+
+	// Group the merged PRs by repository
+	byRepository := make(map[string][]*github.Issue)
+	for _, pr := range merged {
+		repository := searchedRepository(pr)
+		byRepository[strings.ToLower(repository)] = append(byRepository[strings.ToLower(repository)], pr)
+	}
+	fmt.Printf("Found %d merged PRs in %d repositories\n", len(merged), len(byRepository))
+
+	results := make([]Result, 0, len(repositories))
+	for i, repository := range repositories {
+		if common.SkipIfStopped(ctx, repository) {
+			continue
+		}
+		prs := byRepository[strings.ToLower(repository)]
+		// Repositories with a shorter window, or one ending before now (see common.WindowEnd), keep fewer PRs
+		window := s.timeWindow(repository, cfg.Monitors.PRChecker.TimeWindow.Duration)
+		if since, until := common.WindowStart(now, window, s.windowLocation()), common.WindowEnd(now, window, s.windowLocation()); since.After(cutoffTime) || until.Before(now) {
+			prs = mergedWithin(prs, since, until)
+		}
+		if len(prs) > 0 {
+			fmt.Printf("[%d/%d] Checking %d merged PRs of repository: %s\n", i+1, len(repositories), len(prs), repository)
+		}
+
+		result := s.checkSearchedPRs(ctx, client, repository, prs, cfg.Monitors.PRChecker.DebugLogging)
+		if common.SkipIfBudgetExceeded(ctx, repository, result.Error) {
+			continue
+		}
+		results = append(results, result)
+	}
+	fmt.Printf("Completed checking %d of %d repositories\n", len(results), len(repositories))
+
+	return results
+}
+
+// checkSearchedPRs checks the approval of the merged PRs of a repository found with the search API
+func (s *Service) checkSearchedPRs(ctx context.Context, client common.GitHubClientInterface, repository string, prs []*github.Issue, debugLogging bool) Result {
+	result := Result{Repository: repository, UnapprovedPRs: []PR{}}
+
+	owner, repo, ok := common.ParseRepository(repository)
+	if !ok {
+		result.Error = fmt.Errorf("invalid repository format, expected 'owner/repo'")
+		return result
+	}
+	if len(prs) == 0 {
+		return result
+	}
+
+	rules := s.rulesFor(ctx, client, repository)
+	for _, pr := range prs {
+		// PRs of excluded authors, such as dependency update bots, are not checked
+		if s.excludedAuthor(repository, pr.GetUser()) {
+			continue
+		}
+
+		// Search results leave out the base branch, so with base branches the PR is fetched to know it,
+		// and its branches are kept for the rules that need them
+		var full *github.PullRequest
+		if len(rules.BaseBranches) > 0 {
+			var err error
+			full, err = client.GetPullRequest(ctx, owner, repo, pr.GetNumber())
+			if err != nil {
+				result.Error = fmt.Errorf("error getting pull request: %v", err)
+				return result
+			}
+			if !rules.checkedBase(full.GetBase().GetRef()) {
+				continue
+			}
+		}
+
+		// PRs changing no path in the scope of the repository are not checked
+		checked, files, err := inScope(ctx, client, owner, repo, pr.GetNumber(), rules)
+		if err != nil {
+			result.Error = fmt.Errorf("error listing PR files: %v", err)
+			return result
+		}
+		if !checked {
+			continue
+		}
+
+		isApproved, approvals, dismissed, err := isPRApproved(ctx, client, owner, repo, pr.GetNumber(), debugLogging)
+		if err == nil && isApproved {
+			approvals, err = teamApprovals(ctx, client, approvals, rules.ReviewerTeams, debugLogging)
+			isApproved = len(approvals) > 0
+		}
+		if err != nil {
+			result.Error = fmt.Errorf("error checking PR approval: %v", err)
+			return result
+		}
+
+		merged := mergedPR{
+			PR: PR{
+				Number: pr.GetNumber(),
+				Title:  pr.GetTitle(),
+				Author: pr.GetUser().GetLogin(),
+				URL:    pr.GetHTMLURL(),
+			},
+			Body:      pr.GetBody(),
+			CreatedAt: pr.GetCreatedAt(),
+			MergedAt:  pr.GetClosedAt(), // Search results leave out when the PR was merged, it was closed by the merge
+			Approved:  isApproved,
+			Approvals: approvals,
+			Dismissed: dismissed,
+			Files:     files,
+		}
+		if full != nil {
+			merged.HeadBranch = full.GetHead().GetRef()
+			merged.HeadSHA = full.GetHead().GetSHA()
+			merged.BaseBranch = full.GetBase().GetRef()
+			merged.MergedBy = full.GetMergedBy().GetLogin()
+			merged.Fetched = true
+		}
+		violations, err := checkRules(common.WithPagination(ctx, s.statusChecksPagination), client, owner, repo, &merged, rules)
+		if err != nil {
+			result.Error = fmt.Errorf("error checking review rules: %v", err)
+			return result
+		}
+
+		// Flagged PRs are reported with who merged them and how long after they were opened and approved
+		if !isApproved || len(violations) > 0 {
+			merged.PR = withMergeDetails(ctx, client, owner, repo, merged)
+			for i := range violations {
+				violations[i].PR = merged.PR
+			}
+		}
+		if !isApproved {
+			result.UnapprovedPRs = append(result.UnapprovedPRs, merged.PR)
+		}
+		result.Violations = append(result.Violations, violations...)
+	}
+
+	return result
+}
+
+// searchMergedPRs finds the PRs of org merged from from up to to, splitting the period in two while it has
+// more merges than the search API returns for a query
+func searchMergedPRs(ctx context.Context, client common.GitHubClientInterface, org string, from, to time.Time) ([]*github.Issue, error) {
+	prs, total, err := client.SearchMergedPullRequests(ctx, org, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if total <= len(prs) {
+		return prs, nil
+	}
+
+	if to.Sub(from) <= minSearchPeriod {
+		return nil, fmt.Errorf("%d PRs merged between %s and %s, more than the search API returns", total,
+			from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339))
+	}
+
+	// The qualifier's range includes both ends, so the halves overlap by a second rather than miss one
+	middle := from.Add(to.Sub(from) / 2).Truncate(time.Second)
+	earlier, err := searchMergedPRs(ctx, client, org, from, middle)
+	if err != nil {
+		return nil, err
+	}
+	later, err := searchMergedPRs(ctx, client, org, middle, to)
+	if err != nil {
+		return nil, err
+	}
+	return deduplicatePRs(append(earlier, later...)), nil
+}
+
+// mergedWithin returns the PRs found with the search API that were merged at or after since and before until
+func mergedWithin(prs []*github.Issue, since, until time.Time) []*github.Issue {
+	var kept []*github.Issue
+	for _, pr := range prs {
+		// Search results leave out when the PR was merged, it was closed by the merge
+		if closedAt := pr.GetClosedAt(); !closedAt.Before(since) && closedAt.Before(until) {
+			kept = append(kept, pr)
+		}
+	}
+	return kept
+}
+
+// deduplicatePRs drops the PRs found twice, by their API URL
+func deduplicatePRs(prs []*github.Issue) []*github.Issue {
+	seen := make(map[string]bool, len(prs))
+	unique := make([]*github.Issue, 0, len(prs))
+	for _, pr := range prs {
+		if seen[pr.GetURL()] {
+			continue
+		}
+		seen[pr.GetURL()] = true
+		unique = append(unique, pr)
+	}
+	return unique
+}
+
+// searchedRepository returns the "owner/repo" a PR found with the search API belongs to
+func searchedRepository(pr *github.Issue) string {
+	if repo := pr.GetRepository(); repo.GetFullName() != "" {
+		return repo.GetFullName()
+	}
+	// Search results only link to their repository, e.g. https://api.github.com/repos/owner/repo
+	_, repository, _ := strings.Cut(pr.GetRepositoryURL(), "/repos/")
+	return repository
+}
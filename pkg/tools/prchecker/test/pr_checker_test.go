@@ -3,6 +3,8 @@ package test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -215,7 +217,7 @@ func TestCheckRepository(t *testing.T) {
 				t.Skip("Skipping test case that needs more complex fixes")
 			}
 
-			result := service.CheckRepository(tc.repository, "test-token", tc.timeWindow, true)
+			result := service.CheckRepository(context.Background(), tc.repository, "test-token", time.Duration(tc.timeWindow)*time.Hour, true)
 
 			// Check error state
 			if tc.expectError && result.Error == nil {
@@ -284,16 +286,118 @@ func TestPrintResults(t *testing.T) {
 	}
 }
 
+func TestWriteResultsMarkdown(t *testing.T) {
+	var buf strings.Builder
+	prchecker.WriteResultsMarkdown(&buf, []prchecker.Result{
+		{Repository: "owner/repo1", UnapprovedPRs: []prchecker.PR{{Number: 7, Title: "Test PR", Author: "testuser", URL: "http://example.com/pr/7"}}},
+		{Repository: "owner/repo2", Error: errors.New("test error")},
+	})
+
+	output := buf.String()
+	for _, want := range []string{"## :warning: Unapproved Pull Requests", "Found 1 unapproved pull requests", "#7", "http://example.com/pr/7"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	// Repositories that could not be checked are not actionable and are left out
+	if strings.Contains(output, "owner/repo2") {
+		t.Errorf("Expected repositories with errors to be left out, got:\n%s", output)
+	}
+
+	// Nothing is written without unapproved PRs
+	buf.Reset()
+	prchecker.WriteResultsMarkdown(&buf, []prchecker.Result{{Repository: "owner/repo1"}})
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output for approved repositories, got %q", buf.String())
+	}
+}
+
+func TestMergeDetails(t *testing.T) {
+	merged := time.Now().Add(-time.Hour)
+	pr := func(id int) *github.PullRequest {
+		p := createMockPR(id, "Change", "alice", fmt.Sprintf("http://example.com/pr/%d", id), merged.Add(-3*time.Hour), &merged)
+		p.UpdatedAt = &merged
+		return p
+	}
+	mockClient := &mockgithub.MockGitHubClient{
+		MockPullRequests:    []*github.PullRequest{pr(1), pr(2), pr(3)},
+		MockPullRequestResp: &github.Response{},
+		ListPullRequestReviewsFunc: func(ctx context.Context, owner, repo string, number int, opts *github.ListOptions) ([]*github.PullRequestReview, *github.Response, error) {
+			switch number {
+			case 1: // Short of approvals
+				return []*github.PullRequestReview{createApproval("bob", merged.Add(-30*time.Minute))}, nil, nil
+			case 2: // Approved as required, so not flagged
+				return []*github.PullRequestReview{createApproval("bob", merged.Add(-2*time.Hour)), createApproval("carol", merged.Add(-time.Hour))}, nil, nil
+			}
+			return nil, nil, nil
+		},
+		MockPullRequestsByNumber: map[int]*github.PullRequest{
+			1: {MergedBy: &github.User{Login: github.String("bob")}},
+			3: {MergedBy: &github.User{Login: github.String("admin")}},
+		},
+	}
+	service := &prchecker.Service{
+		NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+	}
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{Token: "test-token"},
+		Monitors: config.MonitorsConfig{
+			PRChecker: config.PRCheckerConfig{
+				Enabled:              true,
+				RepoVisibility:       "specific",
+				SpecificRepositories: []string{"owner/repo"},
+				RequiredApprovals:    2,
+				TimeWindow:           config.Hours(24),
+			},
+		},
+	}
+
+	results := prchecker.MonitorWithService(context.Background(), cfg, service)
+	if len(results) != 1 || results[0].Error != nil {
+		t.Fatalf("Expected 1 result without error, got %+v", results)
+	}
+
+	if len(results[0].Violations) != 1 {
+		t.Fatalf("Expected PR 1 short of approvals, got %+v", results[0].Violations)
+	}
+	if got := results[0].Violations[0].PR; got.MergedBy != "bob" || got.MergeLatency != 3*time.Hour || got.ApprovalLatency != 30*time.Minute {
+		t.Errorf("Expected PR 1 merged by bob 3h after opening and 30m after approval, got %+v", got)
+	}
+	if len(results[0].UnapprovedPRs) != 1 {
+		t.Fatalf("Expected PR 3 unapproved, got %+v", results[0].UnapprovedPRs)
+	}
+	if got := results[0].UnapprovedPRs[0]; got.MergedBy != "admin" || got.MergeLatency != 3*time.Hour || got.ApprovalLatency != 0 {
+		t.Errorf("Expected PR 3 merged by admin 3h after opening without approval, got %+v", got)
+	}
+	// Who merged a PR is only fetched for flagged PRs
+	if mockClient.GetPullRequestCalls != 2 {
+		t.Errorf("Expected the 2 flagged PRs fetched, got %d calls", mockClient.GetPullRequestCalls)
+	}
+
+	var buf strings.Builder
+	prchecker.WriteResultsMarkdown(&buf, results)
+	for _, want := range []string{"merged by admin 3h after opening\n", "merged by bob 3h after opening, 30m after approval\n"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected the report to contain %q, got:\n%s", want, buf.String())
+		}
+	}
+}
+
 func TestMonitor(t *testing.T) {
 	tests := []struct {
 		name            string
 		repoVisibility  string
 		organization    string
+		teamSlug        string
+		excludeForks    bool
+		minRepoAge      int // Hours
+		skipInactive    int // Days
 		repos           []string
 		timeWindow      int
 		mockPRs         []*github.PullRequest
 		mockRepos       []*github.Repository
 		mockOrgRepos    []*github.Repository
+		mockTeamRepos   []*github.Repository
 		mockRepoErr     error
 		mockOrgRepoErr  error
 		expectResults   int
@@ -400,6 +504,70 @@ func TestMonitor(t *testing.T) {
 			expectResults: 3,
 			expectError:   false,
 		},
+		{
+			name:           "Team repositories",
+			repoVisibility: "all",
+			organization:   "testorg",
+			teamSlug:       "platform",
+			repos:          []string{},
+			timeWindow:     24,
+			mockOrgRepos: []*github.Repository{
+				createMockRepo("testorg/repo1", false),
+				createMockRepo("testorg/repo2", false),
+				createMockRepo("testorg/repo3", true),
+			},
+			mockTeamRepos: []*github.Repository{
+				createMockRepo("testorg/repo2", false),
+			},
+			mockPRs:       []*github.PullRequest{},
+			expectResults: 1,
+			expectError:   false,
+		},
+		{
+			name:           "Forks excluded",
+			repoVisibility: "all",
+			organization:   "testorg",
+			excludeForks:   true,
+			repos:          []string{},
+			timeWindow:     24,
+			mockOrgRepos: []*github.Repository{
+				createMockRepo("testorg/repo1", false),
+				{FullName: github.String("testorg/upstream-fork"), Fork: github.Bool(true)},
+			},
+			mockPRs:       []*github.PullRequest{},
+			expectResults: 1,
+			expectError:   false,
+		},
+		{
+			name:           "New and inactive repositories skipped",
+			repoVisibility: "all",
+			organization:   "testorg",
+			minRepoAge:     48,
+			skipInactive:   30,
+			repos:          []string{},
+			timeWindow:     24,
+			mockOrgRepos: []*github.Repository{
+				{
+					FullName:  github.String("testorg/active"),
+					CreatedAt: &github.Timestamp{Time: time.Now().AddDate(-1, 0, 0)},
+					PushedAt:  &github.Timestamp{Time: time.Now().Add(-time.Hour)},
+				},
+				{
+					FullName:  github.String("testorg/bootstrap"),
+					CreatedAt: &github.Timestamp{Time: time.Now().Add(-2 * time.Hour)},
+					PushedAt:  &github.Timestamp{Time: time.Now().Add(-time.Hour)},
+				},
+				{
+					FullName:  github.String("testorg/dead"),
+					CreatedAt: &github.Timestamp{Time: time.Now().AddDate(-3, 0, 0)},
+					PushedAt:  &github.Timestamp{Time: time.Now().AddDate(0, 0, -90)},
+				},
+				createMockRepo("testorg/unknown-dates", false),
+			},
+			mockPRs:       []*github.PullRequest{},
+			expectResults: 2,
+			expectError:   false,
+		},
 		{
 			name:           "Error fetching user repositories",
 			repoVisibility: "all",
@@ -434,6 +602,7 @@ func TestMonitor(t *testing.T) {
 				MockRepositoriesErr:    tc.mockRepoErr,
 				MockOrgRepositories:    tc.mockOrgRepos,
 				MockOrgRepositoriesErr: tc.mockOrgRepoErr,
+				MockTeamRepositories:   map[string][]*github.Repository{"testorg/platform": tc.mockTeamRepos},
 				MockReviews:            []*github.PullRequestReview{},
 			}
 
@@ -455,14 +624,18 @@ func TestMonitor(t *testing.T) {
 						Enabled:              tc.name != "PRChecker disabled",
 						RepoVisibility:       tc.repoVisibility,
 						Organization:         tc.organization,
+						TeamSlug:             tc.teamSlug,
+						ExcludeForks:         tc.excludeForks,
+						MinRepoAge:           config.Hours(tc.minRepoAge),
+						SkipInactiveDays:     tc.skipInactive,
 						SpecificRepositories: tc.repos,
-						TimeWindow:           tc.timeWindow,
+						TimeWindow:           config.Hours(tc.timeWindow),
 					},
 				},
 			}
 
 			// Call Monitor with our mock service
-			results := prchecker.MonitorWithService(cfg, mockService)
+			results := prchecker.MonitorWithService(context.Background(), cfg, mockService)
 
 			// Verify results
 			if tc.expectNoResults {
@@ -500,3 +673,398 @@ func createMockRepo(fullName string, isPrivate bool) *github.Repository {
 		Private:  &private,
 	}
 }
+
+func TestExcludedAuthors(t *testing.T) {
+	now := time.Now()
+	merged := now.Add(-time.Hour)
+	pr := func(id int, author, userType string) *github.PullRequest {
+		p := createMockPR(id, "Update dependencies", author, "http://example.com/pr", now.Add(-2*time.Hour), &merged)
+		p.UpdatedAt = &merged
+		if userType != "" {
+			p.User.Type = github.String(userType)
+		}
+		return p
+	}
+	prs := []*github.PullRequest{
+		pr(1, "dependabot[bot]", "Bot"),
+		pr(2, "renovate[bot]", "Bot"),
+		pr(3, "release-automation", "User"),
+		pr(4, "alice", "User"),
+	}
+
+	tests := []struct {
+		name            string
+		excludedAuthors []string
+		excludeBots     bool
+		expectFlagged   []int
+	}{
+		{
+			name:          "No exclusions",
+			expectFlagged: []int{1, 2, 3, 4},
+		},
+		{
+			name:          "Bots excluded",
+			excludeBots:   true,
+			expectFlagged: []int{3, 4},
+		},
+		{
+			name:            "Listed authors excluded",
+			excludedAuthors: []string{"Renovate", "release-automation"},
+			expectFlagged:   []int{1, 4},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &mockgithub.MockGitHubClient{
+				MockPullRequests:    prs,
+				MockPullRequestResp: &github.Response{},
+				MockReviews:         []*github.PullRequestReview{},
+			}
+			service := &prchecker.Service{
+				NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+			}
+			cfg := &config.Config{
+				GitHub: config.GitHubConfig{Token: "test-token"},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:              true,
+						RepoVisibility:       "specific",
+						SpecificRepositories: []string{"owner/repo"},
+						ExcludedAuthors:      tc.excludedAuthors,
+						ExcludeBots:          tc.excludeBots,
+						TimeWindow:           config.Hours(24),
+					},
+				},
+			}
+
+			results := prchecker.MonitorWithService(context.Background(), cfg, service)
+			if len(results) != 1 || results[0].Error != nil {
+				t.Fatalf("Expected 1 result without error, got %+v", results)
+			}
+
+			var flagged []int
+			for _, unapproved := range results[0].UnapprovedPRs {
+				flagged = append(flagged, unapproved.Number)
+			}
+			if fmt.Sprint(flagged) != fmt.Sprint(tc.expectFlagged) {
+				t.Errorf("Expected PRs %v to be flagged, got %v", tc.expectFlagged, flagged)
+			}
+		})
+	}
+}
+
+func TestRepoOverrides(t *testing.T) {
+	now := time.Now()
+	recent, old := now.Add(-time.Hour), now.Add(-48*time.Hour)
+	pr := func(id int, author string, merged time.Time) *github.PullRequest {
+		p := createMockPR(id, "Change", author, "http://example.com/pr", merged.Add(-time.Hour), &merged)
+		p.UpdatedAt = &merged
+		return p
+	}
+	one, none := 1, false
+	mockClient := &mockgithub.MockGitHubClient{
+		MockPullRequests:    []*github.PullRequest{pr(1, "alice", recent), pr(2, "renovate[bot]", recent), pr(3, "alice", old)},
+		MockPullRequestResp: &github.Response{},
+		ListPullRequestReviewsFunc: func(ctx context.Context, owner, repo string, number int, opts *github.ListOptions) ([]*github.PullRequestReview, *github.Response, error) {
+			if number == 2 {
+				return nil, nil, nil
+			}
+			return []*github.PullRequestReview{createMockReview("APPROVED", "bob")}, nil, nil
+		},
+	}
+	service := &prchecker.Service{
+		NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+	}
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{Token: "test-token"},
+		Monitors: config.MonitorsConfig{
+			PRChecker: config.PRCheckerConfig{
+				Enabled:              true,
+				RepoVisibility:       "specific",
+				SpecificRepositories: []string{"owner/repo", "owner/payments"},
+				ExcludedAuthors:      []string{"renovate"},
+				RequiredApprovals:    2,
+				TimeWindow:           config.Hours(24),
+				RepoOverrides: []config.PRCheckerOverride{{
+					Repository:        "Owner/Payments",
+					TimeWindow:        config.Hours(72),
+					RequiredApprovals: &one,
+					ExcludedAuthors:   []string{},
+					ExcludeBots:       &none,
+				}},
+			},
+		},
+	}
+
+	results := prchecker.MonitorWithService(context.Background(), cfg, service)
+	if len(results) != 2 || results[0].Error != nil || results[1].Error != nil {
+		t.Fatalf("Expected 2 results without error, got %+v", results)
+	}
+
+	// The global policy: the bot is excluded, the PR merged before the window is not checked
+	// and the PR approved by one reviewer is short of the two required
+	global := results[0]
+	if len(global.UnapprovedPRs) != 0 {
+		t.Errorf("Expected no unapproved PRs in owner/repo, got %+v", global.UnapprovedPRs)
+	}
+	if len(global.Violations) != 1 || global.Violations[0].Rule != prchecker.RuleApprovals || global.Violations[0].PR.Number != 1 {
+		t.Errorf("Expected PR 1 of owner/repo short of approvals, got %+v", global.Violations)
+	}
+
+	// The overridden policy: the bot is checked, the older PR is within the window, and one approval is enough
+	overridden := results[1]
+	if len(overridden.UnapprovedPRs) != 1 || overridden.UnapprovedPRs[0].Number != 2 {
+		t.Errorf("Expected PR 2 of owner/payments unapproved, got %+v", overridden.UnapprovedPRs)
+	}
+	if len(overridden.Violations) != 0 {
+		t.Errorf("Expected no violations in owner/payments, got %+v", overridden.Violations)
+	}
+	if mockClient.ListPullRequestReviewsCalls != 4 {
+		t.Errorf("Expected the reviews of 4 PRs fetched, got %d", mockClient.ListPullRequestReviewsCalls)
+	}
+}
+
+func TestPagination(t *testing.T) {
+	now := time.Now()
+	recent, old := now.Add(-time.Hour), now.Add(-48*time.Hour)
+	pr := func(id int, merged time.Time) *github.PullRequest {
+		p := createMockPR(id, "Change", "alice", "http://example.com/pr", merged.Add(-time.Hour), &merged)
+		p.UpdatedAt = &recent
+		return p
+	}
+
+	tests := []struct {
+		name          string
+		pagination    config.PaginationConfig
+		threshold     int
+		expectPerPage int
+		expectCalls   int
+		expectFlagged []int
+	}{
+		{
+			name:          "Default page size and threshold",
+			expectPerPage: 100,
+			expectCalls:   3,
+			expectFlagged: []int{1, 5, 9},
+		},
+		{
+			name:          "Capped at two pages",
+			pagination:    config.PaginationConfig{PageSize: 4, MaxPages: 2},
+			expectPerPage: 4,
+			expectCalls:   2,
+			expectFlagged: []int{1, 5},
+		},
+		{
+			name:          "Stopped by a lower threshold",
+			threshold:     3,
+			expectPerPage: 100,
+			expectCalls:   1,
+			expectFlagged: []int{1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var perPage int
+			mockClient := &mockgithub.MockGitHubClient{
+				// Each page holds a PR merged within the window followed by three merged before it
+				GetPullRequestsFunc: func(_ context.Context, _, _ string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error) {
+					perPage = opts.PerPage
+					page := max(opts.Page, 1)
+					first := (page-1)*4 + 1
+					next := page + 1
+					if page == 3 {
+						next = 0
+					}
+					prs := []*github.PullRequest{pr(first, recent), pr(first+1, old), pr(first+2, old), pr(first+3, old)}
+					return prs, &github.Response{NextPage: next}, nil
+				},
+				MockReviews: []*github.PullRequestReview{},
+			}
+			service := &prchecker.Service{
+				NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+			}
+			cfg := &config.Config{
+				GitHub: config.GitHubConfig{Token: "test-token"},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:              true,
+						RepoVisibility:       "specific",
+						SpecificRepositories: []string{"owner/repo"},
+						TimeWindow:           config.Hours(24),
+						OutOfWindowThreshold: tc.threshold,
+						Pagination:           tc.pagination,
+					},
+				},
+			}
+
+			results := prchecker.MonitorWithService(context.Background(), cfg, service)
+			if len(results) != 1 || results[0].Error != nil {
+				t.Fatalf("Expected 1 result without error, got %+v", results)
+			}
+
+			if perPage != tc.expectPerPage {
+				t.Errorf("Expected %d PRs per page, got %d", tc.expectPerPage, perPage)
+			}
+			if mockClient.GetPullRequestsCalls != tc.expectCalls {
+				t.Errorf("Expected %d pages fetched, got %d", tc.expectCalls, mockClient.GetPullRequestsCalls)
+			}
+			var flagged []int
+			for _, unapproved := range results[0].UnapprovedPRs {
+				flagged = append(flagged, unapproved.Number)
+			}
+			if fmt.Sprint(flagged) != fmt.Sprint(tc.expectFlagged) {
+				t.Errorf("Expected PRs %v to be flagged, got %v", tc.expectFlagged, flagged)
+			}
+		})
+	}
+}
+
+func TestBaseBranches(t *testing.T) {
+	now := time.Now()
+	merged := now.Add(-time.Hour)
+	pr := func(id int, base string) *github.PullRequest {
+		p := createMockPR(id, "Change", "alice", "http://example.com/pr", now.Add(-2*time.Hour), &merged)
+		p.UpdatedAt = &merged
+		p.Base = &github.PullRequestBranch{Ref: github.String(base)}
+		return p
+	}
+	prs := []*github.PullRequest{
+		pr(1, "main"),
+		pr(2, "feature/login"),
+		pr(3, "release/1.2"),
+		pr(4, "release/1.2/hotfix"),
+	}
+
+	tests := []struct {
+		name          string
+		baseBranches  []string
+		expectFlagged []int
+	}{
+		{
+			name:          "All base branches",
+			expectFlagged: []int{1, 2, 3, 4},
+		},
+		{
+			name:          "Main and release branches",
+			baseBranches:  []string{"main", "release/*"},
+			expectFlagged: []int{1, 3},
+		},
+		{
+			name:          "Nested release branches",
+			baseBranches:  []string{"release/**"},
+			expectFlagged: []int{3, 4},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &mockgithub.MockGitHubClient{
+				MockPullRequests:    prs,
+				MockPullRequestResp: &github.Response{},
+				MockReviews:         []*github.PullRequestReview{},
+			}
+			service := &prchecker.Service{
+				NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+			}
+			cfg := &config.Config{
+				GitHub: config.GitHubConfig{Token: "test-token"},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:              true,
+						RepoVisibility:       "specific",
+						SpecificRepositories: []string{"owner/repo"},
+						BaseBranches:         tc.baseBranches,
+						TimeWindow:           config.Hours(24),
+					},
+				},
+			}
+
+			results := prchecker.MonitorWithService(context.Background(), cfg, service)
+			if len(results) != 1 || results[0].Error != nil {
+				t.Fatalf("Expected 1 result without error, got %+v", results)
+			}
+
+			var flagged []int
+			for _, unapproved := range results[0].UnapprovedPRs {
+				flagged = append(flagged, unapproved.Number)
+			}
+			if fmt.Sprint(flagged) != fmt.Sprint(tc.expectFlagged) {
+				t.Errorf("Expected PRs %v to be flagged, got %v", tc.expectFlagged, flagged)
+			}
+		})
+	}
+}
+
+func TestBaseBranchesPolicy(t *testing.T) {
+	now := time.Now()
+	merged := now.Add(-time.Hour)
+	pr := func(id int, base string) *github.PullRequest {
+		p := createMockPR(id, "Change", "alice", "http://example.com/pr", now.Add(-2*time.Hour), &merged)
+		p.UpdatedAt = &merged
+		p.Base = &github.PullRequestBranch{Ref: github.String(base)}
+		return p
+	}
+	prs := []*github.PullRequest{
+		pr(1, "main"),
+		pr(2, "feature/login"),
+		pr(3, "release/1.2"),
+	}
+
+	tests := []struct {
+		name          string
+		baseBranches  []string
+		expectFlagged []int
+	}{
+		{
+			name:          "Policy adds the release branches",
+			baseBranches:  []string{"main"},
+			expectFlagged: []int{1, 3},
+		},
+		{
+			name:          "Policy cannot narrow all base branches",
+			expectFlagged: []int{1, 2, 3},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &mockgithub.MockGitHubClient{
+				MockPullRequests:    prs,
+				MockPullRequestResp: &github.Response{},
+				MockReviews:         []*github.PullRequestReview{},
+				MockFileContents:    map[string]string{"owner/repo/.github/git-monitor.toml": "[pr_checker]\nbase_branches = [\"release/*\"]\n"},
+			}
+			service := &prchecker.Service{
+				NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+			}
+			cfg := &config.Config{
+				GitHub:     config.GitHubConfig{Token: "test-token"},
+				RepoPolicy: config.RepoPolicyConfig{Enabled: true, Path: ".github/git-monitor.toml"},
+				Monitors: config.MonitorsConfig{
+					PRChecker: config.PRCheckerConfig{
+						Enabled:              true,
+						RepoVisibility:       "specific",
+						SpecificRepositories: []string{"owner/repo"},
+						BaseBranches:         tc.baseBranches,
+						TimeWindow:           config.Hours(24),
+					},
+				},
+			}
+
+			results := prchecker.MonitorWithService(context.Background(), cfg, service)
+			if len(results) != 1 || results[0].Error != nil {
+				t.Fatalf("Expected 1 result without error, got %+v", results)
+			}
+
+			var flagged []int
+			for _, unapproved := range results[0].UnapprovedPRs {
+				flagged = append(flagged, unapproved.Number)
+			}
+			if fmt.Sprint(flagged) != fmt.Sprint(tc.expectFlagged) {
+				t.Errorf("Expected PRs %v to be flagged, got %v", tc.expectFlagged, flagged)
+			}
+		})
+	}
+}
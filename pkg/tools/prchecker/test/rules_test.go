@@ -0,0 +1,1658 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	mockgithub "github.com/anupsv/git-monitoring/pkg/tools/common/test"
+	"github.com/anupsv/git-monitoring/pkg/tools/prchecker"
+	"github.com/google/go-github/v45/github"
+)
+
+// createApproval creates an approving review submitted at the given time
+func createApproval(reviewer string, submittedAt time.Time) *github.PullRequestReview {
+	return &github.PullRequestReview{
+		User:        &github.User{Login: github.String(reviewer)},
+		State:       github.String("APPROVED"),
+		SubmittedAt: &submittedAt,
+	}
+}
+
+// createCommit creates a PR commit committed at the given time
+func createCommit(committedAt time.Time) *github.RepositoryCommit {
+	return &github.RepositoryCommit{Commit: &github.Commit{Committer: &github.CommitAuthor{Date: &committedAt}}}
+}
+
+func TestRubberStampApprovals(t *testing.T) {
+	opened := time.Now().Add(-6 * time.Hour)
+
+	tests := []struct {
+		name          string
+		approvals     []*github.PullRequestReview
+		commits       []*github.RepositoryCommit
+		policy        string // Repository policy file, none when empty
+		expectStamp   bool
+		expectCommits bool // Whether the PR's commits must be fetched
+	}{
+		{
+			name:        "Approved right after opening",
+			approvals:   []*github.PullRequestReview{createApproval("reviewer", opened.Add(30*time.Second))},
+			expectStamp: true,
+		},
+		{
+			name:          "Approved right after the last push",
+			approvals:     []*github.PullRequestReview{createApproval("reviewer", opened.Add(2*time.Hour))},
+			commits:       []*github.RepositoryCommit{createCommit(opened), createCommit(opened.Add(2*time.Hour - 20*time.Second))},
+			expectStamp:   true,
+			expectCommits: true,
+		},
+		{
+			name:          "Reviewed after the last push",
+			approvals:     []*github.PullRequestReview{createApproval("reviewer", opened.Add(2*time.Hour))},
+			commits:       []*github.RepositoryCommit{createCommit(opened.Add(time.Hour))},
+			expectCommits: true,
+		},
+		{
+			name: "One reviewer took their time",
+			approvals: []*github.PullRequestReview{
+				createApproval("fast", opened.Add(10*time.Second)),
+				createApproval("careful", opened.Add(time.Hour)),
+			},
+			expectCommits: true,
+		},
+		{
+			name:        "Repository policy raises the minimum review time",
+			approvals:   []*github.PullRequestReview{createApproval("reviewer", opened.Add(20*time.Minute))},
+			policy:      "[pr_checker]\nmin_review_time = \"30m\"\n",
+			expectStamp: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pr := createSearchedPR("testorg/repo1", 7)
+			pr.CreatedAt = &opened
+			mockClient := &mockgithub.MockGitHubClient{
+				MockOrgRepositories: []*github.Repository{createMockRepo("testorg/repo1", false)},
+				MockSearchIssues:    []*github.Issue{pr},
+				MockReviews:         tc.approvals,
+				MockPRCommits:       map[int][]*github.RepositoryCommit{7: tc.commits},
+				MockFileContents:    map[string]string{},
+			}
+			service := &prchecker.Service{
+				NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+			}
+
+			cfg := newSearchConfig()
+			cfg.Monitors.PRChecker.MinReviewTime = config.Duration{Duration: 5 * time.Minute}
+			if tc.policy != "" {
+				cfg.RepoPolicy = config.RepoPolicyConfig{Enabled: true, Path: ".github/git-monitor.toml"}
+				mockClient.MockFileContents["testorg/repo1/.github/git-monitor.toml"] = tc.policy
+			}
+
+			results := prchecker.MonitorWithService(context.Background(), cfg, service)
+			if len(results) != 1 || results[0].Error != nil {
+				t.Fatalf("Expected 1 result without error, got %+v", results)
+			}
+
+			stamped := len(results[0].Violations) == 1 && results[0].Violations[0].Rule == prchecker.RuleRubberStamp
+			if stamped != tc.expectStamp {
+				t.Errorf("Expected rubber stamp %v, got violations %+v", tc.expectStamp, results[0].Violations)
+			}
+			if (mockClient.ListPullRequestCommitsCalls > 0) != tc.expectCommits {
+				t.Errorf("Expected commits fetched %v, got %d calls", tc.expectCommits, mockClient.ListPullRequestCommitsCalls)
+			}
+			if len(results[0].UnapprovedPRs) != 0 {
+				t.Errorf("Expected the approved PR not to be reported as unapproved, got %+v", results[0].UnapprovedPRs)
+			}
+		})
+	}
+}
+
+func TestViolationFindings(t *testing.T) {
+	results := []prchecker.Result{{
+		Repository: "owner/repo",
+		Violations: []prchecker.Violation{{
+			PR:     prchecker.PR{Number: 3, Title: "Fix", Author: "alice", URL: "https://github.com/owner/repo/pull/3"},
+			Rule:   prchecker.RuleRubberStamp,
+			Detail: "approved 5s after it was opened or last pushed to",
+		}},
+	}}
+
+	list := prchecker.Findings(results)
+	if len(list) != 1 || list[0].Subject != "PR #3 rubber_stamp" {
+		t.Fatalf("Expected a finding for the violation, got %+v", list)
+	}
+	// Violations of a PR do not share the fingerprint of it being unapproved
+	if list[0].Fingerprint() == findings.Fingerprint("pr_checker", "owner/repo", "PR #3") {
+		t.Errorf("Expected the violation to have its own fingerprint")
+	}
+}
+
+// createAuthoredCommit creates a PR commit by the given author and committer, with the given message
+func createAuthoredCommit(author, committer, message string) *github.RepositoryCommit {
+	return &github.RepositoryCommit{
+		Author:    &github.User{Login: github.String(author)},
+		Committer: &github.User{Login: github.String(committer)},
+		Commit:    &github.Commit{Message: github.String(message)},
+	}
+}
+
+func TestCommitterApprovals(t *testing.T) {
+	opened := time.Now().Add(-6 * time.Hour)
+
+	tests := []struct {
+		name         string
+		approvals    []*github.PullRequestReview
+		commits      []*github.RepositoryCommit
+		expectFlag   bool
+		expectDetail string
+	}{
+		{
+			name:      "Approver pushed to the branch",
+			approvals: []*github.PullRequestReview{createApproval("Bob", opened.Add(time.Hour))},
+			commits: []*github.RepositoryCommit{
+				createAuthoredCommit("alice", "alice", "Add feature"),
+				createAuthoredCommit("bob", "bob", "Fix tests"),
+			},
+			expectFlag:   true,
+			expectDetail: "approved only by Bob, who also contributed commits",
+		},
+		{
+			name:      "Approver co-authored a commit",
+			approvals: []*github.PullRequestReview{createApproval("bob", opened.Add(time.Hour))},
+			commits: []*github.RepositoryCommit{
+				createAuthoredCommit("alice", "alice", "Add feature\n\nCo-authored-by: Bob <12345+bob@users.noreply.github.com>"),
+			},
+			expectFlag: true,
+		},
+		{
+			name: "Independent approver",
+			approvals: []*github.PullRequestReview{
+				createApproval("bob", opened.Add(time.Hour)),
+				createApproval("carol", opened.Add(2*time.Hour)),
+			},
+			commits: []*github.RepositoryCommit{
+				createAuthoredCommit("alice", "alice", "Add feature"),
+				createAuthoredCommit("bob", "bob", "Fix tests"),
+			},
+		},
+		{
+			name:      "Commits made on github.com",
+			approvals: []*github.PullRequestReview{createApproval("bob", opened.Add(time.Hour))},
+			commits: []*github.RepositoryCommit{
+				createAuthoredCommit("alice", "web-flow", "Merge branch 'main' into feature"),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pr := createSearchedPR("testorg/repo1", 7)
+			pr.CreatedAt = &opened
+			mockClient := &mockgithub.MockGitHubClient{
+				MockOrgRepositories: []*github.Repository{createMockRepo("testorg/repo1", false)},
+				MockSearchIssues:    []*github.Issue{pr},
+				MockReviews:         tc.approvals,
+				MockPRCommits:       map[int][]*github.RepositoryCommit{7: tc.commits},
+			}
+			service := &prchecker.Service{
+				NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+			}
+
+			cfg := newSearchConfig()
+			cfg.Monitors.PRChecker.FlagCommitterApprovals = true
+			cfg.Monitors.PRChecker.MinReviewTime = config.Duration{Duration: 5 * time.Minute}
+
+			results := prchecker.MonitorWithService(context.Background(), cfg, service)
+			if len(results) != 1 || results[0].Error != nil {
+				t.Fatalf("Expected 1 result without error, got %+v", results)
+			}
+
+			flagged := len(results[0].Violations) == 1 && results[0].Violations[0].Rule == prchecker.RuleCommitterApproval
+			if flagged != tc.expectFlag {
+				t.Errorf("Expected committer approval %v, got violations %+v", tc.expectFlag, results[0].Violations)
+			}
+			if tc.expectDetail != "" && flagged && results[0].Violations[0].Detail != tc.expectDetail {
+				t.Errorf("Expected detail %q, got %q", tc.expectDetail, results[0].Violations[0].Detail)
+			}
+			// Both rules need the commits, which are fetched once
+			if mockClient.ListPullRequestCommitsCalls != 1 {
+				t.Errorf("Expected the commits to be fetched once, got %d calls", mockClient.ListPullRequestCommitsCalls)
+			}
+		})
+	}
+}
+
+// createPushedCommit creates a PR commit with a SHA, committed at the given time
+func createPushedCommit(sha string, committedAt time.Time) *github.RepositoryCommit {
+	commit := createCommit(committedAt)
+	commit.SHA = github.String(sha)
+	return commit
+}
+
+func TestStaleApprovals(t *testing.T) {
+	opened := time.Now().Add(-6 * time.Hour)
+	onCommit := func(approval *github.PullRequestReview, sha string) *github.PullRequestReview {
+		approval.CommitID = github.String(sha)
+		return approval
+	}
+
+	tests := []struct {
+		name              string
+		approvals         []*github.PullRequestReview
+		requiredApprovals int
+		expectDetail      string
+	}{
+		{
+			name:      "Commit pushed after the approval",
+			approvals: []*github.PullRequestReview{createApproval("bob", opened.Add(time.Hour))},
+			expectDetail: "approved on stale code: approved by bob before the last commit 9f8e7d6, " +
+				"pushed 1h0m0s later",
+		},
+		{
+			name: "Approved again after the last commit",
+			approvals: []*github.PullRequestReview{
+				createApproval("bob", opened.Add(time.Hour)),
+				createApproval("carol", opened.Add(3*time.Hour)),
+			},
+		},
+		{
+			name:      "Approved on the last commit",
+			approvals: []*github.PullRequestReview{onCommit(createApproval("bob", opened.Add(time.Hour)), "9f8e7d6c5b4a")},
+		},
+		{
+			name: "Fewer current approvals than required",
+			approvals: []*github.PullRequestReview{
+				createApproval("bob", opened.Add(time.Hour)),
+				createApproval("carol", opened.Add(3*time.Hour)),
+			},
+			requiredApprovals: 2,
+			expectDetail: "approved on stale code: approved by bob before the last commit 9f8e7d6, " +
+				"pushed 1h0m0s later",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pr := createSearchedPR("testorg/repo1", 7)
+			pr.CreatedAt = &opened
+			mockClient := &mockgithub.MockGitHubClient{
+				MockOrgRepositories: []*github.Repository{createMockRepo("testorg/repo1", false)},
+				MockSearchIssues:    []*github.Issue{pr},
+				MockReviews:         tc.approvals,
+				MockPRCommits: map[int][]*github.RepositoryCommit{7: {
+					createPushedCommit("1a2b3c4d5e6f", opened),
+					createPushedCommit("9f8e7d6c5b4a", opened.Add(2*time.Hour)),
+				}},
+			}
+			service := &prchecker.Service{
+				NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+			}
+
+			cfg := newSearchConfig()
+			cfg.Monitors.PRChecker.FlagStaleApprovals = true
+			cfg.Monitors.PRChecker.RequiredApprovals = tc.requiredApprovals
+
+			results := prchecker.MonitorWithService(context.Background(), cfg, service)
+			if len(results) != 1 || results[0].Error != nil {
+				t.Fatalf("Expected 1 result without error, got %+v", results)
+			}
+
+			detail := ""
+			for _, violation := range results[0].Violations {
+				if violation.Rule == prchecker.RuleStaleApproval {
+					detail = violation.Detail
+				}
+			}
+			if detail != tc.expectDetail {
+				t.Errorf("Expected stale approval %q, got violations %+v", tc.expectDetail, results[0].Violations)
+			}
+		})
+	}
+}
+
+func TestRequiredApprovals(t *testing.T) {
+	opened := time.Now().Add(-6 * time.Hour)
+
+	tests := []struct {
+		name             string
+		reviews          []*github.PullRequestReview
+		expectFlag       bool
+		expectUnapproved bool
+	}{
+		{
+			name:       "Single approval",
+			reviews:    []*github.PullRequestReview{createApproval("bob", opened.Add(time.Hour))},
+			expectFlag: true,
+		},
+		{
+			name: "Same reviewer approving twice",
+			reviews: []*github.PullRequestReview{
+				createApproval("bob", opened.Add(time.Hour)),
+				createApproval("bob", opened.Add(2*time.Hour)),
+			},
+			expectFlag: true,
+		},
+		{
+			name: "Two distinct approvers",
+			reviews: []*github.PullRequestReview{
+				createApproval("bob", opened.Add(time.Hour)),
+				createApproval("carol", opened.Add(2*time.Hour)),
+			},
+		},
+		{
+			name:             "No approval",
+			expectUnapproved: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pr := createSearchedPR("testorg/repo1", 7)
+			pr.CreatedAt = &opened
+			mockClient := &mockgithub.MockGitHubClient{
+				MockOrgRepositories: []*github.Repository{createMockRepo("testorg/repo1", false)},
+				MockSearchIssues:    []*github.Issue{pr},
+				MockReviews:         tc.reviews,
+			}
+			service := &prchecker.Service{
+				NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+			}
+
+			cfg := newSearchConfig()
+			cfg.Monitors.PRChecker.RequiredApprovals = 2
+
+			results := prchecker.MonitorWithService(context.Background(), cfg, service)
+			if len(results) != 1 || results[0].Error != nil {
+				t.Fatalf("Expected 1 result without error, got %+v", results)
+			}
+
+			flagged := len(results[0].Violations) == 1 && results[0].Violations[0].Rule == prchecker.RuleApprovals
+			if flagged != tc.expectFlag {
+				t.Errorf("Expected too few approvals %v, got violations %+v", tc.expectFlag, results[0].Violations)
+			}
+			if flagged && results[0].Violations[0].Detail != "approved by 1 of the 2 required reviewers (bob)" {
+				t.Errorf("Unexpected detail: %q", results[0].Violations[0].Detail)
+			}
+			// PRs without any approval are reported as unapproved, not as lacking approvals
+			if unapproved := len(results[0].UnapprovedPRs) == 1; unapproved != tc.expectUnapproved {
+				t.Errorf("Expected unapproved %v, got %+v", tc.expectUnapproved, results[0].UnapprovedPRs)
+			}
+		})
+	}
+}
+
+func TestRequiredApprovalsPolicy(t *testing.T) {
+	opened := time.Now().Add(-6 * time.Hour)
+	one, three := 1, 3
+
+	tests := []struct {
+		name           string
+		central        int
+		override       *int // Required approvals of the repository in repo_overrides
+		maxApprovals   int  // max_required_approvals of repo_policy
+		policy         string
+		expectRequired int // Approvals required once resolved, 0 when the PR is approved enough
+	}{
+		{
+			name:           "Policy requires more approvals",
+			central:        1,
+			maxApprovals:   3,
+			policy:         "[pr_checker]\nrequired_approvals = 2\n",
+			expectRequired: 2,
+		},
+		{
+			name:           "Policy capped at the max required approvals",
+			central:        1,
+			maxApprovals:   2,
+			policy:         "[pr_checker]\nrequired_approvals = 50\n",
+			expectRequired: 2,
+		},
+		{
+			name:    "Policy without a max required approvals keeps the central value",
+			central: 1,
+			policy:  "[pr_checker]\nrequired_approvals = 2\n",
+		},
+		{
+			name:           "Policy cannot require fewer approvals",
+			central:        2,
+			policy:         "[pr_checker]\nrequired_approvals = 1\n",
+			expectRequired: 2,
+		},
+		{
+			name:    "Policy without required approvals",
+			central: 1,
+			policy:  "[pr_checker]\nmin_review_time = \"1m\"\n",
+		},
+		{
+			name:           "Policy tightens the repository override",
+			central:        2,
+			override:       &one,
+			maxApprovals:   3,
+			policy:         "[pr_checker]\nrequired_approvals = 2\n",
+			expectRequired: 2,
+		},
+		{
+			name:           "Policy cannot loosen the repository override",
+			central:        1,
+			override:       &three,
+			policy:         "[pr_checker]\nrequired_approvals = 2\n",
+			expectRequired: 3,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pr := createSearchedPR("testorg/repo1", 7)
+			pr.CreatedAt = &opened
+			mockClient := &mockgithub.MockGitHubClient{
+				MockOrgRepositories: []*github.Repository{createMockRepo("testorg/repo1", false)},
+				MockSearchIssues:    []*github.Issue{pr},
+				MockReviews:         []*github.PullRequestReview{createApproval("bob", opened.Add(time.Hour))},
+				MockFileContents:    map[string]string{"testorg/repo1/.github/git-monitor.toml": tc.policy},
+			}
+			service := &prchecker.Service{
+				NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+			}
+
+			cfg := newSearchConfig()
+			cfg.Monitors.PRChecker.RequiredApprovals = tc.central
+			if tc.override != nil {
+				cfg.Monitors.PRChecker.RepoOverrides = []config.PRCheckerOverride{{Repository: "testorg/repo1", RequiredApprovals: tc.override}}
+			}
+			cfg.RepoPolicy = config.RepoPolicyConfig{Enabled: true, Path: ".github/git-monitor.toml", MaxRequiredApprovals: tc.maxApprovals}
+
+			results := prchecker.MonitorWithService(context.Background(), cfg, service)
+			if len(results) != 1 || results[0].Error != nil {
+				t.Fatalf("Expected 1 result without error, got %+v", results)
+			}
+
+			flagged := len(results[0].Violations) == 1 && results[0].Violations[0].Rule == prchecker.RuleApprovals
+			if flagged != (tc.expectRequired > 0) {
+				t.Fatalf("Expected too few approvals %v, got violations %+v", tc.expectRequired > 0, results[0].Violations)
+			}
+			expected := fmt.Sprintf("approved by 1 of the %d required reviewers (bob)", tc.expectRequired)
+			if flagged && results[0].Violations[0].Detail != expected {
+				t.Errorf("Expected detail %q, got %q", expected, results[0].Violations[0].Detail)
+			}
+		})
+	}
+}
+
+func TestCodeOwnerApprovals(t *testing.T) {
+	opened := time.Now().Add(-6 * time.Hour)
+	codeOwners := "* @testorg/platform\n/payments/ @testorg/payments @alice\n/docs/\n"
+
+	tests := []struct {
+		name         string
+		codeOwners   string
+		files        []string
+		approvers    []string
+		expectDetail string
+	}{
+		{
+			name:       "Approved by a member of the owning team",
+			codeOwners: codeOwners,
+			files:      []string{"payments/api.go"},
+			approvers:  []string{"bob"},
+		},
+		{
+			name:       "Approved by an owner named in CODEOWNERS",
+			codeOwners: codeOwners,
+			files:      []string{"payments/api.go"},
+			approvers:  []string{"Alice"},
+		},
+		{
+			name:         "Approved by someone outside the owners",
+			codeOwners:   codeOwners,
+			files:        []string{"payments/api.go", "docs/README.md"},
+			approvers:    []string{"carol"},
+			expectDetail: "approved by no code owner of payments/api.go (@testorg/payments @alice)",
+		},
+		{
+			name:         "Each owned file needs one of its owners",
+			codeOwners:   codeOwners,
+			files:        []string{"payments/api.go", "main.go"},
+			approvers:    []string{"bob"},
+			expectDetail: "approved by no code owner of main.go (@testorg/platform)",
+		},
+		{
+			name:      "Repository without CODEOWNERS",
+			files:     []string{"payments/api.go"},
+			approvers: []string{"carol"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pr := createSearchedPR("testorg/repo1", 7)
+			pr.CreatedAt = &opened
+			var approvals []*github.PullRequestReview
+			for _, approver := range tc.approvers {
+				approvals = append(approvals, createApproval(approver, opened.Add(time.Hour)))
+			}
+			mockClient := &mockgithub.MockGitHubClient{
+				MockOrgRepositories: []*github.Repository{createMockRepo("testorg/repo1", false)},
+				MockSearchIssues:    []*github.Issue{pr},
+				MockReviews:         approvals,
+				MockPRFiles:         map[int][]string{7: tc.files},
+				MockTeamMemberships: map[string]bool{"testorg/payments/bob": true},
+			}
+			if tc.codeOwners != "" {
+				mockClient.MockFileContents = map[string]string{"testorg/repo1/.github/CODEOWNERS": tc.codeOwners}
+			}
+			service := &prchecker.Service{
+				NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+			}
+
+			cfg := newSearchConfig()
+			cfg.Monitors.PRChecker.RequireCodeOwners = true
+
+			results := prchecker.MonitorWithService(context.Background(), cfg, service)
+			if len(results) != 1 || results[0].Error != nil {
+				t.Fatalf("Expected 1 result without error, got %+v", results)
+			}
+
+			detail := ""
+			if len(results[0].Violations) == 1 && results[0].Violations[0].Rule == prchecker.RuleCodeOwner {
+				detail = results[0].Violations[0].Detail
+			}
+			if detail != tc.expectDetail {
+				t.Errorf("Expected detail %q, got violations %+v", tc.expectDetail, results[0].Violations)
+			}
+		})
+	}
+}
+
+func TestTemplateSections(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		approved     bool
+		expectDetail string // Detail of the template violation, none when empty
+	}{
+		{
+			name:     "Sections filled in",
+			body:     "## Summary\nFix the parser\n\n## Testing\nUnit tests\n\n## Rollback plan\nRevert the commit\n",
+			approved: true,
+		},
+		{
+			name:         "Section missing",
+			body:         "## Summary\nFix the parser\n\n## Testing\nUnit tests\n",
+			approved:     true,
+			expectDetail: "description template sections missing ^##\\s*Rollback plan",
+		},
+		{
+			name:         "Sections left with template instructions only",
+			body:         "## Testing\n<!-- How was this tested? -->\n\n## Rollback plan\n\n## Notes\nNone\n",
+			approved:     true,
+			expectDetail: "description template sections empty ^##\\s*Testing, ^##\\s*Rollback plan",
+		},
+		{
+			name:         "Unapproved PR without description",
+			expectDetail: "description template sections missing ^##\\s*Testing, ^##\\s*Rollback plan",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pr := createSearchedPR("testorg/repo1", 7)
+			pr.Body = github.String(tc.body)
+			var reviews []*github.PullRequestReview
+			if tc.approved {
+				reviews = []*github.PullRequestReview{createApproval("reviewer", time.Now())}
+			}
+			mockClient := &mockgithub.MockGitHubClient{
+				MockOrgRepositories: []*github.Repository{createMockRepo("testorg/repo1", false)},
+				MockSearchIssues:    []*github.Issue{pr},
+				MockReviews:         reviews,
+			}
+			service := &prchecker.Service{
+				NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+			}
+
+			cfg := newSearchConfig()
+			cfg.Monitors.PRChecker.RequiredSections = []string{`^##\s*Testing`, `^##\s*Rollback plan`}
+
+			results := prchecker.MonitorWithService(context.Background(), cfg, service)
+			if len(results) != 1 || results[0].Error != nil {
+				t.Fatalf("Expected 1 result without error, got %+v", results)
+			}
+
+			var detail string
+			for _, v := range results[0].Violations {
+				if v.Rule == prchecker.RuleTemplate {
+					detail = v.Detail
+				}
+			}
+			if detail != tc.expectDetail {
+				t.Errorf("Expected template violation %q, got %q", tc.expectDetail, detail)
+			}
+			// The description is part of the search results, no further requests are needed
+			if mockClient.ListPullRequestCommitsCalls != 0 {
+				t.Errorf("Expected no commits to be fetched, got %d calls", mockClient.ListPullRequestCommitsCalls)
+			}
+		})
+	}
+}
+
+func TestTitleConvention(t *testing.T) {
+	conforming := createSearchedPR("testorg/repo1", 1)
+	conforming.Title = github.String("fix(parser): handle empty input")
+	other := createSearchedPR("testorg/repo1", 2)
+	other.Title = github.String("Fixed stuff")
+
+	mockClient := &mockgithub.MockGitHubClient{
+		MockOrgRepositories: []*github.Repository{createMockRepo("testorg/repo1", false)},
+		MockSearchIssues:    []*github.Issue{conforming, other},
+		MockReviews:         []*github.PullRequestReview{createApproval("reviewer", time.Now())},
+	}
+	service := &prchecker.Service{
+		NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+	}
+
+	cfg := newSearchConfig()
+	cfg.Monitors.PRChecker.TitlePattern = `^(feat|fix|docs|chore|refactor|test)(\([\w-]+\))?!?: .+`
+
+	results := prchecker.MonitorWithService(context.Background(), cfg, service)
+	if len(results) != 1 || results[0].Error != nil {
+		t.Fatalf("Expected 1 result without error, got %+v", results)
+	}
+	if len(results[0].Violations) != 1 || results[0].Violations[0].Rule != prchecker.RuleTitle || results[0].Violations[0].PR.Number != 2 {
+		t.Fatalf("Expected a title violation of PR #2, got %+v", results[0].Violations)
+	}
+
+	list := prchecker.Findings(results)
+	if len(list) != 1 || list[0].Severity != findings.SeverityLow {
+		t.Errorf("Expected a low-severity finding, got %+v", list)
+	}
+}
+
+func TestTitlePreset(t *testing.T) {
+	titles := []struct {
+		title   string
+		matches bool
+	}{
+		{"feat: add refunds", true},
+		{"fix(parser): handle empty input", true},
+		{"feat(api)!: drop v1 endpoints", true},
+		{"chore(deps/go): bump go-github", true},
+		{"revert: feat: add refunds", true},
+		{"Fixed stuff", false},
+		{"feature: add refunds", false},
+		{"feat:add refunds", false},
+		{"feat(): add refunds", false},
+		{"Feat: add refunds", false},
+	}
+
+	var issues []*github.Issue
+	for i, tc := range titles {
+		pr := createSearchedPR("testorg/repo1", i+1)
+		pr.Title = github.String(tc.title)
+		issues = append(issues, pr)
+	}
+	mockClient := &mockgithub.MockGitHubClient{
+		MockOrgRepositories: []*github.Repository{createMockRepo("testorg/repo1", false)},
+		MockSearchIssues:    issues,
+		MockReviews:         []*github.PullRequestReview{createApproval("reviewer", time.Now())},
+	}
+	service := &prchecker.Service{
+		NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+	}
+
+	cfg := newSearchConfig()
+	cfg.Monitors.PRChecker.TitlePreset = "conventional_commits"
+
+	results := prchecker.MonitorWithService(context.Background(), cfg, service)
+	if len(results) != 1 || results[0].Error != nil {
+		t.Fatalf("Expected 1 result without error, got %+v", results)
+	}
+
+	flagged := make(map[int]string)
+	for _, v := range results[0].Violations {
+		if v.Rule == prchecker.RuleTitle {
+			flagged[v.PR.Number] = v.Detail
+		}
+	}
+	for i, tc := range titles {
+		detail, ok := flagged[i+1]
+		if ok == tc.matches {
+			t.Errorf("Expected %q to match the preset %v, got violations %+v", tc.title, tc.matches, results[0].Violations)
+		}
+		// Details name the preset rather than its pattern
+		if expected := fmt.Sprintf("titled %q, not matching the title convention conventional_commits", tc.title); ok && detail != expected {
+			t.Errorf("Expected detail %q, got %q", expected, detail)
+		}
+	}
+}
+
+func TestTicketReferences(t *testing.T) {
+	tests := []struct {
+		name          string
+		title         string
+		branch        string
+		repoKeys      map[string][]string
+		expectTicket  bool // Whether the PR is flagged as untracked
+		expectFetched bool // Whether the PR had to be fetched for its head branch
+	}{
+		{
+			name:  "Key in the title",
+			title: "PAY-123 Add refunds",
+		},
+		{
+			name:          "Key in the branch",
+			title:         "Add refunds",
+			branch:        "feature/pay-123-refunds",
+			expectFetched: true,
+		},
+		{
+			name:          "Untracked change",
+			title:         "Add refunds",
+			branch:        "refunds",
+			expectTicket:  true,
+			expectFetched: true,
+		},
+		{
+			name:          "Key of another project",
+			title:         "OPS-7 Add refunds",
+			branch:        "refunds",
+			expectTicket:  true,
+			expectFetched: true,
+		},
+		{
+			name:     "Repository with its own keys",
+			title:    "OPS-7 Add refunds",
+			repoKeys: map[string][]string{"TestOrg/Repo1": {"OPS"}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pr := createSearchedPR("testorg/repo1", 7)
+			pr.Title = github.String(tc.title)
+			mockClient := &mockgithub.MockGitHubClient{
+				MockOrgRepositories: []*github.Repository{createMockRepo("testorg/repo1", false)},
+				MockSearchIssues:    []*github.Issue{pr},
+				MockReviews:         []*github.PullRequestReview{createApproval("reviewer", time.Now())},
+				MockPullRequestsByNumber: map[int]*github.PullRequest{
+					7: {Number: github.Int(7), Head: &github.PullRequestBranch{Ref: github.String(tc.branch)}},
+				},
+			}
+			service := &prchecker.Service{
+				NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+			}
+
+			cfg := newSearchConfig()
+			cfg.Monitors.PRChecker.RequireTicket = true
+			cfg.Monitors.PRChecker.TicketKeys = []string{"PAY"}
+			cfg.Monitors.PRChecker.RepoTicketKeys = tc.repoKeys
+
+			results := prchecker.MonitorWithService(context.Background(), cfg, service)
+			if len(results) != 1 || results[0].Error != nil {
+				t.Fatalf("Expected 1 result without error, got %+v", results)
+			}
+
+			untracked := len(results[0].Violations) == 1 && results[0].Violations[0].Rule == prchecker.RuleTicket
+			if untracked != tc.expectTicket {
+				t.Errorf("Expected untracked %v, got violations %+v", tc.expectTicket, results[0].Violations)
+			}
+			if (mockClient.GetPullRequestCalls > 0) != tc.expectFetched {
+				t.Errorf("Expected PR fetched %v, got %d calls", tc.expectFetched, mockClient.GetPullRequestCalls)
+			}
+		})
+	}
+}
+
+// createDismissedReview creates a review that was dismissed
+func createDismissedReview(id int64, reviewer string, submittedAt time.Time) *github.PullRequestReview {
+	return &github.PullRequestReview{
+		ID:          github.Int64(id),
+		User:        &github.User{Login: github.String(reviewer)},
+		State:       github.String("DISMISSED"),
+		SubmittedAt: &submittedAt,
+	}
+}
+
+// createDismissal creates the event of a review dismissal, of a review that was in the given state
+func createDismissal(reviewID int64, state, actor, message string, dismissedAt time.Time) *github.IssueEvent {
+	return &github.IssueEvent{
+		Event:     github.String("review_dismissed"),
+		Actor:     &github.User{Login: github.String(actor)},
+		CreatedAt: &dismissedAt,
+		DismissedReview: &github.DismissedReview{
+			State:            github.String(state),
+			ReviewID:         github.Int64(reviewID),
+			DismissalMessage: github.String(message),
+		},
+	}
+}
+
+func TestDismissedReviews(t *testing.T) {
+	merged := time.Now().Add(-time.Hour)
+	requested := merged.Add(-5 * time.Hour)
+	dismissed := merged.Add(-3 * time.Hour)
+
+	tests := []struct {
+		name          string
+		reviews       []*github.PullRequestReview
+		events        []*github.IssueEvent
+		window        time.Duration
+		expectDetail  string // Detail of the dismissal violation, none when empty
+		expectFetched bool
+	}{
+		{
+			name: "Change request dismissed by the author",
+			reviews: []*github.PullRequestReview{
+				createDismissedReview(1, "bob", requested),
+				createApproval("carol", merged.Add(-2*time.Hour)),
+			},
+			events: []*github.IssueEvent{
+				createDismissal(1, "changes_requested", "author", "outdated", dismissed),
+			},
+			expectDetail:  `changes requested by bob were dismissed by author 3h before the merge ("outdated")`,
+			expectFetched: true,
+		},
+		{
+			name: "Dismissed shortly before the merge",
+			reviews: []*github.PullRequestReview{
+				createDismissedReview(1, "bob", requested),
+				createApproval("carol", merged.Add(-2*time.Hour)),
+			},
+			events: []*github.IssueEvent{
+				createDismissal(1, "changes_requested", "maintainer", "", merged.Add(-10*time.Minute)),
+			},
+			window:        time.Hour,
+			expectDetail:  "changes requested by bob were dismissed by maintainer 10m before the merge",
+			expectFetched: true,
+		},
+		{
+			name: "Dismissed long before the merge, outside the window",
+			reviews: []*github.PullRequestReview{
+				createDismissedReview(1, "bob", requested),
+				createApproval("carol", merged.Add(-2*time.Hour)),
+			},
+			events: []*github.IssueEvent{
+				createDismissal(1, "changes_requested", "author", "outdated", dismissed),
+			},
+			window:        time.Hour,
+			expectFetched: true,
+		},
+		{
+			name: "Reviewer approved after the dismissal",
+			reviews: []*github.PullRequestReview{
+				createDismissedReview(1, "bob", requested),
+				createApproval("bob", merged.Add(-2*time.Hour)),
+			},
+			events: []*github.IssueEvent{
+				createDismissal(1, "changes_requested", "author", "", dismissed),
+			},
+			expectFetched: true,
+		},
+		{
+			name: "Dismissed approval",
+			reviews: []*github.PullRequestReview{
+				createDismissedReview(1, "bob", requested),
+				createApproval("carol", merged.Add(-2*time.Hour)),
+			},
+			events: []*github.IssueEvent{
+				createDismissal(1, "approved", "author", "", dismissed),
+			},
+			expectFetched: true,
+		},
+		{
+			name: "Dismissed after the merge",
+			reviews: []*github.PullRequestReview{
+				createDismissedReview(1, "bob", requested),
+				createApproval("carol", merged.Add(-2*time.Hour)),
+			},
+			events: []*github.IssueEvent{
+				createDismissal(1, "changes_requested", "author", "", merged.Add(time.Minute)),
+			},
+			expectFetched: true,
+		},
+		{
+			name:    "No dismissed reviews",
+			reviews: []*github.PullRequestReview{createApproval("carol", merged.Add(-2*time.Hour))},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pr := createSearchedPR("testorg/repo1", 7)
+			pr.ClosedAt = &merged
+			mockClient := &mockgithub.MockGitHubClient{
+				MockOrgRepositories: []*github.Repository{createMockRepo("testorg/repo1", false)},
+				MockSearchIssues:    []*github.Issue{pr},
+				MockReviews:         tc.reviews,
+				MockIssueEvents:     map[int][]*github.IssueEvent{7: tc.events},
+			}
+			service := &prchecker.Service{
+				NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+			}
+
+			cfg := newSearchConfig()
+			cfg.Monitors.PRChecker.FlagDismissedReviews = true
+			cfg.Monitors.PRChecker.DismissalWindow = config.Duration{Duration: tc.window}
+
+			results := prchecker.MonitorWithService(context.Background(), cfg, service)
+			if len(results) != 1 || results[0].Error != nil {
+				t.Fatalf("Expected 1 result without error, got %+v", results)
+			}
+			if len(results[0].UnapprovedPRs) != 0 {
+				t.Errorf("Expected the PR to count as approved, got %+v", results[0].UnapprovedPRs)
+			}
+
+			var detail string
+			for _, v := range results[0].Violations {
+				if v.Rule == prchecker.RuleDismissedReview {
+					detail = v.Detail
+				}
+			}
+			if detail != tc.expectDetail {
+				t.Errorf("Expected dismissal %q, got violations %+v", tc.expectDetail, results[0].Violations)
+			}
+			if (mockClient.ListIssueEventsCalls > 0) != tc.expectFetched {
+				t.Errorf("Expected events fetched %v, got %d calls", tc.expectFetched, mockClient.ListIssueEventsCalls)
+			}
+		})
+	}
+}
+
+func TestStatusPosters(t *testing.T) {
+	status := func(context, state, creator string) *github.RepoStatus {
+		return &github.RepoStatus{Context: github.String(context), State: github.String(state), Creator: &github.User{Login: github.String(creator)}}
+	}
+	checkRun := func(name, conclusion, app string) *github.CheckRun {
+		return &github.CheckRun{Name: github.String(name), Conclusion: github.String(conclusion), App: &github.App{Slug: github.String(app)}}
+	}
+
+	tests := []struct {
+		name          string
+		posters       map[string][]string
+		statuses      []*github.RepoStatus
+		runs          []*github.CheckRun
+		expectDetail  string // Detail of the status poster violation, none when empty
+		expectFetched bool
+	}{
+		{
+			name:          "Status posted by a user",
+			posters:       map[string][]string{"ci/build": {"jenkins-bot"}},
+			statuses:      []*github.RepoStatus{status("ci/build", "success", "mallory")},
+			expectDetail:  `required check "ci/build" passed by a status from mallory, not allowed to pass it`,
+			expectFetched: true,
+		},
+		{
+			name:          "Status posted by the allowed bot",
+			posters:       map[string][]string{"ci/build": {"jenkins-bot"}},
+			statuses:      []*github.RepoStatus{status("ci/build", "success", "jenkins-bot"), status("ci/build", "pending", "mallory")},
+			expectFetched: true,
+		},
+		{
+			name:          "Latest status failed",
+			posters:       map[string][]string{"ci/build": {"jenkins-bot"}},
+			statuses:      []*github.RepoStatus{status("ci/build", "failure", "jenkins-bot"), status("ci/build", "success", "mallory")},
+			expectFetched: true,
+		},
+		{
+			name:          "Check runs of allowed and other apps",
+			posters:       map[string][]string{"*": {"github-actions"}},
+			statuses:      []*github.RepoStatus{status("ci/build", "success", "github-actions[bot]")},
+			runs:          []*github.CheckRun{checkRun("ci/build", "success", "github-actions"), checkRun("ci/build", "success", "fake-ci")},
+			expectDetail:  `required check "ci/build" passed by a check run from fake-ci, not allowed to pass it`,
+			expectFetched: true,
+		},
+		{
+			name:     "Required check without an allowlist",
+			posters:  map[string][]string{"ci/lint": {"jenkins-bot"}},
+			statuses: []*github.RepoStatus{status("ci/build", "success", "mallory")},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			merged := time.Now().Add(-time.Hour)
+			pr := createSearchedPR("testorg/repo1", 7)
+			pr.ClosedAt = &merged
+			mockClient := &mockgithub.MockGitHubClient{
+				MockOrgRepositories: []*github.Repository{createMockRepo("testorg/repo1", false)},
+				MockSearchIssues:    []*github.Issue{pr},
+				MockReviews:         []*github.PullRequestReview{createApproval("carol", merged.Add(-2*time.Hour))},
+				MockPullRequestsByNumber: map[int]*github.PullRequest{7: {
+					Head: &github.PullRequestBranch{Ref: github.String("feature"), SHA: github.String("abc123")},
+					Base: &github.PullRequestBranch{Ref: github.String("main")},
+				}},
+				MockRequiredChecks: map[string][]*github.RequiredStatusCheck{"testorg/repo1:main": {{Context: "ci/build"}}},
+				MockCommitStatuses: map[string][]*github.RepoStatus{"abc123": tc.statuses},
+				MockCheckRuns:      map[string][]*github.CheckRun{"abc123": tc.runs},
+			}
+			service := &prchecker.Service{
+				NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+			}
+
+			cfg := newSearchConfig()
+			cfg.Monitors.PRChecker.StatusPosters = tc.posters
+			// The ticket rule needs the head branch too, which is fetched once for both rules
+			cfg.Monitors.PRChecker.RequireTicket = true
+
+			results := prchecker.MonitorWithService(context.Background(), cfg, service)
+			if len(results) != 1 || results[0].Error != nil {
+				t.Fatalf("Expected 1 result without error, got %+v", results)
+			}
+
+			var detail string
+			for _, v := range results[0].Violations {
+				if v.Rule == prchecker.RuleStatusPoster {
+					detail = v.Detail
+				}
+			}
+			if detail != tc.expectDetail {
+				t.Errorf("Expected status poster violation %q, got violations %+v", tc.expectDetail, results[0].Violations)
+			}
+			if (mockClient.ListCommitStatusesCalls > 0) != tc.expectFetched {
+				t.Errorf("Expected statuses fetched %v, got %d calls", tc.expectFetched, mockClient.ListCommitStatusesCalls)
+			}
+			if mockClient.GetPullRequestCalls != 1 {
+				t.Errorf("Expected the PR to be fetched once, got %d calls", mockClient.GetPullRequestCalls)
+			}
+		})
+	}
+}
+
+func TestFailingChecks(t *testing.T) {
+	status := func(context, state string) *github.RepoStatus {
+		return &github.RepoStatus{Context: github.String(context), State: github.String(state)}
+	}
+	checkRun := func(name, conclusion string) *github.CheckRun {
+		return &github.CheckRun{Name: github.String(name), Conclusion: github.String(conclusion)}
+	}
+
+	tests := []struct {
+		name         string
+		posters      map[string][]string
+		statuses     []*github.RepoStatus
+		runs         []*github.CheckRun
+		expectDetail string // Detail of the failing checks violation, none when empty
+	}{
+		{
+			name:     "Required checks passed",
+			statuses: []*github.RepoStatus{status("ci/build", "success")},
+			runs:     []*github.CheckRun{checkRun("ci/lint", "skipped")},
+		},
+		{
+			name:         "Latest status failed",
+			statuses:     []*github.RepoStatus{status("ci/build", "failure"), status("ci/build", "success")},
+			runs:         []*github.CheckRun{checkRun("ci/lint", "success")},
+			expectDetail: `merged with failing required checks "ci/build"`,
+		},
+		{
+			name:         "Failed and missing checks",
+			runs:         []*github.CheckRun{checkRun("ci/lint", "failure"), {Name: github.String("ci/lint"), Status: github.String("in_progress")}},
+			expectDetail: `merged with failing required checks "ci/lint" and missing required checks "ci/build"`,
+		},
+		{
+			name:     "Check passed by a rerun",
+			statuses: []*github.RepoStatus{status("ci/build", "pending")},
+			runs:     []*github.CheckRun{checkRun("ci/lint", "failure"), checkRun("ci/lint", "success"), checkRun("ci/build", "success")},
+		},
+		{
+			name:         "Shared with the status poster rule",
+			posters:      map[string][]string{"ci/build": {"jenkins-bot"}},
+			statuses:     []*github.RepoStatus{status("ci/build", "error")},
+			expectDetail: `merged with failing required checks "ci/build" and missing required checks "ci/lint"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			merged := time.Now().Add(-time.Hour)
+			pr := createSearchedPR("testorg/repo1", 7)
+			pr.ClosedAt = &merged
+			mockClient := &mockgithub.MockGitHubClient{
+				MockOrgRepositories: []*github.Repository{createMockRepo("testorg/repo1", false)},
+				MockSearchIssues:    []*github.Issue{pr},
+				MockReviews:         []*github.PullRequestReview{createApproval("carol", merged.Add(-2*time.Hour))},
+				MockPullRequestsByNumber: map[int]*github.PullRequest{7: {
+					Head: &github.PullRequestBranch{Ref: github.String("feature"), SHA: github.String("abc123")},
+					Base: &github.PullRequestBranch{Ref: github.String("main")},
+				}},
+				MockRequiredChecks: map[string][]*github.RequiredStatusCheck{"testorg/repo1:main": {{Context: "ci/build"}, {Context: "ci/lint"}}},
+				MockCommitStatuses: map[string][]*github.RepoStatus{"abc123": tc.statuses},
+				MockCheckRuns:      map[string][]*github.CheckRun{"abc123": tc.runs},
+			}
+			service := &prchecker.Service{
+				NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+			}
+
+			cfg := newSearchConfig()
+			cfg.Monitors.PRChecker.FlagFailingChecks = true
+			cfg.Monitors.PRChecker.StatusPosters = tc.posters
+
+			results := prchecker.MonitorWithService(context.Background(), cfg, service)
+			if len(results) != 1 || results[0].Error != nil {
+				t.Fatalf("Expected 1 result without error, got %+v", results)
+			}
+
+			var detail string
+			for _, v := range results[0].Violations {
+				if v.Rule == prchecker.RuleFailingChecks {
+					detail = v.Detail
+				}
+			}
+			if detail != tc.expectDetail {
+				t.Errorf("Expected failing checks violation %q, got violations %+v", tc.expectDetail, results[0].Violations)
+			}
+			// Statuses and check runs are fetched once, even when both rules about required checks need them
+			if mockClient.ListCommitStatusesCalls != 1 || mockClient.ListCheckRunsCalls != 1 {
+				t.Errorf("Expected the checks fetched once, got %d and %d calls", mockClient.ListCommitStatusesCalls, mockClient.ListCheckRunsCalls)
+			}
+		})
+	}
+}
+
+func TestMergeMethods(t *testing.T) {
+	commit := func(message string, parents int) *github.RepositoryCommit {
+		c := &github.RepositoryCommit{Commit: &github.Commit{Message: github.String(message)}}
+		for i := 0; i < parents; i++ {
+			c.Parents = append(c.Parents, &github.Commit{})
+		}
+		return c
+	}
+	prCommits := []*github.RepositoryCommit{commit("Add retries", 1), commit("Fix lint", 1)}
+
+	tests := []struct {
+		name          string
+		allowed       []string
+		merged        *github.RepositoryCommit
+		expectDetail  string // Detail of the merge method violation, none when empty
+		expectCommits bool   // Whether the commits of the PR were listed to tell squashing from rebasing
+	}{
+		{
+			name:         "Merge commit where only squash merges are allowed",
+			allowed:      []string{"squash"},
+			merged:       commit("Merge pull request #7 from testorg/feature", 2),
+			expectDetail: "merged with a merge commit by admin, the allowed merge methods are squash",
+		},
+		{
+			name:          "Squash merge",
+			allowed:       []string{"squash"},
+			merged:        commit("PR 7 (#7)\n\n* Add retries\n* Fix lint", 1),
+			expectCommits: true,
+		},
+		{
+			name:          "Rebase merge where merge commits are forbidden",
+			allowed:       []string{"squash", "rebase"},
+			merged:        commit("Fix lint", 1),
+			expectCommits: true,
+		},
+		{
+			name:          "Rebase merge where only squash merges are allowed",
+			allowed:       []string{"squash"},
+			merged:        commit("Fix lint", 1),
+			expectDetail:  "merged with a rebase merge by admin, the allowed merge methods are squash",
+			expectCommits: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			merged := time.Now().Add(-time.Hour)
+			pr := createSearchedPR("testorg/repo1", 7)
+			pr.ClosedAt = &merged
+			mockClient := &mockgithub.MockGitHubClient{
+				MockOrgRepositories: []*github.Repository{createMockRepo("testorg/repo1", false)},
+				MockSearchIssues:    []*github.Issue{pr},
+				MockReviews:         []*github.PullRequestReview{createApproval("carol", merged.Add(-2*time.Hour))},
+				MockPullRequestsByNumber: map[int]*github.PullRequest{7: {
+					Head:           &github.PullRequestBranch{Ref: github.String("feature"), SHA: github.String("abc123")},
+					Base:           &github.PullRequestBranch{Ref: github.String("main")},
+					MergeCommitSHA: github.String("def456"),
+					MergedBy:       &github.User{Login: github.String("admin")},
+				}},
+				MockCommits:   map[string]*github.RepositoryCommit{"def456": tc.merged},
+				MockPRCommits: map[int][]*github.RepositoryCommit{7: prCommits},
+			}
+			service := &prchecker.Service{
+				NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+			}
+
+			cfg := newSearchConfig()
+			cfg.Monitors.PRChecker.AllowedMergeMethods = tc.allowed
+
+			results := prchecker.MonitorWithService(context.Background(), cfg, service)
+			if len(results) != 1 || results[0].Error != nil {
+				t.Fatalf("Expected 1 result without error, got %+v", results)
+			}
+
+			var detail string
+			for _, v := range results[0].Violations {
+				if v.Rule == prchecker.RuleMergeMethod {
+					detail = v.Detail
+				}
+			}
+			if detail != tc.expectDetail {
+				t.Errorf("Expected merge method violation %q, got violations %+v", tc.expectDetail, results[0].Violations)
+			}
+			if (mockClient.ListPullRequestCommitsCalls > 0) != tc.expectCommits {
+				t.Errorf("Expected commits listed %v, got %d calls", tc.expectCommits, mockClient.ListPullRequestCommitsCalls)
+			}
+			// Who merged the PR comes with the PR fetched for its merge commit
+			if mockClient.GetPullRequestCalls != 1 || mockClient.GetCommitCalls != 1 {
+				t.Errorf("Expected the PR and its merge commit fetched once, got %d and %d calls", mockClient.GetPullRequestCalls, mockClient.GetCommitCalls)
+			}
+		})
+	}
+}
+
+func TestPathScopes(t *testing.T) {
+	tests := []struct {
+		name            string
+		repoPaths       map[string][]string
+		files           []string
+		expectFlagged   bool // Whether the unapproved PR is reported
+		expectListFiles bool
+	}{
+		{
+			name:          "Repository without a scope",
+			files:         []string{"docs/README.md"},
+			expectFlagged: true,
+		},
+		{
+			name:            "Change in scope",
+			repoPaths:       map[string][]string{"TestOrg/Repo1": {"infra/**", "payments/"}},
+			files:           []string{"docs/README.md", "payments/refunds/api.go"},
+			expectFlagged:   true,
+			expectListFiles: true,
+		},
+		{
+			name:            "Change outside the scope",
+			repoPaths:       map[string][]string{"testorg/repo1": {"infra/**", "payments/"}},
+			files:           []string{"docs/README.md", "infrastructure.md"},
+			expectListFiles: true,
+		},
+		{
+			name:            "Single star stays within a directory",
+			repoPaths:       map[string][]string{"testorg/repo1": {"infra/*.tf"}},
+			files:           []string{"infra/modules/vpc.tf"},
+			expectListFiles: true,
+		},
+		{
+			name:            "Double star matches at any depth",
+			repoPaths:       map[string][]string{"testorg/repo1": {"**/*.tf"}},
+			files:           []string{"main.tf"},
+			expectFlagged:   true,
+			expectListFiles: true,
+		},
+		{
+			name:            "Scope of another repository",
+			repoPaths:       map[string][]string{"testorg/repo2": {"infra/**"}},
+			files:           []string{"docs/README.md"},
+			expectFlagged:   true,
+			expectListFiles: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &mockgithub.MockGitHubClient{
+				MockOrgRepositories: []*github.Repository{createMockRepo("testorg/repo1", false)},
+				MockSearchIssues:    []*github.Issue{createSearchedPR("testorg/repo1", 7)},
+				MockPRFiles:         map[int][]string{7: tc.files},
+			}
+			service := &prchecker.Service{
+				NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+			}
+
+			cfg := newSearchConfig()
+			cfg.Monitors.PRChecker.RepoPaths = tc.repoPaths
+
+			results := prchecker.MonitorWithService(context.Background(), cfg, service)
+			if len(results) != 1 || results[0].Error != nil {
+				t.Fatalf("Expected 1 result without error, got %+v", results)
+			}
+
+			if flagged := len(results[0].UnapprovedPRs) == 1; flagged != tc.expectFlagged {
+				t.Errorf("Expected flagged %v, got %+v", tc.expectFlagged, results[0].UnapprovedPRs)
+			}
+			if (mockClient.ListPullRequestFilesCalls > 0) != tc.expectListFiles {
+				t.Errorf("Expected files listed %v, got %d calls", tc.expectListFiles, mockClient.ListPullRequestFilesCalls)
+			}
+		})
+	}
+}
+
+func TestReviewerTeams(t *testing.T) {
+	tests := []struct {
+		name          string
+		teams         []string
+		repoTeams     map[string][]string
+		approvers     []string
+		membershipErr error
+		expectFlagged bool
+		expectError   bool
+	}{
+		{
+			name:      "Without reviewer teams",
+			approvers: []string{"bob"},
+		},
+		{
+			name:      "Approved by a team member",
+			teams:     []string{"testorg/security", "testorg/platform"},
+			approvers: []string{"bob", "carol"},
+		},
+		{
+			name:          "Approved by no team member",
+			teams:         []string{"testorg/security"},
+			approvers:     []string{"bob"},
+			expectFlagged: true,
+		},
+		{
+			name:          "Teams of the repository replace the central teams",
+			teams:         []string{"testorg/platform"},
+			repoTeams:     map[string][]string{"TestOrg/Repo1": {"testorg/security"}},
+			approvers:     []string{"bob"},
+			expectFlagged: true,
+		},
+		{
+			name:          "Membership lookup fails",
+			teams:         []string{"testorg/security"},
+			approvers:     []string{"carol"},
+			membershipErr: errors.New("API error"),
+			expectError:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var reviews []*github.PullRequestReview
+			for _, approver := range tc.approvers {
+				reviews = append(reviews, createApproval(approver, time.Now().Add(-time.Hour)))
+			}
+			mockClient := &mockgithub.MockGitHubClient{
+				MockOrgRepositories: []*github.Repository{createMockRepo("testorg/repo1", false)},
+				MockSearchIssues:    []*github.Issue{createSearchedPR("testorg/repo1", 7)},
+				MockReviews:         reviews,
+				MockTeamMemberships: map[string]bool{
+					"testorg/security/carol": true,
+					"testorg/platform/bob":   true,
+				},
+				MockMembershipErr: tc.membershipErr,
+			}
+			service := &prchecker.Service{
+				NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+			}
+
+			cfg := newSearchConfig()
+			cfg.Monitors.PRChecker.RequiredReviewerTeams = tc.teams
+			cfg.Monitors.PRChecker.RepoReviewerTeams = tc.repoTeams
+
+			results := prchecker.MonitorWithService(context.Background(), cfg, service)
+			if len(results) != 1 {
+				t.Fatalf("Expected 1 result, got %+v", results)
+			}
+			if (results[0].Error != nil) != tc.expectError {
+				t.Fatalf("Expected error %v, got %v", tc.expectError, results[0].Error)
+			}
+			if flagged := len(results[0].UnapprovedPRs) == 1; flagged != tc.expectFlagged {
+				t.Errorf("Expected flagged %v, got %+v", tc.expectFlagged, results[0].UnapprovedPRs)
+			}
+		})
+	}
+}
+
+func TestOutsideApprovals(t *testing.T) {
+	tests := []struct {
+		name          string
+		approvers     []string
+		membershipErr error
+		expectDetail  string // Detail of the outside approval, none when empty
+		expectLookups int
+		expectError   bool
+	}{
+		{
+			name:          "Approved by a member",
+			approvers:     []string{"carol"},
+			expectLookups: 1,
+		},
+		{
+			name:          "Approved by outside collaborators only",
+			approvers:     []string{"contractor", "vendor"},
+			expectDetail:  "approved only by reviewers outside the testorg organization (contractor, vendor)",
+			expectLookups: 2,
+		},
+		{
+			name:          "Approved by an outside collaborator and a member",
+			approvers:     []string{"carol", "contractor"},
+			expectLookups: 1,
+		},
+		{
+			name: "Unapproved PR",
+		},
+		{
+			name:          "Membership lookup fails",
+			approvers:     []string{"contractor"},
+			membershipErr: errors.New("API error"),
+			expectLookups: 1,
+			expectError:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var reviews []*github.PullRequestReview
+			for i, approver := range tc.approvers {
+				reviews = append(reviews, createApproval(approver, time.Now().Add(-time.Duration(len(tc.approvers)-i)*time.Hour)))
+			}
+			mockClient := &mockgithub.MockGitHubClient{
+				MockOrgRepositories: []*github.Repository{createMockRepo("testorg/repo1", false)},
+				MockSearchIssues:    []*github.Issue{createSearchedPR("testorg/repo1", 7)},
+				MockReviews:         reviews,
+				MockOrgMemberships:  map[string]bool{"testorg/carol": true},
+				MockMembershipErr:   tc.membershipErr,
+			}
+			service := &prchecker.Service{
+				NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+			}
+
+			cfg := newSearchConfig()
+			cfg.Monitors.PRChecker.FlagOutsideApprovals = true
+
+			results := prchecker.MonitorWithService(context.Background(), cfg, service)
+			if len(results) != 1 {
+				t.Fatalf("Expected 1 result, got %+v", results)
+			}
+			if (results[0].Error != nil) != tc.expectError {
+				t.Fatalf("Expected error %v, got %v", tc.expectError, results[0].Error)
+			}
+
+			var detail string
+			for _, v := range results[0].Violations {
+				if v.Rule == prchecker.RuleOutsideApproval {
+					detail = v.Detail
+				}
+			}
+			if detail != tc.expectDetail {
+				t.Errorf("Expected outside approval %q, got violations %+v", tc.expectDetail, results[0].Violations)
+			}
+			// Lookups stop at the first member
+			if mockClient.IsOrgMemberCalls != tc.expectLookups {
+				t.Errorf("Expected %d membership lookups, got %d", tc.expectLookups, mockClient.IsOrgMemberCalls)
+			}
+		})
+	}
+}
+
+func TestMergeHours(t *testing.T) {
+	tests := []struct {
+		name         string
+		mergedAt     time.Time
+		expectDetail string // Detail of the merge hours violation, none when empty
+	}{
+		{
+			name:     "Merged during business hours",
+			mergedAt: time.Date(2024, 3, 12, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Merged at the start of business hours",
+			mergedAt: time.Date(2024, 3, 11, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "Merged in the evening",
+			mergedAt:     time.Date(2024, 3, 12, 17, 30, 0, 0, time.UTC),
+			expectDetail: "merged Tue 2024-03-12 18:30 CET, outside the merge hours (mon, tue, wed, thu, fri 09:00-17:00 Europe/Berlin)",
+		},
+		{
+			name:         "Merged at the end of business hours",
+			mergedAt:     time.Date(2024, 3, 14, 16, 0, 0, 0, time.UTC),
+			expectDetail: "merged Thu 2024-03-14 17:00 CET, outside the merge hours (mon, tue, wed, thu, fri 09:00-17:00 Europe/Berlin)",
+		},
+		{
+			name:         "Merged on a weekend",
+			mergedAt:     time.Date(2024, 3, 16, 10, 0, 0, 0, time.UTC),
+			expectDetail: "merged Sat 2024-03-16 11:00 CET, outside the merge hours (mon, tue, wed, thu, fri 09:00-17:00 Europe/Berlin)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pr := createSearchedPR("testorg/repo1", 7)
+			pr.ClosedAt = &tc.mergedAt
+			mockClient := &mockgithub.MockGitHubClient{
+				MockOrgRepositories: []*github.Repository{createMockRepo("testorg/repo1", false)},
+				MockSearchIssues:    []*github.Issue{pr},
+				MockReviews:         []*github.PullRequestReview{createApproval("reviewer", tc.mergedAt.Add(-time.Hour))},
+			}
+			service := &prchecker.Service{
+				NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+			}
+
+			cfg := newSearchConfig()
+			cfg.Monitors.PRChecker.TimeWindow = config.Hours(24 * 365 * 10)
+			cfg.Monitors.PRChecker.MergeHours = config.MergeHoursConfig{
+				Enabled:  true,
+				Days:     []string{"mon", "tue", "wed", "thu", "fri"},
+				Start:    "09:00",
+				End:      "17:00",
+				Timezone: "Europe/Berlin",
+			}
+
+			results := prchecker.MonitorWithService(context.Background(), cfg, service)
+			if len(results) != 1 || results[0].Error != nil {
+				t.Fatalf("Expected 1 result without error, got %+v", results)
+			}
+
+			var detail string
+			for _, v := range results[0].Violations {
+				if v.Rule == prchecker.RuleMergeHours {
+					detail = v.Detail
+				}
+			}
+			if detail != tc.expectDetail {
+				t.Errorf("Expected merge hours violation %q, got violations %+v", tc.expectDetail, results[0].Violations)
+			}
+		})
+	}
+}
+
+func TestProtectionBypasses(t *testing.T) {
+	tests := []struct {
+		name         string
+		reviews      []*github.PullRequestReview
+		required     int
+		expectDetail string // Detail of the protection bypass, none when empty
+	}{
+		{
+			name:         "Unapproved PR merged into a branch requiring approval",
+			required:     1,
+			expectDetail: "merged by admin with 0 of the 1 approvals required by the protection of main",
+		},
+		{
+			name:         "Fewer approvals than required",
+			reviews:      []*github.PullRequestReview{createApproval("carol", time.Now().Add(-2*time.Hour))},
+			required:     2,
+			expectDetail: "merged by admin with 1 of the 2 approvals required by the protection of main",
+		},
+		{
+			name:     "Approvals the protection requires",
+			reviews:  []*github.PullRequestReview{createApproval("carol", time.Now().Add(-2*time.Hour))},
+			required: 1,
+		},
+		{
+			name: "Branch requiring no reviews",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &mockgithub.MockGitHubClient{
+				MockOrgRepositories: []*github.Repository{createMockRepo("testorg/repo1", false)},
+				MockSearchIssues:    []*github.Issue{createSearchedPR("testorg/repo1", 7)},
+				MockReviews:         tc.reviews,
+				MockPullRequestsByNumber: map[int]*github.PullRequest{7: {
+					Head:     &github.PullRequestBranch{Ref: github.String("feature"), SHA: github.String("abc123")},
+					Base:     &github.PullRequestBranch{Ref: github.String("main")},
+					MergedBy: &github.User{Login: github.String("admin")},
+				}},
+				MockRequiredReviews: map[string]int{"testorg/repo1:main": tc.required},
+			}
+			service := &prchecker.Service{
+				NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+			}
+
+			cfg := newSearchConfig()
+			cfg.Monitors.PRChecker.FlagProtectionBypasses = true
+
+			results := prchecker.MonitorWithService(context.Background(), cfg, service)
+			if len(results) != 1 || results[0].Error != nil {
+				t.Fatalf("Expected 1 result without error, got %+v", results)
+			}
+
+			var detail string
+			for _, v := range results[0].Violations {
+				if v.Rule == prchecker.RuleProtectionBypass {
+					detail = v.Detail
+				}
+			}
+			if detail != tc.expectDetail {
+				t.Errorf("Expected protection bypass %q, got violations %+v", tc.expectDetail, results[0].Violations)
+			}
+			if mockClient.GetPullRequestCalls != 1 {
+				t.Errorf("Expected the PR to be fetched once, got %d calls", mockClient.GetPullRequestCalls)
+			}
+		})
+	}
+}
+
+func TestProtectionBypassReport(t *testing.T) {
+	pr := prchecker.PR{Number: 7, Title: "Hotfix", Author: "alice", URL: "https://github.com/testorg/repo1/pull/7"}
+	results := []prchecker.Result{{
+		Repository: "testorg/repo1",
+		Violations: []prchecker.Violation{
+			{PR: pr, Rule: prchecker.RuleProtectionBypass, Detail: "merged by admin with 0 of the 1 approvals required by the protection of main"},
+			{PR: pr, Rule: prchecker.RuleTicket, Detail: "references an issue tracker key in neither its title nor its branch \"hotfix\""},
+		},
+	}}
+
+	var buf bytes.Buffer
+	prchecker.WriteResultsMarkdown(&buf, results)
+	output := buf.String()
+	if !strings.Contains(output, "Branch Protection Bypasses") || !strings.Contains(output, "Found 1 pull requests merged with fewer approvals") {
+		t.Errorf("Expected a section of protection bypasses, got %s", output)
+	}
+	if !strings.Contains(output, "Found 1 merged pull requests breaking review rules") {
+		t.Errorf("Expected protection bypasses to be left out of the review rule violations, got %s", output)
+	}
+
+	list := prchecker.Findings(results)
+	if len(list) != 2 || list[0].Rule != prchecker.RuleProtectionBypass || list[0].Severity != findings.SeverityHigh {
+		t.Errorf("Expected a high severity protection bypass finding, got %+v", list)
+	}
+}
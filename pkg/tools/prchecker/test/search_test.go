@@ -0,0 +1,193 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	mockgithub "github.com/anupsv/git-monitoring/pkg/tools/common/test"
+	"github.com/anupsv/git-monitoring/pkg/tools/prchecker"
+	"github.com/google/go-github/v45/github"
+)
+
+// createSearchedPR creates a merged PR as returned by the search API
+func createSearchedPR(repository string, number int) *github.Issue {
+	return &github.Issue{
+		Number:        github.Int(number),
+		Title:         github.String(fmt.Sprintf("PR %d", number)),
+		URL:           github.String(fmt.Sprintf("https://api.github.com/repos/%s/issues/%d", repository, number)),
+		HTMLURL:       github.String(fmt.Sprintf("https://github.com/%s/pull/%d", repository, number)),
+		RepositoryURL: github.String("https://api.github.com/repos/" + repository),
+		User:          &github.User{Login: github.String("author")},
+	}
+}
+
+// newSearchConfig creates a config checking the repositories of testorg with the search API
+func newSearchConfig() *config.Config {
+	return &config.Config{
+		GitHub: config.GitHubConfig{Token: "test-token"},
+		Monitors: config.MonitorsConfig{
+			PRChecker: config.PRCheckerConfig{
+				Enabled:        true,
+				RepoVisibility: "all",
+				Organization:   "testorg",
+				Discovery:      "search",
+				TimeWindow:     config.Hours(24),
+			},
+		},
+	}
+}
+
+func TestSearchDiscovery(t *testing.T) {
+	mockClient := &mockgithub.MockGitHubClient{
+		MockOrgRepositories: []*github.Repository{
+			createMockRepo("testorg/repo1", false),
+			createMockRepo("testorg/repo2", false),
+		},
+		MockSearchIssues: []*github.Issue{
+			createSearchedPR("testorg/repo1", 1),
+			createSearchedPR("testorg/repo1", 2),
+			createSearchedPR("testorg/other", 5), // Not among the selected repositories
+		},
+		ListPullRequestReviewsFunc: func(ctx context.Context, owner, repo string, number int, opts *github.ListOptions) ([]*github.PullRequestReview, *github.Response, error) {
+			if number == 1 {
+				return []*github.PullRequestReview{{User: &github.User{Login: github.String("reviewer")}, State: github.String("APPROVED")}}, nil, nil
+			}
+			return nil, nil, nil
+		},
+	}
+	service := &prchecker.Service{
+		NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+	}
+
+	results := prchecker.MonitorWithService(context.Background(), newSearchConfig(), service)
+
+	if len(results) != 2 {
+		t.Fatalf("Expected results for 2 repositories, got %d", len(results))
+	}
+	if results[0].Repository != "testorg/repo1" || len(results[0].UnapprovedPRs) != 1 || results[0].UnapprovedPRs[0].Number != 2 {
+		t.Errorf("Expected PR #2 of testorg/repo1 to be unapproved, got %+v", results[0])
+	}
+	if results[1].Repository != "testorg/repo2" || len(results[1].UnapprovedPRs) != 0 || results[1].Error != nil {
+		t.Errorf("Expected testorg/repo2 without unapproved PRs, got %+v", results[1])
+	}
+
+	// Merged PRs are found with one search instead of listing the PRs of each repository
+	if mockClient.SearchMergedPullRequestsCalls != 1 || mockClient.GetPullRequestsCalls != 0 {
+		t.Errorf("Expected 1 search and no PR listings, got %d and %d", mockClient.SearchMergedPullRequestsCalls, mockClient.GetPullRequestsCalls)
+	}
+	if mockClient.ListPullRequestReviewsCalls != 2 {
+		t.Errorf("Expected the reviews of 2 PRs to be fetched, got %d", mockClient.ListPullRequestReviewsCalls)
+	}
+}
+
+func TestSearchSplitsLargePeriods(t *testing.T) {
+	mockClient := &mockgithub.MockGitHubClient{
+		MockOrgRepositories: []*github.Repository{createMockRepo("testorg/repo1", false)},
+		// Periods over 13 hours have more merges than a search returns, both halves find the PR merged between them
+		SearchMergedPRsFunc: func(ctx context.Context, org string, from, to time.Time) ([]*github.Issue, int, error) {
+			if to.Sub(from) > 13*time.Hour {
+				return []*github.Issue{createSearchedPR("testorg/repo1", 1)}, 1500, nil
+			}
+			return []*github.Issue{createSearchedPR("testorg/repo1", 1)}, 1, nil
+		},
+	}
+	service := &prchecker.Service{
+		NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+	}
+
+	results := prchecker.MonitorWithService(context.Background(), newSearchConfig(), service)
+
+	if mockClient.SearchMergedPullRequestsCalls != 3 {
+		t.Errorf("Expected the period to be searched in two halves after the first search, got %d searches", mockClient.SearchMergedPullRequestsCalls)
+	}
+	if len(results) != 1 || len(results[0].UnapprovedPRs) != 1 {
+		t.Fatalf("Expected 1 unapproved PR found once, got %+v", results)
+	}
+
+	// Searches that fail are reported for the organization
+	mockClient.SearchMergedPRsFunc = nil
+	mockClient.MockSearchErr = fmt.Errorf("API error")
+	results = prchecker.MonitorWithService(context.Background(), newSearchConfig(), service)
+	if len(results) != 1 || results[0].Repository != "org:testorg" || results[0].Error == nil {
+		t.Errorf("Expected the search error to be reported for org:testorg, got %+v", results)
+	}
+}
+
+func TestSearchBaseBranches(t *testing.T) {
+	mockClient := &mockgithub.MockGitHubClient{
+		MockOrgRepositories: []*github.Repository{createMockRepo("testorg/repo1", false)},
+		MockSearchIssues: []*github.Issue{
+			createSearchedPR("testorg/repo1", 1),
+			createSearchedPR("testorg/repo1", 2),
+		},
+		MockPullRequestsByNumber: map[int]*github.PullRequest{
+			1: {Base: &github.PullRequestBranch{Ref: github.String("main")}, Head: &github.PullRequestBranch{Ref: github.String("ABC-1-fix")}},
+			2: {Base: &github.PullRequestBranch{Ref: github.String("feature/login")}},
+		},
+	}
+	service := &prchecker.Service{
+		NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+	}
+
+	cfg := newSearchConfig()
+	cfg.Monitors.PRChecker.BaseBranches = []string{"main"}
+	// The ticket rule needs the head branch, which is known from fetching the base branch
+	cfg.Monitors.PRChecker.RequireTicket = true
+
+	results := prchecker.MonitorWithService(context.Background(), cfg, service)
+	if len(results) != 1 || results[0].Error != nil {
+		t.Fatalf("Expected 1 result without error, got %+v", results)
+	}
+	if len(results[0].UnapprovedPRs) != 1 || results[0].UnapprovedPRs[0].Number != 1 {
+		t.Errorf("Expected only the PR merged into main to be checked, got %+v", results[0].UnapprovedPRs)
+	}
+	if len(results[0].Violations) != 0 {
+		t.Errorf("Expected the ticket in the head branch to be found, got %+v", results[0].Violations)
+	}
+	if mockClient.GetPullRequestCalls != 2 {
+		t.Errorf("Expected each PR to be fetched once, got %d calls", mockClient.GetPullRequestCalls)
+	}
+}
+
+func TestSearchRepoOverrides(t *testing.T) {
+	closed := time.Now().Add(-48 * time.Hour)
+	earlier, later := createSearchedPR("testorg/repo1", 1), createSearchedPR("testorg/repo2", 2)
+	earlier.ClosedAt, later.ClosedAt = &closed, &closed
+	var from time.Time
+	mockClient := &mockgithub.MockGitHubClient{
+		MockOrgRepositories: []*github.Repository{
+			createMockRepo("testorg/repo1", false),
+			createMockRepo("testorg/repo2", false),
+		},
+		SearchMergedPRsFunc: func(ctx context.Context, org string, start, end time.Time) ([]*github.Issue, int, error) {
+			from = start
+			return []*github.Issue{earlier, later}, 2, nil
+		},
+	}
+	service := &prchecker.Service{
+		NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+	}
+
+	cfg := newSearchConfig()
+	cfg.Monitors.PRChecker.RepoOverrides = []config.PRCheckerOverride{{Repository: "testorg/repo2", TimeWindow: config.Hours(72)}}
+
+	results := prchecker.MonitorWithService(context.Background(), cfg, service)
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %+v", results)
+	}
+
+	// The search spans the longest time window, and each repository keeps the PRs merged within its own
+	if since := time.Since(from); since < 71*time.Hour || since > 73*time.Hour {
+		t.Errorf("Expected the search to start 72 hours ago, started %v ago", since)
+	}
+	if len(results[0].UnapprovedPRs) != 0 {
+		t.Errorf("Expected no unapproved PRs in testorg/repo1, got %+v", results[0].UnapprovedPRs)
+	}
+	if len(results[1].UnapprovedPRs) != 1 || results[1].UnapprovedPRs[0].Number != 2 {
+		t.Errorf("Expected PR 2 of testorg/repo2 unapproved, got %+v", results[1].UnapprovedPRs)
+	}
+}
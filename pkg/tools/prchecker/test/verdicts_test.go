@@ -0,0 +1,140 @@
+package test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/state"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	mockgithub "github.com/anupsv/git-monitoring/pkg/tools/common/test"
+	"github.com/anupsv/git-monitoring/pkg/tools/prchecker"
+	"github.com/google/go-github/v45/github"
+)
+
+// createMergedPR creates a PR merged an hour ago with the given merge commit
+func createMergedPR(number int, mergeSHA string) *github.PullRequest {
+	mergedAt := time.Now().Add(-time.Hour)
+	pr := createMockPR(number, "Fix bug", "author", "https://github.com/testorg/repo1/pull/1", mergedAt.Add(-time.Hour), &mergedAt)
+	pr.UpdatedAt = &mergedAt
+	pr.MergeCommitSHA = github.String(mergeSHA)
+	pr.Head = &github.PullRequestBranch{Ref: github.String("fix-bug")}
+	return pr
+}
+
+func TestVerdictCache(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{Token: "test-token"},
+		State:  config.StateConfig{Enabled: true, Path: statePath},
+		Monitors: config.MonitorsConfig{
+			PRChecker: config.PRCheckerConfig{
+				Enabled:              true,
+				RepoVisibility:       "specific",
+				SpecificRepositories: []string{"testorg/repo1"},
+				TimeWindow:           config.Hours(24),
+				RequireTicket:        true,
+				CacheVerdicts:        true,
+			},
+		},
+	}
+
+	run := func(prs ...*github.PullRequest) (*mockgithub.MockGitHubClient, []prchecker.Result) {
+		mockClient := &mockgithub.MockGitHubClient{MockPullRequests: prs, MockPullRequestResp: &github.Response{}}
+		service := &prchecker.Service{
+			NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+		}
+		results := prchecker.MonitorWithService(context.Background(), cfg, service)
+		if len(results) != 1 || results[0].Error != nil {
+			t.Fatalf("Expected 1 result without error, got %+v", results)
+		}
+		return mockClient, results
+	}
+
+	// The first run checks the PR
+	mockClient, results := run(createMergedPR(1, "abc123"))
+	if mockClient.ListPullRequestReviewsCalls != 1 {
+		t.Fatalf("Expected the reviews of the PR to be fetched, got %d calls", mockClient.ListPullRequestReviewsCalls)
+	}
+	if len(results[0].UnapprovedPRs) != 1 || len(results[0].Violations) != 1 {
+		t.Fatalf("Expected the PR to be unapproved and without ticket, got %+v", results[0])
+	}
+
+	// The next run reuses its verdict and only checks the PR merged since
+	mockClient, results = run(createMergedPR(1, "abc123"), createMergedPR(2, "def456"))
+	if mockClient.ListPullRequestReviewsCalls != 1 {
+		t.Errorf("Expected only the reviews of the new PR to be fetched, got %d calls", mockClient.ListPullRequestReviewsCalls)
+	}
+	if len(results[0].UnapprovedPRs) != 2 || len(results[0].Violations) != 2 {
+		t.Errorf("Expected the cached verdict to be reported like a fresh one, got %+v", results[0])
+	}
+	if results[0].Violations[0].Rule != prchecker.RuleTicket || results[0].Violations[0].PR.Number != 1 {
+		t.Errorf("Unexpected cached violation: %+v", results[0].Violations[0])
+	}
+
+	// A PR merged again has another merge commit and is checked again
+	mockClient, _ = run(createMergedPR(1, "789abc"))
+	if mockClient.ListPullRequestReviewsCalls != 1 {
+		t.Errorf("Expected a PR with another merge commit to be checked, got %d calls", mockClient.ListPullRequestReviewsCalls)
+	}
+
+	// Verdicts are not reused once the rules change
+	cfg.Monitors.PRChecker.RequireTicket = false
+	mockClient, results = run(createMergedPR(2, "def456"))
+	if mockClient.ListPullRequestReviewsCalls != 1 || len(results[0].Violations) != 0 {
+		t.Errorf("Expected the PR to be checked against the new rules, got %d calls and %+v", mockClient.ListPullRequestReviewsCalls, results[0].Violations)
+	}
+
+	s, err := state.Load(statePath)
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+	if len(s.Verdicts) != 3 {
+		t.Errorf("Expected the verdicts of 3 merged PRs in the state, got %d", len(s.Verdicts))
+	}
+}
+
+func TestVerdictCacheDropsPRsOutsideTheWindow(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	err := state.Update(statePath, func(s *state.State) error {
+		s.Verdicts = map[string]state.Verdict{
+			"testorg/repo1#9@old": {MergedAt: time.Now().Add(-48 * time.Hour), InScope: true},
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
+
+	mockClient := &mockgithub.MockGitHubClient{
+		MockPullRequests:    []*github.PullRequest{createMergedPR(1, "abc123")},
+		MockPullRequestResp: &github.Response{},
+	}
+	service := &prchecker.Service{
+		NewClient: func(ctx context.Context, token string) common.GitHubClientInterface { return mockClient },
+	}
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{Token: "test-token"},
+		State:  config.StateConfig{Enabled: true, Path: statePath},
+		Monitors: config.MonitorsConfig{
+			PRChecker: config.PRCheckerConfig{
+				Enabled:              true,
+				RepoVisibility:       "specific",
+				SpecificRepositories: []string{"testorg/repo1"},
+				TimeWindow:           config.Hours(24),
+				CacheVerdicts:        true,
+			},
+		},
+	}
+	prchecker.MonitorWithService(context.Background(), cfg, service)
+
+	s, err := state.Load(statePath)
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+	if _, ok := s.Verdicts["testorg/repo1#9@old"]; ok || len(s.Verdicts) != 1 {
+		t.Errorf("Expected only the verdict of the PR in the window to be kept, got %+v", s.Verdicts)
+	}
+}
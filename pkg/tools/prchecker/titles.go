@@ -0,0 +1,10 @@
+package prchecker
+
+import "regexp"
+
+// titlePresets are the title conventions title_preset names, matched like title_pattern
+// Conventional Commits titles are a type, an optional scope, "!" for breaking changes and a description,
+// e.g. "feat(api)!: drop v1 endpoints", the format release tools derive versions and changelogs from
+var titlePresets = map[string]*regexp.Regexp{
+	"conventional_commits": regexp.MustCompile(`^(build|chore|ci|docs|feat|fix|perf|refactor|revert|style|test)(\([\w./-]+\))?!?: \S`),
+}
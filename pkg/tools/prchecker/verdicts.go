@@ -0,0 +1,134 @@
+package prchecker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/state"
+)
+
+// verdict is the outcome of checking a merged PR
+type verdict struct {
+	InScope    bool
+	Approved   bool
+	MergedBy   string // Who merged the PR, known when it was flagged
+	Violations []Violation
+}
+
+// verdictCache holds the verdicts of merged PRs checked by earlier runs, kept in the state, and those of this run
+// Methods on a nil cache are no-ops, so callers do not need to check whether verdicts are cached
+type verdictCache struct {
+	path     string
+	previous map[string]state.Verdict
+	current  map[string]state.Verdict // Verdicts of the PRs checked by this run
+	hits     int                      // PRs whose verdict was reused
+}
+
+// loadVerdicts loads the verdicts recorded in the state at path
+func loadVerdicts(path string) (*verdictCache, error) {
+	s, err := state.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return &verdictCache{path: path, previous: s.Verdicts, current: make(map[string]state.Verdict)}, nil
+}
+
+// verdictKey identifies a merged PR: a PR merged again after being reverted and reopened has another merge commit
+// PRs without a merge commit are not cached and get an empty key
+func verdictKey(repository string, number int, mergeSHA string) string {
+	if mergeSHA == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s#%d@%s", strings.ToLower(repository), number, mergeSHA)
+}
+
+// lookup returns the verdict of a merged PR checked by an earlier run against the same rules
+func (c *verdictCache) lookup(key, rules string) (verdict, bool) {
+	if c == nil || key == "" {
+		return verdict{}, false
+	}
+	cached, ok := c.previous[key]
+	if !ok || cached.Rules != rules {
+		return verdict{}, false
+	}
+
+	v := verdict{InScope: cached.InScope, Approved: cached.Approved, MergedBy: cached.MergedBy}
+	if len(cached.Violations) > 0 {
+		if err := json.Unmarshal(cached.Violations, &v.Violations); err != nil {
+			return verdict{}, false // Checked again, which records a readable verdict
+		}
+	}
+	c.hits++
+	return v, true
+}
+
+// record records the verdict of a merged PR checked by this run
+func (c *verdictCache) record(key, rules string, mergedAt time.Time, v verdict) {
+	if c == nil || key == "" {
+		return
+	}
+	cached := state.Verdict{Rules: rules, MergedAt: mergedAt, InScope: v.InScope, Approved: v.Approved, MergedBy: v.MergedBy}
+	if len(v.Violations) > 0 {
+		violations, err := json.Marshal(v.Violations)
+		if err != nil {
+			return
+		}
+		cached.Violations = violations
+	}
+	c.current[key] = cached
+}
+
+// save records the verdicts of this run in the state, dropping those of PRs merged before since,
+// which no run checks again
+func (c *verdictCache) save(since time.Time) error {
+	if c == nil {
+		return nil
+	}
+	return state.Update(c.path, func(s *state.State) error {
+		if s.Verdicts == nil {
+			s.Verdicts = make(map[string]state.Verdict)
+		}
+		for key, v := range c.current {
+			s.Verdicts[key] = v
+		}
+		for key, v := range s.Verdicts {
+			if v.MergedAt.Before(since) {
+				delete(s.Verdicts, key)
+			}
+		}
+		return nil
+	})
+}
+
+// fingerprint identifies the rules a verdict was reached with, so verdicts are not reused once the rules change
+func (r Rules) fingerprint() string {
+	sections := make([]string, 0, len(r.RequiredSections))
+	for _, section := range r.RequiredSections {
+		sections = append(sections, section.String())
+	}
+	title := ""
+	if r.TitlePattern != nil {
+		title = r.TitlePattern.String()
+	}
+	checks := make([]string, 0, len(r.StatusPosters))
+	for check, posters := range r.StatusPosters {
+		checks = append(checks, check+"="+strings.Join(posters, ","))
+	}
+	sort.Strings(checks)
+	methods := append([]string{}, r.MergeMethods...)
+	sort.Strings(methods)
+	hours := ""
+	if r.MergeHours != nil {
+		hours = r.MergeHours.String()
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d|%d|%t|%t|%t|%t|%t|%q|%q|%q|%t|%q|%q|%q|%t|%q|%t|%d|%q", r.RequiredApprovals, r.MinReviewTime, r.CommitterApprovals,
+		r.CodeOwnerApproval, r.DismissedReviews, r.StaleApprovals, r.ProtectionBypasses, r.ReviewerTeams, sections, title, r.RequireTicket, r.TicketKeys, r.Paths, checks,
+		r.FailingChecks, methods, r.OutsideApprovals, r.DismissalWindow, hours)))
+	return hex.EncodeToString(sum[:8])
+}
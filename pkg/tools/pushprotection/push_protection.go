@@ -0,0 +1,226 @@
+package pushprotection
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+const (
+	// DefaultCheckWindow is the default time window to look for push protection bypasses
+	DefaultCheckWindow = 24 * time.Hour
+)
+
+// Bypass represents a secret that was pushed by bypassing push protection
+type Bypass struct {
+	Repository string
+	Number     int
+	SecretType string
+	BypassedBy string
+	BypassedAt time.Time
+	State      string
+	URL        string
+}
+
+// Checker is a service that reports push protection bypasses within the check window
+type Checker struct {
+	client      common.GitHubClientInterface
+	checkWindow time.Duration
+	config      *config.Config
+}
+
+// NewPushProtectionChecker creates a new Checker
+func NewPushProtectionChecker(client common.GitHubClientInterface, config *config.Config) *Checker {
+	checkWindow := DefaultCheckWindow
+	if config.Monitors.PushProtection.CheckWindow.Duration > 0 {
+		checkWindow = config.Monitors.PushProtection.CheckWindow.Duration
+	}
+
+	return &Checker{
+		client:      client,
+		checkWindow: checkWindow,
+		config:      config,
+	}
+}
+
+// Run checks all configured organizations and repositories for push protection bypasses
+func (c *Checker) Run(ctx context.Context) ([]Bypass, error) {
+	allBypasses := make([]Bypass, 0)
+
+	for _, org := range c.config.Monitors.PushProtection.Organizations {
+		if common.SkipIfStopped(ctx, "org:"+org) {
+			continue
+		}
+		bypasses, err := c.CheckOrganization(ctx, org)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, "org:"+org, err) {
+				continue
+			}
+			log.Printf("Error checking push protection bypasses for organization %s: %v", org, err)
+			continue
+		}
+		allBypasses = append(allBypasses, bypasses...)
+	}
+
+	for _, repository := range c.config.Monitors.PushProtection.Repositories {
+		if common.SkipIfStopped(ctx, repository) {
+			continue
+		}
+		bypasses, err := c.CheckRepository(ctx, repository)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, repository, err) {
+				continue
+			}
+			log.Printf("Error checking push protection bypasses for repository %s: %v", repository, err)
+			continue
+		}
+		allBypasses = append(allBypasses, bypasses...)
+	}
+
+	return allBypasses, nil
+}
+
+// CheckOrganization reports push protection bypasses across an organization
+func (c *Checker) CheckOrganization(ctx context.Context, org string) ([]Bypass, error) {
+	log.Printf("Checking for push protection bypasses in %s organization within the last %v", org, c.checkWindow)
+
+	alerts, err := c.client.ListOrganizationSecretScanningAlerts(ctx, org)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization secret scanning alerts: %w", err)
+	}
+
+	return c.filterBypasses(alerts, ""), nil
+}
+
+// CheckRepository reports push protection bypasses in a single repository
+func (c *Checker) CheckRepository(ctx context.Context, repository string) ([]Bypass, error) {
+	owner, repo, ok := common.ParseRepository(repository)
+	if !ok {
+		return nil, fmt.Errorf("invalid repository format, expected 'owner/repo'")
+	}
+
+	log.Printf("Checking for push protection bypasses in %s within the last %v", repository, c.checkWindow)
+
+	alerts, err := c.client.ListRepositorySecretScanningAlerts(ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repository secret scanning alerts: %w", err)
+	}
+
+	return c.filterBypasses(alerts, repository), nil
+}
+
+// filterBypasses keeps the alerts whose push protection was bypassed within the check window
+// The repository name is taken from the alert when it is not known by the caller
+func (c *Checker) filterBypasses(alerts []*common.SecretScanningAlert, repository string) []Bypass {
+	bypasses := make([]Bypass, 0)
+	now := time.Now()
+	cutoffTime := common.WindowStart(now, c.checkWindow, c.config.WindowLocation())
+	windowEnd := common.WindowEnd(now, c.checkWindow, c.config.WindowLocation())
+
+	for _, alert := range alerts {
+		if !alert.PushProtectionBypassed || alert.PushProtectionBypassedAt == nil {
+			continue
+		}
+		if alert.PushProtectionBypassedAt.Before(cutoffTime) || !alert.PushProtectionBypassedAt.Before(windowEnd) {
+			continue
+		}
+
+		repoName := repository
+		if repoName == "" {
+			repoName = alert.Repository.GetFullName()
+		}
+
+		secretType := alert.SecretTypeDisplayName
+		if secretType == "" {
+			secretType = alert.SecretType
+		}
+
+		bypasses = append(bypasses, Bypass{
+			Repository: repoName,
+			Number:     alert.Number,
+			SecretType: secretType,
+			BypassedBy: alert.PushProtectionBypassedBy.GetLogin(),
+			BypassedAt: common.LocalTime(alert.PushProtectionBypassedAt.Time, c.config.Location()),
+			State:      alert.State,
+			URL:        alert.HTMLURL,
+		})
+	}
+
+	return bypasses
+}
+
+// Findings converts push protection bypasses into findings
+func Findings(bypasses []Bypass) []findings.Finding {
+	list := make([]findings.Finding, 0, len(bypasses))
+	for _, b := range bypasses {
+		list = append(list, findings.Finding{
+			Monitor:    "push_protection_bypasses",
+			Repository: b.Repository,
+			Subject:    fmt.Sprintf("alert #%d", b.Number),
+			Summary:    fmt.Sprintf("%s pushed by %s bypassing push protection", b.SecretType, b.BypassedBy),
+			URL:        b.URL,
+		})
+	}
+	return list
+}
+
+// WriteResultsMarkdown writes push protection bypasses in a code block format
+// suitable for Slack notifications
+func WriteResultsMarkdown(w io.Writer, bypasses []Bypass) {
+	if len(bypasses) == 0 {
+		return // No results to display
+	}
+
+	// Print header for push protection bypasses
+	fmt.Fprintln(w, "## :rotating_light: Secret Scanning Push Protection Bypasses")
+	fmt.Fprintf(w, "Found %d secrets pushed by bypassing push protection.\n\n", len(bypasses))
+
+	// Start code block
+	fmt.Fprintln(w, "```")
+	// Create fixed-width headers with proper spacing for code block
+	fmt.Fprintln(w, "Repository                Alert   Bypassed By         Secret Type          Link")
+	fmt.Fprintln(w, "---------------------------------------------------------------------")
+
+	// Print each bypass in a fixed-width format for code blocks
+	for _, b := range bypasses {
+		// Format repository name with padding
+		repoStr := b.Repository
+		if len(repoStr) > 24 {
+			repoStr = repoStr[:21] + "..."
+		} else {
+			repoStr = fmt.Sprintf("%-24s", repoStr)
+		}
+
+		// Format alert number
+		alertStr := fmt.Sprintf("#%-6d", b.Number)
+
+		// Format actor with padding
+		actorStr := b.BypassedBy
+		if len(actorStr) > 18 {
+			actorStr = actorStr[:15] + "..."
+		} else {
+			actorStr = fmt.Sprintf("%-18s", actorStr)
+		}
+
+		// Format secret type with padding
+		typeStr := b.SecretType
+		if len(typeStr) > 20 {
+			typeStr = typeStr[:17] + "..."
+		} else {
+			typeStr = fmt.Sprintf("%-20s", typeStr)
+		}
+
+		// Format the output row with fixed-width fields
+		fmt.Fprintf(w, "%s %s %s %s %s\n", repoStr, alertStr, actorStr, typeStr, b.URL)
+	}
+
+	// End code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
@@ -0,0 +1,109 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	mockgithub "github.com/anupsv/git-monitoring/pkg/tools/common/test"
+	"github.com/anupsv/git-monitoring/pkg/tools/pushprotection"
+)
+
+func createMockAlert(number int, repo string, bypassed bool, bypassedBy string, bypassedAt time.Time) *common.SecretScanningAlert {
+	return &common.SecretScanningAlert{
+		Number:                   number,
+		State:                    "open",
+		HTMLURL:                  "https://github.com/" + repo + "/security/secret-scanning/1",
+		Repository:               &github.Repository{FullName: &repo},
+		SecretType:               "aws_access_key_id",
+		SecretTypeDisplayName:    "AWS Access Key ID",
+		PushProtectionBypassed:   bypassed,
+		PushProtectionBypassedBy: &github.User{Login: &bypassedBy},
+		PushProtectionBypassedAt: &github.Timestamp{Time: bypassedAt},
+	}
+}
+
+func TestCheckOrganization(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		alerts        []*common.SecretScanningAlert
+		alertsErr     error
+		expectError   bool
+		expectedCount int
+	}{
+		{
+			name: "Recent bypass is reported",
+			alerts: []*common.SecretScanningAlert{
+				createMockAlert(1, "testorg/repo1", true, "alice", now.Add(-time.Hour)),
+			},
+			expectedCount: 1,
+		},
+		{
+			name: "Alerts without bypass are ignored",
+			alerts: []*common.SecretScanningAlert{
+				createMockAlert(1, "testorg/repo1", false, "", now),
+			},
+			expectedCount: 0,
+		},
+		{
+			name: "Bypass outside window is ignored",
+			alerts: []*common.SecretScanningAlert{
+				createMockAlert(1, "testorg/repo1", true, "alice", now.Add(-48*time.Hour)),
+			},
+			expectedCount: 0,
+		},
+		{
+			name:        "Error listing alerts",
+			alertsErr:   errors.New("API error"),
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &mockgithub.MockGitHubClient{
+				MockOrgSecretAlerts:    tc.alerts,
+				MockOrgSecretAlertsErr: tc.alertsErr,
+			}
+
+			cfg := &config.Config{
+				Monitors: config.MonitorsConfig{
+					PushProtection: config.PushProtectionConfig{
+						Enabled:       true,
+						Organizations: []string{"testorg"},
+						CheckWindow:   config.Hours(24),
+					},
+				},
+			}
+
+			checker := pushprotection.NewPushProtectionChecker(mockClient, cfg)
+			bypasses, err := checker.CheckOrganization(context.Background(), "testorg")
+
+			if tc.expectError && err == nil {
+				t.Error("Expected an error but got nil")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("Did not expect an error but got: %v", err)
+			}
+			if len(bypasses) != tc.expectedCount {
+				t.Fatalf("Expected %d bypasses, got %d", tc.expectedCount, len(bypasses))
+			}
+
+			if tc.expectedCount > 0 {
+				if bypasses[0].BypassedBy != "alice" {
+					t.Errorf("Expected bypassed by %q, got %q", "alice", bypasses[0].BypassedBy)
+				}
+				if bypasses[0].SecretType != "AWS Access Key ID" {
+					t.Errorf("Expected secret type %q, got %q", "AWS Access Key ID", bypasses[0].SecretType)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package repocreation
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	"github.com/google/go-github/v45/github"
+)
+
+const (
+	// DefaultCheckWindow is the default time window to look for new repositories
+	DefaultCheckWindow = 24 * time.Hour
+)
+
+// Repository is a repository created within the check window outside the golden path
+type Repository struct {
+	Name      string
+	CreatedAt time.Time
+	Creator   string // Empty when the creation event is no longer available
+	Template  string // Template the repository was created from, empty for none
+	URL       string
+}
+
+// Checker is a service that reports repositories created without an approved template or by unapproved creators
+type Checker struct {
+	client      common.GitHubClientInterface
+	checkWindow time.Duration
+	config      *config.Config
+}
+
+// NewRepoCreationChecker creates a new Checker
+func NewRepoCreationChecker(client common.GitHubClientInterface, config *config.Config) *Checker {
+	checkWindow := DefaultCheckWindow
+	if config.Monitors.RepoCreation.CheckWindow.Duration > 0 {
+		checkWindow = config.Monitors.RepoCreation.CheckWindow.Duration
+	}
+
+	return &Checker{
+		client:      client,
+		checkWindow: checkWindow,
+		config:      config,
+	}
+}
+
+// Run checks all configured organizations for repositories created outside the golden path
+func (c *Checker) Run(ctx context.Context) ([]Repository, error) {
+	allRepos := make([]Repository, 0)
+
+	for _, org := range c.config.Monitors.RepoCreation.Organizations {
+		if common.SkipIfStopped(ctx, "org:"+org) {
+			continue
+		}
+		repos, err := c.CheckOrganization(ctx, org)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, "org:"+org, err) {
+				continue
+			}
+			log.Printf("Error checking repository creation for organization %s: %v", org, err)
+			continue
+		}
+		allRepos = append(allRepos, repos...)
+	}
+
+	return allRepos, nil
+}
+
+// CheckOrganization reports the repositories of an organization created within the check window
+// that were neither created from an approved template nor by an approved creator
+func (c *Checker) CheckOrganization(ctx context.Context, org string) ([]Repository, error) {
+	log.Printf("Checking for repositories created in %s organization within the last %v", org, c.checkWindow)
+
+	repos, err := c.client.ListOrganizationRepositories(ctx, org, "all")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization repositories: %w", err)
+	}
+
+	now := time.Now()
+	cutoffTime := common.WindowStart(now, c.checkWindow, c.config.WindowLocation())
+	windowEnd := common.WindowEnd(now, c.checkWindow, c.config.WindowLocation())
+
+	// The members holding an approved role are only listed once a new repository needs them
+	var roleMembers map[string]bool
+
+	adHoc := make([]Repository, 0)
+	for _, repo := range repos {
+		if repo.GetCreatedAt().Before(cutoffTime) || !repo.GetCreatedAt().Before(windowEnd) {
+			continue
+		}
+
+		result, err := c.checkRepository(ctx, repo)
+		if err != nil {
+			log.Printf("Error checking how repository %s was created: %v", repo.GetFullName(), err)
+			continue
+		}
+		if result == nil {
+			continue
+		}
+
+		if c.approvedCreator(result.Creator) {
+			continue
+		}
+		if roleMembers == nil {
+			roleMembers, err = c.listRoleMembers(ctx, org)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if roleMembers[strings.ToLower(result.Creator)] {
+			continue
+		}
+
+		adHoc = append(adHoc, *result)
+	}
+
+	return adHoc, nil
+}
+
+// checkRepository returns how a new repository was created, or nil when it was created from an approved template
+// Listed repositories leave out their template, so the repository is fetched for it
+func (c *Checker) checkRepository(ctx context.Context, repo *github.Repository) (*Repository, error) {
+	owner, name, ok := common.ParseRepository(repo.GetFullName())
+	if !ok {
+		return nil, fmt.Errorf("invalid repository name %q", repo.GetFullName())
+	}
+
+	full, err := c.client.GetRepository(ctx, owner, name)
+	if err != nil {
+		return nil, err
+	}
+	if full == nil {
+		full = repo
+	}
+
+	template := full.GetTemplateRepository().GetFullName()
+	for _, approved := range c.config.Monitors.RepoCreation.ApprovedTemplates {
+		if template != "" && strings.EqualFold(approved, template) {
+			return nil, nil
+		}
+	}
+
+	creator, err := c.creator(ctx, owner, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Repository{
+		Name:      repo.GetFullName(),
+		CreatedAt: common.LocalTime(repo.GetCreatedAt().Time, c.config.Location()),
+		Creator:   creator,
+		Template:  template,
+		URL:       repo.GetHTMLURL(),
+	}, nil
+}
+
+// creator returns who created a repository, from the CreateEvent of the repository
+// Events are kept for 90 days, so creators of older repositories are not known
+func (c *Checker) creator(ctx context.Context, owner, repo string) (string, error) {
+	events, err := c.client.ListRepositoryEvents(ctx, owner, repo)
+	if err != nil {
+		return "", err
+	}
+
+	for _, event := range events {
+		if event.GetType() != "CreateEvent" {
+			continue
+		}
+		payload, err := event.ParsePayload()
+		if err != nil {
+			continue
+		}
+		if create, ok := payload.(*github.CreateEvent); ok && create.GetRefType() == "repository" {
+			return event.GetActor().GetLogin(), nil
+		}
+	}
+
+	return "", nil
+}
+
+// approvedCreator reports whether a user is one of the approved creators
+func (c *Checker) approvedCreator(user string) bool {
+	if user == "" {
+		return false
+	}
+	for _, approved := range c.config.Monitors.RepoCreation.ApprovedCreators {
+		if strings.EqualFold(approved, user) {
+			return true
+		}
+	}
+	return false
+}
+
+// listRoleMembers returns the lowercase logins of the members of an organization holding an approved role
+func (c *Checker) listRoleMembers(ctx context.Context, org string) (map[string]bool, error) {
+	members := make(map[string]bool)
+	for _, role := range c.config.Monitors.RepoCreation.ApprovedRoles {
+		users, err := c.client.ListOrganizationMembers(ctx, org, role)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s members of organization: %w", role, err)
+		}
+		for _, user := range users {
+			members[strings.ToLower(user.GetLogin())] = true
+		}
+	}
+	return members, nil
+}
+
+// Findings converts repositories created outside the golden path into findings
+func Findings(repos []Repository) []findings.Finding {
+	list := make([]findings.Finding, 0, len(repos))
+	for _, r := range repos {
+		creator := r.Creator
+		if creator == "" {
+			creator = "an unknown user"
+		}
+		summary := fmt.Sprintf("created by %s without an approved template", creator)
+		if r.Template != "" {
+			summary = fmt.Sprintf("created by %s from unapproved template %s", creator, r.Template)
+		}
+		list = append(list, findings.Finding{
+			Monitor:    "repo_creation",
+			Repository: r.Name,
+			Subject:    "creation",
+			Summary:    summary,
+			URL:        r.URL,
+		})
+	}
+	return list
+}
+
+// WriteResultsMarkdown writes repositories created outside the golden path in a code block format
+// suitable for Slack notifications
+func WriteResultsMarkdown(w io.Writer, repos []Repository) {
+	if len(repos) == 0 {
+		return // No results to display
+	}
+
+	// Print header for new repositories
+	fmt.Fprintln(w, "## :construction: Repositories Created Outside the Golden Path")
+	fmt.Fprintf(w, "Found %d repositories created without an approved template or by unapproved creators.\n\n", len(repos))
+
+	// Start code block
+	fmt.Fprintln(w, "```")
+	// Create fixed-width headers with proper spacing for code block
+	fmt.Fprintln(w, "Repository                Created     Creator             Template")
+	fmt.Fprintln(w, "---------------------------------------------------------------------")
+
+	// Print each repository in a fixed-width format for code blocks
+	for _, r := range repos {
+		// Format repository name with padding
+		repoStr := r.Name
+		if len(repoStr) > 24 {
+			repoStr = repoStr[:21] + "..."
+		} else {
+			repoStr = fmt.Sprintf("%-24s", repoStr)
+		}
+
+		creatorStr := r.Creator
+		if creatorStr == "" {
+			creatorStr = "unknown"
+		}
+		templateStr := r.Template
+		if templateStr == "" {
+			templateStr = "-"
+		}
+
+		fmt.Fprintf(w, "%s  %s  %-18s  %s\n", repoStr, r.CreatedAt.Format("2006-01-02"), creatorStr, templateStr)
+	}
+
+	// End code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
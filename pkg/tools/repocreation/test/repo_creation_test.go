@@ -0,0 +1,114 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	mockgithub "github.com/anupsv/git-monitoring/pkg/tools/common/test"
+	"github.com/anupsv/git-monitoring/pkg/tools/repocreation"
+)
+
+// createRepo creates a repository of testorg created at the given time
+func createRepo(name string, createdAt time.Time) *github.Repository {
+	return &github.Repository{
+		FullName:  github.String("testorg/" + name),
+		HTMLURL:   github.String("https://github.com/testorg/" + name),
+		CreatedAt: &github.Timestamp{Time: createdAt},
+	}
+}
+
+// createRepoEvent creates the CreateEvent of a repository by its creator
+func createRepoEvent(creator string) *github.Event {
+	payload := json.RawMessage(`{"ref_type":"repository"}`)
+	return &github.Event{
+		Type:       github.String("CreateEvent"),
+		Actor:      &github.User{Login: github.String(creator)},
+		RawPayload: &payload,
+	}
+}
+
+func TestCheckOrganization(t *testing.T) {
+	now := time.Now()
+
+	fromTemplate := createRepo("service", now.Add(-time.Hour))
+	fromTemplate.TemplateRepository = &github.Repository{FullName: github.String("testorg/golden-service")}
+	fromOtherTemplate := createRepo("copy", now.Add(-time.Hour))
+	fromOtherTemplate.TemplateRepository = &github.Repository{FullName: github.String("someone/starter")}
+
+	repos := []*github.Repository{
+		fromTemplate,
+		fromOtherTemplate,
+		createRepo("by-owner", now.Add(-time.Hour)),
+		createRepo("by-bot", now.Add(-time.Hour)),
+		createRepo("ad-hoc", now.Add(-time.Hour)),
+		createRepo("old", now.Add(-72*time.Hour)),
+	}
+	creators := map[string]string{
+		"copy":     "alice",
+		"by-owner": "Owner",
+		"by-bot":   "provisioner[bot]",
+		"ad-hoc":   "alice",
+	}
+
+	mockClient := &mockgithub.MockGitHubClient{
+		MockOrgRepositories: repos,
+		MockRepository:      make(map[string]*github.Repository),
+		MockOrgMembers:      []*github.User{{Login: github.String("owner")}},
+		ListRepositoryEventsFunc: func(ctx context.Context, owner, repo string) ([]*github.Event, error) {
+			return []*github.Event{createRepoEvent(creators[repo])}, nil
+		},
+	}
+	for _, repo := range repos {
+		mockClient.MockRepository[repo.GetFullName()] = repo
+	}
+
+	cfg := &config.Config{
+		Monitors: config.MonitorsConfig{
+			RepoCreation: config.RepoCreationConfig{
+				Enabled:           true,
+				Organizations:     []string{"testorg"},
+				CheckWindow:       config.Hours(24),
+				ApprovedTemplates: []string{"TestOrg/golden-service"},
+				ApprovedRoles:     []string{"admin"},
+				ApprovedCreators:  []string{"provisioner[bot]"},
+			},
+		},
+	}
+
+	checker := repocreation.NewRepoCreationChecker(mockClient, cfg)
+	adHoc, err := checker.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	if len(adHoc) != 2 {
+		t.Fatalf("Expected 2 repositories, got %+v", adHoc)
+	}
+	if adHoc[0].Name != "testorg/copy" || adHoc[0].Template != "someone/starter" || adHoc[0].Creator != "alice" {
+		t.Errorf("Unexpected repository %+v", adHoc[0])
+	}
+	if adHoc[1].Name != "testorg/ad-hoc" || adHoc[1].Template != "" {
+		t.Errorf("Unexpected repository %+v", adHoc[1])
+	}
+
+	// Old repositories are not looked at, and the owners are listed once
+	if mockClient.GetRepositoryCalls != 5 {
+		t.Errorf("Expected 5 repositories to be fetched, got %d", mockClient.GetRepositoryCalls)
+	}
+	if mockClient.ListOrgMembersCalls != 1 {
+		t.Errorf("Expected the owners to be listed once, got %d calls", mockClient.ListOrgMembersCalls)
+	}
+
+	list := repocreation.Findings(adHoc)
+	if list[0].Summary != "created by alice from unapproved template someone/starter" {
+		t.Errorf("Unexpected summary %q", list[0].Summary)
+	}
+	if list[1].Summary != "created by alice without an approved template" {
+		t.Errorf("Unexpected summary %q", list[1].Summary)
+	}
+}
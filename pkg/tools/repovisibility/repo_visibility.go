@@ -3,10 +3,12 @@ package repovisibility
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"time"
 
 	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
 	"github.com/anupsv/git-monitoring/pkg/tools/common"
 	"github.com/google/go-github/v45/github"
 )
@@ -26,8 +28,8 @@ type Checker struct {
 // NewRepoVisibilityChecker creates a new Checker
 func NewRepoVisibilityChecker(client common.GitHubClientInterface, config *config.Config) *Checker {
 	checkWindow := DefaultCheckWindow
-	if config.Monitors.RepoVisibility.CheckWindow > 0 {
-		checkWindow = time.Duration(config.Monitors.RepoVisibility.CheckWindow) * time.Hour
+	if config.Monitors.RepoVisibility.CheckWindow.Duration > 0 {
+		checkWindow = config.Monitors.RepoVisibility.CheckWindow.Duration
 	}
 
 	return &Checker{
@@ -46,12 +48,23 @@ func (r *Checker) CheckOrganization(ctx context.Context, orgName string) ([]stri
 	if err != nil {
 		return nil, fmt.Errorf("failed to list organization repositories: %w", err)
 	}
+	repos, filtered := common.FilterRepositories(ctx, repos, r.config.RepoFilters)
+	if filtered > 0 {
+		log.Printf("Filtered out %d repositories of %s not matching repo_filters", filtered, orgName)
+	}
 
 	// Filter repositories by creation date and check events
 	recentlyPublic := make([]string, 0)
-	cutoffTime := time.Now().Add(-r.checkWindow)
+	now := time.Now()
+	cutoffTime := common.WindowStart(now, r.checkWindow, r.config.WindowLocation())
+	windowEnd := common.WindowEnd(now, r.checkWindow, r.config.WindowLocation())
 
 	for _, repo := range repos {
+		// Repositories created since the window ended are left to the next run
+		if repo.CreatedAt != nil && !repo.GetCreatedAt().Before(windowEnd) {
+			continue
+		}
+
 		// If CreatedAt is nil, we'll consider it was created recently (for testing purposes)
 		isRecent := true
 		if repo.CreatedAt != nil {
@@ -86,10 +99,17 @@ func (r *Checker) wasRecentlyMadePublic(ctx context.Context, owner, repo string)
 		return false, fmt.Errorf("failed to list repository events: %w", err)
 	}
 
-	cutoffTime := time.Now().Add(-r.checkWindow)
+	now := time.Now()
+	cutoffTime := common.WindowStart(now, r.checkWindow, r.config.WindowLocation())
+	windowEnd := common.WindowEnd(now, r.checkWindow, r.config.WindowLocation())
 
 	// Look for public event
 	for _, event := range events {
+		// Events since the window ended are left to the next run
+		if event.CreatedAt != nil && !event.GetCreatedAt().Before(windowEnd) {
+			continue
+		}
+
 		// If CreateAt is nil (in tests), consider it recent
 		isInWindow := true
 		if event.CreatedAt != nil {
@@ -119,8 +139,14 @@ func (r *Checker) Run(ctx context.Context) ([]string, error) {
 	case "specific":
 		// When using "specific" visibility, check only the specified organizations
 		for _, org := range r.config.Monitors.RepoVisibility.Organizations {
+			if common.SkipIfStopped(ctx, "org:"+org) {
+				continue
+			}
 			repos, err := r.CheckOrganization(ctx, org)
 			if err != nil {
+				if common.SkipIfBudgetExceeded(ctx, "org:"+org, err) {
+					continue
+				}
 				log.Printf("Error checking organization %s: %v", org, err)
 				continue
 			}
@@ -130,8 +156,14 @@ func (r *Checker) Run(ctx context.Context) ([]string, error) {
 	case "all", "public-only", "private-only":
 		// Check all organizations listed in the config with the selected visibility
 		for _, org := range r.config.Monitors.RepoVisibility.Organizations {
+			if common.SkipIfStopped(ctx, "org:"+org) {
+				continue
+			}
 			repos, err := r.CheckOrganizationWithVisibility(ctx, org, r.config.Monitors.RepoVisibility.RepoVisibility)
 			if err != nil {
+				if common.SkipIfBudgetExceeded(ctx, "org:"+org, err) {
+					continue
+				}
 				log.Printf("Error checking organization %s: %v", org, err)
 				continue
 			}
@@ -171,11 +203,13 @@ func (r *Checker) CheckRepository(ctx context.Context, owner, repo string) (bool
 		return false, nil
 	}
 
-	cutoffTime := time.Now().Add(-r.checkWindow)
+	now := time.Now()
+	cutoffTime := common.WindowStart(now, r.checkWindow, r.config.WindowLocation())
 
 	// If recently created and public, consider it recently made public
+	// Repositories created since the window ended are left to the next run
 	if foundRepo.CreatedAt != nil && !foundRepo.GetCreatedAt().Before(cutoffTime) {
-		return true, nil
+		return foundRepo.GetCreatedAt().Before(common.WindowEnd(now, r.checkWindow, r.config.WindowLocation())), nil
 	}
 
 	// Check if repository was recently made public
@@ -208,10 +242,16 @@ func (r *Checker) CheckOrganizationWithVisibility(ctx context.Context, orgName,
 	if err != nil {
 		return nil, fmt.Errorf("failed to list organization repositories: %w", err)
 	}
+	repos, filtered := common.FilterRepositories(ctx, repos, r.config.RepoFilters)
+	if filtered > 0 {
+		log.Printf("Filtered out %d repositories of %s not matching repo_filters", filtered, orgName)
+	}
 
 	// Filter repositories
 	recentlyPublic := make([]string, 0)
-	cutoffTime := time.Now().Add(-r.checkWindow)
+	now := time.Now()
+	cutoffTime := common.WindowStart(now, r.checkWindow, r.config.WindowLocation())
+	windowEnd := common.WindowEnd(now, r.checkWindow, r.config.WindowLocation())
 
 	for _, repo := range repos {
 		// Skip private repos if we're only interested in public ones
@@ -224,6 +264,11 @@ func (r *Checker) CheckOrganizationWithVisibility(ctx context.Context, orgName,
 			continue
 		}
 
+		// Repositories created since the window ended are left to the next run
+		if repo.CreatedAt != nil && !repo.GetCreatedAt().Before(windowEnd) {
+			continue
+		}
+
 		// For non-private repos, check if they're recently public
 		if !repo.GetPrivate() {
 			// If created recently and public, consider it recently made public
@@ -253,22 +298,37 @@ func (r *Checker) CheckOrganizationWithVisibility(ctx context.Context, orgName,
 	return recentlyPublic, nil
 }
 
-// PrintResultsMarkdown outputs recently public repositories in a code block format
+// Findings converts recently public repositories into findings
+func Findings(repositories []string) []findings.Finding {
+	list := make([]findings.Finding, 0, len(repositories))
+	for _, repo := range repositories {
+		list = append(list, findings.Finding{
+			Monitor:    "repo_visibility",
+			Repository: repo,
+			Subject:    "visibility",
+			Summary:    "Repository was recently made public",
+			URL:        "https://github.com/" + repo,
+		})
+	}
+	return list
+}
+
+// WriteResultsMarkdown writes recently public repositories in a code block format
 // suitable for Slack notifications
-func PrintResultsMarkdown(recentlyPublic []string) {
+func WriteResultsMarkdown(w io.Writer, recentlyPublic []string) {
 	if len(recentlyPublic) == 0 {
 		return // No results to display
 	}
 
 	// Print header for public repository issues
-	fmt.Println("## :warning: Recently Public Repositories")
-	fmt.Printf("Found %d repositories that were recently made public.\n\n", len(recentlyPublic))
+	fmt.Fprintln(w, "## :warning: Recently Public Repositories")
+	fmt.Fprintf(w, "Found %d repositories that were recently made public.\n\n", len(recentlyPublic))
 
 	// Start code block
-	fmt.Println("```")
+	fmt.Fprintln(w, "```")
 	// Create fixed-width headers with proper spacing for code block
-	fmt.Println("Repository                              Action Needed")
-	fmt.Println("---------------------------------------------------------------------")
+	fmt.Fprintln(w, "Repository                              Action Needed")
+	fmt.Fprintln(w, "---------------------------------------------------------------------")
 
 	// Print each public repository in a fixed-width format for code blocks
 	for _, repo := range recentlyPublic {
@@ -281,10 +341,10 @@ func PrintResultsMarkdown(recentlyPublic []string) {
 		}
 
 		// Format the output row with fixed-width fields
-		fmt.Printf("%s Review visibility settings\n", repoStr)
+		fmt.Fprintf(w, "%s Review visibility settings\n", repoStr)
 	}
 
 	// End code block
-	fmt.Println("```")
-	fmt.Println("")
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
 }
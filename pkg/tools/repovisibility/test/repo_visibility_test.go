@@ -17,7 +17,7 @@ func TestNewRepoVisibilityChecker(t *testing.T) {
 		Monitors: config.MonitorsConfig{
 			RepoVisibility: config.RepoVisibilityConfig{
 				Enabled:        true,
-				CheckWindow:    48,
+				CheckWindow:    config.Hours(48),
 				RepoVisibility: "all",
 				Organizations:  []string{"testorg"},
 			},
@@ -39,7 +39,7 @@ func TestRunWithInvalidVisibility(t *testing.T) {
 		Monitors: config.MonitorsConfig{
 			RepoVisibility: config.RepoVisibilityConfig{
 				Enabled:        true,
-				CheckWindow:    24,
+				CheckWindow:    config.Hours(24),
 				RepoVisibility: "invalid-value", // Invalid value to trigger error
 				Organizations:  []string{"testorg"},
 			},
@@ -70,7 +70,7 @@ func TestRunWithNoEvents(t *testing.T) {
 		Monitors: config.MonitorsConfig{
 			RepoVisibility: config.RepoVisibilityConfig{
 				Enabled:        true,
-				CheckWindow:    24,
+				CheckWindow:    config.Hours(24),
 				RepoVisibility: "all",
 				Organizations:  []string{"testorg"},
 			},
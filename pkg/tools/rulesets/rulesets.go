@@ -0,0 +1,286 @@
+package rulesets
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+const (
+	// IssueMissing means a desired ruleset does not exist
+	IssueMissing = "missing"
+	// IssueDisabled means a ruleset exists but is not enforced as desired
+	IssueDisabled = "disabled"
+	// IssueBypassed means a ruleset has bypass actors although none are allowed
+	IssueBypassed = "bypassed"
+	// IssueRulesMissing means a ruleset lacks one or more required rule types
+	IssueRulesMissing = "rules-missing"
+)
+
+// Drift represents a difference between a ruleset and its desired state
+type Drift struct {
+	Target  string // "org:<name>" for organization rulesets, "owner/repo" for repositories
+	Ruleset string
+	Issue   string
+	Details string
+}
+
+// Checker is a service that compares rulesets against a desired-state declaration
+type Checker struct {
+	client common.GitHubClientInterface
+	config *config.Config
+}
+
+// NewRulesetsChecker creates a new Checker
+func NewRulesetsChecker(client common.GitHubClientInterface, config *config.Config) *Checker {
+	return &Checker{
+		client: client,
+		config: config,
+	}
+}
+
+// Run checks all configured organizations and repositories for ruleset drift
+func (c *Checker) Run(ctx context.Context) ([]Drift, error) {
+	allDrift := make([]Drift, 0)
+
+	for _, org := range c.config.Monitors.Rulesets.Organizations {
+		if common.SkipIfStopped(ctx, "org:"+org) {
+			continue
+		}
+		drift, err := c.CheckOrganization(ctx, org)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, "org:"+org, err) {
+				continue
+			}
+			log.Printf("Error checking rulesets for organization %s: %v", org, err)
+			continue
+		}
+		allDrift = append(allDrift, drift...)
+	}
+
+	for _, repository := range c.config.Monitors.Rulesets.Repositories {
+		if common.SkipIfStopped(ctx, repository) {
+			continue
+		}
+		drift, err := c.CheckRepository(ctx, repository)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, repository, err) {
+				continue
+			}
+			log.Printf("Error checking rulesets for repository %s: %v", repository, err)
+			continue
+		}
+		allDrift = append(allDrift, drift...)
+	}
+
+	return allDrift, nil
+}
+
+// CheckOrganization compares an organization's rulesets with the desired organization rulesets
+func (c *Checker) CheckOrganization(ctx context.Context, org string) ([]Drift, error) {
+	desired := c.desiredForScope("organization")
+	if len(desired) == 0 {
+		return nil, nil
+	}
+
+	log.Printf("Checking rulesets for organization %s", org)
+
+	rulesets, err := c.client.ListOrganizationRulesets(ctx, org)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization rulesets: %w", err)
+	}
+
+	return c.compare(ctx, "org:"+org, rulesets, desired, func(ctx context.Context, id int64) (*common.Ruleset, error) {
+		return c.client.GetOrganizationRuleset(ctx, org, id)
+	})
+}
+
+// CheckRepository compares the rulesets applying to a repository with the desired repository rulesets
+func (c *Checker) CheckRepository(ctx context.Context, repository string) ([]Drift, error) {
+	desired := c.desiredForScope("repository")
+	if len(desired) == 0 {
+		return nil, nil
+	}
+
+	owner, repo, ok := common.ParseRepository(repository)
+	if !ok {
+		return nil, fmt.Errorf("invalid repository format, expected 'owner/repo'")
+	}
+
+	log.Printf("Checking rulesets for repository %s", repository)
+
+	rulesets, err := c.client.ListRepositoryRulesets(ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repository rulesets: %w", err)
+	}
+
+	return c.compare(ctx, repository, rulesets, desired, func(ctx context.Context, id int64) (*common.Ruleset, error) {
+		return c.client.GetRepositoryRuleset(ctx, owner, repo, id)
+	})
+}
+
+// desiredForScope returns the desired rulesets declared for the given scope
+func (c *Checker) desiredForScope(scope string) []config.DesiredRuleset {
+	var desired []config.DesiredRuleset
+	for _, d := range c.config.Monitors.Rulesets.Desired {
+		if d.Scope == scope {
+			desired = append(desired, d)
+		}
+	}
+	return desired
+}
+
+// compare reports drift between the fetched rulesets and the desired ones
+// The listing endpoints omit rules and bypass actors, so matching rulesets are
+// fetched individually through get
+func (c *Checker) compare(ctx context.Context, target string, rulesets []*common.Ruleset, desired []config.DesiredRuleset,
+	get func(ctx context.Context, id int64) (*common.Ruleset, error)) ([]Drift, error) {
+	byName := make(map[string]*common.Ruleset, len(rulesets))
+	for _, ruleset := range rulesets {
+		byName[ruleset.Name] = ruleset
+	}
+
+	drift := make([]Drift, 0)
+	for _, d := range desired {
+		summary, found := byName[d.Name]
+		if !found {
+			drift = append(drift, Drift{
+				Target:  target,
+				Ruleset: d.Name,
+				Issue:   IssueMissing,
+				Details: "ruleset does not exist",
+			})
+			continue
+		}
+
+		ruleset, err := get(ctx, summary.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ruleset %s: %w", d.Name, err)
+		}
+		if ruleset == nil {
+			ruleset = summary
+		}
+
+		drift = append(drift, compareRuleset(target, ruleset, d)...)
+	}
+
+	return drift, nil
+}
+
+// compareRuleset reports how a single ruleset deviates from its desired state
+func compareRuleset(target string, ruleset *common.Ruleset, desired config.DesiredRuleset) []Drift {
+	var drift []Drift
+
+	expectedEnforcement := desired.Enforcement
+	if expectedEnforcement == "" {
+		expectedEnforcement = "active"
+	}
+	if ruleset.Enforcement != expectedEnforcement {
+		drift = append(drift, Drift{
+			Target:  target,
+			Ruleset: desired.Name,
+			Issue:   IssueDisabled,
+			Details: fmt.Sprintf("enforcement is %s, expected %s", ruleset.Enforcement, expectedEnforcement),
+		})
+	}
+
+	if !desired.AllowBypass && len(ruleset.BypassActors) > 0 {
+		actors := make([]string, 0, len(ruleset.BypassActors))
+		for _, actor := range ruleset.BypassActors {
+			actors = append(actors, fmt.Sprintf("%s:%d", actor.ActorType, actor.ActorID))
+		}
+		drift = append(drift, Drift{
+			Target:  target,
+			Ruleset: desired.Name,
+			Issue:   IssueBypassed,
+			Details: fmt.Sprintf("%d bypass actor(s): %s", len(actors), strings.Join(actors, ", ")),
+		})
+	}
+
+	presentRules := make(map[string]bool, len(ruleset.Rules))
+	for _, rule := range ruleset.Rules {
+		presentRules[rule.Type] = true
+	}
+	var missingRules []string
+	for _, required := range desired.RequiredRules {
+		if !presentRules[required] {
+			missingRules = append(missingRules, required)
+		}
+	}
+	if len(missingRules) > 0 {
+		sort.Strings(missingRules)
+		drift = append(drift, Drift{
+			Target:  target,
+			Ruleset: desired.Name,
+			Issue:   IssueRulesMissing,
+			Details: "missing rules: " + strings.Join(missingRules, ", "),
+		})
+	}
+
+	return drift
+}
+
+// Findings converts ruleset drift into findings
+func Findings(drift []Drift) []findings.Finding {
+	list := make([]findings.Finding, 0, len(drift))
+	for _, d := range drift {
+		list = append(list, findings.Finding{
+			Monitor:    "rulesets",
+			Repository: d.Target,
+			Subject:    fmt.Sprintf("%s (%s)", d.Ruleset, d.Issue),
+			Summary:    d.Details,
+		})
+	}
+	return list
+}
+
+// WriteResultsMarkdown writes ruleset drift in a code block format
+// suitable for Slack notifications
+func WriteResultsMarkdown(w io.Writer, drift []Drift) {
+	if len(drift) == 0 {
+		return // No results to display
+	}
+
+	// Print header for ruleset drift
+	fmt.Fprintln(w, "## :warning: Repository Ruleset Drift")
+	fmt.Fprintf(w, "Found %d rulesets that differ from the desired state.\n\n", len(drift))
+
+	// Start code block
+	fmt.Fprintln(w, "```")
+	// Create fixed-width headers with proper spacing for code block
+	fmt.Fprintln(w, "Target                    Ruleset              Issue          Details")
+	fmt.Fprintln(w, "---------------------------------------------------------------------")
+
+	// Print each drift in a fixed-width format for code blocks
+	for _, d := range drift {
+		// Format target with padding
+		targetStr := d.Target
+		if len(targetStr) > 24 {
+			targetStr = targetStr[:21] + "..."
+		} else {
+			targetStr = fmt.Sprintf("%-24s", targetStr)
+		}
+
+		// Format ruleset name with padding
+		rulesetStr := d.Ruleset
+		if len(rulesetStr) > 20 {
+			rulesetStr = rulesetStr[:17] + "..."
+		} else {
+			rulesetStr = fmt.Sprintf("%-20s", rulesetStr)
+		}
+
+		// Format the output row with fixed-width fields
+		fmt.Fprintf(w, "%s %s %-14s %s\n", targetStr, rulesetStr, d.Issue, d.Details)
+	}
+
+	// End code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
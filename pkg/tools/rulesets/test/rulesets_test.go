@@ -0,0 +1,165 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	mockgithub "github.com/anupsv/git-monitoring/pkg/tools/common/test"
+	"github.com/anupsv/git-monitoring/pkg/tools/rulesets"
+)
+
+func TestCheckOrganization(t *testing.T) {
+	desired := []config.DesiredRuleset{
+		{
+			Name:          "protect-main",
+			Scope:         "organization",
+			RequiredRules: []string{"pull_request", "deletion"},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		orgRulesets    []*common.Ruleset
+		orgRulesetsErr error
+		expectError    bool
+		expectedIssues []string
+	}{
+		{
+			name: "Ruleset matches desired state",
+			orgRulesets: []*common.Ruleset{
+				{
+					ID:          1,
+					Name:        "protect-main",
+					Enforcement: "active",
+					Rules:       []*common.RulesetRule{{Type: "pull_request"}, {Type: "deletion"}},
+				},
+			},
+			expectedIssues: []string{},
+		},
+		{
+			name:           "Ruleset missing",
+			orgRulesets:    []*common.Ruleset{},
+			expectedIssues: []string{rulesets.IssueMissing},
+		},
+		{
+			name: "Ruleset disabled",
+			orgRulesets: []*common.Ruleset{
+				{
+					ID:          1,
+					Name:        "protect-main",
+					Enforcement: "disabled",
+					Rules:       []*common.RulesetRule{{Type: "pull_request"}, {Type: "deletion"}},
+				},
+			},
+			expectedIssues: []string{rulesets.IssueDisabled},
+		},
+		{
+			name: "Ruleset bypassed and missing rules",
+			orgRulesets: []*common.Ruleset{
+				{
+					ID:           1,
+					Name:         "protect-main",
+					Enforcement:  "active",
+					BypassActors: []*common.RulesetBypassActor{{ActorID: 5, ActorType: "RepositoryRole"}},
+					Rules:        []*common.RulesetRule{{Type: "pull_request"}},
+				},
+			},
+			expectedIssues: []string{rulesets.IssueBypassed, rulesets.IssueRulesMissing},
+		},
+		{
+			name:           "Error listing rulesets",
+			orgRulesetsErr: errors.New("API error"),
+			expectError:    true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &mockgithub.MockGitHubClient{
+				MockOrgRulesets:    tc.orgRulesets,
+				MockOrgRulesetsErr: tc.orgRulesetsErr,
+			}
+
+			cfg := &config.Config{
+				Monitors: config.MonitorsConfig{
+					Rulesets: config.RulesetsConfig{
+						Enabled:       true,
+						Organizations: []string{"testorg"},
+						Desired:       desired,
+					},
+				},
+			}
+
+			checker := rulesets.NewRulesetsChecker(mockClient, cfg)
+			drift, err := checker.CheckOrganization(context.Background(), "testorg")
+
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected an error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Did not expect an error but got: %v", err)
+			}
+
+			if len(drift) != len(tc.expectedIssues) {
+				t.Fatalf("Expected %d drift entries, got %d: %+v", len(tc.expectedIssues), len(drift), drift)
+			}
+			for i, issue := range tc.expectedIssues {
+				if drift[i].Issue != issue {
+					t.Errorf("Expected issue %q at index %d, got %q", issue, i, drift[i].Issue)
+				}
+				if drift[i].Target != "org:testorg" {
+					t.Errorf("Expected target %q, got %q", "org:testorg", drift[i].Target)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckRepository(t *testing.T) {
+	mockClient := &mockgithub.MockGitHubClient{
+		MockRepoRulesets: []*common.Ruleset{
+			{ID: 7, Name: "release-tags", Enforcement: "evaluate"},
+		},
+	}
+
+	cfg := &config.Config{
+		Monitors: config.MonitorsConfig{
+			Rulesets: config.RulesetsConfig{
+				Enabled:      true,
+				Repositories: []string{"owner/repo"},
+				Desired: []config.DesiredRuleset{
+					{Name: "release-tags", Scope: "repository"},
+					{Name: "protect-main", Scope: "organization"},
+				},
+			},
+		},
+	}
+
+	checker := rulesets.NewRulesetsChecker(mockClient, cfg)
+
+	t.Run("Only repository scoped rulesets are compared", func(t *testing.T) {
+		drift, err := checker.CheckRepository(context.Background(), "owner/repo")
+		if err != nil {
+			t.Fatalf("Did not expect an error but got: %v", err)
+		}
+		if len(drift) != 1 || drift[0].Issue != rulesets.IssueDisabled {
+			t.Errorf("Expected a single %q drift entry, got %+v", rulesets.IssueDisabled, drift)
+		}
+		if mockClient.GetRepoRulesetCalls != 1 {
+			t.Errorf("Expected 1 GetRepositoryRuleset call, got %d", mockClient.GetRepoRulesetCalls)
+		}
+	})
+
+	t.Run("Invalid repository format", func(t *testing.T) {
+		_, err := checker.CheckRepository(context.Background(), "invalid-format")
+		if err == nil {
+			t.Error("Expected an error for invalid repository format but got nil")
+		}
+	})
+}
@@ -0,0 +1,216 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	mockgithub "github.com/anupsv/git-monitoring/pkg/tools/common/test"
+	"github.com/anupsv/git-monitoring/pkg/tools/tokenhealth"
+)
+
+// newConfig creates a configuration checking the token against testorg and ssoorg
+func newConfig() *config.Config {
+	return &config.Config{
+		Monitors: config.MonitorsConfig{
+			TokenHealth: config.TokenHealthConfig{
+				Enabled:              true,
+				Organizations:        []string{"testorg", "ssoorg"},
+				ExpiryWarningDays:    14,
+				MinRateLimitHeadroom: 20,
+				RequiredScopes:       []string{"read:org", "repo:status", "admin:org_hook"},
+			},
+		},
+	}
+}
+
+// healthyToken returns a classic token with plenty of rate limit left that does not expire soon
+func healthyToken() *common.TokenInfo {
+	return &common.TokenInfo{
+		Login:       "monitor-bot",
+		Scopes:      []string{"repo", "admin:org", "admin:org_hook"},
+		ScopesKnown: true,
+		Expires:     time.Now().Add(90 * 24 * time.Hour),
+		RateLimit:   common.RateLimit{Limit: 5000, Remaining: 4900, Reset: time.Now().Add(59 * time.Minute)},
+	}
+}
+
+// subjects returns the subjects of the problems of the token, by target
+func subjects(tokens []tokenhealth.Token) map[string]string {
+	result := make(map[string]string)
+	for _, t := range tokens {
+		for _, p := range t.Problems {
+			result[p.Target] += p.Subject + " "
+		}
+	}
+	return result
+}
+
+func TestHealthyToken(t *testing.T) {
+	client := &mockgithub.MockGitHubClient{MockTokenInfo: healthyToken()}
+
+	tokens, err := tokenhealth.NewTokenHealthChecker(client, newConfig()).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	if len(tokens) != 1 {
+		t.Fatalf("Expected 1 token, got %d", len(tokens))
+	}
+	if len(tokens[0].Problems) != 0 {
+		t.Errorf("Expected no problems, got %+v", tokens[0].Problems)
+	}
+	if tokens[0].Headroom != 98 {
+		t.Errorf("Expected 98%% headroom, got %d", tokens[0].Headroom)
+	}
+	if len(tokens[0].Organizations) != 2 || client.GetOrganizationAccessCalls != 2 {
+		t.Errorf("Expected both organizations to be checked, got %+v", tokens[0].Organizations)
+	}
+}
+
+func TestUnhealthyToken(t *testing.T) {
+	info := healthyToken()
+	info.Scopes = []string{"public_repo", "write:org"}
+	info.Expires = time.Now().Add(3*24*time.Hour + time.Hour)
+	info.RateLimit.Remaining = 400
+	client := &mockgithub.MockGitHubClient{
+		MockTokenInfo: info,
+		MockOrgAccess: map[string]*common.OrganizationAccess{
+			"ssoorg": {SSORequired: true, SSOURL: "https://github.com/orgs/ssoorg/sso?authorization_request=abc"},
+		},
+	}
+
+	tokens, err := tokenhealth.NewTokenHealthChecker(client, newConfig()).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	got := subjects(tokens)
+	if got["token:monitor-bot"] != "token_expiration token_scopes rate_limit " {
+		t.Errorf("Unexpected problems of the token: %q", got["token:monitor-bot"])
+	}
+	if got["org:ssoorg"] != "sso_authorization " || got["org:testorg"] != "" {
+		t.Errorf("Unexpected problems of the organizations: %v", got)
+	}
+
+	list := tokenhealth.Findings(tokens)
+	if len(list) != 4 {
+		t.Fatalf("Expected 4 findings, got %d", len(list))
+	}
+	for _, f := range list {
+		switch f.Subject {
+		case tokenhealth.SubjectExpiration:
+			if !strings.Contains(f.Summary, "expires in 3 days") || f.Severity != findings.SeverityHigh {
+				t.Errorf("Unexpected expiration finding: %+v", f)
+			}
+		case tokenhealth.SubjectScopes:
+			// write:org implies read:org, and public_repo does not imply repo:status
+			if !strings.HasSuffix(f.Summary, "lacks the required scopes admin:org_hook, repo:status") {
+				t.Errorf("Unexpected scopes finding: %+v", f)
+			}
+		case tokenhealth.SubjectSSO:
+			if f.URL != "https://github.com/orgs/ssoorg/sso?authorization_request=abc" {
+				t.Errorf("Expected the SSO authorization URL, got %q", f.URL)
+			}
+		}
+		if f.Monitor != "token_health" {
+			t.Errorf("Unexpected monitor %q", f.Monitor)
+		}
+	}
+
+	var buf bytes.Buffer
+	tokenhealth.WriteResultsMarkdown(&buf, tokens)
+	output := buf.String()
+	for _, expected := range []string{"GitHub Token Health", "Token of monitor-bot: 4 problems", "400 of 5000 left (8%)", "SSO authorization missing"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestExpiredFineGrainedToken(t *testing.T) {
+	info := healthyToken()
+	info.Scopes, info.ScopesKnown = nil, false
+	info.Expires = time.Now().Add(-time.Hour)
+	client := &mockgithub.MockGitHubClient{MockTokenInfo: info}
+
+	tokens, err := tokenhealth.NewTokenHealthChecker(client, newConfig()).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	// Fine-grained tokens have no scopes to check
+	problems := tokens[0].Problems
+	if len(problems) != 1 || problems[0].Subject != tokenhealth.SubjectExpiration {
+		t.Fatalf("Expected only the expiration to be reported, got %+v", problems)
+	}
+	if problems[0].Severity != findings.SeverityCritical || !strings.Contains(problems[0].Summary, "expired on") {
+		t.Errorf("Unexpected expiration problem: %+v", problems[0])
+	}
+	if problems[0].URL != "https://github.com/settings/personal-access-tokens" {
+		t.Errorf("Expected the fine-grained token settings, got %q", problems[0].URL)
+	}
+}
+
+func TestRateLimitPace(t *testing.T) {
+	// Half of the rate limit used in the first 10 minutes of the window
+	info := healthyToken()
+	info.RateLimit = common.RateLimit{Limit: 5000, Remaining: 2500, Reset: time.Now().Add(50 * time.Minute)}
+	client := &mockgithub.MockGitHubClient{MockTokenInfo: info}
+
+	tokens, err := tokenhealth.NewTokenHealthChecker(client, newConfig()).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+	problems := tokens[0].Problems
+	if len(problems) != 1 || !strings.Contains(problems[0].Summary, "on pace to exhaust its rate limit") {
+		t.Errorf("Expected the pace to be reported, got %+v", problems)
+	}
+}
+
+func TestRateLimitTrend(t *testing.T) {
+	cfg := newConfig()
+	cfg.Monitors.TokenHealth.Organizations = nil
+	cfg.State = config.StateConfig{Enabled: true, Path: filepath.Join(t.TempDir(), "state.json")}
+
+	// The headroom shrinks by 25 points a run, early enough in each window not to project its pace
+	var tokens []tokenhealth.Token
+	for _, remaining := range []int{4500, 3250, 2000} {
+		info := healthyToken()
+		info.RateLimit = common.RateLimit{Limit: 5000, Remaining: remaining, Reset: time.Now().Add(58 * time.Minute)}
+		client := &mockgithub.MockGitHubClient{MockTokenInfo: info}
+
+		var err error
+		tokens, err = tokenhealth.NewTokenHealthChecker(client, cfg).Run(context.Background())
+		if err != nil {
+			t.Fatalf("Did not expect an error but got: %v", err)
+		}
+		if remaining != 2000 && len(tokens[0].Problems) != 0 {
+			t.Errorf("Expected no problems with %d remaining, got %+v", remaining, tokens[0].Problems)
+		}
+	}
+
+	if got := tokens[0].Trend; len(got) != 2 || got[0] != 90 || got[1] != 65 {
+		t.Errorf("Expected the headroom of the earlier runs, got %v", got)
+	}
+	problems := tokens[0].Problems
+	if len(problems) != 1 || !strings.Contains(problems[0].Summary, "shrank from 90% to 40% over the last 3 runs") {
+		t.Errorf("Expected the shrinking headroom to be reported, got %+v", problems)
+	}
+}
+
+func TestTokenInfoError(t *testing.T) {
+	client := &mockgithub.MockGitHubClient{}
+
+	if _, err := tokenhealth.NewTokenHealthChecker(client, newConfig()).Run(context.Background()); err == nil {
+		t.Errorf("Expected an error when the token cannot be checked")
+	}
+	if client.GetOrganizationAccessCalls != 0 {
+		t.Errorf("Expected no organization to be checked, got %d calls", client.GetOrganizationAccessCalls)
+	}
+}
@@ -0,0 +1,407 @@
+package tokenhealth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/state"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+// Subjects of the problems of a token
+const (
+	SubjectExpiration = "token_expiration"
+	SubjectScopes     = "token_scopes"
+	SubjectRateLimit  = "rate_limit"
+	SubjectSSO        = "sso_authorization"
+	SubjectAccess     = "organization_access"
+)
+
+// maxSamples is the number of rate limit samples kept per token, one per run
+const maxSamples = 10
+
+// minPaceWindow is how much of the rate limit window must have passed before its pace is projected,
+// as the first requests of a window say little about the rest of it
+const minPaceWindow = 5 * time.Minute
+
+// rateLimitWindow is how long a rate limit window of GitHub lasts
+const rateLimitWindow = time.Hour
+
+// impliedScopes are the OAuth scopes granted by broader ones
+var impliedScopes = map[string][]string{
+	"repo":             {"repo:status", "repo_deployment", "public_repo", "repo:invite", "security_events"},
+	"admin:org":        {"write:org", "read:org", "manage_runners:org"},
+	"write:org":        {"read:org"},
+	"admin:repo_hook":  {"write:repo_hook", "read:repo_hook"},
+	"write:repo_hook":  {"read:repo_hook"},
+	"admin:public_key": {"write:public_key", "read:public_key"},
+	"write:public_key": {"read:public_key"},
+	"user":             {"read:user", "user:email", "user:follow"},
+	"write:packages":   {"read:packages"},
+	"project":          {"read:project"},
+	"admin:gpg_key":    {"write:gpg_key", "read:gpg_key"},
+	"write:gpg_key":    {"read:gpg_key"},
+	"audit_log":        {"read:audit_log"},
+	"admin:enterprise": {"manage_runners:enterprise", "manage_billing:enterprise", "read:enterprise"},
+}
+
+// Token is the health of the token an account's monitors use
+type Token struct {
+	Login       string
+	Scopes      []string  // OAuth scopes of a classic token
+	ScopesKnown bool      // Whether GitHub listed the scopes, which it does not for fine-grained tokens
+	Expires     time.Time // When the token expires, zero when it does not
+	RateLimit   common.RateLimit
+	Headroom    int   // Percentage of the rate limit remaining
+	Trend       []int // Headroom at the previous runs, oldest first, when the state is enabled
+
+	Organizations []Organization
+	Problems      []Problem
+}
+
+// Organization is whether the token can read the resources of an organization
+type Organization struct {
+	Name        string
+	Accessible  bool
+	SSORequired bool   // Whether the token is not authorized for the organization's SAML single sign-on
+	SSOURL      string // Where to authorize the token
+}
+
+// Problem is something that will break or already breaks the monitoring with the token
+type Problem struct {
+	Target   string // "token:<login>", or "org:<name>" for organizations the token cannot read
+	Subject  string
+	Summary  string
+	URL      string
+	Severity string
+}
+
+// Checker is a service that checks the health of the GitHub token
+type Checker struct {
+	client common.GitHubClientInterface
+	config *config.Config
+}
+
+// NewTokenHealthChecker creates a new Checker
+func NewTokenHealthChecker(client common.GitHubClientInterface, config *config.Config) *Checker {
+	return &Checker{
+		client: client,
+		config: config,
+	}
+}
+
+// Run checks the expiration, scopes and rate limit of the token, and its access to the configured organizations
+func (c *Checker) Run(ctx context.Context) ([]Token, error) {
+	if common.SkipIfStopped(ctx, "token") {
+		return []Token{}, nil
+	}
+
+	log.Printf("Checking the health of the GitHub token")
+	info, err := c.client.GetTokenInfo(ctx)
+	if err != nil {
+		if common.SkipIfBudgetExceeded(ctx, "token", err) {
+			return []Token{}, nil
+		}
+		return nil, err
+	}
+
+	cfg := c.config.Monitors.TokenHealth
+	now := time.Now()
+	token := Token{
+		Login:       info.Login,
+		Scopes:      info.Scopes,
+		ScopesKnown: info.ScopesKnown,
+		Expires:     info.Expires,
+		RateLimit:   info.RateLimit,
+		Headroom:    headroom(info.RateLimit),
+	}
+
+	if c.config.State.Enabled {
+		token.Trend, err = recordHeadroom(c.config.State.Path, token.Login, state.RateLimitSample{At: now, Headroom: token.Headroom})
+		if err != nil {
+			log.Printf("Error recording the rate limit headroom of the token: %v", err)
+		}
+	}
+
+	token.Problems = append(token.Problems, expiration(token, now, cfg.ExpiryWarningDays)...)
+	token.Problems = append(token.Problems, scopes(token, cfg.RequiredScopes)...)
+	token.Problems = append(token.Problems, rateLimit(token, now, cfg.MinRateLimitHeadroom)...)
+
+	for _, org := range cfg.Organizations {
+		if common.SkipIfStopped(ctx, "org:"+org) {
+			continue
+		}
+		access, err := c.client.GetOrganizationAccess(ctx, org)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, "org:"+org, err) {
+				continue
+			}
+			log.Printf("Error checking the access of the token to organization %s: %v", org, err)
+			continue
+		}
+
+		result := Organization{Name: org, Accessible: access.Accessible, SSORequired: access.SSORequired, SSOURL: access.SSOURL}
+		token.Organizations = append(token.Organizations, result)
+		switch {
+		case result.SSORequired:
+			url := result.SSOURL
+			if url == "" {
+				url = "https://github.com/orgs/" + org + "/sso"
+			}
+			token.Problems = append(token.Problems, Problem{
+				Target:   "org:" + org,
+				Subject:  SubjectSSO,
+				Summary:  fmt.Sprintf("token of %s is not authorized for the SAML single sign-on of %s, which hides its repositories", loginOf(token), org),
+				URL:      url,
+				Severity: findings.SeverityHigh,
+			})
+		case !result.Accessible:
+			token.Problems = append(token.Problems, Problem{
+				Target:  "org:" + org,
+				Subject: SubjectAccess,
+				Summary: fmt.Sprintf("token of %s cannot list the repositories of %s", loginOf(token), org),
+				URL:     "https://github.com/" + org,
+			})
+		}
+	}
+
+	return []Token{token}, nil
+}
+
+// headroom returns the percentage of a rate limit remaining
+func headroom(limit common.RateLimit) int {
+	if limit.Limit <= 0 {
+		return 100
+	}
+	return limit.Remaining * 100 / limit.Limit
+}
+
+// recordHeadroom adds a rate limit sample of the token to the state and returns the headroom of the earlier samples
+func recordHeadroom(path, login string, sample state.RateLimitSample) ([]int, error) {
+	var trend []int
+	err := state.Update(path, func(s *state.State) error {
+		if s.RateLimits == nil {
+			s.RateLimits = make(map[string][]state.RateLimitSample)
+		}
+		samples := s.RateLimits[login]
+		for _, previous := range samples {
+			trend = append(trend, previous.Headroom)
+		}
+		samples = append(samples, sample)
+		if len(samples) > maxSamples {
+			samples = samples[len(samples)-maxSamples:]
+		}
+		s.RateLimits[login] = samples
+		return nil
+	})
+	return trend, err
+}
+
+// loginOf names the user of a token
+func loginOf(t Token) string {
+	if t.Login == "" {
+		return "an unknown user"
+	}
+	return t.Login
+}
+
+// settingsURL returns where the token is managed
+func settingsURL(t Token) string {
+	if t.ScopesKnown {
+		return "https://github.com/settings/tokens"
+	}
+	return "https://github.com/settings/personal-access-tokens"
+}
+
+// expiration reports a token that expired or expires within warningDays
+func expiration(t Token, now time.Time, warningDays int) []Problem {
+	if t.Expires.IsZero() {
+		return nil
+	}
+
+	left := t.Expires.Sub(now)
+	switch {
+	case left <= 0:
+		return []Problem{{
+			Target:   "token:" + t.Login,
+			Subject:  SubjectExpiration,
+			Summary:  fmt.Sprintf("token of %s expired on %s", loginOf(t), t.Expires.Format("2006-01-02")),
+			URL:      settingsURL(t),
+			Severity: findings.SeverityCritical,
+		}}
+	case left <= time.Duration(warningDays)*24*time.Hour:
+		return []Problem{{
+			Target:   "token:" + t.Login,
+			Subject:  SubjectExpiration,
+			Summary:  fmt.Sprintf("token of %s expires in %d days on %s", loginOf(t), int(left.Hours()/24), t.Expires.Format("2006-01-02")),
+			URL:      settingsURL(t),
+			Severity: findings.SeverityHigh,
+		}}
+	}
+	return nil
+}
+
+// scopes reports the required OAuth scopes a classic token lacks, fine-grained tokens have permissions instead
+func scopes(t Token, required []string) []Problem {
+	if !t.ScopesKnown || len(required) == 0 {
+		return nil
+	}
+
+	granted := make(map[string]bool)
+	for _, scope := range t.Scopes {
+		granted[scope] = true
+		for _, implied := range impliedScopes[scope] {
+			granted[implied] = true
+		}
+	}
+	var missing []string
+	for _, scope := range required {
+		if !granted[scope] {
+			missing = append(missing, scope)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+
+	return []Problem{{
+		Target:   "token:" + t.Login,
+		Subject:  SubjectScopes,
+		Summary:  fmt.Sprintf("token of %s lacks the required scopes %s", loginOf(t), strings.Join(missing, ", ")),
+		URL:      settingsURL(t),
+		Severity: findings.SeverityHigh,
+	}}
+}
+
+// rateLimit reports a token with less headroom than minHeadroom, on pace to exhaust its rate limit before it resets,
+// or whose headroom shrank at every recent run and will drop below minHeadroom at the next one
+func rateLimit(t Token, now time.Time, minHeadroom int) []Problem {
+	if t.RateLimit.Limit <= 0 {
+		return nil
+	}
+
+	problem := func(summary string) []Problem {
+		return []Problem{{
+			Target:  "token:" + t.Login,
+			Subject: SubjectRateLimit,
+			Summary: summary,
+			URL:     "https://docs.github.com/rest/using-the-rest-api/rate-limits-for-the-rest-api",
+		}}
+	}
+	reset := t.RateLimit.Reset.UTC().Format("15:04 MST")
+
+	if t.Headroom < minHeadroom {
+		return problem(fmt.Sprintf("token of %s has %d%% of its rate limit left until %s, below the minimum of %d%%",
+			loginOf(t), t.Headroom, reset, minHeadroom))
+	}
+
+	// Project the pace of the current window until it resets
+	elapsed := rateLimitWindow - t.RateLimit.Reset.Sub(now)
+	if elapsed >= minPaceWindow && elapsed < rateLimitWindow {
+		used := float64(t.RateLimit.Limit - t.RateLimit.Remaining)
+		projected := used * float64(rateLimitWindow) / float64(elapsed)
+		if projected >= float64(t.RateLimit.Limit) {
+			return problem(fmt.Sprintf("token of %s is on pace to exhaust its rate limit of %d requests before it resets at %s",
+				loginOf(t), t.RateLimit.Limit, reset))
+		}
+	}
+
+	// Extrapolate the headroom of the recent runs to the next run
+	const runs = 3
+	if len(t.Trend) >= runs-1 {
+		recent := append(append([]int(nil), t.Trend[len(t.Trend)-(runs-1):]...), t.Headroom)
+		for i := 1; i < len(recent); i++ {
+			if recent[i] >= recent[i-1] {
+				return nil
+			}
+		}
+		next := t.Headroom - (recent[0]-t.Headroom)/(len(recent)-1)
+		if next < minHeadroom {
+			return problem(fmt.Sprintf("rate limit headroom of the token of %s shrank from %d%% to %d%% over the last %d runs, below %d%% at this pace",
+				loginOf(t), recent[0], t.Headroom, runs, minHeadroom))
+		}
+	}
+	return nil
+}
+
+// Findings converts the problems of the tokens into findings
+func Findings(tokens []Token) []findings.Finding {
+	list := make([]findings.Finding, 0)
+	for _, t := range tokens {
+		for _, p := range t.Problems {
+			list = append(list, findings.Finding{
+				Monitor:    "token_health",
+				Repository: p.Target,
+				Subject:    p.Subject,
+				Summary:    p.Summary,
+				URL:        p.URL,
+				Severity:   p.Severity,
+			})
+		}
+	}
+	return list
+}
+
+// WriteResultsMarkdown writes the health of the tokens in a code block format suitable for Slack notifications
+func WriteResultsMarkdown(w io.Writer, tokens []Token) {
+	if len(tokens) == 0 {
+		return // No results to display
+	}
+
+	// Print header for the token health
+	fmt.Fprintln(w, "## :key: GitHub Token Health")
+	for _, t := range tokens {
+		fmt.Fprintf(w, "Token of %s: %d problems.\n\n", loginOf(t), len(t.Problems))
+
+		// Start code block
+		fmt.Fprintln(w, "```")
+		expires := "never"
+		if !t.Expires.IsZero() {
+			expires = t.Expires.UTC().Format("2006-01-02")
+		}
+		fmt.Fprintf(w, "Expires:     %s\n", expires)
+		switch {
+		case !t.ScopesKnown:
+			fmt.Fprintln(w, "Scopes:      fine-grained permissions")
+		case len(t.Scopes) == 0:
+			fmt.Fprintln(w, "Scopes:      none")
+		default:
+			fmt.Fprintf(w, "Scopes:      %s\n", strings.Join(t.Scopes, ", "))
+		}
+		if t.RateLimit.Limit > 0 {
+			fmt.Fprintf(w, "Rate limit:  %d of %d left (%d%%) until %s\n",
+				t.RateLimit.Remaining, t.RateLimit.Limit, t.Headroom, t.RateLimit.Reset.UTC().Format("15:04 MST"))
+		}
+		if len(t.Trend) > 0 {
+			trend := make([]string, 0, len(t.Trend)+1)
+			for _, h := range append(append([]int(nil), t.Trend...), t.Headroom) {
+				trend = append(trend, fmt.Sprintf("%d%%", h))
+			}
+			fmt.Fprintf(w, "Headroom:    %s\n", strings.Join(trend, " -> "))
+		}
+		for _, o := range t.Organizations {
+			status := "accessible"
+			switch {
+			case o.SSORequired:
+				status = "SSO authorization missing"
+			case !o.Accessible:
+				status = "not accessible"
+			}
+			fmt.Fprintf(w, "Org %-24s %s\n", o.Name+":", status)
+		}
+		for _, p := range t.Problems {
+			fmt.Fprintf(w, "WARNING:     %s\n", p.Summary)
+		}
+		// End code block
+		fmt.Fprintln(w, "```")
+		fmt.Fprintln(w, "")
+	}
+}
@@ -0,0 +1,144 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+	mockgithub "github.com/anupsv/git-monitoring/pkg/tools/common/test"
+	"github.com/anupsv/git-monitoring/pkg/tools/workflowpermissions"
+)
+
+func TestCheckOrganization(t *testing.T) {
+	tests := []struct {
+		name              string
+		permissions       *common.WorkflowPermissions
+		permissionsErr    error
+		allowWriteDefault bool
+		expectError       bool
+		expectedSettings  []string
+	}{
+		{
+			name:        "Read only without PR approvals",
+			permissions: &common.WorkflowPermissions{DefaultWorkflowPermissions: "read"},
+		},
+		{
+			name:             "Workflows can approve PRs",
+			permissions:      &common.WorkflowPermissions{DefaultWorkflowPermissions: "read", CanApprovePullRequestReviews: true},
+			expectedSettings: []string{workflowpermissions.SettingApprovePullRequests},
+		},
+		{
+			name:             "Write by default",
+			permissions:      &common.WorkflowPermissions{DefaultWorkflowPermissions: "write"},
+			expectedSettings: []string{workflowpermissions.SettingDefaultPermissions},
+		},
+		{
+			name:              "Write by default allowed",
+			permissions:       &common.WorkflowPermissions{DefaultWorkflowPermissions: "write", CanApprovePullRequestReviews: true},
+			allowWriteDefault: true,
+			expectedSettings:  []string{workflowpermissions.SettingApprovePullRequests},
+		},
+		{
+			name:             "Write by default and PR approvals",
+			permissions:      &common.WorkflowPermissions{DefaultWorkflowPermissions: "write", CanApprovePullRequestReviews: true},
+			expectedSettings: []string{workflowpermissions.SettingApprovePullRequests, workflowpermissions.SettingDefaultPermissions},
+		},
+		{
+			name:           "Error getting permissions",
+			permissionsErr: errors.New("API error"),
+			expectError:    true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &mockgithub.MockGitHubClient{
+				MockOrgWorkflowPerms: map[string]*common.WorkflowPermissions{"testorg": tc.permissions},
+				MockWorkflowPermsErr: tc.permissionsErr,
+			}
+
+			cfg := &config.Config{
+				Monitors: config.MonitorsConfig{
+					WorkflowPermissions: config.WorkflowPermissionsConfig{
+						Enabled:           true,
+						Organizations:     []string{"testorg"},
+						AllowWriteDefault: tc.allowWriteDefault,
+					},
+				},
+			}
+
+			checker := workflowpermissions.NewWorkflowPermissionsChecker(mockClient, cfg)
+			exposures, err := checker.CheckOrganization(context.Background(), "testorg")
+
+			if tc.expectError && err == nil {
+				t.Error("Expected an error but got nil")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("Did not expect an error but got: %v", err)
+			}
+			if len(exposures) != len(tc.expectedSettings) {
+				t.Fatalf("Expected %d exposures, got %d", len(tc.expectedSettings), len(exposures))
+			}
+
+			for i, e := range exposures {
+				if e.Setting != tc.expectedSettings[i] {
+					t.Errorf("Expected setting %q, got %q", tc.expectedSettings[i], e.Setting)
+				}
+				if e.Target != "org:testorg" {
+					t.Errorf("Expected target %q, got %q", "org:testorg", e.Target)
+				}
+				if e.URL != "https://github.com/organizations/testorg/settings/actions" {
+					t.Errorf("Unexpected settings URL %q", e.URL)
+				}
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	mockClient := &mockgithub.MockGitHubClient{
+		MockOrgWorkflowPerms: map[string]*common.WorkflowPermissions{
+			"testorg": {DefaultWorkflowPermissions: "read", CanApprovePullRequestReviews: true},
+		},
+		MockRepoWorkflowPerms: map[string]*common.WorkflowPermissions{
+			"owner/strict": {DefaultWorkflowPermissions: "read"},
+			"owner/loose":  {DefaultWorkflowPermissions: "write"},
+		},
+	}
+
+	cfg := &config.Config{
+		Monitors: config.MonitorsConfig{
+			WorkflowPermissions: config.WorkflowPermissionsConfig{
+				Enabled:       true,
+				Organizations: []string{"testorg"},
+				Repositories:  []string{"owner/strict", "owner/loose", "invalid"},
+			},
+		},
+	}
+
+	checker := workflowpermissions.NewWorkflowPermissionsChecker(mockClient, cfg)
+	exposures, err := checker.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	if len(exposures) != 2 {
+		t.Fatalf("Expected 2 exposures, got %d", len(exposures))
+	}
+	if exposures[0].Target != "org:testorg" || exposures[1].Target != "owner/loose" {
+		t.Errorf("Unexpected targets %q and %q", exposures[0].Target, exposures[1].Target)
+	}
+	if exposures[1].URL != "https://github.com/owner/loose/settings/actions" {
+		t.Errorf("Unexpected settings URL %q", exposures[1].URL)
+	}
+	if mockClient.GetRepoWorkflowPermsCalls != 2 {
+		t.Errorf("Expected 2 repository lookups, got %d", mockClient.GetRepoWorkflowPermsCalls)
+	}
+
+	list := workflowpermissions.Findings(exposures)
+	if len(list) != 2 || list[0].Monitor != "workflow_permissions" || list[0].Subject != workflowpermissions.SettingApprovePullRequests {
+		t.Errorf("Unexpected findings %+v", list)
+	}
+}
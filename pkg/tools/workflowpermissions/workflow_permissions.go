@@ -0,0 +1,177 @@
+package workflowpermissions
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/anupsv/git-monitoring/pkg/config"
+	"github.com/anupsv/git-monitoring/pkg/findings"
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+// Settings of the workflow token that are reported
+const (
+	SettingApprovePullRequests = "can_approve_pull_request_reviews" // Workflows can create and approve PRs
+	SettingDefaultPermissions  = "default_workflow_permissions"     // The token can write by default
+)
+
+// Exposure is an organization or repository whose workflow token settings undermine code review
+type Exposure struct {
+	Target  string // "org:<name>" for organizations, "owner/repo" for repositories
+	Setting string // Setting at fault, e.g. SettingApprovePullRequests
+	Detail  string
+	URL     string // Actions settings page of the target
+}
+
+// Checker is a service that audits the default permissions of the workflow GITHUB_TOKEN
+type Checker struct {
+	client common.GitHubClientInterface
+	config *config.Config
+}
+
+// NewWorkflowPermissionsChecker creates a new Checker
+func NewWorkflowPermissionsChecker(client common.GitHubClientInterface, config *config.Config) *Checker {
+	return &Checker{
+		client: client,
+		config: config,
+	}
+}
+
+// Run checks all configured organizations and repositories
+func (c *Checker) Run(ctx context.Context) ([]Exposure, error) {
+	allExposures := make([]Exposure, 0)
+
+	for _, org := range c.config.Monitors.WorkflowPermissions.Organizations {
+		if common.SkipIfStopped(ctx, "org:"+org) {
+			continue
+		}
+		exposures, err := c.CheckOrganization(ctx, org)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, "org:"+org, err) {
+				continue
+			}
+			log.Printf("Error checking workflow permissions for organization %s: %v", org, err)
+			continue
+		}
+		allExposures = append(allExposures, exposures...)
+	}
+
+	for _, repository := range c.config.Monitors.WorkflowPermissions.Repositories {
+		if common.SkipIfStopped(ctx, repository) {
+			continue
+		}
+		exposures, err := c.CheckRepository(ctx, repository)
+		if err != nil {
+			if common.SkipIfBudgetExceeded(ctx, repository, err) {
+				continue
+			}
+			log.Printf("Error checking workflow permissions for repository %s: %v", repository, err)
+			continue
+		}
+		allExposures = append(allExposures, exposures...)
+	}
+
+	return allExposures, nil
+}
+
+// CheckOrganization audits the default workflow token permissions of an organization
+func (c *Checker) CheckOrganization(ctx context.Context, org string) ([]Exposure, error) {
+	log.Printf("Checking default workflow permissions of %s organization", org)
+
+	permissions, err := c.client.GetOrganizationWorkflowPermissions(ctx, org)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization workflow permissions: %w", err)
+	}
+
+	return c.audit(permissions, "org:"+org, fmt.Sprintf("https://github.com/organizations/%s/settings/actions", org)), nil
+}
+
+// CheckRepository audits the default workflow token permissions of a repository, which can be stricter than
+// those of its organization
+func (c *Checker) CheckRepository(ctx context.Context, repository string) ([]Exposure, error) {
+	owner, repo, ok := common.ParseRepository(repository)
+	if !ok {
+		return nil, fmt.Errorf("invalid repository format, expected 'owner/repo'")
+	}
+
+	log.Printf("Checking default workflow permissions of %s", repository)
+
+	permissions, err := c.client.GetRepositoryWorkflowPermissions(ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository workflow permissions: %w", err)
+	}
+
+	return c.audit(permissions, repository, fmt.Sprintf("https://github.com/%s/settings/actions", repository)), nil
+}
+
+// audit returns the settings of a target that let workflows approve PRs or write by default
+func (c *Checker) audit(permissions *common.WorkflowPermissions, target, url string) []Exposure {
+	exposures := make([]Exposure, 0)
+
+	if permissions.CanApprovePullRequestReviews {
+		exposures = append(exposures, Exposure{
+			Target:  target,
+			Setting: SettingApprovePullRequests,
+			Detail:  "GitHub Actions can create and approve pull requests, so a workflow can satisfy required reviews",
+			URL:     url,
+		})
+	}
+
+	if strings.EqualFold(permissions.DefaultWorkflowPermissions, "write") && !c.config.Monitors.WorkflowPermissions.AllowWriteDefault {
+		exposures = append(exposures, Exposure{
+			Target:  target,
+			Setting: SettingDefaultPermissions,
+			Detail:  "the GITHUB_TOKEN of workflows has read and write permissions by default",
+			URL:     url,
+		})
+	}
+
+	return exposures
+}
+
+// Findings converts workflow permission exposures into findings
+func Findings(exposures []Exposure) []findings.Finding {
+	list := make([]findings.Finding, 0, len(exposures))
+	for _, e := range exposures {
+		list = append(list, findings.Finding{
+			Monitor:    "workflow_permissions",
+			Repository: e.Target,
+			Subject:    e.Setting,
+			Summary:    e.Detail,
+			URL:        e.URL,
+		})
+	}
+	return list
+}
+
+// WriteResultsMarkdown writes workflow permission exposures in a code block format
+// suitable for Slack notifications
+func WriteResultsMarkdown(w io.Writer, exposures []Exposure) {
+	if len(exposures) == 0 {
+		return // No results to display
+	}
+
+	fmt.Fprintln(w, "## :robot_face: Workflow Token Permissions")
+	fmt.Fprintf(w, "Found %d workflow token settings that undermine code review.\n\n", len(exposures))
+
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "Target                    Setting                           Link")
+	fmt.Fprintln(w, "---------------------------------------------------------------------")
+
+	for _, e := range exposures {
+		targetStr := e.Target
+		if len(targetStr) > 24 {
+			targetStr = targetStr[:21] + "..."
+		} else {
+			targetStr = fmt.Sprintf("%-24s", targetStr)
+		}
+
+		fmt.Fprintf(w, "%s  %-32s  %s\n", targetStr, e.Setting, e.URL)
+	}
+
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
@@ -0,0 +1,74 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/usage"
+)
+
+func TestReport(t *testing.T) {
+	ctx := context.Background()
+	report := usage.NewReport()
+	start := usage.Take(ctx)
+	start.At = start.At.Add(-1500 * time.Millisecond)
+	start.APICalls -= 120
+	start.CacheHits -= 3
+	start.CacheMisses -= 1
+	m := usage.Measure(ctx, "pr_checker", "acme", "unused-token", start)
+	report.AddMonitor(m)
+
+	start = usage.Take(ctx)
+	start.APICalls -= 30
+	report.AddMonitor(usage.Measure(ctx, "rulesets", "", "unused-token", start))
+
+	if m.Monitor != "pr_checker" || m.Account != "acme" || m.APICalls != 120 || m.DurationSeconds < 1.5 {
+		t.Errorf("Unexpected monitor usage: %+v", m)
+	}
+	// Without requests the monitor's time all goes to processing
+	if m.LimiterSeconds != 0 || m.APISeconds != 0 || m.ProcessingSeconds != m.DurationSeconds {
+		t.Errorf("Unexpected timing breakdown: %+v", m)
+	}
+	if m.CacheHits != 3 || m.CacheMisses != 1 || m.CacheHitRate != 0.75 {
+		t.Errorf("Unexpected cache usage: %+v", m)
+	}
+	if m.RateLimit != nil {
+		t.Errorf("Expected no rate limit for a token without requests, got %+v", m.RateLimit)
+	}
+	if got := report.APICalls(); got != 150 {
+		t.Errorf("Expected 150 API calls, got %d", got)
+	}
+
+	// Tokens without requests have no known rate limit and are left out
+	report.AddRateLimit("acme", "unused-token")
+	if len(report.RateLimits) != 0 {
+		t.Errorf("Expected no rate limits, got %+v", report.RateLimits)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	merged := usage.Merge([]usage.Monitor{
+		{Monitor: "pr_checker", APICalls: 100, DurationSeconds: 10, CacheHits: 2, CacheMisses: 2},
+		{Monitor: "pr_checker", APICalls: 50, DurationSeconds: 5, CacheHits: 4},
+	})
+	if merged.Monitor != "pr_checker" || merged.APICalls != 150 || merged.DurationSeconds != 15 {
+		t.Errorf("Expected the calls and time of the shards to add up, got %+v", merged)
+	}
+	if merged.CacheHits != 6 || merged.CacheMisses != 2 || merged.CacheHitRate != 0.75 {
+		t.Errorf("Expected the cache hit rate of all lookups, got %+v", merged)
+	}
+}
+
+func TestReportJSON(t *testing.T) {
+	data, err := json.Marshal(usage.NewReport())
+	if err != nil {
+		t.Fatalf("Did not expect an error but got: %v", err)
+	}
+
+	// An empty report keeps its lists, so consumers do not have to handle null
+	if string(data) != `{"monitors":[],"rate_limits":[]}` {
+		t.Errorf("Unexpected JSON: %s", data)
+	}
+}
@@ -0,0 +1,201 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+
+	"github.com/anupsv/git-monitoring/pkg/tools/common"
+)
+
+// Monitor is the GitHub API usage of a monitor in a run
+type Monitor struct {
+	Monitor         string            `json:"monitor"`
+	Account         string            `json:"account,omitempty"`
+	APICalls        int64             `json:"api_calls"`            // Requests sent by the monitor, including rate limit checks
+	DurationSeconds float64           `json:"duration_seconds"`     // How long the monitor ran
+	RateLimit       *common.RateLimit `json:"rate_limit,omitempty"` // Rate limit of the account's token after the monitor ran
+
+	// Where the time went: waiting for the rate limiter, waiting for GitHub, and everything else
+	LimiterSeconds    float64 `json:"limiter_seconds"`
+	APISeconds        float64 `json:"api_seconds"`
+	ProcessingSeconds float64 `json:"processing_seconds"`
+
+	// Membership lookups answered from the cache and those that needed an API call
+	CacheHits    int64   `json:"cache_hits"`
+	CacheMisses  int64   `json:"cache_misses"`
+	CacheHitRate float64 `json:"cache_hit_rate"` // Share of the lookups answered from the cache, 0 without lookups
+}
+
+// Sample is the process-wide API usage at a point in time, to measure the usage of a monitor from
+type Sample struct {
+	At       time.Time
+	APICalls int64
+	Timings  common.Timings
+
+	CacheHits   int64
+	CacheMisses int64
+}
+
+// Take samples the API usage now, of the monitor run the context belongs to or of the whole process
+func Take(ctx context.Context) Sample {
+	u := common.UsageOf(ctx)
+	return Sample{At: time.Now(), APICalls: u.APICalls, Timings: u.Timings, CacheHits: u.CacheHits, CacheMisses: u.CacheMisses}
+}
+
+// RateLimit is the remaining rate limit of an account's token
+type RateLimit struct {
+	Account string `json:"account,omitempty"`
+	common.RateLimit
+}
+
+// Report is the GitHub API usage of a run
+type Report struct {
+	Monitors   []Monitor   `json:"monitors"`
+	RateLimits []RateLimit `json:"rate_limits"` // Rate limits at the end of the run, of the tokens that sent requests
+}
+
+// NewReport creates an empty usage report
+func NewReport() *Report {
+	return &Report{Monitors: []Monitor{}, RateLimits: []RateLimit{}}
+}
+
+// Measure returns the API calls a monitor sent with token since start, and where its time went
+// The monitor's usage is taken from the context it ran with, see common.WithScope
+func Measure(ctx context.Context, monitor, account, token string, start Sample) Monitor {
+	end := Take(ctx)
+	duration := end.At.Sub(start.At)
+	limiter := end.Timings.Limiter - start.Timings.Limiter
+	api := end.Timings.API - start.Timings.API
+	processing := duration - limiter - api
+	if processing < 0 {
+		processing = 0 // Rate limit checks overlap with the monitor's own timing in rare cases
+	}
+
+	m := Monitor{
+		Monitor:           monitor,
+		Account:           account,
+		APICalls:          end.APICalls - start.APICalls,
+		DurationSeconds:   seconds(duration),
+		LimiterSeconds:    seconds(limiter),
+		APISeconds:        seconds(api),
+		ProcessingSeconds: seconds(processing),
+		CacheHits:         end.CacheHits - start.CacheHits,
+		CacheMisses:       end.CacheMisses - start.CacheMisses,
+	}
+	if lookups := m.CacheHits + m.CacheMisses; lookups > 0 {
+		m.CacheHitRate = math.Round(float64(m.CacheHits)/float64(lookups)*1000) / 1000
+	}
+	if limit, ok := common.LatestRateLimit(token); ok {
+		m.RateLimit = &limit
+	}
+	return m
+}
+
+// Merge combines the usage of the shards of a monitor run, see the merge-reports command
+// Calls and time add up across the shards, however long they ran in parallel. The rate limits of the shards
+// were taken at different times, possibly of different tokens, so none is kept
+func Merge(shards []Monitor) Monitor {
+	var merged Monitor
+	for i, m := range shards {
+		if i == 0 {
+			merged.Monitor, merged.Account = m.Monitor, m.Account
+		}
+		merged.APICalls += m.APICalls
+		merged.DurationSeconds += m.DurationSeconds
+		merged.LimiterSeconds += m.LimiterSeconds
+		merged.APISeconds += m.APISeconds
+		merged.ProcessingSeconds += m.ProcessingSeconds
+		merged.CacheHits += m.CacheHits
+		merged.CacheMisses += m.CacheMisses
+	}
+	if lookups := merged.CacheHits + merged.CacheMisses; lookups > 0 {
+		merged.CacheHitRate = math.Round(float64(merged.CacheHits)/float64(lookups)*1000) / 1000
+	}
+	return merged
+}
+
+// AddMonitor records the usage of a monitor run
+func (r *Report) AddMonitor(m Monitor) {
+	r.Monitors = append(r.Monitors, m)
+}
+
+// seconds converts a duration to seconds, to the millisecond
+func seconds(d time.Duration) float64 {
+	return d.Round(time.Millisecond).Seconds()
+}
+
+// AddRateLimit records the remaining rate limit of an account's token
+// Tokens that have not sent any request are left out, as their rate limit is unknown without an extra call
+func (r *Report) AddRateLimit(account, token string) {
+	if limit, ok := common.LatestRateLimit(token); ok {
+		r.RateLimits = append(r.RateLimits, RateLimit{Account: account, RateLimit: limit})
+	}
+}
+
+// APICalls returns the API calls of all monitors
+func (r *Report) APICalls() int64 {
+	var total int64
+	for _, m := range r.Monitors {
+		total += m.APICalls
+	}
+	return total
+}
+
+// WriteMarkdown writes the usage in a code block format suitable for Slack
+func (r *Report) WriteMarkdown(w io.Writer) {
+	if len(r.Monitors) == 0 {
+		return // No monitors ran
+	}
+
+	fmt.Fprintln(w, "## :chart_with_downwards_trend: GitHub API Usage")
+	fmt.Fprintf(w, "%d API calls across %d monitor runs.\n\n", r.APICalls(), len(r.Monitors))
+
+	// Start code block
+	fmt.Fprintln(w, "```")
+	r.write(w)
+	// End code block
+	fmt.Fprintln(w, "```")
+	fmt.Fprintln(w, "")
+}
+
+// PrintText outputs the usage as plain text
+func (r *Report) PrintText() {
+	if len(r.Monitors) == 0 {
+		return
+	}
+
+	fmt.Printf("GitHub API usage: %d calls\n", r.APICalls())
+	r.write(os.Stdout)
+}
+
+// write writes the usage table and the remaining rate limits
+func (r *Report) write(w io.Writer) {
+	// Create fixed-width headers with proper spacing for code block
+	fmt.Fprintln(w, "Monitor                   API calls  Duration  Limiter   API       Processing")
+	fmt.Fprintln(w, "-------------------------------------------------------------------------------")
+	for _, m := range r.Monitors {
+		monitor := m.Monitor
+		if m.Account != "" {
+			monitor = fmt.Sprintf("%s (%s)", monitor, m.Account)
+		}
+		fmt.Fprintf(w, "%-25s %-10d %-9s %-9s %-9s %s\n", monitor, m.APICalls,
+			formatSeconds(m.DurationSeconds), formatSeconds(m.LimiterSeconds), formatSeconds(m.APISeconds), formatSeconds(m.ProcessingSeconds))
+	}
+
+	for _, limit := range r.RateLimits {
+		prefix := "Rate limit"
+		if limit.Account != "" {
+			prefix += " (" + limit.Account + ")"
+		}
+		fmt.Fprintf(w, "%s: %d of %d remaining, resets at %s\n", prefix, limit.Remaining, limit.Limit, limit.Reset.Format(time.RFC3339))
+	}
+}
+
+// formatSeconds formats seconds as a duration to the tenth of a second, e.g. "1m2.3s"
+func formatSeconds(s float64) string {
+	return time.Duration(s * float64(time.Second)).Round(100 * time.Millisecond).String()
+}